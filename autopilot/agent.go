@@ -0,0 +1,484 @@
+package autopilot
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// Config couples all the items that an autopilot agent needs to function.
+// All items within the struct MUST be populated for the Agent to be able to
+// carry out its duties.
+type Config struct {
+	// Self is the identity public key of the Lightning Network node that
+	// is being driven by the agent. This is used to ensure that we don't
+	// accidentally attempt to open a channel with ourselves.
+	Self *btcec.PublicKey
+
+	// Heuristic is an attachment heuristic which will govern to whom we
+	// open channels to, and also what those channels look like in terms
+	// of desired capacity. The Heuristic will take into account the
+	// current state of the graph, our set of open channels, and the
+	// amount of available funds when determining how channels are to be
+	// opened. Additionally, a heuristic may also factor in extra-graph
+	// information in order to make more pertinent recommendations.
+	Heuristic AttachmentHeuristic
+
+	// ChanController is an interface that is able to directly manage the
+	// creation of new channels.
+	ChanController ChannelController
+
+	// WalletBalance is a function closure that should return the current
+	// available balance of the backing wallet.
+	WalletBalance func() (btcutil.Amount, error)
+
+	// Graph is an abstract channel graph that the Heuristic and the Agent
+	// will use to make decisions w.r.t channel allocation and placement
+	// within the graph.
+	Graph ChannelGraph
+
+	// FailedNodeBackoff is the duration for which a node that we've
+	// failed to open a channel to is skipped in attachment attempts.
+	// Once the backoff has passed, the node may be selected again.
+	FailedNodeBackoff time.Duration
+}
+
+// DefaultFailedNodeBackoff is the default duration for which the agent skips
+// a node after failing to open a channel to it.
+const DefaultFailedNodeBackoff = time.Hour
+
+// Status is a snapshot of the current state of an autopilot agent.
+type Status struct {
+	// NumChannels is the number of open channels the agent is currently
+	// aware of.
+	NumChannels int
+
+	// TotalAllocation is the sum of the capacities of all open channels
+	// the agent is currently aware of.
+	TotalAllocation btcutil.Amount
+
+	// NumPendingOpens is the number of channels the agent has initiated,
+	// but which haven't yet been announced as open.
+	NumPendingOpens int
+}
+
+// channelState is a type that represents the set of active channels of the
+// backing LN node that the Agent should be aware of. This type contains
+// exactly the set of channels that the Agent uses within its internal
+// heuristics.
+type channelState map[wire.OutPoint]Channel
+
+// Channels returns a slice of all the active channels.
+func (c channelState) Channels() []Channel {
+	chans := make([]Channel, 0, len(c))
+	for _, channel := range c {
+		chans = append(chans, channel)
+	}
+	return chans
+}
+
+// ConnectedNodes returns the set of nodes we currently have a channel with.
+// This information is needed as we want to avoid making repeated channels
+// with any node.
+func (c channelState) ConnectedNodes() map[NodeID]struct{} {
+	nodes := make(map[NodeID]struct{})
+	for _, channel := range c {
+		nodes[channel.Node] = struct{}{}
+	}
+	return nodes
+}
+
+// Agent implements a closed-loop control system which seeks to autonomously
+// optimize the allocation of satoshis within channels throughout the network's
+// channel graph. An agent is configurable by swapping out different
+// AttachmentHeuristic strategies. The agent uses external signals such as the
+// wallet balance changing, or new channels being opened/closed for the local
+// node as an indicator to re-examine its internal state, and the amount of
+// available funds in order to make updated decisions w.r.t the channel graph.
+// The Agent will automatically open, or close channels in order to drive the
+// control system towards the state specified by the attachment heuristic.
+//
+// TODO(roasbeef): also add autopilot.Closer, to close channels which aren't
+// pulling their weight.
+type Agent struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	// cfg houses the configuration state of the Agent.
+	cfg Config
+
+	// chanState tracks the current set of open channels.
+	chanState channelState
+
+	// pendingOpens tracks the channels that we've requested to be
+	// initiated, but haven't yet been confirmed as being fully opened.
+	// This state is required as otherwise, we may go over our allotted
+	// channel limit, or open multiple channels to the same node.
+	pendingOpens map[NodeID]Channel
+
+	// failedNodes maps the nodes that we've previously failed to open a
+	// channel to, to the time of the failure. We'll skip these nodes in
+	// subsequent attachment attempts until the failed node backoff has
+	// passed, so we don't repeatedly target an unreachable node.
+	failedNodes map[NodeID]time.Time
+
+	// stateUpdates is a channel that any external state updates that may
+	// affect the heuristics of the agent will be sent over.
+	stateUpdates chan interface{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new instance of the Agent instantiated using the passed
+// configuration and initial channel state. The initial channel state slice
+// should be populated with the set of Channels that are currently opened by
+// the backing Lightning Node.
+func New(cfg Config, initialState []Channel) (*Agent, error) {
+	a := &Agent{
+		cfg:          cfg,
+		chanState:    make(map[wire.OutPoint]Channel),
+		pendingOpens: make(map[NodeID]Channel),
+		failedNodes:  make(map[NodeID]time.Time),
+		quit:         make(chan struct{}),
+		stateUpdates: make(chan interface{}),
+	}
+
+	for _, c := range initialState {
+		a.chanState[c.ChanPoint] = c
+	}
+
+	return a, nil
+}
+
+// Start starts the agent along with any goroutines it needs to perform its
+// normal duties.
+func (a *Agent) Start() error {
+	if !atomic.CompareAndSwapUint32(&a.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Autopilot Agent starting")
+
+	// We'll kick off the controller with an initial balance update, so
+	// it'll immediately examine its state and open any channels that it
+	// deems necessary.
+	a.wg.Add(1)
+	go a.controller()
+
+	a.OnBalanceChange()
+
+	return nil
+}
+
+// Stop signals the Agent to gracefully shutdown. This function will block
+// until all goroutines have exited.
+func (a *Agent) Stop() error {
+	if !atomic.CompareAndSwapUint32(&a.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Autopilot Agent stopping")
+
+	close(a.quit)
+	a.wg.Wait()
+
+	return nil
+}
+
+// balanceUpdate is a type of external state update that reflects an
+// increase/decrease in the funds currently available to the wallet.
+type balanceUpdate struct{}
+
+// chanOpenUpdate is a type of external state update that indicates a new
+// channel has been opened, either by the Agent itself (within the main
+// controller loop), or by an external user to the system.
+type chanOpenUpdate struct {
+	newChan Channel
+}
+
+// chanOpenFailureUpdate is a type of state update that indicates a failure to
+// open a channel to the target node.
+type chanOpenFailureUpdate struct {
+	nodeID NodeID
+}
+
+// chanCloseUpdate is a type of external state update that indicates that the
+// backing Lightning Node has closed a previously open channel.
+type chanCloseUpdate struct {
+	closedChans []wire.OutPoint
+}
+
+// statusRequest is a request for a snapshot of the agent's current state.
+// The response is sent over the resp channel.
+type statusRequest struct {
+	resp chan *Status
+}
+
+// sendUpdate hands the passed state update to the main controller loop,
+// without blocking the caller.
+func (a *Agent) sendUpdate(update interface{}) {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		select {
+		case a.stateUpdates <- update:
+		case <-a.quit:
+		}
+	}()
+}
+
+// OnBalanceChange is a callback that should be executed each time the
+// balance of the backing wallet changes.
+func (a *Agent) OnBalanceChange() {
+	a.sendUpdate(&balanceUpdate{})
+}
+
+// OnChannelOpen is a callback that should be executed each time a new channel
+// is manually opened by the user or any system outside the autopilot agent.
+func (a *Agent) OnChannelOpen(c Channel) {
+	a.sendUpdate(&chanOpenUpdate{newChan: c})
+}
+
+// OnChannelClose is a callback that should be executed each time a prior
+// channel has been closed for any reason. This includes regular
+// closes, force closes, and channel breaches.
+func (a *Agent) OnChannelClose(closedChans ...wire.OutPoint) {
+	a.sendUpdate(&chanCloseUpdate{closedChans: closedChans})
+}
+
+// Status returns a snapshot of the agent's current state.
+func (a *Agent) Status() (*Status, error) {
+	req := &statusRequest{
+		resp: make(chan *Status, 1),
+	}
+
+	select {
+	case a.stateUpdates <- req:
+	case <-a.quit:
+		return nil, fmt.Errorf("autopilot agent shutting down")
+	}
+
+	select {
+	case status := <-req.resp:
+		return status, nil
+	case <-a.quit:
+		return nil, fmt.Errorf("autopilot agent shutting down")
+	}
+}
+
+// controller implements the closed-loop control system of the Agent. The
+// controller will make a decision w.r.t channel placement within the graph
+// based on: its current internal state of the set of active channels open,
+// and external state changes as a result of decisions it makes w.r.t channel
+// allocation, or attributes affecting its control loop being updated by the
+// backing Lightning Node.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *Agent) controller() {
+	defer a.wg.Done()
+
+	for {
+		select {
+		// A new external signal has arrived. We'll use this to update
+		// our internal state, then determine if we should trigger a
+		// channel state modification (open/close, splice in/out).
+		case signal := <-a.stateUpdates:
+			log.Infof("Processing new external signal")
+
+			switch update := signal.(type) {
+			// The balance of the backing wallet has changed, if
+			// more funds are now available, we may attempt to open
+			// up an additional channel, or splice in funds to an
+			// existing one.
+			case *balanceUpdate:
+				log.Debugf("Applying external balance state " +
+					"update")
+
+			// A new channel has been opened successfully. This was
+			// either opened by the Agent, or an external system
+			// that is able to drive the Lightning Node.
+			case *chanOpenUpdate:
+				log.Debugf("New channel successfully opened, "+
+					"updating state with: %v",
+					update.newChan.ChanPoint)
+
+				newChan := update.newChan
+				a.chanState[newChan.ChanPoint] = newChan
+				delete(a.pendingOpens, newChan.Node)
+
+			// A channel we attempted to open has failed, so we'll
+			// clear its pending state to allow another attempt.
+			case *chanOpenFailureUpdate:
+				log.Debugf("Retrying after previous channel " +
+					"open failure.")
+
+				delete(a.pendingOpens, update.nodeID)
+				a.failedNodes[update.nodeID] = time.Now()
+
+			// A channel has been closed, this may free up an
+			// available slot, triggering a new channel update.
+			case *chanCloseUpdate:
+				log.Debugf("Applying closed channel "+
+					"updates: %v", update.closedChans)
+
+				for _, closedChan := range update.closedChans {
+					delete(a.chanState, closedChan)
+				}
+
+			// A caller is querying our current state. As this
+			// doesn't modify our state, there's no need to
+			// re-examine whether we need more channels.
+			case *statusRequest:
+				update.resp <- a.status()
+				continue
+			}
+
+		// The agent has been signalled to exit, so we'll bail out
+		// immediately.
+		case <-a.quit:
+			return
+		}
+
+		a.attemptAttachment()
+	}
+}
+
+// status returns a snapshot of the agent's current state.
+//
+// NOTE: This MUST only be called from within the controller goroutine.
+func (a *Agent) status() *Status {
+	status := &Status{
+		NumChannels:     len(a.chanState),
+		NumPendingOpens: len(a.pendingOpens),
+	}
+	for _, channel := range a.chanState {
+		status.TotalAllocation += channel.Capacity
+	}
+
+	return status
+}
+
+// attemptAttachment consults the heuristic to determine if we need more
+// channels, and if so, executes the attachment directives it recommends.
+//
+// NOTE: This MUST only be called from within the controller goroutine.
+func (a *Agent) attemptAttachment() {
+	// With all the updates applied, we'll obtain a set of the current
+	// active channels (confirmed channels), and also factor in our set of
+	// unconfirmed channels.
+	totalChans := a.chanState.Channels()
+	for _, pendingChan := range a.pendingOpens {
+		totalChans = append(totalChans, pendingChan)
+	}
+
+	walletBalance, err := a.cfg.WalletBalance()
+	if err != nil {
+		log.Errorf("Unable to fetch wallet balance: %v", err)
+		return
+	}
+
+	// Now that we've updated our internal state, we'll consult our
+	// channel attachment heuristic to determine if we should open
+	// up any additional channels or modify existing channels.
+	availableFunds, numChans, needMore := a.cfg.Heuristic.NeedMoreChans(
+		totalChans, walletBalance,
+	)
+	if !needMore {
+		return
+	}
+
+	log.Infof("Triggering attachment directive dispatch, "+
+		"available_funds=%v, num_chans=%v", availableFunds, numChans)
+
+	// We're to attempt an attachment so we'll obtain the set of
+	// nodes that we currently have channels with, or have pending
+	// channels to, so we avoid duplicate edges. Nodes we've recently
+	// failed to open a channel to are skipped as well, while those whose
+	// backoff has passed are forgotten.
+	nodesToSkip := a.chanState.ConnectedNodes()
+	for nodeID := range a.pendingOpens {
+		nodesToSkip[nodeID] = struct{}{}
+	}
+	for nodeID, failedAt := range a.failedNodes {
+		if time.Since(failedAt) >= a.cfg.FailedNodeBackoff {
+			delete(a.failedNodes, nodeID)
+			continue
+		}
+		nodesToSkip[nodeID] = struct{}{}
+	}
+
+	// If we do need more channels, then we'll query the heuristic
+	// to obtain a set of attachment directives that describe which
+	// channels we should open.
+	chanCandidates, err := a.cfg.Heuristic.Select(
+		a.cfg.Self, a.cfg.Graph, availableFunds, numChans,
+		nodesToSkip,
+	)
+	if err != nil {
+		log.Errorf("Unable to select candidates for "+
+			"attachment: %v", err)
+		return
+	}
+
+	if len(chanCandidates) == 0 {
+		log.Infof("No eligible candidates to connect to")
+		return
+	}
+
+	log.Infof("Attempting to execute channel attachment "+
+		"directives: %v", newLogClosure(func() string {
+		return spew.Sdump(chanCandidates)
+	}))
+
+	// For each recommended attachment directive, we'll launch a
+	// new goroutine to attempt to carry out the directive. If any
+	// of these succeed, then we'll receive a new state update,
+	// taking us back to the top of our controller loop.
+	for _, chanCandidate := range chanCandidates {
+		nodeID := NewNodeID(chanCandidate.PeerKey)
+		a.pendingOpens[nodeID] = Channel{
+			Capacity: chanCandidate.ChanAmt,
+			Node:     nodeID,
+		}
+
+		a.wg.Add(1)
+		go func(directive AttachmentDirective) {
+			defer a.wg.Done()
+
+			pub := directive.PeerKey
+			err := a.cfg.ChanController.OpenChannel(
+				directive.PeerKey, directive.ChanAmt,
+				directive.Addrs,
+			)
+			if err != nil {
+				log.Warnf("Unable to open channel to %x of "+
+					"%v: %v", pub.SerializeCompressed(),
+					directive.ChanAmt, err)
+
+				// As the attempt failed, we'll clear the
+				// peer from the set of pending opens and
+				// mark them as failed so we can retry
+				// later.
+				a.sendUpdate(&chanOpenFailureUpdate{
+					nodeID: NewNodeID(pub),
+				})
+				return
+			}
+
+			// The channel will be added to our channel state
+			// once it has been announced as open by the backing
+			// Lightning Node via OnChannelOpen.
+			log.Infof("Funding transaction for channel to %x "+
+				"of %v broadcast", pub.SerializeCompressed(),
+				directive.ChanAmt)
+		}(chanCandidate)
+	}
+}
@@ -0,0 +1,400 @@
+package autopilot
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+// moreChansResp is the response the mockHeuristic returns for a call to
+// NeedMoreChans.
+type moreChansResp struct {
+	needMore bool
+	numMore  uint32
+	amt      btcutil.Amount
+}
+
+// mockHeuristic is a mock AttachmentHeuristic which hands the parameters of
+// each call to the test, and returns the responses the test provides. The
+// argument channels are buffered, so tests must drain them in order to
+// inspect the arguments of later calls.
+type mockHeuristic struct {
+	moreChansResps chan moreChansResp
+	moreChanArgs   chan []Channel
+
+	directiveResps chan []AttachmentDirective
+	directiveArgs  chan map[NodeID]struct{}
+}
+
+func (m *mockHeuristic) NeedMoreChans(chans []Channel,
+	balance btcutil.Amount) (btcutil.Amount, uint32, bool) {
+
+	m.moreChanArgs <- chans
+
+	resp := <-m.moreChansResps
+	return resp.amt, resp.numMore, resp.needMore
+}
+
+func (m *mockHeuristic) Select(self *btcec.PublicKey, graph ChannelGraph,
+	amtToUse btcutil.Amount, numNewChans uint32,
+	skipNodes map[NodeID]struct{}) ([]AttachmentDirective, error) {
+
+	m.directiveArgs <- skipNodes
+
+	resp := <-m.directiveResps
+	return resp, nil
+}
+
+var _ AttachmentHeuristic = (*mockHeuristic)(nil)
+
+// openChanIntent records a call to the mockChanController.
+type openChanIntent struct {
+	target *btcec.PublicKey
+	amt    btcutil.Amount
+	addrs  []net.Addr
+}
+
+// mockChanController is a mock ChannelController which records every channel
+// open request, optionally failing them.
+type mockChanController struct {
+	openChanSignals chan openChanIntent
+	fail            bool
+}
+
+func (m *mockChanController) OpenChannel(target *btcec.PublicKey,
+	amt btcutil.Amount, addrs []net.Addr) error {
+
+	m.openChanSignals <- openChanIntent{
+		target: target,
+		amt:    amt,
+		addrs:  addrs,
+	}
+
+	if m.fail {
+		return fmt.Errorf("unable to open channel")
+	}
+
+	return nil
+}
+
+var _ ChannelController = (*mockChanController)(nil)
+
+// agentHarness couples an autopilot Agent with the mocks that back it.
+type agentHarness struct {
+	agent      *Agent
+	heuristic  *mockHeuristic
+	controller *mockChanController
+
+	balanceMtx sync.Mutex
+	balance    btcutil.Amount
+}
+
+// newAgentHarness creates and starts a new Agent backed by mocks, using the
+// passed initial channel state.
+func newAgentHarness(t *testing.T, initialChans []Channel,
+	failOpens bool, failedNodeBackoff time.Duration) *agentHarness {
+
+	h := &agentHarness{
+		heuristic: &mockHeuristic{
+			moreChansResps: make(chan moreChansResp),
+			moreChanArgs:   make(chan []Channel, 10),
+			directiveResps: make(chan []AttachmentDirective),
+			directiveArgs:  make(chan map[NodeID]struct{}, 10),
+		},
+		controller: &mockChanController{
+			openChanSignals: make(chan openChanIntent, 10),
+			fail:            failOpens,
+		},
+		balance: btcutil.SatoshiPerBitcoin,
+	}
+
+	cfg := Config{
+		Self:           randKey(t),
+		Heuristic:      h.heuristic,
+		ChanController: h.controller,
+		WalletBalance: func() (btcutil.Amount, error) {
+			h.balanceMtx.Lock()
+			defer h.balanceMtx.Unlock()
+			return h.balance, nil
+		},
+		Graph:             newMemChannelGraph(),
+		FailedNodeBackoff: failedNodeBackoff,
+	}
+	agent, err := New(cfg, initialChans)
+	if err != nil {
+		t.Fatalf("unable to create agent: %v", err)
+	}
+	if err := agent.Start(); err != nil {
+		t.Fatalf("unable to start agent: %v", err)
+	}
+	h.agent = agent
+
+	return h
+}
+
+// respondMoreChans delivers the passed response to the heuristic's next
+// NeedMoreChans call, failing the test if the call isn't made in time.
+func (h *agentHarness) respondMoreChans(t *testing.T, resp moreChansResp) {
+	select {
+	case h.heuristic.moreChansResps <- resp:
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+}
+
+// respondSelect delivers the passed directives to the heuristic's next Select
+// call, failing the test if the call isn't made in time.
+func (h *agentHarness) respondSelect(t *testing.T,
+	directives []AttachmentDirective) {
+
+	select {
+	case h.heuristic.directiveResps <- directives:
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+}
+
+// TestAgentChannelOpenSignal tests that upon receipt of a chanOpenUpdate, then
+// agent modifies its local state accordingly, and reconsults the heuristic.
+func TestAgentChannelOpenSignal(t *testing.T) {
+	t.Parallel()
+
+	h := newAgentHarness(t, nil, false, DefaultFailedNodeBackoff)
+	defer h.agent.Stop()
+
+	// The agent is kicked off with an initial balance update, so we'll
+	// have it indicate that it doesn't need any more channels.
+	h.respondMoreChans(t, moreChansResp{false, 0, 0})
+	<-h.heuristic.moreChanArgs
+
+	// Next we'll signal a new channel being opened by the backing LN
+	// node, with a capacity of 1 BTC.
+	newChan := Channel{
+		ChanPoint: nextChanPoint(),
+		Capacity:  btcutil.SatoshiPerBitcoin,
+		Node:      NewNodeID(randKey(t)),
+	}
+	h.agent.OnChannelOpen(newChan)
+
+	// The agent should now query the heuristic, passing the new channel
+	// within its state.
+	h.respondMoreChans(t, moreChansResp{false, 0, 0})
+	select {
+	case chans := <-h.heuristic.moreChanArgs:
+		if len(chans) != 1 || chans[0] != newChan {
+			t.Fatalf("expected channel %v, instead got %v",
+				newChan, chans)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	status, err := h.agent.Status()
+	if err != nil {
+		t.Fatalf("unable to query status: %v", err)
+	}
+	if status.NumChannels != 1 ||
+		status.TotalAllocation != newChan.Capacity {
+
+		t.Fatalf("unexpected status: %v", spewStatus(status))
+	}
+
+	// If we close the channel, then the agent should remove it from its
+	// state.
+	h.agent.OnChannelClose(newChan.ChanPoint)
+	h.respondMoreChans(t, moreChansResp{false, 0, 0})
+	select {
+	case chans := <-h.heuristic.moreChanArgs:
+		if len(chans) != 0 {
+			t.Fatalf("expected no channels, instead got %v",
+				chans)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	// No channels should've been opened during this process.
+	select {
+	case <-h.controller.openChanSignals:
+		t.Fatalf("agent shouldn't have opened a channel")
+	default:
+	}
+}
+
+// TestAgentBalanceUpdate tests that upon receipt of a balance update, the
+// agent executes the directives returned by the heuristic, and tracks the
+// resulting channels as pending so they're skipped in future attempts.
+func TestAgentBalanceUpdate(t *testing.T) {
+	t.Parallel()
+
+	h := newAgentHarness(t, nil, false, DefaultFailedNodeBackoff)
+	defer h.agent.Stop()
+
+	// We'll have the initial balance update indicate we need two more
+	// channels, and return two directives.
+	h.respondMoreChans(t, moreChansResp{true, 2, btcutil.SatoshiPerBitcoin})
+
+	nodeKey1, nodeKey2 := randKey(t), randKey(t)
+	directives := []AttachmentDirective{
+		{
+			PeerKey: nodeKey1,
+			ChanAmt: btcutil.SatoshiPerBitcoin / 2,
+			Addrs:   []net.Addr{testAddr},
+		},
+		{
+			PeerKey: nodeKey2,
+			ChanAmt: btcutil.SatoshiPerBitcoin / 2,
+			Addrs:   []net.Addr{testAddr},
+		},
+	}
+	h.respondSelect(t, directives)
+	<-h.heuristic.moreChanArgs
+	<-h.heuristic.directiveArgs
+
+	// The agent should now open a channel for each of the directives.
+	opened := make(map[NodeID]btcutil.Amount)
+	for i := 0; i < len(directives); i++ {
+		select {
+		case intent := <-h.controller.openChanSignals:
+			opened[NewNodeID(intent.target)] = intent.amt
+		case <-time.After(time.Second * 10):
+			t.Fatalf("channel not opened in time")
+		}
+	}
+	for _, directive := range directives {
+		amt, ok := opened[NewNodeID(directive.PeerKey)]
+		if !ok {
+			t.Fatalf("channel to %x not opened",
+				directive.PeerKey.SerializeCompressed())
+		}
+		if amt != directive.ChanAmt {
+			t.Fatalf("expected chan amt %v, instead got %v",
+				directive.ChanAmt, amt)
+		}
+	}
+
+	// With the channels pending, a new balance update should cause the
+	// agent to pass both pending channels to the heuristic, and skip
+	// both nodes when selecting new candidates.
+	h.agent.OnBalanceChange()
+
+	h.respondMoreChans(t, moreChansResp{true, 1, btcutil.SatoshiPerBitcoin})
+	select {
+	case chans := <-h.heuristic.moreChanArgs:
+		if len(chans) != 2 {
+			t.Fatalf("expected 2 pending channels, instead got %v",
+				len(chans))
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	h.respondSelect(t, nil)
+	select {
+	case skipNodes := <-h.heuristic.directiveArgs:
+		for _, key := range []*btcec.PublicKey{nodeKey1, nodeKey2} {
+			if _, ok := skipNodes[NewNodeID(key)]; !ok {
+				t.Fatalf("pending node %x not skipped",
+					key.SerializeCompressed())
+			}
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	status, err := h.agent.Status()
+	if err != nil {
+		t.Fatalf("unable to query status: %v", err)
+	}
+	if status.NumPendingOpens != 2 {
+		t.Fatalf("expected 2 pending opens, instead got %v",
+			status.NumPendingOpens)
+	}
+}
+
+// TestAgentOpenFailure tests that if the agent fails to open a channel to a
+// node, then the pending state is cleared, and the node is skipped in all
+// subsequent attempts until the failed node backoff has passed.
+func TestAgentOpenFailure(t *testing.T) {
+	t.Parallel()
+
+	const failedNodeBackoff = time.Second
+	h := newAgentHarness(t, nil, true, failedNodeBackoff)
+	defer h.agent.Stop()
+
+	h.respondMoreChans(t, moreChansResp{true, 1, btcutil.SatoshiPerBitcoin})
+
+	nodeKey := randKey(t)
+	h.respondSelect(t, []AttachmentDirective{
+		{
+			PeerKey: nodeKey,
+			ChanAmt: btcutil.SatoshiPerBitcoin,
+			Addrs:   []net.Addr{testAddr},
+		},
+	})
+	<-h.heuristic.moreChanArgs
+	<-h.heuristic.directiveArgs
+
+	select {
+	case <-h.controller.openChanSignals:
+	case <-time.After(time.Second * 10):
+		t.Fatalf("channel not opened in time")
+	}
+
+	// As the open failed, the agent should re-examine its state. The
+	// failed channel should no longer be counted as pending, and the
+	// failed node should be skipped.
+
+	h.respondMoreChans(t, moreChansResp{true, 1, btcutil.SatoshiPerBitcoin})
+	select {
+	case chans := <-h.heuristic.moreChanArgs:
+		if len(chans) != 0 {
+			t.Fatalf("expected no pending channels, instead "+
+				"got %v", len(chans))
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	h.respondSelect(t, nil)
+	select {
+	case skipNodes := <-h.heuristic.directiveArgs:
+		if _, ok := skipNodes[NewNodeID(nodeKey)]; !ok {
+			t.Fatalf("failed node %x not skipped",
+				nodeKey.SerializeCompressed())
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+
+	// Once the backoff has passed, the failed node should no longer be
+	// skipped when the agent next selects new candidates.
+	time.Sleep(failedNodeBackoff)
+	h.agent.OnBalanceChange()
+
+	h.respondMoreChans(t, moreChansResp{true, 1, btcutil.SatoshiPerBitcoin})
+	<-h.heuristic.moreChanArgs
+
+	h.respondSelect(t, nil)
+	select {
+	case skipNodes := <-h.heuristic.directiveArgs:
+		if _, ok := skipNodes[NewNodeID(nodeKey)]; ok {
+			t.Fatalf("failed node %x skipped after backoff",
+				nodeKey.SerializeCompressed())
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatalf("heuristic wasn't queried in time")
+	}
+}
+
+// spewStatus returns a string representation of the passed status for use
+// within test failure messages.
+func spewStatus(s *Status) string {
+	return fmt.Sprintf("channels=%v, allocation=%v, pending=%v",
+		s.NumChannels, s.TotalAllocation, s.NumPendingOpens)
+}
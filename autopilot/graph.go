@@ -0,0 +1,236 @@
+package autopilot
+
+import (
+	"net"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// databaseChannelGraph wraps a channeldb.ChannelGraph instance with the
+// necessary API to properly implement the autopilot.ChannelGraph interface.
+type databaseChannelGraph struct {
+	db *channeldb.ChannelGraph
+}
+
+// A compile time assertion to ensure databaseChannelGraph meets the
+// autopilot.ChannelGraph interface.
+var _ ChannelGraph = (*databaseChannelGraph)(nil)
+
+// ChannelGraphFromDatabase returns an instance of the autopilot.ChannelGraph
+// backed by a live, open channeldb instance.
+func ChannelGraphFromDatabase(db *channeldb.ChannelGraph) ChannelGraph {
+	return &databaseChannelGraph{
+		db: db,
+	}
+}
+
+// dbNode is a wrapper struct around a channeldb.LightningNode. The wrapper
+// methods implement the autopilot.Node interface.
+type dbNode struct {
+	node *channeldb.LightningNode
+}
+
+// A compile time assertion to ensure dbNode meets the autopilot.Node
+// interface.
+var _ Node = (*dbNode)(nil)
+
+// PubKey is the identity public key of the node. This will be used to attempt
+// to target a node for channel opening by the main autopilot agent.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (d dbNode) PubKey() [33]byte {
+	return NewNodeID(d.node.PubKey)
+}
+
+// Addrs returns a slice of publicly reachable public TCP addresses that the
+// peer is known to be listening on.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (d dbNode) Addrs() []net.Addr {
+	if d.node.Address == nil {
+		return nil
+	}
+
+	return []net.Addr{d.node.Address}
+}
+
+// ForEachChannel is a higher-order function that will be used to iterate
+// through all edges emanating from/to the target node. For each active
+// channel, this function should be called with the populated ChannelEdge that
+// describes the active channel.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (d dbNode) ForEachChannel(cb func(ChannelEdge) error) error {
+	err := d.node.ForEachChannel(nil, func(edge *channeldb.ChannelEdge) error {
+		return cb(ChannelEdge{
+			Channel: Channel{
+				ChanPoint: edge.ChannelPoint,
+				Capacity:  edge.Capacity,
+				Node:      NewNodeID(edge.Node.PubKey),
+			},
+			Peer: dbNode{
+				node: edge.Node,
+			},
+		})
+	})
+	if err == channeldb.ErrGraphNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// ForEachNode is a higher-order function that should be called once for each
+// connected node within the channel graph. If the passed callback returns an
+// error, then execution should be terminated.
+//
+// NOTE: Part of the autopilot.ChannelGraph interface.
+func (d *databaseChannelGraph) ForEachNode(cb func(Node) error) error {
+	// We first read all the nodes into memory, as the callback will
+	// likely traverse the channels of each node, and we'd like to avoid
+	// nesting database transactions.
+	var nodes []*channeldb.LightningNode
+	err := d.db.ForEachNode(func(n *channeldb.LightningNode) error {
+		nodes = append(nodes, n)
+		return nil
+	})
+	switch {
+	case err == channeldb.ErrGraphNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, node := range nodes {
+		if err := cb(dbNode{node: node}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// memNode is a purely in-memory implementation of the autopilot.Node
+// interface.
+type memNode struct {
+	pub *btcec.PublicKey
+
+	chans []ChannelEdge
+
+	addrs []net.Addr
+}
+
+// A compile time assertion to ensure memNode meets the autopilot.Node
+// interface.
+var _ Node = (*memNode)(nil)
+
+// PubKey is the identity public key of the node. This will be used to attempt
+// to target a node for channel opening by the main autopilot agent.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (m memNode) PubKey() [33]byte {
+	return NewNodeID(m.pub)
+}
+
+// Addrs returns a slice of publicly reachable public TCP addresses that the
+// peer is known to be listening on.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (m memNode) Addrs() []net.Addr {
+	return m.addrs
+}
+
+// ForEachChannel is a higher-order function that will be used to iterate
+// through all edges emanating from/to the target node. For each active
+// channel, this function should be called with the populated ChannelEdge that
+// describes the active channel.
+//
+// NOTE: Part of the autopilot.Node interface.
+func (m memNode) ForEachChannel(cb func(ChannelEdge) error) error {
+	for _, channel := range m.chans {
+		if err := cb(channel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// memChannelGraph is an implementation of the autopilot.ChannelGraph backed by
+// an in-memory graph. This allows the heuristics and the agent to be
+// exercised without a backing database.
+type memChannelGraph struct {
+	graph map[NodeID]*memNode
+}
+
+// A compile time assertion to ensure memChannelGraph meets the
+// autopilot.ChannelGraph interface.
+var _ ChannelGraph = (*memChannelGraph)(nil)
+
+// newMemChannelGraph creates a new blank in-memory channel graph
+// implementation.
+func newMemChannelGraph() *memChannelGraph {
+	return &memChannelGraph{
+		graph: make(map[NodeID]*memNode),
+	}
+}
+
+// ForEachNode is a higher-order function that should be called once for each
+// connected node within the channel graph. If the passed callback returns an
+// error, then execution should be terminated.
+//
+// NOTE: Part of the autopilot.ChannelGraph interface.
+func (m *memChannelGraph) ForEachNode(cb func(Node) error) error {
+	for _, node := range m.graph {
+		if err := cb(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addNode adds a node with the passed public key and addresses to the graph.
+// If the node already exists, then the existing node is returned.
+func (m *memChannelGraph) addNode(pub *btcec.PublicKey,
+	addrs ...net.Addr) *memNode {
+
+	nodeID := NewNodeID(pub)
+	if node, ok := m.graph[nodeID]; ok {
+		return node
+	}
+
+	node := &memNode{
+		pub:   pub,
+		addrs: addrs,
+	}
+	m.graph[nodeID] = node
+
+	return node
+}
+
+// addChannel adds a new channel between the two passed nodes to the graph,
+// creating a directed edge in each direction.
+func (m *memChannelGraph) addChannel(chanPoint wire.OutPoint,
+	capacity btcutil.Amount, node1, node2 *memNode) {
+
+	node1.chans = append(node1.chans, ChannelEdge{
+		Channel: Channel{
+			ChanPoint: chanPoint,
+			Capacity:  capacity,
+			Node:      NewNodeID(node2.pub),
+		},
+		Peer: node2,
+	})
+	node2.chans = append(node2.chans, ChannelEdge{
+		Channel: Channel{
+			ChanPoint: chanPoint,
+			Capacity:  capacity,
+			Node:      NewNodeID(node1.pub),
+		},
+		Peer: node1,
+	})
+}
@@ -0,0 +1,251 @@
+package autopilot
+
+import (
+	"bytes"
+	prand "math/rand"
+	"net"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+// ConstrainedPrefAttachment is an implementation of the AttachmentHeuristic
+// interface that implements a constrained linear preferential attachment
+// heuristic. This means that given a threshold to allocate to automatic
+// channel establishment, the heuristic will attempt to favor connecting to
+// nodes which already have a large number of links, with the probability of
+// selecting a node being proportional to its degree. The heuristic is
+// constrained in that the total number of channels, the fraction of funds
+// allocated to channels, and the size of each channel are all bounded.
+type ConstrainedPrefAttachment struct {
+	minChanSize btcutil.Amount
+	maxChanSize btcutil.Amount
+
+	chanLimit uint16
+
+	threshold float64
+}
+
+// NewConstrainedPrefAttachment creates a new instance of a
+// ConstrainedPrefAttachment heuristics given bounds on allowed channel sizes,
+// and an allocation amount which is interpreted as a percentage of funds that
+// is to be committed to channels at all times.
+func NewConstrainedPrefAttachment(minChanSize, maxChanSize btcutil.Amount,
+	chanLimit uint16, allocation float64) *ConstrainedPrefAttachment {
+
+	return &ConstrainedPrefAttachment{
+		minChanSize: minChanSize,
+		chanLimit:   chanLimit,
+		maxChanSize: maxChanSize,
+		threshold:   allocation,
+	}
+}
+
+// init seeds the pseudo-random number generator used to sample nodes from
+// the channel graph.
+func init() {
+	prand.Seed(time.Now().Unix())
+}
+
+// A compile time assertion to ensure ConstrainedPrefAttachment meets the
+// AttachmentHeuristic interface.
+var _ AttachmentHeuristic = (*ConstrainedPrefAttachment)(nil)
+
+// NeedMoreChans is a predicate that should return true if, given the passed
+// parameters, and its internal state, more channels should be opened within
+// the channel graph. If the heuristic decides that we do indeed need more
+// channels, then the second argument returned will represent the amount of
+// additional funds to be used towards creating channels, and the third the
+// number of additional channels that may be opened.
+//
+// NOTE: This is a part of the AttachmentHeuristic interface.
+func (p *ConstrainedPrefAttachment) NeedMoreChans(channels []Channel,
+	funds btcutil.Amount) (btcutil.Amount, uint32, bool) {
+
+	// If we're already over our maximum allowed number of channels, then
+	// we'll instruct the controller not to create any more channels.
+	if len(channels) >= int(p.chanLimit) {
+		return 0, 0, false
+	}
+
+	numAdditionalChans := uint32(p.chanLimit) - uint32(len(channels))
+
+	// First, we'll tally up the total amount of funds that are currently
+	// present within the set of active channels.
+	var totalChanAllocation btcutil.Amount
+	for _, channel := range channels {
+		totalChanAllocation += channel.Capacity
+	}
+
+	// With this value known, we'll now compute the total amount of fund
+	// allocated across regular utxo's and channel utxo's.
+	totalFunds := funds + totalChanAllocation
+	if totalFunds == 0 {
+		return 0, 0, false
+	}
+
+	// Once the total amount has been computed, we then calculate the
+	// fraction of funds currently allocated to channels.
+	fundsFraction := float64(totalChanAllocation) / float64(totalFunds)
+
+	// If this fraction is below our threshold, then we'll return true, to
+	// indicate the controller should call Select to obtain a candidate set
+	// of channels to attempt to open.
+	needMore := fundsFraction < p.threshold
+	if !needMore {
+		return 0, 0, false
+	}
+
+	// Now that we know we need more funds, we'll compute the amount of
+	// additional funds we should allocate towards channels.
+	targetAllocation := btcutil.Amount(float64(totalFunds) * p.threshold)
+	fundsAvailable := targetAllocation - totalChanAllocation
+	if fundsAvailable > funds {
+		fundsAvailable = funds
+	}
+
+	return fundsAvailable, numAdditionalChans, true
+}
+
+// nodeWithDegree couples a node within the channel graph with the number of
+// channels it currently has open, which is the signal used to weight the
+// node during selection.
+type nodeWithDegree struct {
+	node   Node
+	degree int
+}
+
+// Select returns a candidate set of attachment directives that should be
+// executed based on the current internal state, the state of the channel
+// graph, the set of nodes we should exclude, and the amount of funds
+// available. The heuristic employed by this method is one that attempts to
+// promote a scale-free network globally, via local attachment preferences for
+// new nodes joining the network with an amount of available funds to be
+// allocated to channels. Specifically, we consider the degree of each node
+// and utilize the Barabási–Albert model to drive our recommended attachment
+// heuristics. If implemented globally for each new participant, this results
+// in a channel graph that is scale-free and follows a power law distribution.
+//
+// The available funds are split evenly across the selected nodes, with each
+// channel capped at the maximum channel size. No more channels than the
+// available funds can support at the minimum channel size are returned.
+//
+// NOTE: This is a part of the AttachmentHeuristic interface.
+func (p *ConstrainedPrefAttachment) Select(self *btcec.PublicKey,
+	g ChannelGraph, fundsAvailable btcutil.Amount, numNewChans uint32,
+	skipNodes map[NodeID]struct{}) ([]AttachmentDirective, error) {
+
+	// If we don't have enough funds to open even a single channel of the
+	// minimum size, then there's nothing to be done.
+	if fundsAvailable < p.minChanSize || numNewChans == 0 {
+		return nil, nil
+	}
+
+	// We'll cap the number of channels we open at the number of minimum
+	// sized channels the available funds can support.
+	if p.minChanSize != 0 {
+		maxChans := uint32(fundsAvailable / p.minChanSize)
+		if maxChans < numNewChans {
+			numNewChans = maxChans
+		}
+	}
+
+	// We'll continue by gathering all eligible candidates within the
+	// graph along with their degree. We skip ourselves, any nodes the
+	// caller instructed us to ignore, as well as nodes which haven't
+	// advertised an address, as we'd be unable to connect out to them.
+	selfPubBytes := self.SerializeCompressed()
+	var (
+		candidates  []nodeWithDegree
+		totalDegree int
+	)
+	if err := g.ForEachNode(func(node Node) error {
+		nodeID := NodeID(node.PubKey())
+		if bytes.Equal(nodeID[:], selfPubBytes) {
+			return nil
+		}
+		if _, ok := skipNodes[nodeID]; ok {
+			return nil
+		}
+		if len(node.Addrs()) == 0 {
+			return nil
+		}
+
+		var degree int
+		err := node.ForEachChannel(func(_ ChannelEdge) error {
+			degree++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Nodes without any channels have a zero probability of being
+		// selected, so we don't consider them as candidates.
+		if degree == 0 {
+			return nil
+		}
+
+		candidates = append(candidates, nodeWithDegree{node, degree})
+		totalDegree += degree
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// With the set of candidates obtained, we'll now sample from them
+	// without replacement. The probability of a node being selected is
+	// proportional to its degree: p_i = k_i / sum(k_j).
+	var selected []Node
+	for uint32(len(selected)) < numNewChans && len(candidates) > 0 {
+		target := prand.Intn(totalDegree)
+
+		var i int
+		for ; i < len(candidates); i++ {
+			target -= candidates[i].degree
+			if target < 0 {
+				break
+			}
+		}
+
+		selected = append(selected, candidates[i].node)
+
+		// Remove the selected node from the candidate set, so we
+		// don't attempt to open two channels to the same node.
+		totalDegree -= candidates[i].degree
+		candidates[i] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+	}
+
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	// We'll split the available funds evenly amongst the selected nodes,
+	// capped at the maximum channel size.
+	chanSize := fundsAvailable / btcutil.Amount(len(selected))
+	if chanSize > p.maxChanSize {
+		chanSize = p.maxChanSize
+	}
+
+	directives := make([]AttachmentDirective, 0, len(selected))
+	for _, node := range selected {
+		pubBytes := node.PubKey()
+		pub, err := btcec.ParsePubKey(pubBytes[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		addrs := make([]net.Addr, len(node.Addrs()))
+		copy(addrs, node.Addrs())
+
+		directives = append(directives, AttachmentDirective{
+			PeerKey: pub,
+			ChanAmt: chanSize,
+			Addrs:   addrs,
+		})
+	}
+
+	return directives, nil
+}
@@ -0,0 +1,371 @@
+package autopilot
+
+import (
+	"bytes"
+	prand "math/rand"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	testAddr = &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 9000,
+	}
+
+	chanIndex uint32
+)
+
+// randKey returns a freshly generated public key.
+func randKey(t *testing.T) *btcec.PublicKey {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	return priv.PubKey()
+}
+
+// nextChanPoint returns a unique funding outpoint for a test channel.
+func nextChanPoint() wire.OutPoint {
+	return wire.OutPoint{
+		Index: atomic.AddUint32(&chanIndex, 1),
+	}
+}
+
+// TestConstrainedPrefAttachmentNeedMoreChans tests that the
+// ConstrainedPrefAttachment heuristic properly reports when it needs more
+// channels, along with the funds and channels it may allocate.
+func TestConstrainedPrefAttachmentNeedMoreChans(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = 0
+		maxChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+
+		chanLimit = 3
+
+		threshold = 0.5
+	)
+
+	prefAttach := NewConstrainedPrefAttachment(minChanSize, maxChanSize,
+		chanLimit, threshold)
+
+	testCases := []struct {
+		channels  []Channel
+		walletAmt btcutil.Amount
+
+		needMore     bool
+		amtAvailable btcutil.Amount
+		numMore      uint32
+	}{
+		// Many available funds, but already have too many active open
+		// channels.
+		{
+			[]Channel{
+				{Capacity: btcutil.Amount(prand.Int31())},
+				{Capacity: btcutil.Amount(prand.Int31())},
+				{Capacity: btcutil.Amount(prand.Int31())},
+			},
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 10),
+			false,
+			0,
+			0,
+		},
+
+		// Ratio of funds in channels and total funds meets the
+		// threshold.
+		{
+			[]Channel{
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+			},
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 2),
+			false,
+			0,
+			0,
+		},
+
+		// Ratio of funds in channels and total funds is below the
+		// threshold. We have 10 BTC allocated amongst channels and
+		// funds, atm. We're targeting 50%, so 5 BTC should be
+		// allocated. Only 1 BTC is atm, so 4 BTC should be
+		// recommended. We should also request 2 more channels as the
+		// limit is 3.
+		{
+			[]Channel{
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+			},
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 9),
+			true,
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 4),
+			2,
+		},
+
+		// Ratio of funds in channels and total funds is below the
+		// threshold. We have 14 BTC total amongst the wallet's
+		// balance, and our currently opened channels. Since we're
+		// targeting a 50% allocation, we should commit 7 BTC. The
+		// current channels commit 4 BTC, so we should expected 3 BTC
+		// to be committed. We should only request a single additional
+		// channel as the limit is 3.
+		{
+			[]Channel{
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin * 3)},
+			},
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 10),
+			true,
+			btcutil.Amount(btcutil.SatoshiPerBitcoin * 3),
+			1,
+		},
+
+		// Ratio of funds in channels and total funds is above the
+		// threshold.
+		{
+			[]Channel{
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+				{Capacity: btcutil.Amount(btcutil.SatoshiPerBitcoin)},
+			},
+			btcutil.Amount(btcutil.SatoshiPerBitcoin),
+			false,
+			0,
+			0,
+		},
+
+		// No funds at all, so we can't open any channels.
+		{
+			nil,
+			0,
+			false,
+			0,
+			0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		amtToAllocate, numMore, needMore := prefAttach.NeedMoreChans(
+			testCase.channels, testCase.walletAmt,
+		)
+
+		if amtToAllocate != testCase.amtAvailable {
+			t.Fatalf("test #%v: expected %v, got %v",
+				i, testCase.amtAvailable, amtToAllocate)
+		}
+		if needMore != testCase.needMore {
+			t.Fatalf("test #%v: expected %v, got %v",
+				i, testCase.needMore, needMore)
+		}
+		if numMore != testCase.numMore {
+			t.Fatalf("test #%v: expected %v, got %v",
+				i, testCase.numMore, numMore)
+		}
+	}
+}
+
+// TestConstrainedPrefAttachmentSelectEmptyGraph ensures that when passed an
+// empty graph, the Select function always detects the state, and returns nil.
+// Otherwise, it would be possible for the main Select loop to enter an
+// infinite loop.
+func TestConstrainedPrefAttachmentSelectEmptyGraph(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = 0
+		maxChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		chanLimit   = 3
+		threshold   = 0.5
+	)
+
+	prefAttach := NewConstrainedPrefAttachment(minChanSize, maxChanSize,
+		chanLimit, threshold)
+
+	graph := newMemChannelGraph()
+	self := randKey(t)
+
+	directives, err := prefAttach.Select(self, graph,
+		btcutil.SatoshiPerBitcoin, 5, nil)
+	if err != nil {
+		t.Fatalf("unable to select attachment directives: %v", err)
+	}
+	if len(directives) != 0 {
+		t.Fatalf("zero attachment directives should have been "+
+			"returned instead %v were", len(directives))
+	}
+}
+
+// TestConstrainedPrefAttachmentSelectTwoVertexes ensures that when passed a
+// graph with only two eligible vertexes, then both are selected (without any
+// repeats), and the funds are appropriately allocated across each peer.
+func TestConstrainedPrefAttachmentSelectTwoVertexes(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = 0
+		maxChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		chanLimit   = 3
+		threshold   = 0.5
+	)
+
+	prefAttach := NewConstrainedPrefAttachment(minChanSize, maxChanSize,
+		chanLimit, threshold)
+
+	graph := newMemChannelGraph()
+	self := randKey(t)
+
+	node1 := graph.addNode(randKey(t), testAddr)
+	node2 := graph.addNode(randKey(t), testAddr)
+	graph.addChannel(nextChanPoint(), maxChanSize, node1, node2)
+
+	// With the channel added, we'll now attempt to select a set of
+	// directives to use. We'll ask for five channels, but as there are
+	// only two eligible nodes, both should be selected exactly once.
+	const amtToAllocate = btcutil.SatoshiPerBitcoin * 10
+	directives, err := prefAttach.Select(self, graph, amtToAllocate, 5,
+		nil)
+	if err != nil {
+		t.Fatalf("unable to select attachment directives: %v", err)
+	}
+	if len(directives) != 2 {
+		t.Fatalf("two attachment directives should have been "+
+			"generated instead %v were", len(directives))
+	}
+
+	seen := make(map[NodeID]struct{})
+	for _, directive := range directives {
+		nodeID := NewNodeID(directive.PeerKey)
+		if _, ok := seen[nodeID]; ok {
+			t.Fatalf("node %x selected twice", nodeID[:])
+		}
+		seen[nodeID] = struct{}{}
+
+		if nodeID != node1.PubKey() && nodeID != node2.PubKey() {
+			t.Fatalf("unknown node %x selected", nodeID[:])
+		}
+
+		// As the amount to allocate is well above the maximum
+		// channel size, each channel should be capped at it.
+		if directive.ChanAmt != maxChanSize {
+			t.Fatalf("max channel size should be allocated, "+
+				"instead %v was: ", directive.ChanAmt)
+		}
+		if len(directive.Addrs) != 1 ||
+			directive.Addrs[0].String() != testAddr.String() {
+
+			t.Fatalf("unexpected addrs: %v", directive.Addrs)
+		}
+	}
+}
+
+// TestConstrainedPrefAttachmentSelectSkipNodes ensures that nodes within the
+// skip set, ourselves, and nodes without any advertised addresses are never
+// selected.
+func TestConstrainedPrefAttachmentSelectSkipNodes(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = 0
+		maxChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		chanLimit   = 3
+		threshold   = 0.5
+	)
+
+	prefAttach := NewConstrainedPrefAttachment(minChanSize, maxChanSize,
+		chanLimit, threshold)
+
+	graph := newMemChannelGraph()
+	self := graph.addNode(randKey(t), testAddr)
+	skipped := graph.addNode(randKey(t), testAddr)
+	noAddrs := graph.addNode(randKey(t))
+	target := graph.addNode(randKey(t), testAddr)
+
+	graph.addChannel(nextChanPoint(), maxChanSize, self, skipped)
+	graph.addChannel(nextChanPoint(), maxChanSize, skipped, noAddrs)
+	graph.addChannel(nextChanPoint(), maxChanSize, noAddrs, target)
+
+	skipNodes := map[NodeID]struct{}{
+		NodeID(skipped.PubKey()): {},
+	}
+	directives, err := prefAttach.Select(self.pub, graph,
+		btcutil.SatoshiPerBitcoin, 3, skipNodes)
+	if err != nil {
+		t.Fatalf("unable to select attachment directives: %v", err)
+	}
+	if len(directives) != 1 {
+		t.Fatalf("one attachment directive should have been "+
+			"generated instead %v were", len(directives))
+	}
+
+	targetPub := target.PubKey()
+	if !bytes.Equal(directives[0].PeerKey.SerializeCompressed(),
+		targetPub[:]) {
+
+		t.Fatalf("wrong node selected: %x",
+			directives[0].PeerKey.SerializeCompressed())
+	}
+}
+
+// TestConstrainedPrefAttachmentSelectInsufficientFunds ensures that if the
+// available funds are below the minimum channel size, then no directives are
+// returned, and that the number of channels is limited by the number of
+// minimum sized channels the funds can support.
+func TestConstrainedPrefAttachmentSelectInsufficientFunds(t *testing.T) {
+	t.Parallel()
+
+	const (
+		minChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin)
+		maxChanSize = btcutil.Amount(btcutil.SatoshiPerBitcoin * 5)
+		chanLimit   = 10
+		threshold   = 0.5
+	)
+
+	prefAttach := NewConstrainedPrefAttachment(minChanSize, maxChanSize,
+		chanLimit, threshold)
+
+	graph := newMemChannelGraph()
+	self := randKey(t)
+
+	nodes := make([]*memNode, 5)
+	for i := range nodes {
+		nodes[i] = graph.addNode(randKey(t), testAddr)
+	}
+	for i := 1; i < len(nodes); i++ {
+		graph.addChannel(nextChanPoint(), minChanSize, nodes[0],
+			nodes[i])
+	}
+
+	// With funds below the minimum channel size, no directives should be
+	// returned.
+	directives, err := prefAttach.Select(self, graph, minChanSize-1, 5,
+		nil)
+	if err != nil {
+		t.Fatalf("unable to select attachment directives: %v", err)
+	}
+	if len(directives) != 0 {
+		t.Fatalf("zero attachment directives should have been "+
+			"returned instead %v were", len(directives))
+	}
+
+	// With enough funds for two and a half minimum sized channels, only
+	// two directives should be returned, splitting the funds evenly.
+	amt := minChanSize*2 + minChanSize/2
+	directives, err = prefAttach.Select(self, graph, amt, 5, nil)
+	if err != nil {
+		t.Fatalf("unable to select attachment directives: %v", err)
+	}
+	if len(directives) != 2 {
+		t.Fatalf("two attachment directives should have been "+
+			"returned instead %v were", len(directives))
+	}
+	for _, directive := range directives {
+		if directive.ChanAmt != amt/2 {
+			t.Fatalf("expected chan amt of %v, instead got %v",
+				amt/2, directive.ChanAmt)
+		}
+	}
+}
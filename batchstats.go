@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// batchSizeBuckets are the upper bounds of the buckets used to build a
+// histogram of the number of HTLC updates included within each commitment
+// update. The final bucket catches all batches larger than the last bound.
+var batchSizeBuckets = []int{1, 2, 5, 10, 20, 50}
+
+// batchStats tracks metrics concerning the size of the batches of HTLC updates
+// that are committed to within a single commitment update. These metrics are
+// useful in tuning the htlcbatchsize and htlccommitinterval configuration
+// parameters: if most batches are of size one, then the commit interval is
+// likely too short to allow any batching to occur, while if most batches hit
+// the maximum batch size, then the batch size may be too restrictive.
+//
+// NOTE: All methods of the batchStats are safe for concurrent use.
+type batchStats struct {
+	sync.Mutex
+
+	// numBatches is the total number of commitment updates which included
+	// at least a single HTLC update.
+	numBatches uint64
+
+	// numUpdates is the total number of HTLC updates across all batches.
+	numUpdates uint64
+
+	// maxBatch is the size of the largest batch recorded thus far.
+	maxBatch int
+
+	// histogram houses a count of batches for each of the buckets defined
+	// by batchSizeBuckets, with a final bucket for any larger batches.
+	histogram []uint64
+}
+
+// newBatchStats returns a new empty instance of the batchStats.
+func newBatchStats() *batchStats {
+	return &batchStats{
+		histogram: make([]uint64, len(batchSizeBuckets)+1),
+	}
+}
+
+// recordBatch records a new commitment update which included batchSize
+// pending HTLC updates. Empty batches aren't recorded as they carry no
+// information w.r.t the effectiveness of batching.
+func (b *batchStats) recordBatch(batchSize int) {
+	if batchSize == 0 {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.numBatches++
+	b.numUpdates += uint64(batchSize)
+	if batchSize > b.maxBatch {
+		b.maxBatch = batchSize
+	}
+
+	// Locate the first bucket whose upper bound is able to contain this
+	// batch, falling back to the overflow bucket if none is found.
+	bucket := len(batchSizeBuckets)
+	for i, bound := range batchSizeBuckets {
+		if batchSize <= bound {
+			bucket = i
+			break
+		}
+	}
+	b.histogram[bucket]++
+}
+
+// avgBatchSize returns the average number of HTLC updates included within
+// each recorded batch.
+func (b *batchStats) avgBatchSize() float64 {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.numBatches == 0 {
+		return 0
+	}
+
+	return float64(b.numUpdates) / float64(b.numBatches)
+}
+
+// rpcStats returns a snapshot of the recorded batch metrics in the form
+// returned over the RPC interface.
+func (b *batchStats) rpcStats() *lnrpc.HTLCBatchStats {
+	b.Lock()
+	defer b.Unlock()
+
+	var avg float64
+	if b.numBatches != 0 {
+		avg = float64(b.numUpdates) / float64(b.numBatches)
+	}
+
+	return &lnrpc.HTLCBatchStats{
+		NumBatches:   b.numBatches,
+		NumUpdates:   b.numUpdates,
+		AvgBatchSize: avg,
+		MaxBatchSize: uint32(b.maxBatch),
+		Histogram:    append([]uint64(nil), b.histogram...),
+	}
+}
+
+// String returns a human readable summary of the recorded batch metrics.
+//
+// NOTE: Part of the fmt.Stringer interface.
+func (b *batchStats) String() string {
+	b.Lock()
+	defer b.Unlock()
+
+	var avg float64
+	if b.numBatches != 0 {
+		avg = float64(b.numUpdates) / float64(b.numBatches)
+	}
+
+	buckets := ""
+	for i, count := range b.histogram {
+		if i < len(batchSizeBuckets) {
+			buckets += fmt.Sprintf("<=%v:%v ", batchSizeBuckets[i], count)
+		} else {
+			buckets += fmt.Sprintf(">%v:%v",
+				batchSizeBuckets[len(batchSizeBuckets)-1], count)
+		}
+	}
+
+	return fmt.Sprintf("batches=%v, updates=%v, avg_size=%.2f, "+
+		"max_size=%v, histogram=[%v]", b.numBatches, b.numUpdates, avg,
+		b.maxBatch, buckets)
+}
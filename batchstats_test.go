@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func TestBatchStatsRecord(t *testing.T) {
+	stats := newBatchStats()
+
+	// Empty batches shouldn't be recorded at all.
+	stats.recordBatch(0)
+	if stats.numBatches != 0 {
+		t.Fatalf("empty batch recorded: %v", stats.numBatches)
+	}
+
+	// Record a series of batches which span each of the histogram
+	// buckets, including the final overflow bucket.
+	batches := []int{1, 2, 4, 10, 15, 50, 64}
+	for _, batch := range batches {
+		stats.recordBatch(batch)
+	}
+
+	if stats.numBatches != uint64(len(batches)) {
+		t.Fatalf("expected %v batches, instead have %v", len(batches),
+			stats.numBatches)
+	}
+	if stats.numUpdates != 146 {
+		t.Fatalf("expected %v updates, instead have %v", 146,
+			stats.numUpdates)
+	}
+	if stats.maxBatch != 64 {
+		t.Fatalf("expected max batch of %v, instead have %v", 64,
+			stats.maxBatch)
+	}
+
+	expectedHistogram := []uint64{1, 1, 1, 1, 1, 1, 1}
+	for i, count := range expectedHistogram {
+		if stats.histogram[i] != count {
+			t.Fatalf("bucket %v: expected count %v, instead have %v",
+				i, count, stats.histogram[i])
+		}
+	}
+
+	if avg := stats.avgBatchSize(); avg != 146.0/7.0 {
+		t.Fatalf("incorrect average batch size: %v", avg)
+	}
+
+	// The metrics exposed over RPC should match those recorded.
+	rpcStats := stats.rpcStats()
+	if rpcStats.NumBatches != 7 || rpcStats.NumUpdates != 146 ||
+		rpcStats.MaxBatchSize != 64 {
+		t.Fatalf("rpc stats don't match recorded stats: %v", rpcStats)
+	}
+	if len(rpcStats.Histogram) != len(expectedHistogram) {
+		t.Fatalf("expected %v histogram buckets, instead have %v",
+			len(expectedHistogram), len(rpcStats.Histogram))
+	}
+}
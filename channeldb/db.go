@@ -43,6 +43,18 @@ var (
 			number:    1,
 			migration: deliveryScriptBugMigration,
 		},
+		{
+			// The DB version which indexes open invoices by
+			// their expiry date.
+			number:    2,
+			migration: invoiceExpiryIndexMigration,
+		},
+		{
+			// The DB version which indexes invoices and payments
+			// by their creation date.
+			number:    3,
+			migration: creationDateIndexMigration,
+		},
 	}
 
 	// Big endian is the preferred byte order, due to cursor scans over
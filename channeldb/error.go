@@ -14,8 +14,31 @@ var (
 	ErrNoInvoicesCreated = fmt.Errorf("there are no existing invoices")
 	ErrDuplicateInvoice  = fmt.Errorf("invoice with payment hash already exists")
 
+	ErrInvoiceAlreadySettled  = fmt.Errorf("invoice already settled")
+	ErrInvoiceAlreadyCanceled = fmt.Errorf("invoice already canceled")
+	ErrInvoiceExpired         = fmt.Errorf("invoice expired")
+
+	ErrInvoiceNotHold          = fmt.Errorf("invoice isn't a hold invoice")
+	ErrInvoiceNotAccepted      = fmt.Errorf("hold invoice hasn't been accepted")
+	ErrInvoicePreimageRequired = fmt.Errorf("preimage required to settle " +
+		"hold invoice")
+
 	ErrNoPaymentsCreated = fmt.Errorf("there are no existing payments")
 
+	ErrPaymentInFlight      = fmt.Errorf("payment is already in flight")
+	ErrAlreadyPaid          = fmt.Errorf("payment has already succeeded")
+	ErrPaymentNotInitiated  = fmt.Errorf("payment hasn't been initiated")
+	ErrPaymentAlreadyFailed = fmt.Errorf("payment has already failed")
+
+	ErrPaymentAttemptNotFound  = fmt.Errorf("payment attempt not found")
+	ErrPaymentAttemptResolved  = fmt.Errorf("payment attempt already resolved")
+	ErrPaymentAttemptsInFlight = fmt.Errorf("payment still has attempts " +
+		"in flight")
+
+	ErrWebhookDeliveryNotFound = fmt.Errorf("webhook delivery not found")
+
+	ErrWebhookIndexesNotFound = fmt.Errorf("webhook indexes not found")
+
 	ErrNodeNotFound = fmt.Errorf("link node with target identity not found")
 	ErrMetaNotFound = fmt.Errorf("unable to locate meta information")
 
@@ -25,7 +48,10 @@ var (
 	ErrGraphNodeNotFound  = fmt.Errorf("unable to find node")
 	ErrGraphNeverPruned   = fmt.Errorf("graph never pruned")
 
-	ErrEdgeNotFound = fmt.Errorf("edge for chanID not found")
+	ErrEdgeNotFound      = fmt.Errorf("edge for chanID not found")
+	ErrEdgeProofNotFound = fmt.Errorf("proof for chanID not found")
+
+	ErrZombieEdgeNotFound = fmt.Errorf("zombie edge for chanID not found")
 
 	ErrNodeAliasNotFound = fmt.Errorf("alias for node not found")
 
@@ -76,6 +76,43 @@ var (
 	// maps: outPoint -> chanID
 	channelPointBucket = []byte("chan-index")
 
+	// privateEdgeBucket is an index of all the channels which have been
+	// marked as private. Private channels are used locally during path
+	// finding, but are never advertised to the rest of the network. This
+	// bucket resides within the edgeBucket above.
+	//
+	// maps: chanID -> nil
+	privateEdgeBucket = []byte("private-edges")
+
+	// edgeProofBucket stores the authentication proof for each channel
+	// which has been announced to the network. A channel's proof consists
+	// of the four signatures, along with the two funding keys, which are
+	// needed to reconstruct the original channel announcement. This
+	// bucket resides within the edgeBucket above.
+	//
+	// maps: chanID -> proof
+	edgeProofBucket = []byte("edge-proofs")
+
+	// edgeAddTimeBucket records the time at which each channel was added
+	// to the graph. For a channel which has yet to receive an update in
+	// one direction, the age of its announcement is used in its place
+	// when determining if the channel is a zombie. This bucket resides
+	// within the edgeBucket above.
+	//
+	// maps: chanID -> unixTime
+	edgeAddTimeBucket = []byte("edge-add-times")
+
+	// zombieBucket is an index of all the channels which have been pruned
+	// from the graph as zombies: channels for which neither node has sent
+	// an update within the staleness horizon. For each zombie, the two
+	// nodes of the channel are stored, allowing a fresh update to be
+	// authenticated, along with the funding outpoint and authentication
+	// proof of the channel so it can be fully restored once resurrected.
+	// This bucket resides within the edgeBucket above.
+	//
+	// maps: chanID -> pub1 || pub2 || outPoint || proof
+	zombieBucket = []byte("zombie-index")
+
 	// graphMetaBucket is a top-level bucket which stores various meta-deta
 	// related to the on-disk channel graph. Data strored in this bucket
 	// includes the block to which the graph has been synced to, the total
@@ -434,6 +471,12 @@ func (c *ChannelGraph) AddChannelEdge(from, to *btcec.PublicKey,
 			return err
 		}
 
+		// We'll also note when the channel was first added, so its
+		// age can be judged before any updates for it are received.
+		if err := putEdgeAddTime(edges, chanKey[:], time.Now()); err != nil {
+			return err
+		}
+
 		// Finally we add it to the channel index which maps channel
 		// points (outpoints) to the shorter channel ID's.
 		var b bytes.Buffer
@@ -444,6 +487,228 @@ func (c *ChannelGraph) AddChannelEdge(from, to *btcec.PublicKey,
 	})
 }
 
+// putEdgeAddTime records the passed time as the time at which the channel
+// identified by chanID was added to the graph.
+func putEdgeAddTime(edges *bolt.Bucket, chanID []byte, addTime time.Time) error {
+	addTimes, err := edges.CreateBucketIfNotExists(edgeAddTimeBucket)
+	if err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(addTime.Unix()))
+	return addTimes.Put(chanID, scratch[:])
+}
+
+// fetchEdgeAddTime returns the time at which the channel identified by chanID
+// was added to the graph. If the time wasn't recorded, then the zero time is
+// returned.
+func fetchEdgeAddTime(edges *bolt.Bucket, chanID []byte) time.Time {
+	addTimes := edges.Bucket(edgeAddTimeBucket)
+	if addTimes == nil {
+		return time.Time{}
+	}
+
+	addTime := addTimes.Get(chanID)
+	if addTime == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(byteOrder.Uint64(addTime)), 0)
+}
+
+// MarkChannelPrivate marks the channel identified by the passed channel ID as
+// private, indicating that it should never be announced to the network. A
+// channel may be marked as private before it has been added to the graph, and
+// the mark is removed along with the channel once it's deleted.
+func (c *ChannelGraph) MarkChannelPrivate(chanID uint64) error {
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		privateIndex, err := edges.CreateBucketIfNotExists(privateEdgeBucket)
+		if err != nil {
+			return err
+		}
+
+		return privateIndex.Put(chanKey[:], nil)
+	})
+}
+
+// IsChannelPrivate returns true if the channel identified by the passed
+// channel ID has been marked as private, and false otherwise.
+func (c *ChannelGraph) IsChannelPrivate(chanID uint64) (bool, error) {
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	var isPrivate bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		privateIndex := edges.Bucket(privateEdgeBucket)
+		if privateIndex == nil {
+			return nil
+		}
+
+		// As the index stores no values, we'll use a cursor to check
+		// for the existence of the key itself.
+		k, _ := privateIndex.Cursor().Seek(chanKey[:])
+		isPrivate = bytes.Equal(k, chanKey[:])
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isPrivate, nil
+}
+
+// ChannelAuthProof is the authentication proof (the signature portion) for a
+// channel. Using the four signatures contained in the struct, and some
+// auxiliary knowledge (the funding script, node identities, and outpoint)
+// nodes on the network are able to validate the authenticity and existence
+// of a channel.
+type ChannelAuthProof struct {
+	// NodeSig1 is the signature using the identity key of the node that
+	// is first in a lexicographical ordering of the serialized public
+	// keys of the two nodes that created the channel.
+	NodeSig1 *btcec.Signature
+
+	// NodeSig2 is the signature using the identity key of the node that
+	// is second in a lexicographical ordering of the serialized public
+	// keys of the two nodes that created the channel.
+	NodeSig2 *btcec.Signature
+
+	// BitcoinSig1 is the signature using the public key of the first
+	// node that was used in the channel's multi-sig output.
+	BitcoinSig1 *btcec.Signature
+
+	// BitcoinSig2 is the signature using the public key of the second
+	// node that was used in the channel's multi-sig output.
+	BitcoinSig2 *btcec.Signature
+
+	// BitcoinKey1 is the public key of the first node within the
+	// channel's multi-sig output.
+	BitcoinKey1 *btcec.PublicKey
+
+	// BitcoinKey2 is the public key of the second node within the
+	// channel's multi-sig output.
+	BitcoinKey2 *btcec.PublicKey
+}
+
+// AddChannelProof stores the authentication proof for the channel identified
+// by the passed channel ID. The proof allows the channel's announcement to be
+// reconstructed and relayed to other nodes. An existing proof for the channel
+// will be overwritten.
+func (c *ChannelGraph) AddChannelProof(chanID uint64, proof *ChannelAuthProof) error {
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	var b bytes.Buffer
+	sigs := []*btcec.Signature{
+		proof.NodeSig1, proof.NodeSig2,
+		proof.BitcoinSig1, proof.BitcoinSig2,
+	}
+	for _, sig := range sigs {
+		if err := wire.WriteVarBytes(&b, 0, sig.Serialize()); err != nil {
+			return err
+		}
+	}
+	if _, err := b.Write(proof.BitcoinKey1.SerializeCompressed()); err != nil {
+		return err
+	}
+	if _, err := b.Write(proof.BitcoinKey2.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		proofIndex, err := edges.CreateBucketIfNotExists(edgeProofBucket)
+		if err != nil {
+			return err
+		}
+
+		return proofIndex.Put(chanKey[:], b.Bytes())
+	})
+}
+
+// FetchChannelProof retrieves the authentication proof for the channel
+// identified by the passed channel ID. If no proof has been stored for the
+// channel, then ErrEdgeProofNotFound is returned.
+func (c *ChannelGraph) FetchChannelProof(chanID uint64) (*ChannelAuthProof, error) {
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	var proofBytes []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return ErrEdgeProofNotFound
+		}
+		proofIndex := edges.Bucket(edgeProofBucket)
+		if proofIndex == nil {
+			return ErrEdgeProofNotFound
+		}
+
+		proof := proofIndex.Get(chanKey[:])
+		if proof == nil {
+			return ErrEdgeProofNotFound
+		}
+
+		proofBytes = make([]byte, len(proof))
+		copy(proofBytes, proof)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(proofBytes)
+
+	var sigs [4]*btcec.Signature
+	for i := range sigs {
+		sigBytes, err := wire.ReadVarBytes(r, 0, 80, "sig")
+		if err != nil {
+			return nil, err
+		}
+		sigs[i], err = btcec.ParseSignature(sigBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var keys [2]*btcec.PublicKey
+	for i := range keys {
+		var keyBytes [33]byte
+		if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+			return nil, err
+		}
+		keys[i], err = btcec.ParsePubKey(keyBytes[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ChannelAuthProof{
+		NodeSig1:    sigs[0],
+		NodeSig2:    sigs[1],
+		BitcoinSig1: sigs[2],
+		BitcoinSig2: sigs[3],
+		BitcoinKey1: keys[0],
+		BitcoinKey2: keys[1],
+	}, nil
+}
+
 // HasChannelEdge returns true if the database knows of a channel edge with the
 // passed channel ID, and false otherwise. If the an edge with that ID is found
 // within the graph, then two time stamps representing the last time the edge
@@ -507,6 +772,102 @@ func (c *ChannelGraph) HasChannelEdge(chanID uint64) (time.Time, time.Time, bool
 	return node1UpdateTime, node2UpdateTime, exists, nil
 }
 
+// ChannelUpdateInfo couples the ID of an announced channel with the time
+// stamps of the latest updates for both directed edges of the channel. A zero
+// time stamp indicates that the corresponding edge hasn't been advertised.
+type ChannelUpdateInfo struct {
+	// ChannelID is the unique identifier of the channel.
+	ChannelID uint64
+
+	// Node1UpdateTimestamp is the time of the latest update for the
+	// directed edge of the first node of the channel.
+	Node1UpdateTimestamp time.Time
+
+	// Node2UpdateTimestamp is the time of the latest update for the
+	// directed edge of the second node of the channel.
+	Node2UpdateTimestamp time.Time
+}
+
+// ChannelsInRange returns the IDs of all announced channels with a funding
+// transaction confirmed within the passed (inclusive) range of block heights,
+// along with the time stamps of their latest updates. Only channels for which
+// an authentication proof is known are returned, as only those are able to be
+// relayed to other nodes. The channels are returned in ascending order of
+// their IDs.
+func (c *ChannelGraph) ChannelsInRange(startHeight,
+	endHeight uint32) ([]ChannelUpdateInfo, error) {
+
+	// As the block height makes up the most significant bytes of a
+	// channel ID, all channels within the range can be found by seeking
+	// to the lowest possible ID of the start height, and iterating up to
+	// the highest possible ID of the end height.
+	var startKey, endKey [8]byte
+	byteOrder.PutUint64(startKey[:], uint64(startHeight)<<40)
+	byteOrder.PutUint64(endKey[:], uint64(endHeight)<<40|(1<<40-1))
+
+	var chanInfos []ChannelUpdateInfo
+	err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		proofIndex := edges.Bucket(edgeProofBucket)
+		if proofIndex == nil {
+			return nil
+		}
+		edgeIndex := edges.Bucket(edgeIndexBucket)
+		if edgeIndex == nil {
+			return nil
+		}
+		nodes := tx.Bucket(nodeBucket)
+		if nodes == nil {
+			return nil
+		}
+		privateIndex := edges.Bucket(privateEdgeBucket)
+
+		cursor := proofIndex.Cursor()
+		for k, _ := cursor.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, _ = cursor.Next() {
+
+			// Private channels are never relayed, so we'll skip
+			// any which may have had a proof stored.
+			if privateIndex != nil {
+				pk, _ := privateIndex.Cursor().Seek(k)
+				if bytes.Equal(pk, k) {
+					continue
+				}
+			}
+
+			e1, e2, err := fetchEdges(edgeIndex, edges, nodes, k,
+				c.db)
+			if err == ErrEdgeNotFound {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			chanInfo := ChannelUpdateInfo{
+				ChannelID: byteOrder.Uint64(k),
+			}
+			if e1 != nil {
+				chanInfo.Node1UpdateTimestamp = e1.LastUpdate
+			}
+			if e2 != nil {
+				chanInfo.Node2UpdateTimestamp = e2.LastUpdate
+			}
+
+			chanInfos = append(chanInfos, chanInfo)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanInfos, nil
+}
+
 const (
 	// pruneTipBytes is the total size of the value which stores the
 	// current prune tip of the graph. The prune tip indicates if the
@@ -565,6 +926,13 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 			}
 		}
 
+		// Any zombie channels whose funding outpoint has been spent
+		// can never be resurrected, so we'll purge them from the
+		// zombie index as well.
+		if err := delSpentZombies(edges, spentOutputs); err != nil {
+			return err
+		}
+
 		metaBucket, err := tx.CreateBucketIfNotExists(graphMetaBucket)
 		if err != nil {
 			return err
@@ -586,6 +954,283 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 	return numChans, nil
 }
 
+// delSpentZombies removes from the zombie index all channels whose funding
+// outpoint is found within the set of spent outputs.
+func delSpentZombies(edges *bolt.Bucket, spentOutputs []*wire.OutPoint) error {
+	zombieIndex := edges.Bucket(zombieBucket)
+	if zombieIndex == nil {
+		return nil
+	}
+
+	spent := make(map[wire.OutPoint]struct{}, len(spentOutputs))
+	for _, op := range spentOutputs {
+		spent[*op] = struct{}{}
+	}
+
+	// The bucket can't be modified while we iterate over it, so we'll
+	// first gather the IDs of the spent zombies.
+	var spentZombies [][]byte
+	err := zombieIndex.ForEach(func(chanID, zombie []byte) error {
+		var chanPoint wire.OutPoint
+		r := bytes.NewReader(zombie[66:])
+		if err := readOutpoint(r, &chanPoint); err != nil {
+			return err
+		}
+		if _, ok := spent[chanPoint]; ok {
+			spentZombies = append(spentZombies, chanID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, chanID := range spentZombies {
+		if err := zombieIndex.Delete(chanID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneZombieChannels removes from the graph all channels for which neither
+// directed edge has been updated since the passed stale time, adding them to
+// the zombie index. A direction of a channel which has never been updated is
+// judged by the age of the channel's announcement instead. Channels of the source node are never pruned, as their
+// liveness is known to us directly. The funding outpoints of the pruned
+// channels are returned.
+func (c *ChannelGraph) PruneZombieChannels(staleTime time.Time) ([]*wire.OutPoint, error) {
+	var zombies []*wire.OutPoint
+
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		edgeIndex, err := edges.CreateBucketIfNotExists(edgeIndexBucket)
+		if err != nil {
+			return err
+		}
+		chanIndex, err := edges.CreateBucketIfNotExists(channelPointBucket)
+		if err != nil {
+			return err
+		}
+		zombieIndex, err := edges.CreateBucketIfNotExists(zombieBucket)
+		if err != nil {
+			return err
+		}
+
+		var sourcePub []byte
+		if nodes := tx.Bucket(nodeBucket); nodes != nil {
+			sourcePub = nodes.Get(sourceKey)
+		}
+
+		// We'll first gather the set of zombie channels, as the
+		// buckets can't be modified while we iterate over them.
+		err = chanIndex.ForEach(func(chanPoint, chanID []byte) error {
+			nodeKeys := edgeIndex.Get(chanID)
+			if nodeKeys == nil {
+				return nil
+			}
+
+			if sourcePub != nil &&
+				(bytes.Equal(nodeKeys[:33], sourcePub) ||
+					bytes.Equal(nodeKeys[33:], sourcePub)) {
+
+				return nil
+			}
+
+			for _, nodePub := range [][]byte{nodeKeys[:33], nodeKeys[33:]} {
+				lastUpdate, err := fetchEdgeUpdateTime(edges,
+					chanID, nodePub)
+				if err != nil {
+					return err
+				}
+				if lastUpdate.IsZero() {
+					lastUpdate = fetchEdgeAddTime(
+						edges, chanID,
+					)
+				}
+				if !lastUpdate.Before(staleTime) {
+					return nil
+				}
+			}
+
+			op := &wire.OutPoint{}
+			err := readOutpoint(bytes.NewReader(chanPoint), op)
+			if err != nil {
+				return err
+			}
+			zombies = append(zombies, op)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// With the zombies found, we'll record each of them within
+		// the zombie index before deleting them from the graph.
+		for _, chanPoint := range zombies {
+			var b bytes.Buffer
+			if err := writeOutpoint(&b, chanPoint); err != nil {
+				return err
+			}
+			chanID := chanIndex.Get(b.Bytes())
+
+			var zombie bytes.Buffer
+			if _, err := zombie.Write(edgeIndex.Get(chanID)); err != nil {
+				return err
+			}
+			if _, err := zombie.Write(b.Bytes()); err != nil {
+				return err
+			}
+			if proofIndex := edges.Bucket(edgeProofBucket); proofIndex != nil {
+				proof := proofIndex.Get(chanID)
+				if _, err := zombie.Write(proof); err != nil {
+					return err
+				}
+			}
+
+			var chanKey [8]byte
+			copy(chanKey[:], chanID)
+			if err := zombieIndex.Put(chanKey[:], zombie.Bytes()); err != nil {
+				return err
+			}
+
+			err := delChannelByEdge(edges, edgeIndex, chanIndex,
+				chanPoint)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return zombies, nil
+}
+
+// IsZombieChannel returns true if the channel identified by the passed
+// channel ID has been pruned from the graph as a zombie. If so, the public
+// keys of the two nodes of the channel are also returned, allowing a fresh
+// update for the channel to be authenticated.
+func (c *ChannelGraph) IsZombieChannel(chanID uint64) (bool, [33]byte, [33]byte, error) {
+	var (
+		isZombie bool
+		node1    [33]byte
+		node2    [33]byte
+	)
+
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		zombieIndex := edges.Bucket(zombieBucket)
+		if zombieIndex == nil {
+			return nil
+		}
+
+		zombie := zombieIndex.Get(chanKey[:])
+		if zombie == nil {
+			return nil
+		}
+
+		isZombie = true
+		copy(node1[:], zombie[:33])
+		copy(node2[:], zombie[33:66])
+
+		return nil
+	})
+	if err != nil {
+		return false, node1, node2, err
+	}
+
+	return isZombie, node1, node2, nil
+}
+
+// MarkChannelLive resurrects the zombie channel identified by the passed
+// channel ID, removing it from the zombie index and restoring the channel
+// along with its authentication proof within the graph. The routing policies
+// of the channel aren't restored, so the channel will only be used for path
+// finding once fresh updates for it have been applied. If the channel isn't
+// a zombie, then ErrZombieEdgeNotFound is returned.
+func (c *ChannelGraph) MarkChannelLive(chanID uint64) error {
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		zombieIndex := edges.Bucket(zombieBucket)
+		if zombieIndex == nil {
+			return ErrZombieEdgeNotFound
+		}
+		zombie := zombieIndex.Get(chanKey[:])
+		if zombie == nil {
+			return ErrZombieEdgeNotFound
+		}
+
+		edgeIndex, err := edges.CreateBucketIfNotExists(edgeIndexBucket)
+		if err != nil {
+			return err
+		}
+		chanIndex, err := edges.CreateBucketIfNotExists(channelPointBucket)
+		if err != nil {
+			return err
+		}
+
+		// The zombie entry begins with the two node keys, followed
+		// by the funding outpoint of the channel. Any remaining bytes
+		// hold the authentication proof of the channel.
+		r := bytes.NewReader(zombie[66:])
+		var chanPoint wire.OutPoint
+		if err := readOutpoint(r, &chanPoint); err != nil {
+			return err
+		}
+		proof := zombie[len(zombie)-r.Len():]
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, &chanPoint); err != nil {
+			return err
+		}
+
+		if err := edgeIndex.Put(chanKey[:], zombie[:66]); err != nil {
+			return err
+		}
+		if err := chanIndex.Put(b.Bytes(), chanKey[:]); err != nil {
+			return err
+		}
+		if err := putEdgeAddTime(edges, chanKey[:], time.Now()); err != nil {
+			return err
+		}
+		if len(proof) != 0 {
+			proofIndex, err := edges.CreateBucketIfNotExists(
+				edgeProofBucket,
+			)
+			if err != nil {
+				return err
+			}
+			if err := proofIndex.Put(chanKey[:], proof); err != nil {
+				return err
+			}
+		}
+
+		return zombieIndex.Delete(chanKey[:])
+	})
+}
+
 // PruneTip returns the block height and hash of the latest block that has been
 // used to prune channels in the graph. Knowing the "prune tip" allows callers
 // to tell if the graph is currently in sync with the current best known UTXO
@@ -687,6 +1332,47 @@ func (c *ChannelGraph) ChannelID(chanPoint *wire.OutPoint) (uint64, error) {
 	return chanID, nil
 }
 
+// ChannelNodes returns the compressed public keys of the two nodes connected
+// by the channel identified by the passed channel ID. The first key returned
+// is always the lexicographically smaller of the two, matching the ordering
+// used to determine the direction of an edge update via its flags. If the
+// channel doesn't exist within the database, then ErrEdgeNotFound is
+// returned.
+func (c *ChannelGraph) ChannelNodes(chanID uint64) ([33]byte, [33]byte, error) {
+	var (
+		node1 [33]byte
+		node2 [33]byte
+	)
+
+	var chanKey [8]byte
+	byteOrder.PutUint64(chanKey[:], chanID)
+
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgeBucket)
+		if edges == nil {
+			return ErrGraphNoEdgesFound
+		}
+		edgeIndex := edges.Bucket(edgeIndexBucket)
+		if edgeIndex == nil {
+			return ErrGraphNoEdgesFound
+		}
+
+		edgeInfo := edgeIndex.Get(chanKey[:])
+		if edgeInfo == nil {
+			return ErrEdgeNotFound
+		}
+
+		copy(node1[:], edgeInfo[:33])
+		copy(node2[:], edgeInfo[33:])
+
+		return nil
+	}); err != nil {
+		return node1, node2, err
+	}
+
+	return node1, node2, nil
+}
+
 func delChannelByEdge(edges *bolt.Bucket, edgeIndex *bolt.Bucket,
 	chanIndex *bolt.Bucket, chanPoint *wire.OutPoint) error {
 	var b bytes.Buffer
@@ -728,6 +1414,28 @@ func delChannelByEdge(edges *bolt.Bucket, edgeIndex *bolt.Bucket,
 		}
 	}
 
+	// If the channel was marked as private, then we'll also remove it
+	// from the private index.
+	if privateIndex := edges.Bucket(privateEdgeBucket); privateIndex != nil {
+		if err := privateIndex.Delete(chanID); err != nil {
+			return err
+		}
+	}
+
+	// Similarly, we'll remove the channel's authentication proof if one
+	// was stored.
+	if proofIndex := edges.Bucket(edgeProofBucket); proofIndex != nil {
+		if err := proofIndex.Delete(chanID); err != nil {
+			return err
+		}
+	}
+
+	if addTimes := edges.Bucket(edgeAddTimeBucket); addTimes != nil {
+		if err := addTimes.Delete(chanID); err != nil {
+			return err
+		}
+	}
+
 	// Finally, with the edge data deleted, we can purge the
 	// information from the two edge indexes.
 	if err := edgeIndex.Delete(chanID); err != nil {
@@ -805,6 +1513,13 @@ type LightningNode struct {
 	// a node's identity or to serve as a short ID for an address book.
 	Alias string
 
+	// AuthSig is a signature under the advertised public key which serves
+	// to authenticate the attributes announced by this node. The
+	// signature is stored so the node's announcement can be relayed to
+	// other peers verbatim. This value may be nil for nodes which haven't
+	// been advertised by an authenticated announcement.
+	AuthSig *btcec.Signature
+
 	db *DB
 
 	// TODO(roasbeef): discovery will need storage to keep it's last IP
@@ -1008,6 +1723,11 @@ type ChannelEdge struct {
 	// in millisatoshi.
 	MinHTLC btcutil.Amount
 
+	// MaxHTLC is the largest value HTLC this node will accept, expressed
+	// in millisatoshi. A value of zero indicates that there's no limit
+	// beyond the capacity of the channel.
+	MaxHTLC btcutil.Amount
+
 	// FeeBaseMSat is the base HTLC fee that will be charged for forwarding
 	// ANY HTLC, expressed in mSAT's.
 	FeeBaseMSat btcutil.Amount
@@ -1024,6 +1744,12 @@ type ChannelEdge struct {
 	// this pointer the channel graph can further be traversed.
 	Node *LightningNode
 
+	// AuthSig is a signature by the node on the origin side of this
+	// directed edge which authenticates the policy above. This value may
+	// be nil for edges which haven't been updated by an authenticated
+	// announcement.
+	AuthSig *btcec.Signature
+
 	db *DB
 }
 
@@ -1181,6 +1907,10 @@ func putLightningNode(nodeBucket *bolt.Bucket, aliasBucket *bolt.Bucket, node *L
 		return err
 	}
 
+	if err := writeAuthSig(&b, node.AuthSig); err != nil {
+		return err
+	}
+
 	return nodeBucket.Put(nodePub, b.Bytes())
 }
 
@@ -1240,6 +1970,11 @@ func deserializeLightningNode(r io.Reader) (*LightningNode, error) {
 		return nil, err
 	}
 
+	node.AuthSig, err = readAuthSig(r)
+	if err != nil {
+		return nil, err
+	}
+
 	return node, nil
 }
 
@@ -1288,6 +2023,13 @@ func putChannelEdge(edges *bolt.Bucket, edge *ChannelEdge, from, to []byte) erro
 		return err
 	}
 
+	if err := writeAuthSig(&b, edge.AuthSig); err != nil {
+		return err
+	}
+	if err := binary.Write(&b, byteOrder, uint64(edge.MaxHTLC)); err != nil {
+		return err
+	}
+
 	return edges.Put(edgeKey[:], b.Bytes()[:])
 }
 
@@ -1331,6 +2073,38 @@ func fetchEdges(edgeIndex *bolt.Bucket, edges *bolt.Bucket, nodes *bolt.Bucket,
 	return edge1, edge2, nil
 }
 
+// fetchEdgeUpdateTime returns the time of the latest update of the directed
+// edge of the passed node for the target channel, without deserializing the
+// edge in full. If the edge has never been updated, then the zero time is
+// returned.
+func fetchEdgeUpdateTime(edges *bolt.Bucket, chanID []byte,
+	nodePub []byte) (time.Time, error) {
+
+	var edgeKey [33 + 8]byte
+	copy(edgeKey[:], nodePub)
+	copy(edgeKey[33:], chanID)
+
+	edgeBytes := edges.Get(edgeKey[:])
+	if edgeBytes == nil {
+		return time.Time{}, nil
+	}
+
+	// The update time is stored directly after the channel ID and the
+	// funding outpoint of the edge.
+	r := bytes.NewReader(edgeBytes[8:])
+	var chanPoint wire.OutPoint
+	if err := readOutpoint(r, &chanPoint); err != nil {
+		return time.Time{}, err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(byteOrder.Uint64(scratch[:])), 0), nil
+}
+
 func fetchChannelEdge(edges *bolt.Bucket, chanID []byte,
 	nodePub []byte, nodes *bolt.Bucket) (*ChannelEdge, error) {
 
@@ -1404,7 +2178,52 @@ func deserializeChannelEdge(r io.Reader, nodes *bolt.Bucket) (*ChannelEdge, erro
 	if err != nil {
 		return nil, err
 	}
-
 	edge.Node = node
+
+	edge.AuthSig, err = readAuthSig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edges written before the maximum HTLC was stored lack the field
+	// entirely, in which case the edge is left without a limit.
+	err = binary.Read(r, byteOrder, &n)
+	switch {
+	case err == io.EOF:
+		return edge, nil
+	case err != nil:
+		return nil, err
+	}
+	edge.MaxHTLC = btcutil.Amount(n)
+
 	return edge, nil
 }
+
+// writeAuthSig serializes an optional authentication signature to the passed
+// io.Writer. A nil signature is written as an empty byte slice.
+func writeAuthSig(w io.Writer, sig *btcec.Signature) error {
+	var sigBytes []byte
+	if sig != nil {
+		sigBytes = sig.Serialize()
+	}
+
+	return wire.WriteVarBytes(w, 0, sigBytes)
+}
+
+// readAuthSig deserializes an optional authentication signature from the
+// passed io.Reader. Records written before signatures were stored lack the
+// field entirely, so in that case, as well as when an empty signature was
+// written, a nil signature is returned.
+func readAuthSig(r io.Reader) (*btcec.Signature, error) {
+	sigBytes, err := wire.ReadVarBytes(r, 0, 80, "sig")
+	switch {
+	case err == io.EOF:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	case len(sigBytes) == 0:
+		return nil, nil
+	}
+
+	return btcec.ParseSignature(sigBytes, btcec.S256())
+}
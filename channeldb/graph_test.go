@@ -220,12 +220,57 @@ func TestEdgeInsertionDeletion(t *testing.T) {
 		t.Fatalf("unable to create channel edge: %v", err)
 	}
 
+	// The two nodes of the channel should be returned in their
+	// lexicographical order.
+	n1, n2, err := graph.ChannelNodes(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel nodes: %v", err)
+	}
+	node1Bytes := node1.PubKey.SerializeCompressed()
+	node2Bytes := node2.PubKey.SerializeCompressed()
+	if bytes.Compare(node1Bytes, node2Bytes) == 1 {
+		node1Bytes, node2Bytes = node2Bytes, node1Bytes
+	}
+	if !bytes.Equal(n1[:], node1Bytes) || !bytes.Equal(n2[:], node2Bytes) {
+		t.Fatalf("channel nodes don't match: expected (%x, %x), "+
+			"got (%x, %x)", node1Bytes, node2Bytes, n1, n2)
+	}
+
+	// The channel shouldn't be private until it has been marked as such.
+	isPrivate, err := graph.IsChannelPrivate(chanID)
+	if err != nil {
+		t.Fatalf("unable to query private index: %v", err)
+	}
+	if isPrivate {
+		t.Fatalf("channel shouldn't be private")
+	}
+	if err := graph.MarkChannelPrivate(chanID); err != nil {
+		t.Fatalf("unable to mark channel private: %v", err)
+	}
+	isPrivate, err = graph.IsChannelPrivate(chanID)
+	if err != nil {
+		t.Fatalf("unable to query private index: %v", err)
+	}
+	if !isPrivate {
+		t.Fatalf("channel should be private")
+	}
+
 	// Next, attempt to delete the edge from the database, again this
 	// should proceed without any issues.
 	if err := graph.DeleteChannelEdge(&outpoint); err != nil {
 		t.Fatalf("unable to delete edge: %v", err)
 	}
 
+	// Once deleted, the channel should also be removed from the private
+	// index.
+	isPrivate, err = graph.IsChannelPrivate(chanID)
+	if err != nil {
+		t.Fatalf("unable to query private index: %v", err)
+	}
+	if isPrivate {
+		t.Fatalf("deleted channel shouldn't be private")
+	}
+
 	// Finally, attempt to delete a (now) non-existent edge within the
 	// database, this should result in an error.
 	err = graph.DeleteChannelEdge(&outpoint)
@@ -298,6 +343,7 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		Flags:                     0,
 		Expiry:                    99,
 		MinHTLC:                   2342135,
+		MaxHTLC:                   13928598,
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 3452352,
 		Capacity:                  9903453,
@@ -311,6 +357,7 @@ func TestEdgeInfoUpdates(t *testing.T) {
 		Flags:                     1,
 		Expiry:                    99,
 		MinHTLC:                   2342135,
+		MaxHTLC:                   13928598,
 		FeeBaseMSat:               4352345,
 		FeeProportionalMillionths: 90392423,
 		Capacity:                  324523,
@@ -387,6 +434,7 @@ func randEdge(chanID uint64, op wire.OutPoint, db *DB) *ChannelEdge {
 		LastUpdate:                time.Unix(update, 0),
 		Expiry:                    uint16(prand.Int63()),
 		MinHTLC:                   btcutil.Amount(prand.Int63()),
+		MaxHTLC:                   btcutil.Amount(prand.Int63()),
 		FeeBaseMSat:               btcutil.Amount(prand.Int63()),
 		FeeProportionalMillionths: btcutil.Amount(prand.Int63()),
 		Capacity:                  btcutil.Amount(prand.Int63()),
@@ -695,3 +743,424 @@ func TestGraphPruning(t *testing.T) {
 	assertPruneTip(t, graph, &blockHash, blockHeight)
 	asserNumChans(t, graph, 0)
 }
+
+func TestChannelAuthProofStorage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	// We'll start by creating a signature which we'll use to authenticate
+	// both vertexes, the edge between them, and within the edge's proof.
+	sig, err := privKey.Sign(key[:])
+	if err != nil {
+		t.Fatalf("unable to create signature: %v", err)
+	}
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node1.AuthSig = sig
+	if err := graph.AddLightningNode(node1); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.AddLightningNode(node2); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+
+	// The signature of the first node should be retrieved intact, while
+	// the second node shouldn't have a signature at all.
+	dbNode, err := graph.FetchLightningNode(node1.PubKey)
+	if err != nil {
+		t.Fatalf("unable to locate node: %v", err)
+	}
+	if !reflect.DeepEqual(dbNode.AuthSig, sig) {
+		t.Fatalf("node signature doesn't match")
+	}
+	dbNode, err = graph.FetchLightningNode(node2.PubKey)
+	if err != nil {
+		t.Fatalf("unable to locate node: %v", err)
+	}
+	if dbNode.AuthSig != nil {
+		t.Fatalf("node shouldn't have a signature")
+	}
+
+	chanID := uint64(prand.Int63())
+	outpoint := wire.OutPoint{
+		Hash:  rev,
+		Index: 3,
+	}
+	if err := graph.AddChannelEdge(node1.PubKey, node2.PubKey, &outpoint,
+		chanID); err != nil {
+		t.Fatalf("unable to create channel edge: %v", err)
+	}
+
+	// Until a proof has been added, the proof of the channel shouldn't be
+	// found.
+	if _, err := graph.FetchChannelProof(chanID); err != ErrEdgeProofNotFound {
+		t.Fatalf("expected ErrEdgeProofNotFound, instead got: %v", err)
+	}
+
+	proof := &ChannelAuthProof{
+		NodeSig1:    sig,
+		NodeSig2:    sig,
+		BitcoinSig1: sig,
+		BitcoinSig2: sig,
+		BitcoinKey1: node1.PubKey,
+		BitcoinKey2: node2.PubKey,
+	}
+	if err := graph.AddChannelProof(chanID, proof); err != nil {
+		t.Fatalf("unable to add proof: %v", err)
+	}
+	dbProof, err := graph.FetchChannelProof(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch proof: %v", err)
+	}
+	if !reflect.DeepEqual(dbProof, proof) {
+		t.Fatalf("proof doesn't match: expected %#v, \n got %#v",
+			proof, dbProof)
+	}
+
+	// The signature authenticating an edge update should also be stored
+	// along with the edge.
+	edge := randEdge(chanID, outpoint, db)
+	edge.Flags = 0
+	edge.AuthSig = sig
+	if err := graph.UpdateEdgeInfo(edge); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+	dbEdge1, _, err := graph.FetchChannelEdgesByID(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel by ID: %v", err)
+	}
+	if !reflect.DeepEqual(dbEdge1.AuthSig, sig) {
+		t.Fatalf("edge signature doesn't match")
+	}
+
+	// Once the channel is closed, its proof should be removed along with
+	// the rest of the channel's state.
+	if err := graph.DeleteChannelEdge(&outpoint); err != nil {
+		t.Fatalf("unable to delete edge: %v", err)
+	}
+	if _, err := graph.FetchChannelProof(chanID); err != ErrEdgeProofNotFound {
+		t.Fatalf("expected ErrEdgeProofNotFound, instead got: %v", err)
+	}
+}
+
+// TestChannelsInRange tests that only the announced, public channels within
+// the queried block range are returned along with the time stamps of their
+// latest updates.
+func TestChannelsInRange(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	sig, err := privKey.Sign(key[:])
+	if err != nil {
+		t.Fatalf("unable to create signature: %v", err)
+	}
+
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	for _, node := range []*LightningNode{node1, node2} {
+		if err := graph.AddLightningNode(node); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	}
+
+	proof := &ChannelAuthProof{
+		NodeSig1:    sig,
+		NodeSig2:    sig,
+		BitcoinSig1: sig,
+		BitcoinSig2: sig,
+		BitcoinKey1: node1.PubKey,
+		BitcoinKey2: node2.PubKey,
+	}
+
+	// addChannel adds a new channel confirmed at the passed height,
+	// optionally storing its proof or marking it as private.
+	addChannel := func(height uint32, withProof, private bool) uint64 {
+		chanID := uint64(height)<<40 | uint64(prand.Int31n(1<<16))<<16
+		outpoint := wire.OutPoint{
+			Hash:  rev,
+			Index: uint32(chanID >> 16),
+		}
+		if err := graph.AddChannelEdge(node1.PubKey, node2.PubKey,
+			&outpoint, chanID); err != nil {
+			t.Fatalf("unable to create channel edge: %v", err)
+		}
+		if withProof {
+			if err := graph.AddChannelProof(chanID, proof); err != nil {
+				t.Fatalf("unable to add proof: %v", err)
+			}
+		}
+		if private {
+			if err := graph.MarkChannelPrivate(chanID); err != nil {
+				t.Fatalf("unable to mark channel private: %v",
+					err)
+			}
+		}
+
+		return chanID
+	}
+
+	chan1 := addChannel(100, true, false)
+	chan2 := addChannel(150, true, false)
+	addChannel(120, false, false)
+	addChannel(130, true, true)
+	addChannel(200, true, false)
+
+	// We'll add an update for one direction of the second channel, which
+	// should be reflected in the returned time stamps.
+	edge := randEdge(chan2, wire.OutPoint{Hash: rev,
+		Index: uint32(chan2 >> 16)}, db)
+	edge.Flags = 1
+	if err := graph.UpdateEdgeInfo(edge); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+
+	chanInfos, err := graph.ChannelsInRange(100, 199)
+	if err != nil {
+		t.Fatalf("unable to query channel range: %v", err)
+	}
+	if len(chanInfos) != 2 {
+		t.Fatalf("expected 2 channels, got %v", len(chanInfos))
+	}
+	if chanInfos[0].ChannelID != chan1 || chanInfos[1].ChannelID != chan2 {
+		t.Fatalf("unexpected channels returned: %v", chanInfos)
+	}
+	if !chanInfos[1].Node1UpdateTimestamp.IsZero() {
+		t.Fatalf("first edge shouldn't have an update")
+	}
+	if !chanInfos[1].Node2UpdateTimestamp.Equal(edge.LastUpdate) {
+		t.Fatalf("expected timestamp %v, got %v", edge.LastUpdate,
+			chanInfos[1].Node2UpdateTimestamp)
+	}
+
+	// A range beyond all known channels should return nothing.
+	chanInfos, err = graph.ChannelsInRange(201, 300)
+	if err != nil {
+		t.Fatalf("unable to query channel range: %v", err)
+	}
+	if len(chanInfos) != 0 {
+		t.Fatalf("expected no channels, got %v", len(chanInfos))
+	}
+}
+
+// TestZombieChannelPruning tests that channels which haven't been updated
+// within the staleness horizon are moved from the graph to the zombie index,
+// that a zombie channel can be fully restored once resurrected, and that
+// zombies whose funding outpoint is spent are removed from the zombie index.
+func TestZombieChannelPruning(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	sig, err := privKey.Sign(key[:])
+	if err != nil {
+		t.Fatalf("unable to create signature: %v", err)
+	}
+
+	sourceNode, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	if err := graph.SetSourceNode(sourceNode); err != nil {
+		t.Fatalf("unable to set source node: %v", err)
+	}
+	node1, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	node2, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	for _, node := range []*LightningNode{node1, node2} {
+		if err := graph.AddLightningNode(node); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	}
+
+	proof := &ChannelAuthProof{
+		NodeSig1:    sig,
+		NodeSig2:    sig,
+		BitcoinSig1: sig,
+		BitcoinSig2: sig,
+		BitcoinKey1: node1.PubKey,
+		BitcoinKey2: node2.PubKey,
+	}
+
+	// addChannel adds a new announced channel between the two passed
+	// nodes, applying an update for each of the passed update times. A
+	// zero update time leaves the corresponding direction un-updated.
+	addChannel := func(chanID uint64, from, to *LightningNode,
+		updateTimes ...time.Time) wire.OutPoint {
+
+		outpoint := wire.OutPoint{
+			Hash:  rev,
+			Index: uint32(chanID),
+		}
+		if err := graph.AddChannelEdge(from.PubKey, to.PubKey,
+			&outpoint, chanID); err != nil {
+			t.Fatalf("unable to create channel edge: %v", err)
+		}
+		if err := graph.AddChannelProof(chanID, proof); err != nil {
+			t.Fatalf("unable to add proof: %v", err)
+		}
+
+		for i, updateTime := range updateTimes {
+			if updateTime.IsZero() {
+				continue
+			}
+
+			edge := randEdge(chanID, outpoint, db)
+			edge.Flags = uint16(i)
+			edge.LastUpdate = updateTime
+			if err := graph.UpdateEdgeInfo(edge); err != nil {
+				t.Fatalf("unable to update edge: %v", err)
+			}
+		}
+
+		return outpoint
+	}
+
+	now := time.Now()
+	staleTime := time.Unix(1000, 0)
+
+	// Our own channel is never pruned, while a channel with a single
+	// fresh direction is still considered live. A freshly announced
+	// channel without any updates is also live, leaving only the channel
+	// for which neither direction is fresh as a zombie.
+	addChannel(1, sourceNode, node1)
+	addChannel(2, node1, node2, staleTime, now)
+	staleChan := addChannel(3, node1, node2, staleTime, staleTime)
+	emptyChan := addChannel(4, node1, node2)
+
+	zombies, err := graph.PruneZombieChannels(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unable to prune zombies: %v", err)
+	}
+	if len(zombies) != 1 {
+		t.Fatalf("expected 1 zombie, got %v", len(zombies))
+	}
+	if *zombies[0] != staleChan {
+		t.Fatalf("unexpected zombie pruned: %v", zombies[0])
+	}
+	asserNumChans(t, graph, 3)
+
+	// The zombie should no longer be found within the graph, but should
+	// be found within the zombie index along with its nodes.
+	_, _, exists, err := graph.HasChannelEdge(3)
+	if err != nil {
+		t.Fatalf("unable to query for edge: %v", err)
+	}
+	if exists {
+		t.Fatalf("zombie channel still found within graph")
+	}
+	isZombie, zombieNode1, zombieNode2, err := graph.IsZombieChannel(3)
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if !isZombie {
+		t.Fatalf("channel not found within zombie index")
+	}
+	nodeKeys := [][33]byte{zombieNode1, zombieNode2}
+	for _, node := range []*LightningNode{node1, node2} {
+		var pub [33]byte
+		copy(pub[:], node.PubKey.SerializeCompressed())
+		if pub != nodeKeys[0] && pub != nodeKeys[1] {
+			t.Fatalf("zombie nodes don't match: %x", pub)
+		}
+	}
+
+	// Once resurrected, the channel should be restored within the graph
+	// along with its proof, and removed from the zombie index.
+	if err := graph.MarkChannelLive(3); err != nil {
+		t.Fatalf("unable to resurrect zombie: %v", err)
+	}
+	isZombie, _, _, err = graph.IsZombieChannel(3)
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if isZombie {
+		t.Fatalf("resurrected channel still found within zombie index")
+	}
+	chanID, err := graph.ChannelID(&staleChan)
+	if err != nil {
+		t.Fatalf("unable to find resurrected channel: %v", err)
+	}
+	if chanID != 3 {
+		t.Fatalf("expected chan_id 3, got %v", chanID)
+	}
+	if _, err := graph.FetchChannelProof(3); err != nil {
+		t.Fatalf("unable to fetch resurrected proof: %v", err)
+	}
+
+	// Resurrecting a channel which isn't a zombie should fail.
+	if err := graph.MarkChannelLive(2); err != ErrZombieEdgeNotFound {
+		t.Fatalf("expected ErrZombieEdgeNotFound, got %v", err)
+	}
+
+	// Once the announcement of the channel without updates has aged past
+	// the horizon, it should be pruned along with the remaining channels
+	// which weren't updated since.
+	zombies, err = graph.PruneZombieChannels(now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to prune zombies: %v", err)
+	}
+	if len(zombies) != 3 {
+		t.Fatalf("expected 3 zombies, got %v", len(zombies))
+	}
+	asserNumChans(t, graph, 1)
+
+	// Spending the funding outpoint of a zombie should remove it from the
+	// zombie index, leaving the other zombies untouched.
+	blockHash := chainhash.Hash(rev)
+	_, err = graph.PruneGraph([]*wire.OutPoint{&emptyChan}, &blockHash, 1)
+	if err != nil {
+		t.Fatalf("unable to prune graph: %v", err)
+	}
+	isZombie, _, _, err = graph.IsZombieChannel(4)
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if isZombie {
+		t.Fatalf("spent channel still found within zombie index")
+	}
+	for _, chanID := range []uint64{2, 3} {
+		isZombie, _, _, err = graph.IsZombieChannel(chanID)
+		if err != nil {
+			t.Fatalf("unable to query zombie index: %v", err)
+		}
+		if !isZombie {
+			t.Fatalf("channel %v not found within zombie index",
+				chanID)
+		}
+	}
+	if err := graph.MarkChannelLive(4); err != ErrZombieEdgeNotFound {
+		t.Fatalf("expected ErrZombieEdgeNotFound, got %v", err)
+	}
+}
@@ -1,13 +1,17 @@
 package channeldb
 
 import (
+	"bytes"
 	"crypto/rand"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/boltdb/bolt"
 	"github.com/btcsuite/fastsha256"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 )
 
@@ -70,14 +74,15 @@ func TestInvoiceWorkflow(t *testing.T) {
 
 	// Settle the invoice, the versin retreived from the database should
 	// now have the settled bit toggle to true.
-	if err := db.SettleInvoice(paymentHash); err != nil {
+	err = db.SettleInvoice(paymentHash, fakeInvoice.Terms.Value, nil)
+	if err != nil {
 		t.Fatalf("unable to settle invoice: %v", err)
 	}
 	dbInvoice2, err := db.LookupInvoice(paymentHash)
 	if err != nil {
 		t.Fatalf("unable to fetch invoice: %v", err)
 	}
-	if !dbInvoice2.Terms.Settled {
+	if dbInvoice2.Terms.State != ContractSettled {
 		t.Fatalf("invoice should now be settled but isn't")
 	}
 
@@ -131,3 +136,997 @@ func TestInvoiceWorkflow(t *testing.T) {
 		}
 	}
 }
+
+// TestInvoicePaymentRequestSerialization tests that the payment request
+// stored along side an invoice survives serialization, and that invoices
+// written before payment requests were stored can still be read.
+func TestInvoicePaymentRequestSerialization(t *testing.T) {
+	invoice, err := randInvoice(btcutil.Amount(10000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice.CreationDate = time.Unix(1496314658, 0)
+	invoice.PaymentRequest = []byte("lntb100u1payreq")
+
+	var b bytes.Buffer
+	if err := serializeInvoice(&b, invoice); err != nil {
+		t.Fatalf("unable to serialize invoice: %v", err)
+	}
+	serialized := b.Bytes()
+
+	newInvoice, err := deserializeInvoice(bytes.NewReader(serialized))
+	if err != nil {
+		t.Fatalf("unable to deserialize invoice: %v", err)
+	}
+	if !reflect.DeepEqual(invoice, newInvoice) {
+		t.Fatalf("invoice mismatch: expected %v, got %v",
+			spew.Sdump(invoice), spew.Sdump(newInvoice))
+	}
+
+	// We'll now strip the payment request, along with the empty expiry
+	// date, hold hash, settle index, amount paid and custom records, from
+	// the serialized invoice, leaving the record as it would've been
+	// written by prior versions. The invoice should still be readable,
+	// lacking a payment request.
+	legacyLen := len(serialized) - 1 - len(invoice.PaymentRequest) - 2 -
+		8 - 8 - 1
+	legacyInvoice, err := deserializeInvoice(
+		bytes.NewReader(serialized[:legacyLen]),
+	)
+	if err != nil {
+		t.Fatalf("unable to deserialize legacy invoice: %v", err)
+	}
+	invoice.PaymentRequest = nil
+	if !reflect.DeepEqual(invoice, legacyInvoice) {
+		t.Fatalf("invoice mismatch: expected %v, got %v",
+			spew.Sdump(invoice), spew.Sdump(legacyInvoice))
+	}
+}
+
+// TestInvoiceStateTransitions tests that invoices may only transition out of
+// the open state, and that the expiry date of an invoice is persisted.
+func TestInvoiceStateTransitions(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// We'll add two invoices, one of which we'll settle, and the other
+	// we'll cancel.
+	var hashes [2][32]byte
+	for i := range hashes {
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.CreationDate = time.Unix(1496314658, 0)
+		invoice.ExpiryDate = invoice.CreationDate.Add(time.Hour)
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		hashes[i] = fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
+
+		dbInvoice, err := db.LookupInvoice(hashes[i])
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		if !reflect.DeepEqual(invoice, dbInvoice) {
+			t.Fatalf("invoice mismatch: expected %v, got %v",
+				spew.Sdump(invoice), spew.Sdump(dbInvoice))
+		}
+	}
+	settledHash, canceledHash := hashes[0], hashes[1]
+
+	if err := db.SettleInvoice(settledHash, 1000, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	if err := db.CancelInvoice(canceledHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+
+	// Settling the settled invoice again should be a noop, while any
+	// other transition out of a final state should fail.
+	if err := db.SettleInvoice(settledHash, 0, nil); err != nil {
+		t.Fatalf("unable to settle invoice again: %v", err)
+	}
+	if err := db.CancelInvoice(settledHash); err != ErrInvoiceAlreadySettled {
+		t.Fatalf("expected ErrInvoiceAlreadySettled, got %v", err)
+	}
+	err = db.SettleInvoice(canceledHash, 1000, nil)
+	if err != ErrInvoiceAlreadyCanceled {
+		t.Fatalf("expected ErrInvoiceAlreadyCanceled, got %v", err)
+	}
+
+	for hash, state := range map[[32]byte]ContractState{
+		settledHash:  ContractSettled,
+		canceledHash: ContractCanceled,
+	} {
+		invoice, err := db.LookupInvoice(hash)
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		if invoice.Terms.State != state {
+			t.Fatalf("expected state %v, got %v", state,
+				invoice.Terms.State)
+		}
+	}
+
+	// Neither invoice is open any longer, so they should be excluded
+	// when fetching pending invoices.
+	pending, err := db.FetchAllInvoices(true)
+	if err != nil {
+		t.Fatalf("unable to fetch invoices: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending invoices, got %v", len(pending))
+	}
+}
+
+// TestInvoiceAmtPaid tests that the amount actually paid to an invoice is
+// recorded separately from its value, including for invoices which don't
+// request a specific amount.
+func TestInvoiceAmtPaid(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Invoices may not request a negative amount, though they may leave
+	// the amount up to the payer.
+	invoice, err := randInvoice(btcutil.Amount(-1))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddInvoice(invoice); err == nil {
+		t.Fatalf("invoice with negative value shouldn't be added")
+	}
+	invoice.Terms.Value = 0
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	hash := invoice.PaymentHash()
+
+	// Settling the invoice should record the amount paid, and any further
+	// payments should be added to it without assigning a new settle
+	// index.
+	if err := db.SettleInvoice(hash, 1500, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	if err := db.SettleInvoice(hash, 500, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice, err := db.LookupInvoice(hash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.Value != 0 {
+		t.Fatalf("expected value 0, got %v", dbInvoice.Terms.Value)
+	}
+	if dbInvoice.AmtPaid != 2000 {
+		t.Fatalf("expected amount paid 2000, got %v", dbInvoice.AmtPaid)
+	}
+	if dbInvoice.SettleIndex != 1 {
+		t.Fatalf("expected settle index 1, got %v",
+			dbInvoice.SettleIndex)
+	}
+
+	// The amount paid to a hold invoice is recorded once it's accepted.
+	hold, err := randInvoice(btcutil.Amount(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	preimage := hold.Terms.PaymentPreimage
+	holdHash := hold.PaymentHash()
+	hold.Terms.PaymentPreimage = [32]byte{}
+	hold.Terms.HoldHash = &holdHash
+	if err := db.AddInvoice(hold); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	if err := db.AcceptInvoice(holdHash, 1200, nil); err != nil {
+		t.Fatalf("unable to accept invoice: %v", err)
+	}
+	if err := db.SettleHoldInvoice(preimage); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice, err = db.LookupInvoice(holdHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.AmtPaid != 1200 {
+		t.Fatalf("expected amount paid 1200, got %v", dbInvoice.AmtPaid)
+	}
+
+	// Settled invoices written before the amount paid was recorded must
+	// have been paid their exact value. We'll mimic the records written
+	// by each older version by cutting the record short at the end of
+	// each of the fields following the state, up to the amount paid.
+	var b bytes.Buffer
+	if err := serializeInvoice(&b, dbInvoice); err != nil {
+		t.Fatalf("unable to serialize invoice: %v", err)
+	}
+	serialized := b.Bytes()
+
+	varBytesSize := func(b []byte) int {
+		return wire.VarIntSerializeSize(uint64(len(b))) + len(b)
+	}
+	var expiryBytes []byte
+	if !dbInvoice.ExpiryDate.IsZero() {
+		expiryBytes, err = dbInvoice.ExpiryDate.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unable to serialize expiry: %v", err)
+		}
+	}
+
+	// The records end at the settle index, the hold hash, the expiry
+	// date, the payment request, and the state respectively.
+	recordLens := []int{len(serialized) - 1 - 8}
+	for _, fieldSize := range []int{
+		8, varBytesSize(holdHash[:]), varBytesSize(expiryBytes),
+		varBytesSize(dbInvoice.PaymentRequest),
+	} {
+		recordLen := recordLens[len(recordLens)-1] - fieldSize
+		recordLens = append(recordLens, recordLen)
+	}
+
+	for _, recordLen := range recordLens {
+		legacyInvoice, err := deserializeInvoice(
+			bytes.NewReader(serialized[:recordLen]),
+		)
+		if err != nil {
+			t.Fatalf("unable to deserialize legacy invoice of "+
+				"length %v: %v", recordLen, err)
+		}
+		if legacyInvoice.AmtPaid != 1000 {
+			t.Fatalf("expected amount paid 1000 for record of "+
+				"length %v, got %v", recordLen,
+				legacyInvoice.AmtPaid)
+		}
+	}
+}
+
+// TestInvoiceCustomRecordsSerialization tests that the custom records of an
+// invoice survive serialization, that the records delivered by a payment are
+// stored once the invoice is settled, and that oversized records are
+// rejected.
+func TestInvoiceCustomRecordsSerialization(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	invoice, err := randInvoice(btcutil.Amount(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice.CreationDate = time.Unix(1496314658, 0)
+	invoice.CustomRecords = map[uint64][]byte{
+		65536:      []byte("custom"),
+		5482373484: bytes.Repeat([]byte{1}, 32),
+		65537:      {},
+	}
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	dbInvoice, err := db.LookupInvoice(invoice.PaymentHash())
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if !reflect.DeepEqual(invoice, dbInvoice) {
+		t.Fatalf("invoice mismatch: expected %v, got %v",
+			spew.Sdump(invoice), spew.Sdump(dbInvoice))
+	}
+
+	// The records delivered by the payment of the invoice should be
+	// added to those of the invoice once settled, without replacing any
+	// existing records of the same type. Similarly, the records delivered
+	// by any further payments shouldn't replace those of earlier ones.
+	err = db.SettleInvoice(invoice.PaymentHash(), 1000, map[uint64][]byte{
+		65536: []byte("order"),
+		65538: []byte("message"),
+	})
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	err = db.SettleInvoice(invoice.PaymentHash(), 1000, map[uint64][]byte{
+		65538: []byte("later message"),
+		65539: []byte("tip"),
+	})
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice, err = db.LookupInvoice(invoice.PaymentHash())
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	expectedRecords := map[uint64][]byte{
+		65536:      []byte("custom"),
+		65537:      {},
+		65538:      []byte("message"),
+		65539:      []byte("tip"),
+		5482373484: bytes.Repeat([]byte{1}, 32),
+	}
+	if !reflect.DeepEqual(dbInvoice.CustomRecords, expectedRecords) {
+		t.Fatalf("custom records mismatch: expected %v, got %v",
+			spew.Sdump(expectedRecords),
+			spew.Sdump(dbInvoice.CustomRecords))
+	}
+
+	invoice, err = randInvoice(btcutil.Amount(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	invoice.CustomRecords = map[uint64][]byte{
+		65536: make([]byte, MaxCustomRecordSize+1),
+	}
+	if err := db.AddInvoice(invoice); err == nil {
+		t.Fatalf("invoice with oversized record shouldn't be added")
+	}
+}
+
+// TestExpireInvoices tests that only open invoices whose expiry date has
+// passed are marked as expired.
+func TestExpireInvoices(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	now := time.Unix(1496314658, 0)
+	addInvoice := func(expiry time.Time, settle bool) [32]byte {
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.ExpiryDate = expiry
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		hash := fastsha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		if settle {
+			if err := db.SettleInvoice(hash, 1000, nil); err != nil {
+				t.Fatalf("unable to settle invoice: %v", err)
+			}
+		}
+		return hash
+	}
+
+	expiredHash := addInvoice(now.Add(-time.Minute), false)
+	settledHash := addInvoice(now.Add(-time.Minute), true)
+	openHash := addInvoice(now.Add(time.Minute), false)
+	noExpiryHash := addInvoice(time.Time{}, false)
+
+	expired, err := db.ExpireInvoices(now)
+	if err != nil {
+		t.Fatalf("unable to expire invoices: %v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("expected 1 expired invoice, got %v", len(expired))
+	}
+	hash := fastsha256.Sum256(expired[0].Terms.PaymentPreimage[:])
+	if hash != expiredHash {
+		t.Fatalf("wrong invoice expired: expected %x, got %x",
+			expiredHash, hash)
+	}
+
+	for hash, state := range map[[32]byte]ContractState{
+		expiredHash:  ContractExpired,
+		settledHash:  ContractSettled,
+		openHash:     ContractOpen,
+		noExpiryHash: ContractOpen,
+	} {
+		invoice, err := db.LookupInvoice(hash)
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		if invoice.Terms.State != state {
+			t.Fatalf("expected state %v, got %v", state,
+				invoice.Terms.State)
+		}
+	}
+
+	// An expired invoice can no longer be settled.
+	err = db.SettleInvoice(expiredHash, 1000, nil)
+	if err != ErrInvoiceExpired {
+		t.Fatalf("expected ErrInvoiceExpired, got %v", err)
+	}
+
+	// Only the invoice which remains open with an expiry should be left
+	// within the expiry index.
+	if n := numExpiryIndexEntries(t, db); n != 1 {
+		t.Fatalf("expected 1 expiry index entry, got %v", n)
+	}
+
+	// Once its expiry has passed, the remaining open invoice should be
+	// expired as well, leaving the index empty.
+	expired, err = db.ExpireInvoices(now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to expire invoices: %v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("expected 1 expired invoice, got %v", len(expired))
+	}
+	hash = fastsha256.Sum256(expired[0].Terms.PaymentPreimage[:])
+	if hash != openHash {
+		t.Fatalf("wrong invoice expired: expected %x, got %x",
+			openHash, hash)
+	}
+	if n := numExpiryIndexEntries(t, db); n != 0 {
+		t.Fatalf("expected empty expiry index, got %v entries", n)
+	}
+}
+
+// numExpiryIndexEntries returns the number of invoices within the expiry
+// index of the passed database.
+func numExpiryIndexEntries(t *testing.T, db *DB) int {
+	var n int
+	err := db.View(func(tx *bolt.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return nil
+		}
+		expiryIndex := invoices.Bucket(invoiceExpiryIndexBucket)
+		if expiryIndex == nil {
+			return nil
+		}
+
+		n = expiryIndex.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read expiry index: %v", err)
+	}
+
+	return n
+}
+
+// TestInvoiceExpiryIndexMigration tests that the migration to database
+// version 2 indexes all open invoices which expire by their expiry date.
+func TestInvoiceExpiryIndexMigration(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	now := time.Unix(1496314658, 0)
+	for _, expiry := range []time.Time{
+		now.Add(-time.Minute), now.Add(time.Minute), {},
+	} {
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.ExpiryDate = expiry
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+
+	// We'll remove the expiry index to mimic a database created before
+	// the index was introduced, then apply the migration.
+	err = db.Update(func(tx *bolt.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		err := invoices.DeleteBucket(invoiceExpiryIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		return invoiceExpiryIndexMigration(tx)
+	})
+	if err != nil {
+		t.Fatalf("unable to migrate database: %v", err)
+	}
+
+	if n := numExpiryIndexEntries(t, db); n != 2 {
+		t.Fatalf("expected 2 expiry index entries, got %v", n)
+	}
+	expired, err := db.ExpireInvoices(now)
+	if err != nil {
+		t.Fatalf("unable to expire invoices: %v", err)
+	}
+	if len(expired) != 1 {
+		t.Fatalf("expected 1 expired invoice, got %v", len(expired))
+	}
+}
+
+// TestHoldInvoiceWorkflow tests that hold invoices are indexed by their
+// payment hash, and may only be settled using their preimage once accepted.
+func TestHoldInvoiceWorkflow(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// We'll create two hold invoices, knowing only their payment hashes.
+	var (
+		preimages [2][32]byte
+		hashes    [2][32]byte
+	)
+	for i := range preimages {
+		if _, err := rand.Read(preimages[i][:]); err != nil {
+			t.Fatalf("unable to generate preimage: %v", err)
+		}
+		hashes[i] = fastsha256.Sum256(preimages[i][:])
+
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.CreationDate = time.Unix(1496314658, 0)
+		invoice.Terms.PaymentPreimage = [32]byte{}
+		invoice.Terms.HoldHash = &hashes[i]
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		dbInvoice, err := db.LookupInvoice(hashes[i])
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		if !reflect.DeepEqual(invoice, dbInvoice) {
+			t.Fatalf("invoice mismatch: expected %v, got %v",
+				spew.Sdump(invoice), spew.Sdump(dbInvoice))
+		}
+	}
+
+	// A hold invoice can't be settled before it has been accepted, nor
+	// without its preimage.
+	if err := db.SettleHoldInvoice(preimages[0]); err != ErrInvoiceNotAccepted {
+		t.Fatalf("expected ErrInvoiceNotAccepted, got %v", err)
+	}
+	if err := db.AcceptInvoice(hashes[0], 1000, nil); err != nil {
+		t.Fatalf("unable to accept invoice: %v", err)
+	}
+	err = db.SettleInvoice(hashes[0], 1000, nil)
+	if err != ErrInvoicePreimageRequired {
+		t.Fatalf("expected ErrInvoicePreimageRequired, got %v", err)
+	}
+
+	// Once accepted, the invoice should still be considered pending.
+	pending, err := db.FetchAllInvoices(true)
+	if err != nil {
+		t.Fatalf("unable to fetch invoices: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending invoices, got %v", len(pending))
+	}
+
+	// Settling the invoice using its preimage should store the preimage
+	// along side it.
+	if err := db.SettleHoldInvoice(preimages[0]); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	invoice, err := db.LookupInvoice(hashes[0])
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.State != ContractSettled {
+		t.Fatalf("expected invoice to be settled, got %v",
+			invoice.Terms.State)
+	}
+	if invoice.Terms.PaymentPreimage != preimages[0] {
+		t.Fatalf("preimage not stored with settled invoice")
+	}
+	if invoice.PaymentHash() != hashes[0] {
+		t.Fatalf("payment hash mismatch")
+	}
+
+	// The second invoice will be canceled after being accepted, after
+	// which it can no longer be settled.
+	if err := db.AcceptInvoice(hashes[1], 1000, nil); err != nil {
+		t.Fatalf("unable to accept invoice: %v", err)
+	}
+	if err := db.CancelInvoice(hashes[1]); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	if err := db.SettleHoldInvoice(preimages[1]); err != ErrInvoiceAlreadyCanceled {
+		t.Fatalf("expected ErrInvoiceAlreadyCanceled, got %v", err)
+	}
+
+	// Finally, regular invoices can't be accepted.
+	regular, err := randInvoice(btcutil.Amount(1000))
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddInvoice(regular); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	err = db.AcceptInvoice(regular.PaymentHash(), 1000, nil)
+	if err != ErrInvoiceNotHold {
+		t.Fatalf("expected ErrInvoiceNotHold, got %v", err)
+	}
+}
+
+// TestInvoiceAddSettleIndex tests that invoices are assigned monotonically
+// increasing add and settle indexes, and that the invoices added or settled
+// since a particular index can be queried.
+func TestInvoiceAddSettleIndex(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Before any invoices have been added, no invoices should be returned.
+	added, err := db.InvoicesAddedSince(0)
+	if err != nil {
+		t.Fatalf("unable to query added invoices: %v", err)
+	}
+	if len(added) != 0 {
+		t.Fatalf("expected no invoices, got %v", len(added))
+	}
+
+	// We'll add five invoices, which should be assigned add indexes 1
+	// through 5.
+	const numInvoices = 5
+	var hashes [numInvoices][32]byte
+	for i := 0; i < numInvoices; i++ {
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+		if invoice.AddIndex != uint64(i+1) {
+			t.Fatalf("expected add index %v, got %v", i+1,
+				invoice.AddIndex)
+		}
+		hashes[i] = invoice.PaymentHash()
+	}
+
+	added, err = db.InvoicesAddedSince(2)
+	if err != nil {
+		t.Fatalf("unable to query added invoices: %v", err)
+	}
+	if len(added) != 3 {
+		t.Fatalf("expected 3 invoices, got %v", len(added))
+	}
+	for i, invoice := range added {
+		if invoice.AddIndex != uint64(i+3) {
+			t.Fatalf("expected add index %v, got %v", i+3,
+				invoice.AddIndex)
+		}
+	}
+
+	// We'll now settle the invoices out of order. Their settle indexes
+	// should reflect the order they were settled in, and settling an
+	// invoice a second time shouldn't assign it a new index.
+	settleOrder := []int{3, 0, 4}
+	for _, i := range settleOrder {
+		if err := db.SettleInvoice(hashes[i], 1000, nil); err != nil {
+			t.Fatalf("unable to settle invoice: %v", err)
+		}
+	}
+	if err := db.SettleInvoice(hashes[3], 0, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	invoice, err := db.LookupInvoice(hashes[3])
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.SettleIndex != 1 || invoice.AddIndex != 4 {
+		t.Fatalf("expected add index 4 and settle index 1, got %v "+
+			"and %v", invoice.AddIndex, invoice.SettleIndex)
+	}
+	invoice, err = db.LookupInvoice(hashes[1])
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.SettleIndex != 0 {
+		t.Fatalf("open invoice has settle index %v",
+			invoice.SettleIndex)
+	}
+
+	settled, err := db.InvoicesSettledSince(1)
+	if err != nil {
+		t.Fatalf("unable to query settled invoices: %v", err)
+	}
+	if len(settled) != 2 {
+		t.Fatalf("expected 2 invoices, got %v", len(settled))
+	}
+	for i, invoice := range settled {
+		if invoice.SettleIndex != uint64(i+2) {
+			t.Fatalf("expected settle index %v, got %v", i+2,
+				invoice.SettleIndex)
+		}
+		if invoice.PaymentHash() != hashes[settleOrder[i+1]] {
+			t.Fatalf("settled invoice %v has wrong payment hash",
+				i)
+		}
+	}
+
+	settled, err = db.InvoicesSettledSince(3)
+	if err != nil {
+		t.Fatalf("unable to query settled invoices: %v", err)
+	}
+	if len(settled) != 0 {
+		t.Fatalf("expected no invoices, got %v", len(settled))
+	}
+}
+
+// TestQueryInvoices tests that pages of invoices can be queried in either
+// direction, and that the query's filters are applied.
+func TestQueryInvoices(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Querying an empty database should return an empty page.
+	resp, err := db.QueryInvoices(InvoiceQuery{})
+	if err != nil {
+		t.Fatalf("unable to query invoices: %v", err)
+	}
+	if len(resp.Invoices) != 0 {
+		t.Fatalf("expected no invoices, got %v", len(resp.Invoices))
+	}
+
+	// We'll add ten invoices, created a second apart, settling those with
+	// an even add index.
+	const numInvoices = 10
+	baseTime := time.Unix(1496314658, 0)
+	for i := 0; i < numInvoices; i++ {
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.CreationDate = baseTime.Add(time.Duration(i) * time.Second)
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		if invoice.AddIndex%2 == 0 {
+			err := db.SettleInvoice(invoice.PaymentHash(), 1000, nil)
+			if err != nil {
+				t.Fatalf("unable to settle invoice: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   InvoiceQuery
+		indexes []uint64
+	}{
+		{
+			name:    "first page",
+			query:   InvoiceQuery{NumMaxInvoices: 3},
+			indexes: []uint64{1, 2, 3},
+		},
+		{
+			name: "next page",
+			query: InvoiceQuery{
+				IndexOffset:    3,
+				NumMaxInvoices: 3,
+			},
+			indexes: []uint64{4, 5, 6},
+		},
+		{
+			name:    "past last invoice",
+			query:   InvoiceQuery{IndexOffset: numInvoices},
+			indexes: nil,
+		},
+		{
+			name: "last page",
+			query: InvoiceQuery{
+				NumMaxInvoices: 3,
+				Reversed:       true,
+			},
+			indexes: []uint64{8, 9, 10},
+		},
+		{
+			name: "previous page",
+			query: InvoiceQuery{
+				IndexOffset:    8,
+				NumMaxInvoices: 3,
+				Reversed:       true,
+			},
+			indexes: []uint64{5, 6, 7},
+		},
+		{
+			name: "partial first page in reverse",
+			query: InvoiceQuery{
+				IndexOffset:    2,
+				NumMaxInvoices: 3,
+				Reversed:       true,
+			},
+			indexes: []uint64{1},
+		},
+		{
+			name: "settled invoices",
+			query: InvoiceQuery{
+				IndexOffset:    2,
+				NumMaxInvoices: 2,
+				States:         []ContractState{ContractSettled},
+			},
+			indexes: []uint64{4, 6},
+		},
+		{
+			name: "pending invoices in reverse",
+			query: InvoiceQuery{
+				NumMaxInvoices: 2,
+				Reversed:       true,
+				PendingOnly:    true,
+			},
+			indexes: []uint64{7, 9},
+		},
+		{
+			name: "creation date range",
+			query: InvoiceQuery{
+				CreationDateStart: baseTime.Add(3 * time.Second),
+				CreationDateEnd:   baseTime.Add(5 * time.Second),
+			},
+			indexes: []uint64{4, 5, 6},
+		},
+		{
+			name: "creation date range in reverse",
+			query: InvoiceQuery{
+				NumMaxInvoices:    2,
+				Reversed:          true,
+				CreationDateStart: baseTime.Add(3 * time.Second),
+				CreationDateEnd:   baseTime.Add(7 * time.Second),
+			},
+			indexes: []uint64{7, 8},
+		},
+		{
+			name: "created at or before date",
+			query: InvoiceQuery{
+				CreationDateEnd: baseTime.Add(time.Second),
+			},
+			indexes: []uint64{1, 2},
+		},
+		{
+			name: "created at or after date",
+			query: InvoiceQuery{
+				IndexOffset:       9,
+				CreationDateStart: baseTime.Add(7 * time.Second),
+			},
+			indexes: []uint64{10},
+		},
+		{
+			name: "no invoices created within range",
+			query: InvoiceQuery{
+				CreationDateStart: baseTime.Add(time.Hour),
+			},
+			indexes: nil,
+		},
+	}
+
+	for _, test := range tests {
+		resp, err := db.QueryInvoices(test.query)
+		if err != nil {
+			t.Fatalf("%v: unable to query invoices: %v", test.name,
+				err)
+		}
+
+		var indexes []uint64
+		for _, invoice := range resp.Invoices {
+			indexes = append(indexes, invoice.AddIndex)
+		}
+		if !reflect.DeepEqual(indexes, test.indexes) {
+			t.Fatalf("%v: expected invoices %v, got %v", test.name,
+				test.indexes, indexes)
+		}
+
+		var first, last uint64
+		if len(indexes) != 0 {
+			first, last = indexes[0], indexes[len(indexes)-1]
+		}
+		if resp.FirstIndexOffset != first || resp.LastIndexOffset != last {
+			t.Fatalf("%v: expected index offsets %v and %v, got "+
+				"%v and %v", test.name, first, last,
+				resp.FirstIndexOffset, resp.LastIndexOffset)
+		}
+	}
+}
+
+// TestCreationDateIndexMigration tests that the creation date index migration
+// indexes all existing invoices and payments, allowing them to be queried by
+// their creation date.
+func TestCreationDateIndexMigration(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	baseTime := time.Unix(1496314658, 0)
+	for i := 0; i < 3; i++ {
+		creationDate := baseTime.Add(time.Duration(i) * time.Minute)
+
+		invoice, err := randInvoice(btcutil.Amount(1000))
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.CreationDate = creationDate
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+
+		payment, err := makeRandomFakePayment()
+		if err != nil {
+			t.Fatalf("unable to create payment: %v", err)
+		}
+		payment.CreationDate = creationDate
+		if err := db.AddPayment(payment); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+	}
+
+	// We'll remove the creation indexes to mimic a database created
+	// before they were introduced, then apply the migration.
+	err = db.Update(func(tx *bolt.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		err := invoices.DeleteBucket(invoiceCreationIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		payments := tx.Bucket(paymentBucket)
+		err = payments.DeleteBucket(paymentCreationIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		return creationDateIndexMigration(tx)
+	})
+	if err != nil {
+		t.Fatalf("unable to migrate database: %v", err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		invoiceIndex := tx.Bucket(invoiceBucket).Bucket(
+			invoiceCreationIndexBucket,
+		)
+		paymentIndex := tx.Bucket(paymentBucket).Bucket(
+			paymentCreationIndexBucket,
+		)
+		if invoiceIndex == nil || paymentIndex == nil {
+			return fmt.Errorf("creation indexes not found")
+		}
+		if n := invoiceIndex.Stats().KeyN; n != 3 {
+			return fmt.Errorf("expected 3 indexed invoices, got %v",
+				n)
+		}
+		if n := paymentIndex.Stats().KeyN; n != 3 {
+			return fmt.Errorf("expected 3 indexed payments, got %v",
+				n)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("invalid creation indexes: %v", err)
+	}
+
+	invoices, err := db.QueryInvoices(InvoiceQuery{
+		CreationDateStart: baseTime.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unable to query invoices: %v", err)
+	}
+	if len(invoices.Invoices) != 2 {
+		t.Fatalf("expected 2 invoices, got %v", len(invoices.Invoices))
+	}
+
+	payments, err := db.QueryPayments(PaymentsQuery{
+		CreationDateEnd: baseTime.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unable to query payments: %v", err)
+	}
+	if len(payments.Payments) != 2 {
+		t.Fatalf("expected 2 payments, got %v", len(payments.Payments))
+	}
+}
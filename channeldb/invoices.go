@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -33,6 +34,28 @@ var (
 	// stored within the invoiceIndexBucket. Within the invoiceBucket
 	// invoices are uniquely identified by the invoice ID.
 	numInvoicesKey = []byte("nik")
+
+	// settleIndexBucket is the name of the sub-bucket within the
+	// invoiceBucket which indexes all settled invoices by their settle
+	// index. The settle index is a monotonically increasing uint64 which
+	// is assigned to each invoice in the order they're settled, and maps
+	// to the invoice ID of the settled invoice.
+	settleIndexBucket = []byte("settleindex")
+
+	// invoiceExpiryIndexBucket is the name of the sub-bucket within the
+	// invoiceBucket which indexes open invoices by their expiry date. Each
+	// key is the expiry date of an invoice as a big-endian unix timestamp,
+	// followed by the invoice ID, allowing the invoices which have expired
+	// by a given time to be found with a cursor scan. Invoices are removed
+	// from the index once they're no longer open.
+	invoiceExpiryIndexBucket = []byte("expiryindex")
+
+	// invoiceCreationIndexBucket is the name of the sub-bucket within the
+	// invoiceBucket which indexes all invoices by their creation date.
+	// Keys have the same format as those of the expiry index, allowing
+	// the invoices created within a range of dates to be found without
+	// reading every invoice.
+	invoiceCreationIndexBucket = []byte("creationindex")
 )
 
 const (
@@ -43,24 +66,87 @@ const (
 	// MaxReceiptSize is the maximum size of the payment receipt stored
 	// within the database along side incoming/outgoing invoices.
 	MaxReceiptSize = 1024
+
+	// MaxPaymentRequestSize is the maximum size of the encoded payment
+	// request stored within the database along side an invoice.
+	MaxPaymentRequestSize = 4096
+
+	// MaxCustomRecordSize is the maximum size of the value of each custom
+	// record stored within the database along side an invoice.
+	MaxCustomRecordSize = 1024
 )
 
+// ContractState describes the state the contract of an invoice is in. Every
+// invoice starts out as open, and may then transition into exactly one of
+// the remaining, final, states.
+type ContractState uint8
+
+const (
+	// ContractOpen means the invoice has only been created, and is still
+	// awaiting payment.
+	ContractOpen ContractState = 0
+
+	// ContractSettled means the invoice has been paid in full.
+	ContractSettled ContractState = 1
+
+	// ContractCanceled means the invoice has been canceled by its creator,
+	// and will no longer accept payment.
+	ContractCanceled ContractState = 2
+
+	// ContractExpired means the expiry of the invoice passed before it was
+	// paid, so it will no longer accept payment.
+	ContractExpired ContractState = 3
+
+	// ContractAccepted means the full payment of a hold invoice has
+	// arrived, and its HTLCs are being held until the invoice is either
+	// settled using its preimage, or canceled.
+	ContractAccepted ContractState = 4
+)
+
+// String returns a human-readable version of the ContractState.
+func (c ContractState) String() string {
+	switch c {
+	case ContractOpen:
+		return "Open"
+	case ContractSettled:
+		return "Settled"
+	case ContractCanceled:
+		return "Canceled"
+	case ContractExpired:
+		return "Expired"
+	case ContractAccepted:
+		return "Accepted"
+	default:
+		return "Unknown"
+	}
+}
+
 // ContractTerm is a companion struct to the Invoice struct. This struct houses
 // the necessary conditions required before the invoice can be considered fully
 // settled by the payee.
 type ContractTerm struct {
 	// PaymentPreimage is the preimage which is to be revealed in the
 	// occasion that an HTLC paying to the hash of this preimage is
-	// extended.
+	// extended. For hold invoices, this is only known once the invoice
+	// has been settled.
 	PaymentPreimage [32]byte
 
+	// HoldHash is the payment hash of a hold invoice. The preimage of a
+	// hold invoice isn't known when the invoice is created, and is only
+	// supplied once the invoice is settled, so HTLCs paying to the
+	// invoice are matched against this hash instead. This is nil for
+	// regular invoices, whose payment hash is derived from their
+	// preimage.
+	HoldHash *[32]byte
+
 	// Value is the expected amount to be payed to an HTLC which can be
-	// satisfied by the above preimage.
+	// satisfied by the above preimage. A value of zero indicates the
+	// payer may choose the amount to pay.
 	Value btcutil.Amount
 
-	// Settled indicates if this particular contract term has been fully
-	// settled by the payer.
-	Settled bool
+	// State is the current state of the contract. An invoice may only be
+	// paid while its contract is open.
+	State ContractState
 }
 
 // Invoice is a payment invoice generated by a payee in order to request
@@ -89,6 +175,10 @@ type Invoice struct {
 	// CreationDate is the exact time the invoice was created.
 	CreationDate time.Time
 
+	// ExpiryDate is the time after which the invoice will no longer accept
+	// payment. A zero value indicates the invoice never expires.
+	ExpiryDate time.Time
+
 	// Terms are the contractual payment terms of the invoice. Once
 	// all the terms have been satisfied by the payer, then the invoice can
 	// be considered fully fulfilled.
@@ -96,9 +186,58 @@ type Invoice struct {
 	// TODO(roasbeef): later allow for multiple terms to fulfill the final
 	// invoice: payment fragmentation, etc.
 	Terms ContractTerm
+
+	// PaymentRequest is an optional field storing the encoded payment
+	// request handed out to the payer of the invoice. As the payment
+	// request is signed, it can't be re-created when the invoice is
+	// listed, so it's stored along side the invoice.
+	PaymentRequest []byte
+
+	// AddIndex is a monotonically increasing index assigned to each
+	// invoice in the order they're added to the database, starting at 1.
+	// It's derived from the invoice ID, so isn't stored within the
+	// serialized invoice itself.
+	AddIndex uint64
+
+	// SettleIndex is a monotonically increasing index assigned to each
+	// invoice in the order they're settled, starting at 1. A value of
+	// zero indicates the invoice hasn't been settled.
+	SettleIndex uint64
+
+	// AmtPaid is the total amount actually paid to the invoice, which may
+	// differ from the requested value if the invoice is overpaid, or
+	// doesn't request a specific amount.
+	AmtPaid btcutil.Amount
+
+	// CustomRecords is the set of custom records delivered by the payer
+	// within the final hop payload of the payment, keyed by their type.
+	// Records are added once the invoice is paid. Records of a type the
+	// invoice already carries are kept, so later payments can't replace
+	// the records delivered by the first.
+	CustomRecords map[uint64][]byte
+}
+
+// IsHold returns true if the invoice is a hold invoice, whose preimage is
+// only supplied once the invoice is settled.
+func (i *Invoice) IsHold() bool {
+	return i.Terms.HoldHash != nil
+}
+
+// PaymentHash returns the payment hash HTLCs paying to the invoice are locked
+// to.
+func (i *Invoice) PaymentHash() [32]byte {
+	if i.Terms.HoldHash != nil {
+		return *i.Terms.HoldHash
+	}
+
+	return fastsha256.Sum256(i.Terms.PaymentPreimage[:])
 }
 
 func validateInvoice(i *Invoice) error {
+	if i.Terms.Value < 0 {
+		return fmt.Errorf("invoice value must not be negative, %v "+
+			"was provided", i.Terms.Value)
+	}
 	if len(i.Memo) > MaxMemoSize {
 		return fmt.Errorf("max length a memo is %v, and invoice "+
 			"of length %v was provided", MaxMemoSize, len(i.Memo))
@@ -108,6 +247,18 @@ func validateInvoice(i *Invoice) error {
 			"of length %v was provided", MaxReceiptSize,
 			len(i.Receipt))
 	}
+	if len(i.PaymentRequest) > MaxPaymentRequestSize {
+		return fmt.Errorf("max length of payment request is %v, and "+
+			"invoice of length %v was provided",
+			MaxPaymentRequestSize, len(i.PaymentRequest))
+	}
+	for recordType, value := range i.CustomRecords {
+		if len(value) > MaxCustomRecordSize {
+			return fmt.Errorf("max length of custom record is %v, "+
+				"and record %v of length %v was provided",
+				MaxCustomRecordSize, recordType, len(value))
+		}
+	}
 	return nil
 }
 
@@ -132,7 +283,7 @@ func (d *DB) AddInvoice(i *Invoice) error {
 
 		// Ensure that an invoice an identical payment hash doesn't
 		// already exist within the index.
-		paymentHash := fastsha256.Sum256(i.Terms.PaymentPreimage[:])
+		paymentHash := i.PaymentHash()
 		if invoiceIndex.Get(paymentHash[:]) != nil {
 			return ErrDuplicateInvoice
 		}
@@ -198,8 +349,8 @@ func (d *DB) LookupInvoice(paymentHash [32]byte) (*Invoice, error) {
 }
 
 // FetchAllInvoices returns all invoices currently stored within the database.
-// If the pendingOnly param is true, then only unsettled invoices will be
-// returned, skipping all invoices that are fully settled.
+// If the pendingOnly param is true, then only open and accepted invoices will
+// be returned, skipping all invoices that are settled, canceled or expired.
 func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	var invoices []*Invoice
 
@@ -217,13 +368,14 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 				return nil
 			}
 
-			invoiceReader := bytes.NewReader(v)
-			invoice, err := deserializeInvoice(invoiceReader)
+			invoice, err := decodeInvoice(k, v)
 			if err != nil {
 				return err
 			}
 
-			if pendingOnly && invoice.Terms.Settled {
+			if pendingOnly && invoice.Terms.State != ContractOpen &&
+				invoice.Terms.State != ContractAccepted {
+
 				return nil
 			}
 
@@ -239,11 +391,429 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 	return invoices, nil
 }
 
+// InvoiceQuery represents a query to the invoice database. The query allows a
+// caller to retrieve a page of invoices, starting after a particular add
+// index, which optionally match a set of filters.
+type InvoiceQuery struct {
+	// IndexOffset is the add index of the invoice after which the page
+	// starts. The invoice at this index is excluded. If Reversed is set,
+	// then the page instead consists of invoices preceding this index. A
+	// value of zero starts the page at either the first or last invoice.
+	IndexOffset uint64
+
+	// NumMaxInvoices is the maximum number of invoices returned within
+	// the page. A value of zero places no limit on the number of invoices.
+	NumMaxInvoices uint64
+
+	// Reversed, if set, pages through the invoices in descending add index
+	// order, allowing the most recently added invoices to be retrieved
+	// first.
+	Reversed bool
+
+	// PendingOnly, if set, only returns open and accepted invoices.
+	PendingOnly bool
+
+	// States, if non-empty, only returns invoices in one of these states.
+	States []ContractState
+
+	// CreationDateStart, if non-zero, only returns invoices created at or
+	// after this time.
+	CreationDateStart time.Time
+
+	// CreationDateEnd, if non-zero, only returns invoices created at or
+	// before this time.
+	CreationDateEnd time.Time
+}
+
+// matches returns true if the passed invoice matches the filters of the
+// query.
+func (q *InvoiceQuery) matches(invoice *Invoice) bool {
+	state := invoice.Terms.State
+	if q.PendingOnly && state != ContractOpen && state != ContractAccepted {
+		return false
+	}
+
+	if len(q.States) != 0 {
+		var found bool
+		for _, s := range q.States {
+			if s == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !q.CreationDateStart.IsZero() &&
+		invoice.CreationDate.Before(q.CreationDateStart) {
+
+		return false
+	}
+	if !q.CreationDateEnd.IsZero() &&
+		invoice.CreationDate.After(q.CreationDateEnd) {
+
+		return false
+	}
+
+	return true
+}
+
+// InvoiceSlice is the response to an invoice query. It includes the original
+// query, the page of invoices matching it, and the add indexes of the first
+// and last invoices within the page, which are used to continue paging.
+type InvoiceSlice struct {
+	InvoiceQuery
+
+	// Invoices is the page of invoices matching the query, in ascending
+	// add index order regardless of the direction of the query.
+	Invoices []*Invoice
+
+	// FirstIndexOffset is the add index of the first invoice within the
+	// page. When paging in reverse, it's used as the index offset of the
+	// next query.
+	FirstIndexOffset uint64
+
+	// LastIndexOffset is the add index of the last invoice within the
+	// page. When paging forwards, it's used as the index offset of the
+	// next query.
+	LastIndexOffset uint64
+}
+
+// QueryInvoices returns a page of the invoices matching the passed query. The
+// invoices are traversed using their add index, so only the invoices within
+// the page, along with those skipped by the query's filters, are read from
+// the database. Invoices created outside of the query's creation date range
+// are skipped using the creation index, without being read.
+func (d *DB) QueryInvoices(q InvoiceQuery) (InvoiceSlice, error) {
+	resp := InvoiceSlice{
+		InvoiceQuery: q,
+	}
+
+	err := d.View(func(tx *bolt.Tx) error {
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return ErrNoInvoicesCreated
+		}
+
+		// The add index of an invoice is its invoice ID plus one, so
+		// the invoice at the index offset is keyed by the offset
+		// minus one.
+		var startKey []byte
+		if q.IndexOffset != 0 {
+			offset := q.IndexOffset - 1
+			if offset > math.MaxUint32 {
+				offset = math.MaxUint32
+			}
+
+			startKey = make([]byte, 4)
+			byteOrder.PutUint32(startKey, uint32(offset))
+		}
+
+		// If the query filters invoices by their creation date, then
+		// we'll use the creation index to find the invoices created
+		// within the range, so that only they're read.
+		var created *timeRange
+		creationIndex := invoiceB.Bucket(invoiceCreationIndexBucket)
+		if creationIndex != nil && (!q.CreationDateStart.IsZero() ||
+			!q.CreationDateEnd.IsZero()) {
+
+			created = fetchTimeRange(
+				creationIndex, q.CreationDateStart,
+				q.CreationDateEnd,
+			)
+		}
+
+		return paginate(invoiceB.Cursor(), startKey, 4, q.Reversed,
+			func(k, v []byte) (bool, error) {
+				if created != nil {
+					inRange, cont := created.filter(
+						k, q.Reversed,
+					)
+					if !inRange {
+						return cont, nil
+					}
+				}
+
+				invoice, err := decodeInvoice(k, v)
+				if err != nil {
+					return false, err
+				}
+
+				if !q.matches(invoice) {
+					return true, nil
+				}
+
+				resp.Invoices = append(resp.Invoices, invoice)
+
+				return q.NumMaxInvoices == 0 ||
+					uint64(len(resp.Invoices)) < q.NumMaxInvoices, nil
+			},
+		)
+	})
+	if err != nil && err != ErrNoInvoicesCreated {
+		return resp, err
+	}
+
+	// When paging in reverse, the invoices were gathered in descending
+	// order, so we'll restore them to ascending order.
+	if q.Reversed {
+		numInvoices := len(resp.Invoices)
+		for i := 0; i < numInvoices/2; i++ {
+			opposite := numInvoices - i - 1
+			resp.Invoices[i], resp.Invoices[opposite] =
+				resp.Invoices[opposite], resp.Invoices[i]
+		}
+	}
+
+	if len(resp.Invoices) != 0 {
+		resp.FirstIndexOffset = resp.Invoices[0].AddIndex
+		resp.LastIndexOffset = resp.Invoices[len(resp.Invoices)-1].AddIndex
+	}
+
+	return resp, nil
+}
+
 // SettleInvoice attempts to mark an invoice corresponding to the passed
-// payment hash as fully settled. If an invoice matching the passed payment
-// hash doesn't existing within the database, then the action will fail with a
-// "not found" error.
-func (d *DB) SettleInvoice(paymentHash [32]byte) error {
+// payment hash as fully settled, adding amtPaid to the amount paid to the
+// invoice, and storing any custom records delivered by the payment along side
+// it. If an invoice matching the passed payment hash doesn't existing within
+// the database, then the action will fail with a "not found" error. Settling
+// an invoice which has already been settled only records the additional
+// payment, while attempting to settle a canceled or expired invoice fails.
+// Hold invoices must instead be settled using SettleHoldInvoice.
+func (d *DB) SettleInvoice(paymentHash [32]byte, amtPaid btcutil.Amount,
+	customRecords map[uint64][]byte) error {
+
+	return d.updateInvoiceState(
+		paymentHash, ContractSettled, nil, amtPaid, customRecords,
+	)
+}
+
+// AcceptInvoice marks the open hold invoice corresponding to the passed
+// payment hash as accepted, signalling that its full payment of amtPaid has
+// arrived and is being held until the invoice is settled or canceled. Any
+// custom records delivered by the payment are stored along side the invoice.
+func (d *DB) AcceptInvoice(paymentHash [32]byte, amtPaid btcutil.Amount,
+	customRecords map[uint64][]byte) error {
+
+	return d.updateInvoiceState(
+		paymentHash, ContractAccepted, nil, amtPaid, customRecords,
+	)
+}
+
+// SettleHoldInvoice settles the accepted hold invoice whose payment hash
+// matches the passed preimage, storing the preimage along side the invoice.
+func (d *DB) SettleHoldInvoice(preimage [32]byte) error {
+	paymentHash := fastsha256.Sum256(preimage[:])
+	return d.updateInvoiceState(
+		paymentHash, ContractSettled, &preimage, 0, nil,
+	)
+}
+
+// CancelInvoice attempts to mark an invoice corresponding to the passed
+// payment hash as canceled, after which it'll no longer accept payment. Only
+// open and accepted invoices may be canceled.
+func (d *DB) CancelInvoice(paymentHash [32]byte) error {
+	return d.updateInvoiceState(paymentHash, ContractCanceled, nil, 0, nil)
+}
+
+// ExpireInvoices marks all open invoices whose expiry date lies before the
+// passed time as expired. The set of newly expired invoices is returned.
+func (d *DB) ExpireInvoices(now time.Time) ([]*Invoice, error) {
+	var expired []*Invoice
+	err := d.Update(func(tx *bolt.Tx) error {
+		// Reset the set of expired invoices, as the closure may be
+		// re-executed.
+		expired = nil
+
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return nil
+		}
+		expiryIndex := invoices.Bucket(invoiceExpiryIndexBucket)
+		if expiryIndex == nil {
+			return nil
+		}
+
+		// As the index is ordered by expiry date, we only need to scan
+		// it up to the passed time. The bucket can't be modified while
+		// iterating over it, so we'll first gather the keys of all the
+		// invoices which have expired, and only then update them.
+		var expiredKeys [][]byte
+		cursor := expiryIndex.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			expiry := time.Unix(int64(byteOrder.Uint64(k[:8])), 0)
+			if !expiry.Before(now) {
+				break
+			}
+
+			key := make([]byte, len(k))
+			copy(key, k)
+			expiredKeys = append(expiredKeys, key)
+		}
+
+		for _, key := range expiredKeys {
+			invoiceNum := key[8:]
+			invoice, err := fetchInvoice(invoiceNum, invoices)
+			if err != nil {
+				return err
+			}
+
+			// The index only stores the expiry date to the
+			// second, so the invoice itself may not have expired
+			// quite yet.
+			if !invoice.ExpiryDate.Before(now) {
+				continue
+			}
+			if err := expiryIndex.Delete(key); err != nil {
+				return err
+			}
+			if invoice.Terms.State != ContractOpen {
+				continue
+			}
+
+			invoice.Terms.State = ContractExpired
+			err = putSerializedInvoice(invoices, invoiceNum, invoice)
+			if err != nil {
+				return err
+			}
+			expired = append(expired, invoice)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+// InvoicesAddedSince returns all invoices with an add index greater than the
+// passed index, in the order they were added.
+func (d *DB) InvoicesAddedSince(sinceAddIndex uint64) ([]*Invoice, error) {
+	var invoices []*Invoice
+	err := d.View(func(tx *bolt.Tx) error {
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return nil
+		}
+
+		// As the add index of an invoice is its invoice ID plus one,
+		// all invoices added since the passed index have an invoice
+		// ID of at least that index.
+		if sinceAddIndex >= math.MaxUint32 {
+			return nil
+		}
+		var startKey [4]byte
+		byteOrder.PutUint32(startKey[:], uint32(sinceAddIndex))
+
+		c := invoiceB.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil; k, v = c.Next() {
+			// Skip over the nested index buckets.
+			if v == nil || len(k) != 4 {
+				continue
+			}
+
+			invoice, err := decodeInvoice(k, v)
+			if err != nil {
+				return err
+			}
+			invoices = append(invoices, invoice)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// InvoicesSettledSince returns all invoices with a settle index greater than
+// the passed index, in the order they were settled.
+func (d *DB) InvoicesSettledSince(sinceSettleIndex uint64) ([]*Invoice, error) {
+	var invoices []*Invoice
+	err := d.View(func(tx *bolt.Tx) error {
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return nil
+		}
+		settleIndex := invoiceB.Bucket(settleIndexBucket)
+		if settleIndex == nil {
+			return nil
+		}
+
+		var startKey [8]byte
+		byteOrder.PutUint64(startKey[:], sinceSettleIndex+1)
+
+		c := settleIndex.Cursor()
+		for k, invoiceNum := c.Seek(startKey[:]); k != nil; k, invoiceNum = c.Next() {
+			invoice, err := fetchInvoice(invoiceNum, invoiceB)
+			if err != nil {
+				return err
+			}
+			invoices = append(invoices, invoice)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// LatestInvoiceIndexes returns the add index of the most recently added
+// invoice, and the settle index of the most recently settled invoice. Zero is
+// returned for either index if no invoice has been added or settled yet.
+func (d *DB) LatestInvoiceIndexes() (uint64, uint64, error) {
+	var addIndex, settleIndex uint64
+	err := d.View(func(tx *bolt.Tx) error {
+		invoiceB := tx.Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return nil
+		}
+
+		// The invoice counter is the ID of the next invoice to be
+		// added, which is the add index of the latest invoice.
+		invoiceIndex := invoiceB.Bucket(invoiceIndexBucket)
+		if invoiceIndex != nil {
+			invoiceCounter := invoiceIndex.Get(numInvoicesKey)
+			if len(invoiceCounter) == 4 {
+				addIndex = uint64(byteOrder.Uint32(invoiceCounter))
+			}
+		}
+
+		settleIndexB := invoiceB.Bucket(settleIndexBucket)
+		if settleIndexB != nil {
+			k, _ := settleIndexB.Cursor().Last()
+			if len(k) == 8 {
+				settleIndex = byteOrder.Uint64(k)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return addIndex, settleIndex, nil
+}
+
+// updateInvoiceState attempts to transition the invoice corresponding to the
+// passed payment hash into the target state, adding amtPaid to the amount paid
+// to the invoice, along with the passed custom records. The preimage must only
+// be set when settling a hold invoice.
+func (d *DB) updateInvoiceState(paymentHash [32]byte, newState ContractState,
+	preimage *[32]byte, amtPaid btcutil.Amount,
+	customRecords map[uint64][]byte) error {
+
 	return d.Update(func(tx *bolt.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
 		if err != nil {
@@ -261,7 +831,10 @@ func (d *DB) SettleInvoice(paymentHash [32]byte) error {
 			return ErrInvoiceNotFound
 		}
 
-		return settleInvoice(invoices, invoiceNum)
+		return updateInvoiceState(
+			invoices, invoiceNum, newState, preimage, amtPaid,
+			customRecords,
+		)
 	})
 }
 
@@ -272,6 +845,7 @@ func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	// of the invoice number.
 	var invoiceKey [4]byte
 	byteOrder.PutUint32(invoiceKey[:], invoiceNum)
+	i.AddIndex = uint64(invoiceNum) + 1
 
 	// Increment the num invoice counter index so the next invoice bares
 	// the proper ID.
@@ -285,11 +859,37 @@ func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	// Add the payment hash to the invoice index. This'll let us quickly
 	// identify if we can settle an incoming payment, and also to possibly
 	// allow a single invoice to have multiple payment installations.
-	paymentHash := fastsha256.Sum256(i.Terms.PaymentPreimage[:])
+	paymentHash := i.PaymentHash()
 	if err := invoiceIndex.Put(paymentHash[:], invoiceKey[:]); err != nil {
 		return err
 	}
 
+	// Open invoices which expire are added to the expiry index, allowing
+	// them to be expired efficiently.
+	if i.Terms.State == ContractOpen && !i.ExpiryDate.IsZero() {
+		expiryIndex, err := invoices.CreateBucketIfNotExists(
+			invoiceExpiryIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+		indexKey := timeIndexKey(i.ExpiryDate, invoiceKey[:])
+		if err := expiryIndex.Put(indexKey, nil); err != nil {
+			return err
+		}
+	}
+
+	creationIndex, err := invoices.CreateBucketIfNotExists(
+		invoiceCreationIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+	indexKey := timeIndexKey(i.CreationDate, invoiceKey[:])
+	if err := creationIndex.Put(indexKey, nil); err != nil {
+		return err
+	}
+
 	// Finally, serialize the invoice itself to be written to the disk.
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, i); err != nil {
@@ -325,14 +925,71 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		return err
 	}
 
-	var settleByte [1]byte
-	if i.Terms.Settled {
-		settleByte[0] = 1
+	// The state of the contract is stored within the byte previously used
+	// to indicate whether the invoice was settled, as the values of the
+	// open and settled states match the values used by that flag.
+	stateByte := [1]byte{byte(i.Terms.State)}
+	if _, err := w.Write(stateByte[:]); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarBytes(w, 0, i.PaymentRequest); err != nil {
+		return err
+	}
+
+	// Invoices which never expire are written with an empty expiry date.
+	var expiryBytes []byte
+	if !i.ExpiryDate.IsZero() {
+		expiryBytes, err = i.ExpiryDate.MarshalBinary()
+		if err != nil {
+			return err
+		}
 	}
-	if _, err := w.Write(settleByte[:]); err != nil {
+
+	if err := wire.WriteVarBytes(w, 0, expiryBytes); err != nil {
 		return err
 	}
 
+	// We'll then write the payment hash of hold invoices, which is left
+	// empty for regular invoices.
+	var holdHash []byte
+	if i.Terms.HoldHash != nil {
+		holdHash = i.Terms.HoldHash[:]
+	}
+
+	if err := wire.WriteVarBytes(w, 0, holdHash); err != nil {
+		return err
+	}
+
+	// Next, we'll write the settle index, which is zero for invoices yet
+	// to be settled.
+	byteOrder.PutUint64(scratch[:], i.SettleIndex)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	// Next, we'll write the amount paid to the invoice.
+	byteOrder.PutUint64(scratch[:], uint64(i.AmtPaid))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	// Finally, we'll write the number of custom records, followed by each
+	// record's type and value.
+	numRecords := uint64(len(i.CustomRecords))
+	if err := wire.WriteVarInt(w, 0, numRecords); err != nil {
+		return err
+	}
+	for recordType, value := range i.CustomRecords {
+		byteOrder.PutUint64(scratch[:], recordType)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -342,9 +999,19 @@ func fetchInvoice(invoiceNum []byte, invoices *bolt.Bucket) (*Invoice, error) {
 		return nil, ErrInvoiceNotFound
 	}
 
-	invoiceReader := bytes.NewReader(invoiceBytes)
+	return decodeInvoice(invoiceNum, invoiceBytes)
+}
+
+// decodeInvoice deserializes the invoice stored under the passed invoice
+// number, populating its add index.
+func decodeInvoice(invoiceNum, invoiceBytes []byte) (*Invoice, error) {
+	invoice, err := deserializeInvoice(bytes.NewReader(invoiceBytes))
+	if err != nil {
+		return nil, err
+	}
+	invoice.AddIndex = uint64(byteOrder.Uint32(invoiceNum)) + 1
 
-	return deserializeInvoice(invoiceReader)
+	return invoice, nil
 }
 
 func deserializeInvoice(r io.Reader) (*Invoice, error) {
@@ -378,28 +1045,263 @@ func deserializeInvoice(r io.Reader) (*Invoice, error) {
 	}
 	invoice.Terms.Value = btcutil.Amount(byteOrder.Uint64(scratch[:]))
 
-	var settleByte [1]byte
-	if _, err := io.ReadFull(r, settleByte[:]); err != nil {
+	var stateByte [1]byte
+	if _, err := io.ReadFull(r, stateByte[:]); err != nil {
+		return nil, err
+	}
+	invoice.Terms.State = ContractState(stateByte[0])
+
+	// Invoices written before payment requests were stored lack the
+	// field entirely, so we'll tolerate reaching the end of the record.
+	invoice.PaymentRequest, err = wire.ReadVarBytes(
+		r, 0, MaxPaymentRequestSize, "payreq",
+	)
+	switch {
+	case err == io.EOF:
+		invoice.PaymentRequest = nil
+	case err != nil:
+		return nil, err
+	case len(invoice.PaymentRequest) == 0:
+		invoice.PaymentRequest = nil
+	}
+
+	// Similarly, invoices written before expiry dates were stored never
+	// expire.
+	expiryBytes, err := wire.ReadVarBytes(r, 0, 300, "expiry")
+	switch {
+	case err == io.EOF:
+		return withLegacyAmtPaid(invoice), nil
+	case err != nil:
+		return nil, err
+	case len(expiryBytes) != 0:
+		err := invoice.ExpiryDate.UnmarshalBinary(expiryBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Invoices written before hold invoices were introduced are all
+	// regular invoices.
+	holdHash, err := wire.ReadVarBytes(r, 0, 32, "holdhash")
+	switch {
+	case err == io.EOF:
+		return withLegacyAmtPaid(invoice), nil
+	case err != nil:
+		return nil, err
+	case len(holdHash) == 32:
+		invoice.Terms.HoldHash = &[32]byte{}
+		copy(invoice.Terms.HoldHash[:], holdHash)
+	case len(holdHash) != 0:
+		return nil, fmt.Errorf("invalid hold hash length: %v",
+			len(holdHash))
+	}
+
+	// Invoices written before settle indexes were assigned lack one.
+	_, err = io.ReadFull(r, scratch[:])
+	switch {
+	case err == io.EOF:
+		return withLegacyAmtPaid(invoice), nil
+	case err != nil:
 		return nil, err
 	}
-	if settleByte[0] == 1 {
-		invoice.Terms.Settled = true
+	invoice.SettleIndex = byteOrder.Uint64(scratch[:])
+
+	// Invoices written before the amount paid was recorded, like those
+	// ending at any of the preceding fields, lack it.
+	_, err = io.ReadFull(r, scratch[:])
+	switch {
+	case err == io.EOF:
+		return withLegacyAmtPaid(invoice), nil
+	case err != nil:
+		return nil, err
+	}
+	invoice.AmtPaid = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	// Invoices written before custom records were stored lack any.
+	numRecords, err := wire.ReadVarInt(r, 0)
+	switch {
+	case err == io.EOF:
+		return invoice, nil
+	case err != nil:
+		return nil, err
+	case numRecords == 0:
+		return invoice, nil
+	}
+
+	invoice.CustomRecords = make(map[uint64][]byte)
+	for i := uint64(0); i < numRecords; i++ {
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		value, err := wire.ReadVarBytes(
+			r, 0, MaxCustomRecordSize, "record",
+		)
+		if err != nil {
+			return nil, err
+		}
+		invoice.CustomRecords[byteOrder.Uint64(scratch[:])] = value
 	}
 
 	return invoice, nil
 }
 
-func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
+// withLegacyAmtPaid fills in the amount paid to the passed invoice, which was
+// read from a record written before the amount paid was recorded. Such
+// invoices could only be settled by paying their exact value.
+func withLegacyAmtPaid(invoice *Invoice) *Invoice {
+	if invoice.Terms.State == ContractSettled ||
+		invoice.Terms.State == ContractAccepted {
+
+		invoice.AmtPaid = invoice.Terms.Value
+	}
+
+	return invoice
+}
+
+// updateInvoiceState transitions the invoice stored under the passed invoice
+// number into the target state, ensuring the transition is valid. The passed
+// amtPaid is added to the amount paid to the invoice, and the passed custom
+// records are added to those of the invoice.
+func updateInvoiceState(invoices *bolt.Bucket, invoiceNum []byte,
+	newState ContractState, preimage *[32]byte, amtPaid btcutil.Amount,
+	customRecords map[uint64][]byte) error {
+
 	invoice, err := fetchInvoice(invoiceNum, invoices)
 	if err != nil {
 		return err
 	}
 
-	invoice.Terms.Settled = true
+	switch invoice.Terms.State {
+	// Open invoices may transition into any other state. However, only
+	// hold invoices may be accepted, and they may only be settled once
+	// accepted.
+	case ContractOpen:
+		switch {
+		case newState == ContractAccepted && !invoice.IsHold():
+			return ErrInvoiceNotHold
+
+		case newState == ContractSettled && invoice.IsHold():
+			return ErrInvoiceNotAccepted
+		}
+
+	// Accepted hold invoices may only be settled using their preimage, or
+	// canceled.
+	case ContractAccepted:
+		switch newState {
+		case ContractSettled:
+			if preimage == nil {
+				return ErrInvoicePreimageRequired
+			}
+
+		case ContractCanceled:
+
+		default:
+			return fmt.Errorf("invalid transition of accepted "+
+				"invoice to %v", newState)
+		}
+
+	// As the preimage of a settled invoice has already been revealed, it
+	// may be paid several times, in which case settling it again only
+	// records the additional payment.
+	case ContractSettled:
+		if newState != ContractSettled {
+			return ErrInvoiceAlreadySettled
+		}
+		if amtPaid == 0 && len(customRecords) == 0 {
+			return nil
+		}
+		invoice.AmtPaid += amtPaid
+		addCustomRecords(invoice, customRecords)
+
+		return putSerializedInvoice(invoices, invoiceNum, invoice)
+
+	case ContractCanceled:
+		return ErrInvoiceAlreadyCanceled
+
+	case ContractExpired:
+		return ErrInvoiceExpired
+
+	default:
+		return fmt.Errorf("unknown invoice state: %v",
+			invoice.Terms.State)
+	}
+
+	if preimage != nil {
+		if !invoice.IsHold() {
+			return ErrInvoiceNotHold
+		}
+		invoice.Terms.PaymentPreimage = *preimage
+	}
+
+	// As the invoice is no longer open, it can't expire, so we'll remove
+	// it from the expiry index.
+	expiryIndex := invoices.Bucket(invoiceExpiryIndexBucket)
+	if invoice.Terms.State == ContractOpen && expiryIndex != nil &&
+		!invoice.ExpiryDate.IsZero() {
+
+		indexKey := timeIndexKey(invoice.ExpiryDate, invoiceNum)
+		if err := expiryIndex.Delete(indexKey); err != nil {
+			return err
+		}
+	}
+	invoice.Terms.State = newState
+	invoice.AmtPaid += amtPaid
+	addCustomRecords(invoice, customRecords)
+
+	// Settled invoices are assigned the next settle index, and added to
+	// the settle index.
+	if newState == ContractSettled {
+		settleIndex, err := invoices.CreateBucketIfNotExists(
+			settleIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		invoice.SettleIndex = 1
+		if lastIndex, _ := settleIndex.Cursor().Last(); lastIndex != nil {
+			invoice.SettleIndex = byteOrder.Uint64(lastIndex) + 1
+		}
+
+		var indexKey [8]byte
+		byteOrder.PutUint64(indexKey[:], invoice.SettleIndex)
+		invoiceKey := make([]byte, len(invoiceNum))
+		copy(invoiceKey, invoiceNum)
+		if err := settleIndex.Put(indexKey[:], invoiceKey); err != nil {
+			return err
+		}
+	}
+
+	return putSerializedInvoice(invoices, invoiceNum, invoice)
+}
+
+// addCustomRecords adds the passed custom records to those of the invoice.
+// Existing records of the same type are kept, so that the records delivered by
+// the first payment of the invoice can't be overwritten by later payments.
+func addCustomRecords(invoice *Invoice, customRecords map[uint64][]byte) {
+	if len(customRecords) == 0 {
+		return
+	}
+
+	if invoice.CustomRecords == nil {
+		invoice.CustomRecords = make(map[uint64][]byte)
+	}
+	for recordType, value := range customRecords {
+		if _, ok := invoice.CustomRecords[recordType]; ok {
+			continue
+		}
+		invoice.CustomRecords[recordType] = value
+	}
+}
+
+// putSerializedInvoice overwrites the invoice stored under the passed invoice
+// number with the serialization of the passed invoice.
+func putSerializedInvoice(invoices *bolt.Bucket, invoiceNum []byte,
+	invoice *Invoice) error {
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, invoice); err != nil {
-		return nil
+		return err
 	}
 
 	return invoices.Put(invoiceNum[:], buf.Bytes())
@@ -2,6 +2,7 @@ package channeldb
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/roasbeef/btcd/wire"
@@ -96,3 +97,148 @@ func deliveryScriptBugMigration(tx *bolt.Tx) error {
 		return nodeChanBucket.Delete(deliveryScriptsKey)
 	})
 }
+
+// invoiceExpiryIndexMigration is a database migration that populates the
+// expiry index of the invoice bucket with all open invoices which expire. As
+// of database version 2, invoices are expired by scanning the expiry index,
+// rather than every invoice within the database.
+func invoiceExpiryIndexMigration(tx *bolt.Tx) error {
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices == nil {
+		return nil
+	}
+
+	log.Infof("Migrating database to index open invoices by expiry date")
+
+	expiryIndex, err := invoices.CreateBucketIfNotExists(
+		invoiceExpiryIndexBucket,
+	)
+	if err != nil {
+		return err
+	}
+
+	// As the bucket can't be modified while iterating over it, we'll
+	// first gather the index keys of all open invoices which expire.
+	var indexKeys [][]byte
+	err = invoices.ForEach(func(k, v []byte) error {
+		// Sub-buckets, such as the expiry index itself, have a nil
+		// value, and aren't invoices.
+		if v == nil {
+			return nil
+		}
+
+		invoice, err := decodeInvoice(k, v)
+		if err != nil {
+			return err
+		}
+		if invoice.Terms.State != ContractOpen ||
+			invoice.ExpiryDate.IsZero() {
+
+			return nil
+		}
+
+		indexKeys = append(
+			indexKeys, timeIndexKey(invoice.ExpiryDate, k),
+		)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, indexKey := range indexKeys {
+		if err := expiryIndex.Put(indexKey, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// creationDateIndexMigration is a database migration that populates the
+// creation index of the invoice and payment buckets with all existing invoices
+// and payments. As of database version 3, queries filtering invoices or
+// payments by their creation date only read those created within the range.
+func creationDateIndexMigration(tx *bolt.Tx) error {
+	log.Infof("Migrating database to index invoices and payments by " +
+		"creation date")
+
+	invoices := tx.Bucket(invoiceBucket)
+	if invoices != nil {
+		err := indexCreationDates(
+			invoices, invoiceCreationIndexBucket,
+			func(k, v []byte) (time.Time, error) {
+				invoice, err := decodeInvoice(k, v)
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return invoice.CreationDate, nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	payments := tx.Bucket(paymentBucket)
+	if payments != nil {
+		err := indexCreationDates(
+			payments, paymentCreationIndexBucket,
+			func(k, v []byte) (time.Time, error) {
+				payment, err := decodeOutgoingPayment(k, v)
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return payment.CreationDate, nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexCreationDates adds each record of the passed bucket to the creation
+// index of the bucket with the given name, using creationDate to decode the
+// creation date of each record.
+func indexCreationDates(bucket *bolt.Bucket, indexName []byte,
+	creationDate func(k, v []byte) (time.Time, error)) error {
+
+	creationIndex, err := bucket.CreateBucketIfNotExists(indexName)
+	if err != nil {
+		return err
+	}
+
+	// As the bucket can't be modified while iterating over it, we'll
+	// first gather the index keys of all records.
+	var indexKeys [][]byte
+	err = bucket.ForEach(func(k, v []byte) error {
+		// Sub-buckets have a nil value, and aren't records.
+		if v == nil {
+			return nil
+		}
+
+		date, err := creationDate(k, v)
+		if err != nil {
+			return err
+		}
+
+		indexKeys = append(indexKeys, timeIndexKey(date, k))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, indexKey := range indexKeys {
+		if err := creationIndex.Put(indexKey, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// missionControlBucket is the name of the bucket within the database
+	// that stores the payment history of the router's mission control.
+	//
+	// Within the mission control bucket, the history of each directed node
+	// pair is keyed by the concatenation of the compressed public keys of
+	// the source and destination nodes of the pair: from || to.
+	missionControlBucket = []byte("mission-control")
+)
+
+// MissionControlPair houses the most recent payment outcomes observed when
+// attempting to forward a payment from one node to another. A pair is
+// directed, so the history of (A, B) is distinct from that of (B, A).
+type MissionControlPair struct {
+	// From is the compressed public key of the node which forwards the
+	// payment.
+	From [33]byte
+
+	// To is the compressed public key of the node the payment is
+	// forwarded to.
+	To [33]byte
+
+	// LastFailTime is the time of the most recent failed attempt to route
+	// a payment over this pair. A zero time indicates that no failure has
+	// been recorded.
+	LastFailTime time.Time
+
+	// LastFailAmt is the amount of the most recent failed attempt.
+	LastFailAmt btcutil.Amount
+
+	// LastSuccessTime is the time of the most recent successful attempt
+	// to route a payment over this pair. A zero time indicates that no
+	// success has been recorded.
+	LastSuccessTime time.Time
+
+	// LastSuccessAmt is the amount of the most recent successful attempt.
+	LastSuccessAmt btcutil.Amount
+}
+
+// PutMissionControlPair writes the latest payment history of the target node
+// pair to the database, overwriting any prior history of the pair.
+func (db *DB) PutMissionControlPair(pair *MissionControlPair) error {
+	var b bytes.Buffer
+	if err := serializeMissionControlPair(&b, pair); err != nil {
+		return err
+	}
+
+	var key [66]byte
+	copy(key[:33], pair.From[:])
+	copy(key[33:], pair.To[:])
+
+	return db.Update(func(tx *bolt.Tx) error {
+		pairs, err := tx.CreateBucketIfNotExists(missionControlBucket)
+		if err != nil {
+			return err
+		}
+
+		return pairs.Put(key[:], b.Bytes())
+	})
+}
+
+// FetchMissionControlPairs returns the payment history of all node pairs
+// stored within the database.
+func (db *DB) FetchMissionControlPairs() ([]*MissionControlPair, error) {
+	var pairs []*MissionControlPair
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(missionControlBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			// If the key isn't the length of a node pair, then
+			// we'll skip it as it isn't a pair entry.
+			if len(k) != 66 || v == nil {
+				return nil
+			}
+
+			pair, err := deserializeMissionControlPair(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			copy(pair.From[:], k[:33])
+			copy(pair.To[:], k[33:])
+
+			pairs = append(pairs, pair)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+// ResetMissionControl deletes the payment history of all node pairs from the
+// database.
+func (db *DB) ResetMissionControl() error {
+	return db.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket(missionControlBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		_, err = tx.CreateBucket(missionControlBucket)
+		return err
+	})
+}
+
+// serializeMissionControlPair writes the outcome history of the pair to the
+// passed io.Writer. The pair's node keys aren't included as they're used as
+// the key of the entry.
+func serializeMissionControlPair(w io.Writer, p *MissionControlPair) error {
+	var scratch [8]byte
+
+	writeTime := func(t time.Time) error {
+		var unixNano int64
+		if !t.IsZero() {
+			unixNano = t.UnixNano()
+		}
+		byteOrder.PutUint64(scratch[:], uint64(unixNano))
+		_, err := w.Write(scratch[:])
+		return err
+	}
+	writeAmt := func(a btcutil.Amount) error {
+		byteOrder.PutUint64(scratch[:], uint64(a))
+		_, err := w.Write(scratch[:])
+		return err
+	}
+
+	if err := writeTime(p.LastFailTime); err != nil {
+		return err
+	}
+	if err := writeAmt(p.LastFailAmt); err != nil {
+		return err
+	}
+	if err := writeTime(p.LastSuccessTime); err != nil {
+		return err
+	}
+	return writeAmt(p.LastSuccessAmt)
+}
+
+// deserializeMissionControlPair reads the outcome history of a pair from the
+// passed io.Reader.
+func deserializeMissionControlPair(r io.Reader) (*MissionControlPair, error) {
+	var scratch [8]byte
+
+	readUint64 := func() (uint64, error) {
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return 0, err
+		}
+		return byteOrder.Uint64(scratch[:]), nil
+	}
+	readTime := func() (time.Time, error) {
+		unixNano, err := readUint64()
+		if err != nil || unixNano == 0 {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(unixNano)), nil
+	}
+
+	p := &MissionControlPair{}
+
+	var err error
+	if p.LastFailTime, err = readTime(); err != nil {
+		return nil, err
+	}
+	failAmt, err := readUint64()
+	if err != nil {
+		return nil, err
+	}
+	p.LastFailAmt = btcutil.Amount(failAmt)
+
+	if p.LastSuccessTime, err = readTime(); err != nil {
+		return nil, err
+	}
+	successAmt, err := readUint64()
+	if err != nil {
+		return nil, err
+	}
+	p.LastSuccessAmt = btcutil.Amount(successAmt)
+
+	return p, nil
+}
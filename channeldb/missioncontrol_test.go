@@ -0,0 +1,74 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func TestMissionControlPairStorage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// With no history written, fetching the pairs should return an empty
+	// set rather than an error.
+	pairs, err := db.FetchMissionControlPairs()
+	if err != nil {
+		t.Fatalf("unable to fetch pairs: %v", err)
+	}
+	if len(pairs) != 0 {
+		t.Fatalf("expected no pairs, instead have %v", len(pairs))
+	}
+
+	failedPair := &MissionControlPair{
+		LastFailTime: time.Unix(1000, 0),
+		LastFailAmt:  1000,
+	}
+	copy(failedPair.From[:], bytes.Repeat([]byte{1}, 33))
+	copy(failedPair.To[:], bytes.Repeat([]byte{2}, 33))
+
+	successPair := &MissionControlPair{
+		LastFailTime:    time.Unix(1000, 0),
+		LastFailAmt:     5000,
+		LastSuccessTime: time.Unix(2000, 0),
+		LastSuccessAmt:  2000,
+	}
+	copy(successPair.From[:], bytes.Repeat([]byte{2}, 33))
+	copy(successPair.To[:], bytes.Repeat([]byte{1}, 33))
+
+	for _, pair := range []*MissionControlPair{failedPair, successPair} {
+		if err := db.PutMissionControlPair(pair); err != nil {
+			t.Fatalf("unable to put pair: %v", err)
+		}
+	}
+
+	// Both pairs should be returned, ordered by their key, with all fields
+	// intact.
+	pairs, err = db.FetchMissionControlPairs()
+	if err != nil {
+		t.Fatalf("unable to fetch pairs: %v", err)
+	}
+	expected := []*MissionControlPair{failedPair, successPair}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Fatalf("pairs don't match: expected %v, got %v",
+			spew.Sdump(expected), spew.Sdump(pairs))
+	}
+
+	// Finally, after a reset, no pairs should remain.
+	if err := db.ResetMissionControl(); err != nil {
+		t.Fatalf("unable to reset mission control: %v", err)
+	}
+	pairs, err = db.FetchMissionControlPairs()
+	if err != nil {
+		t.Fatalf("unable to fetch pairs: %v", err)
+	}
+	if len(pairs) != 0 {
+		t.Fatalf("expected no pairs, instead have %v", len(pairs))
+	}
+}
@@ -0,0 +1,153 @@
+package channeldb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// paginate iterates over the records of a bucket whose keys are big-endian
+// encoded indexes of length keyLen, calling visit for each record in index
+// order. Iteration starts at the record following startKey, or at the first
+// record if startKey is nil. If reversed is true, then the records are instead
+// visited in descending order, starting at the record preceding startKey, or
+// the last record if startKey is nil. Any nested buckets, or keys of a
+// different length, are skipped. Iteration stops once visit returns false.
+func paginate(c *bolt.Cursor, startKey []byte, keyLen int, reversed bool,
+	visit func(k, v []byte) (bool, error)) error {
+
+	var k, v []byte
+	switch {
+	case startKey == nil && !reversed:
+		k, v = c.First()
+
+	case startKey == nil && reversed:
+		k, v = c.Last()
+
+	// Seek positions the cursor at the first key which is equal to or
+	// greater than the start key. As the start key itself is excluded,
+	// we'll step past it if it exists.
+	case !reversed:
+		k, v = c.Seek(startKey)
+		if k != nil && bytes.Equal(k, startKey) {
+			k, v = c.Next()
+		}
+
+	// When iterating in reverse, the record preceding the key found by
+	// Seek is the first with a lower index. If no key is found, then all
+	// keys are lower than the start key, so we begin at the last key.
+	default:
+		k, v = c.Seek(startKey)
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	}
+
+	next := c.Next
+	if reversed {
+		next = c.Prev
+	}
+
+	for ; k != nil; k, v = next() {
+		if v == nil || len(k) != keyLen {
+			continue
+		}
+
+		cont, err := visit(k, v)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// timeIndexKey returns the key of the record with the passed ID within an
+// index ordered by time. Each key is the time as a big-endian unix timestamp,
+// followed by the ID. Times preceding the unix epoch are indexed as the epoch.
+func timeIndexKey(t time.Time, id []byte) []byte {
+	unix := t.Unix()
+	if unix < 0 {
+		unix = 0
+	}
+
+	key := make([]byte, 8+len(id))
+	byteOrder.PutUint64(key[:8], uint64(unix))
+	copy(key[8:], id)
+
+	return key
+}
+
+// timeRange is the set of IDs of the records within a time index whose time
+// lies within a range of times.
+type timeRange struct {
+	ids map[string]struct{}
+
+	// first and last are the lowest and highest IDs within the range.
+	first []byte
+	last  []byte
+}
+
+// fetchTimeRange returns the IDs of the records within the passed time index
+// whose time lies between start and end inclusive. A zero start or end time
+// leaves the range unbounded in that direction. As the index only has second
+// resolution, records at the edges of the range may lie just outside of it,
+// so they must still be filtered by their exact time.
+func fetchTimeRange(index *bolt.Bucket, start, end time.Time) *timeRange {
+	r := &timeRange{
+		ids: make(map[string]struct{}),
+	}
+
+	c := index.Cursor()
+
+	var k []byte
+	if start.IsZero() {
+		k, _ = c.First()
+	} else {
+		k, _ = c.Seek(timeIndexKey(start, nil))
+	}
+
+	endKey := timeIndexKey(end, nil)
+	for ; k != nil; k, _ = c.Next() {
+		if !end.IsZero() && bytes.Compare(k[:8], endKey) > 0 {
+			break
+		}
+
+		id := k[8:]
+		r.ids[string(id)] = struct{}{}
+
+		if r.first == nil || bytes.Compare(id, r.first) < 0 {
+			r.first = id
+		}
+		if r.last == nil || bytes.Compare(id, r.last) > 0 {
+			r.last = id
+		}
+	}
+
+	return r
+}
+
+// filter returns whether the record with the passed ID lies within the range,
+// along with whether a traversal of the records in ascending, or if reversed
+// is true descending, ID order may find any further records within it.
+func (r *timeRange) filter(id []byte, reversed bool) (bool, bool) {
+	switch {
+	case len(r.ids) == 0:
+		return false, false
+
+	case !reversed && bytes.Compare(id, r.last) > 0:
+		return false, false
+
+	case reversed && bytes.Compare(id, r.first) < 0:
+		return false, false
+	}
+
+	_, ok := r.ids[string(id)]
+	return ok, true
+}
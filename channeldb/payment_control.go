@@ -0,0 +1,792 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// paymentControlBucket is the name of the bucket within the database
+	// that stores the state of all outgoing payments tracked by the
+	// payment control tower.
+	//
+	// Within the payment control bucket, each payment has its own
+	// sub-bucket keyed by its payment hash. The sub-bucket stores the
+	// payment's status, creation info, preimage or failure reason, and a
+	// nested bucket of attempts keyed by their monotonically increasing
+	// attempt ID.
+	paymentControlBucket = []byte("payment-control")
+
+	paymentStatusKey   = []byte("status")
+	paymentInfoKey     = []byte("info")
+	paymentPreimageKey = []byte("preimage")
+	paymentFailureKey  = []byte("failure")
+	paymentAttemptsKey = []byte("attempts")
+)
+
+// MaxPaymentFailureSize is the maximum size of the failure description stored
+// for a payment attempt.
+const MaxPaymentFailureSize = 1024
+
+// PaymentStatus represents the current state of an outgoing payment tracked
+// by the payment control tower.
+type PaymentStatus byte
+
+const (
+	// StatusUnknown is the status of a payment which has never been
+	// initiated.
+	StatusUnknown PaymentStatus = 0
+
+	// StatusInitiated is the status of a payment which has been
+	// initiated, but for which no HTLC has yet been sent.
+	StatusInitiated PaymentStatus = 1
+
+	// StatusInFlight is the status of a payment for which at least one
+	// HTLC has been sent, and which hasn't yet been resolved.
+	StatusInFlight PaymentStatus = 2
+
+	// StatusSucceeded is the status of a payment for which an HTLC has
+	// been settled, revealing the preimage.
+	StatusSucceeded PaymentStatus = 3
+
+	// StatusFailed is the status of a payment which has failed, and has no
+	// HTLCs remaining in flight. A failed payment may be initiated again.
+	StatusFailed PaymentStatus = 4
+)
+
+// String returns a human readable version of the payment status.
+func (s PaymentStatus) String() string {
+	switch s {
+	case StatusUnknown:
+		return "Unknown"
+	case StatusInitiated:
+		return "Initiated"
+	case StatusInFlight:
+		return "In Flight"
+	case StatusSucceeded:
+		return "Succeeded"
+	case StatusFailed:
+		return "Failed"
+	default:
+		return "Invalid"
+	}
+}
+
+// AttemptState represents the current state of a single HTLC sent as part of
+// an outgoing payment.
+type AttemptState byte
+
+const (
+	// AttemptInFlight is the state of an attempt whose HTLC hasn't yet
+	// been resolved.
+	AttemptInFlight AttemptState = 0
+
+	// AttemptSettled is the state of an attempt whose HTLC has been
+	// settled by the destination.
+	AttemptSettled AttemptState = 1
+
+	// AttemptFailed is the state of an attempt whose HTLC has been
+	// cancelled, or which couldn't be sent at all.
+	AttemptFailed AttemptState = 2
+)
+
+// PaymentCreationInfo houses the details of an outgoing payment known at the
+// time it's initiated.
+type PaymentCreationInfo struct {
+	// PaymentHash is the payment hash of the payment, which uniquely
+	// identifies it.
+	PaymentHash [32]byte
+
+	// Value is the amount to be delivered to the destination.
+	Value btcutil.Amount
+
+	// CreationDate is the time the payment was initiated.
+	CreationDate time.Time
+
+	// PaymentRequest is the encoded payment request the payment pays, if
+	// any.
+	PaymentRequest []byte
+}
+
+// AttemptHop is a single hop within the route of a payment attempt.
+type AttemptHop struct {
+	// PubKey is the compressed public key of the node the hop leads to.
+	PubKey [33]byte
+
+	// ChannelID is the short channel ID of the channel the hop traverses.
+	ChannelID uint64
+
+	// AmtToForward is the amount the node at the end of the hop is to
+	// forward.
+	AmtToForward btcutil.Amount
+
+	// Fee is the fee charged by the node at the start of the hop.
+	Fee btcutil.Amount
+}
+
+// PaymentAttempt records a single HTLC sent as part of an outgoing payment,
+// along with the route it took, and its outcome.
+type PaymentAttempt struct {
+	// AttemptID is the unique identifier of the attempt within the
+	// payment. It's assigned when the attempt is registered.
+	AttemptID uint64
+
+	// TotalAmount is the amount of the HTLC extended to the first hop,
+	// including all fees.
+	TotalAmount btcutil.Amount
+
+	// TotalFees is the total fee paid to the route's intermediate nodes.
+	TotalFees btcutil.Amount
+
+	// TotalTimeLock is the total time lock of the route.
+	TotalTimeLock uint32
+
+	// Hops is the route the attempt's HTLC was sent over.
+	Hops []AttemptHop
+
+	// AttemptTime is the time the HTLC was sent.
+	AttemptTime time.Time
+
+	// State is the current state of the attempt.
+	State AttemptState
+
+	// ResolveTime is the time the attempt was either settled or failed. A
+	// zero time indicates the attempt is still in flight.
+	ResolveTime time.Time
+
+	// Failure describes why the attempt failed, if it did.
+	Failure string
+}
+
+// TrackedPayment is the full state of an outgoing payment as recorded by the
+// payment control tower.
+type TrackedPayment struct {
+	// Info is the creation info of the payment.
+	Info PaymentCreationInfo
+
+	// Status is the current status of the payment.
+	Status PaymentStatus
+
+	// Attempts is the set of HTLCs sent as part of the payment, ordered by
+	// their attempt ID.
+	Attempts []*PaymentAttempt
+
+	// Preimage is the preimage revealed by the destination. It's only set
+	// once the payment has succeeded.
+	Preimage [32]byte
+
+	// FailureReason describes why the payment failed, if it did.
+	FailureReason string
+}
+
+// InFlightAttempts returns the attempts of the payment which are still in
+// flight.
+func (p *TrackedPayment) InFlightAttempts() []*PaymentAttempt {
+	var inFlight []*PaymentAttempt
+	for _, attempt := range p.Attempts {
+		if attempt.State == AttemptInFlight {
+			inFlight = append(inFlight, attempt)
+		}
+	}
+
+	return inFlight
+}
+
+// PaymentControl is the control tower for outgoing payments. It persists the
+// state of each payment as it transitions from initiated, to in flight, to
+// either succeeded or failed, along with each of the HTLCs sent on its
+// behalf. As each transition is carried out within a single database
+// transaction, the control tower is able to guarantee that a payment hash is
+// never paid twice, even across restarts.
+type PaymentControl struct {
+	db *DB
+}
+
+// NewPaymentControl creates a new payment control tower backed by the passed
+// database.
+func NewPaymentControl(db *DB) *PaymentControl {
+	return &PaymentControl{
+		db: db,
+	}
+}
+
+// InitPayment records the intent to send the described payment. An error is
+// returned if a payment to the same payment hash is already in flight, or
+// has already succeeded. A payment which previously failed is reset, allowing
+// it to be attempted anew.
+func (p *PaymentControl) InitPayment(info *PaymentCreationInfo) error {
+	var b bytes.Buffer
+	if err := serializePaymentCreationInfo(&b, info); err != nil {
+		return err
+	}
+
+	return p.db.Update(func(tx *bolt.Tx) error {
+		payments, err := tx.CreateBucketIfNotExists(paymentControlBucket)
+		if err != nil {
+			return err
+		}
+
+		switch fetchPaymentStatus(payments, info.PaymentHash[:]) {
+		case StatusInitiated, StatusInFlight:
+			return ErrPaymentInFlight
+
+		case StatusSucceeded:
+			return ErrAlreadyPaid
+
+		// A failed payment is wiped entirely, such that none of its
+		// prior attempts linger within the new payment.
+		case StatusFailed:
+			err := payments.DeleteBucket(info.PaymentHash[:])
+			if err != nil {
+				return err
+			}
+		}
+
+		payment, err := payments.CreateBucket(info.PaymentHash[:])
+		if err != nil {
+			return err
+		}
+		if err := payment.Put(paymentInfoKey, b.Bytes()); err != nil {
+			return err
+		}
+
+		return putPaymentStatus(payment, StatusInitiated)
+	})
+}
+
+// RegisterAttempt records that an HTLC is about to be sent over the route of
+// the passed attempt, transitioning the payment to in flight. The ID assigned
+// to the attempt is returned, and is also set on the passed attempt.
+func (p *PaymentControl) RegisterAttempt(paymentHash [32]byte,
+	attempt *PaymentAttempt) (uint64, error) {
+
+	err := p.db.Update(func(tx *bolt.Tx) error {
+		payment, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		switch fetchPaymentStatus(payment, nil) {
+		case StatusSucceeded:
+			return ErrAlreadyPaid
+		case StatusFailed:
+			return ErrPaymentAlreadyFailed
+		}
+
+		attempts, err := payment.CreateBucketIfNotExists(
+			paymentAttemptsKey,
+		)
+		if err != nil {
+			return err
+		}
+		attemptID, err := attempts.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		attempt.AttemptID = attemptID
+		attempt.State = AttemptInFlight
+		attempt.ResolveTime = time.Time{}
+		attempt.Failure = ""
+		if err := putPaymentAttempt(attempts, attempt); err != nil {
+			return err
+		}
+
+		return putPaymentStatus(payment, StatusInFlight)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt.AttemptID, nil
+}
+
+// SettleAttempt marks the target attempt as settled, transitioning the
+// payment to succeeded and storing the revealed preimage. Other attempts of
+// the payment may remain in flight, and may still be resolved afterwards.
+func (p *PaymentControl) SettleAttempt(paymentHash [32]byte, attemptID uint64,
+	preimage [32]byte) error {
+
+	return p.db.Update(func(tx *bolt.Tx) error {
+		payment, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		err = resolvePaymentAttempt(payment, attemptID, AttemptSettled,
+			"")
+		if err != nil {
+			return err
+		}
+
+		if err := payment.Put(paymentPreimageKey, preimage[:]); err != nil {
+			return err
+		}
+
+		return putPaymentStatus(payment, StatusSucceeded)
+	})
+}
+
+// FailAttempt marks the target attempt as failed for the described reason.
+// The status of the payment itself is left untouched, as further attempts may
+// follow.
+func (p *PaymentControl) FailAttempt(paymentHash [32]byte, attemptID uint64,
+	failure string) error {
+
+	return p.db.Update(func(tx *bolt.Tx) error {
+		payment, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		return resolvePaymentAttempt(payment, attemptID, AttemptFailed,
+			failure)
+	})
+}
+
+// Fail transitions the payment to failed for the described reason. A payment
+// can only fail once none of its attempts remain in flight, and it hasn't
+// already succeeded.
+func (p *PaymentControl) Fail(paymentHash [32]byte, reason string) error {
+	return p.db.Update(func(tx *bolt.Tx) error {
+		payment, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		switch fetchPaymentStatus(payment, nil) {
+		case StatusSucceeded:
+			return ErrAlreadyPaid
+		case StatusFailed:
+			return ErrPaymentAlreadyFailed
+		}
+
+		attempts, err := fetchPaymentAttempts(payment)
+		if err != nil {
+			return err
+		}
+		for _, attempt := range attempts {
+			if attempt.State == AttemptInFlight {
+				return ErrPaymentAttemptsInFlight
+			}
+		}
+
+		err = payment.Put(paymentFailureKey, []byte(reason))
+		if err != nil {
+			return err
+		}
+
+		return putPaymentStatus(payment, StatusFailed)
+	})
+}
+
+// FetchPayment returns the full state of the payment with the target payment
+// hash. ErrPaymentNotInitiated is returned if no such payment exists.
+func (p *PaymentControl) FetchPayment(paymentHash [32]byte) (*TrackedPayment,
+	error) {
+
+	var payment *TrackedPayment
+	err := p.db.View(func(tx *bolt.Tx) error {
+		bucket, err := fetchPaymentBucket(tx, paymentHash)
+		if err != nil {
+			return err
+		}
+
+		payment, err = fetchTrackedPayment(bucket, paymentHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// FetchInFlightPayments returns all payments which are either initiated or in
+// flight. After a restart, these are the payments whose outcome is still to
+// be determined.
+func (p *PaymentControl) FetchInFlightPayments() ([]*TrackedPayment, error) {
+	var inFlight []*TrackedPayment
+	err := p.db.View(func(tx *bolt.Tx) error {
+		payments := tx.Bucket(paymentControlBucket)
+		if payments == nil {
+			return nil
+		}
+
+		return payments.ForEach(func(k, v []byte) error {
+			// Each payment is stored within its own sub-bucket, so
+			// any other keys are skipped.
+			if v != nil || len(k) != 32 {
+				return nil
+			}
+			bucket := payments.Bucket(k)
+
+			status := fetchPaymentStatus(bucket, nil)
+			if status != StatusInitiated && status != StatusInFlight {
+				return nil
+			}
+
+			var paymentHash [32]byte
+			copy(paymentHash[:], k)
+
+			payment, err := fetchTrackedPayment(bucket, paymentHash)
+			if err != nil {
+				return err
+			}
+
+			inFlight = append(inFlight, payment)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inFlight, nil
+}
+
+// fetchPaymentBucket returns the sub-bucket of the payment with the target
+// payment hash.
+func fetchPaymentBucket(tx *bolt.Tx, paymentHash [32]byte) (*bolt.Bucket,
+	error) {
+
+	payments := tx.Bucket(paymentControlBucket)
+	if payments == nil {
+		return nil, ErrPaymentNotInitiated
+	}
+
+	payment := payments.Bucket(paymentHash[:])
+	if payment == nil {
+		return nil, ErrPaymentNotInitiated
+	}
+
+	return payment, nil
+}
+
+// fetchPaymentStatus returns the status of the payment stored within the
+// passed bucket. If paymentHash is non-nil, then the bucket is the top-level
+// payment control bucket, and the status of the payment with that hash is
+// returned instead. StatusUnknown is returned if the payment doesn't exist.
+func fetchPaymentStatus(bucket *bolt.Bucket, paymentHash []byte) PaymentStatus {
+	if paymentHash != nil {
+		bucket = bucket.Bucket(paymentHash)
+		if bucket == nil {
+			return StatusUnknown
+		}
+	}
+
+	status := bucket.Get(paymentStatusKey)
+	if len(status) != 1 {
+		return StatusUnknown
+	}
+
+	return PaymentStatus(status[0])
+}
+
+// putPaymentStatus writes the status of the payment stored within the passed
+// bucket.
+func putPaymentStatus(payment *bolt.Bucket, status PaymentStatus) error {
+	return payment.Put(paymentStatusKey, []byte{byte(status)})
+}
+
+// fetchTrackedPayment reads the full state of the payment with the passed
+// payment hash stored within the bucket.
+func fetchTrackedPayment(bucket *bolt.Bucket,
+	paymentHash [32]byte) (*TrackedPayment, error) {
+
+	payment := &TrackedPayment{
+		Status: fetchPaymentStatus(bucket, nil),
+	}
+
+	info, err := deserializePaymentCreationInfo(
+		bytes.NewReader(bucket.Get(paymentInfoKey)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	payment.Info = *info
+	payment.Info.PaymentHash = paymentHash
+
+	payment.Attempts, err = fetchPaymentAttempts(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(payment.Preimage[:], bucket.Get(paymentPreimageKey))
+	payment.FailureReason = string(bucket.Get(paymentFailureKey))
+
+	return payment, nil
+}
+
+// fetchPaymentAttempts reads all attempts of the payment stored within the
+// passed bucket, ordered by their attempt ID.
+func fetchPaymentAttempts(payment *bolt.Bucket) ([]*PaymentAttempt, error) {
+	attempts := payment.Bucket(paymentAttemptsKey)
+	if attempts == nil {
+		return nil, nil
+	}
+
+	var paymentAttempts []*PaymentAttempt
+	err := attempts.ForEach(func(k, v []byte) error {
+		attempt, err := deserializePaymentAttempt(bytes.NewReader(v))
+		if err != nil {
+			return err
+		}
+		attempt.AttemptID = binary.BigEndian.Uint64(k)
+
+		paymentAttempts = append(paymentAttempts, attempt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paymentAttempts, nil
+}
+
+// putPaymentAttempt writes the passed attempt to the attempts bucket, keyed
+// by its attempt ID.
+func putPaymentAttempt(attempts *bolt.Bucket, attempt *PaymentAttempt) error {
+	var b bytes.Buffer
+	if err := serializePaymentAttempt(&b, attempt); err != nil {
+		return err
+	}
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], attempt.AttemptID)
+
+	return attempts.Put(key[:], b.Bytes())
+}
+
+// resolvePaymentAttempt transitions the target in flight attempt of the
+// payment stored within the passed bucket to its final state.
+func resolvePaymentAttempt(payment *bolt.Bucket, attemptID uint64,
+	state AttemptState, failure string) error {
+
+	attempts := payment.Bucket(paymentAttemptsKey)
+	if attempts == nil {
+		return ErrPaymentAttemptNotFound
+	}
+
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], attemptID)
+
+	attemptBytes := attempts.Get(key[:])
+	if attemptBytes == nil {
+		return ErrPaymentAttemptNotFound
+	}
+	attempt, err := deserializePaymentAttempt(bytes.NewReader(attemptBytes))
+	if err != nil {
+		return err
+	}
+	attempt.AttemptID = attemptID
+
+	if attempt.State != AttemptInFlight {
+		return ErrPaymentAttemptResolved
+	}
+
+	// Overly long failure descriptions are truncated, such that the
+	// attempt can always be read back.
+	if len(failure) > MaxPaymentFailureSize {
+		failure = failure[:MaxPaymentFailureSize]
+	}
+
+	attempt.State = state
+	attempt.ResolveTime = time.Now()
+	attempt.Failure = failure
+
+	return putPaymentAttempt(attempts, attempt)
+}
+
+// serializePaymentCreationInfo writes the creation info to the passed
+// io.Writer. The payment hash isn't included as it's used as the key of the
+// payment's bucket.
+func serializePaymentCreationInfo(w io.Writer, c *PaymentCreationInfo) error {
+	var scratch [8]byte
+
+	byteOrder.PutUint64(scratch[:], uint64(c.Value))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(c.CreationDate.UnixNano()))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, c.PaymentRequest)
+}
+
+func deserializePaymentCreationInfo(r io.Reader) (*PaymentCreationInfo, error) {
+	var scratch [8]byte
+
+	c := &PaymentCreationInfo{}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	c.Value = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	c.CreationDate = time.Unix(0, int64(byteOrder.Uint64(scratch[:])))
+
+	payReq, err := wire.ReadVarBytes(r, 0, MaxPaymentRequestSize, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(payReq) != 0 {
+		c.PaymentRequest = payReq
+	}
+
+	return c, nil
+}
+
+func serializePaymentAttempt(w io.Writer, a *PaymentAttempt) error {
+	var scratch [8]byte
+
+	writeUint64 := func(v uint64) error {
+		byteOrder.PutUint64(scratch[:], v)
+		_, err := w.Write(scratch[:])
+		return err
+	}
+	writeTime := func(t time.Time) error {
+		var unixNano int64
+		if !t.IsZero() {
+			unixNano = t.UnixNano()
+		}
+		return writeUint64(uint64(unixNano))
+	}
+
+	if err := writeUint64(uint64(a.TotalAmount)); err != nil {
+		return err
+	}
+	if err := writeUint64(uint64(a.TotalFees)); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], a.TotalTimeLock)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint16(scratch[:2], uint16(len(a.Hops)))
+	if _, err := w.Write(scratch[:2]); err != nil {
+		return err
+	}
+	for _, hop := range a.Hops {
+		if _, err := w.Write(hop.PubKey[:]); err != nil {
+			return err
+		}
+		if err := writeUint64(hop.ChannelID); err != nil {
+			return err
+		}
+		if err := writeUint64(uint64(hop.AmtToForward)); err != nil {
+			return err
+		}
+		if err := writeUint64(uint64(hop.Fee)); err != nil {
+			return err
+		}
+	}
+
+	if err := writeTime(a.AttemptTime); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{byte(a.State)}); err != nil {
+		return err
+	}
+	if err := writeTime(a.ResolveTime); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, []byte(a.Failure))
+}
+
+func deserializePaymentAttempt(r io.Reader) (*PaymentAttempt, error) {
+	var scratch [8]byte
+
+	readUint64 := func() (uint64, error) {
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return 0, err
+		}
+		return byteOrder.Uint64(scratch[:]), nil
+	}
+	readTime := func() (time.Time, error) {
+		unixNano, err := readUint64()
+		if err != nil || unixNano == 0 {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(unixNano)), nil
+	}
+
+	a := &PaymentAttempt{}
+
+	totalAmt, err := readUint64()
+	if err != nil {
+		return nil, err
+	}
+	a.TotalAmount = btcutil.Amount(totalAmt)
+
+	totalFees, err := readUint64()
+	if err != nil {
+		return nil, err
+	}
+	a.TotalFees = btcutil.Amount(totalFees)
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	a.TotalTimeLock = byteOrder.Uint32(scratch[:4])
+
+	if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+		return nil, err
+	}
+	numHops := byteOrder.Uint16(scratch[:2])
+
+	a.Hops = make([]AttemptHop, numHops)
+	for i := range a.Hops {
+		hop := &a.Hops[i]
+		if _, err := io.ReadFull(r, hop.PubKey[:]); err != nil {
+			return nil, err
+		}
+		if hop.ChannelID, err = readUint64(); err != nil {
+			return nil, err
+		}
+		amtToForward, err := readUint64()
+		if err != nil {
+			return nil, err
+		}
+		hop.AmtToForward = btcutil.Amount(amtToForward)
+		fee, err := readUint64()
+		if err != nil {
+			return nil, err
+		}
+		hop.Fee = btcutil.Amount(fee)
+	}
+
+	if a.AttemptTime, err = readTime(); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return nil, err
+	}
+	a.State = AttemptState(scratch[0])
+	if a.ResolveTime, err = readTime(); err != nil {
+		return nil, err
+	}
+
+	failure, err := wire.ReadVarBytes(r, 0, MaxPaymentFailureSize, "")
+	if err != nil {
+		return nil, err
+	}
+	a.Failure = string(failure)
+
+	return a, nil
+}
@@ -0,0 +1,300 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcutil"
+)
+
+func makeFakePaymentInfo(seed byte) *PaymentCreationInfo {
+	info := &PaymentCreationInfo{
+		Value:          10000,
+		CreationDate:   time.Unix(time.Now().Unix(), 0),
+		PaymentRequest: []byte("lntb1fakepayreq"),
+	}
+	copy(info.PaymentHash[:], bytes.Repeat([]byte{seed}, 32))
+
+	return info
+}
+
+func makeFakeAttempt(amt int64) *PaymentAttempt {
+	attempt := &PaymentAttempt{
+		TotalAmount:   btcutil.Amount(amt + 10),
+		TotalFees:     10,
+		TotalTimeLock: 144,
+		Hops: []AttemptHop{
+			{
+				ChannelID:    1,
+				AmtToForward: btcutil.Amount(amt),
+				Fee:          10,
+			},
+			{
+				ChannelID:    2,
+				AmtToForward: btcutil.Amount(amt),
+			},
+		},
+		AttemptTime: time.Unix(time.Now().Unix(), 0),
+	}
+	copy(attempt.Hops[0].PubKey[:], bytes.Repeat([]byte{2}, 33))
+	copy(attempt.Hops[1].PubKey[:], bytes.Repeat([]byte{3}, 33))
+
+	return attempt
+}
+
+func assertPaymentStatus(t *testing.T, pControl *PaymentControl,
+	hash [32]byte, expected PaymentStatus) *TrackedPayment {
+
+	payment, err := pControl.FetchPayment(hash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if payment.Status != expected {
+		t.Fatalf("expected payment status %v, got %v", expected,
+			payment.Status)
+	}
+
+	return payment
+}
+
+func TestPaymentControlWorkflow(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	pControl := NewPaymentControl(db)
+	info := makeFakePaymentInfo(1)
+	hash := info.PaymentHash
+
+	// Before the payment is initiated, any attempt to modify it should
+	// fail.
+	if _, err := pControl.FetchPayment(hash); err != ErrPaymentNotInitiated {
+		t.Fatalf("expected ErrPaymentNotInitiated, got %v", err)
+	}
+	_, err = pControl.RegisterAttempt(hash, makeFakeAttempt(1000))
+	if err != ErrPaymentNotInitiated {
+		t.Fatalf("expected ErrPaymentNotInitiated, got %v", err)
+	}
+
+	if err := pControl.InitPayment(info); err != nil {
+		t.Fatalf("unable to init payment: %v", err)
+	}
+	assertPaymentStatus(t, pControl, hash, StatusInitiated)
+
+	// A second payment to the same hash must be rejected while the first
+	// is still outstanding.
+	if err := pControl.InitPayment(info); err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
+	}
+
+	// Register two attempts, the first of which fails, and the second of
+	// which is settled.
+	firstAttempt := makeFakeAttempt(1000)
+	firstID, err := pControl.RegisterAttempt(hash, firstAttempt)
+	if err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
+	assertPaymentStatus(t, pControl, hash, StatusInFlight)
+
+	secondAttempt := makeFakeAttempt(1000)
+	secondID, err := pControl.RegisterAttempt(hash, secondAttempt)
+	if err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
+	if firstID == secondID {
+		t.Fatalf("attempts share the same ID %v", firstID)
+	}
+
+	if err := pControl.InitPayment(info); err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
+	}
+
+	if err := pControl.FailAttempt(hash, firstID, "no route"); err != nil {
+		t.Fatalf("unable to fail attempt: %v", err)
+	}
+	err = pControl.FailAttempt(hash, firstID, "no route")
+	if err != ErrPaymentAttemptResolved {
+		t.Fatalf("expected ErrPaymentAttemptResolved, got %v", err)
+	}
+	assertPaymentStatus(t, pControl, hash, StatusInFlight)
+
+	var preimage [32]byte
+	copy(preimage[:], bytes.Repeat([]byte{9}, 32))
+	if err := pControl.SettleAttempt(hash, secondID, preimage); err != nil {
+		t.Fatalf("unable to settle attempt: %v", err)
+	}
+	if err := pControl.SettleAttempt(hash, 100, preimage); err !=
+		ErrPaymentAttemptNotFound {
+
+		t.Fatalf("expected ErrPaymentAttemptNotFound, got %v", err)
+	}
+
+	payment := assertPaymentStatus(t, pControl, hash, StatusSucceeded)
+	if payment.Preimage != preimage {
+		t.Fatalf("expected preimage %x, got %x", preimage,
+			payment.Preimage)
+	}
+	if !reflect.DeepEqual(&payment.Info, info) {
+		t.Fatalf("creation info doesn't match: expected %v, got %v",
+			spew.Sdump(info), spew.Sdump(payment.Info))
+	}
+	if len(payment.Attempts) != 2 {
+		t.Fatalf("expected 2 attempts, got %v", len(payment.Attempts))
+	}
+	if len(payment.InFlightAttempts()) != 0 {
+		t.Fatalf("expected no attempts in flight")
+	}
+
+	// The stored attempts should match those registered, along with their
+	// outcomes.
+	first, second := payment.Attempts[0], payment.Attempts[1]
+	if first.State != AttemptFailed || first.Failure != "no route" ||
+		first.ResolveTime.IsZero() {
+
+		t.Fatalf("first attempt not failed: %v", spew.Sdump(first))
+	}
+	if second.State != AttemptSettled || second.ResolveTime.IsZero() {
+		t.Fatalf("second attempt not settled: %v", spew.Sdump(second))
+	}
+	second.ResolveTime = time.Time{}
+	second.State = AttemptInFlight
+	if !reflect.DeepEqual(second, secondAttempt) {
+		t.Fatalf("attempts don't match: expected %v, got %v",
+			spew.Sdump(secondAttempt), spew.Sdump(second))
+	}
+
+	// Now that the payment has succeeded, it can neither be initiated
+	// again, nor failed.
+	if err := pControl.InitPayment(info); err != ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, got %v", err)
+	}
+	if err := pControl.Fail(hash, "timeout"); err != ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, got %v", err)
+	}
+}
+
+func TestPaymentControlFailAndRetry(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	pControl := NewPaymentControl(db)
+	info := makeFakePaymentInfo(2)
+	hash := info.PaymentHash
+
+	if err := pControl.InitPayment(info); err != nil {
+		t.Fatalf("unable to init payment: %v", err)
+	}
+	attemptID, err := pControl.RegisterAttempt(hash, makeFakeAttempt(500))
+	if err != nil {
+		t.Fatalf("unable to register attempt: %v", err)
+	}
+
+	// The payment can't fail while its attempt is still in flight.
+	if err := pControl.Fail(hash, "timeout"); err != ErrPaymentAttemptsInFlight {
+		t.Fatalf("expected ErrPaymentAttemptsInFlight, got %v", err)
+	}
+
+	if err := pControl.FailAttempt(hash, attemptID, "fail"); err != nil {
+		t.Fatalf("unable to fail attempt: %v", err)
+	}
+	if err := pControl.Fail(hash, "timeout"); err != nil {
+		t.Fatalf("unable to fail payment: %v", err)
+	}
+	payment := assertPaymentStatus(t, pControl, hash, StatusFailed)
+	if payment.FailureReason != "timeout" {
+		t.Fatalf("expected failure reason timeout, got %v",
+			payment.FailureReason)
+	}
+
+	_, err = pControl.RegisterAttempt(hash, makeFakeAttempt(500))
+	if err != ErrPaymentAlreadyFailed {
+		t.Fatalf("expected ErrPaymentAlreadyFailed, got %v", err)
+	}
+
+	// A failed payment may be initiated anew, which should wipe all traces
+	// of the prior payment.
+	if err := pControl.InitPayment(info); err != nil {
+		t.Fatalf("unable to re-init payment: %v", err)
+	}
+	payment = assertPaymentStatus(t, pControl, hash, StatusInitiated)
+	if len(payment.Attempts) != 0 || payment.FailureReason != "" {
+		t.Fatalf("prior payment not wiped: %v", spew.Sdump(payment))
+	}
+}
+
+func TestPaymentControlFetchInFlight(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	pControl := NewPaymentControl(db)
+
+	// With no payments initiated, no payments should be in flight.
+	inFlight, err := pControl.FetchInFlightPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch in flight payments: %v", err)
+	}
+	if len(inFlight) != 0 {
+		t.Fatalf("expected no payments, got %v", len(inFlight))
+	}
+
+	// We'll create four payments: one initiated, one in flight, one
+	// succeeded and one failed.
+	var infos []*PaymentCreationInfo
+	for i := byte(0); i < 4; i++ {
+		info := makeFakePaymentInfo(i + 10)
+		if err := pControl.InitPayment(info); err != nil {
+			t.Fatalf("unable to init payment: %v", err)
+		}
+		infos = append(infos, info)
+	}
+
+	for _, info := range infos[1:] {
+		_, err := pControl.RegisterAttempt(
+			info.PaymentHash, makeFakeAttempt(1000),
+		)
+		if err != nil {
+			t.Fatalf("unable to register attempt: %v", err)
+		}
+	}
+
+	err = pControl.SettleAttempt(infos[2].PaymentHash, 1, [32]byte{1})
+	if err != nil {
+		t.Fatalf("unable to settle attempt: %v", err)
+	}
+	if err := pControl.FailAttempt(infos[3].PaymentHash, 1, ""); err != nil {
+		t.Fatalf("unable to fail attempt: %v", err)
+	}
+	if err := pControl.Fail(infos[3].PaymentHash, "failed"); err != nil {
+		t.Fatalf("unable to fail payment: %v", err)
+	}
+
+	inFlight, err = pControl.FetchInFlightPayments()
+	if err != nil {
+		t.Fatalf("unable to fetch in flight payments: %v", err)
+	}
+	if len(inFlight) != 2 {
+		t.Fatalf("expected 2 payments, got %v", len(inFlight))
+	}
+	if inFlight[0].Info.PaymentHash != infos[0].PaymentHash ||
+		inFlight[0].Status != StatusInitiated {
+
+		t.Fatalf("unexpected payment: %v", spew.Sdump(inFlight[0]))
+	}
+	if inFlight[1].Info.PaymentHash != infos[1].PaymentHash ||
+		inFlight[1].Status != StatusInFlight ||
+		len(inFlight[1].InFlightAttempts()) != 1 {
+
+		t.Fatalf("unexpected payment: %v", spew.Sdump(inFlight[1]))
+	}
+}
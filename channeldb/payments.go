@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"io"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/roasbeef/btcutil"
@@ -17,6 +18,12 @@ var (
 	// which is a monotonically increasing uint64.  BoltDB's sequence
 	// feature is used for generating monotonically increasing id.
 	paymentBucket = []byte("payments")
+
+	// paymentCreationIndexBucket is the name of the sub-bucket within the
+	// paymentBucket which indexes all payments by their creation date.
+	// Each key is the creation date of a payment as a big-endian unix
+	// timestamp, followed by the payment's sequence number.
+	paymentCreationIndexBucket = []byte("creationindex")
 )
 
 // OutgoingPayment represents a successful payment between the daemon and a
@@ -42,6 +49,12 @@ type OutgoingPayment struct {
 	// TODO(roasbeef): weave through preimage on payment success to can
 	// store only supplemental info the embedded Invoice
 	PaymentHash [32]byte
+
+	// SequenceNum is the unique, monotonically increasing index assigned
+	// to the payment when it was added to the database, starting at 1.
+	// It's derived from the key the payment is stored under, so isn't
+	// stored within the serialized payment itself.
+	SequenceNum uint64
 }
 
 // AddPayment saves a successful payment to the database. It is assumed that
@@ -79,6 +92,18 @@ func (db *DB) AddPayment(payment *OutgoingPayment) error {
 		// in the order in which they were created.
 		paymentIdBytes := make([]byte, 8)
 		binary.BigEndian.PutUint64(paymentIdBytes, paymentId)
+		payment.SequenceNum = paymentId
+
+		creationIndex, err := payments.CreateBucketIfNotExists(
+			paymentCreationIndexBucket,
+		)
+		if err != nil {
+			return err
+		}
+		indexKey := timeIndexKey(payment.CreationDate, paymentIdBytes)
+		if err := creationIndex.Put(indexKey, nil); err != nil {
+			return err
+		}
 
 		return payments.Put(paymentIdBytes, paymentBytes)
 	})
@@ -101,8 +126,7 @@ func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 				return nil
 			}
 
-			r := bytes.NewReader(v)
-			payment, err := deserializeOutgoingPayment(r)
+			payment, err := decodeOutgoingPayment(k, v)
 			if err != nil {
 				return err
 			}
@@ -118,6 +142,160 @@ func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 	return payments, nil
 }
 
+// PaymentsQuery represents a query to the payments database. The query allows
+// a caller to retrieve a page of payments, starting after a particular
+// sequence number, which optionally match a set of filters.
+type PaymentsQuery struct {
+	// IndexOffset is the sequence number of the payment after which the
+	// page starts. The payment with this sequence number is excluded. If
+	// Reversed is set, then the page instead consists of payments
+	// preceding this sequence number. A value of zero starts the page at
+	// either the first or last payment.
+	IndexOffset uint64
+
+	// MaxPayments is the maximum number of payments returned within the
+	// page. A value of zero places no limit on the number of payments.
+	MaxPayments uint64
+
+	// Reversed, if set, pages through the payments in descending sequence
+	// number order, allowing the most recent payments to be retrieved
+	// first.
+	Reversed bool
+
+	// CreationDateStart, if non-zero, only returns payments created at or
+	// after this time.
+	CreationDateStart time.Time
+
+	// CreationDateEnd, if non-zero, only returns payments created at or
+	// before this time.
+	CreationDateEnd time.Time
+}
+
+// matches returns true if the passed payment matches the filters of the
+// query.
+func (q *PaymentsQuery) matches(payment *OutgoingPayment) bool {
+	if !q.CreationDateStart.IsZero() &&
+		payment.CreationDate.Before(q.CreationDateStart) {
+
+		return false
+	}
+	if !q.CreationDateEnd.IsZero() &&
+		payment.CreationDate.After(q.CreationDateEnd) {
+
+		return false
+	}
+
+	return true
+}
+
+// PaymentsSlice is the response to a payments query. It includes the original
+// query, the page of payments matching it, and the sequence numbers of the
+// first and last payments within the page, which are used to continue
+// paging.
+type PaymentsSlice struct {
+	PaymentsQuery
+
+	// Payments is the page of payments matching the query, in ascending
+	// sequence number order regardless of the direction of the query.
+	Payments []*OutgoingPayment
+
+	// FirstIndexOffset is the sequence number of the first payment within
+	// the page. When paging in reverse, it's used as the index offset of
+	// the next query.
+	FirstIndexOffset uint64
+
+	// LastIndexOffset is the sequence number of the last payment within
+	// the page. When paging forwards, it's used as the index offset of the
+	// next query.
+	LastIndexOffset uint64
+}
+
+// QueryPayments returns a page of the payments matching the passed query. The
+// payments are traversed using their sequence number, so only the payments
+// within the page, along with those skipped by the query's filters, are read
+// from the database. Payments created outside of the query's creation date
+// range are skipped using the creation index, without being read.
+func (db *DB) QueryPayments(q PaymentsQuery) (PaymentsSlice, error) {
+	resp := PaymentsSlice{
+		PaymentsQuery: q,
+	}
+
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(paymentBucket)
+		if bucket == nil {
+			return ErrNoPaymentsCreated
+		}
+
+		var startKey []byte
+		if q.IndexOffset != 0 {
+			startKey = make([]byte, 8)
+			binary.BigEndian.PutUint64(startKey, q.IndexOffset)
+		}
+
+		// If the query filters payments by their creation date, then
+		// we'll use the creation index to find the payments created
+		// within the range, so that only they're read.
+		var created *timeRange
+		creationIndex := bucket.Bucket(paymentCreationIndexBucket)
+		if creationIndex != nil && (!q.CreationDateStart.IsZero() ||
+			!q.CreationDateEnd.IsZero()) {
+
+			created = fetchTimeRange(
+				creationIndex, q.CreationDateStart,
+				q.CreationDateEnd,
+			)
+		}
+
+		return paginate(bucket.Cursor(), startKey, 8, q.Reversed,
+			func(k, v []byte) (bool, error) {
+				if created != nil {
+					inRange, cont := created.filter(
+						k, q.Reversed,
+					)
+					if !inRange {
+						return cont, nil
+					}
+				}
+
+				payment, err := decodeOutgoingPayment(k, v)
+				if err != nil {
+					return false, err
+				}
+
+				if !q.matches(payment) {
+					return true, nil
+				}
+
+				resp.Payments = append(resp.Payments, payment)
+
+				return q.MaxPayments == 0 ||
+					uint64(len(resp.Payments)) < q.MaxPayments, nil
+			},
+		)
+	})
+	if err != nil && err != ErrNoPaymentsCreated {
+		return resp, err
+	}
+
+	// When paging in reverse, the payments were gathered in descending
+	// order, so we'll restore them to ascending order.
+	if q.Reversed {
+		numPayments := len(resp.Payments)
+		for i := 0; i < numPayments/2; i++ {
+			opposite := numPayments - i - 1
+			resp.Payments[i], resp.Payments[opposite] =
+				resp.Payments[opposite], resp.Payments[i]
+		}
+	}
+
+	if len(resp.Payments) != 0 {
+		resp.FirstIndexOffset = resp.Payments[0].SequenceNum
+		resp.LastIndexOffset = resp.Payments[len(resp.Payments)-1].SequenceNum
+	}
+
+	return resp, nil
+}
+
 // DeleteAllPayments deletes all payments from DB.
 func (db *DB) DeleteAllPayments() error {
 	return db.Update(func(tx *bolt.Tx) error {
@@ -174,6 +352,18 @@ func serializeOutgoingPayment(w io.Writer, p *OutgoingPayment) error {
 	return nil
 }
 
+// decodeOutgoingPayment deserializes the payment stored under the passed key,
+// populating its sequence number.
+func decodeOutgoingPayment(k, v []byte) (*OutgoingPayment, error) {
+	payment, err := deserializeOutgoingPayment(bytes.NewReader(v))
+	if err != nil {
+		return nil, err
+	}
+	payment.SequenceNum = binary.BigEndian.Uint64(k)
+
+	return payment, nil
+}
+
 func deserializeOutgoingPayment(r io.Reader) (*OutgoingPayment, error) {
 	var scratch [8]byte
 
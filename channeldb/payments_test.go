@@ -185,3 +185,110 @@ func TestOutgoingPaymentWorkflow(t *testing.T) {
 			len(paymentsAfterDeletion), 0)
 	}
 }
+
+// TestQueryPayments tests that pages of payments can be queried in either
+// direction, and that the query's creation date filters are applied.
+func TestQueryPayments(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// We'll add six payments, created a minute apart.
+	baseTime := time.Unix(1496314658, 0)
+	for i := 0; i < 6; i++ {
+		payment, err := makeRandomFakePayment()
+		if err != nil {
+			t.Fatalf("Internal error in tests: %v", err)
+		}
+		payment.CreationDate = baseTime.Add(time.Duration(i) * time.Minute)
+
+		if err := db.AddPayment(payment); err != nil {
+			t.Fatalf("unable to put payment in DB: %v", err)
+		}
+		if payment.SequenceNum != uint64(i+1) {
+			t.Fatalf("expected sequence number %v, got %v", i+1,
+				payment.SequenceNum)
+		}
+	}
+
+	tests := []struct {
+		query   PaymentsQuery
+		seqNums []uint64
+	}{
+		{
+			query:   PaymentsQuery{MaxPayments: 4},
+			seqNums: []uint64{1, 2, 3, 4},
+		},
+		{
+			query:   PaymentsQuery{IndexOffset: 4, MaxPayments: 4},
+			seqNums: []uint64{5, 6},
+		},
+		{
+			query:   PaymentsQuery{MaxPayments: 2, Reversed: true},
+			seqNums: []uint64{5, 6},
+		},
+		{
+			query: PaymentsQuery{
+				IndexOffset: 5,
+				MaxPayments: 2,
+				Reversed:    true,
+			},
+			seqNums: []uint64{3, 4},
+		},
+		{
+			query: PaymentsQuery{
+				CreationDateStart: baseTime.Add(time.Minute),
+				CreationDateEnd:   baseTime.Add(2 * time.Minute),
+			},
+			seqNums: []uint64{2, 3},
+		},
+		{
+			query: PaymentsQuery{
+				MaxPayments:       2,
+				Reversed:          true,
+				CreationDateStart: baseTime.Add(time.Minute),
+				CreationDateEnd:   baseTime.Add(4 * time.Minute),
+			},
+			seqNums: []uint64{4, 5},
+		},
+		{
+			query: PaymentsQuery{
+				CreationDateEnd: baseTime,
+			},
+			seqNums: []uint64{1},
+		},
+		{
+			query: PaymentsQuery{
+				CreationDateStart: baseTime.Add(time.Hour),
+			},
+			seqNums: nil,
+		},
+	}
+
+	for i, test := range tests {
+		resp, err := db.QueryPayments(test.query)
+		if err != nil {
+			t.Fatalf("test #%v: unable to query payments: %v", i, err)
+		}
+
+		var seqNums []uint64
+		for _, payment := range resp.Payments {
+			seqNums = append(seqNums, payment.SequenceNum)
+		}
+		if !reflect.DeepEqual(seqNums, test.seqNums) {
+			t.Fatalf("test #%v: expected payments %v, got %v", i,
+				test.seqNums, seqNums)
+		}
+
+		var first, last uint64
+		if len(seqNums) != 0 {
+			first, last = seqNums[0], seqNums[len(seqNums)-1]
+		}
+		if resp.FirstIndexOffset != first || resp.LastIndexOffset != last {
+			t.Fatalf("test #%v: incorrect index offsets %v and %v",
+				i, resp.FirstIndexOffset, resp.LastIndexOffset)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// webhookQueueBucket is the name of the bucket within the database
+	// that stores the queue of webhook notifications which are yet to be
+	// delivered.
+	//
+	// Within the webhook queue bucket, each delivery is keyed by its ID,
+	// serialized as an 8-byte big-endian integer, such that deliveries are
+	// iterated over in the order they were added.
+	webhookQueueBucket = []byte("webhook-queue")
+
+	// webhookIndexBucket is the name of the bucket within the database
+	// that stores the add and settle indexes of the latest invoices for
+	// which webhook notifications have been queued.
+	webhookIndexBucket = []byte("webhook-indexes")
+
+	// webhookIndexesKey is the key within the webhook index bucket under
+	// which the indexes are stored, serialized as two 8-byte big-endian
+	// integers.
+	webhookIndexesKey = []byte("indexes")
+)
+
+// WebhookIndexes are the add and settle indexes of the latest invoices for
+// which webhook notifications have been queued. They allow the invoices
+// which were added or settled while no notifications were being queued,
+// such as while lnd was shut down, to be determined.
+type WebhookIndexes struct {
+	// AddIndex is the add index of the latest invoice for which an added
+	// notification has been queued.
+	AddIndex uint64
+
+	// SettleIndex is the settle index of the latest invoice for which a
+	// settled notification has been queued.
+	SettleIndex uint64
+}
+
+// WebhookDelivery is a notification which is yet to be successfully
+// delivered to a webhook URL. Deliveries remain within the queue across
+// restarts until they're either delivered, or dropped after too many failed
+// attempts.
+type WebhookDelivery struct {
+	// ID uniquely identifies the delivery within the queue. It's assigned
+	// when the delivery is added to the queue.
+	ID uint64
+
+	// URL is the URL the notification is to be delivered to.
+	URL string
+
+	// Payload is the body of the notification.
+	Payload []byte
+
+	// Attempts is the number of failed attempts to deliver the
+	// notification so far.
+	Attempts uint32
+
+	// NextAttempt is the earliest time at which delivery of the
+	// notification is to be attempted again.
+	NextAttempt time.Time
+}
+
+// AddWebhookDeliveries adds the passed deliveries to the webhook queue,
+// assigning each a new ID. If indexes is non-nil, then it's stored within
+// the same transaction as the deliveries, such that the stored indexes never
+// run ahead of the queue.
+func (db *DB) AddWebhookDeliveries(deliveries []*WebhookDelivery,
+	indexes *WebhookIndexes) error {
+
+	return db.Update(func(tx *bolt.Tx) error {
+		queue, err := tx.CreateBucketIfNotExists(webhookQueueBucket)
+		if err != nil {
+			return err
+		}
+
+		if indexes != nil {
+			indexBucket, err := tx.CreateBucketIfNotExists(
+				webhookIndexBucket,
+			)
+			if err != nil {
+				return err
+			}
+
+			var b [16]byte
+			byteOrder.PutUint64(b[:8], indexes.AddIndex)
+			byteOrder.PutUint64(b[8:], indexes.SettleIndex)
+			err = indexBucket.Put(webhookIndexesKey, b[:])
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, delivery := range deliveries {
+			id, err := queue.NextSequence()
+			if err != nil {
+				return err
+			}
+			delivery.ID = id
+
+			err = putWebhookDelivery(queue, delivery)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchWebhookDeliveries returns all the deliveries within the webhook queue,
+// in the order they were added.
+func (db *DB) FetchWebhookDeliveries() ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	err := db.View(func(tx *bolt.Tx) error {
+		queue := tx.Bucket(webhookQueueBucket)
+		if queue == nil {
+			return nil
+		}
+
+		return queue.ForEach(func(k, v []byte) error {
+			delivery, err := deserializeWebhookDelivery(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			delivery.ID = byteOrder.Uint64(k)
+
+			deliveries = append(deliveries, delivery)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FetchWebhookIndexes returns the indexes last stored by
+// AddWebhookDeliveries. If no indexes have been stored yet, then
+// ErrWebhookIndexesNotFound is returned.
+func (db *DB) FetchWebhookIndexes() (*WebhookIndexes, error) {
+	var indexes *WebhookIndexes
+	err := db.View(func(tx *bolt.Tx) error {
+		indexBucket := tx.Bucket(webhookIndexBucket)
+		if indexBucket == nil {
+			return ErrWebhookIndexesNotFound
+		}
+
+		b := indexBucket.Get(webhookIndexesKey)
+		if len(b) != 16 {
+			return ErrWebhookIndexesNotFound
+		}
+
+		indexes = &WebhookIndexes{
+			AddIndex:    byteOrder.Uint64(b[:8]),
+			SettleIndex: byteOrder.Uint64(b[8:]),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return indexes, nil
+}
+
+// UpdateWebhookDelivery overwrites the stored state of the passed delivery,
+// such as after a failed attempt to deliver it. If the delivery isn't within
+// the queue, then ErrWebhookDeliveryNotFound is returned.
+func (db *DB) UpdateWebhookDelivery(delivery *WebhookDelivery) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		queue := tx.Bucket(webhookQueueBucket)
+		if queue == nil {
+			return ErrWebhookDeliveryNotFound
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], delivery.ID)
+		if queue.Get(key[:]) == nil {
+			return ErrWebhookDeliveryNotFound
+		}
+
+		return putWebhookDelivery(queue, delivery)
+	})
+}
+
+// DeleteWebhookDelivery removes the delivery with the passed ID from the
+// webhook queue. Deleting a delivery which isn't within the queue is a no-op.
+func (db *DB) DeleteWebhookDelivery(id uint64) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		queue := tx.Bucket(webhookQueueBucket)
+		if queue == nil {
+			return nil
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], id)
+		return queue.Delete(key[:])
+	})
+}
+
+// putWebhookDelivery writes the passed delivery to the webhook queue, keyed
+// by its ID.
+func putWebhookDelivery(queue *bolt.Bucket, delivery *WebhookDelivery) error {
+	var b bytes.Buffer
+	if err := serializeWebhookDelivery(&b, delivery); err != nil {
+		return err
+	}
+
+	var key [8]byte
+	byteOrder.PutUint64(key[:], delivery.ID)
+	return queue.Put(key[:], b.Bytes())
+}
+
+// serializeWebhookDelivery writes the delivery to the passed io.Writer. The
+// delivery's ID isn't included as it's used as the key of the entry.
+func serializeWebhookDelivery(w io.Writer, d *WebhookDelivery) error {
+	if err := wire.WriteVarString(w, 0, d.URL); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, d.Payload); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint32(scratch[:4], d.Attempts)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	var nextAttempt int64
+	if !d.NextAttempt.IsZero() {
+		nextAttempt = d.NextAttempt.UnixNano()
+	}
+	byteOrder.PutUint64(scratch[:], uint64(nextAttempt))
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+// deserializeWebhookDelivery reads a delivery from the passed io.Reader.
+func deserializeWebhookDelivery(r io.Reader) (*WebhookDelivery, error) {
+	d := &WebhookDelivery{}
+
+	var err error
+	d.URL, err = wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload, err = wire.ReadVarBytes(r, 0, math.MaxUint32, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	d.Attempts = byteOrder.Uint32(scratch[:4])
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	nextAttempt := int64(byteOrder.Uint64(scratch[:]))
+	if nextAttempt != 0 {
+		d.NextAttempt = time.Unix(0, nextAttempt)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,129 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func TestWebhookQueue(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// An empty queue should be returned before any deliveries are added.
+	deliveries, err := db.FetchWebhookDeliveries()
+	if err != nil {
+		t.Fatalf("unable to fetch deliveries: %v", err)
+	}
+	if len(deliveries) != 0 {
+		t.Fatalf("expected no deliveries, got %v", len(deliveries))
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	added := []*WebhookDelivery{
+		{
+			URL:         "http://localhost/first",
+			Payload:     []byte(`{"event":"invoice_added"}`),
+			NextAttempt: now,
+		},
+		{
+			URL:         "http://localhost/second",
+			Payload:     []byte(`{"event":"invoice_added"}`),
+			NextAttempt: now,
+		},
+	}
+	if err := db.AddWebhookDeliveries(added, nil); err != nil {
+		t.Fatalf("unable to add deliveries: %v", err)
+	}
+	if added[0].ID == added[1].ID {
+		t.Fatalf("deliveries share the same ID %v", added[0].ID)
+	}
+
+	deliveries, err = db.FetchWebhookDeliveries()
+	if err != nil {
+		t.Fatalf("unable to fetch deliveries: %v", err)
+	}
+	if !reflect.DeepEqual(deliveries, added) {
+		t.Fatalf("deliveries don't match: expected %v, got %v",
+			spew.Sdump(added), spew.Sdump(deliveries))
+	}
+
+	// After a failed attempt, the first delivery is rescheduled, which
+	// should be reflected within the queue.
+	added[0].Attempts = 1
+	added[0].NextAttempt = now.Add(time.Minute)
+	if err := db.UpdateWebhookDelivery(added[0]); err != nil {
+		t.Fatalf("unable to update delivery: %v", err)
+	}
+
+	// The second delivery succeeds, so it's removed from the queue, after
+	// which it can no longer be updated.
+	if err := db.DeleteWebhookDelivery(added[1].ID); err != nil {
+		t.Fatalf("unable to delete delivery: %v", err)
+	}
+	err = db.UpdateWebhookDelivery(added[1])
+	if err != ErrWebhookDeliveryNotFound {
+		t.Fatalf("expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+
+	deliveries, err = db.FetchWebhookDeliveries()
+	if err != nil {
+		t.Fatalf("unable to fetch deliveries: %v", err)
+	}
+	if !reflect.DeepEqual(deliveries, added[:1]) {
+		t.Fatalf("deliveries don't match: expected %v, got %v",
+			spew.Sdump(added[:1]), spew.Sdump(deliveries))
+	}
+}
+
+// TestWebhookIndexes tests that the webhook indexes are only stored once
+// passed along with deliveries, and that the latest ones are returned.
+func TestWebhookIndexes(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	_, err = db.FetchWebhookIndexes()
+	if err != ErrWebhookIndexesNotFound {
+		t.Fatalf("expected ErrWebhookIndexesNotFound, got %v", err)
+	}
+
+	// Adding deliveries without any indexes shouldn't store them.
+	delivery := &WebhookDelivery{
+		URL:     "http://localhost/first",
+		Payload: []byte(`{"event":"invoice_added"}`),
+	}
+	err = db.AddWebhookDeliveries([]*WebhookDelivery{delivery}, nil)
+	if err != nil {
+		t.Fatalf("unable to add deliveries: %v", err)
+	}
+	_, err = db.FetchWebhookIndexes()
+	if err != ErrWebhookIndexesNotFound {
+		t.Fatalf("expected ErrWebhookIndexesNotFound, got %v", err)
+	}
+
+	for _, expected := range []*WebhookIndexes{
+		{AddIndex: 1},
+		{AddIndex: 2, SettleIndex: 1},
+	} {
+		if err := db.AddWebhookDeliveries(nil, expected); err != nil {
+			t.Fatalf("unable to add indexes: %v", err)
+		}
+
+		indexes, err := db.FetchWebhookIndexes()
+		if err != nil {
+			t.Fatalf("unable to fetch indexes: %v", err)
+		}
+		if *indexes != *expected {
+			t.Fatalf("expected indexes %v, got %v", expected,
+				indexes)
+		}
+	}
+}
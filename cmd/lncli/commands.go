@@ -238,6 +238,11 @@ var OpenChannelCommand = cli.Command{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
 		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "make the channel private, such that it won't " +
+				"be announced to the greater network",
+		},
 	},
 	Action: openChannel,
 }
@@ -257,6 +262,7 @@ func openChannel(ctx *cli.Context) error {
 		LocalFundingAmount: int64(ctx.Int("local_amt")),
 		PushSat:            int64(ctx.Int("push_amt")),
 		NumConfs:           uint32(ctx.Int("num_confs")),
+		Private:            ctx.Bool("private"),
 	}
 
 	if ctx.Int("peer_id") != 0 {
@@ -443,6 +449,39 @@ func listPeers(ctx *cli.Context) error {
 	return nil
 }
 
+var PeerLimitsCommand = cli.Command{
+	Name: "peerlimits",
+	Description: "Display the HTLC rate limits and forwarding caps " +
+		"enforced by the daemon, along with the current limiter " +
+		"state of each peer.",
+	Usage: "peerlimits --pub_key=[node_key]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "pub_key",
+			Usage: "if set, only the state of the peer with this " +
+				"public key will be displayed",
+		},
+	},
+	Action: peerLimits,
+}
+
+func peerLimits(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PeerLimitsRequest{
+		PubKey: ctx.String("pub_key"),
+	}
+	resp, err := client.PeerLimits(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+	return nil
+}
+
 var WalletBalanceCommand = cli.Command{
 	Name:        "walletbalance",
 	Description: "compute and display the wallet's current balance",
@@ -597,19 +636,118 @@ func listChannels(ctx *cli.Context) error {
 	return nil
 }
 
+// routeRestrictionFlags are the flags shared by the commands which accept
+// restrictions on the route selected for a payment.
+var routeRestrictionFlags = []cli.Flag{
+	cli.Int64Flag{
+		Name: "fee_limit",
+		Usage: "the maximum total fee in satoshis the route may " +
+			"carry, 0 for no limit",
+	},
+	cli.Uint64Flag{
+		Name: "cltv_limit",
+		Usage: "the maximum total time lock in blocks the route " +
+			"may carry, 0 for no limit",
+	},
+	cli.Uint64Flag{
+		Name: "outgoing_chan_id",
+		Usage: "the short channel id of the channel that must be " +
+			"used as the first hop",
+	},
+	cli.StringFlag{
+		Name: "last_hop",
+		Usage: "the pubkey of the node that must be the last hop " +
+			"before the destination",
+	},
+	cli.StringSliceFlag{
+		Name:  "ignore_node",
+		Usage: "the pubkey of a node the route must not pass through",
+	},
+	cli.Int64SliceFlag{
+		Name: "ignore_edge",
+		Usage: "the short channel id of a channel the route must " +
+			"not pass through",
+	},
+}
+
+// parseRestrictedNodes decodes the hex encoded public keys of the last hop
+// and the ignored nodes passed within the route restriction flags.
+func parseRestrictedNodes(ctx *cli.Context) ([]byte, [][]byte, error) {
+	var lastHop []byte
+	if ctx.IsSet("last_hop") {
+		var err error
+		lastHop, err = hex.DecodeString(ctx.String("last_hop"))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var ignoredNodes [][]byte
+	for _, nodeStr := range ctx.StringSlice("ignore_node") {
+		node, err := hex.DecodeString(nodeStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		ignoredNodes = append(ignoredNodes, node)
+	}
+
+	return lastHop, ignoredNodes, nil
+}
+
+// customRecordsFlag is the flag shared by the commands which deliver custom
+// records to the destination of a payment.
+var customRecordsFlag = cli.StringFlag{
+	Name: "data",
+	Usage: "custom records to deliver to the destination, as a comma " +
+		"separated list of type=hex_value pairs, with each type " +
+		"at least 65536",
+}
+
+// parseCustomRecords parses the custom records passed to the data flag.
+func parseCustomRecords(data string) (map[uint64][]byte, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	records := make(map[uint64][]byte)
+	for _, record := range strings.Split(data, ",") {
+		parts := strings.SplitN(record, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid custom record %q, "+
+				"expected type=hex_value", record)
+		}
+
+		recordType, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom record type "+
+				"%q: %v", parts[0], err)
+		}
+		value, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom record value "+
+				"%q: %v", parts[1], err)
+		}
+		records[recordType] = value
+	}
+
+	return records, nil
+}
+
 var SendPaymentCommand = cli.Command{
 	Name:        "sendpayment",
 	Description: "send a payment over lightning",
-	Usage:       "sendpayment --dest=[node_key] --amt=[in_satoshis] --payment_hash=[hash] --debug_send=[true|false]",
-	Flags: []cli.Flag{
+	Usage:       "sendpayment --dest=[node_key] --amt=[in_satoshis] --payment_hash=[hash] --debug_send=[true|false] --keysend=[true|false] --data=[type=hex_value,...]",
+	Flags: append([]cli.Flag{
 		cli.StringFlag{
 			Name: "dest, d",
 			Usage: "the compressed identity pubkey of the " +
 				"payment recipient",
 		},
 		cli.IntFlag{ // TODO(roasbeef): float64?
-			Name:  "amt, a",
-			Usage: "number of satoshis to send",
+			Name: "amt, a",
+			Usage: "number of satoshis to send, required when " +
+				"paying a payment request which doesn't " +
+				"specify an amount",
 		},
 		cli.StringFlag{
 			Name:  "payment_hash, r",
@@ -619,11 +757,34 @@ var SendPaymentCommand = cli.Command{
 			Name:  "debug_send",
 			Usage: "use the debug rHash when sending the HTLC",
 		},
+		cli.BoolFlag{
+			Name: "keysend",
+			Usage: "send a spontaneous payment to the destination " +
+				"without an invoice, generating the payment's " +
+				"preimage locally",
+		},
 		cli.StringFlag{
 			Name:  "pay_req",
 			Usage: "a zbase32-check encoded payment request to fulfill",
 		},
-	},
+		cli.IntFlag{
+			Name: "timeout",
+			Usage: "the maximum number of seconds to spend " +
+				"attempting routes for the payment",
+		},
+		cli.IntFlag{
+			Name: "max_shards",
+			Usage: "the maximum number of HTLCs the payment may " +
+				"be split into, 1 disables splitting",
+		},
+		cli.Uint64Flag{
+			Name: "final_cltv_delta",
+			Usage: "the time lock delta in blocks required by the " +
+				"destination for the final hop, ignored if a " +
+				"payment request is used",
+		},
+		customRecordsFlag,
+	}, routeRestrictionFlags...),
 	Action: sendPaymentCommand,
 }
 
@@ -635,6 +796,7 @@ func sendPaymentCommand(ctx *cli.Context) error {
 	if ctx.String("pay_req") != "" {
 		req = &lnrpc.SendRequest{
 			PaymentRequest: ctx.String("pay_req"),
+			Amt:            int64(ctx.Int("amt")),
 		}
 	} else {
 		destNode, err := hex.DecodeString(ctx.String("dest"))
@@ -647,11 +809,13 @@ func sendPaymentCommand(ctx *cli.Context) error {
 		}
 
 		req = &lnrpc.SendRequest{
-			Dest: destNode,
-			Amt:  int64(ctx.Int("amt")),
+			Dest:           destNode,
+			Amt:            int64(ctx.Int("amt")),
+			Keysend:        ctx.Bool("keysend"),
+			FinalCltvDelta: uint32(ctx.Uint64("final_cltv_delta")),
 		}
 
-		if !ctx.Bool("debug_send") {
+		if !ctx.Bool("debug_send") && !ctx.Bool("keysend") {
 			rHash, err := hex.DecodeString(ctx.String("payment_hash"))
 			if err != nil {
 				return err
@@ -664,6 +828,26 @@ func sendPaymentCommand(ctx *cli.Context) error {
 		}
 	}
 
+	req.TimeoutSeconds = int32(ctx.Int("timeout"))
+	req.MaxShards = uint32(ctx.Int("max_shards"))
+
+	customRecords, err := parseCustomRecords(ctx.String("data"))
+	if err != nil {
+		return err
+	}
+	req.DestCustomRecords = customRecords
+
+	req.FeeLimit = ctx.Int64("fee_limit")
+	req.CltvLimit = uint32(ctx.Uint64("cltv_limit"))
+	req.OutgoingChanId = ctx.Uint64("outgoing_chan_id")
+	req.LastHopPubkey, req.IgnoredNodes, err = parseRestrictedNodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, chanID := range ctx.Int64Slice("ignore_edge") {
+		req.IgnoredEdges = append(req.IgnoredEdges, uint64(chanID))
+	}
+
 	paymentStream, err := client.SendPayment(context.Background())
 	if err != nil {
 		return err
@@ -703,8 +887,35 @@ var AddInvoiceCommand = cli.Command{
 			Usage: "the hex-encoded preimage which will allow settling an incoming HTLC payable to this preimage",
 		},
 		cli.IntFlag{
-			Name:  "value",
-			Usage: "the value of this invoice in satoshis",
+			Name: "value",
+			Usage: "the value of this invoice in satoshis, 0 to " +
+				"let the payer choose the amount",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include route hints for our private channels " +
+				"within the payment request",
+		},
+		cli.StringFlag{
+			Name: "description_hash",
+			Usage: "the hex-encoded hash of a description of the " +
+				"payment, included in the payment request in " +
+				"place of the memo",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the number of seconds after which the payment " +
+				"request expires",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "an optional on-chain address the payer may " +
+				"use if the payment can't be made off-chain",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_expiry",
+			Usage: "the minimum CLTV expiry delta of the final hop " +
+				"of the payment",
 		},
 	},
 	Action: addInvoice,
@@ -724,11 +935,21 @@ func addInvoice(ctx *cli.Context) error {
 		return fmt.Errorf("unable to parse receipt: %v", err)
 	}
 
+	descHash, err := hex.DecodeString(ctx.String("description_hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse description_hash: %v", err)
+	}
+
 	invoice := &lnrpc.Invoice{
-		Memo:      ctx.String("memo"),
-		Receipt:   receipt,
-		RPreimage: preimage,
-		Value:     int64(ctx.Int("value")),
+		Memo:            ctx.String("memo"),
+		Receipt:         receipt,
+		RPreimage:       preimage,
+		Value:           int64(ctx.Int("value")),
+		Private:         ctx.Bool("private"),
+		DescriptionHash: descHash,
+		Expiry:          ctx.Int64("expiry"),
+		FallbackAddr:    ctx.String("fallback_addr"),
+		CltvExpiry:      ctx.Uint64("cltv_expiry"),
 	}
 
 	resp, err := client.AddInvoice(context.Background(), invoice)
@@ -747,6 +968,105 @@ func addInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+var AddHoldInvoiceCommand = cli.Command{
+	Name:        "addholdinvoice",
+	Description: "add a new hold invoice, whose payment is held until it's settled with the preimage of its payment hash",
+	Usage:       "addholdinvoice --hash=[32_byte_hash] --memo=[note] --value=[in_satoshis]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "hash",
+			Usage: "the hex-encoded payment hash of the invoice",
+		},
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "an optional memo to attach along with the invoice",
+		},
+		cli.StringFlag{
+			Name:  "receipt",
+			Usage: "an optional cryptographic receipt of payment",
+		},
+		cli.IntFlag{
+			Name: "value",
+			Usage: "the value of this invoice in satoshis, 0 to " +
+				"let the payer choose the amount",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include route hints for our private channels " +
+				"within the payment request",
+		},
+		cli.StringFlag{
+			Name: "description_hash",
+			Usage: "the hex-encoded hash of a description of the " +
+				"payment, included in the payment request in " +
+				"place of the memo",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the number of seconds after which the payment " +
+				"request expires",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "an optional on-chain address the payer may " +
+				"use if the payment can't be made off-chain",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_expiry",
+			Usage: "the minimum CLTV expiry delta of the final hop " +
+				"of the payment",
+		},
+	},
+	Action: addHoldInvoice,
+}
+
+func addHoldInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	rHash, err := hex.DecodeString(ctx.String("hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse hash: %v", err)
+	}
+
+	receipt, err := hex.DecodeString(ctx.String("receipt"))
+	if err != nil {
+		return fmt.Errorf("unable to parse receipt: %v", err)
+	}
+
+	descHash, err := hex.DecodeString(ctx.String("description_hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse description_hash: %v", err)
+	}
+
+	invoice := &lnrpc.Invoice{
+		Memo:            ctx.String("memo"),
+		Receipt:         receipt,
+		RHash:           rHash,
+		Value:           int64(ctx.Int("value")),
+		Private:         ctx.Bool("private"),
+		DescriptionHash: descHash,
+		Expiry:          ctx.Int64("expiry"),
+		FallbackAddr:    ctx.String("fallback_addr"),
+		CltvExpiry:      ctx.Uint64("cltv_expiry"),
+	}
+
+	resp, err := client.AddHoldInvoice(context.Background(), invoice)
+	if err != nil {
+		return err
+	}
+
+	printJson(struct {
+		RHash  string `json:"r_hash"`
+		PayReq string `json:"pay_req"`
+	}{
+		RHash:  hex.EncodeToString(resp.RHash),
+		PayReq: resp.PaymentRequest,
+	})
+
+	return nil
+}
+
 var LookupInvoiceCommand = cli.Command{
 	Name:        "lookupinvoice",
 	Description: "lookup an existing invoice by its payment hash",
@@ -784,15 +1104,119 @@ func lookupInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+var CancelInvoiceCommand = cli.Command{
+	Name:        "cancelinvoice",
+	Description: "cancel an open invoice, after which it no longer accepts payment",
+	Usage:       "cancelinvoice --rhash=[32_byte_hash]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the payment hash of the invoice to cancel, the hash " +
+				"should be a hex-encoded string",
+		},
+	},
+	Action: cancelInvoice,
+}
+
+func cancelInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	rHash, err := hex.DecodeString(ctx.String("rhash"))
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.PaymentHash{
+		RHash: rHash,
+	}
+
+	resp, err := client.CancelInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+
+	return nil
+}
+
+var SettleInvoiceCommand = cli.Command{
+	Name:        "settleinvoice",
+	Description: "settle an accepted hold invoice, settling the payment held for it",
+	Usage:       "settleinvoice --preimage=[32_byte_preimage]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "preimage",
+			Usage: "the hex-encoded preimage of the payment hash " +
+				"of the hold invoice to settle",
+		},
+	},
+	Action: settleInvoice,
+}
+
+func settleInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	preimage, err := hex.DecodeString(ctx.String("preimage"))
+	if err != nil {
+		return fmt.Errorf("unable to parse preimage: %v", err)
+	}
+
+	req := &lnrpc.SettleInvoiceRequest{
+		Preimage: preimage,
+	}
+
+	resp, err := client.SettleInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+
+	return nil
+}
+
 var ListInvoicesCommand = cli.Command{
 	Name:        "listinvoices",
-	Usage:       "listinvoice --pending_only=[true|false]",
+	Usage:       "listinvoice --pending_only=[true|false] --state=[open|settled|canceled|expired|accepted] --index_offset=[add_index] --max_invoices=[n] --reversed",
 	Description: "list all invoices currently stored",
 	Flags: []cli.Flag{
 		cli.BoolFlag{
 			Name: "pending_only",
 			Usage: "toggles if all invoices should be returned, or only " +
-				"those that are currently unsettled",
+				"those that are currently open",
+		},
+		cli.StringSliceFlag{
+			Name: "state",
+			Usage: "only return invoices in this state, may be " +
+				"specified multiple times",
+		},
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the add index of the invoice after which the " +
+				"page of invoices starts, or ends if reversed",
+		},
+		cli.Uint64Flag{
+			Name: "max_invoices",
+			Usage: "the maximum number of invoices to return, " +
+				"zero returns all invoices",
+		},
+		cli.BoolFlag{
+			Name: "reversed",
+			Usage: "return the page of most recently added " +
+				"invoices preceding the index offset",
+		},
+		cli.Int64Flag{
+			Name: "creation_date_start",
+			Usage: "only return invoices created at or after this " +
+				"unix timestamp",
+		},
+		cli.Int64Flag{
+			Name: "creation_date_end",
+			Usage: "only return invoices created at or before this " +
+				"unix timestamp",
 		},
 	},
 	Action: listInvoices,
@@ -808,7 +1232,19 @@ func listInvoices(ctx *cli.Context) error {
 	}
 
 	req := &lnrpc.ListInvoiceRequest{
-		PendingOnly: pendingOnly,
+		PendingOnly:       pendingOnly,
+		IndexOffset:       ctx.Uint64("index_offset"),
+		NumMaxInvoices:    ctx.Uint64("max_invoices"),
+		Reversed:          ctx.Bool("reversed"),
+		CreationDateStart: ctx.Int64("creation_date_start"),
+		CreationDateEnd:   ctx.Int64("creation_date_end"),
+	}
+	for _, state := range ctx.StringSlice("state") {
+		stateVal, ok := lnrpc.Invoice_InvoiceState_value[strings.ToUpper(state)]
+		if !ok {
+			return fmt.Errorf("unknown invoice state: %v", state)
+		}
+		req.States = append(req.States, lnrpc.Invoice_InvoiceState(stateVal))
 	}
 
 	invoices, err := client.ListInvoices(context.Background(), req)
@@ -1006,16 +1442,49 @@ func drawChannelGraph(graph *lnrpc.ChannelGraph) error {
 
 var ListPaymentsCommand = cli.Command{
 	Name:        "listpayments",
-	Usage:       "listpayments",
+	Usage:       "listpayments --index_offset=[payment_index] --max_payments=[n] --reversed",
 	Description: "list all outgoing payments",
-	Action:      listPayments,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the index of the payment after which the page " +
+				"of payments starts, or ends if reversed",
+		},
+		cli.Uint64Flag{
+			Name: "max_payments",
+			Usage: "the maximum number of payments to return, " +
+				"zero returns all payments",
+		},
+		cli.BoolFlag{
+			Name: "reversed",
+			Usage: "return the page of most recent payments " +
+				"preceding the index offset",
+		},
+		cli.Int64Flag{
+			Name: "creation_date_start",
+			Usage: "only return payments created at or after this " +
+				"unix timestamp",
+		},
+		cli.Int64Flag{
+			Name: "creation_date_end",
+			Usage: "only return payments created at or before this " +
+				"unix timestamp",
+		},
+	},
+	Action: listPayments,
 }
 
 func listPayments(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListPaymentsRequest{}
+	req := &lnrpc.ListPaymentsRequest{
+		IndexOffset:       ctx.Uint64("index_offset"),
+		MaxPayments:       ctx.Uint64("max_payments"),
+		Reversed:          ctx.Bool("reversed"),
+		CreationDateStart: ctx.Int64("creation_date_start"),
+		CreationDateEnd:   ctx.Int64("creation_date_end"),
+	}
 
 	payments, err := client.ListPayments(context.Background(), req)
 	if err != nil {
@@ -1095,7 +1564,7 @@ var QueryRouteCommand = cli.Command{
 	Name:        "queryroute",
 	Usage:       "queryroute --dest=[dest_pub_key] --amt=[amt_to_send_in_satoshis]",
 	Description: "queries the channel router for a potential path to the destination that has sufficient flow for the amount including fees",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		cli.StringFlag{
 			Name: "dest",
 			Usage: "the 33-byte hex-encoded public key for the payment " +
@@ -1105,7 +1574,13 @@ var QueryRouteCommand = cli.Command{
 			Name:  "amt",
 			Usage: "the amount to send expressed in satoshis",
 		},
-	},
+		cli.IntFlag{
+			Name: "num_routes",
+			Usage: "the max number of routes to return, sorted by " +
+				"total fee",
+			Value: 1,
+		},
+	}, routeRestrictionFlags...),
 	Action: queryRoute,
 }
 
@@ -1115,19 +1590,181 @@ func queryRoute(ctx *cli.Context) error {
 	defer cleanUp()
 
 	req := &lnrpc.RouteRequest{
-		PubKey: ctx.String("dest"),
-		Amt:    int64(ctx.Int("amt")),
+		PubKey:         ctx.String("dest"),
+		Amt:            int64(ctx.Int("amt")),
+		NumRoutes:      int32(ctx.Int("num_routes")),
+		FeeLimit:       ctx.Int64("fee_limit"),
+		CltvLimit:      uint32(ctx.Uint64("cltv_limit")),
+		OutgoingChanId: ctx.Uint64("outgoing_chan_id"),
 	}
 
-	route, err := client.QueryRoute(ctxb, req)
+	var err error
+	req.LastHopPubkey, req.IgnoredNodes, err = parseRestrictedNodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, chanID := range ctx.Int64Slice("ignore_edge") {
+		req.IgnoredEdges = append(req.IgnoredEdges, uint64(chanID))
+	}
+
+	routes, err := client.QueryRoute(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJson(route)
+	printRespJson(routes)
 	return nil
 }
 
+var BuildRouteCommand = cli.Command{
+	Name:  "buildroute",
+	Usage: "buildroute --amt=[amt_to_send_in_satoshis] --hops=[pub_key,pub_key,...]",
+	Description: "builds a route which travels through the specified " +
+		"hops in order, filling in the fees and time locks of each hop",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "amt",
+			Usage: "the amount to send expressed in satoshis",
+		},
+		cli.StringFlag{
+			Name: "hops",
+			Usage: "a comma separated list of the hex-encoded public " +
+				"keys of each hop, ending with the destination",
+		},
+	},
+	Action: buildRoute,
+}
+
+func buildRoute(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("hops") {
+		return fmt.Errorf("the hops of the route must be specified")
+	}
+
+	req := &lnrpc.BuildRouteRequest{
+		Amt:        int64(ctx.Int("amt")),
+		HopPubkeys: strings.Split(ctx.String("hops"), ","),
+	}
+
+	resp, err := client.BuildRoute(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+	return nil
+}
+
+var SendToRouteCommand = cli.Command{
+	Name:  "sendtoroute",
+	Usage: "sendtoroute --payment_hash=[hash] --route=[json_route] --data=[type=hex_value,...]",
+	Description: "sends a payment over an explicitly specified route, " +
+		"such as one returned by buildroute",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash, r",
+			Usage: "the hash to use within the payment's HTLC",
+		},
+		cli.StringFlag{
+			Name: "route",
+			Usage: "the JSON encoded route to send the payment " +
+				"over, as returned by buildroute",
+		},
+		customRecordsFlag,
+	},
+	Action: sendToRoute,
+}
+
+func sendToRoute(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("route") {
+		return fmt.Errorf("the route must be specified")
+	}
+
+	// The route may be passed either as the raw output of buildroute, or
+	// as a bare route.
+	route := &lnrpc.Route{}
+	buildResp := &lnrpc.BuildRouteResponse{}
+	err := jsonpb.UnmarshalString(ctx.String("route"), buildResp)
+	if err == nil && buildResp.Route != nil {
+		route = buildResp.Route
+	} else {
+		err := jsonpb.UnmarshalString(ctx.String("route"), route)
+		if err != nil {
+			return fmt.Errorf("unable to parse route: %v", err)
+		}
+	}
+
+	customRecords, err := parseCustomRecords(ctx.String("data"))
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.SendToRouteRequest{
+		PaymentHashString: ctx.String("payment_hash"),
+		Route:             route,
+		DestCustomRecords: customRecords,
+	}
+
+	resp, err := client.SendToRoute(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+	return nil
+}
+
+var TrackPaymentCommand = cli.Command{
+	Name:  "trackpayment",
+	Usage: "trackpayment --payment_hash=[hash]",
+	Description: "streams the state of an outgoing payment, starting " +
+		"with its current state, until it has either succeeded or " +
+		"failed",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash, r",
+			Usage: "the hash of the payment to track",
+		},
+	},
+	Action: trackPayment,
+}
+
+func trackPayment(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("payment_hash") {
+		return fmt.Errorf("the payment hash must be specified")
+	}
+
+	req := &lnrpc.TrackPaymentRequest{
+		PaymentHashString: ctx.String("payment_hash"),
+	}
+	stream, err := client.TrackPayment(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		status, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJson(status)
+	}
+}
+
 var GetNetworkInfoCommand = cli.Command{
 	Name:  "getnetworkinfo",
 	Usage: "getnetworkinfo",
@@ -1152,6 +1789,141 @@ func getNetworkInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var SubscribeChannelGraphCommand = cli.Command{
+	Name:  "subscribechannelgraph",
+	Usage: "subscribechannelgraph",
+	Description: "streams all changes to the known channel graph as " +
+		"they occur: node updates, channel edge updates including " +
+		"the prior routing policy, and closed channels",
+	Action: subscribeChannelGraph,
+}
+
+func subscribeChannelGraph(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GraphTopologySubscription{}
+	stream, err := client.SubscribeChannelGraph(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJson(update)
+	}
+}
+
+var QueryMissionControlCommand = cli.Command{
+	Name:  "querymc",
+	Usage: "querymc",
+	Description: "returns the payment history recorded by mission " +
+		"control for each node pair",
+	Action: queryMissionControl,
+}
+
+func queryMissionControl(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.QueryMissionControlRequest{}
+	resp, err := client.QueryMissionControl(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+	return nil
+}
+
+var ResetMissionControlCommand = cli.Command{
+	Name:        "resetmc",
+	Usage:       "resetmc",
+	Description: "clears all payment history recorded by mission control",
+	Action:      resetMissionControl,
+}
+
+func resetMissionControl(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ResetMissionControlRequest{}
+	if _, err := client.ResetMissionControl(ctxb, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var AutopilotStatusCommand = cli.Command{
+	Name:        "autopilotstatus",
+	Usage:       "autopilotstatus",
+	Description: "returns whether the autopilot agent is active, along with its configuration and the channels it's currently managing",
+	Action:      autopilotStatus,
+}
+
+func autopilotStatus(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.AutopilotStatusRequest{}
+	resp, err := client.AutopilotStatus(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJson(resp)
+	return nil
+}
+
+var SetAutopilotCommand = cli.Command{
+	Name:        "setautopilot",
+	Usage:       "setautopilot [--enable|--disable]",
+	Description: "enables or disables the autopilot agent, which automatically opens channels on behalf of the node",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "enable",
+			Usage: "enable the autopilot agent",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "disable the autopilot agent",
+		},
+	},
+	Action: setAutopilot,
+}
+
+func setAutopilot(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	enable, disable := ctx.Bool("enable"), ctx.Bool("disable")
+	if enable == disable {
+		return fmt.Errorf("exactly one of --enable or --disable " +
+			"must be set")
+	}
+
+	req := &lnrpc.SetAutopilotRequest{
+		Enable: enable,
+	}
+	if _, err := client.SetAutopilot(ctxb, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 var DebugLevel = cli.Command{
 	Name:        "debuglevel",
 	Usage:       "debuglevel [--show|--level=<level_spec>]",
@@ -60,13 +60,17 @@ func main() {
 		OpenChannelCommand,
 		CloseChannelCommand,
 		ListPeersCommand,
+		PeerLimitsCommand,
 		WalletBalanceCommand,
 		ChannelBalanceCommand,
 		GetInfoCommand,
 		PendingChannelsCommand,
 		SendPaymentCommand,
 		AddInvoiceCommand,
+		AddHoldInvoiceCommand,
 		LookupInvoiceCommand,
+		CancelInvoiceCommand,
+		SettleInvoiceCommand,
 		ListInvoicesCommand,
 		ListChannelsCommand,
 		ListPaymentsCommand,
@@ -74,7 +78,15 @@ func main() {
 		GetChanInfoCommand,
 		GetNodeInfoCommand,
 		QueryRouteCommand,
+		BuildRouteCommand,
+		SendToRouteCommand,
+		TrackPaymentCommand,
 		GetNetworkInfoCommand,
+		SubscribeChannelGraphCommand,
+		QueryMissionControlCommand,
+		ResetMissionControlCommand,
+		AutopilotStatusCommand,
+		SetAutopilotCommand,
 		DebugLevel,
 		DecodePayReq,
 	}
@@ -2,16 +2,21 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	flags "github.com/btcsuite/go-flags"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 )
@@ -30,6 +35,27 @@ const (
 	defaultRPCPass            = "passwd"
 	defaultSPVHostAdr         = "localhost:18333"
 	defaultMaxPendingChannels = 1
+	defaultHTLCBatchSize      = 10
+	defaultHTLCCommitInterval = 10 * time.Millisecond
+	defaultMPPTimeout         = time.Minute
+	defaultMaxOverpayment     = 1.0
+
+	defaultHTLCPeerRate            = 10
+	defaultHTLCPeerBurst           = 20
+	defaultHTLCChanRate            = 5
+	defaultHTLCChanBurst           = 10
+	defaultMaxPendingForwards      = 100
+	defaultHTLCHoldThreshold       = 5 * time.Minute
+	defaultHTLCReputationThreshold = 5
+
+	defaultAutopilotMaxChannels = 5
+	defaultAutopilotAllocation  = 0.6
+	defaultAutopilotMinChanSize = 20000
+	defaultAutopilotMaxChanSize = 16777215
+
+	defaultWebhookMaxAttempts = 20
+	defaultWebhookMinBackoff  = 5 * time.Second
+	defaultWebhookMaxBackoff  = time.Hour
 )
 
 var (
@@ -42,6 +68,27 @@ var (
 	defaultRPCCertFile = filepath.Join(btcdHomeDir, "rpc.cert")
 )
 
+// autoPilotConfig houses the configuration options for the autopilot agent,
+// which automatically opens channels on behalf of the node.
+type autoPilotConfig struct {
+	Active      bool    `long:"active" description:"If the autopilot agent should be active or not."`
+	MaxChannels int     `long:"maxchannels" description:"The maximum number of channels that should be created"`
+	Allocation  float64 `long:"allocation" description:"The percentage of total funds that should be committed to automatic channel establishment"`
+	MinChanSize int64   `long:"minchansize" description:"The smallest channel that the autopilot agent should create"`
+	MaxChanSize int64   `long:"maxchansize" description:"The largest channel that the autopilot agent should create"`
+}
+
+// webhooksConfig houses the configuration options for the webhook
+// dispatcher, which POSTs signed JSON notifications of invoice events to a set
+// of URLs.
+type webhooksConfig struct {
+	URLs        []string      `long:"url" description:"A URL to POST signed JSON notifications of added, settled, canceled and expired invoices to. May be specified multiple times. The dispatcher is only active if at least one URL is specified."`
+	Secret      string        `long:"secret" default-mask:"-" description:"The secret key with which each notification is signed using HMAC-SHA256. Required if any URLs are specified."`
+	MaxAttempts int           `long:"maxattempts" description:"The maximum number of attempts to deliver a notification to a URL before it's dropped."`
+	MinBackoff  time.Duration `long:"minbackoff" description:"The delay before a failed notification is retried, which doubles with each subsequent failure. Valid time units are {ms, s, m, h}."`
+	MaxBackoff  time.Duration `long:"maxbackoff" description:"The maximum delay before a failed notification is retried. Valid time units are {ms, s, m, h}."`
+}
+
 // config defines the configuration options for lnd.
 //
 // See loadConfig for further details regarding the configuration
@@ -71,6 +118,33 @@ type config struct {
 	SimNet             bool   `long:"simnet" description:"Use the simulation test network"`
 	DebugHTLC          bool   `long:"debughtlc" description:"Activate the debug htlc mode. With the debug HTLC mode, all payments sent use a pre-determined R-Hash. Additionally, all HTLCs sent to a node with the debug HTLC R-Hash are immediately settled in the next available state transition."`
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
+
+	HTLCBatchSize      int           `long:"htlcbatchsize" description:"The maximum number of pending HTLC updates accumulated within a channel before a new commitment update is forced."`
+	HTLCCommitInterval time.Duration `long:"htlccommitinterval" description:"The interval at which any pending HTLC updates within a channel are committed to if the batch size hasn't yet been reached. Valid time units are {ms, s, m, h}."`
+	MPPTimeout         time.Duration `long:"mpptimeout" description:"The duration to hold the received shards of a multi-path payment while waiting for the remainder of the payment to arrive, before cancelling them all. Valid time units are {ms, s, m, h}."`
+	MaxOverpayment     float64       `long:"maxoverpayment" description:"The maximum amount by which an invoice may be overpaid, as a multiple of its value. For example, a value of 1 accepts payments of up to twice the invoice's value. Invoices which don't request a specific amount accept payments of any amount."`
+	AcceptKeySend      bool          `long:"acceptkeysend" description:"Accept spontaneous keysend payments, which carry their preimage within the payment itself, by creating an invoice for each on the fly."`
+
+	HTLCPeerRate            float64       `long:"htlcpeerrate" description:"The number of HTLCs per second a peer may add across all its channels with us. A value of 0 disables the limit."`
+	HTLCPeerBurst           int           `long:"htlcpeerburst" description:"The maximum number of HTLCs a peer may add in a single burst across all its channels with us."`
+	HTLCChanRate            float64       `long:"htlcchanrate" description:"The number of HTLCs per second which may be added to a single channel. A value of 0 disables the limit."`
+	HTLCChanBurst           int           `long:"htlcchanburst" description:"The maximum number of HTLCs which may be added to a single channel in a single burst."`
+	MaxPendingForwards      int           `long:"maxpendingforwards" description:"The maximum number of HTLCs received over a single channel which may concurrently be pending resolution downstream. A value of 0 disables the limit."`
+	HTLCReputation          bool          `long:"htlcreputation" description:"Track the reputation of peers whose forwarded HTLCs hold our outgoing liquidity for long durations, rejecting new forwards from peers with a poor reputation."`
+	HTLCHoldThreshold       time.Duration `long:"htlcholdthreshold" description:"The duration after which a forwarded HTLC is considered to have been held for a long duration when reputation tracking is active. Valid time units are {ms, s, m, h}."`
+	HTLCReputationThreshold float64       `long:"htlcreputationthreshold" description:"The decaying count of long held HTLCs at which all new forwards from a peer are rejected when reputation tracking is active."`
+
+	PenaltyHalfLife       time.Duration `long:"penaltyhalflife" description:"The half-life of a payment failure recorded by mission control. Valid time units are {ms, s, m, h}."`
+	PersistMissionControl bool          `long:"persistmissioncontrol" description:"Persist the payment history recorded by mission control to disk so it survives restarts."`
+	AttemptCost           int64         `long:"attemptcost" description:"The virtual cost in satoshis of an additional payment attempt. When non-zero, path finding will pay up to this amount in extra fees to avoid node pairs which have recently failed. A value of 0 disables the penalty."`
+
+	ChanPruneExpiry time.Duration `long:"chanpruneexpiry" description:"The duration after which a channel for which neither node has sent an update is considered a zombie, and pruned from the channel graph. A zombie channel is resurrected once a fresh update for it is received. Valid time units are {ms, s, m, h}."`
+
+	NumGraphSyncPeers int `long:"numgraphsyncpeers" description:"The number of peers with which we'll actively reconcile our channel graph, and from which we'll receive new graph updates. The graph is only passively synced with all other peers."`
+
+	Autopilot *autoPilotConfig `group:"autopilot" namespace:"autopilot"`
+
+	Webhooks *webhooksConfig `group:"webhooks" namespace:"webhooks"`
 }
 
 // loadConfig initializes and parses the config using a config file and command
@@ -94,6 +168,38 @@ func loadConfig() (*config, error) {
 		RPCPass:            defaultRPCPass,
 		RPCCert:            defaultRPCCertFile,
 		MaxPendingChannels: defaultMaxPendingChannels,
+		HTLCBatchSize:      defaultHTLCBatchSize,
+		HTLCCommitInterval: defaultHTLCCommitInterval,
+		MPPTimeout:         defaultMPPTimeout,
+		MaxOverpayment:     defaultMaxOverpayment,
+
+		HTLCPeerRate:            defaultHTLCPeerRate,
+		HTLCPeerBurst:           defaultHTLCPeerBurst,
+		HTLCChanRate:            defaultHTLCChanRate,
+		HTLCChanBurst:           defaultHTLCChanBurst,
+		MaxPendingForwards:      defaultMaxPendingForwards,
+		HTLCHoldThreshold:       defaultHTLCHoldThreshold,
+		HTLCReputationThreshold: defaultHTLCReputationThreshold,
+
+		PenaltyHalfLife: routing.DefaultPenaltyHalfLife,
+		AttemptCost:     int64(routing.DefaultAttemptCost),
+
+		ChanPruneExpiry: routing.DefaultChannelPruneExpiry,
+
+		NumGraphSyncPeers: discovery.DefaultNumActiveSyncers,
+
+		Autopilot: &autoPilotConfig{
+			MaxChannels: defaultAutopilotMaxChannels,
+			Allocation:  defaultAutopilotAllocation,
+			MinChanSize: defaultAutopilotMinChanSize,
+			MaxChanSize: defaultAutopilotMaxChanSize,
+		},
+
+		Webhooks: &webhooksConfig{
+			MaxAttempts: defaultWebhookMaxAttempts,
+			MinBackoff:  defaultWebhookMinBackoff,
+			MaxBackoff:  defaultWebhookMaxBackoff,
+		},
 	}
 
 	// Pre-parse the command line options to pick up an alternative config
@@ -174,6 +280,166 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	// Ensure the HTLC batching parameters are sane. A batch must hold at
+	// least a single update, and the commit ticker must fire at a
+	// positive interval.
+	if cfg.HTLCBatchSize < 1 {
+		str := "%s: The htlcbatchsize must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.HTLCCommitInterval <= 0 {
+		str := "%s: The htlccommitinterval must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Shards of multi-path payments must be held for some positive
+	// duration, otherwise they could never be reassembled.
+	if cfg.MPPTimeout <= 0 {
+		str := "%s: The mpptimeout must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Invoices may never accept less than their value.
+	if cfg.MaxOverpayment < 0 {
+		str := "%s: The maxoverpayment must be non-negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// The HTLC rate limits must be non-negative, and any enabled rate
+	// limit must permit a burst of at least a single HTLC.
+	switch {
+	case cfg.HTLCPeerRate < 0 || cfg.HTLCChanRate < 0:
+		str := "%s: The htlcpeerrate and htlcchanrate must be " +
+			"non-negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case (cfg.HTLCPeerRate > 0 && cfg.HTLCPeerBurst < 1) ||
+		(cfg.HTLCChanRate > 0 && cfg.HTLCChanBurst < 1):
+		str := "%s: The htlcpeerburst and htlcchanburst must be at " +
+			"least 1 if the corresponding rate limit is active"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.PenaltyHalfLife <= 0:
+		str := "%s: The penaltyhalflife must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.AttemptCost < 0:
+		str := "%s: The attemptcost must be non-negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.ChanPruneExpiry <= 0:
+		str := "%s: The chanpruneexpiry must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.NumGraphSyncPeers < 1:
+		str := "%s: The numgraphsyncpeers must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.MaxPendingForwards < 0:
+		str := "%s: The maxpendingforwards must be non-negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.Autopilot.MaxChannels < 0 ||
+		cfg.Autopilot.MaxChannels > math.MaxUint16:
+
+		str := "%s: The autopilot.maxchannels must be between 0 " +
+			"and %d"
+		err := fmt.Errorf(str, funcName, math.MaxUint16)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.Autopilot.Allocation < 0 || cfg.Autopilot.Allocation > 1:
+		str := "%s: The autopilot.allocation must be between 0 and 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.Autopilot.MinChanSize < 0 ||
+		cfg.Autopilot.MaxChanSize < cfg.Autopilot.MinChanSize:
+
+		str := "%s: The autopilot.minchansize must be non-negative, " +
+			"and no larger than the autopilot.maxchansize"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case len(cfg.Webhooks.URLs) != 0 && cfg.Webhooks.Secret == "":
+		str := "%s: The webhooks.secret must be specified along with " +
+			"the webhooks.url"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.Webhooks.MaxAttempts < 1:
+		str := "%s: The webhooks.maxattempts must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+
+	case cfg.Webhooks.MinBackoff <= 0 ||
+		cfg.Webhooks.MaxBackoff < cfg.Webhooks.MinBackoff:
+
+		str := "%s: The webhooks.minbackoff must be positive, and no " +
+			"larger than the webhooks.maxbackoff"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Each webhook URL must be an absolute HTTP or HTTPS URL.
+	for _, webhookURL := range cfg.Webhooks.URLs {
+		u, err := url.Parse(webhookURL)
+		if err != nil || u.Host == "" ||
+			(u.Scheme != "http" && u.Scheme != "https") {
+
+			str := "%s: The webhooks.url %v must be an absolute " +
+				"http or https URL"
+			err := fmt.Errorf(str, funcName, webhookURL)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
 	// Append the network type to the data directory so it is "namespaced"
 	// per network. In addition to the block database, there are other
 	// pieces of data that are saved to disk such as address manager state.
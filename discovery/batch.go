@@ -0,0 +1,96 @@
+package discovery
+
+import "github.com/lightningnetwork/lnd/lnwire"
+
+// deDupedAnnouncements de-duplicates announcements that have been added to
+// the batch. Internally, announcements are stored in three maps (one each for
+// channel announcements, channel updates, and node announcements). These maps
+// keep track of unique announcements and ensure no announcements are
+// duplicated.
+type deDupedAnnouncements struct {
+	// channelAnnouncements are identified by the channel ID.
+	channelAnnouncements map[uint64]*lnwire.ChannelAnnouncement
+
+	// channelUpdates are identified by the channel ID and the direction
+	// of the update.
+	channelUpdates map[updateKey]*lnwire.ChannelUpdateAnnouncement
+
+	// nodeAnnouncements are identified by the serialized public key of
+	// the node.
+	nodeAnnouncements map[[33]byte]*lnwire.NodeAnnouncement
+}
+
+// newDeDupedAnnouncements returns a new, empty set of de-duplicated
+// announcements.
+func newDeDupedAnnouncements() *deDupedAnnouncements {
+	d := &deDupedAnnouncements{}
+	d.Reset()
+	return d
+}
+
+// Reset operates on deDupedAnnouncements to reset the storage of
+// announcements.
+func (d *deDupedAnnouncements) Reset() {
+	d.channelAnnouncements = make(map[uint64]*lnwire.ChannelAnnouncement)
+	d.channelUpdates = make(map[updateKey]*lnwire.ChannelUpdateAnnouncement)
+	d.nodeAnnouncements = make(map[[33]byte]*lnwire.NodeAnnouncement)
+}
+
+// AddMsg adds a new message to the current batch. If a message of the same
+// kind, describing the same entity, is already within the batch, then only
+// the most recent of the two is kept.
+func (d *deDupedAnnouncements) AddMsg(message lnwire.Message) {
+	switch msg := message.(type) {
+
+	// Channel announcements are idempotent, so the latest one simply
+	// replaces any prior announcement of the same channel.
+	case *lnwire.ChannelAnnouncement:
+		d.channelAnnouncements[msg.ChannelID.ToUint64()] = msg
+
+	// Channel updates are keyed by the channel and the direction they
+	// describe, keeping the update with the most recent timestamp.
+	case *lnwire.ChannelUpdateAnnouncement:
+		key := updateKey{
+			chanID: msg.ChannelID.ToUint64(),
+			flags:  msg.Flags,
+		}
+
+		old, ok := d.channelUpdates[key]
+		if !ok || old.Timestamp < msg.Timestamp {
+			d.channelUpdates[key] = msg
+		}
+
+	// Node announcements are keyed by the node's identity key, keeping
+	// the announcement with the most recent timestamp.
+	case *lnwire.NodeAnnouncement:
+		var key [33]byte
+		copy(key[:], msg.NodeID.SerializeCompressed())
+
+		old, ok := d.nodeAnnouncements[key]
+		if !ok || old.Timestamp < msg.Timestamp {
+			d.nodeAnnouncements[key] = msg
+		}
+	}
+}
+
+// Emit returns the set of de-duplicated announcements to be sent out during
+// the next announcement epoch. Channel announcements are placed first, as
+// peers must know of a channel before they're able to process any updates
+// for it.
+func (d *deDupedAnnouncements) Emit() []lnwire.Message {
+	numAnnouncements := len(d.channelAnnouncements) +
+		len(d.channelUpdates) + len(d.nodeAnnouncements)
+	announcements := make([]lnwire.Message, 0, numAnnouncements)
+
+	for _, msg := range d.channelAnnouncements {
+		announcements = append(announcements, msg)
+	}
+	for _, msg := range d.channelUpdates {
+		announcements = append(announcements, msg)
+	}
+	for _, msg := range d.nodeAnnouncements {
+		announcements = append(announcements, msg)
+	}
+
+	return announcements
+}
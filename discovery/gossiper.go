@@ -0,0 +1,1445 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// DefaultTrickleDelay is the default period of time between flushes
+	// of the batch of new announcements to our connected peers.
+	DefaultTrickleDelay = time.Millisecond * 300
+
+	// DefaultRetransmitDelay is the default period of time after which
+	// the announcements for our own channels are re-broadcast to our
+	// connected peers.
+	DefaultRetransmitDelay = time.Minute * 30
+
+	// DefaultChannelUpdateInterval is the default length of the window
+	// within which the number of channel updates accepted for each
+	// direction of a channel is limited.
+	DefaultChannelUpdateInterval = time.Minute
+
+	// DefaultMaxChannelUpdateBurst is the default number of channel
+	// updates we'll accept for each direction of a channel within a
+	// single ChannelUpdateInterval.
+	DefaultMaxChannelUpdateBurst = 10
+
+	// DefaultNumActiveSyncers is the default number of peers for which
+	// we'll actively reconcile our channel graph.
+	DefaultNumActiveSyncers = 3
+
+	// maxPendingProofs is the maximum number of remote halves of channel
+	// announcement proofs we'll hold on to while waiting for the
+	// corresponding local channel announcement.
+	maxPendingProofs = 1000
+)
+
+var (
+	// ErrGossiperShuttingDown is returned for any announcements which
+	// are sent to the gossiper after it has been signalled to exit.
+	ErrGossiperShuttingDown = fmt.Errorf("gossiper is shutting down")
+)
+
+// Config defines the configuration for the AuthenticatedGossiper. ALL
+// elements within the configuration MUST be non-nil for the gossiper to carry
+// out its duties.
+type Config struct {
+	// Router is the subsystem which is responsible for managing the
+	// topology of the lightning network. Once incoming node, channel and
+	// channel update announcements have been fully validated, they're sent
+	// to the router in order to be included within the channel graph.
+	Router routing.ChannelGraphSource
+
+	// Graph is the channel graph which the gossiper consults in order to
+	// determine whether announcements are new from our PoV. The graph is
+	// also used to persist the authentication proofs of announced
+	// channels so they can be relayed to newly connected peers.
+	Graph *channeldb.ChannelGraph
+
+	// Chain is the gossiper's source to the most up-to-date blockchain
+	// data. All incoming advertised channels are checked against the
+	// chain to ensure that the funding output exists, pays to the
+	// advertised 2-of-2 multi-sig script, and is still unspent.
+	Chain lnwallet.BlockChainIO
+
+	// Notifier is an instance of the ChainNotifier which the gossiper
+	// uses to receive notifications of incoming blocks. With each new
+	// block, any announcements which were premature as of the prior
+	// block are processed once again.
+	Notifier chainntnfs.ChainNotifier
+
+	// SendToPeer is a function which allows the gossiper to send a set of
+	// messages to a particular peer identified by the target public key.
+	// All gossip is sent through the sync state of each peer, ensuring
+	// peers only receive the announcements within their update horizon.
+	SendToPeer func(target *btcec.PublicKey, msg ...lnwire.Message) error
+
+	// NumActiveSyncers is the number of peers for which we'll actively
+	// reconcile our channel graph and from which we'll receive new
+	// updates. The sync state with all other peers is passive. If zero,
+	// then DefaultNumActiveSyncers is used.
+	NumActiveSyncers int
+
+	// MaxChanQueryChunk is the maximum number of channels included within
+	// a single channel range reply or short channel ID query. If zero,
+	// then DefaultMaxChanQueryChunk is used.
+	MaxChanQueryChunk int
+
+	// TrickleDelay is the period of time between flushes of the batch of
+	// new announcements to our connected peers. If zero, then
+	// DefaultTrickleDelay is used.
+	TrickleDelay time.Duration
+
+	// RetransmitDelay is the period of time after which the announcements
+	// for our own channels are re-broadcast to our connected peers. If
+	// zero, then DefaultRetransmitDelay is used.
+	RetransmitDelay time.Duration
+
+	// ChannelUpdateInterval is the length of the window within which the
+	// number of channel updates accepted for each direction of a channel
+	// is limited to MaxChannelUpdateBurst. If zero, then
+	// DefaultChannelUpdateInterval is used.
+	ChannelUpdateInterval time.Duration
+
+	// MaxChannelUpdateBurst is the number of channel updates we'll accept
+	// for each direction of a channel within a single
+	// ChannelUpdateInterval. If zero, then DefaultMaxChannelUpdateBurst
+	// is used.
+	MaxChannelUpdateBurst uint32
+
+	// ChannelPruneExpiry is the duration after which a channel for which
+	// neither node has sent an update is considered a zombie. A zombie
+	// channel is only resurrected once an update within this horizon is
+	// received for it. If zero, then routing.DefaultChannelPruneExpiry is
+	// used.
+	ChannelPruneExpiry time.Duration
+}
+
+// networkMsg couples a routing related wire message with the peer that
+// originally sent it.
+type networkMsg struct {
+	msg      lnwire.Message
+	isRemote bool
+	peer     *btcec.PublicKey
+
+	err chan error
+}
+
+// updateKey uniquely identifies a single direction of a channel.
+type updateKey struct {
+	chanID uint64
+	flags  uint16
+}
+
+// updateWindow tracks the number of channel updates accepted for a single
+// direction of a channel within the current rate limiting window.
+type updateWindow struct {
+	start time.Time
+	count uint32
+}
+
+// AuthenticatedGossiper is a subsystem which is responsible for receiving
+// announcements, validating them and applying the changes to the router,
+// syncing the lightning network with newly connected nodes, and broadcasting
+// our own channels to the rest of the network. Every announcement is fully
+// authenticated before it's accepted: all signatures must be valid, and any
+// channel must be backed by an unspent 2-of-2 funding output on chain.
+type AuthenticatedGossiper struct {
+	started uint32
+	stopped uint32
+
+	// cfg is a copy of the configuration struct that the gossiper was
+	// initialized with.
+	cfg *Config
+
+	// selfKey is the identity public key of the backing lightning node.
+	selfKey *btcec.PublicKey
+
+	// newBlocks is a channel in which new blocks connected to the end of
+	// the main chain are sent over.
+	newBlocks <-chan *chainntnfs.BlockEpoch
+
+	// networkMsgs is a channel that carries new network broadcasted
+	// messages from outside the gossiper to the main networkHandler.
+	networkMsgs chan *networkMsg
+
+	// bestHeight is the height of the block at the tip of the main chain
+	// as we know it.
+	bestHeight uint32
+
+	// prematureAnnouncements maps a block height to a set of
+	// announcements which are "premature" from our PoV. An announcement
+	// is premature if it claims to be anchored in a block which is beyond
+	// the current main chain tip as we know it. Premature announcements
+	// will be processed once the chain tip as we know it extends to/past
+	// the premature height.
+	//
+	// TODO(roasbeef): limit premature announcements to N
+	prematureAnnouncements map[uint32][]*networkMsg
+
+	// localChanAnns houses the announcements for our own channels which
+	// are waiting on the remote party's half of the announcement proof.
+	//
+	// TODO(roasbeef): persist to survive restarts
+	localChanAnns map[uint64]*lnwire.ChannelAnnouncement
+
+	// localProofs and remoteProofs house our, and the remote party's,
+	// half of the announcement proof for our own channels. Once both
+	// halves have been gathered, the full channel announcement can be
+	// assembled and broadcast.
+	localProofs  map[uint64]*lnwire.AnnounceSignatures
+	remoteProofs map[uint64]*lnwire.AnnounceSignatures
+
+	// syncerMtx guards the set of gossip syncers.
+	syncerMtx sync.RWMutex
+
+	// peerSyncers keeps track of the gossip syncer of each connected peer,
+	// keyed by the peer's serialized public key.
+	peerSyncers map[[33]byte]*gossipSyncer
+
+	// updateWindows tracks the number of channel updates we've accepted
+	// within the current window for each direction of each channel. It's
+	// used to rate limit channel updates.
+	updateWindows map[updateKey]*updateWindow
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a new AuthenticatedGossiper instance, initialized with the
+// passed configuration parameters.
+func New(cfg Config) (*AuthenticatedGossiper, error) {
+	selfNode, err := cfg.Graph.SourceNode()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.TrickleDelay == 0 {
+		cfg.TrickleDelay = DefaultTrickleDelay
+	}
+	if cfg.RetransmitDelay == 0 {
+		cfg.RetransmitDelay = DefaultRetransmitDelay
+	}
+	if cfg.ChannelUpdateInterval == 0 {
+		cfg.ChannelUpdateInterval = DefaultChannelUpdateInterval
+	}
+	if cfg.MaxChannelUpdateBurst == 0 {
+		cfg.MaxChannelUpdateBurst = DefaultMaxChannelUpdateBurst
+	}
+	if cfg.NumActiveSyncers == 0 {
+		cfg.NumActiveSyncers = DefaultNumActiveSyncers
+	}
+	if cfg.MaxChanQueryChunk == 0 {
+		cfg.MaxChanQueryChunk = DefaultMaxChanQueryChunk
+	}
+	if cfg.ChannelPruneExpiry == 0 {
+		cfg.ChannelPruneExpiry = routing.DefaultChannelPruneExpiry
+	}
+
+	return &AuthenticatedGossiper{
+		cfg:                    &cfg,
+		selfKey:                selfNode.PubKey,
+		networkMsgs:            make(chan *networkMsg),
+		peerSyncers:            make(map[[33]byte]*gossipSyncer),
+		prematureAnnouncements: make(map[uint32][]*networkMsg),
+		localChanAnns:          make(map[uint64]*lnwire.ChannelAnnouncement),
+		localProofs:            make(map[uint64]*lnwire.AnnounceSignatures),
+		remoteProofs:           make(map[uint64]*lnwire.AnnounceSignatures),
+		updateWindows:          make(map[updateKey]*updateWindow),
+		quit:                   make(chan struct{}),
+	}, nil
+}
+
+// Start spawns network messages handler goroutine and registers on new block
+// notifications in order to properly handle the premature announcements.
+func (d *AuthenticatedGossiper) Start() error {
+	if !atomic.CompareAndSwapUint32(&d.started, 0, 1) {
+		return nil
+	}
+
+	log.Info("Authenticated Gossiper is starting")
+
+	// First we register for new notifications of newly discovered blocks.
+	// We do this immediately so we'll later be able to consume any/all
+	// blocks which were discovered.
+	blockEpochs, err := d.cfg.Notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+	d.newBlocks = blockEpochs.Epochs
+
+	_, height, err := d.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	d.bestHeight = uint32(height)
+
+	d.wg.Add(1)
+	go d.networkHandler()
+
+	return nil
+}
+
+// Stop signals any active goroutines for a graceful closure. This method
+// will *block* until all goroutines have exited.
+func (d *AuthenticatedGossiper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&d.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Info("Authenticated Gossiper is stopping")
+
+	close(d.quit)
+	d.wg.Wait()
+
+	d.syncerMtx.Lock()
+	for _, syncer := range d.peerSyncers {
+		syncer.Stop()
+	}
+	d.syncerMtx.Unlock()
+
+	return nil
+}
+
+// ProcessRemoteAnnouncement sends a new remote announcement message along
+// with the peer that sent the routing message. The announcement will be
+// fully validated, then added to a queue for batched trickled announcement
+// to all connected peers. The returned channel will receive the result of
+// processing the announcement.
+func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
+	src *btcec.PublicKey) chan error {
+
+	errChan := make(chan error, 1)
+
+	// Queries concerning the sync state with the peer are handed off
+	// directly to the peer's gossip syncer, as they don't modify the
+	// channel graph.
+	switch msg.(type) {
+	case *lnwire.QueryChannelRange,
+		*lnwire.ReplyChannelRange,
+		*lnwire.QueryShortChanIDs,
+		*lnwire.ReplyShortChanIDsEnd,
+		*lnwire.GossipTimestampRange:
+
+		syncer, ok := d.findSyncer(src)
+		if !ok {
+			errChan <- fmt.Errorf("no sync state for peer %x",
+				src.SerializeCompressed())
+			return errChan
+		}
+
+		syncer.ProcessQueryMsg(msg)
+		errChan <- nil
+		return errChan
+	}
+
+	return d.processAnnouncement(&networkMsg{
+		msg:      msg,
+		isRemote: true,
+		peer:     src,
+		err:      make(chan error, 1),
+	})
+}
+
+// ProcessLocalAnnouncement sends a new announcement created by our own node
+// to the gossiper. Channel announcements for our own channels are only
+// broadcast once the remote party's half of the announcement proof has been
+// received. The returned channel will receive the result of processing the
+// announcement.
+func (d *AuthenticatedGossiper) ProcessLocalAnnouncement(msg lnwire.Message,
+	src *btcec.PublicKey) chan error {
+
+	return d.processAnnouncement(&networkMsg{
+		msg:      msg,
+		isRemote: false,
+		peer:     src,
+		err:      make(chan error, 1),
+	})
+}
+
+// processAnnouncement hands the network message off to the networkHandler,
+// returning the channel over which the result of processing it is sent.
+func (d *AuthenticatedGossiper) processAnnouncement(nMsg *networkMsg) chan error {
+	select {
+	case d.networkMsgs <- nMsg:
+	case <-d.quit:
+		nMsg.err <- ErrGossiperShuttingDown
+	}
+
+	return nMsg.err
+}
+
+// ProcessPrivateChannel adds a newly created private channel, described by its
+// channel announcement and our half of the routing policy, to the channel
+// graph. The channel is available for use during path finding, but neither
+// announcement will ever be broadcast to the network.
+func (d *AuthenticatedGossiper) ProcessPrivateChannel(
+	chanAnn *lnwire.ChannelAnnouncement,
+	edgeUpdate *lnwire.ChannelUpdateAnnouncement,
+	src *btcec.PublicKey) error {
+
+	// We mark the channel as private before processing the
+	// announcements, ensuring the network handler never adds them to a
+	// broadcast batch.
+	chanID := chanAnn.ChannelID.ToUint64()
+	if err := d.cfg.Graph.MarkChannelPrivate(chanID); err != nil {
+		return err
+	}
+
+	if err := <-d.ProcessLocalAnnouncement(chanAnn, src); err != nil {
+		return err
+	}
+	return <-d.ProcessLocalAnnouncement(edgeUpdate, src)
+}
+
+// InitSyncState is called by outside sub-systems when a connection is
+// established to a new peer. A gossip syncer is created for the peer, which
+// answers its queries and relays to it the gossip within its update horizon.
+// If we have fewer than NumActiveSyncers active syncers, then we'll also
+// reconcile our channel graph with the peer, and request new updates from it.
+func (d *AuthenticatedGossiper) InitSyncState(peer *btcec.PublicKey) {
+	var peerPub [33]byte
+	copy(peerPub[:], peer.SerializeCompressed())
+
+	d.syncerMtx.Lock()
+	defer d.syncerMtx.Unlock()
+
+	// If we already have a syncer for this peer, then we can exit early.
+	if _, ok := d.peerSyncers[peerPub]; ok {
+		return
+	}
+
+	syncType := PassiveSync
+	if d.numActiveSyncers() < d.cfg.NumActiveSyncers {
+		syncType = ActiveSync
+	}
+
+	log.Infof("Creating new gossipSyncer for peer=%x, sync_type=%v",
+		peerPub[:], syncType)
+
+	syncer := newGossipSyncer(gossipSyncerCfg{
+		peerPub: peer,
+		graph:   d.cfg.Graph,
+		bestHeight: func() (uint32, error) {
+			_, height, err := d.cfg.Chain.GetBestBlock()
+			return uint32(height), err
+		},
+		chunkSize:       d.cfg.MaxChanQueryChunk,
+		chanPruneExpiry: d.cfg.ChannelPruneExpiry,
+		sendToPeer: func(msgs ...lnwire.Message) error {
+			return d.cfg.SendToPeer(peer, msgs...)
+		},
+	}, syncType)
+	d.peerSyncers[peerPub] = syncer
+
+	syncer.Start()
+}
+
+// PruneSyncState is called by outside sub-systems once a peer that we were
+// previously connected to has been disconnected. In this case we can stop the
+// existing gossip syncer we used to sync with the peer. If it was an active
+// syncer, then a passive syncer is promoted in its place.
+func (d *AuthenticatedGossiper) PruneSyncState(peer *btcec.PublicKey) {
+	var peerPub [33]byte
+	copy(peerPub[:], peer.SerializeCompressed())
+
+	d.syncerMtx.Lock()
+	defer d.syncerMtx.Unlock()
+
+	syncer, ok := d.peerSyncers[peerPub]
+	if !ok {
+		return
+	}
+
+	log.Infof("Removing gossipSyncer for peer=%x", peerPub[:])
+
+	delete(d.peerSyncers, peerPub)
+
+	// The syncer may be blocked sending a message to the now disconnected
+	// peer, so we'll stop it without waiting.
+	go syncer.Stop()
+
+	if syncer.SyncType() != ActiveSync {
+		return
+	}
+
+	// As an active syncer has been removed, we'll promote one of the
+	// passive syncers to take its place.
+	for pub, candidate := range d.peerSyncers {
+		if candidate.SyncType() != PassiveSync {
+			continue
+		}
+
+		log.Infof("Promoting gossipSyncer for peer=%x to %v", pub[:],
+			ActiveSync)
+
+		go candidate.ProcessSyncTransition(ActiveSync)
+		break
+	}
+}
+
+// numActiveSyncers returns the number of syncers which are actively syncing
+// with their peer.
+//
+// NOTE: The syncerMtx MUST be held when calling this method.
+func (d *AuthenticatedGossiper) numActiveSyncers() int {
+	var numActive int
+	for _, syncer := range d.peerSyncers {
+		if syncer.SyncType() == ActiveSync {
+			numActive++
+		}
+	}
+	return numActive
+}
+
+// findSyncer returns the gossip syncer of the target peer, if one exists.
+func (d *AuthenticatedGossiper) findSyncer(peer *btcec.PublicKey) (*gossipSyncer, bool) {
+	var peerPub [33]byte
+	copy(peerPub[:], peer.SerializeCompressed())
+
+	d.syncerMtx.RLock()
+	syncer, ok := d.peerSyncers[peerPub]
+	d.syncerMtx.RUnlock()
+
+	return syncer, ok
+}
+
+// sendToSyncers relays the passed set of announcements to each connected
+// peer through its gossip syncer, which ensures only the announcements
+// within the peer's update horizon are sent.
+func (d *AuthenticatedGossiper) sendToSyncers(msgs ...lnwire.Message) {
+	d.syncerMtx.RLock()
+	syncers := make([]*gossipSyncer, 0, len(d.peerSyncers))
+	for _, syncer := range d.peerSyncers {
+		syncers = append(syncers, syncer)
+	}
+	d.syncerMtx.RUnlock()
+
+	for _, syncer := range syncers {
+		syncer.FilterGossipMsgs(msgs...)
+	}
+}
+
+// networkHandler is the primary goroutine that drives this service. The roles
+// of this goroutine includes answering queries related to the state of the
+// network, syncing up newly connected peers, and also periodically
+// broadcasting our latest topology state to all connected peers.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *AuthenticatedGossiper) networkHandler() {
+	defer d.wg.Done()
+
+	announcements := newDeDupedAnnouncements()
+
+	trickleTimer := time.NewTicker(d.cfg.TrickleDelay)
+	defer trickleTimer.Stop()
+
+	retransmitTimer := time.NewTicker(d.cfg.RetransmitDelay)
+	defer retransmitTimer.Stop()
+
+	for {
+		select {
+		// A new network message has just arrived. After it's been
+		// fully validated, we'll modify the channel graph accordingly
+		// and add any resulting announcements to our next batch.
+		case nMsg := <-d.networkMsgs:
+			for _, ann := range d.processNetworkAnnouncement(nMsg) {
+				announcements.AddMsg(ann)
+			}
+
+		// A new block has arrived, so we can re-process the
+		// previously premature announcements.
+		case newBlock, ok := <-d.newBlocks:
+			// If the channel has been closed, then this indicates
+			// the daemon is shutting down, so we exit ourselves.
+			if !ok {
+				return
+			}
+
+			// Once a new block arrives, we update our running
+			// track of the height of the chain tip.
+			blockHeight := uint32(newBlock.Height)
+			d.bestHeight = blockHeight
+
+			// Next we check if we have any premature announcements
+			// for this height, if so, then we process them once
+			// more as normal announcements.
+			prematureAnns := d.prematureAnnouncements[blockHeight]
+			if len(prematureAnns) != 0 {
+				log.Infof("Re-processing %v premature "+
+					"announcements for height %v",
+					len(prematureAnns), blockHeight)
+			}
+
+			for _, nMsg := range prematureAnns {
+				for _, ann := range d.processNetworkAnnouncement(nMsg) {
+					announcements.AddMsg(ann)
+				}
+			}
+			delete(d.prematureAnnouncements, blockHeight)
+
+		// The trickle timer has ticked, which indicates we should
+		// flush to the network the pending batch of new announcements
+		// we've received since the last trickle tick.
+		case <-trickleTimer.C:
+			batch := announcements.Emit()
+
+			// If the current announcement batch is empty, then we
+			// have no further work here.
+			if len(batch) == 0 {
+				continue
+			}
+
+			log.Infof("Broadcasting batch of %v new announcements",
+				len(batch))
+
+			// If we have new things to announce then relay them
+			// to all our immediately connected peers, filtered by
+			// their update horizons.
+			d.sendToSyncers(batch...)
+
+			// With the batch sent, we reset it for a new round of
+			// announcements.
+			announcements.Reset()
+
+		// The retransmission timer has ticked which indicates that we
+		// should broadcast our personal channels to the network. This
+		// addresses the case of channel advertisements either being
+		// dropped, or not properly propagated through the network.
+		case <-retransmitTimer.C:
+			if err := d.retransmitChannels(); err != nil {
+				log.Errorf("unable to re-broadcast "+
+					"channels: %v", err)
+			}
+
+			// We'll also take this opportunity to purge any rate
+			// limiting windows which have since expired.
+			d.pruneUpdateWindows()
+
+		// The gossiper has been signalled to exit, to we exit our main
+		// loop so the wait group can be decremented.
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// processNetworkAnnouncement processes a new network related authenticated
+// channel or node announcement or an announcement proof. If the announcement
+// didn't affect the internal state due to either being out of date, invalid,
+// or redundant, then nil is returned. Otherwise, the set of announcements
+// which should be broadcast to our immediate peers during the next
+// announcement epoch is returned. The result of processing the announcement
+// is sent over the network message's error channel.
+func (d *AuthenticatedGossiper) processNetworkAnnouncement(nMsg *networkMsg) []lnwire.Message {
+	var announcements []lnwire.Message
+
+	isPremature := func(chanID *lnwire.ChannelID) bool {
+		return chanID.BlockHeight > d.bestHeight
+	}
+
+	// deferPremature places the network message in limbo until the chain
+	// tip as we know it reaches the advertised height.
+	deferPremature := func(chanID *lnwire.ChannelID) {
+		blockHeight := chanID.BlockHeight
+		log.Infof("Announcement for chan_id=(%v), is premature: "+
+			"advertises height %v, only height %v is known",
+			chanID.ToUint64(), blockHeight, d.bestHeight)
+
+		d.prematureAnnouncements[blockHeight] = append(
+			d.prematureAnnouncements[blockHeight], nMsg,
+		)
+	}
+
+	switch msg := nMsg.msg.(type) {
+
+	// A new node announcement has arrived which either presents a new
+	// node, or a node updating previously advertised information.
+	case *lnwire.NodeAnnouncement:
+		if err := ValidateNodeAnn(msg); err != nil {
+			err := fmt.Errorf("unable to validate node "+
+				"announcement: %v", err)
+			log.Error(err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// Before proceeding ensure that we aren't already aware of
+		// this node, and if we are then this is a newer update than
+		// we know of.
+		lastUpdate, exists, err := d.cfg.Graph.HasLightningNode(msg.NodeID)
+		if err != nil {
+			log.Errorf("unable to query for the existence of "+
+				"node: %v", err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// If we're aware of the vertex being advertised, then we only
+		// accept the announcement if it has a newer time stamp, as it
+		// would otherwise override newer data.
+		msgTimestamp := time.Unix(int64(msg.Timestamp), 0)
+		if exists && !msgTimestamp.After(lastUpdate) {
+			log.Debugf("Ignoring outdated announcement for %x",
+				msg.NodeID.SerializeCompressed())
+			nMsg.err <- nil
+			return nil
+		}
+
+		node := &channeldb.LightningNode{
+			LastUpdate: msgTimestamp,
+			Address:    msg.Address,
+			PubKey:     msg.NodeID,
+			Alias:      msg.Alias.String(),
+			AuthSig:    msg.Signature,
+		}
+		if err := d.cfg.Router.AddNode(node); err != nil {
+			log.Errorf("unable to add node %x: %v",
+				msg.NodeID.SerializeCompressed(), err)
+			nMsg.err <- err
+			return nil
+		}
+
+		announcements = append(announcements, msg)
+
+	// A new channel announcement has arrived, this indicates the
+	// *creation* of a new channel within the graph. This only advertises
+	// the existence of a channel and not yet the routing policies in
+	// either direction of the channel.
+	case *lnwire.ChannelAnnouncement:
+		// If the advertised inclusionary block is beyond our
+		// knowledge of the chain tip, then we'll put the announcement
+		// in limbo to be fully verified once we advance forward in
+		// the chain.
+		if isPremature(&msg.ChannelID) {
+			deferPremature(&msg.ChannelID)
+			nMsg.err <- nil
+			return nil
+		}
+
+		// Prior to processing the announcement we first check if we
+		// already know of this channel, if so, then we can exit early.
+		chanID := msg.ChannelID.ToUint64()
+		_, _, exists, err := d.cfg.Graph.HasChannelEdge(chanID)
+		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+			log.Errorf("unable to check for edge existence: %v", err)
+			nMsg.err <- err
+			return nil
+		} else if exists {
+			log.Debugf("Ignoring announcement for known "+
+				"chan_id=%v", chanID)
+			nMsg.err <- nil
+			return nil
+		}
+
+		// Zombie channels are only resurrected by a fresh update, so
+		// we'll also ignore any announcements for them.
+		isZombie, _, _, err := d.cfg.Graph.IsZombieChannel(chanID)
+		if err != nil {
+			log.Errorf("unable to check for zombie edge: %v", err)
+			nMsg.err <- err
+			return nil
+		} else if isZombie {
+			log.Debugf("Ignoring announcement for zombie "+
+				"chan_id=%v", chanID)
+			nMsg.err <- nil
+			return nil
+		}
+
+		// Announcements received from the network must carry a full
+		// proof. Our own announcements are only signed once both
+		// halves of the proof have been exchanged, so those are
+		// validated once the proof is assembled.
+		if nMsg.isRemote {
+			if err := ValidateChannelAnn(msg); err != nil {
+				err := fmt.Errorf("unable to validate "+
+					"announcement for chan_id=%v: %v",
+					chanID, err)
+				log.Error(err)
+				nMsg.err <- err
+				return nil
+			}
+		}
+
+		// With the signatures verified, we'll ensure that the funding
+		// output of the channel exists on chain, pays to the announced
+		// multi-sig keys, and hasn't yet been spent.
+		fundingPoint, err := d.validateFundingOutput(msg)
+		if err != nil {
+			err := fmt.Errorf("unable to validate funding output "+
+				"for chan_id=%v: %v", chanID, err)
+			log.Error(err)
+			nMsg.err <- err
+			return nil
+		}
+
+		err = d.cfg.Router.AddEdge(msg.FirstNodeID, msg.SecondNodeID,
+			fundingPoint, chanID)
+		if err != nil {
+			log.Errorf("unable to add channel: %v", err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// If this is one of our own channels, then we'll wait for
+		// both halves of the proof before announcing it, unless the
+		// channel is private in which case it's never announced.
+		if !nMsg.isRemote {
+			if d.isPrivate(chanID) {
+				nMsg.err <- nil
+				return nil
+			}
+
+			d.localChanAnns[chanID] = msg
+
+			anns, err := d.assembleProof(chanID)
+			nMsg.err <- err
+			return anns
+		}
+
+		// Otherwise, we'll store the proof so we're able to relay the
+		// announcement to newly connected peers.
+		proof := &channeldb.ChannelAuthProof{
+			NodeSig1:    msg.FirstNodeSig,
+			NodeSig2:    msg.SecondNodeSig,
+			BitcoinSig1: msg.FirstBitcoinSig,
+			BitcoinSig2: msg.SecondBitcoinSig,
+			BitcoinKey1: msg.FirstBitcoinKey,
+			BitcoinKey2: msg.SecondBitcoinKey,
+		}
+		if err := d.cfg.Graph.AddChannelProof(chanID, proof); err != nil {
+			log.Errorf("unable to store proof for chan_id=%v: %v",
+				chanID, err)
+			nMsg.err <- err
+			return nil
+		}
+
+		announcements = append(announcements, msg)
+
+	// A new authenticated channel update has has arrived, this indicates
+	// that the directional information for an already known channel has
+	// been updated.
+	case *lnwire.ChannelUpdateAnnouncement:
+		// If the advertised inclusionary block is beyond our
+		// knowledge of the chain tip, then we'll put the announcement
+		// in limbo to be fully verified once we advance forward in
+		// the chain.
+		if isPremature(&msg.ChannelID) {
+			deferPremature(&msg.ChannelID)
+			nMsg.err <- nil
+			return nil
+		}
+
+		// The update can only be authenticated if we know of the two
+		// nodes that created the channel. If the channel has been
+		// pruned as a zombie, then a fresh update may resurrect it.
+		chanID := msg.ChannelID.ToUint64()
+		node1, node2, err := d.cfg.Graph.ChannelNodes(chanID)
+		resurrected := false
+		if err == channeldb.ErrEdgeNotFound ||
+			err == channeldb.ErrGraphNoEdgesFound {
+
+			node1, node2, err = d.processZombieUpdate(msg)
+			resurrected = err == nil
+		}
+		if err != nil {
+			err := fmt.Errorf("unable to find nodes of "+
+				"chan_id=%v: %v", chanID, err)
+			log.Error(err)
+			nMsg.err <- err
+			return nil
+		}
+		edge1Timestamp, edge2Timestamp, _, err := d.cfg.Graph.HasChannelEdge(chanID)
+		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+			log.Errorf("unable to check for edge existence: %v", err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// As edges are directional, each node has a unique policy for
+		// the direction of the edge they control. The flags indicate
+		// which of the nodes has signed the update, and which of the
+		// directed edges we've last updated.
+		var (
+			pubKeyBytes []byte
+			lastUpdate  time.Time
+		)
+		switch msg.Flags {
+		case 0:
+			pubKeyBytes = node1[:]
+			lastUpdate = edge1Timestamp
+		case 1:
+			pubKeyBytes = node2[:]
+			lastUpdate = edge2Timestamp
+		default:
+			err := fmt.Errorf("invalid flags %v for update of "+
+				"chan_id=%v", msg.Flags, chanID)
+			log.Error(err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// If we already have the most up to date information for that
+		// edge, then we can exit early.
+		updateTimestamp := time.Unix(int64(msg.Timestamp), 0)
+		if !updateTimestamp.After(lastUpdate) {
+			log.Debugf("Ignoring announcement (flags=%v) for "+
+				"known chan_id=%v", msg.Flags, chanID)
+			nMsg.err <- nil
+			return nil
+		}
+
+		// Validate the channel update announcement by checking that
+		// the signature was created by the node on the advertised
+		// side of the channel.
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+		if err != nil {
+			nMsg.err <- err
+			return nil
+		}
+		if err := ValidateChannelUpdateAnn(pubKey, msg); err != nil {
+			err := fmt.Errorf("unable to validate channel update "+
+				"announcement for chan_id=%v: %v", chanID, err)
+			log.Error(err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// Only once the update has been authenticated do we apply
+		// rate limiting, ensuring a forged update can't exhaust the
+		// allowance of the legitimate node.
+		if nMsg.isRemote && d.isRateLimited(chanID, msg.Flags) {
+			err := fmt.Errorf("rate limiting updates for "+
+				"chan_id=%v (flags=%v)", chanID, msg.Flags)
+			log.Debug(err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// Before we can update the channel information, we need to
+		// get the UTXO itself so we can store the proper capacity.
+		_, chanPoint, err := d.fetchFundingTx(&msg.ChannelID)
+		if err != nil {
+			log.Errorf("unable to fetch chan point for "+
+				"chan_id=%v: %v", chanID, err)
+			nMsg.err <- err
+			return nil
+		}
+		utxo, err := d.cfg.Chain.GetUtxo(&chanPoint.Hash,
+			chanPoint.Index)
+		if err != nil {
+			log.Errorf("unable to fetch utxo for chan_id=%v: %v",
+				chanID, err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// TODO(roasbeef): should be msat here
+		chanUpdate := &channeldb.ChannelEdge{
+			ChannelID:                 chanID,
+			ChannelPoint:              *chanPoint,
+			LastUpdate:                updateTimestamp,
+			Flags:                     msg.Flags,
+			Expiry:                    msg.Expiry,
+			MinHTLC:                   btcutil.Amount(msg.HtlcMinimumMstat),
+			MaxHTLC:                   btcutil.Amount(msg.HtlcMaximumMstat),
+			FeeBaseMSat:               btcutil.Amount(msg.FeeBaseMstat),
+			FeeProportionalMillionths: btcutil.Amount(msg.FeeProportionalMillionths),
+			// TODO(roasbeef): this is a hack, needs to be removed
+			// after commitment fees are dynamic.
+			Capacity: btcutil.Amount(utxo.Value) - btcutil.Amount(5000),
+			AuthSig:  msg.Signature,
+		}
+		if err := d.cfg.Router.UpdateEdge(chanUpdate); err != nil {
+			log.Errorf("unable to update channel: %v", err)
+			nMsg.err <- err
+			return nil
+		}
+
+		// Updates for channels which haven't been announced, either
+		// because they're private or because the proof hasn't been
+		// assembled yet, can't be validated by the rest of the
+		// network, so we won't broadcast them.
+		if !d.isAnnounced(chanID) {
+			nMsg.err <- nil
+			return nil
+		}
+
+		// If the channel was just resurrected, then our peers may
+		// have pruned it as well, so we'll also re-broadcast the
+		// announcement of the channel itself.
+		if resurrected {
+			chanAnn, err := createChanAnnouncement(d.cfg.Graph, chanID)
+			if err != nil {
+				log.Errorf("unable to create announcement for "+
+					"chan_id=%v: %v", chanID, err)
+			} else {
+				announcements = append(announcements, chanAnn)
+			}
+		}
+
+		announcements = append(announcements, msg)
+
+	// A new half of the announcement proof for one of our own channels
+	// has arrived, either created locally or sent by our channel
+	// counterparty.
+	case *lnwire.AnnounceSignatures:
+		chanID := msg.ChannelID.ToUint64()
+
+		if nMsg.isRemote {
+			// If we already know of the local announcement, then
+			// we can ensure the proof was sent by the other party
+			// to the channel.
+			chanAnn, ok := d.localChanAnns[chanID]
+			if ok && !chanAnn.FirstNodeID.IsEqual(nMsg.peer) &&
+				!chanAnn.SecondNodeID.IsEqual(nMsg.peer) {
+
+				err := fmt.Errorf("peer %x isn't party to "+
+					"chan_id=%v", nMsg.peer.SerializeCompressed(),
+					chanID)
+				log.Error(err)
+				nMsg.err <- err
+				return nil
+			}
+
+			_, known := d.remoteProofs[chanID]
+			if !known && len(d.remoteProofs) >= maxPendingProofs {
+				err := fmt.Errorf("too many pending proofs, "+
+					"dropping proof for chan_id=%v", chanID)
+				log.Error(err)
+				nMsg.err <- err
+				return nil
+			}
+
+			d.remoteProofs[chanID] = msg
+		} else {
+			d.localProofs[chanID] = msg
+		}
+
+		anns, err := d.assembleProof(chanID)
+		nMsg.err <- err
+		return anns
+
+	default:
+		nMsg.err <- fmt.Errorf("unknown announcement type %T", msg)
+		return nil
+	}
+
+	nMsg.err <- nil
+	return announcements
+}
+
+// assembleProof attempts to combine both halves of the announcement proof for
+// one of our own channels into a fully authenticated channel announcement. If
+// either half, or the local channel announcement itself, is still missing,
+// then nil is returned. Otherwise, the full announcement is validated, its
+// proof stored, and the announcements to be broadcast for the channel are
+// returned.
+func (d *AuthenticatedGossiper) assembleProof(chanID uint64) ([]lnwire.Message, error) {
+	chanAnn, ok := d.localChanAnns[chanID]
+	if !ok {
+		return nil, nil
+	}
+	localProof, ok := d.localProofs[chanID]
+	if !ok {
+		return nil, nil
+	}
+	remoteProof, ok := d.remoteProofs[chanID]
+	if !ok {
+		return nil, nil
+	}
+
+	// The order of the signatures within the announcement is dictated by
+	// the order of the node identities, so we'll place each half of the
+	// proof accordingly.
+	fullAnn := *chanAnn
+	if chanAnn.FirstNodeID.IsEqual(d.selfKey) {
+		fullAnn.FirstNodeSig = localProof.NodeSignature
+		fullAnn.FirstBitcoinSig = localProof.BitcoinSignature
+		fullAnn.SecondNodeSig = remoteProof.NodeSignature
+		fullAnn.SecondBitcoinSig = remoteProof.BitcoinSignature
+	} else {
+		fullAnn.FirstNodeSig = remoteProof.NodeSignature
+		fullAnn.FirstBitcoinSig = remoteProof.BitcoinSignature
+		fullAnn.SecondNodeSig = localProof.NodeSignature
+		fullAnn.SecondBitcoinSig = localProof.BitcoinSignature
+	}
+
+	// With the proof assembled, we'll ensure it's valid. If it isn't,
+	// then we'll discard the remote half so that a valid one is able to
+	// take its place.
+	if err := ValidateChannelAnn(&fullAnn); err != nil {
+		delete(d.remoteProofs, chanID)
+		return nil, fmt.Errorf("invalid proof for chan_id=%v: %v",
+			chanID, err)
+	}
+
+	proof := &channeldb.ChannelAuthProof{
+		NodeSig1:    fullAnn.FirstNodeSig,
+		NodeSig2:    fullAnn.SecondNodeSig,
+		BitcoinSig1: fullAnn.FirstBitcoinSig,
+		BitcoinSig2: fullAnn.SecondBitcoinSig,
+		BitcoinKey1: fullAnn.FirstBitcoinKey,
+		BitcoinKey2: fullAnn.SecondBitcoinKey,
+	}
+	if err := d.cfg.Graph.AddChannelProof(chanID, proof); err != nil {
+		return nil, err
+	}
+
+	delete(d.localChanAnns, chanID)
+	delete(d.localProofs, chanID)
+	delete(d.remoteProofs, chanID)
+
+	log.Infof("Assembled announcement proof for chan_id=%v", chanID)
+
+	// Now that the channel can be announced, we'll also broadcast the
+	// latest routing policies for both directions of the channel, as
+	// they were held back until the channel itself was announced.
+	announcements := []lnwire.Message{&fullAnn}
+	edge1, edge2, err := d.cfg.Graph.FetchChannelEdgesByID(chanID)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return nil, err
+	}
+	for _, edge := range []*channeldb.ChannelEdge{edge1, edge2} {
+		if edge == nil || edge.AuthSig == nil {
+			continue
+		}
+		announcements = append(announcements, createChanUpdate(edge))
+	}
+
+	return announcements, nil
+}
+
+// validateFundingOutput ensures that the funding output referenced by the
+// channel announcement exists within the main chain, pays to the 2-of-2
+// multi-sig script made up of the announced bitcoin keys, and hasn't yet been
+// spent. If so, then the funding outpoint of the channel is returned.
+func (d *AuthenticatedGossiper) validateFundingOutput(
+	msg *lnwire.ChannelAnnouncement) (*wire.OutPoint, error) {
+
+	fundingTx, fundingPoint, err := d.fetchFundingTx(&msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(fundingPoint.Index) >= len(fundingTx.TxOut) {
+		return nil, fmt.Errorf("output index %v is out of range "+
+			"(max_index=%v)", fundingPoint.Index,
+			len(fundingTx.TxOut)-1)
+	}
+	fundingOutput := fundingTx.TxOut[fundingPoint.Index]
+
+	// Reconstruct the funding output script from the two announced
+	// bitcoin keys, ensuring it matches the output found on chain.
+	_, expectedOutput, err := lnwallet.GenFundingPkScript(
+		msg.FirstBitcoinKey.SerializeCompressed(),
+		msg.SecondBitcoinKey.SerializeCompressed(),
+		fundingOutput.Value,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expectedOutput.PkScript, fundingOutput.PkScript) {
+		return nil, fmt.Errorf("funding output script doesn't match " +
+			"announced bitcoin keys")
+	}
+
+	// Finally, ensure that the funding output hasn't yet been spent. If
+	// so, then this channel has been closed so we'll ignore it.
+	if _, err := d.cfg.Chain.GetUtxo(&fundingPoint.Hash,
+		fundingPoint.Index); err != nil {
+		return nil, fmt.Errorf("unable to fetch utxo: %v", err)
+	}
+
+	return fundingPoint, nil
+}
+
+// fetchFundingTx retrieves the funding transaction and funding outpoint which
+// are encoded within the channel ID.
+func (d *AuthenticatedGossiper) fetchFundingTx(
+	chanID *lnwire.ChannelID) (*wire.MsgTx, *wire.OutPoint, error) {
+
+	// First fetch the block hash by the block number encoded, then use
+	// that hash to fetch the block itself.
+	blockNum := int64(chanID.BlockHeight)
+	blockHash, err := d.cfg.Chain.GetBlockHash(blockNum)
+	if err != nil {
+		return nil, nil, err
+	}
+	fundingBlock, err := d.cfg.Chain.GetBlock(blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// As a sanity check, ensure that the advertised transaction index is
+	// within the bounds of the total number of transactions within a
+	// block.
+	numTxns := uint32(len(fundingBlock.Transactions))
+	if chanID.TxIndex >= numTxns {
+		return nil, nil, fmt.Errorf("tx_index=#%v is out of range "+
+			"(num_txns=%v), chan_id=%v", chanID.TxIndex, numTxns,
+			chanID.ToUint64())
+	}
+
+	// Finally once we have the block itself, we seek to the targeted
+	// transaction index to obtain the funding output and txid.
+	fundingTx := fundingBlock.Transactions[chanID.TxIndex]
+	return fundingTx, &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: uint32(chanID.TxPosition),
+	}, nil
+}
+
+// processZombieUpdate attempts to resurrect the zombie channel targeted by the
+// passed channel update. A zombie is only resurrected if the update is signed
+// by the node on the advertised side of the channel, is fresh, meaning it
+// falls within the channel prune expiry, and the funding output of the
+// channel remains unspent. Once resurrected, the public keys
+// of the two nodes of the channel are returned, allowing the update to be
+// processed as usual.
+func (d *AuthenticatedGossiper) processZombieUpdate(
+	msg *lnwire.ChannelUpdateAnnouncement) ([33]byte, [33]byte, error) {
+
+	chanID := msg.ChannelID.ToUint64()
+	isZombie, node1, node2, err := d.cfg.Graph.IsZombieChannel(chanID)
+	if err != nil {
+		return node1, node2, err
+	}
+	if !isZombie {
+		return node1, node2, channeldb.ErrEdgeNotFound
+	}
+
+	updateTime := time.Unix(int64(msg.Timestamp), 0)
+	if time.Since(updateTime) >= d.cfg.ChannelPruneExpiry {
+		return node1, node2, fmt.Errorf("ignoring stale update for "+
+			"zombie chan_id=%v", chanID)
+	}
+
+	pubKeyBytes := node1[:]
+	if msg.Flags == 1 {
+		pubKeyBytes = node2[:]
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return node1, node2, err
+	}
+	if err := ValidateChannelUpdateAnn(pubKey, msg); err != nil {
+		return node1, node2, err
+	}
+
+	// The channel may have been closed while it was a zombie, so we'll
+	// ensure its funding output is still unspent before bringing it back.
+	_, chanPoint, err := d.fetchFundingTx(&msg.ChannelID)
+	if err != nil {
+		return node1, node2, err
+	}
+	_, err = d.cfg.Chain.GetUtxo(&chanPoint.Hash, chanPoint.Index)
+	if err != nil {
+		return node1, node2, fmt.Errorf("unable to fetch utxo for "+
+			"zombie chan_id=%v: %v", chanID, err)
+	}
+
+	if err := d.cfg.Graph.MarkChannelLive(chanID); err != nil {
+		return node1, node2, err
+	}
+
+	log.Infof("Resurrected zombie chan_id=%v due to fresh update", chanID)
+
+	return node1, node2, nil
+}
+
+// isPrivate returns true if the channel identified by the passed channel ID
+// has been marked as private, and so must not be broadcast. In the case of an
+// error, we err on the side of caution and treat the channel as private.
+func (d *AuthenticatedGossiper) isPrivate(chanID uint64) bool {
+	isPrivate, err := d.cfg.Graph.IsChannelPrivate(chanID)
+	if err != nil {
+		log.Errorf("unable to check if chan_id=%v is private: %v",
+			chanID, err)
+		return true
+	}
+
+	return isPrivate
+}
+
+// isAnnounced returns true if the channel identified by the passed channel ID
+// may be announced to the network. This is only the case for public channels
+// for which we hold a full authentication proof.
+func (d *AuthenticatedGossiper) isAnnounced(chanID uint64) bool {
+	if d.isPrivate(chanID) {
+		return false
+	}
+
+	_, err := d.cfg.Graph.FetchChannelProof(chanID)
+	return err == nil
+}
+
+// isRateLimited returns true if the number of updates we've accepted for the
+// target direction of the channel within the current window has reached the
+// configured burst. Otherwise, the update is counted against the current
+// window and false is returned.
+func (d *AuthenticatedGossiper) isRateLimited(chanID uint64, flags uint16) bool {
+	key := updateKey{chanID: chanID, flags: flags}
+	now := time.Now()
+
+	window, ok := d.updateWindows[key]
+	if !ok || now.Sub(window.start) >= d.cfg.ChannelUpdateInterval {
+		d.updateWindows[key] = &updateWindow{
+			start: now,
+			count: 1,
+		}
+		return false
+	}
+
+	if window.count >= d.cfg.MaxChannelUpdateBurst {
+		return true
+	}
+
+	window.count++
+	return false
+}
+
+// pruneUpdateWindows removes all rate limiting windows which have expired.
+func (d *AuthenticatedGossiper) pruneUpdateWindows() {
+	now := time.Now()
+	for key, window := range d.updateWindows {
+		if now.Sub(window.start) >= d.cfg.ChannelUpdateInterval {
+			delete(d.updateWindows, key)
+		}
+	}
+}
+
+// retransmitChannels re-broadcasts the latest updates for all our announced
+// channels to our connected peers.
+func (d *AuthenticatedGossiper) retransmitChannels() error {
+	selfNode, err := d.cfg.Graph.SourceNode()
+	if err != nil {
+		return err
+	}
+
+	var edges []*channeldb.ChannelEdge
+	err = selfNode.ForEachChannel(nil, func(c *channeldb.ChannelEdge) error {
+		edges = append(edges, c)
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return err
+	}
+
+	// Only our authenticated updates for channels which have been
+	// announced to the network are able to be retransmitted.
+	var selfChans []lnwire.Message
+	for _, edge := range edges {
+		if edge.AuthSig == nil || !d.isAnnounced(edge.ChannelID) {
+			continue
+		}
+		selfChans = append(selfChans, createChanUpdate(edge))
+	}
+
+	if len(selfChans) == 0 {
+		return nil
+	}
+
+	log.Infof("Retransmitting %v outgoing channels", len(selfChans))
+
+	// With all the wire messages properly crafted, we'll broadcast our
+	// known outgoing channels to all our immediate peers.
+	d.sendToSyncers(selfChans...)
+
+	return nil
+}
+
+// createChanAnnouncement reconstructs the fully authenticated announcement of
+// the target channel from the node identities and proof stored within the
+// channel graph.
+func createChanAnnouncement(graph *channeldb.ChannelGraph,
+	chanID uint64) (*lnwire.ChannelAnnouncement, error) {
+
+	proof, err := graph.FetchChannelProof(chanID)
+	if err != nil {
+		return nil, err
+	}
+	node1, node2, err := graph.ChannelNodes(chanID)
+	if err != nil {
+		return nil, err
+	}
+	node1Key, err := btcec.ParsePubKey(node1[:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	node2Key, err := btcec.ParsePubKey(node2[:], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.ChannelAnnouncement{
+		FirstNodeSig:     proof.NodeSig1,
+		SecondNodeSig:    proof.NodeSig2,
+		ChannelID:        lnwire.NewChanIDFromInt(chanID),
+		FirstBitcoinSig:  proof.BitcoinSig1,
+		SecondBitcoinSig: proof.BitcoinSig2,
+		FirstNodeID:      node1Key,
+		SecondNodeID:     node2Key,
+		FirstBitcoinKey:  proof.BitcoinKey1,
+		SecondBitcoinKey: proof.BitcoinKey2,
+	}, nil
+}
+
+// createChanUpdate reconstructs the authenticated channel update announcement
+// for the passed directed edge.
+func createChanUpdate(edge *channeldb.ChannelEdge) *lnwire.ChannelUpdateAnnouncement {
+	return &lnwire.ChannelUpdateAnnouncement{
+		Signature:                 edge.AuthSig,
+		ChannelID:                 lnwire.NewChanIDFromInt(edge.ChannelID),
+		Timestamp:                 uint32(edge.LastUpdate.Unix()),
+		Flags:                     edge.Flags,
+		Expiry:                    edge.Expiry,
+		HtlcMinimumMstat:          uint32(edge.MinHTLC),
+		HtlcMaximumMstat:          uint32(edge.MaxHTLC),
+		FeeBaseMstat:              uint32(edge.FeeBaseMSat),
+		FeeProportionalMillionths: uint32(edge.FeeProportionalMillionths),
+	}
+}
+
+// createNodeAnnouncement reconstructs the authenticated announcement of the
+// passed node. Not all fields of the original announcement are stored within
+// the graph, so an error is returned if the reconstructed announcement can't
+// be authenticated.
+func createNodeAnnouncement(node *channeldb.LightningNode) (*lnwire.NodeAnnouncement, error) {
+	if node.AuthSig == nil {
+		return nil, fmt.Errorf("node %x has no signature",
+			node.PubKey.SerializeCompressed())
+	}
+
+	alias, err := lnwire.NewAlias(node.Alias)
+	if err != nil {
+		return nil, err
+	}
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Signature: node.AuthSig,
+		Timestamp: uint32(node.LastUpdate.Unix()),
+		Address:   node.Address,
+		NodeID:    node.PubKey,
+		Alias:     alias,
+	}
+	if err := ValidateNodeAnn(nodeAnn); err != nil {
+		return nil, fmt.Errorf("unable to reconstruct announcement "+
+			"for node %x: %v", node.PubKey.SerializeCompressed(),
+			err)
+	}
+
+	return nodeAnn, nil
+}
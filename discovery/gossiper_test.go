@@ -0,0 +1,934 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// testHeight is the height of the block which contains all the
+	// funding transactions used within the tests.
+	testHeight = 100
+
+	// testFundingAmt is the value of the funding outputs used within the
+	// tests.
+	testFundingAmt = 1000000
+)
+
+var (
+	testTimeout = time.Second * 5
+
+	testAddr = &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9735}
+
+	selfKeyPriv, _   = btcec.NewPrivateKey(btcec.S256())
+	remoteKeyPriv, _ = btcec.NewPrivateKey(btcec.S256())
+	otherKeyPriv, _  = btcec.NewPrivateKey(btcec.S256())
+
+	selfBitcoinPriv, _   = btcec.NewPrivateKey(btcec.S256())
+	remoteBitcoinPriv, _ = btcec.NewPrivateKey(btcec.S256())
+	otherBitcoinPriv, _  = btcec.NewPrivateKey(btcec.S256())
+)
+
+// mockSigner is a simple implementation of the lnwallet.MessageSigner
+// interface which signs with any of the private keys it's aware of.
+type mockSigner struct {
+	keys []*btcec.PrivateKey
+}
+
+func (m *mockSigner) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	for _, key := range m.keys {
+		if key.PubKey().IsEqual(pubKey) {
+			return key.Sign(chainhash.DoubleHashB(msg))
+		}
+	}
+
+	return nil, fmt.Errorf("unknown public key %x",
+		pubKey.SerializeCompressed())
+}
+
+var _ lnwallet.MessageSigner = (*mockSigner)(nil)
+
+var testSigner = &mockSigner{
+	keys: []*btcec.PrivateKey{
+		selfKeyPriv, remoteKeyPriv, otherKeyPriv,
+		selfBitcoinPriv, remoteBitcoinPriv, otherBitcoinPriv,
+	},
+}
+
+// mockGraphSource is an implementation of the routing.ChannelGraphSource
+// interface which writes directly to the backing channel graph.
+type mockGraphSource struct {
+	graph *channeldb.ChannelGraph
+}
+
+func (m *mockGraphSource) AddNode(node *channeldb.LightningNode) error {
+	return m.graph.AddLightningNode(node)
+}
+
+func (m *mockGraphSource) AddEdge(node1, node2 *btcec.PublicKey,
+	chanPoint *wire.OutPoint, chanID uint64) error {
+
+	return m.graph.AddChannelEdge(node1, node2, chanPoint, chanID)
+}
+
+func (m *mockGraphSource) UpdateEdge(edge *channeldb.ChannelEdge) error {
+	return m.graph.UpdateEdgeInfo(edge)
+}
+
+// mockChain is a mock implementation of the lnwallet.BlockChainIO interface
+// backed by a single block.
+type mockChain struct {
+	sync.RWMutex
+
+	block *wire.MsgBlock
+	utxos map[wire.OutPoint]*wire.TxOut
+}
+
+func (m *mockChain) GetBestBlock() (*chainhash.Hash, int32, error) {
+	hash := m.block.BlockHash()
+	return &hash, testHeight, nil
+}
+
+func (m *mockChain) GetUtxo(txid *chainhash.Hash,
+	index uint32) (*wire.TxOut, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	utxo, ok := m.utxos[wire.OutPoint{Hash: *txid, Index: index}]
+	if !ok {
+		return nil, fmt.Errorf("utxo not found")
+	}
+
+	return utxo, nil
+}
+
+func (m *mockChain) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockChain) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	if blockHeight != testHeight {
+		return nil, fmt.Errorf("block at height %v not found",
+			blockHeight)
+	}
+
+	hash := m.block.BlockHash()
+	return &hash, nil
+}
+
+func (m *mockChain) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	if *blockHash != m.block.BlockHash() {
+		return nil, fmt.Errorf("block %v not found", blockHash)
+	}
+
+	return m.block, nil
+}
+
+// addFundingTx adds a new transaction paying to the 2-of-2 multi-sig script of
+// the passed bitcoin keys to the block, returning the ID of the channel it
+// funds.
+func (m *mockChain) addFundingTx(t *testing.T, btcKey1,
+	btcKey2 *btcec.PublicKey) lnwire.ChannelID {
+
+	_, fundingOutput, err := lnwallet.GenFundingPkScript(
+		btcKey1.SerializeCompressed(), btcKey2.SerializeCompressed(),
+		testFundingAmt,
+	)
+	if err != nil {
+		t.Fatalf("unable to generate funding script: %v", err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// The lock time is set to the index of the transaction, ensuring
+	// channels funded by the same keys have distinct funding outpoints.
+	tx := wire.NewMsgTx(1)
+	tx.AddTxOut(fundingOutput)
+	tx.LockTime = uint32(len(m.block.Transactions))
+	m.block.Transactions = append(m.block.Transactions, tx)
+	m.utxos[wire.OutPoint{Hash: tx.TxHash(), Index: 0}] = fundingOutput
+
+	return lnwire.ChannelID{
+		BlockHeight: testHeight,
+		TxIndex:     uint32(len(m.block.Transactions) - 1),
+		TxPosition:  0,
+	}
+}
+
+// spendFundingTx marks the funding output of the passed channel as spent.
+func (m *mockChain) spendFundingTx(chanID lnwire.ChannelID) {
+	m.Lock()
+	defer m.Unlock()
+
+	tx := m.block.Transactions[chanID.TxIndex]
+	delete(m.utxos, wire.OutPoint{
+		Hash:  tx.TxHash(),
+		Index: uint32(chanID.TxPosition),
+	})
+}
+
+// mockNotifier is a mock implementation of the chainntnfs.ChainNotifier
+// interface which only delivers block epochs sent by the test.
+type mockNotifier struct {
+	epochs chan *chainntnfs.BlockEpoch
+}
+
+func (m *mockNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockNotifier) RegisterSpendNtfn(
+	outpoint *wire.OutPoint) (*chainntnfs.SpendEvent, error) {
+
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent, error) {
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: m.epochs,
+	}, nil
+}
+
+func (m *mockNotifier) Start() error {
+	return nil
+}
+
+func (m *mockNotifier) Stop() error {
+	return nil
+}
+
+// testCtx houses a running gossiper along with its mocked dependencies.
+type testCtx struct {
+	gossiper   *AuthenticatedGossiper
+	graph      *channeldb.ChannelGraph
+	chain      *mockChain
+	notifier   *mockNotifier
+	broadcasts chan []lnwire.Message
+}
+
+// createTestCtx creates and starts a new gossiper backed by a temporary
+// channel graph. The returned callback stops the gossiper and removes the
+// graph, and is intended to be executed after the test completes.
+func createTestCtx(t *testing.T, cfg Config) (*testCtx, func()) {
+	tempDirName, err := ioutil.TempDir("", "discovery")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		os.RemoveAll(tempDirName)
+		t.Fatalf("unable to open db: %v", err)
+	}
+
+	graph := cdb.ChannelGraph()
+	selfNode := &channeldb.LightningNode{
+		LastUpdate: time.Unix(1, 0),
+		Address:    testAddr,
+		PubKey:     selfKeyPriv.PubKey(),
+		Alias:      "self",
+	}
+	if err := graph.SetSourceNode(selfNode); err != nil {
+		t.Fatalf("unable to set source node: %v", err)
+	}
+
+	// The policies of a channel can only be read back from the graph if
+	// both of its nodes are known, so we'll add the remaining nodes used
+	// within the tests.
+	for i, key := range []*btcec.PrivateKey{remoteKeyPriv, otherKeyPriv} {
+		node := &channeldb.LightningNode{
+			LastUpdate: time.Unix(1, 0),
+			Address:    testAddr,
+			PubKey:     key.PubKey(),
+			Alias:      fmt.Sprintf("node%v", i),
+		}
+		if err := graph.AddLightningNode(node); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	}
+
+	ctx := &testCtx{
+		graph: graph,
+		chain: &mockChain{
+			block: &wire.MsgBlock{},
+			utxos: make(map[wire.OutPoint]*wire.TxOut),
+		},
+		notifier: &mockNotifier{
+			epochs: make(chan *chainntnfs.BlockEpoch),
+		},
+		broadcasts: make(chan []lnwire.Message, 10),
+	}
+
+	cfg.Router = &mockGraphSource{graph: graph}
+	cfg.Graph = graph
+	cfg.Chain = ctx.chain
+	cfg.Notifier = ctx.notifier
+	cfg.SendToPeer = func(_ *btcec.PublicKey, msgs ...lnwire.Message) error {
+		// We're only interested in the announcements sent to the
+		// peer, so we'll ignore any sync related messages.
+		var anns []lnwire.Message
+		for _, msg := range msgs {
+			switch msg.(type) {
+			case *lnwire.ChannelAnnouncement,
+				*lnwire.ChannelUpdateAnnouncement,
+				*lnwire.NodeAnnouncement:
+
+				anns = append(anns, msg)
+			}
+		}
+		if len(anns) != 0 {
+			ctx.broadcasts <- anns
+		}
+		return nil
+	}
+	if cfg.TrickleDelay == 0 {
+		cfg.TrickleDelay = time.Millisecond * 10
+	}
+
+	ctx.gossiper, err = New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create gossiper: %v", err)
+	}
+	if err := ctx.gossiper.Start(); err != nil {
+		t.Fatalf("unable to start gossiper: %v", err)
+	}
+
+	// We'll register a peer which requests all gossip, so announcements
+	// broadcast by the gossiper are sent to it.
+	ctx.gossiper.InitSyncState(remoteKeyPriv.PubKey())
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(
+		&lnwire.GossipTimestampRange{TimestampRange: math.MaxUint32},
+		remoteKeyPriv.PubKey(),
+	))
+	if err != nil {
+		t.Fatalf("unable to apply gossip filter: %v", err)
+	}
+	syncer, ok := ctx.gossiper.findSyncer(remoteKeyPriv.PubKey())
+	if !ok {
+		t.Fatalf("syncer for peer not found")
+	}
+	for i := 0; ; i++ {
+		syncer.Lock()
+		horizon := syncer.remoteUpdateHorizon
+		syncer.Unlock()
+		if horizon != nil {
+			break
+		}
+		if i == 500 {
+			t.Fatalf("gossip filter wasn't applied")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	cleanUp := func() {
+		ctx.gossiper.Stop()
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return ctx, cleanUp
+}
+
+// createChanAnn creates a fully signed channel announcement for a channel
+// between the two passed nodes.
+func createChanAnn(t *testing.T, chanID lnwire.ChannelID, node1, node2,
+	btc1, btc2 *btcec.PrivateKey) *lnwire.ChannelAnnouncement {
+
+	// The nodes of the channel are ordered by their serialized public
+	// keys, just as they're stored within the channel graph.
+	if bytes.Compare(node1.PubKey().SerializeCompressed(),
+		node2.PubKey().SerializeCompressed()) == 1 {
+
+		node1, node2 = node2, node1
+		btc1, btc2 = btc2, btc1
+	}
+
+	ann := &lnwire.ChannelAnnouncement{
+		ChannelID:        chanID,
+		FirstNodeID:      node1.PubKey(),
+		SecondNodeID:     node2.PubKey(),
+		FirstBitcoinKey:  btc1.PubKey(),
+		SecondBitcoinKey: btc2.PubKey(),
+	}
+
+	var err error
+	ann.FirstNodeSig, err = SignAnnouncement(testSigner, node1.PubKey(), ann)
+	if err != nil {
+		t.Fatalf("unable to sign announcement: %v", err)
+	}
+	ann.SecondNodeSig, err = SignAnnouncement(testSigner, node2.PubKey(), ann)
+	if err != nil {
+		t.Fatalf("unable to sign announcement: %v", err)
+	}
+	ann.FirstBitcoinSig, err = SignBitcoinKeyBinding(testSigner,
+		btc1.PubKey(), node1.PubKey())
+	if err != nil {
+		t.Fatalf("unable to sign key binding: %v", err)
+	}
+	ann.SecondBitcoinSig, err = SignBitcoinKeyBinding(testSigner,
+		btc2.PubKey(), node2.PubKey())
+	if err != nil {
+		t.Fatalf("unable to sign key binding: %v", err)
+	}
+
+	return ann
+}
+
+// createChanUpdate creates a channel update for the passed direction of the
+// channel, signed by the given key.
+func createUpdateAnn(t *testing.T, chanID lnwire.ChannelID, flags uint16,
+	timestamp uint32, signer *btcec.PrivateKey) *lnwire.ChannelUpdateAnnouncement {
+
+	update := &lnwire.ChannelUpdateAnnouncement{
+		ChannelID: chanID,
+		Timestamp: timestamp,
+		Flags:     flags,
+		Expiry:    144,
+	}
+
+	var err error
+	update.Signature, err = SignAnnouncement(testSigner, signer.PubKey(),
+		update)
+	if err != nil {
+		t.Fatalf("unable to sign update: %v", err)
+	}
+
+	return update
+}
+
+// firstNode returns the private key of the passed nodes which sorts first.
+func firstNode(node1, node2 *btcec.PrivateKey) *btcec.PrivateKey {
+	if bytes.Compare(node1.PubKey().SerializeCompressed(),
+		node2.PubKey().SerializeCompressed()) == -1 {
+
+		return node1
+	}
+	return node2
+}
+
+// waitErr waits for the result of processing an announcement.
+func waitErr(t *testing.T, errChan chan error) error {
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(testTimeout):
+		t.Fatalf("announcement wasn't processed")
+	}
+	return nil
+}
+
+// TestProcessRemoteChannelAnnouncement tests that remote channel
+// announcements are only accepted once both their signatures and their
+// funding output have been validated.
+func TestProcessRemoteChannelAnnouncement(t *testing.T) {
+	ctx, cleanUp := createTestCtx(t, Config{})
+	defer cleanUp()
+
+	// A properly signed announcement of a channel with a valid funding
+	// output should be accepted, and its proof stored.
+	chanID := ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	ann := createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	err := waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process valid announcement: %v", err)
+	}
+	if _, err := ctx.graph.FetchChannelProof(chanID.ToUint64()); err != nil {
+		t.Fatalf("unable to fetch channel proof: %v", err)
+	}
+
+	// The announcement should be broadcast during the next trickle.
+	select {
+	case msgs := <-ctx.broadcasts:
+		if len(msgs) != 1 {
+			t.Fatalf("expected 1 broadcast message, got %v",
+				len(msgs))
+		}
+	case <-time.After(testTimeout):
+		t.Fatalf("announcement wasn't broadcast")
+	}
+
+	// An announcement with an invalid node signature should be rejected.
+	chanID = ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	ann = createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	ann.SecondNodeSig = ann.FirstNodeSig
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("announcement with invalid signature was accepted")
+	}
+
+	// An announcement with valid signatures, but bitcoin keys which don't
+	// match the on-chain funding output should also be rejected.
+	ann = createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, selfBitcoinPriv)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("announcement with mismatched funding script " +
+			"was accepted")
+	}
+
+	// Finally, an announcement for a channel whose funding output has
+	// been spent should be rejected.
+	chanID = ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	ctx.chain.Lock()
+	fundingTx := ctx.chain.block.Transactions[chanID.TxIndex]
+	delete(ctx.chain.utxos, wire.OutPoint{Hash: fundingTx.TxHash()})
+	ctx.chain.Unlock()
+
+	ann = createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("announcement with spent funding output was accepted")
+	}
+
+	if _, _, exists, _ := ctx.graph.HasChannelEdge(chanID.ToUint64()); exists {
+		t.Fatalf("invalid channel was added to the graph")
+	}
+}
+
+// TestProcessNodeAnnouncement tests that only properly signed node
+// announcements are accepted.
+func TestProcessNodeAnnouncement(t *testing.T) {
+	ctx, cleanUp := createTestCtx(t, Config{})
+	defer cleanUp()
+
+	alias, _ := lnwire.NewAlias("remote")
+	nodeAnn := &lnwire.NodeAnnouncement{
+		Timestamp: 100,
+		Address:   testAddr,
+		NodeID:    remoteKeyPriv.PubKey(),
+		Alias:     alias,
+	}
+	var err error
+	nodeAnn.Signature, err = SignAnnouncement(testSigner,
+		otherKeyPriv.PubKey(), nodeAnn)
+	if err != nil {
+		t.Fatalf("unable to sign node announcement: %v", err)
+	}
+
+	// As the announcement was signed by a key other than the one being
+	// advertised, it should be rejected.
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(nodeAnn,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("node announcement with invalid signature was " +
+			"accepted")
+	}
+
+	nodeAnn.Signature, err = SignAnnouncement(testSigner,
+		remoteKeyPriv.PubKey(), nodeAnn)
+	if err != nil {
+		t.Fatalf("unable to sign node announcement: %v", err)
+	}
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(nodeAnn,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process node announcement: %v", err)
+	}
+
+	node, err := ctx.graph.FetchLightningNode(remoteKeyPriv.PubKey())
+	if err != nil {
+		t.Fatalf("unable to fetch node: %v", err)
+	}
+	if node.AuthSig == nil {
+		t.Fatalf("node signature wasn't stored")
+	}
+}
+
+// TestAnnounceSignaturesProofAssembly tests that the announcement of one of
+// our own channels is only broadcast once both halves of the proof have been
+// received, and that the assembled announcement is valid.
+func TestAnnounceSignaturesProofAssembly(t *testing.T) {
+	ctx, cleanUp := createTestCtx(t, Config{})
+	defer cleanUp()
+
+	selfKey := selfKeyPriv.PubKey()
+	remoteKey := remoteKeyPriv.PubKey()
+
+	chanID := ctx.chain.addFundingTx(t, selfBitcoinPriv.PubKey(),
+		remoteBitcoinPriv.PubKey())
+
+	// We'll create the unsigned announcement of the channel, along with
+	// the proof created by each of the parties.
+	fullAnn := createChanAnn(t, chanID, selfKeyPriv, remoteKeyPriv,
+		selfBitcoinPriv, remoteBitcoinPriv)
+	chanAnn := *fullAnn
+	chanAnn.FirstNodeSig = nil
+	chanAnn.SecondNodeSig = nil
+	chanAnn.FirstBitcoinSig = nil
+	chanAnn.SecondBitcoinSig = nil
+
+	localProof := &lnwire.AnnounceSignatures{ChannelID: chanID}
+	remoteProof := &lnwire.AnnounceSignatures{ChannelID: chanID}
+	var flags uint16
+	if fullAnn.FirstNodeID.IsEqual(selfKey) {
+		localProof.NodeSignature = fullAnn.FirstNodeSig
+		localProof.BitcoinSignature = fullAnn.FirstBitcoinSig
+		remoteProof.NodeSignature = fullAnn.SecondNodeSig
+		remoteProof.BitcoinSignature = fullAnn.SecondBitcoinSig
+	} else {
+		localProof.NodeSignature = fullAnn.SecondNodeSig
+		localProof.BitcoinSignature = fullAnn.SecondBitcoinSig
+		remoteProof.NodeSignature = fullAnn.FirstNodeSig
+		remoteProof.BitcoinSignature = fullAnn.FirstBitcoinSig
+		flags = 1
+	}
+	update := createUpdateAnn(t, chanID, flags, 100, selfKeyPriv)
+
+	for _, msg := range []lnwire.Message{&chanAnn, update, localProof} {
+		err := waitErr(t, ctx.gossiper.ProcessLocalAnnouncement(msg,
+			selfKey))
+		if err != nil {
+			t.Fatalf("unable to process %T: %v", msg, err)
+		}
+	}
+
+	// As the remote half of the proof is still missing, nothing should
+	// be broadcast.
+	select {
+	case msgs := <-ctx.broadcasts:
+		t.Fatalf("unexpected broadcast of %v messages", len(msgs))
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// A remote proof which doesn't carry valid signatures should be
+	// rejected.
+	badProof := &lnwire.AnnounceSignatures{
+		ChannelID:        chanID,
+		NodeSignature:    localProof.NodeSignature,
+		BitcoinSignature: localProof.BitcoinSignature,
+	}
+	err := waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(badProof,
+		remoteKey))
+	if err == nil {
+		t.Fatalf("invalid proof was accepted")
+	}
+
+	// Once the valid remote half arrives, the proof should be stored and
+	// both the channel announcement and our update broadcast.
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(remoteProof,
+		remoteKey))
+	if err != nil {
+		t.Fatalf("unable to process remote proof: %v", err)
+	}
+	if _, err := ctx.graph.FetchChannelProof(chanID.ToUint64()); err != nil {
+		t.Fatalf("unable to fetch channel proof: %v", err)
+	}
+
+	select {
+	case msgs := <-ctx.broadcasts:
+		if len(msgs) != 2 {
+			t.Fatalf("expected 2 broadcast messages, got %v",
+				len(msgs))
+		}
+		ann, ok := msgs[0].(*lnwire.ChannelAnnouncement)
+		if !ok {
+			t.Fatalf("expected channel announcement, got %T",
+				msgs[0])
+		}
+		if err := ValidateChannelAnn(ann); err != nil {
+			t.Fatalf("broadcast announcement is invalid: %v", err)
+		}
+		if _, ok := msgs[1].(*lnwire.ChannelUpdateAnnouncement); !ok {
+			t.Fatalf("expected channel update, got %T", msgs[1])
+		}
+	case <-time.After(testTimeout):
+		t.Fatalf("announcement wasn't broadcast")
+	}
+}
+
+// TestChannelUpdateRateLimit tests that the number of channel updates
+// accepted for each direction of a channel is limited within each window.
+func TestChannelUpdateRateLimit(t *testing.T) {
+	ctx, cleanUp := createTestCtx(t, Config{
+		ChannelUpdateInterval: time.Hour,
+		MaxChannelUpdateBurst: 2,
+	})
+	defer cleanUp()
+
+	chanID := ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	ann := createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	err := waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process announcement: %v", err)
+	}
+
+	signer := firstNode(remoteKeyPriv, otherKeyPriv)
+
+	// An update with an invalid signature shouldn't count against the
+	// allowance of the channel.
+	update := createUpdateAnn(t, chanID, 0, 100, signer)
+	update.Timestamp++
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(update,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("update with invalid signature was accepted")
+	}
+
+	// The first two valid updates should be accepted, while the third
+	// exceeds the burst.
+	for i := uint32(0); i < 3; i++ {
+		update := createUpdateAnn(t, chanID, 0, 200+i, signer)
+		err := waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(
+			update, remoteKeyPriv.PubKey()))
+		switch {
+		case i < 2 && err != nil:
+			t.Fatalf("unable to process update #%v: %v", i, err)
+		case i == 2 && err == nil:
+			t.Fatalf("update exceeding burst was accepted")
+		}
+	}
+
+	// The opposite direction of the channel has its own allowance.
+	update = createUpdateAnn(t, chanID, 1, 300,
+		otherNode(signer, remoteKeyPriv, otherKeyPriv))
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(update,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process update: %v", err)
+	}
+}
+
+// TestZombieChannelResurrection tests that announcements for zombie channels
+// are ignored, and that a zombie channel is only resurrected by a fresh,
+// authenticated update for a channel whose funding output is unspent.
+func TestZombieChannelResurrection(t *testing.T) {
+	ctx, cleanUp := createTestCtx(t, Config{
+		ChannelPruneExpiry: time.Hour,
+	})
+	defer cleanUp()
+
+	chanID := ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	ann := createChanAnn(t, chanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	err := waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process announcement: %v", err)
+	}
+
+	// We'll prune the channel as a zombie, as it hasn't been updated in
+	// either direction.
+	zombies, err := ctx.graph.PruneZombieChannels(time.Now())
+	if err != nil {
+		t.Fatalf("unable to prune zombies: %v", err)
+	}
+	if len(zombies) != 1 {
+		t.Fatalf("expected 1 zombie, got %v", len(zombies))
+	}
+
+	// A new announcement for the zombie shouldn't add it back to the
+	// graph.
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(ann,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process announcement: %v", err)
+	}
+	_, _, exists, err := ctx.graph.HasChannelEdge(chanID.ToUint64())
+	if err != nil {
+		t.Fatalf("unable to query for edge: %v", err)
+	}
+	if exists {
+		t.Fatalf("zombie channel was added back to the graph")
+	}
+
+	// Neither should an update which isn't within the prune expiry, nor
+	// an update with an invalid signature.
+	signer := firstNode(remoteKeyPriv, otherKeyPriv)
+	staleTime := uint32(time.Now().Add(-time.Hour * 2).Unix())
+	update := createUpdateAnn(t, chanID, 0, staleTime, signer)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(update,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("stale update resurrected zombie")
+	}
+
+	freshTime := uint32(time.Now().Unix())
+	update = createUpdateAnn(t, chanID, 0, freshTime,
+		otherNode(signer, remoteKeyPriv, otherKeyPriv))
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(update,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("update with invalid signature resurrected zombie")
+	}
+
+	// A fresh authenticated update also shouldn't resurrect a zombie
+	// whose funding output has since been spent.
+	spentChanID := ctx.chain.addFundingTx(t, remoteBitcoinPriv.PubKey(),
+		otherBitcoinPriv.PubKey())
+	spentAnn := createChanAnn(t, spentChanID, remoteKeyPriv, otherKeyPriv,
+		remoteBitcoinPriv, otherBitcoinPriv)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(spentAnn,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process announcement: %v", err)
+	}
+	if _, err := ctx.graph.PruneZombieChannels(time.Now()); err != nil {
+		t.Fatalf("unable to prune zombies: %v", err)
+	}
+	ctx.chain.spendFundingTx(spentChanID)
+	spentUpdate := createUpdateAnn(t, spentChanID, 0, freshTime, signer)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(spentUpdate,
+		remoteKeyPriv.PubKey()))
+	if err == nil {
+		t.Fatalf("update for spent channel resurrected zombie")
+	}
+	isZombie, _, _, err := ctx.graph.IsZombieChannel(
+		spentChanID.ToUint64(),
+	)
+	if err != nil {
+		t.Fatalf("unable to query zombie index: %v", err)
+	}
+	if !isZombie {
+		t.Fatalf("spent channel removed from zombie index")
+	}
+
+	// Finally, a fresh authenticated update should resurrect the channel
+	// and be applied, with the announcement of the channel being
+	// re-broadcast along with the update.
+	update = createUpdateAnn(t, chanID, 0, freshTime, signer)
+	err = waitErr(t, ctx.gossiper.ProcessRemoteAnnouncement(update,
+		remoteKeyPriv.PubKey()))
+	if err != nil {
+		t.Fatalf("unable to process fresh update: %v", err)
+	}
+	ts1, _, exists, err := ctx.graph.HasChannelEdge(chanID.ToUint64())
+	if err != nil {
+		t.Fatalf("unable to query for edge: %v", err)
+	}
+	if !exists || ts1.Unix() != int64(freshTime) {
+		t.Fatalf("channel wasn't resurrected")
+	}
+
+	for {
+		select {
+		case msgs := <-ctx.broadcasts:
+			var chanAnn, chanUpdate bool
+			for _, msg := range msgs {
+				switch msg.(type) {
+				case *lnwire.ChannelAnnouncement:
+					chanAnn = true
+				case *lnwire.ChannelUpdateAnnouncement:
+					chanUpdate = msg == update
+				}
+			}
+			if !chanUpdate {
+				continue
+			}
+			if !chanAnn {
+				t.Fatalf("channel announcement wasn't " +
+					"re-broadcast")
+			}
+			return
+
+		case <-time.After(testTimeout):
+			t.Fatalf("update wasn't broadcast")
+		}
+	}
+}
+
+// otherNode returns whichever of the two nodes isn't the passed node.
+func otherNode(node, node1, node2 *btcec.PrivateKey) *btcec.PrivateKey {
+	if node == node1 {
+		return node2
+	}
+	return node1
+}
+
+// TestDeDupedAnnouncements tests that only the latest announcement for each
+// channel, channel direction, and node is kept within a batch.
+func TestDeDupedAnnouncements(t *testing.T) {
+	announcements := newDeDupedAnnouncements()
+
+	chanID := lnwire.ChannelID{BlockHeight: testHeight}
+	ann := &lnwire.ChannelAnnouncement{ChannelID: chanID}
+	announcements.AddMsg(ann)
+	announcements.AddMsg(ann)
+
+	// Of the updates for the first direction, only the newest should be
+	// kept, regardless of the order they were added in.
+	newUpdate := &lnwire.ChannelUpdateAnnouncement{
+		ChannelID: chanID,
+		Timestamp: 2,
+	}
+	announcements.AddMsg(newUpdate)
+	announcements.AddMsg(&lnwire.ChannelUpdateAnnouncement{
+		ChannelID: chanID,
+		Timestamp: 1,
+	})
+	announcements.AddMsg(&lnwire.ChannelUpdateAnnouncement{
+		ChannelID: chanID,
+		Timestamp: 1,
+		Flags:     1,
+	})
+
+	newNodeAnn := &lnwire.NodeAnnouncement{
+		NodeID:    remoteKeyPriv.PubKey(),
+		Timestamp: 2,
+	}
+	announcements.AddMsg(&lnwire.NodeAnnouncement{
+		NodeID:    remoteKeyPriv.PubKey(),
+		Timestamp: 1,
+	})
+	announcements.AddMsg(newNodeAnn)
+
+	batch := announcements.Emit()
+	if len(batch) != 4 {
+		t.Fatalf("expected 4 announcements, got %v", len(batch))
+	}
+	if batch[0] != ann {
+		t.Fatalf("channel announcement wasn't emitted first")
+	}
+
+	var foundUpdate, foundNode bool
+	for _, msg := range batch {
+		switch msg {
+		case newUpdate:
+			foundUpdate = true
+		case newNodeAnn:
+			foundNode = true
+		}
+	}
+	if !foundUpdate || !foundNode {
+		t.Fatalf("latest announcements weren't kept: update=%v, "+
+			"node=%v", foundUpdate, foundNode)
+	}
+
+	announcements.Reset()
+	if len(announcements.Emit()) != 0 {
+		t.Fatalf("batch wasn't reset")
+	}
+}
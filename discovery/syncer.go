@@ -0,0 +1,1011 @@
+package discovery
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// SyncerType encapsulates the different types of syncing mechanisms for a
+// gossip syncer.
+type SyncerType uint8
+
+const (
+	// ActiveSync denotes that a gossip syncer should exercise its default
+	// behavior. This includes reconciling the set of missing graph updates
+	// with the remote peer _and_ receiving new updates from them.
+	ActiveSync SyncerType = iota
+
+	// PassiveSync denotes that a gossip syncer should not perform any
+	// reconciliation of the channel graph with the remote peer, nor
+	// request any new updates from them. It'll only answer the queries
+	// of the remote peer, and relay the new updates they've requested.
+	PassiveSync
+)
+
+// String returns a human readable string describing the target SyncerType.
+func (t SyncerType) String() string {
+	switch t {
+	case ActiveSync:
+		return "ActiveSync"
+	case PassiveSync:
+		return "PassiveSync"
+	default:
+		return fmt.Sprintf("unknown sync type %d", t)
+	}
+}
+
+// syncerState is an enum that represents the current state of the
+// gossipSyncer. As the syncer is a state machine, we'll gate our actions
+// based off of the current state and the next incoming message.
+type syncerState uint32
+
+const (
+	// syncingChans is the default state of an active gossipSyncer. In
+	// this state, the syncer will send a QueryChannelRange message to the
+	// remote peer covering the entire chain as we know it.
+	syncingChans syncerState = iota
+
+	// waitingQueryRangeReply is the second main phase of the
+	// gossipSyncer. We enter this state after we send out our first
+	// QueryChannelRange reply. We'll stay in this state until the remote
+	// party sends us a ReplyChannelRange message that indicates they've
+	// responded to our query entirely. After this state, we'll transition
+	// to queryNewChannels.
+	waitingQueryRangeReply
+
+	// queryNewChannels is the third main phase of the gossipSyncer. In
+	// this phase we'll send out all of our QueryShortChanIDs messages in
+	// response to the new channels or updates that we don't yet know
+	// about.
+	queryNewChannels
+
+	// waitingQueryChanReply is the fourth main phase of the gossipSyncer.
+	// We enter this phase once we've sent off a query chunk to the remote
+	// peer. We'll stay in this phase until we receive a
+	// ReplyShortChanIDsEnd message which indicates that the remote party
+	// has responded to all of our requests.
+	waitingQueryChanReply
+
+	// chansSynced is the terminal stage of the gossipSyncer. Once we
+	// enter this phase, we'll send out our update horizon, which filters
+	// out the set of channel updates that we're interested in. In this
+	// state, we'll be able to accept any outgoing messages from the
+	// AuthenticatedGossiper, and decide if we should forward them to our
+	// target peer based on its update horizon.
+	chansSynced
+)
+
+// String returns a human readable string describing the target syncerState.
+func (s syncerState) String() string {
+	switch s {
+	case syncingChans:
+		return "syncingChans"
+	case waitingQueryRangeReply:
+		return "waitingQueryRangeReply"
+	case queryNewChannels:
+		return "queryNewChannels"
+	case waitingQueryChanReply:
+		return "waitingQueryChanReply"
+	case chansSynced:
+		return "chansSynced"
+	default:
+		return "UNKNOWN STATE"
+	}
+}
+
+const (
+	// DefaultMaxChanQueryChunk is the default number of channels we'll
+	// include within a single ReplyChannelRange or QueryShortChanIDs
+	// message.
+	DefaultMaxChanQueryChunk = 500
+
+	// syncerBufferSize is the size of the syncer's buffers of incoming
+	// query messages.
+	syncerBufferSize = 50
+)
+
+// gossipSyncerCfg is a struct that packages all the information a
+// gossipSyncer needs to carry out its duties.
+type gossipSyncerCfg struct {
+	// peerPub is the public key of the peer we're syncing with.
+	peerPub *btcec.PublicKey
+
+	// graph is the channel graph which is consulted to answer the queries
+	// of the remote peer, and to determine which channels we lack.
+	graph *channeldb.ChannelGraph
+
+	// bestHeight returns the height of the block at the tip of the main
+	// chain as we know it.
+	bestHeight func() (uint32, error)
+
+	// chunkSize is the max number of channels that we'll include within a
+	// single ReplyChannelRange or QueryShortChanIDs message.
+	chunkSize int
+
+	// chanPruneExpiry is the duration after which a channel for which
+	// neither node has sent an update is considered a zombie. Zombie
+	// channels are only queried for if the remote peer holds an update
+	// within this horizon.
+	chanPruneExpiry time.Duration
+
+	// sendToPeer sends a set of messages to the remote peer.
+	sendToPeer func(msgs ...lnwire.Message) error
+}
+
+// gossipSyncer is a struct that handles synchronizing the channel graph state
+// with a remote peer. The gossipSyncer implements a state machine that will
+// progressively ensure we're synchronized with the channel state of the
+// remote node. Once both nodes have been synchronized, we'll use an update
+// filter to filter out which messages should be sent to a remote peer based
+// on their update horizon. If the update horizon isn't specified, then we'll
+// send no gossip to the remote peer at all.
+type gossipSyncer struct {
+	started uint32
+	stopped uint32
+
+	// state is the current state of the gossipSyncer.
+	//
+	// NOTE: This variable MUST be used atomically.
+	state uint32
+
+	// syncType denotes the SyncerType the gossipSyncer is currently
+	// exercising.
+	//
+	// NOTE: This variable MUST be used atomically.
+	syncType uint32
+
+	// syncTransitions is a channel through which new sync type transition
+	// requests will be sent through.
+	syncTransitions chan SyncerType
+
+	// gossipMsgs is a channel that all query messages from the target
+	// peer will be sent over.
+	gossipMsgs chan lnwire.Message
+
+	// bufferedChanRangeReplies is used in the waitingQueryRangeReply
+	// state to buffer all the chunked responses to our query.
+	bufferedChanRangeReplies []channeldb.ChannelUpdateInfo
+
+	// newChansToQuery is used to pass the set of channels we should query
+	// for from the waitingQueryRangeReply state to the queryNewChannels
+	// state.
+	newChansToQuery []lnwire.ChannelID
+
+	// remoteUpdateHorizon is the update horizon of the remote peer. We'll
+	// use this to properly filter out any messages.
+	remoteUpdateHorizon *lnwire.GossipTimestampRange
+
+	// localUpdateHorizon is our local update horizon, we'll use this to
+	// determine if we've already sent out our update.
+	localUpdateHorizon *lnwire.GossipTimestampRange
+
+	cfg gossipSyncerCfg
+
+	sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newGossipSyncer returns a new instance of the gossipSyncer populated using
+// the passed config, exercising the passed sync type.
+func newGossipSyncer(cfg gossipSyncerCfg, syncType SyncerType) *gossipSyncer {
+	if cfg.chunkSize == 0 || cfg.chunkSize > lnwire.MaxShortChanIDsPerMsg {
+		cfg.chunkSize = DefaultMaxChanQueryChunk
+	}
+
+	// A passive syncer performs no reconciliation, so it starts out in
+	// the terminal state.
+	initialState := syncingChans
+	if syncType == PassiveSync {
+		initialState = chansSynced
+	}
+
+	return &gossipSyncer{
+		cfg:             cfg,
+		state:           uint32(initialState),
+		syncType:        uint32(syncType),
+		syncTransitions: make(chan SyncerType),
+		gossipMsgs:      make(chan lnwire.Message, syncerBufferSize),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start starts the gossipSyncer and any goroutines that it needs to carry out
+// its duties.
+func (g *gossipSyncer) Start() error {
+	if !atomic.CompareAndSwapUint32(&g.started, 0, 1) {
+		return nil
+	}
+
+	log.Debugf("Starting gossipSyncer(%x) as %v",
+		g.cfg.peerPub.SerializeCompressed(), g.SyncType())
+
+	g.wg.Add(1)
+	go g.channelGraphSyncer()
+
+	return nil
+}
+
+// Stop signals the gossipSyncer for a graceful exit, then waits until it has
+// exited.
+func (g *gossipSyncer) Stop() error {
+	if !atomic.CompareAndSwapUint32(&g.stopped, 0, 1) {
+		return nil
+	}
+
+	close(g.quit)
+	g.wg.Wait()
+
+	return nil
+}
+
+// syncState returns the current syncerState of the target gossipSyncer.
+func (g *gossipSyncer) syncState() syncerState {
+	return syncerState(atomic.LoadUint32(&g.state))
+}
+
+// setSyncState sets the gossipSyncer's state to the given state.
+func (g *gossipSyncer) setSyncState(state syncerState) {
+	atomic.StoreUint32(&g.state, uint32(state))
+}
+
+// SyncType returns the current SyncerType of the target gossipSyncer.
+func (g *gossipSyncer) SyncType() SyncerType {
+	return SyncerType(atomic.LoadUint32(&g.syncType))
+}
+
+// ProcessSyncTransition sends a request to the gossipSyncer to transition
+// to the given sync type.
+func (g *gossipSyncer) ProcessSyncTransition(newSyncType SyncerType) {
+	select {
+	case g.syncTransitions <- newSyncType:
+	case <-g.quit:
+	}
+}
+
+// ProcessQueryMsg is used by outside callers to pass new channel time series
+// queries to the internal processing goroutine.
+func (g *gossipSyncer) ProcessQueryMsg(msg lnwire.Message) {
+	select {
+	case g.gossipMsgs <- msg:
+	case <-g.quit:
+	}
+}
+
+// channelGraphSyncer is the main goroutine responsible for ensuring that we
+// properly channel graph state with the remote peer, and also that we only
+// send them messages which actually pass their defined update horizon.
+//
+// NOTE: This MUST be run as a goroutine.
+func (g *gossipSyncer) channelGraphSyncer() {
+	defer g.wg.Done()
+
+	peerPub := g.cfg.peerPub.SerializeCompressed()
+
+	for {
+		state := g.syncState()
+		log.Debugf("gossipSyncer(%x): state=%v", peerPub, state)
+
+		switch state {
+		// When we're in this state, we're trying to synchronize our
+		// view of the network with the remote peer. We'll kick off
+		// this sync by asking them for the set of channels they know
+		// of within the chain as we know it.
+		case syncingChans:
+			bestHeight, err := g.cfg.bestHeight()
+			if err != nil {
+				log.Errorf("unable to fetch best height: %v", err)
+				return
+			}
+
+			log.Infof("gossipSyncer(%x): requesting channels for "+
+				"blocks [0, %v]", peerPub, bestHeight)
+
+			// Reset any state left over from a prior sync.
+			g.bufferedChanRangeReplies = nil
+			g.newChansToQuery = nil
+
+			err = g.cfg.sendToPeer(&lnwire.QueryChannelRange{
+				FirstBlockHeight: 0,
+				NumBlocks:        bestHeight + 1,
+			})
+			if err != nil {
+				log.Errorf("unable to send chan range query: %v",
+					err)
+				return
+			}
+
+			g.setSyncState(waitingQueryRangeReply)
+
+		// In this state, we've sent out our initial channel range
+		// query and are waiting for the final response from the
+		// remote peer before we perform a diff to see which channels
+		// they know of that we don't.
+		case waitingQueryRangeReply:
+			select {
+			case msg := <-g.gossipMsgs:
+				// The remote peer is responding to our range
+				// query, so we'll process it.
+				if reply, ok := msg.(*lnwire.ReplyChannelRange); ok {
+					err := g.processChanRangeReply(reply)
+					if err != nil {
+						log.Errorf("unable to process "+
+							"chan range reply: %v",
+							err)
+						return
+					}
+					continue
+				}
+
+				// Otherwise, it's the remote peer performing a
+				// query, which we'll attempt to reply to.
+				if err := g.replyPeerQueries(msg); err != nil {
+					log.Errorf("unable to reply to peer "+
+						"query: %v", err)
+				}
+
+			case newSyncType := <-g.syncTransitions:
+				g.handleSyncTransition(newSyncType)
+
+			case <-g.quit:
+				return
+			}
+
+		// We'll enter this state once we've discovered which channels
+		// the remote party knows of that we don't yet know of
+		// ourselves.
+		case queryNewChannels:
+			// If we've queried for all the channels we lack, then
+			// we're fully synced.
+			if len(g.newChansToQuery) == 0 {
+				log.Infof("gossipSyncer(%x): no more chans "+
+					"to query", peerPub)
+				g.setSyncState(chansSynced)
+				continue
+			}
+
+			// Otherwise, we'll query for the next chunk of
+			// channels.
+			numChans := len(g.newChansToQuery)
+			if numChans > g.cfg.chunkSize {
+				numChans = g.cfg.chunkSize
+			}
+			queryChunk := g.newChansToQuery[:numChans]
+			g.newChansToQuery = g.newChansToQuery[numChans:]
+
+			log.Infof("gossipSyncer(%x): querying for %v new "+
+				"channels", peerPub, len(queryChunk))
+
+			err := g.cfg.sendToPeer(&lnwire.QueryShortChanIDs{
+				ShortChanIDs: queryChunk,
+			})
+			if err != nil {
+				log.Errorf("unable to query for new "+
+					"channels: %v", err)
+				return
+			}
+
+			g.setSyncState(waitingQueryChanReply)
+
+		// In this state, we're waiting for the remote peer to signal
+		// the end of its response to our last chunk of queried
+		// channels.
+		case waitingQueryChanReply:
+			select {
+			case msg := <-g.gossipMsgs:
+				if _, ok := msg.(*lnwire.ReplyShortChanIDsEnd); ok {
+					g.setSyncState(queryNewChannels)
+					continue
+				}
+
+				if err := g.replyPeerQueries(msg); err != nil {
+					log.Errorf("unable to reply to peer "+
+						"query: %v", err)
+				}
+
+			case newSyncType := <-g.syncTransitions:
+				g.handleSyncTransition(newSyncType)
+
+			case <-g.quit:
+				return
+			}
+
+		// This is our final terminal state where we'll only reply to
+		// any further queries by the remote peer.
+		case chansSynced:
+			// If we're actively syncing, then we'll request all
+			// new updates from the remote peer by sending our
+			// update horizon, if we haven't already.
+			if g.SyncType() == ActiveSync {
+				if err := g.sendUpdateHorizon(); err != nil {
+					log.Errorf("unable to send update "+
+						"horizon: %v", err)
+					return
+				}
+			}
+
+			select {
+			case msg := <-g.gossipMsgs:
+				if err := g.replyPeerQueries(msg); err != nil {
+					log.Errorf("unable to reply to peer "+
+						"query: %v", err)
+				}
+
+			case newSyncType := <-g.syncTransitions:
+				g.handleSyncTransition(newSyncType)
+
+			case <-g.quit:
+				return
+			}
+		}
+	}
+}
+
+// handleSyncTransition transitions the gossipSyncer to the given sync type.
+// A syncer promoted to ActiveSync will reconcile its view of the channel
+// graph with the remote peer before requesting new updates, while a syncer
+// demoted to PassiveSync asks the remote peer to stop sending it updates.
+func (g *gossipSyncer) handleSyncTransition(newSyncType SyncerType) {
+	if newSyncType == g.SyncType() {
+		return
+	}
+
+	log.Infof("gossipSyncer(%x): transitioning from %v to %v",
+		g.cfg.peerPub.SerializeCompressed(), g.SyncType(), newSyncType)
+
+	atomic.StoreUint32(&g.syncType, uint32(newSyncType))
+
+	switch newSyncType {
+	case ActiveSync:
+		g.setSyncState(syncingChans)
+
+	case PassiveSync:
+		g.setSyncState(chansSynced)
+
+		// A zero range indicates that the remote peer shouldn't send
+		// us any gossip at all.
+		horizon := &lnwire.GossipTimestampRange{}
+		if err := g.cfg.sendToPeer(horizon); err != nil {
+			log.Errorf("unable to clear update horizon: %v", err)
+			return
+		}
+		g.localUpdateHorizon = nil
+	}
+}
+
+// sendUpdateHorizon sends our update horizon to the remote peer, requesting
+// that they send us all new updates from this point onwards. The horizon is
+// only sent once.
+func (g *gossipSyncer) sendUpdateHorizon() error {
+	if g.localUpdateHorizon != nil {
+		return nil
+	}
+
+	horizon := &lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(time.Now().Unix()),
+		TimestampRange: math.MaxUint32,
+	}
+
+	log.Infof("gossipSyncer(%x): applying gossipFilter(start=%v)",
+		g.cfg.peerPub.SerializeCompressed(),
+		time.Unix(int64(horizon.FirstTimestamp), 0))
+
+	if err := g.cfg.sendToPeer(horizon); err != nil {
+		return err
+	}
+
+	g.localUpdateHorizon = horizon
+	return nil
+}
+
+// processChanRangeReply is called each time the gossipSyncer receives a new
+// reply to the initial range query to discover new channels that it didn't
+// previously know of. Once the final reply has been received, we'll
+// determine the set of channels which we lack, or hold outdated updates for.
+func (g *gossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	for i, chanID := range msg.ShortChanIDs {
+		g.bufferedChanRangeReplies = append(
+			g.bufferedChanRangeReplies, channeldb.ChannelUpdateInfo{
+				ChannelID: chanID.ToUint64(),
+				Node1UpdateTimestamp: fromTimestamp(
+					msg.Timestamps[i].Timestamp1,
+				),
+				Node2UpdateTimestamp: fromTimestamp(
+					msg.Timestamps[i].Timestamp2,
+				),
+			},
+		)
+	}
+
+	// If this isn't the last response, then we can exit as we've already
+	// buffered the latest portion of the streaming reply.
+	if msg.Complete == 0 {
+		return nil
+	}
+
+	log.Infof("gossipSyncer(%x): filtering through %v chans",
+		g.cfg.peerPub.SerializeCompressed(),
+		len(g.bufferedChanRangeReplies))
+
+	// Otherwise, this is the final response, so we'll now check to see
+	// which channels they know of that we don't, or for which they hold
+	// more recent updates.
+	newChans, err := g.filterKnownChans(g.bufferedChanRangeReplies)
+	if err != nil {
+		return fmt.Errorf("unable to filter chan ids: %v", err)
+	}
+	g.bufferedChanRangeReplies = nil
+
+	log.Infof("gossipSyncer(%x): starting query for %v new chans",
+		g.cfg.peerPub.SerializeCompressed(), len(newChans))
+
+	g.newChansToQuery = newChans
+	g.setSyncState(queryNewChannels)
+
+	return nil
+}
+
+// filterKnownChans returns the IDs of the channels within the passed set
+// which we either don't know of, or for which the remote peer holds a more
+// recent update in either direction.
+func (g *gossipSyncer) filterKnownChans(
+	chanInfos []channeldb.ChannelUpdateInfo) ([]lnwire.ChannelID, error) {
+
+	var newChans []lnwire.ChannelID
+	for _, chanInfo := range chanInfos {
+		ts1, ts2, exists, err := g.cfg.graph.HasChannelEdge(
+			chanInfo.ChannelID,
+		)
+		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+			return nil, err
+		}
+
+		if exists &&
+			!chanInfo.Node1UpdateTimestamp.After(ts1) &&
+			!chanInfo.Node2UpdateTimestamp.After(ts2) {
+
+			continue
+		}
+
+		// If we've pruned the channel as a zombie, then we'll only
+		// query for it if the remote peer holds a fresh update that
+		// may resurrect it.
+		if !exists {
+			isZombie, _, _, err := g.cfg.graph.IsZombieChannel(
+				chanInfo.ChannelID,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			staleTime := time.Now().Add(-g.cfg.chanPruneExpiry)
+			if isZombie &&
+				!chanInfo.Node1UpdateTimestamp.After(staleTime) &&
+				!chanInfo.Node2UpdateTimestamp.After(staleTime) {
+
+				continue
+			}
+		}
+
+		newChans = append(newChans,
+			lnwire.NewChanIDFromInt(chanInfo.ChannelID))
+	}
+
+	return newChans, nil
+}
+
+// replyPeerQueries is called in response to any query by the remote peer.
+// We'll examine our state and send back our best response.
+func (g *gossipSyncer) replyPeerQueries(msg lnwire.Message) error {
+	switch msg := msg.(type) {
+
+	// In this state, we'll also handle any incoming channel range queries
+	// from the remote peer as they're trying to sync their state as well.
+	case *lnwire.QueryChannelRange:
+		return g.replyChanRangeQuery(msg)
+
+	// If the remote peer skips straight to requesting new channels that
+	// they don't know of, then we'll ensure that we also handle this
+	// case.
+	case *lnwire.QueryShortChanIDs:
+		return g.replyShortChanIDs(msg)
+
+	// The remote peer has sent its update horizon, so we'll apply it to
+	// all gossip we send them from now on.
+	case *lnwire.GossipTimestampRange:
+		return g.applyGossipFilter(msg)
+
+	default:
+		return fmt.Errorf("unknown message: %T", msg)
+	}
+}
+
+// replyChanRangeQuery will be dispatched in response to a channel range query
+// by the remote node. We'll query the channel time series for channels that
+// meet the channel range, then chunk our responses to the remote node. We
+// also ensure that our final fragment carries the "complete" bit to indicate
+// the end of our streaming response.
+func (g *gossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) error {
+	log.Infof("gossipSyncer(%x): filtering chan range: start_height=%v, "+
+		"num_blocks=%v", g.cfg.peerPub.SerializeCompressed(),
+		query.FirstBlockHeight, query.NumBlocks)
+
+	if err := query.Validate(); err != nil {
+		return err
+	}
+
+	// Next, we'll consult the channel graph for the set of announced
+	// channels within the target range.
+	chanInfos, err := g.cfg.graph.ChannelsInRange(
+		query.FirstBlockHeight, query.LastBlockHeight(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// We'll send our response in a streaming manner, chunk-by-chunk. We
+	// do this as there's a transport message size limit which we'll need
+	// to adhere to.
+	var replies []lnwire.Message
+	for {
+		numChans := len(chanInfos)
+		if numChans > g.cfg.chunkSize {
+			numChans = g.cfg.chunkSize
+		}
+		chunk := chanInfos[:numChans]
+		chanInfos = chanInfos[numChans:]
+
+		reply := &lnwire.ReplyChannelRange{
+			QueryChannelRange: *query,
+			ShortChanIDs:      make([]lnwire.ChannelID, 0, len(chunk)),
+			Timestamps: make(
+				[]lnwire.ChanUpdateTimestamps, 0, len(chunk),
+			),
+		}
+		for _, chanInfo := range chunk {
+			reply.ShortChanIDs = append(reply.ShortChanIDs,
+				lnwire.NewChanIDFromInt(chanInfo.ChannelID))
+			reply.Timestamps = append(reply.Timestamps,
+				lnwire.ChanUpdateTimestamps{
+					Timestamp1: toTimestamp(
+						chanInfo.Node1UpdateTimestamp,
+					),
+					Timestamp2: toTimestamp(
+						chanInfo.Node2UpdateTimestamp,
+					),
+				},
+			)
+		}
+
+		// If this is the final chunk, then we'll mark it as complete
+		// so the remote peer knows our response has ended.
+		if len(chanInfos) == 0 {
+			reply.Complete = 1
+			replies = append(replies, reply)
+			break
+		}
+
+		replies = append(replies, reply)
+	}
+
+	return g.cfg.sendToPeer(replies...)
+}
+
+// toTimestamp converts the passed time into its wire representation. A zero
+// time, indicating an unknown update, is encoded as zero.
+func toTimestamp(t time.Time) uint32 {
+	if t.IsZero() || t.Unix() <= 0 {
+		return 0
+	}
+	return uint32(t.Unix())
+}
+
+// fromTimestamp converts the passed wire time stamp into a time. A zero time
+// stamp, indicating an unknown update, is converted into the zero time.
+func fromTimestamp(timestamp uint32) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(timestamp), 0)
+}
+
+// replyShortChanIDs will be dispatched in response to a query by the remote
+// node for information concerning a set of short channel ID's. Our response
+// will be sent in a streaming chunked manner to ensure that we remain below
+// the current transport level message size.
+func (g *gossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error {
+	log.Infof("gossipSyncer(%x): fetching chan anns for %v chans",
+		g.cfg.peerPub.SerializeCompressed(), len(query.ShortChanIDs))
+
+	// For each channel we know of, we'll send the channel announcement,
+	// the latest updates of both directions, and the announcements of
+	// both nodes, only sending each node once.
+	var replyMsgs []lnwire.Message
+	sentNodes := make(map[[33]byte]struct{})
+	for _, chanID := range query.ShortChanIDs {
+		msgs, err := fetchChanAnnouncements(g.cfg.graph,
+			chanID.ToUint64(), sentNodes)
+		if err != nil {
+			log.Debugf("Unable to fetch announcements for "+
+				"chan_id=%v: %v", chanID.ToUint64(), err)
+			continue
+		}
+
+		replyMsgs = append(replyMsgs, msgs...)
+	}
+
+	// Regardless of whether we had any messages to reply with, send over
+	// the sentinel message to signal that the stream has terminated.
+	replyMsgs = append(replyMsgs, &lnwire.ReplyShortChanIDsEnd{
+		Complete: 1,
+	})
+
+	return g.cfg.sendToPeer(replyMsgs...)
+}
+
+// applyGossipFilter applies a gossiper filter sent by the remote node to the
+// state machine. Once applied, we'll ensure that we don't forward any
+// messages to the peer that aren't within the time range of the filter.
+// Additionally, all known announcements within the new range are sent.
+func (g *gossipSyncer) applyGossipFilter(filter *lnwire.GossipTimestampRange) error {
+	g.Lock()
+	g.remoteUpdateHorizon = filter
+	g.Unlock()
+
+	// A zero range indicates the remote peer no longer wishes to receive
+	// any gossip from us.
+	if filter.TimestampRange == 0 {
+		return nil
+	}
+
+	startTime := time.Unix(int64(filter.FirstTimestamp), 0)
+	endTime := startTime.Add(
+		time.Duration(filter.TimestampRange) * time.Second,
+	)
+
+	log.Infof("gossipSyncer(%x): applying new update horizon: start=%v, "+
+		"end=%v", g.cfg.peerPub.SerializeCompressed(), startTime,
+		endTime)
+
+	// Now that the remote peer has applied their filter, we'll send them
+	// all the announcements we know of within the new horizon.
+	msgs, err := fetchUpdatesInHorizon(g.cfg.graph, startTime, endTime)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return g.cfg.sendToPeer(msgs...)
+}
+
+// FilterGossipMsgs takes a set of gossip messages, and only sends them to a
+// peer iff the message is within the bounds of their set gossip filter. If
+// the peer doesn't have a gossip filter set, then no messages will be
+// forwarded.
+func (g *gossipSyncer) FilterGossipMsgs(msgs ...lnwire.Message) {
+	g.Lock()
+	horizon := g.remoteUpdateHorizon
+	g.Unlock()
+
+	// If the peer doesn't have an update horizon set, then we won't send
+	// it any new update messages.
+	if horizon == nil || horizon.TimestampRange == 0 {
+		return
+	}
+
+	startTime := time.Unix(int64(horizon.FirstTimestamp), 0)
+	endTime := startTime.Add(
+		time.Duration(horizon.TimestampRange) * time.Second,
+	)
+	passesFilter := func(timestamp uint32) bool {
+		t := time.Unix(int64(timestamp), 0)
+		return !t.Before(startTime) && !t.After(endTime)
+	}
+
+	// As channel announcements carry no time stamp, we'll send them if
+	// any of the updates for the channel within the batch pass the
+	// filter.
+	chanUpdatePasses := make(map[uint64]bool)
+	for _, msg := range msgs {
+		if update, ok := msg.(*lnwire.ChannelUpdateAnnouncement); ok {
+			if passesFilter(update.Timestamp) {
+				chanUpdatePasses[update.ChannelID.ToUint64()] = true
+			}
+		}
+	}
+
+	var msgsToSend []lnwire.Message
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *lnwire.ChannelAnnouncement:
+			chanID := msg.ChannelID.ToUint64()
+			if chanUpdatePasses[chanID] {
+				msgsToSend = append(msgsToSend, msg)
+				continue
+			}
+
+			// Otherwise, we'll check whether any of the updates
+			// for the channel we already know of pass the filter.
+			ts1, ts2, _, err := g.cfg.graph.HasChannelEdge(chanID)
+			if err != nil {
+				continue
+			}
+			if passesFilter(toTimestamp(ts1)) ||
+				passesFilter(toTimestamp(ts2)) {
+
+				msgsToSend = append(msgsToSend, msg)
+			}
+
+		case *lnwire.ChannelUpdateAnnouncement:
+			if passesFilter(msg.Timestamp) {
+				msgsToSend = append(msgsToSend, msg)
+			}
+
+		case *lnwire.NodeAnnouncement:
+			if passesFilter(msg.Timestamp) {
+				msgsToSend = append(msgsToSend, msg)
+			}
+		}
+	}
+
+	log.Tracef("gossipSyncer(%x): filtered gossip msgs: set=%v, sent=%v",
+		g.cfg.peerPub.SerializeCompressed(), len(msgs), len(msgsToSend))
+
+	if len(msgsToSend) == 0 {
+		return
+	}
+
+	if err := g.cfg.sendToPeer(msgsToSend...); err != nil {
+		log.Errorf("unable to send gossip msgs to %x: %v",
+			g.cfg.peerPub.SerializeCompressed(), err)
+	}
+}
+
+// fetchChanAnnouncements reconstructs the full set of announcements for the
+// target channel: the channel announcement itself, the signed updates for
+// either direction, and the announcements of both nodes. Nodes within the
+// passed set are skipped, and any node announced is added to it. Channels
+// which are private, or for which we hold no proof, can't be announced.
+func fetchChanAnnouncements(graph *channeldb.ChannelGraph, chanID uint64,
+	sentNodes map[[33]byte]struct{}) ([]lnwire.Message, error) {
+
+	isPrivate, err := graph.IsChannelPrivate(chanID)
+	if err != nil {
+		return nil, err
+	}
+	if isPrivate {
+		return nil, fmt.Errorf("channel is private")
+	}
+
+	chanAnn, err := createChanAnnouncement(graph, chanID)
+	if err != nil {
+		return nil, err
+	}
+	msgs := []lnwire.Message{chanAnn}
+
+	edge1, edge2, err := graph.FetchChannelEdgesByID(chanID)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return nil, err
+	}
+	for _, edge := range []*channeldb.ChannelEdge{edge1, edge2} {
+		if edge == nil || edge.AuthSig == nil {
+			continue
+		}
+		msgs = append(msgs, createChanUpdate(edge))
+	}
+
+	for _, nodeKey := range []*btcec.PublicKey{chanAnn.FirstNodeID,
+		chanAnn.SecondNodeID} {
+
+		var nodePub [33]byte
+		copy(nodePub[:], nodeKey.SerializeCompressed())
+		if _, ok := sentNodes[nodePub]; ok {
+			continue
+		}
+
+		node, err := graph.FetchLightningNode(nodeKey)
+		if err != nil {
+			continue
+		}
+		nodeAnn, err := createNodeAnnouncement(node)
+		if err != nil {
+			continue
+		}
+
+		sentNodes[nodePub] = struct{}{}
+		msgs = append(msgs, nodeAnn)
+	}
+
+	return msgs, nil
+}
+
+// fetchUpdatesInHorizon returns all the announcements we know of with a time
+// stamp within the passed horizon. Channel announcements are included for any
+// channel with an update in the horizon.
+//
+// TODO(roasbeef): add an update index to the graph to avoid a full scan
+func fetchUpdatesInHorizon(graph *channeldb.ChannelGraph, startTime,
+	endTime time.Time) ([]lnwire.Message, error) {
+
+	inHorizon := func(t time.Time) bool {
+		return !t.Before(startTime) && !t.After(endTime)
+	}
+
+	// First, we'll gather the updates within the horizon along with the
+	// IDs of their channels. As the announcements for each channel are
+	// assembled with additional database queries, we'll do so outside of
+	// the iteration.
+	type chanUpdates struct {
+		chanID  uint64
+		updates []lnwire.Message
+	}
+	var channels []chanUpdates
+	err := graph.ForEachChannel(func(e1, e2 *channeldb.ChannelEdge) error {
+		var c chanUpdates
+		for _, edge := range []*channeldb.ChannelEdge{e1, e2} {
+			if edge == nil || edge.AuthSig == nil ||
+				!inHorizon(edge.LastUpdate) {
+
+				continue
+			}
+			c.chanID = edge.ChannelID
+			c.updates = append(c.updates, createChanUpdate(edge))
+		}
+		if len(c.updates) != 0 {
+			channels = append(channels, c)
+		}
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound &&
+		err != channeldb.ErrGraphNotFound {
+		return nil, err
+	}
+
+	var msgs []lnwire.Message
+	for _, c := range channels {
+		isPrivate, err := graph.IsChannelPrivate(c.chanID)
+		if err != nil || isPrivate {
+			continue
+		}
+		chanAnn, err := createChanAnnouncement(graph, c.chanID)
+		if err != nil {
+			continue
+		}
+
+		msgs = append(msgs, chanAnn)
+		msgs = append(msgs, c.updates...)
+	}
+
+	err = graph.ForEachNode(func(node *channeldb.LightningNode) error {
+		if !inHorizon(node.LastUpdate) {
+			return nil
+		}
+		nodeAnn, err := createNodeAnnouncement(node)
+		if err != nil {
+			return nil
+		}
+
+		msgs = append(msgs, nodeAnn)
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNodesNotFound &&
+		err != channeldb.ErrGraphNotFound {
+		return nil, err
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,271 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// makeSyncerGraph creates a new channel graph containing the two test nodes,
+// backed by a temporary database. A callback which cleans up the database is
+// also returned.
+func makeSyncerGraph(t *testing.T) (*channeldb.ChannelGraph, func()) {
+	tempDirName, err := ioutil.TempDir("", "syncer")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	cdb, err := channeldb.Open(tempDirName)
+	if err != nil {
+		os.RemoveAll(tempDirName)
+		t.Fatalf("unable to open db: %v", err)
+	}
+	cleanUp := func() {
+		cdb.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	graph := cdb.ChannelGraph()
+	for _, key := range []*btcec.PrivateKey{remoteKeyPriv, otherKeyPriv} {
+		node := &channeldb.LightningNode{
+			LastUpdate: time.Unix(1, 0),
+			Address:    testAddr,
+			PubKey:     key.PubKey(),
+			Alias:      "node",
+		}
+		if err := graph.AddLightningNode(node); err != nil {
+			t.Fatalf("unable to add node: %v", err)
+		}
+	}
+
+	return graph, cleanUp
+}
+
+// addSyncerChannel adds an announced channel with the passed ID to the graph,
+// along with an update of the first direction with the passed time stamp.
+func addSyncerChannel(t *testing.T, graph *channeldb.ChannelGraph,
+	chanID lnwire.ChannelID, updateTime uint32) {
+
+	sig, err := remoteKeyPriv.Sign(chainhash.DoubleHashB([]byte("test")))
+	if err != nil {
+		t.Fatalf("unable to create signature: %v", err)
+	}
+
+	outpoint := wire.OutPoint{Index: chanID.TxIndex}
+	err = graph.AddChannelEdge(remoteKeyPriv.PubKey(), otherKeyPriv.PubKey(),
+		&outpoint, chanID.ToUint64())
+	if err != nil {
+		t.Fatalf("unable to add channel: %v", err)
+	}
+	err = graph.AddChannelProof(chanID.ToUint64(), &channeldb.ChannelAuthProof{
+		NodeSig1:    sig,
+		NodeSig2:    sig,
+		BitcoinSig1: sig,
+		BitcoinSig2: sig,
+		BitcoinKey1: remoteBitcoinPriv.PubKey(),
+		BitcoinKey2: otherBitcoinPriv.PubKey(),
+	})
+	if err != nil {
+		t.Fatalf("unable to add proof: %v", err)
+	}
+
+	err = graph.UpdateEdgeInfo(&channeldb.ChannelEdge{
+		ChannelID:    chanID.ToUint64(),
+		ChannelPoint: outpoint,
+		LastUpdate:   time.Unix(int64(updateTime), 0),
+		Flags:        0,
+		AuthSig:      sig,
+	})
+	if err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+}
+
+// TestGossipSyncerReconciliation tests that an active syncer only queries the
+// remote peer for the channels it lacks or holds outdated updates for, and
+// that it requests new updates from the peer once synced.
+func TestGossipSyncerReconciliation(t *testing.T) {
+	remoteGraph, cleanUp := makeSyncerGraph(t)
+	defer cleanUp()
+	localGraph, cleanUp := makeSyncerGraph(t)
+	defer cleanUp()
+
+	// The remote peer knows of five channels. We already know of the
+	// first channel, along with its latest update, and hold an outdated
+	// update for the second. The remaining three channels are unknown to
+	// us.
+	chanIDs := make([]lnwire.ChannelID, 5)
+	for i := range chanIDs {
+		chanIDs[i] = lnwire.ChannelID{
+			BlockHeight: uint32(100 + i),
+			TxIndex:     uint32(i),
+		}
+		addSyncerChannel(t, remoteGraph, chanIDs[i], 1000)
+	}
+	addSyncerChannel(t, localGraph, chanIDs[0], 1000)
+	addSyncerChannel(t, localGraph, chanIDs[1], 500)
+
+	bestHeight := func() (uint32, error) {
+		return 200, nil
+	}
+
+	// We'll link the two syncers together, with any announcements sent by
+	// the remote syncer being collected.
+	var localSyncer, remoteSyncer *gossipSyncer
+	announcements := make(chan lnwire.Message, 100)
+	relay := func(target **gossipSyncer) func(...lnwire.Message) error {
+		return func(msgs ...lnwire.Message) error {
+			for _, msg := range msgs {
+				switch msg.(type) {
+				case *lnwire.ChannelAnnouncement,
+					*lnwire.ChannelUpdateAnnouncement,
+					*lnwire.NodeAnnouncement:
+
+					announcements <- msg
+				default:
+					(*target).ProcessQueryMsg(msg)
+				}
+			}
+			return nil
+		}
+	}
+
+	// Both syncers use a small chunk size to ensure the replies and
+	// queries are properly split up.
+	remoteSyncer = newGossipSyncer(gossipSyncerCfg{
+		peerPub:    selfKeyPriv.PubKey(),
+		graph:      remoteGraph,
+		bestHeight: bestHeight,
+		chunkSize:  2,
+		sendToPeer: relay(&localSyncer),
+	}, PassiveSync)
+	localSyncer = newGossipSyncer(gossipSyncerCfg{
+		peerPub:    remoteKeyPriv.PubKey(),
+		graph:      localGraph,
+		bestHeight: bestHeight,
+		chunkSize:  2,
+		sendToPeer: relay(&remoteSyncer),
+	}, ActiveSync)
+
+	if err := remoteSyncer.Start(); err != nil {
+		t.Fatalf("unable to start syncer: %v", err)
+	}
+	defer remoteSyncer.Stop()
+	if err := localSyncer.Start(); err != nil {
+		t.Fatalf("unable to start syncer: %v", err)
+	}
+	defer localSyncer.Stop()
+
+	// Once synced, the local syncer should have sent its update horizon
+	// to the remote syncer.
+	for i := 0; ; i++ {
+		remoteSyncer.Lock()
+		horizon := remoteSyncer.remoteUpdateHorizon
+		remoteSyncer.Unlock()
+
+		if localSyncer.syncState() == chansSynced && horizon != nil {
+			if horizon.TimestampRange != math.MaxUint32 {
+				t.Fatalf("unexpected update horizon: %v",
+					horizon.TimestampRange)
+			}
+			break
+		}
+		if i == 500 {
+			t.Fatalf("syncers didn't sync, local state: %v",
+				localSyncer.syncState())
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	// We should've only received the announcements of the four channels
+	// which we lacked or held outdated updates for.
+	queried := make(map[uint64]struct{})
+	for len(announcements) != 0 {
+		msg := <-announcements
+		if ann, ok := msg.(*lnwire.ChannelAnnouncement); ok {
+			queried[ann.ChannelID.ToUint64()] = struct{}{}
+		}
+	}
+	if len(queried) != 4 {
+		t.Fatalf("expected 4 channels to be queried, got %v",
+			len(queried))
+	}
+	if _, ok := queried[chanIDs[0].ToUint64()]; ok {
+		t.Fatalf("up to date channel was queried")
+	}
+}
+
+// TestGossipSyncerFilterGossipMsgs tests that only the gossip messages within
+// the update horizon of the remote peer are sent to it.
+func TestGossipSyncerFilterGossipMsgs(t *testing.T) {
+	graph, cleanUp := makeSyncerGraph(t)
+	defer cleanUp()
+
+	sentMsgs := make(chan []lnwire.Message, 1)
+	syncer := newGossipSyncer(gossipSyncerCfg{
+		peerPub: remoteKeyPriv.PubKey(),
+		graph:   graph,
+		sendToPeer: func(msgs ...lnwire.Message) error {
+			sentMsgs <- msgs
+			return nil
+		},
+	}, PassiveSync)
+
+	chanID1 := lnwire.ChannelID{BlockHeight: 100}
+	chanID2 := lnwire.ChannelID{BlockHeight: 101}
+	msgs := []lnwire.Message{
+		&lnwire.ChannelAnnouncement{ChannelID: chanID1},
+		&lnwire.ChannelUpdateAnnouncement{
+			ChannelID: chanID1,
+			Timestamp: 1500,
+		},
+		&lnwire.ChannelAnnouncement{ChannelID: chanID2},
+		&lnwire.ChannelUpdateAnnouncement{
+			ChannelID: chanID2,
+			Timestamp: 500,
+		},
+		&lnwire.NodeAnnouncement{
+			NodeID:    remoteKeyPriv.PubKey(),
+			Timestamp: 2500,
+		},
+	}
+
+	// Without an update horizon, nothing should be sent.
+	syncer.FilterGossipMsgs(msgs...)
+	select {
+	case <-sentMsgs:
+		t.Fatalf("messages sent without an update horizon")
+	default:
+	}
+
+	// Once the horizon is applied, only the first channel, which has an
+	// update within the horizon, should be sent along with its update.
+	err := syncer.applyGossipFilter(&lnwire.GossipTimestampRange{
+		FirstTimestamp: 1000,
+		TimestampRange: 1000,
+	})
+	if err != nil {
+		t.Fatalf("unable to apply gossip filter: %v", err)
+	}
+	syncer.FilterGossipMsgs(msgs...)
+
+	select {
+	case sent := <-sentMsgs:
+		if len(sent) != 2 {
+			t.Fatalf("expected 2 messages to be sent, got %v",
+				len(sent))
+		}
+		if sent[0] != msgs[0] || sent[1] != msgs[1] {
+			t.Fatalf("unexpected messages sent: %v", sent)
+		}
+	default:
+		t.Fatalf("no messages sent")
+	}
+}
@@ -0,0 +1,144 @@
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// ValidateNodeAnn validates the node announcement by ensuring that the
+// attached signature is valid under the advertised node identity key.
+func ValidateNodeAnn(a *lnwire.NodeAnnouncement) error {
+	if a.Signature == nil {
+		return fmt.Errorf("node announcement for %x has no signature",
+			a.NodeID.SerializeCompressed())
+	}
+
+	if err := a.Alias.Validate(); err != nil {
+		return err
+	}
+
+	// Reconstruct the data of announcement which should be covered by the
+	// signature so we can verify the signature shortly below.
+	data, err := a.DataToSign()
+	if err != nil {
+		return err
+	}
+
+	// Finally ensure that the passed signature is valid, if not we'll
+	// return an error so this node announcement can be rejected.
+	dataHash := chainhash.DoubleHashB(data)
+	if !a.Signature.Verify(dataHash, a.NodeID) {
+		return fmt.Errorf("signature on node announcement for %x is "+
+			"invalid", a.NodeID.SerializeCompressed())
+	}
+
+	return nil
+}
+
+// ValidateChannelAnn validates the channel announcement message and checks
+// that all of the included signatures are valid. The bitcoin signatures prove
+// that each funding key is bound to its node's identity, and the node
+// signatures prove that both nodes are willing to route payments over the
+// channel.
+func ValidateChannelAnn(a *lnwire.ChannelAnnouncement) error {
+	if a.FirstNodeSig == nil || a.SecondNodeSig == nil ||
+		a.FirstBitcoinSig == nil || a.SecondBitcoinSig == nil {
+
+		return fmt.Errorf("channel announcement for chan_id=%v is "+
+			"missing signatures", a.ChannelID.ToUint64())
+	}
+
+	// First we'll verify that the passed bitcoin keys are bound to the
+	// node identities of the channel.
+	hash := chainhash.DoubleHashB(a.FirstNodeID.SerializeCompressed())
+	if !a.FirstBitcoinSig.Verify(hash, a.FirstBitcoinKey) {
+		return fmt.Errorf("can't verify first bitcoin signature")
+	}
+	hash = chainhash.DoubleHashB(a.SecondNodeID.SerializeCompressed())
+	if !a.SecondBitcoinSig.Verify(hash, a.SecondBitcoinKey) {
+		return fmt.Errorf("can't verify second bitcoin signature")
+	}
+
+	// With the bitcoin keys verified, we'll now ensure that both nodes
+	// have signed the contents of the announcement.
+	data, err := a.DataToSign()
+	if err != nil {
+		return err
+	}
+	dataHash := chainhash.DoubleHashB(data)
+
+	if !a.FirstNodeSig.Verify(dataHash, a.FirstNodeID) {
+		return fmt.Errorf("can't verify data in first node signature")
+	}
+	if !a.SecondNodeSig.Verify(dataHash, a.SecondNodeID) {
+		return fmt.Errorf("can't verify data in second node signature")
+	}
+
+	return nil
+}
+
+// ValidateChannelUpdateAnn validates the channel update announcement by
+// checking that the included signature covers the announcement and has been
+// signed by the node's private key.
+func ValidateChannelUpdateAnn(pubKey *btcec.PublicKey,
+	a *lnwire.ChannelUpdateAnnouncement) error {
+
+	if a.Signature == nil {
+		return fmt.Errorf("channel update for chan_id=%v has no "+
+			"signature", a.ChannelID.ToUint64())
+	}
+
+	data, err := a.DataToSign()
+	if err != nil {
+		return fmt.Errorf("unable to reconstruct message: %v", err)
+	}
+	dataHash := chainhash.DoubleHashB(data)
+
+	if !a.Signature.Verify(dataHash, pubKey) {
+		return fmt.Errorf("invalid signature for channel update "+
+			"chan_id=%v", a.ChannelID.ToUint64())
+	}
+
+	return nil
+}
+
+// SignAnnouncement signs any type of gossiped announcement, namely a node,
+// channel or channel update announcement, with the key corresponding to the
+// passed public key. The signature covers the portion of the message returned
+// by its DataToSign method.
+func SignAnnouncement(signer lnwallet.MessageSigner, pubKey *btcec.PublicKey,
+	msg lnwire.Message) (*btcec.Signature, error) {
+
+	var (
+		data []byte
+		err  error
+	)
+	switch m := msg.(type) {
+	case *lnwire.ChannelAnnouncement:
+		data, err = m.DataToSign()
+	case *lnwire.ChannelUpdateAnnouncement:
+		data, err = m.DataToSign()
+	case *lnwire.NodeAnnouncement:
+		data, err = m.DataToSign()
+	default:
+		return nil, fmt.Errorf("can't sign %T message", m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get data to sign: %v", err)
+	}
+
+	return signer.SignMessage(pubKey, data)
+}
+
+// SignBitcoinKeyBinding creates the bitcoin signature portion of a channel
+// announcement proof. The signature is produced by the passed funding key
+// over the node's identity key, binding the funding key to the node.
+func SignBitcoinKeyBinding(signer lnwallet.MessageSigner, fundingKey,
+	nodeID *btcec.PublicKey) (*btcec.Signature, error) {
+
+	return signer.SignMessage(fundingKey, nodeID.SerializeCompressed())
+}
@@ -2,13 +2,14 @@ package main
 
 import (
 	"bytes"
-	"encoding/hex"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -23,6 +24,18 @@ import (
 const (
 	// TODO(roasbeef): tune
 	msgBufferSize = 50
+
+	// defaultTimeLockDelta is the time lock delta we advertise within the
+	// routing policy of our own channels.
+	defaultTimeLockDelta = 1
+
+	// defaultFeeBaseMSat is the base fee we advertise within the routing
+	// policy of our own channels.
+	defaultFeeBaseMSat = 0
+
+	// defaultFeeRate is the proportional fee rate, in millionths, we
+	// advertise within the routing policy of our own channels.
+	defaultFeeRate = 0
 )
 
 // reservationWithCtx encapsulates a pending channel reservation. This wrapper
@@ -37,6 +50,10 @@ type reservationWithCtx struct {
 	reservation *lnwallet.ChannelReservation
 	peer        *peer
 
+	// private indicates that the channel shouldn't be announced to the
+	// greater network once open.
+	private bool
+
 	updates chan *lnrpc.OpenStatusUpdate
 	err     chan error
 }
@@ -139,8 +156,6 @@ type fundingManager struct {
 	// requests from a local subsystem within the daemon.
 	fundingRequests chan *initFundingMsg
 
-	fakeProof *channelProof
-
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -148,23 +163,10 @@ type fundingManager struct {
 // newFundingManager creates and initializes a new instance of the
 // fundingManager.
 func newFundingManager(w *lnwallet.LightningWallet, b *breachArbiter) *fundingManager {
-	// TODO(roasbeef): remove once we actually sign the funding_locked
-	// stuffs
-	s := "30450221008ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa" +
-		"1bf0314f882d70220299105481d63e0f4bc2a88121167221b6700d72a0e" +
-		"ad154c03be696a292d24ae"
-	fakeSigHex, _ := hex.DecodeString(s)
-	fakeSig, _ := btcec.ParseSignature(fakeSigHex, btcec.S256())
-
 	return &fundingManager{
 		wallet:        w,
 		breachAribter: b,
 
-		fakeProof: &channelProof{
-			nodeSig:    fakeSig,
-			bitcoinSig: fakeSig,
-		},
-
 		activeReservations: make(map[int32]pendingChannels),
 		fundingMsgs:        make(chan interface{}, msgBufferSize),
 		fundingRequests:    make(chan *initFundingMsg, msgBufferSize),
@@ -408,6 +410,7 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 	f.activeReservations[fmsg.peer.id][msg.ChannelID] = &reservationWithCtx{
 		reservation: reservation,
 		peer:        fmsg.peer,
+		private:     msg.ChannelFlags&lnwire.FFAnnounceChannel == 0,
 	}
 	f.resMtx.Unlock()
 
@@ -601,19 +604,14 @@ func (f *fundingManager) processFundingSignComplete(msg *lnwire.SingleFundingSig
 	f.fundingMsgs <- &fundingSignCompleteMsg{msg, peer}
 }
 
-// channelProof is one half of the proof necessary to create an authenticated
-// announcement on the network. The two signatures individually sign a
-// statement of the existence of a channel.
-type channelProof struct {
-	nodeSig    *btcec.Signature
-	bitcoinSig *btcec.Signature
-}
-
 // chanAnnouncement encapsulates the two authenticated announcements that we
-// send out to the network after a new channel has been created locally.
+// send out to the network after a new channel has been created locally, along
+// with our half of the proof required to authenticate the channel
+// announcement.
 type chanAnnouncement struct {
 	chanAnn    *lnwire.ChannelAnnouncement
 	edgeUpdate *lnwire.ChannelUpdateAnnouncement
+	chanProof  *lnwire.AnnounceSignatures
 }
 
 // newChanAnnouncement creates the authenticated channel announcement messages
@@ -622,10 +620,13 @@ type chanAnnouncement struct {
 // channel and contains four signatures binding the funding pub keys and
 // identity pub keys of both parties to the channel, and the second segment is
 // authenticated only by us and contains our directional routing policy for the
-// channel.
+// channel. As only our half of the channel proof can be created locally, the
+// channel announcement is returned unsigned, with our half of the proof to be
+// exchanged with the remote party as a separate message.
 func newChanAnnouncement(localIdentity *btcec.PublicKey,
-	channel *lnwallet.LightningChannel, chanID lnwire.ChannelID,
-	localProof, remoteProof *channelProof) *chanAnnouncement {
+	signer, fundingSigner lnwallet.MessageSigner,
+	channel *lnwallet.LightningChannel,
+	chanID lnwire.ChannelID) (*chanAnnouncement, error) {
 
 	// First obtain the remote party's identity public key, this will be
 	// used to determine the order of the keys and signatures in the
@@ -656,10 +657,6 @@ func newChanAnnouncement(localIdentity *btcec.PublicKey,
 	if bytes.Compare(selfBytes, remoteBytes) == -1 {
 		chanAnn.FirstNodeID = localPub
 		chanAnn.SecondNodeID = &remotePub
-		chanAnn.FirstNodeSig = localProof.nodeSig
-		chanAnn.SecondNodeSig = remoteProof.nodeSig
-		chanAnn.FirstBitcoinSig = localProof.nodeSig
-		chanAnn.SecondBitcoinSig = remoteProof.nodeSig
 		chanAnn.FirstBitcoinKey = channel.LocalFundingKey
 		chanAnn.SecondBitcoinKey = channel.RemoteFundingKey
 
@@ -669,10 +666,6 @@ func newChanAnnouncement(localIdentity *btcec.PublicKey,
 	} else {
 		chanAnn.FirstNodeID = &remotePub
 		chanAnn.SecondNodeID = localPub
-		chanAnn.FirstNodeSig = remoteProof.nodeSig
-		chanAnn.SecondNodeSig = localProof.nodeSig
-		chanAnn.FirstBitcoinSig = remoteProof.nodeSig
-		chanAnn.SecondBitcoinSig = localProof.nodeSig
 		chanAnn.FirstBitcoinKey = channel.RemoteFundingKey
 		chanAnn.SecondBitcoinKey = channel.LocalFundingKey
 
@@ -681,22 +674,51 @@ func newChanAnnouncement(localIdentity *btcec.PublicKey,
 		chanFlags = 1
 	}
 
-	// TODO(roasbeef): add real sig, populate proper FeeSchema
+	// TODO(roasbeef): populate proper FeeSchema
 	chanUpdateAnn := &lnwire.ChannelUpdateAnnouncement{
-		Signature:                 localProof.nodeSig,
 		ChannelID:                 chanID,
 		Timestamp:                 uint32(time.Now().Unix()),
 		Flags:                     chanFlags,
-		Expiry:                    1,
+		Expiry:                    defaultTimeLockDelta,
 		HtlcMinimumMstat:          0,
-		FeeBaseMstat:              0,
-		FeeProportionalMillionths: 0,
+		HtlcMaximumMstat:          0,
+		FeeBaseMstat:              defaultFeeBaseMSat,
+		FeeProportionalMillionths: defaultFeeRate,
+	}
+
+	// With the update crafted, we'll sign it with our identity key so the
+	// rest of the network is able to authenticate our routing policy.
+	var err error
+	chanUpdateAnn.Signature, err = discovery.SignAnnouncement(signer,
+		localPub, chanUpdateAnn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign channel update: %v", err)
+	}
+
+	// Finally, we'll create our half of the channel proof. The node
+	// signature commits to the contents of the channel announcement,
+	// while the bitcoin signature binds our funding key to our identity.
+	nodeSig, err := discovery.SignAnnouncement(signer, localPub, chanAnn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign channel announcement: %v",
+			err)
+	}
+	bitcoinSig, err := discovery.SignBitcoinKeyBinding(fundingSigner,
+		channel.LocalFundingKey, localPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign funding key binding: %v",
+			err)
 	}
 
 	return &chanAnnouncement{
 		chanAnn:    chanAnn,
 		edgeUpdate: chanUpdateAnn,
-	}
+		chanProof: &lnwire.AnnounceSignatures{
+			ChannelID:        chanID,
+			NodeSignature:    nodeSig,
+			BitcoinSignature: bitcoinSig,
+		},
+	}, nil
 }
 
 // handleFundingSignComplete processes the final message received in a single
@@ -796,8 +818,8 @@ func (f *fundingManager) handleFundingSignComplete(fmsg *fundingSignCompleteMsg)
 		// TODO(roasbeef): should include sigs from funding
 		// locked
 		//  * should be moved to after funding locked is recv'd
-		f.announceChannel(fmsg.peer.server, openChanDetails.Channel,
-			chainID, f.fakeProof, f.fakeProof)
+		f.announceChannel(fmsg.peer, openChanDetails.Channel,
+			chainID, resCtx.private)
 
 		// Finally give the caller a final update notifying them that
 		// the channel is now open.
@@ -819,20 +841,55 @@ func (f *fundingManager) handleFundingSignComplete(fmsg *fundingSignCompleteMsg)
 // announceChannel announces a newly created channel to the rest of the network
 // by crafting the two authenticated announcements required for the peers on the
 // network to recognize the legitimacy of the channel. The crafted
-// announcements are then send to the channel router to handle broadcasting to
-// the network during its next trickle.
-func (f *fundingManager) announceChannel(s *server,
+// announcements are then sent to the gossiper, and our half of the channel
+// proof is sent to the remote peer. Once both halves of the proof have been
+// exchanged, the gossiper broadcasts the channel during its next trickle. If
+// the channel is private, then the announcements are only added to our local
+// channel graph, and are never broadcast.
+func (f *fundingManager) announceChannel(p *peer,
 	channel *lnwallet.LightningChannel, chanID lnwire.ChannelID,
-	localProof, remoteProof *channelProof) {
+	private bool) {
 
-	// TODO(roasbeef): need a Signer.SignMessage method to finalize
-	// advertisements
+	s := p.server
 	localIdentity := s.identityPriv.PubKey()
-	chanAnnouncement := newChanAnnouncement(localIdentity, channel,
-		chanID, localProof, remoteProof)
+	chanAnnouncement, err := newChanAnnouncement(localIdentity,
+		s.nodeSigner, f.wallet, channel, chanID)
+	if err != nil {
+		fndgLog.Errorf("unable to create announcement for "+
+			"chan_id=%v: %v", chanID.ToUint64(), err)
+		return
+	}
+
+	if private {
+		err := s.discoverSrv.ProcessPrivateChannel(
+			chanAnnouncement.chanAnn, chanAnnouncement.edgeUpdate,
+			localIdentity,
+		)
+		if err != nil {
+			fndgLog.Errorf("unable to add private chan_id=%v: %v",
+				chanID.ToUint64(), err)
+		}
+		return
+	}
+
+	for _, msg := range []lnwire.Message{
+		chanAnnouncement.chanAnn,
+		chanAnnouncement.edgeUpdate,
+		chanAnnouncement.chanProof,
+	} {
+		err := <-s.discoverSrv.ProcessLocalAnnouncement(msg,
+			localIdentity)
+		if err != nil {
+			fndgLog.Errorf("unable to process %T for "+
+				"chan_id=%v: %v", msg, chanID.ToUint64(), err)
+			return
+		}
+	}
 
-	s.chanRouter.ProcessRoutingMessage(chanAnnouncement.chanAnn, localIdentity)
-	s.chanRouter.ProcessRoutingMessage(chanAnnouncement.edgeUpdate, localIdentity)
+	// With our own announcements processed, we'll send our half of the
+	// proof to the remote party so they're able to assemble the full
+	// channel announcement.
+	p.queueMsg(chanAnnouncement.chanProof, nil)
 }
 
 // processFundingOpenProof sends a message to the fundingManager allowing it
@@ -882,8 +939,8 @@ func (f *fundingManager) handleFundingOpen(fmsg *fundingOpenMsg) {
 	// recv'd
 	//  * also ensure fault tolerance, scan opened chan on start up check
 	//  for graph existence
-	f.announceChannel(fmsg.peer.server, openChan, fmsg.msg.ChanChainID,
-		f.fakeProof, f.fakeProof)
+	f.announceChannel(fmsg.peer, openChan, fmsg.msg.ChanChainID,
+		resCtx.private)
 
 	// Send the newly opened channel to the breach arbiter to it can watch
 	// for uncooperative channel breaches, potentially punishing the
@@ -952,6 +1009,7 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	f.activeReservations[msg.peer.id][chanID] = &reservationWithCtx{
 		reservation: reservation,
 		peer:        msg.peer,
+		private:     msg.private,
 		updates:     msg.updates,
 		err:         msg.err,
 	}
@@ -984,6 +1042,13 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		ourDustLimit,
 		msg.pushAmt,
 	)
+
+	// Unless the channel is private, we'll signal to the remote peer
+	// that the channel should be announced once open.
+	if !msg.private {
+		fundingReq.ChannelFlags = lnwire.FFAnnounceChannel
+	}
+
 	msg.peer.queueMsg(fundingReq, nil)
 }
 
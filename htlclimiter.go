@@ -0,0 +1,451 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// errPeerRateLimited is returned when a peer attempts to add HTLCs to
+	// our channels faster than the per-peer rate limit allows.
+	errPeerRateLimited = errors.New("peer htlc rate limit exceeded")
+
+	// errChanRateLimited is returned when HTLCs are added to a particular
+	// channel faster than the per-channel rate limit allows.
+	errChanRateLimited = errors.New("channel htlc rate limit exceeded")
+
+	// errMaxPendingForwards is returned when accepting a new HTLC for
+	// forwarding would exceed the number of concurrent pending forwards
+	// permitted on the incoming channel.
+	errMaxPendingForwards = errors.New("max pending forwards exceeded")
+
+	// errPoorReputation is returned when a peer's HTLCs have repeatedly
+	// held our outgoing liquidity for long durations, and reputation
+	// tracking is enabled.
+	errPoorReputation = errors.New("peer reputation below threshold")
+)
+
+// reputationHalfLife is the half-life of a peer's reputation score. Each
+// time an HTLC forwarded on behalf of a peer is held for longer than the
+// hold threshold, the peer's score is incremented by one, regardless of
+// whether the HTLC has been resolved yet. The score then
+// decays exponentially over time, allowing peers to recover from past
+// behavior.
+const reputationHalfLife = time.Hour
+
+// tokenBucket is a simple token bucket rate limiter. The bucket holds at most
+// burst tokens, and is refilled at a rate of rate tokens per second. Each
+// admitted event consumes a single token. A rate of zero disables the
+// limiter entirely.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	tokens     float64
+	lastRefill time.Time
+}
+
+// newTokenBucket returns a new token bucket which is initially full.
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:       rate,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: now,
+	}
+}
+
+// refill tops up the bucket with any tokens accrued since the last refill.
+func (t *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(t.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	t.tokens = math.Min(t.burst, t.tokens+elapsed*t.rate)
+	t.lastRefill = now
+}
+
+// allow returns true if the bucket currently holds at least one token,
+// consuming the token in the process.
+func (t *tokenBucket) allow(now time.Time) bool {
+	if t.rate == 0 {
+		return true
+	}
+
+	t.refill(now)
+	if t.tokens < 1 {
+		return false
+	}
+
+	t.tokens--
+	return true
+}
+
+// available returns the number of whole tokens currently within the bucket.
+func (t *tokenBucket) available(now time.Time) uint32 {
+	if t.rate == 0 {
+		return 0
+	}
+
+	t.refill(now)
+	return uint32(t.tokens)
+}
+
+// htlcLimiterConfig houses the set of limits enforced by the htlcLimiter.
+type htlcLimiterConfig struct {
+	// PeerRate is the number of HTLCs per second a peer may add across
+	// all of its channels with us. A value of zero disables the limit.
+	PeerRate float64
+
+	// PeerBurst is the maximum number of HTLCs a peer may add in a single
+	// burst across all of its channels with us.
+	PeerBurst int
+
+	// ChanRate is the number of HTLCs per second which may be added to
+	// any one channel. A value of zero disables the limit.
+	ChanRate float64
+
+	// ChanBurst is the maximum number of HTLCs which may be added to any
+	// one channel in a single burst.
+	ChanBurst int
+
+	// MaxPendingForwards is the maximum number of HTLCs received over a
+	// single channel which may concurrently be pending resolution
+	// downstream. A value of zero disables the limit.
+	MaxPendingForwards int
+
+	// TrackReputation, if true, enables tracking of peers whose forwarded
+	// HTLCs hold our outgoing liquidity for long durations.
+	TrackReputation bool
+
+	// HoldThreshold is the duration after which a forwarded HTLC is
+	// considered to have been held for a long duration.
+	HoldThreshold time.Duration
+
+	// ReputationThreshold is the reputation score at or above which all
+	// new forwards from the peer will be rejected until the score decays.
+	ReputationThreshold float64
+}
+
+// pendingForward records the time at which a pending forward was accepted,
+// and whether the peer has already been penalized for the duration it has
+// been held.
+type pendingForward struct {
+	accepted  time.Time
+	penalized bool
+}
+
+// chanLimitState is the limiter state of a single channel.
+type chanLimitState struct {
+	bucket *tokenBucket
+
+	// pendingForwards is the set of HTLCs received over the channel which
+	// are pending resolution downstream, keyed by the index of the HTLC
+	// within the remote party's update log.
+	pendingForwards map[uint32]*pendingForward
+}
+
+// peerLimitState is the limiter state of a single peer, along with the
+// state of all its channels.
+type peerLimitState struct {
+	bucket *tokenBucket
+
+	channels map[wire.OutPoint]*chanLimitState
+
+	// reputationScore is the decaying count of forwarded HTLCs which were
+	// held for longer than the hold threshold. scoreUpdated is the last
+	// time the score was decayed.
+	reputationScore float64
+	scoreUpdated    time.Time
+
+	// numRejected is the total number of HTLCs from this peer which were
+	// rejected by the limiter.
+	numRejected uint64
+}
+
+// penalizeHeldForwards increments the peer's reputation score for each of
+// its pending forwards which has been held for longer than the passed hold
+// threshold, yet hasn't been penalized. This ensures that HTLCs which are
+// never resolved still count against the peer.
+func (p *peerLimitState) penalizeHeldForwards(peerKey [33]byte,
+	holdThreshold time.Duration, now time.Time) {
+
+	for chanPoint, chanState := range p.channels {
+		for index, fwd := range chanState.pendingForwards {
+			held := now.Sub(fwd.accepted)
+			if fwd.penalized || held < holdThreshold {
+				continue
+			}
+
+			fwd.penalized = true
+			p.reputationScore++
+
+			hswcLog.Debugf("HTLC(%v:%v) from peer %x unresolved "+
+				"for %v, reputation_score=%.2f", chanPoint,
+				index, peerKey[:], held, p.reputationScore)
+		}
+	}
+}
+
+// decayScore applies exponential decay to the peer's reputation score up to
+// the passed time.
+func (p *peerLimitState) decayScore(now time.Time) {
+	elapsed := now.Sub(p.scoreUpdated)
+	if elapsed <= 0 {
+		return
+	}
+
+	halfLives := float64(elapsed) / float64(reputationHalfLife)
+	p.reputationScore *= math.Pow(0.5, halfLives)
+	p.scoreUpdated = now
+}
+
+// chanLimitSnapshot is a point-in-time view of the limiter state of a
+// channel.
+type chanLimitSnapshot struct {
+	chanPoint       wire.OutPoint
+	tokens          uint32
+	pendingForwards uint32
+}
+
+// peerLimitSnapshot is a point-in-time view of the limiter state of a peer.
+type peerLimitSnapshot struct {
+	pubKey          [33]byte
+	tokens          uint32
+	reputationScore float64
+	restricted      bool
+	numRejected     uint64
+	channels        []chanLimitSnapshot
+}
+
+// htlcLimiter protects our channels from being flooded or jammed by a
+// particular peer. Incoming HTLCs are subject to token bucket rate limits
+// both per peer and per channel, and the number of HTLCs received over a
+// channel which are concurrently pending resolution downstream is capped.
+// Optionally, the limiter also tracks the reputation of peers whose forwarded
+// HTLCs hold our outgoing liquidity for long durations, rejecting new
+// forwards from peers with a poor reputation.
+//
+// NOTE: All methods of the htlcLimiter are safe for concurrent use.
+type htlcLimiter struct {
+	sync.Mutex
+
+	cfg htlcLimiterConfig
+
+	peers map[[33]byte]*peerLimitState
+
+	// now returns the current time. It's a field in order to allow tests
+	// to control the passage of time.
+	now func() time.Time
+}
+
+// newHtlcLimiter creates a new htlcLimiter enforcing the passed limits.
+func newHtlcLimiter(cfg htlcLimiterConfig) *htlcLimiter {
+	return &htlcLimiter{
+		cfg:   cfg,
+		peers: make(map[[33]byte]*peerLimitState),
+		now:   time.Now,
+	}
+}
+
+// fetchState returns the limiter state for the target peer and channel,
+// creating fresh state for either if it doesn't yet exist.
+//
+// NOTE: The limiter's mutex MUST be held when calling this method.
+func (h *htlcLimiter) fetchState(peerKey [33]byte,
+	chanPoint wire.OutPoint) (*peerLimitState, *chanLimitState) {
+
+	now := h.now()
+
+	peerState, ok := h.peers[peerKey]
+	if !ok {
+		peerState = &peerLimitState{
+			bucket: newTokenBucket(h.cfg.PeerRate,
+				h.cfg.PeerBurst, now),
+			channels:     make(map[wire.OutPoint]*chanLimitState),
+			scoreUpdated: now,
+		}
+		h.peers[peerKey] = peerState
+	}
+
+	chanState, ok := peerState.channels[chanPoint]
+	if !ok {
+		chanState = &chanLimitState{
+			bucket: newTokenBucket(h.cfg.ChanRate,
+				h.cfg.ChanBurst, now),
+			pendingForwards: make(map[uint32]*pendingForward),
+		}
+		peerState.channels[chanPoint] = chanState
+	}
+
+	return peerState, chanState
+}
+
+// admitHTLC determines if a newly received HTLC from the target peer over
+// the target channel is within the configured rate limits. A non-nil error
+// is returned if the HTLC should be failed back.
+func (h *htlcLimiter) admitHTLC(peerKey [33]byte, chanPoint wire.OutPoint) error {
+	h.Lock()
+	defer h.Unlock()
+
+	now := h.now()
+	peerState, chanState := h.fetchState(peerKey, chanPoint)
+
+	// We check the channel bucket first so a token isn't needlessly
+	// consumed from the peer's bucket if this particular channel is
+	// being flooded.
+	if !chanState.bucket.allow(now) {
+		peerState.numRejected++
+		return errChanRateLimited
+	}
+	if !peerState.bucket.allow(now) {
+		peerState.numRejected++
+		return errPeerRateLimited
+	}
+
+	return nil
+}
+
+// addPendingForward attempts to reserve a pending forward slot on the
+// target channel for the HTLC with the passed index within the remote
+// party's update log. A non-nil error is returned if the channel is at
+// capacity, or the peer's reputation is too poor to accept any new forwards.
+func (h *htlcLimiter) addPendingForward(peerKey [33]byte,
+	chanPoint wire.OutPoint, htlcIndex uint32) error {
+
+	h.Lock()
+	defer h.Unlock()
+
+	now := h.now()
+	peerState, chanState := h.fetchState(peerKey, chanPoint)
+
+	if h.cfg.TrackReputation {
+		peerState.decayScore(now)
+		peerState.penalizeHeldForwards(
+			peerKey, h.cfg.HoldThreshold, now,
+		)
+		if peerState.reputationScore >= h.cfg.ReputationThreshold {
+			peerState.numRejected++
+			return errPoorReputation
+		}
+	}
+
+	if h.cfg.MaxPendingForwards != 0 &&
+		len(chanState.pendingForwards) >= h.cfg.MaxPendingForwards {
+
+		peerState.numRejected++
+		return errMaxPendingForwards
+	}
+
+	chanState.pendingForwards[htlcIndex] = &pendingForward{
+		accepted: now,
+	}
+
+	return nil
+}
+
+// resolvePendingForward releases the pending forward slot of the HTLC with
+// the target index within the remote party's update log on the passed
+// channel. If reputation tracking is enabled, and the HTLC was held for
+// longer than the hold threshold, then the peer's reputation score is
+// penalized unless it already has been while the HTLC was pending.
+func (h *htlcLimiter) resolvePendingForward(peerKey [33]byte,
+	chanPoint wire.OutPoint, htlcIndex uint32) {
+
+	h.Lock()
+	defer h.Unlock()
+
+	peerState, ok := h.peers[peerKey]
+	if !ok {
+		return
+	}
+	chanState, ok := peerState.channels[chanPoint]
+	if !ok {
+		return
+	}
+
+	fwd, ok := chanState.pendingForwards[htlcIndex]
+	if !ok {
+		return
+	}
+	delete(chanState.pendingForwards, htlcIndex)
+
+	if !h.cfg.TrackReputation || fwd.penalized {
+		return
+	}
+
+	now := h.now()
+	if now.Sub(fwd.accepted) < h.cfg.HoldThreshold {
+		return
+	}
+
+	peerState.decayScore(now)
+	peerState.reputationScore++
+
+	hswcLog.Debugf("HTLC(%v:%v) from peer %x held for %v, "+
+		"reputation_score=%.2f", chanPoint, htlcIndex, peerKey[:],
+		now.Sub(fwd.accepted), peerState.reputationScore)
+}
+
+// removeChannel removes all state related to the target channel. This
+// should be called once the channel is no longer active.
+func (h *htlcLimiter) removeChannel(peerKey [33]byte, chanPoint wire.OutPoint) {
+	h.Lock()
+	defer h.Unlock()
+
+	peerState, ok := h.peers[peerKey]
+	if !ok {
+		return
+	}
+
+	delete(peerState.channels, chanPoint)
+}
+
+// snapshot returns a point-in-time view of the limiter state of each peer
+// we've received HTLCs from.
+func (h *htlcLimiter) snapshot() []peerLimitSnapshot {
+	h.Lock()
+	defer h.Unlock()
+
+	now := h.now()
+
+	snapshots := make([]peerLimitSnapshot, 0, len(h.peers))
+	for peerKey, peerState := range h.peers {
+		peerState.decayScore(now)
+		if h.cfg.TrackReputation {
+			peerState.penalizeHeldForwards(
+				peerKey, h.cfg.HoldThreshold, now,
+			)
+		}
+
+		snapshot := peerLimitSnapshot{
+			pubKey:          peerKey,
+			tokens:          peerState.bucket.available(now),
+			reputationScore: peerState.reputationScore,
+			restricted: h.cfg.TrackReputation &&
+				peerState.reputationScore >= h.cfg.ReputationThreshold,
+			numRejected: peerState.numRejected,
+		}
+
+		for chanPoint, chanState := range peerState.channels {
+			snapshot.channels = append(snapshot.channels,
+				chanLimitSnapshot{
+					chanPoint: chanPoint,
+					tokens:    chanState.bucket.available(now),
+					pendingForwards: uint32(
+						len(chanState.pendingForwards),
+					),
+				},
+			)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// mockClock is a controllable time source for the htlcLimiter.
+type mockClock struct {
+	now time.Time
+}
+
+func (m *mockClock) Now() time.Time {
+	return m.now
+}
+
+func newTestLimiter(cfg htlcLimiterConfig) (*htlcLimiter, *mockClock) {
+	clock := &mockClock{now: time.Unix(1000, 0)}
+
+	limiter := newHtlcLimiter(cfg)
+	limiter.now = clock.Now
+
+	return limiter, clock
+}
+
+func TestHtlcLimiterRateLimits(t *testing.T) {
+	limiter, clock := newTestLimiter(htlcLimiterConfig{
+		PeerRate:  2,
+		PeerBurst: 4,
+		ChanRate:  1,
+		ChanBurst: 2,
+	})
+
+	var peerKey [33]byte
+	chanA := wire.OutPoint{Index: 0}
+	chanB := wire.OutPoint{Index: 1}
+
+	// The channel burst is two, so the first two HTLCs over chanA should
+	// be admitted, while the third should be rejected.
+	for i := 0; i < 2; i++ {
+		if err := limiter.admitHTLC(peerKey, chanA); err != nil {
+			t.Fatalf("htlc #%v should be admitted: %v", i, err)
+		}
+	}
+	if err := limiter.admitHTLC(peerKey, chanA); err != errChanRateLimited {
+		t.Fatalf("expected channel rate limit, got: %v", err)
+	}
+
+	// The peer still has two tokens left within its bucket, so two HTLCs
+	// over chanB should be admitted. At that point the peer's bucket is
+	// exhausted, so a further HTLC is rejected by the peer limit even
+	// though chanB itself has no tokens left either way.
+	for i := 0; i < 2; i++ {
+		if err := limiter.admitHTLC(peerKey, chanB); err != nil {
+			t.Fatalf("htlc #%v should be admitted: %v", i, err)
+		}
+	}
+	clock.now = clock.now.Add(time.Second)
+	if err := limiter.admitHTLC(peerKey, chanA); err != nil {
+		t.Fatalf("htlc should be admitted after refill: %v", err)
+	}
+	if err := limiter.admitHTLC(peerKey, chanB); err != nil {
+		t.Fatalf("htlc should be admitted after refill: %v", err)
+	}
+	if err := limiter.admitHTLC(peerKey, chanB); err != errChanRateLimited {
+		t.Fatalf("expected channel rate limit, got: %v", err)
+	}
+
+	snapshot := limiter.snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("expected a single peer, instead have %v", len(snapshot))
+	}
+	if snapshot[0].numRejected != 2 {
+		t.Fatalf("expected %v rejections, instead have %v", 2,
+			snapshot[0].numRejected)
+	}
+}
+
+func TestHtlcLimiterPendingForwards(t *testing.T) {
+	limiter, clock := newTestLimiter(htlcLimiterConfig{
+		MaxPendingForwards:  2,
+		TrackReputation:     true,
+		HoldThreshold:       time.Minute,
+		ReputationThreshold: 1,
+	})
+
+	var peerKey [33]byte
+	chanPoint := wire.OutPoint{}
+
+	// The first two forwards should be accepted, while the third exceeds
+	// the cap on concurrent pending forwards.
+	if err := limiter.addPendingForward(peerKey, chanPoint, 1); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+	if err := limiter.addPendingForward(peerKey, chanPoint, 2); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+	err := limiter.addPendingForward(peerKey, chanPoint, 3)
+	if err != errMaxPendingForwards {
+		t.Fatalf("expected max pending forwards, got: %v", err)
+	}
+
+	// Resolving the first forward quickly frees up a slot without
+	// affecting the peer's reputation.
+	limiter.resolvePendingForward(peerKey, chanPoint, 1)
+	if err := limiter.addPendingForward(peerKey, chanPoint, 3); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+	limiter.resolvePendingForward(peerKey, chanPoint, 3)
+
+	// Next, we'll resolve the second forward only after it has been held
+	// for longer than the hold threshold. This should push the peer's
+	// reputation score to the threshold, causing any new forwards to be
+	// rejected.
+	clock.now = clock.now.Add(2 * time.Minute)
+	limiter.resolvePendingForward(peerKey, chanPoint, 2)
+
+	err = limiter.addPendingForward(peerKey, chanPoint, 4)
+	if err != errPoorReputation {
+		t.Fatalf("expected poor reputation, got: %v", err)
+	}
+
+	// After a single half-life, the peer's score should have decayed
+	// enough for new forwards to be accepted once again.
+	clock.now = clock.now.Add(reputationHalfLife)
+	if err := limiter.addPendingForward(peerKey, chanPoint, 4); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+}
+
+func TestHtlcLimiterUnresolvedForwards(t *testing.T) {
+	limiter, clock := newTestLimiter(htlcLimiterConfig{
+		TrackReputation:     true,
+		HoldThreshold:       time.Minute,
+		ReputationThreshold: 1,
+	})
+
+	var peerKey [33]byte
+	chanPoint := wire.OutPoint{}
+
+	// We'll accept a forward which then remains unresolved for longer
+	// than the hold threshold. Even though it hasn't been resolved yet,
+	// it should count against the peer, causing new forwards to be
+	// rejected.
+	if err := limiter.addPendingForward(peerKey, chanPoint, 1); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	err := limiter.addPendingForward(peerKey, chanPoint, 2)
+	if err != errPoorReputation {
+		t.Fatalf("expected poor reputation, got: %v", err)
+	}
+
+	// Once the forward is finally resolved, the peer shouldn't be
+	// penalized a second time, so after a single half-life new forwards
+	// should be accepted once again.
+	limiter.resolvePendingForward(peerKey, chanPoint, 1)
+	clock.now = clock.now.Add(reputationHalfLife)
+	if err := limiter.addPendingForward(peerKey, chanPoint, 2); err != nil {
+		t.Fatalf("forward should be accepted: %v", err)
+	}
+}
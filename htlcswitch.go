@@ -61,6 +61,11 @@ type htlcPacket struct {
 	amt     btcutil.Amount
 
 	err chan error
+
+	// preimage is the channel over which the preimage of a locally
+	// initiated payment is delivered once the payment has been settled.
+	// It's only set for packets created via SendHTLC.
+	preimage chan [32]byte
 }
 
 // circuitKey uniquely identifies an active Sphinx (onion routing) circuit
@@ -152,6 +157,15 @@ type htlcSwitch struct {
 	// fully locked in.
 	htlcPlex chan *htlcPacket
 
+	// resolveLocalPayment, if non-nil, is called once an HTLC initiated
+	// by this node has been either settled or cancelled by the link with
+	// the passed channel point. A nil error
+	// indicates the HTLC was settled, revealing the passed preimage. This
+	// allows the outcome of HTLCs which were in flight across a restart,
+	// and therefore no longer have a caller awaiting them, to be recorded.
+	resolveLocalPayment func(payHash [32]byte, chanPoint wire.OutPoint,
+		amt btcutil.Amount, preimage [32]byte, err error)
+
 	// TODO(roasbeef): sampler to log sat/sec and tx/sec
 
 	wg   sync.WaitGroup
@@ -205,13 +219,19 @@ func (h *htlcSwitch) Stop() error {
 // SendHTLC queues a HTLC packet for forwarding over the designated interface.
 // In the event that the interface has insufficient capacity for the payment,
 // an error is returned. Additionally, if the interface cannot be found, an
-// alternative error is returned.
-func (h *htlcSwitch) SendHTLC(htlcPkt *htlcPacket) error {
+// alternative error is returned. If the payment is settled, then the preimage
+// revealed by the destination is returned.
+func (h *htlcSwitch) SendHTLC(htlcPkt *htlcPacket) ([32]byte, error) {
 	htlcPkt.err = make(chan error, 1)
+	htlcPkt.preimage = make(chan [32]byte, 1)
 
 	h.outgoingPayments <- htlcPkt
 
-	return <-htlcPkt.err
+	if err := <-htlcPkt.err; err != nil {
+		return [32]byte{}, err
+	}
+
+	return <-htlcPkt.preimage, nil
 }
 
 // htlcForwarder is responsible for optimally forwarding (and possibly
@@ -404,6 +424,13 @@ out:
 					hswcLog.Debugf("No existing circuit "+
 						"for %x to settle", rHash[:])
 					satSent += pkt.amt
+
+					if h.resolveLocalPayment != nil {
+						h.resolveLocalPayment(rHash,
+							pkt.srcLink, pkt.amt,
+							wireMsg.RedemptionProofs[0],
+							nil)
+					}
 					continue
 				}
 
@@ -442,6 +469,13 @@ out:
 				if !ok {
 					hswcLog.Debugf("No existing circuit "+
 						"for %x to cancel", pkt.payHash)
+
+					if h.resolveLocalPayment != nil {
+						h.resolveLocalPayment(pkt.payHash,
+							pkt.srcLink, pkt.amt,
+							[32]byte{},
+							wireMsg.Reason)
+					}
 					continue
 				}
 
@@ -785,3 +819,19 @@ type linkInfoUpdateMsg struct {
 func (h *htlcSwitch) UpdateLink(chanPoint *wire.OutPoint, bandwidthDelta btcutil.Amount) {
 	h.linkControl <- &linkInfoUpdateMsg{chanPoint, bandwidthDelta}
 }
+
+// LinkBandwidth returns the current spendable balance of the link which
+// encapsulates the channel identified by the passed channel point. If the
+// link isn't currently registered with the switch (the peer is offline, or
+// the channel is being closed), then a bandwidth of zero is returned as the
+// channel is unable to carry any HTLC's at the moment.
+func (h *htlcSwitch) LinkBandwidth(chanPoint *wire.OutPoint) btcutil.Amount {
+	h.chanIndexMtx.RLock()
+	link, ok := h.chanIndex[*chanPoint]
+	h.chanIndexMtx.RUnlock()
+	if !ok {
+		return 0
+	}
+
+	return btcutil.Amount(atomic.LoadInt64(&link.availableBandwidth))
+}
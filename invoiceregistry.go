@@ -2,12 +2,18 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/fastsha256"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
 )
@@ -21,6 +27,33 @@ var (
 	debugPre, _ = chainhash.NewHash(bytes.Repeat([]byte{1}, 32))
 
 	debugHash = chainhash.Hash(fastsha256.Sum256(debugPre[:]))
+
+	// errKeySendDisabled is returned when a keysend payment is received
+	// while the registry isn't configured to accept them.
+	errKeySendDisabled = errors.New("keysend payments aren't accepted")
+
+	// errInvalidKeySendPreimage is returned when the custom records of a
+	// keysend payment don't carry a preimage matching its payment hash.
+	errInvalidKeySendPreimage = errors.New("keysend payment lacks a " +
+		"valid preimage")
+
+	// errNoHeldHTLCs is returned when attempting to settle a hold invoice
+	// for which the registry doesn't hold any HTLCs, and so has no payment
+	// to settle.
+	errNoHeldHTLCs = errors.New("no HTLCs held for hold invoice")
+)
+
+const (
+	// defaultInvoiceExpiryInterval is the interval at which the invoice
+	// registry marks invoices whose expiry has passed as expired.
+	defaultInvoiceExpiryInterval = time.Minute
+
+	// defaultHoldExpiryDelta is the number of blocks before the expiry of
+	// the earliest expiring HTLC held for a hold invoice at which the
+	// invoice is automatically canceled. This leaves us enough time to
+	// cancel the HTLCs backwards before our counterparty is forced to go
+	// on-chain to reclaim them.
+	defaultHoldExpiryDelta = 10
 )
 
 // invoiceRegistry is a central registry of all the outstanding invoices
@@ -31,6 +64,10 @@ type invoiceRegistry struct {
 
 	cdb *channeldb.DB
 
+	// notifier is used to receive notifications of new blocks, in order to
+	// cancel hold invoices whose held HTLCs are close to expiring.
+	notifier chainntnfs.ChainNotifier
+
 	clientMtx           sync.Mutex
 	nextClientID        uint32
 	notificationClients map[uint32]*invoiceSubscription
@@ -39,17 +76,198 @@ type invoiceRegistry struct {
 	// should be only created/used when manual tests require an invoice
 	// that *all* nodes are able to fully settle.
 	debugInvoices map[chainhash.Hash]*channeldb.Invoice
+
+	// mppTimeout is the duration the registry will hold the shards of a
+	// multi-path payment while waiting for the remainder of the payment
+	// to arrive.
+	mppTimeout time.Duration
+
+	// maxOverpayment is the maximum amount by which an invoice may be
+	// overpaid, as a multiple of its value.
+	maxOverpayment float64
+
+	// acceptKeySend indicates whether spontaneous keysend payments, which
+	// carry their preimage within their custom records, are accepted.
+	acceptKeySend bool
+
+	// shardSets tracks the shards of each multi-path payment currently
+	// held by the registry, keyed by payment hash.
+	shardMtx  sync.Mutex
+	shardSets map[chainhash.Hash]*shardSet
+
+	// heldSets tracks the complete payments to hold invoices that are
+	// being held until the invoice is either settled or canceled, keyed by
+	// payment hash. This map is also guarded by the shardMtx.
+	heldSets map[chainhash.Hash]*shardSet
+
+	// holdExpiryDelta is the number of blocks before the expiry of the
+	// earliest expiring HTLC held for a hold invoice at which the invoice
+	// is automatically canceled.
+	holdExpiryDelta uint32
+
+	// expiryInterval is the interval at which the registry marks invoices
+	// whose expiry has passed as expired.
+	expiryInterval time.Duration
+
+	started uint32
+	stopped uint32
+	quit    chan struct{}
+	wg      sync.WaitGroup
 }
 
 // newInvoiceRegistry creates a new invoice registry. The invoice registry
 // wraps the persistent on-disk invoice storage with an additional in-memory
 // layer. The in-memory layer is in place such that debug invoices can be added
-// which are volatile yet available system wide within the daemon.
-func newInvoiceRegistry(cdb *channeldb.DB) *invoiceRegistry {
+// which are volatile yet available system wide within the daemon. Shards of
+// multi-path payments are held for at most mppTimeout before being cancelled,
+// and invoices accept payments overpaying them by at most maxOverpayment times
+// their value. Keysend payments are only accepted if acceptKeySend is true.
+// If non-nil, the passed notifier is used to cancel hold invoices whose held
+// HTLCs are close to expiring.
+func newInvoiceRegistry(cdb *channeldb.DB, notifier chainntnfs.ChainNotifier,
+	mppTimeout time.Duration, maxOverpayment float64,
+	acceptKeySend bool) *invoiceRegistry {
+
 	return &invoiceRegistry{
 		cdb:                 cdb,
+		notifier:            notifier,
 		debugInvoices:       make(map[chainhash.Hash]*channeldb.Invoice),
 		notificationClients: make(map[uint32]*invoiceSubscription),
+		mppTimeout:          mppTimeout,
+		maxOverpayment:      maxOverpayment,
+		acceptKeySend:       acceptKeySend,
+		shardSets:           make(map[chainhash.Hash]*shardSet),
+		heldSets:            make(map[chainhash.Hash]*shardSet),
+		holdExpiryDelta:     defaultHoldExpiryDelta,
+		expiryInterval:      defaultInvoiceExpiryInterval,
+		quit:                make(chan struct{}),
+	}
+}
+
+// Start launches the goroutines responsible for marking invoices whose expiry
+// has passed as expired, and canceling hold invoices whose held HTLCs are
+// close to expiring. Any accepted hold invoices whose HTLCs were lost across
+// a restart are canceled first.
+func (i *invoiceRegistry) Start() error {
+	if !atomic.CompareAndSwapUint32(&i.started, 0, 1) {
+		return nil
+	}
+
+	if err := i.cancelOrphanedHolds(); err != nil {
+		return err
+	}
+
+	if i.notifier != nil {
+		blockEpochs, err := i.notifier.RegisterBlockEpochNtfn()
+		if err != nil {
+			return err
+		}
+
+		i.wg.Add(1)
+		go i.holdExpiryWatcher(blockEpochs)
+	}
+
+	i.wg.Add(1)
+	go i.invoiceExpirer()
+
+	return nil
+}
+
+// Stop signals the registry's goroutines to exit, and waits for them to do
+// so.
+func (i *invoiceRegistry) Stop() error {
+	if !atomic.CompareAndSwapUint32(&i.stopped, 0, 1) {
+		return nil
+	}
+
+	close(i.quit)
+	i.wg.Wait()
+
+	return nil
+}
+
+// invoiceExpirer periodically marks all open invoices whose expiry has
+// passed as expired, notifying clients of each. Any shards of multi-path
+// payments to the newly expired invoices held by the registry are cancelled.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *invoiceRegistry) invoiceExpirer() {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		// We'll expire invoices immediately on start up, as they may
+		// have expired while we were offline.
+		expired, err := i.cdb.ExpireInvoices(time.Now())
+		if err != nil {
+			ltndLog.Errorf("unable to expire invoices: %v", err)
+		}
+
+		for _, invoice := range expired {
+			rHash := chainhash.Hash(invoice.PaymentHash())
+
+			ltndLog.Infof("Invoice %x expired", rHash[:])
+
+			i.cancelShardSet(rHash)
+			i.notifyClients(invoice)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// holdExpiryWatcher cancels hold invoices once the chain comes within
+// holdExpiryDelta blocks of the expiry of any of their held HTLCs.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *invoiceRegistry) holdExpiryWatcher(
+	blockEpochs *chainntnfs.BlockEpochEvent) {
+
+	defer i.wg.Done()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			i.cancelExpiringHolds(uint32(epoch.Height))
+
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// cancelExpiringHolds cancels all hold invoices with a held HTLC that expires
+// within holdExpiryDelta blocks of the passed height.
+func (i *invoiceRegistry) cancelExpiringHolds(height uint32) {
+	var expiring []chainhash.Hash
+
+	i.shardMtx.Lock()
+	for rHash, set := range i.heldSets {
+		expiry := set.earliestExpiry()
+		if expiry != 0 && height+i.holdExpiryDelta >= expiry {
+			expiring = append(expiring, rHash)
+		}
+	}
+	i.shardMtx.Unlock()
+
+	for _, rHash := range expiring {
+		ltndLog.Infof("Canceling hold invoice %x: held HTLCs expire "+
+			"within %v blocks", rHash[:], i.holdExpiryDelta)
+
+		if err := i.CancelInvoice(rHash); err != nil {
+			ltndLog.Errorf("unable to cancel hold invoice %x: %v",
+				rHash[:], err)
+		}
 	}
 }
 
@@ -92,8 +310,9 @@ func (i *invoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
 		return err
 	}
 
-	// TODO(roasbeef): re-enable?
-	//go i.notifyClients(invoice, false)
+	// With the invoice added, its add index has been populated, so we can
+	// now notify all clients of the new invoice.
+	i.notifyClients(invoice)
 
 	return nil
 }
@@ -118,11 +337,250 @@ func (i *invoiceRegistry) LookupInvoice(rHash chainhash.Hash) (*channeldb.Invoic
 	return i.cdb.LookupInvoice(rHash)
 }
 
-// SettleInvoice attempts to mark an invoice as settled. If the invoice is a
-// debug invoice, then this method is a noop as debug invoices are never fully
-// settled.
-func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
-	ltndLog.Debugf("Settling invoice %x", rHash[:])
+// keySendInvoice returns the invoice which would be created for a spontaneous
+// keysend payment to the passed payment hash, totalling the passed amount,
+// without adding it to the database. An error is returned if keysend payments
+// aren't accepted, or the custom records don't carry the payment's preimage.
+func (i *invoiceRegistry) keySendInvoice(rHash chainhash.Hash,
+	amt btcutil.Amount,
+	customRecords map[uint64][]byte) (*channeldb.Invoice, error) {
+
+	if !i.acceptKeySend {
+		return nil, errKeySendDisabled
+	}
+
+	preimage := customRecords[routing.KeySendRecordType]
+	if len(preimage) != 32 || fastsha256.Sum256(preimage) != rHash {
+		return nil, errInvalidKeySendPreimage
+	}
+
+	invoice := &channeldb.Invoice{
+		CreationDate:  time.Now(),
+		CustomRecords: customRecords,
+		Terms: channeldb.ContractTerm{
+			Value: amt,
+		},
+	}
+	copy(invoice.Terms.PaymentPreimage[:], preimage)
+
+	return invoice, nil
+}
+
+// AddKeySendInvoice creates an invoice on the fly for a spontaneous keysend
+// payment to the passed payment hash, totalling the passed amount. The
+// preimage of the payment is carried within its custom records, which are
+// stored along side the invoice. If an invoice for the payment has already
+// been created, for example by another shard of the payment, then it's
+// returned instead. It should only be called once an HTLC of the payment has
+// been locked in.
+func (i *invoiceRegistry) AddKeySendInvoice(rHash chainhash.Hash,
+	amt btcutil.Amount,
+	customRecords map[uint64][]byte) (*channeldb.Invoice, error) {
+
+	invoice, err := i.keySendInvoice(rHash, amt, customRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.AddInvoice(invoice)
+	switch {
+	case err == channeldb.ErrDuplicateInvoice:
+		return i.LookupInvoice(rHash)
+	case err != nil:
+		return nil, err
+	}
+
+	ltndLog.Infof("Created invoice for keysend payment %x of %v",
+		rHash[:], amt)
+
+	return invoice, nil
+}
+
+// CancelInvoice attempts to mark an invoice as canceled, after which any
+// HTLCs paying to it are rejected. Any shards of a multi-path payment to the
+// invoice held by the registry, including the HTLCs held for an accepted hold
+// invoice, are cancelled, and all invoice notification clients are notified of
+// the canceled invoice. Debug invoices can't be canceled.
+func (i *invoiceRegistry) CancelInvoice(rHash chainhash.Hash) error {
+	ltndLog.Debugf("Canceling invoice %x", rHash[:])
+
+	i.RLock()
+	_, ok := i.debugInvoices[rHash]
+	i.RUnlock()
+	if ok {
+		return fmt.Errorf("debug invoices can't be canceled")
+	}
+
+	if err := i.cdb.CancelInvoice(rHash); err != nil {
+		return err
+	}
+
+	i.cancelShardSet(rHash)
+	i.notifyInvoice(rHash)
+
+	return nil
+}
+
+// SettleHoldInvoice settles the accepted hold invoice whose payment hash
+// matches the passed preimage, settling all the HTLCs held for it. If the
+// registry doesn't hold any HTLCs for the invoice, then errNoHeldHTLCs is
+// returned, and the invoice is left untouched.
+func (i *invoiceRegistry) SettleHoldInvoice(preimage [32]byte) error {
+	rHash := chainhash.Hash(fastsha256.Sum256(preimage[:]))
+
+	ltndLog.Debugf("Settling hold invoice %x", rHash[:])
+
+	// We'll take ownership of the held HTLCs before settling the invoice,
+	// ensuring they can't be concurrently canceled once the invoice has
+	// been marked as settled.
+	i.shardMtx.Lock()
+	set, ok := i.heldSets[rHash]
+	delete(i.heldSets, rHash)
+	i.shardMtx.Unlock()
+
+	if !ok {
+		return errNoHeldHTLCs
+	}
+
+	if err := i.cdb.SettleHoldInvoice(preimage); err != nil {
+		i.shardMtx.Lock()
+		i.heldSets[rHash] = set
+		i.shardMtx.Unlock()
+
+		return err
+	}
+
+	// With the invoice settled, we'll settle each of the HTLCs held for
+	// it.
+	set.resolveAll(rHash, &preimage, 0)
+
+	i.notifyInvoice(rHash)
+
+	return nil
+}
+
+// cancelOrphanedHolds cancels all accepted hold invoices for which the
+// registry doesn't hold any HTLCs. As held HTLCs are only tracked in memory,
+// the HTLCs of any invoice accepted before a restart can no longer be
+// settled, nor canceled once they near expiry, so the invoices are canceled
+// on start up.
+//
+// NOTE: Only the invoices are canceled. The HTLCs themselves remain locked in
+// within their channels, as the htlcManager doesn't yet process HTLCs
+// restored after a restart, and the log indexes they're restored at needn't
+// match those the remote party assigned. They're left to the remote party to
+// time out once their expiry passes.
+func (i *invoiceRegistry) cancelOrphanedHolds() error {
+	invoices, err := i.cdb.FetchAllInvoices(true)
+	if err != nil && err != channeldb.ErrNoInvoicesCreated {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		if invoice.Terms.State != channeldb.ContractAccepted {
+			continue
+		}
+
+		rHash := chainhash.Hash(invoice.PaymentHash())
+
+		i.shardMtx.Lock()
+		_, held := i.heldSets[rHash]
+		i.shardMtx.Unlock()
+		if held {
+			continue
+		}
+
+		ltndLog.Infof("Canceling hold invoice %x: held HTLCs lost "+
+			"on restart", rHash[:])
+
+		if err := i.CancelInvoice(rHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// acceptHoldInvoice marks the hold invoice with the passed payment hash as
+// accepted, holding the HTLCs of the complete payment to it until the
+// invoice is either settled or canceled.
+func (i *invoiceRegistry) acceptHoldInvoice(rHash chainhash.Hash,
+	set *shardSet) {
+
+	// We'll track the held HTLCs before marking the invoice as accepted,
+	// ensuring they're found if the invoice is settled immediately after.
+	i.shardMtx.Lock()
+	i.heldSets[rHash] = set
+	i.shardMtx.Unlock()
+
+	err := i.cdb.AcceptInvoice(rHash, set.received, set.customRecords)
+	if err != nil {
+		ltndLog.Errorf("unable to accept hold invoice %x: %v",
+			rHash[:], err)
+
+		i.shardMtx.Lock()
+		delete(i.heldSets, rHash)
+		i.shardMtx.Unlock()
+
+		set.resolveAll(rHash, nil, lnwire.UnknownPaymentHash)
+		return
+	}
+
+	ltndLog.Infof("Accepted payment of %v to hold invoice %x, holding %v "+
+		"HTLCs", set.received, rHash[:], len(set.shards))
+
+	i.notifyInvoice(rHash)
+}
+
+// isInvoicePayable returns true if an HTLC paying to the passed invoice may
+// be accepted at the given time. Payment is refused for invoices which have
+// been canceled, or whose expiry has passed, even if the registry has yet to
+// mark them as expired.
+func isInvoicePayable(invoice *channeldb.Invoice, now time.Time) bool {
+	switch invoice.Terms.State {
+	case channeldb.ContractOpen:
+		return invoice.ExpiryDate.IsZero() ||
+			now.Before(invoice.ExpiryDate)
+
+	// Accepted hold invoices are already fully paid, and have their
+	// payment held, so no further payments are accepted.
+	case channeldb.ContractAccepted:
+		return false
+
+	// As the preimage of a settled invoice has already been revealed,
+	// there's no harm in accepting further payments to it.
+	case channeldb.ContractSettled:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// acceptsAmount returns true if a payment of the passed amount satisfies the
+// value of the invoice, without overpaying it by more than the registry's
+// maxOverpayment. Invoices which don't request a specific amount accept any
+// positive amount.
+func (i *invoiceRegistry) acceptsAmount(invoice *channeldb.Invoice,
+	amt btcutil.Amount) bool {
+
+	value := invoice.Terms.Value
+	if value == 0 {
+		return amt > 0
+	}
+
+	maxAmt := value + btcutil.Amount(float64(value)*i.maxOverpayment)
+	return amt >= value && amt <= maxAmt
+}
+
+// SettleInvoice attempts to mark an invoice as settled, recording the amount
+// paid to it, along with any custom records delivered by the payment. If the
+// invoice is a debug invoice, then this method is a noop as debug invoices are
+// never fully settled.
+func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash,
+	amtPaid btcutil.Amount, customRecords map[uint64][]byte) error {
+
+	ltndLog.Debugf("Settling invoice %x, paid %v", rHash[:], amtPaid)
 
 	// First check the in-memory debug invoice index to see if this is an
 	// existing invoice added for debugging.
@@ -138,44 +596,342 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 
 	// If this isn't a debug invoice, then we'll attempt to settle an
 	// invoice matching this rHash on disk (if one exists).
-	if err := i.cdb.SettleInvoice(rHash); err != nil {
+	if err := i.cdb.SettleInvoice(rHash, amtPaid, customRecords); err != nil {
 		return err
 	}
 
-	// Launch a new goroutine to notify any/all registered invoice
-	// notification clients.
-	go func() {
-		invoice, err := i.cdb.LookupInvoice(rHash)
-		if err != nil {
-			ltndLog.Errorf("unable to find invoice: %v", err)
-			return
+	// Notify any/all registered invoice notification clients. As clients
+	// queue their notifications, this won't block, and ensures clients
+	// are notified in the order the invoice's updates are made.
+	i.notifyInvoice(rHash)
+
+	return nil
+}
+
+// SettlePayment settles the invoice paid by a locked-in HTLC of the passed
+// amount, returning the preimage with which the HTLC may be settled. The
+// invoice is marked as settled before the preimage is returned, so an error
+// is returned instead if the invoice no longer accepts payment.
+func (i *invoiceRegistry) SettlePayment(rHash chainhash.Hash,
+	amtPaid btcutil.Amount) ([32]byte, error) {
+
+	invoice, err := i.LookupInvoice(rHash)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	// The invoice may have been canceled, or have expired, since the HTLC
+	// was first received.
+	if !isInvoicePayable(invoice, time.Now()) {
+		return [32]byte{}, fmt.Errorf("invoice for payment hash %x "+
+			"no longer payable: state=%v", rHash[:],
+			invoice.Terms.State)
+	}
+
+	if err := i.SettleInvoice(rHash, amtPaid, nil); err != nil {
+		return [32]byte{}, err
+	}
+
+	return invoice.Terms.PaymentPreimage, nil
+}
+
+// paymentShard is a single locked-in HTLC, received over one of our
+// channels, which carries a portion of a multi-path payment.
+type paymentShard struct {
+	// htlcIndex is the index of the HTLC within the remote party's update
+	// log of the channel it was received over.
+	htlcIndex uint32
+
+	// amt is the value of the HTLC.
+	amt btcutil.Amount
+
+	// resolutions is the channel over which the shard's resolution is
+	// delivered to the htlcManager of the channel the HTLC was received
+	// over.
+	resolutions chan<- *shardResolution
+
+	// quit is closed once the resolution can no longer be delivered.
+	quit <-chan struct{}
+
+	// expiry is the absolute block height at which the shard's HTLC
+	// expires. A value of zero indicates the HTLC carries no expiry.
+	expiry uint32
+
+	// customRecords is the set of custom records carried within the
+	// final hop payload of the shard's HTLC.
+	customRecords map[uint64][]byte
+}
+
+// resolve delivers the passed resolution to the channel the shard was
+// received over.
+func (p *paymentShard) resolve(res *shardResolution) {
+	select {
+	case p.resolutions <- res:
+	case <-p.quit:
+	}
+}
+
+// shardResolution is the final resolution of a held payment shard. If the
+// preimage is non-nil, then the shard's HTLC should be settled. Otherwise,
+// the HTLC should be cancelled with the given reason. The HTLC is identified
+// by its index within the remote party's update log, as several HTLCs over
+// the same channel may share a payment hash.
+type shardResolution struct {
+	rHash     chainhash.Hash
+	htlcIndex uint32
+	amt       btcutil.Amount
+	preimage  *[32]byte
+	reason    lnwire.CancelReason
+}
+
+// shardSet is the set of shards of a single multi-path payment which have
+// arrived thus far.
+type shardSet struct {
+	// total is the total amount of the payment, as specified within the
+	// final hop payload of each of its shards.
+	total btcutil.Amount
+
+	// received is the sum of the amounts of all the shards in the set.
+	received btcutil.Amount
+
+	shards []*paymentShard
+
+	// customRecords is the union of the custom records carried by the
+	// shards in the set, which are stored with the invoice once the
+	// payment completes.
+	customRecords map[uint64][]byte
+
+	// timer fires once the registry gives up waiting for the remainder
+	// of the payment.
+	timer *time.Timer
+}
+
+// earliestExpiry returns the earliest expiry height of the shards within the
+// set, ignoring shards without an expiry. Zero is returned if none of the
+// shards carry an expiry.
+func (s *shardSet) earliestExpiry() uint32 {
+	var earliest uint32
+	for _, shard := range s.shards {
+		if shard.expiry != 0 && (earliest == 0 || shard.expiry < earliest) {
+			earliest = shard.expiry
 		}
+	}
 
-		ltndLog.Infof("Payment received: %v", spew.Sdump(invoice))
+	return earliest
+}
 
-		i.notifyClients(invoice, true)
-	}()
+// resolveAll delivers a resolution to each shard within the set. If preimage
+// is nil, then all the shards are cancelled with the passed reason.
+func (s *shardSet) resolveAll(rHash chainhash.Hash, preimage *[32]byte,
+	reason lnwire.CancelReason) {
 
-	return nil
+	for _, shard := range s.shards {
+		go shard.resolve(&shardResolution{
+			rHash:     rHash,
+			htlcIndex: shard.htlcIndex,
+			amt:       shard.amt,
+			preimage:  preimage,
+			reason:    reason,
+		})
+	}
+}
+
+// AddPaymentShard hands a locked-in shard of a multi-path payment to the
+// registry. The registry holds the shards of each payment until their amounts
+// add up to the total amount of the payment, at which point all the shards
+// are settled at once, and the invoice is marked as settled. If the
+// remainder of the payment doesn't arrive before the registry's mppTimeout
+// elapses, then all the held shards are cancelled instead. Payments to hold
+// invoices are also handed to the registry as shards, which are held until
+// the invoice is settled or canceled once the payment is complete.
+//
+// NOTE: The resolution of the shard is always delivered asynchronously, so
+// this method may safely be called from the goroutine which receives it.
+func (i *invoiceRegistry) AddPaymentShard(rHash chainhash.Hash,
+	total btcutil.Amount, shard *paymentShard) {
+
+	i.shardMtx.Lock()
+
+	set, ok := i.shardSets[rHash]
+	if !ok {
+		set = &shardSet{
+			total: total,
+		}
+		set.timer = time.AfterFunc(i.mppTimeout, func() {
+			i.expireShardSet(rHash, set)
+		})
+		i.shardSets[rHash] = set
+	}
+
+	// All shards of a payment must agree on the payment's total amount,
+	// otherwise we'll reject the offending shard.
+	if total != set.total {
+		i.shardMtx.Unlock()
+
+		ltndLog.Errorf("rejecting shard of payment %x: total amount "+
+			"%v doesn't match expected total %v", rHash[:], total,
+			set.total)
+
+		go shard.resolve(&shardResolution{
+			rHash:     rHash,
+			htlcIndex: shard.htlcIndex,
+			amt:       shard.amt,
+			reason:    lnwire.IncorrectValue,
+		})
+		return
+	}
+
+	set.shards = append(set.shards, shard)
+	set.received += shard.amt
+	for recordType, value := range shard.customRecords {
+		if set.customRecords == nil {
+			set.customRecords = make(map[uint64][]byte)
+		}
+		set.customRecords[recordType] = value
+	}
+
+	ltndLog.Debugf("Received shard of %v for payment %x, %v of %v "+
+		"received", shard.amt, rHash[:], set.received, set.total)
+
+	// If the remainder of the payment has yet to arrive, then we'll
+	// continue to hold the shard.
+	if set.received < set.total {
+		i.shardMtx.Unlock()
+		return
+	}
+
+	// Otherwise, the full amount of the payment has arrived, so we no
+	// longer need to track the set.
+	set.timer.Stop()
+	delete(i.shardSets, rHash)
+	i.shardMtx.Unlock()
+
+	// With the payment complete, we'll settle each of the shards using
+	// the preimage of the invoice, then mark the invoice itself as
+	// settled.
+	invoice, err := i.LookupInvoice(rHash)
+	if err != nil {
+		ltndLog.Errorf("unable to find invoice for payment %x: %v",
+			rHash[:], err)
+		set.resolveAll(rHash, nil, lnwire.UnknownPaymentHash)
+		return
+	}
+
+	// The invoice may have been canceled, or have expired, while we were
+	// waiting for the remainder of the payment.
+	if !isInvoicePayable(invoice, time.Now()) {
+		ltndLog.Errorf("rejecting payment %x: invoice no longer "+
+			"payable", rHash[:])
+		set.resolveAll(rHash, nil, lnwire.UnknownPaymentHash)
+		return
+	}
+
+	// Each shard was only checked against the total amount claimed by
+	// the payer, so we'll ensure the amount which actually arrived
+	// neither underpays nor overpays the invoice by more than we allow.
+	if !i.acceptsAmount(invoice, set.received) {
+		ltndLog.Errorf("rejecting payment %x: incorrect amount: "+
+			"requested %v, received %v", rHash[:],
+			invoice.Terms.Value, set.received)
+		set.resolveAll(rHash, nil, lnwire.IncorrectValue)
+		return
+	}
+
+	// If this is a payment to a hold invoice, then we don't yet know the
+	// preimage, so we'll hold the payment until the invoice is settled or
+	// canceled.
+	if invoice.IsHold() {
+		i.acceptHoldInvoice(rHash, set)
+		return
+	}
+
+	// The invoice is marked as settled before the preimage is released,
+	// ensuring we never reveal the preimage of an invoice which was
+	// concurrently canceled or expired.
+	err = i.SettleInvoice(rHash, set.received, set.customRecords)
+	if err != nil {
+		ltndLog.Errorf("unable to settle invoice: %v", err)
+		set.resolveAll(rHash, nil, lnwire.UnknownPaymentHash)
+		return
+	}
+
+	preimage := invoice.Terms.PaymentPreimage
+	set.resolveAll(rHash, &preimage, 0)
+}
+
+// expireShardSet cancels all the shards of a multi-path payment whose
+// remainder failed to arrive within the registry's mppTimeout.
+func (i *invoiceRegistry) expireShardSet(rHash chainhash.Hash, set *shardSet) {
+	i.shardMtx.Lock()
+	if i.shardSets[rHash] != set {
+		i.shardMtx.Unlock()
+		return
+	}
+	delete(i.shardSets, rHash)
+	i.shardMtx.Unlock()
+
+	ltndLog.Infof("Cancelling %v shards of payment %x: only %v of %v "+
+		"received before timeout", len(set.shards), rHash[:],
+		set.received, set.total)
+
+	set.resolveAll(rHash, nil, lnwire.MPPTimeout)
+}
+
+// cancelShardSet cancels all the shards of a payment to the invoice with the
+// passed payment hash that are currently held by the registry, as the invoice
+// will no longer accept payment. This includes both the shards of incomplete
+// multi-path payments, and the HTLCs held for accepted hold invoices.
+func (i *invoiceRegistry) cancelShardSet(rHash chainhash.Hash) {
+	i.shardMtx.Lock()
+	set, ok := i.shardSets[rHash]
+	if ok {
+		set.timer.Stop()
+		delete(i.shardSets, rHash)
+	} else {
+		set, ok = i.heldSets[rHash]
+		delete(i.heldSets, rHash)
+	}
+	i.shardMtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	ltndLog.Infof("Cancelling %v held shards of payment %x", len(set.shards),
+		rHash[:])
+
+	set.resolveAll(rHash, nil, lnwire.UnknownPaymentHash)
+}
+
+// notifyInvoice looks up the invoice with the passed payment hash, and
+// notifies all currently registered invoice notification clients of its
+// current state.
+func (i *invoiceRegistry) notifyInvoice(rHash chainhash.Hash) {
+	invoice, err := i.cdb.LookupInvoice(rHash)
+	if err != nil {
+		ltndLog.Errorf("unable to find invoice: %v", err)
+		return
+	}
+
+	switch invoice.Terms.State {
+	case channeldb.ContractSettled:
+		ltndLog.Infof("Payment received: %v", spew.Sdump(invoice))
+	case channeldb.ContractAccepted:
+		ltndLog.Infof("Payment accepted: %v", spew.Sdump(invoice))
+	}
+
+	i.notifyClients(invoice)
 }
 
 // notifyClients notifies all currently registered invoice notification clients
-// of a newly added/settled invoice.
-func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool) {
+// of a newly added, accepted, settled, canceled or expired invoice, according
+// to the invoice's state.
+func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice) {
 	i.clientMtx.Lock()
 	defer i.clientMtx.Unlock()
 
 	for _, client := range i.notificationClients {
-		var eventChan chan *channeldb.Invoice
-		if settle {
-			eventChan = client.SettledInvoices
-		} else {
-			eventChan = client.NewInvoices
-		}
-
-		go func() {
-			eventChan <- invoice
-		}()
+		client.enqueue(invoice)
 	}
 }
 
@@ -183,31 +939,186 @@ func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool)
 // or settled invoices. For each newly added invoice, a copy of the invoice
 // will be sent over the NewInvoices channel. Similarly, for each newly settled
 // invoice, a copy of the invoice will be sent over the SettledInvoices
-// channel.
+// channel, for each hold invoice whose payment has been accepted, a copy of
+// the invoice will be sent over the AcceptedInvoices channel, and for each
+// canceled or expired invoice, a copy of the invoice will be sent over the
+// CanceledInvoices channel. Expired invoices may be told apart from canceled
+// ones by their state.
+//
+// Notifications are delivered in the order they're dispatched by the
+// registry. Any invoices added or settled before the subscription was created,
+// but after the add and settle indexes it was created with, are delivered
+// first.
 type invoiceSubscription struct {
-	NewInvoices     chan *channeldb.Invoice
-	SettledInvoices chan *channeldb.Invoice
+	NewInvoices      chan *channeldb.Invoice
+	AcceptedInvoices chan *channeldb.Invoice
+	SettledInvoices  chan *channeldb.Invoice
+	CanceledInvoices chan *channeldb.Invoice
+
+	// addIndex and settleIndex are the latest add and settle indexes of
+	// the invoices delivered to the client. As the client is registered
+	// before its backlog is fetched, and an invoice may be notified more
+	// than once, any notifications of invoices at or below these indexes
+	// have already been delivered, so they're skipped.
+	addIndex    uint64
+	settleIndex uint64
+
+	// ntfnQueue is the queue of notifications yet to be delivered to the
+	// client, which is guarded by the ntfnMtx. The ntfnSignal channel is
+	// signalled each time a notification is added to the queue.
+	ntfnMtx    sync.Mutex
+	ntfnQueue  []*channeldb.Invoice
+	ntfnSignal chan struct{}
 
 	inv *invoiceRegistry
 	id  uint32
+
+	cancelled uint32
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// enqueue adds the passed invoice to the client's queue of notifications,
+// without blocking.
+func (i *invoiceSubscription) enqueue(invoice *channeldb.Invoice) {
+	i.ntfnMtx.Lock()
+	i.ntfnQueue = append(i.ntfnQueue, invoice)
+	i.ntfnMtx.Unlock()
+
+	select {
+	case i.ntfnSignal <- struct{}{}:
+	default:
+	}
+}
+
+// notificationDispatcher delivers the passed backlog of added and settled
+// invoices to the client, followed by all live notifications dispatched by
+// the registry.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *invoiceSubscription) notificationDispatcher(addBacklog,
+	settleBacklog []*channeldb.Invoice) {
+
+	defer i.wg.Done()
+
+	for _, invoice := range addBacklog {
+		if !i.deliver(i.NewInvoices, invoice) {
+			return
+		}
+	}
+	for _, invoice := range settleBacklog {
+		if !i.deliver(i.SettledInvoices, invoice) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-i.ntfnSignal:
+		case <-i.quit:
+			return
+		case <-i.inv.quit:
+			return
+		}
+
+		i.ntfnMtx.Lock()
+		ntfns := i.ntfnQueue
+		i.ntfnQueue = nil
+		i.ntfnMtx.Unlock()
+
+		for _, invoice := range ntfns {
+			var eventChan chan *channeldb.Invoice
+			switch invoice.Terms.State {
+			case channeldb.ContractOpen:
+				if invoice.AddIndex <= i.addIndex {
+					continue
+				}
+				eventChan = i.NewInvoices
+
+			case channeldb.ContractAccepted:
+				eventChan = i.AcceptedInvoices
+
+			case channeldb.ContractSettled:
+				if invoice.SettleIndex <= i.settleIndex {
+					continue
+				}
+				eventChan = i.SettledInvoices
+
+			case channeldb.ContractCanceled,
+				channeldb.ContractExpired:
+
+				eventChan = i.CanceledInvoices
+
+			default:
+				continue
+			}
+
+			if !i.deliver(eventChan, invoice) {
+				return
+			}
+
+			// With the invoice delivered, we'll advance the
+			// client's indexes so that it isn't delivered again.
+			switch {
+			case eventChan == i.NewInvoices:
+				i.addIndex = invoice.AddIndex
+			case eventChan == i.SettledInvoices:
+				i.settleIndex = invoice.SettleIndex
+			}
+		}
+	}
+}
+
+// deliver sends the invoice over the passed channel, returning false if the
+// subscription was cancelled, or the registry is shutting down, before the
+// invoice could be delivered.
+func (i *invoiceSubscription) deliver(eventChan chan *channeldb.Invoice,
+	invoice *channeldb.Invoice) bool {
+
+	select {
+	case eventChan <- invoice:
+		return true
+	case <-i.quit:
+		return false
+	case <-i.inv.quit:
+		return false
+	}
 }
 
 // Cancel unregisters the invoiceSubscription, freeing any previously allocated
 // resources.
 func (i *invoiceSubscription) Cancel() {
+	if !atomic.CompareAndSwapUint32(&i.cancelled, 0, 1) {
+		return
+	}
+
 	i.inv.clientMtx.Lock()
 	delete(i.inv.notificationClients, i.id)
 	i.inv.clientMtx.Unlock()
+
+	close(i.quit)
+	i.wg.Wait()
 }
 
 // SubscribeNotifications returns an invoiceSubscription which allows the
 // caller to receive async notifications when any invoices are settled or
-// added.
-func (i *invoiceRegistry) SubscribeNotifications() *invoiceSubscription {
+// added. If addIndex is non-zero, then all invoices with a greater add index
+// are first delivered over the NewInvoices channel. Similarly, if settleIndex
+// is non-zero, then all invoices with a greater settle index are first
+// delivered over the SettledInvoices channel.
+func (i *invoiceRegistry) SubscribeNotifications(addIndex,
+	settleIndex uint64) (*invoiceSubscription, error) {
+
 	client := &invoiceSubscription{
-		NewInvoices:     make(chan *channeldb.Invoice),
-		SettledInvoices: make(chan *channeldb.Invoice),
-		inv:             i,
+		NewInvoices:      make(chan *channeldb.Invoice),
+		AcceptedInvoices: make(chan *channeldb.Invoice),
+		SettledInvoices:  make(chan *channeldb.Invoice),
+		CanceledInvoices: make(chan *channeldb.Invoice),
+		addIndex:         addIndex,
+		settleIndex:      settleIndex,
+		ntfnSignal:       make(chan struct{}, 1),
+		inv:              i,
+		quit:             make(chan struct{}),
 	}
 
 	i.clientMtx.Lock()
@@ -216,5 +1127,39 @@ func (i *invoiceRegistry) SubscribeNotifications() *invoiceSubscription {
 	i.nextClientID++
 	i.clientMtx.Unlock()
 
-	return client
+	// Now that the client is registered, we'll fetch the backlog of
+	// invoices it has yet to be notified of. Any invoices added or settled
+	// in the meantime are queued, and skipped if they're also part of the
+	// backlog.
+	var addBacklog, settleBacklog []*channeldb.Invoice
+	if addIndex != 0 {
+		invoices, err := i.cdb.InvoicesAddedSince(addIndex)
+		if err != nil {
+			client.Cancel()
+			return nil, err
+		}
+		addBacklog = invoices
+
+		if len(addBacklog) != 0 {
+			client.addIndex = addBacklog[len(addBacklog)-1].AddIndex
+		}
+	}
+	if settleIndex != 0 {
+		invoices, err := i.cdb.InvoicesSettledSince(settleIndex)
+		if err != nil {
+			client.Cancel()
+			return nil, err
+		}
+		settleBacklog = invoices
+
+		if len(settleBacklog) != 0 {
+			last := settleBacklog[len(settleBacklog)-1]
+			client.settleIndex = last.SettleIndex
+		}
+	}
+
+	client.wg.Add(1)
+	go client.notificationDispatcher(addBacklog, settleBacklog)
+
+	return client, nil
 }
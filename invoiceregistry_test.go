@@ -0,0 +1,940 @@
+package main
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/fastsha256"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcutil"
+)
+
+// receiveResolutions waits for exactly num shard resolutions to be delivered
+// over the passed channel.
+func receiveResolutions(t *testing.T, resolutions chan *shardResolution,
+	num int) []*shardResolution {
+
+	var received []*shardResolution
+	for i := 0; i < num; i++ {
+		select {
+		case res := <-resolutions:
+			received = append(received, res)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("expected %v resolutions, only received %v",
+				num, len(received))
+		}
+	}
+
+	select {
+	case res := <-resolutions:
+		t.Fatalf("received unexpected resolution: %v", res)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	return received
+}
+
+func TestInvoiceRegistryPaymentShards(t *testing.T) {
+	registry := newInvoiceRegistry(nil, nil, time.Minute, 0, false)
+
+	preimage := chainhash.Hash{1, 2, 3}
+	rHash := chainhash.Hash(fastsha256.Sum256(preimage[:]))
+	registry.AddDebugInvoice(btcutil.Amount(1000), preimage)
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// Each shard is assigned a distinct HTLC index, allowing us to ensure
+	// that every resolution targets the HTLC of its shard.
+	shardAmts := make(map[uint32]btcutil.Amount)
+	newShard := func(amt btcutil.Amount) *paymentShard {
+		htlcIndex := uint32(len(shardAmts))
+		shardAmts[htlcIndex] = amt
+
+		return &paymentShard{
+			htlcIndex:   htlcIndex,
+			amt:         amt,
+			resolutions: resolutions,
+			quit:        quit,
+		}
+	}
+
+	// The first shard only carries a portion of the payment, so it should
+	// be held by the registry.
+	registry.AddPaymentShard(rHash, 1000, newShard(400))
+	receiveResolutions(t, resolutions, 0)
+
+	// A shard which disagrees on the total amount of the payment should be
+	// rejected immediately.
+	registry.AddPaymentShard(rHash, 900, newShard(600))
+	res := receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil || res[0].reason != lnwire.IncorrectValue {
+		t.Fatalf("expected shard to be cancelled with %v, instead "+
+			"have %v", lnwire.IncorrectValue, res[0])
+	}
+
+	// Once the remainder of the payment arrives, both shards should be
+	// settled with the invoice's preimage.
+	registry.AddPaymentShard(rHash, 1000, newShard(600))
+	res = receiveResolutions(t, resolutions, 2)
+
+	var settled btcutil.Amount
+	for _, r := range res {
+		if r.preimage == nil || *r.preimage != preimage {
+			t.Fatalf("shard wasn't settled with the invoice's " +
+				"preimage")
+		}
+		if r.rHash != rHash {
+			t.Fatalf("resolution has incorrect payment hash")
+		}
+		if shardAmts[r.htlcIndex] != r.amt {
+			t.Fatalf("resolution of %v targets htlc %v of %v",
+				r.amt, r.htlcIndex, shardAmts[r.htlcIndex])
+		}
+		settled += r.amt
+	}
+	if settled != 1000 {
+		t.Fatalf("expected %v to be settled, instead have %v", 1000,
+			settled)
+	}
+}
+
+// TestInvoiceRegistryPaymentShardsOverpaid tests that a multi-path payment
+// whose shards together overpay the invoice by more than we allow is
+// rejected, even though each shard claims an acceptable total amount.
+func TestInvoiceRegistryPaymentShardsOverpaid(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	_, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// Both shards claim the invoice's value as the payment's total
+	// amount, yet together deliver almost twice as much.
+	for i := uint32(0); i < 2; i++ {
+		registry.AddPaymentShard(rHash, 1000, &paymentShard{
+			htlcIndex:   i,
+			amt:         999,
+			resolutions: resolutions,
+			quit:        quit,
+		})
+	}
+
+	res := receiveResolutions(t, resolutions, 2)
+	for _, r := range res {
+		if r.preimage != nil || r.reason != lnwire.IncorrectValue {
+			t.Fatalf("expected shard to be cancelled with %v, "+
+				"instead have %v", lnwire.IncorrectValue, r)
+		}
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractOpen)
+}
+
+func TestInvoiceRegistryPaymentShardTimeout(t *testing.T) {
+	registry := newInvoiceRegistry(nil, nil, time.Millisecond*100, 0, false)
+
+	preimage := chainhash.Hash{4, 5, 6}
+	rHash := chainhash.Hash(fastsha256.Sum256(preimage[:]))
+	registry.AddDebugInvoice(btcutil.Amount(1000), preimage)
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// As the remainder of the payment never arrives, both held shards
+	// should be cancelled once the timeout elapses.
+	for _, amt := range []btcutil.Amount{300, 300} {
+		registry.AddPaymentShard(rHash, 1000, &paymentShard{
+			amt:         amt,
+			resolutions: resolutions,
+			quit:        quit,
+		})
+	}
+
+	res := receiveResolutions(t, resolutions, 2)
+	for _, r := range res {
+		if r.preimage != nil || r.reason != lnwire.MPPTimeout {
+			t.Fatalf("expected shard to be cancelled with %v, "+
+				"instead have %v", lnwire.MPPTimeout, r)
+		}
+	}
+
+	// A shard arriving after the set expired should start a new set
+	// rather than completing the expired one.
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         700,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	res = receiveResolutions(t, resolutions, 1)
+	if res[0].reason != lnwire.MPPTimeout {
+		t.Fatalf("expected shard to be cancelled with %v, instead "+
+			"have %v", lnwire.MPPTimeout, res[0])
+	}
+}
+
+// makeTestDB creates a channeldb instance within a temporary directory for
+// testing purposes, along with a callback which closes and removes it.
+func makeTestDB(t *testing.T) (*channeldb.DB, func()) {
+	tempDir, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		t.Fatalf("unable to open db: %v", err)
+	}
+
+	return cdb, func() {
+		cdb.Close()
+		os.RemoveAll(tempDir)
+	}
+}
+
+// addTestInvoice adds a new invoice with a random preimage, expiring at the
+// passed time, to the passed registry.
+func addTestInvoice(t *testing.T, registry *invoiceRegistry,
+	expiry time.Time) (*channeldb.Invoice, chainhash.Hash) {
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		ExpiryDate:   expiry,
+		Terms: channeldb.ContractTerm{
+			Value: btcutil.Amount(1000),
+		},
+	}
+	if _, err := rand.Read(invoice.Terms.PaymentPreimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	if err := registry.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	rHash := chainhash.Hash(fastsha256.Sum256(
+		invoice.Terms.PaymentPreimage[:],
+	))
+	return invoice, rHash
+}
+
+func TestInvoiceRegistryCancelInvoice(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	_, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// We'll hand the registry a shard which only carries a portion of the
+	// payment, so it's held while waiting for the remainder.
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         400,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	receiveResolutions(t, resolutions, 0)
+
+	// Canceling the invoice should cancel the held shard, and mark the
+	// invoice as canceled.
+	if err := registry.CancelInvoice(rHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	res := receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil || res[0].reason != lnwire.UnknownPaymentHash {
+		t.Fatalf("expected shard to be cancelled with %v, instead "+
+			"have %v", lnwire.UnknownPaymentHash, res[0])
+	}
+
+	invoice, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.State != channeldb.ContractCanceled {
+		t.Fatalf("expected invoice to be canceled, instead %v",
+			invoice.Terms.State)
+	}
+	if isInvoicePayable(invoice, time.Now()) {
+		t.Fatalf("canceled invoice shouldn't be payable")
+	}
+
+	// Any further payments to the invoice should be rejected once
+	// complete.
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	res = receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil {
+		t.Fatalf("payment to canceled invoice was settled")
+	}
+
+	// The invoice can't be canceled a second time, nor can debug
+	// invoices be canceled.
+	if err := registry.CancelInvoice(rHash); err != channeldb.ErrInvoiceAlreadyCanceled {
+		t.Fatalf("expected ErrInvoiceAlreadyCanceled, instead %v", err)
+	}
+	debugPreimage := chainhash.Hash{7, 8, 9}
+	registry.AddDebugInvoice(btcutil.Amount(1000), debugPreimage)
+	debugHash := chainhash.Hash(fastsha256.Sum256(debugPreimage[:]))
+	if err := registry.CancelInvoice(debugHash); err == nil {
+		t.Fatalf("debug invoice shouldn't be cancelable")
+	}
+}
+
+func TestInvoiceRegistryExpiry(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	registry.expiryInterval = time.Millisecond * 50
+
+	// We'll add an invoice which expires shortly, along with one that
+	// doesn't expire for a while.
+	expiringInvoice, expiringHash := addTestInvoice(
+		t, registry, time.Now().Add(time.Millisecond*200),
+	)
+	_, openHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	if !isInvoicePayable(expiringInvoice, time.Now()) {
+		t.Fatalf("invoice should be payable before its expiry")
+	}
+	if isInvoicePayable(expiringInvoice, time.Now().Add(time.Second)) {
+		t.Fatalf("invoice shouldn't be payable after its expiry")
+	}
+
+	if err := registry.Start(); err != nil {
+		t.Fatalf("unable to start registry: %v", err)
+	}
+	defer registry.Stop()
+
+	client, err := registry.SubscribeNotifications(2, 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer client.Cancel()
+
+	// The registry should mark the expiring invoice as expired shortly
+	// after its expiry passes, notifying the client over the canceled
+	// invoices channel.
+	select {
+	case invoice := <-client.CanceledInvoices:
+		rHash := chainhash.Hash(invoice.PaymentHash())
+		if rHash != expiringHash {
+			t.Fatalf("expected expiring invoice %v, got %v",
+				expiringHash, rHash)
+		}
+		if invoice.Terms.State != channeldb.ContractExpired {
+			t.Fatalf("expected expired invoice, instead %v",
+				invoice.Terms.State)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expired invoice not notified")
+	}
+
+	var state channeldb.ContractState
+	for i := 0; i < 100; i++ {
+		invoice, err := registry.LookupInvoice(expiringHash)
+		if err != nil {
+			t.Fatalf("unable to find invoice: %v", err)
+		}
+		state = invoice.Terms.State
+		if state == channeldb.ContractExpired {
+			break
+		}
+
+		time.Sleep(time.Millisecond * 50)
+	}
+	if state != channeldb.ContractExpired {
+		t.Fatalf("expected invoice to be expired, instead %v", state)
+	}
+
+	invoice, err := registry.LookupInvoice(openHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.State != channeldb.ContractOpen {
+		t.Fatalf("expected invoice to be open, instead %v",
+			invoice.Terms.State)
+	}
+}
+
+// TestInvoiceRegistrySettlePayment tests that the preimage of an invoice is
+// only released once the invoice has been marked as settled, and never for
+// an invoice which no longer accepts payment.
+func TestInvoiceRegistrySettlePayment(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	invoice, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	preimage, err := registry.SettlePayment(rHash, 1000)
+	if err != nil {
+		t.Fatalf("unable to settle payment: %v", err)
+	}
+	if preimage != invoice.Terms.PaymentPreimage {
+		t.Fatalf("settled with incorrect preimage")
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractSettled)
+
+	// As its preimage has been revealed, a settled invoice may be paid
+	// again, with the additional payment recorded.
+	if _, err := registry.SettlePayment(rHash, 1000); err != nil {
+		t.Fatalf("unable to settle payment: %v", err)
+	}
+	settled, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if settled.AmtPaid != 2000 {
+		t.Fatalf("expected %v paid, instead have %v", 2000,
+			settled.AmtPaid)
+	}
+
+	// The preimage of a canceled invoice, or one whose expiry has passed,
+	// should never be released.
+	_, canceledHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+	if err := registry.CancelInvoice(canceledHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	if _, err := registry.SettlePayment(canceledHash, 1000); err == nil {
+		t.Fatalf("canceled invoice was settled")
+	}
+	assertInvoiceState(t, registry, canceledHash, channeldb.ContractCanceled)
+
+	_, expiredHash := addTestInvoice(t, registry, time.Now().Add(-time.Second))
+	if _, err := registry.SettlePayment(expiredHash, 1000); err == nil {
+		t.Fatalf("expired invoice was settled")
+	}
+	assertInvoiceState(t, registry, expiredHash, channeldb.ContractOpen)
+}
+
+// addTestHoldInvoice adds a new hold invoice, for which the returned preimage
+// is withheld, to the passed registry.
+func addTestHoldInvoice(t *testing.T,
+	registry *invoiceRegistry) ([32]byte, chainhash.Hash) {
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	rHash := fastsha256.Sum256(preimage[:])
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		ExpiryDate:   time.Now().Add(time.Hour),
+		Terms: channeldb.ContractTerm{
+			Value:    btcutil.Amount(1000),
+			HoldHash: &rHash,
+		},
+	}
+	if err := registry.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	return preimage, chainhash.Hash(rHash)
+}
+
+// assertInvoiceState asserts that the invoice with the passed payment hash is
+// in the expected state.
+func assertInvoiceState(t *testing.T, registry *invoiceRegistry,
+	rHash chainhash.Hash, state channeldb.ContractState) {
+
+	invoice, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if invoice.Terms.State != state {
+		t.Fatalf("expected invoice to be %v, instead %v", state,
+			invoice.Terms.State)
+	}
+}
+
+func TestInvoiceRegistryHoldInvoice(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	preimage, rHash := addTestHoldInvoice(t, registry)
+
+	client, err := registry.SubscribeNotifications(0, 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer client.Cancel()
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// Once the payment to the hold invoice is complete, it should be held
+	// by the registry, with the invoice marked as accepted.
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	receiveResolutions(t, resolutions, 0)
+
+	select {
+	case invoice := <-client.AcceptedInvoices:
+		if invoice.PaymentHash() != rHash {
+			t.Fatalf("accepted notification for wrong invoice")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no accepted invoice notification received")
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractAccepted)
+
+	// An incorrect preimage shouldn't settle the invoice, while the
+	// correct one should settle the held payment.
+	if err := registry.SettleHoldInvoice([32]byte{1}); err == nil {
+		t.Fatalf("invoice settled with incorrect preimage")
+	}
+	if err := registry.SettleHoldInvoice(preimage); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	res := receiveResolutions(t, resolutions, 1)
+	if res[0].preimage == nil || *res[0].preimage != preimage {
+		t.Fatalf("held payment wasn't settled with the preimage")
+	}
+
+	select {
+	case invoice := <-client.SettledInvoices:
+		if invoice.Terms.PaymentPreimage != preimage {
+			t.Fatalf("settled invoice has incorrect preimage")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no settled invoice notification received")
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractSettled)
+
+	// A second hold invoice whose payment is accepted and then canceled
+	// should have its held payment cancelled.
+	_, rHash = addTestHoldInvoice(t, registry)
+	<-client.NewInvoices
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	receiveResolutions(t, resolutions, 0)
+	<-client.AcceptedInvoices
+
+	if err := registry.CancelInvoice(rHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	res = receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil || res[0].reason != lnwire.UnknownPaymentHash {
+		t.Fatalf("expected held payment to be cancelled with %v, "+
+			"instead have %v", lnwire.UnknownPaymentHash, res[0])
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractCanceled)
+}
+
+func TestInvoiceRegistryHoldInvoiceExpiry(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// We'll hold the payments of two hold invoices, one paid by an HTLC
+	// expiring at height 500, and another by an HTLC without an expiry.
+	_, expiringHash := addTestHoldInvoice(t, registry)
+	registry.AddPaymentShard(expiringHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+		expiry:      500,
+	})
+	_, heldHash := addTestHoldInvoice(t, registry)
+	registry.AddPaymentShard(heldHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	receiveResolutions(t, resolutions, 0)
+
+	// While the chain is further than holdExpiryDelta blocks from the
+	// HTLC's expiry, the payment should continue to be held.
+	registry.cancelExpiringHolds(500 - registry.holdExpiryDelta - 1)
+	receiveResolutions(t, resolutions, 0)
+	assertInvoiceState(t, registry, expiringHash, channeldb.ContractAccepted)
+
+	// Once within holdExpiryDelta blocks, the invoice should be canceled
+	// along with its held payment. The payment without an expiry should
+	// remain held.
+	registry.cancelExpiringHolds(500 - registry.holdExpiryDelta)
+	res := receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil || res[0].rHash != expiringHash {
+		t.Fatalf("expected expiring payment to be cancelled, "+
+			"instead have %v", res[0])
+	}
+	assertInvoiceState(t, registry, expiringHash, channeldb.ContractCanceled)
+	assertInvoiceState(t, registry, heldHash, channeldb.ContractAccepted)
+}
+
+// TestInvoiceRegistryHoldInvoiceRestart tests that a hold invoice whose held
+// HTLCs were lost across a restart can't be settled, and is instead canceled
+// once the registry starts.
+func TestInvoiceRegistryHoldInvoiceRestart(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	preimage, rHash := addTestHoldInvoice(t, registry)
+	registry.AddPaymentShard(rHash, 1000, &paymentShard{
+		amt:         1000,
+		resolutions: resolutions,
+		quit:        quit,
+	})
+	receiveResolutions(t, resolutions, 0)
+	assertInvoiceState(t, registry, rHash, channeldb.ContractAccepted)
+
+	// A new registry over the same database no longer holds the HTLCs of
+	// the invoice, so settling it should fail, leaving it accepted.
+	registry = newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	if err := registry.SettleHoldInvoice(preimage); err != errNoHeldHTLCs {
+		t.Fatalf("expected errNoHeldHTLCs, instead got %v", err)
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractAccepted)
+
+	// Once started, the registry should cancel the orphaned invoice.
+	if err := registry.Start(); err != nil {
+		t.Fatalf("unable to start registry: %v", err)
+	}
+	defer registry.Stop()
+
+	assertInvoiceState(t, registry, rHash, channeldb.ContractCanceled)
+}
+
+// receiveInvoice waits for an invoice to be delivered over the passed channel,
+// and asserts that it has the expected payment hash.
+func receiveInvoice(t *testing.T, invoices chan *channeldb.Invoice,
+	rHash chainhash.Hash) *channeldb.Invoice {
+
+	select {
+	case invoice := <-invoices:
+		if invoice.PaymentHash() != rHash {
+			t.Fatalf("expected invoice %x, instead received %x",
+				rHash[:], invoice.PaymentHash())
+		}
+		return invoice
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no invoice notification received")
+	}
+
+	return nil
+}
+
+func TestInvoiceRegistrySubscriptionReplay(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+
+	// We'll add three invoices, and settle the first and last of them,
+	// before any clients subscribe.
+	var hashes []chainhash.Hash
+	for i := 0; i < 3; i++ {
+		_, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+		hashes = append(hashes, rHash)
+	}
+	for _, rHash := range []chainhash.Hash{hashes[0], hashes[2]} {
+		if err := cdb.SettleInvoice(rHash, 1000, nil); err != nil {
+			t.Fatalf("unable to settle invoice: %v", err)
+		}
+	}
+
+	// A client subscribing from add index 1 and settle index 1 should
+	// first be sent the second and third invoices as added, followed by
+	// the third invoice as settled.
+	client, err := registry.SubscribeNotifications(1, 1)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer client.Cancel()
+
+	invoice := receiveInvoice(t, client.NewInvoices, hashes[1])
+	if invoice.AddIndex != 2 {
+		t.Fatalf("expected add index 2, instead %v", invoice.AddIndex)
+	}
+	receiveInvoice(t, client.NewInvoices, hashes[2])
+	invoice = receiveInvoice(t, client.SettledInvoices, hashes[2])
+	if invoice.SettleIndex != 2 {
+		t.Fatalf("expected settle index 2, instead %v",
+			invoice.SettleIndex)
+	}
+
+	// Once the backlog has been delivered, live notifications should
+	// follow.
+	_, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+	invoice = receiveInvoice(t, client.NewInvoices, rHash)
+	if invoice.AddIndex != 4 {
+		t.Fatalf("expected add index 4, instead %v", invoice.AddIndex)
+	}
+
+	if err := registry.SettleInvoice(hashes[1], 1000, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	invoice = receiveInvoice(t, client.SettledInvoices, hashes[1])
+	if invoice.SettleIndex != 3 {
+		t.Fatalf("expected settle index 3, instead %v",
+			invoice.SettleIndex)
+	}
+
+	// Notifying the client of the same settled invoice again shouldn't
+	// result in a duplicate notification.
+	registry.notifyInvoice(hashes[1])
+	select {
+	case invoice := <-client.SettledInvoices:
+		t.Fatalf("received duplicate invoice: %v", invoice)
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	// A client subscribing without indexes shouldn't be sent any backlog.
+	liveClient, err := registry.SubscribeNotifications(0, 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer liveClient.Cancel()
+
+	select {
+	case invoice := <-liveClient.NewInvoices:
+		t.Fatalf("received unexpected invoice: %v", invoice)
+	case invoice := <-liveClient.SettledInvoices:
+		t.Fatalf("received unexpected invoice: %v", invoice)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestInvoiceRegistryAcceptsAmount(t *testing.T) {
+	registry := newInvoiceRegistry(nil, nil, time.Minute, 0.5, false)
+
+	tests := []struct {
+		value    btcutil.Amount
+		amt      btcutil.Amount
+		accepted bool
+	}{
+		{value: 1000, amt: 999, accepted: false},
+		{value: 1000, amt: 1000, accepted: true},
+		{value: 1000, amt: 1500, accepted: true},
+		{value: 1000, amt: 1501, accepted: false},
+		{value: 0, amt: 0, accepted: false},
+		{value: 0, amt: 1, accepted: true},
+		{value: 0, amt: 1000000, accepted: true},
+	}
+	for i, test := range tests {
+		invoice := &channeldb.Invoice{
+			Terms: channeldb.ContractTerm{
+				Value: test.value,
+			},
+		}
+		accepted := registry.acceptsAmount(invoice, test.amt)
+		if accepted != test.accepted {
+			t.Fatalf("test #%v: expected payment of %v to invoice "+
+				"of %v to be accepted=%v, instead %v", i,
+				test.amt, test.value, test.accepted, accepted)
+		}
+	}
+}
+
+func TestInvoiceRegistryZeroValueInvoice(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+	}
+	if _, err := rand.Read(invoice.Terms.PaymentPreimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	if err := registry.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	rHash := chainhash.Hash(invoice.PaymentHash())
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// The payer chooses to pay 1500 satoshis over two shards, which
+	// should be settled once both have arrived.
+	for _, amt := range []btcutil.Amount{600, 900} {
+		registry.AddPaymentShard(rHash, 1500, &paymentShard{
+			amt:         amt,
+			resolutions: resolutions,
+			quit:        quit,
+		})
+	}
+	res := receiveResolutions(t, resolutions, 2)
+	for _, r := range res {
+		if r.preimage == nil {
+			t.Fatalf("shard wasn't settled")
+		}
+	}
+
+	// The invoice should record the amount actually paid, leaving its
+	// value untouched.
+	dbInvoice, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.State != channeldb.ContractSettled {
+		t.Fatalf("expected invoice to be settled, instead %v",
+			dbInvoice.Terms.State)
+	}
+	if dbInvoice.Terms.Value != 0 || dbInvoice.AmtPaid != 1500 {
+		t.Fatalf("expected value 0 and amount paid 1500, instead "+
+			"have value %v and amount paid %v",
+			dbInvoice.Terms.Value, dbInvoice.AmtPaid)
+	}
+}
+
+func TestInvoiceRegistryKeySend(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		t.Fatalf("unable to generate preimage: %v", err)
+	}
+	rHash := chainhash.Hash(fastsha256.Sum256(preimage[:]))
+	customRecords := map[uint64][]byte{
+		routing.KeySendRecordType:     preimage[:],
+		routing.CustomRecordTypeStart: []byte("custom"),
+	}
+
+	// Unless enabled, keysend payments should be rejected.
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	_, err := registry.AddKeySendInvoice(rHash, 1000, customRecords)
+	if err != errKeySendDisabled {
+		t.Fatalf("expected errKeySendDisabled, got %v", err)
+	}
+
+	// Once enabled, a keysend payment lacking a matching preimage should
+	// still be rejected.
+	registry = newInvoiceRegistry(cdb, nil, time.Minute, 0, true)
+	_, err = registry.AddKeySendInvoice(chainhash.Hash{1}, 1000,
+		customRecords)
+	if err != errInvalidKeySendPreimage {
+		t.Fatalf("expected errInvalidKeySendPreimage, got %v", err)
+	}
+
+	// A valid keysend payment should result in an invoice paying to its
+	// preimage, which carries the payment's custom records.
+	invoice, err := registry.AddKeySendInvoice(rHash, 1000, customRecords)
+	if err != nil {
+		t.Fatalf("unable to add keysend invoice: %v", err)
+	}
+	dbInvoice, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.PaymentPreimage != preimage ||
+		dbInvoice.Terms.Value != 1000 {
+
+		t.Fatalf("invalid keysend invoice: %v", spew.Sdump(dbInvoice))
+	}
+	if !reflect.DeepEqual(dbInvoice.CustomRecords, customRecords) {
+		t.Fatalf("custom records mismatch: expected %v, got %v",
+			customRecords, dbInvoice.CustomRecords)
+	}
+
+	// Another shard of the same payment should find the existing invoice.
+	invoice2, err := registry.AddKeySendInvoice(rHash, 1000, customRecords)
+	if err != nil {
+		t.Fatalf("unable to add keysend invoice: %v", err)
+	}
+	if invoice2.AddIndex != invoice.AddIndex {
+		t.Fatalf("expected existing invoice with add index %v, got %v",
+			invoice.AddIndex, invoice2.AddIndex)
+	}
+}
+
+func TestInvoiceRegistryCustomRecords(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	_, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	client, err := registry.SubscribeNotifications(0, 0)
+	if err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	defer client.Cancel()
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	// The payment is split over two shards, each carrying its own custom
+	// records, which should be merged once the payment settles.
+	shardRecords := []map[uint64][]byte{
+		{routing.CustomRecordTypeStart: []byte("order")},
+		{routing.CustomRecordTypeStart + 1: []byte("message")},
+	}
+	for _, records := range shardRecords {
+		registry.AddPaymentShard(rHash, 1000, &paymentShard{
+			amt:           500,
+			customRecords: records,
+			resolutions:   resolutions,
+			quit:          quit,
+		})
+	}
+	res := receiveResolutions(t, resolutions, 2)
+	for _, r := range res {
+		if r.preimage == nil {
+			t.Fatalf("shard wasn't settled")
+		}
+	}
+
+	expectedRecords := map[uint64][]byte{
+		routing.CustomRecordTypeStart:     []byte("order"),
+		routing.CustomRecordTypeStart + 1: []byte("message"),
+	}
+	invoice := receiveInvoice(t, client.SettledInvoices, rHash)
+	if !reflect.DeepEqual(invoice.CustomRecords, expectedRecords) {
+		t.Fatalf("custom records mismatch: expected %v, got %v",
+			expectedRecords, invoice.CustomRecords)
+	}
+
+	dbInvoice, err := registry.LookupInvoice(rHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if !reflect.DeepEqual(dbInvoice.CustomRecords, expectedRecords) {
+		t.Fatalf("custom records mismatch: expected %v, got %v",
+			expectedRecords, dbInvoice.CustomRecords)
+	}
+}
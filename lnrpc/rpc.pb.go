@@ -14,6 +14,11 @@ It has these top-level messages:
 	TransactionDetails
 	SendRequest
 	SendResponse
+	SendToRouteRequest
+	SendToRouteResponse
+	TrackPaymentRequest
+	HTLCAttempt
+	PaymentStatus
 	ChannelPoint
 	LightningAddress
 	SendManyRequest
@@ -32,8 +37,13 @@ It has these top-level messages:
 	Peer
 	ListPeersRequest
 	ListPeersResponse
+	ChannelLimits
+	PeerLimit
+	PeerLimitsRequest
+	PeerLimitsResponse
 	GetInfoRequest
 	GetInfoResponse
+	HTLCBatchStats
 	ConfirmationUpdate
 	ChannelOpenUpdate
 	ChannelCloseUpdate
@@ -51,6 +61,9 @@ It has these top-level messages:
 	RouteRequest
 	Hop
 	Route
+	QueryRouteResponse
+	BuildRouteRequest
+	BuildRouteResponse
 	NodeInfoRequest
 	NodeInfo
 	LightningNode
@@ -61,12 +74,22 @@ It has these top-level messages:
 	ChanInfoRequest
 	NetworkInfoRequest
 	NetworkInfo
+	GraphTopologySubscription
+	GraphTopologyUpdate
+	NodeUpdate
+	ChannelEdgeUpdate
+	ClosedChannelUpdate
 	SetAliasRequest
 	SetAliasResponse
 	Invoice
+	HopHint
+	RouteHint
 	AddInvoiceResponse
 	PaymentHash
 	ListInvoiceRequest
+	CancelInvoiceResponse
+	SettleInvoiceRequest
+	SettleInvoiceResponse
 	ListInvoiceResponse
 	InvoiceSubscription
 	Payment
@@ -78,6 +101,15 @@ It has these top-level messages:
 	DebugLevelResponse
 	PayReqString
 	PayReq
+	PairHistory
+	QueryMissionControlRequest
+	QueryMissionControlResponse
+	ResetMissionControlRequest
+	ResetMissionControlResponse
+	AutopilotStatusRequest
+	AutopilotStatusResponse
+	SetAutopilotRequest
+	SetAutopilotResponse
 */
 package lnrpc
 
@@ -126,6 +158,62 @@ func (x ChannelStatus) String() string {
 }
 func (ChannelStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+type HTLCAttempt_HTLCStatus int32
+
+const (
+	HTLCAttempt_IN_FLIGHT HTLCAttempt_HTLCStatus = 0
+	HTLCAttempt_SETTLED   HTLCAttempt_HTLCStatus = 1
+	HTLCAttempt_FAILED    HTLCAttempt_HTLCStatus = 2
+)
+
+var HTLCAttempt_HTLCStatus_name = map[int32]string{
+	0: "IN_FLIGHT",
+	1: "SETTLED",
+	2: "FAILED",
+}
+var HTLCAttempt_HTLCStatus_value = map[string]int32{
+	"IN_FLIGHT": 0,
+	"SETTLED":   1,
+	"FAILED":    2,
+}
+
+func (x HTLCAttempt_HTLCStatus) String() string {
+	return proto.EnumName(HTLCAttempt_HTLCStatus_name, int32(x))
+}
+func (HTLCAttempt_HTLCStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 0} }
+
+type PaymentStatus_PaymentState int32
+
+const (
+	PaymentStatus_UNKNOWN   PaymentStatus_PaymentState = 0
+	PaymentStatus_INITIATED PaymentStatus_PaymentState = 1
+	PaymentStatus_IN_FLIGHT PaymentStatus_PaymentState = 2
+	PaymentStatus_SUCCEEDED PaymentStatus_PaymentState = 3
+	PaymentStatus_FAILED    PaymentStatus_PaymentState = 4
+)
+
+var PaymentStatus_PaymentState_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "INITIATED",
+	2: "IN_FLIGHT",
+	3: "SUCCEEDED",
+	4: "FAILED",
+}
+var PaymentStatus_PaymentState_value = map[string]int32{
+	"UNKNOWN":   0,
+	"INITIATED": 1,
+	"IN_FLIGHT": 2,
+	"SUCCEEDED": 3,
+	"FAILED":    4,
+}
+
+func (x PaymentStatus_PaymentState) String() string {
+	return proto.EnumName(PaymentStatus_PaymentState_name, int32(x))
+}
+func (PaymentStatus_PaymentState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{9, 0}
+}
+
 type NewAddressRequest_AddressType int32
 
 const (
@@ -149,8 +237,38 @@ func (x NewAddressRequest_AddressType) String() string {
 	return proto.EnumName(NewAddressRequest_AddressType_name, int32(x))
 }
 func (NewAddressRequest_AddressType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{11, 0}
+	return fileDescriptor0, []int{16, 0}
+}
+
+type Invoice_InvoiceState int32
+
+const (
+	Invoice_OPEN     Invoice_InvoiceState = 0
+	Invoice_SETTLED  Invoice_InvoiceState = 1
+	Invoice_CANCELED Invoice_InvoiceState = 2
+	Invoice_EXPIRED  Invoice_InvoiceState = 3
+	Invoice_ACCEPTED Invoice_InvoiceState = 4
+)
+
+var Invoice_InvoiceState_name = map[int32]string{
+	0: "OPEN",
+	1: "SETTLED",
+	2: "CANCELED",
+	3: "EXPIRED",
+	4: "ACCEPTED",
+}
+var Invoice_InvoiceState_value = map[string]int32{
+	"OPEN":     0,
+	"SETTLED":  1,
+	"CANCELED": 2,
+	"EXPIRED":  3,
+	"ACCEPTED": 4,
+}
+
+func (x Invoice_InvoiceState) String() string {
+	return proto.EnumName(Invoice_InvoiceState_name, int32(x))
 }
+func (Invoice_InvoiceState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{72, 0} }
 
 type Transaction struct {
 	TxHash           string  `protobuf:"bytes,1,opt,name=tx_hash" json:"tx_hash,omitempty"`
@@ -241,12 +359,24 @@ func (m *TransactionDetails) GetTransactions() []*Transaction {
 }
 
 type SendRequest struct {
-	Dest              []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
-	DestString        string `protobuf:"bytes,2,opt,name=dest_string" json:"dest_string,omitempty"`
-	Amt               int64  `protobuf:"varint,3,opt,name=amt" json:"amt,omitempty"`
-	PaymentHash       []byte `protobuf:"bytes,4,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
-	PaymentHashString string `protobuf:"bytes,5,opt,name=payment_hash_string" json:"payment_hash_string,omitempty"`
-	PaymentRequest    string `protobuf:"bytes,6,opt,name=payment_request" json:"payment_request,omitempty"`
+	Dest              []byte            `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	DestString        string            `protobuf:"bytes,2,opt,name=dest_string" json:"dest_string,omitempty"`
+	Amt               int64             `protobuf:"varint,3,opt,name=amt" json:"amt,omitempty"`
+	PaymentHash       []byte            `protobuf:"bytes,4,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	PaymentHashString string            `protobuf:"bytes,5,opt,name=payment_hash_string" json:"payment_hash_string,omitempty"`
+	PaymentRequest    string            `protobuf:"bytes,6,opt,name=payment_request" json:"payment_request,omitempty"`
+	TimeoutSeconds    int32             `protobuf:"varint,7,opt,name=timeout_seconds" json:"timeout_seconds,omitempty"`
+	FeeLimit          int64             `protobuf:"varint,8,opt,name=fee_limit" json:"fee_limit,omitempty"`
+	CltvLimit         uint32            `protobuf:"varint,9,opt,name=cltv_limit" json:"cltv_limit,omitempty"`
+	OutgoingChanId    uint64            `protobuf:"varint,10,opt,name=outgoing_chan_id" json:"outgoing_chan_id,omitempty"`
+	LastHopPubkey     []byte            `protobuf:"bytes,11,opt,name=last_hop_pubkey,proto3" json:"last_hop_pubkey,omitempty"`
+	IgnoredNodes      [][]byte          `protobuf:"bytes,12,rep,name=ignored_nodes,proto3" json:"ignored_nodes,omitempty"`
+	IgnoredEdges      []uint64          `protobuf:"varint,13,rep,packed,name=ignored_edges" json:"ignored_edges,omitempty"`
+	RouteHints        []*RouteHint      `protobuf:"bytes,14,rep,name=route_hints" json:"route_hints,omitempty"`
+	MaxShards         uint32            `protobuf:"varint,15,opt,name=max_shards" json:"max_shards,omitempty"`
+	Keysend           bool              `protobuf:"varint,16,opt,name=keysend" json:"keysend,omitempty"`
+	DestCustomRecords map[uint64][]byte `protobuf:"bytes,17,rep,name=dest_custom_records" json:"dest_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FinalCltvDelta    uint32            `protobuf:"varint,18,opt,name=final_cltv_delta" json:"final_cltv_delta,omitempty"`
 }
 
 func (m *SendRequest) Reset()                    { *m = SendRequest{} }
@@ -296,8 +426,94 @@ func (m *SendRequest) GetPaymentRequest() string {
 	return ""
 }
 
+func (m *SendRequest) GetTimeoutSeconds() int32 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
+func (m *SendRequest) GetFeeLimit() int64 {
+	if m != nil {
+		return m.FeeLimit
+	}
+	return 0
+}
+
+func (m *SendRequest) GetCltvLimit() uint32 {
+	if m != nil {
+		return m.CltvLimit
+	}
+	return 0
+}
+
+func (m *SendRequest) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *SendRequest) GetLastHopPubkey() []byte {
+	if m != nil {
+		return m.LastHopPubkey
+	}
+	return nil
+}
+
+func (m *SendRequest) GetIgnoredNodes() [][]byte {
+	if m != nil {
+		return m.IgnoredNodes
+	}
+	return nil
+}
+
+func (m *SendRequest) GetIgnoredEdges() []uint64 {
+	if m != nil {
+		return m.IgnoredEdges
+	}
+	return nil
+}
+
+func (m *SendRequest) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
+
+func (m *SendRequest) GetMaxShards() uint32 {
+	if m != nil {
+		return m.MaxShards
+	}
+	return 0
+}
+
+func (m *SendRequest) GetKeysend() bool {
+	if m != nil {
+		return m.Keysend
+	}
+	return false
+}
+
+func (m *SendRequest) GetDestCustomRecords() map[uint64][]byte {
+	if m != nil {
+		return m.DestCustomRecords
+	}
+	return nil
+}
+
+func (m *SendRequest) GetFinalCltvDelta() uint32 {
+	if m != nil {
+		return m.FinalCltvDelta
+	}
+	return 0
+}
+
 type SendResponse struct {
-	PaymentRoute *Route `protobuf:"bytes,1,opt,name=payment_route" json:"payment_route,omitempty"`
+	PaymentRoute  *Route   `protobuf:"bytes,1,opt,name=payment_route" json:"payment_route,omitempty"`
+	PaymentRoutes []*Route `protobuf:"bytes,2,rep,name=payment_routes" json:"payment_routes,omitempty"`
+	PaymentHash   []byte   `protobuf:"bytes,3,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
 }
 
 func (m *SendResponse) Reset()                    { *m = SendResponse{} }
@@ -312,6 +528,244 @@ func (m *SendResponse) GetPaymentRoute() *Route {
 	return nil
 }
 
+func (m *SendResponse) GetPaymentRoutes() []*Route {
+	if m != nil {
+		return m.PaymentRoutes
+	}
+	return nil
+}
+
+func (m *SendResponse) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+type SendToRouteRequest struct {
+	PaymentHash       []byte            `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	PaymentHashString string            `protobuf:"bytes,2,opt,name=payment_hash_string" json:"payment_hash_string,omitempty"`
+	Route             *Route            `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+	DestCustomRecords map[uint64][]byte `protobuf:"bytes,4,rep,name=dest_custom_records" json:"dest_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *SendToRouteRequest) Reset()                    { *m = SendToRouteRequest{} }
+func (m *SendToRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendToRouteRequest) ProtoMessage()               {}
+func (*SendToRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *SendToRouteRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *SendToRouteRequest) GetPaymentHashString() string {
+	if m != nil {
+		return m.PaymentHashString
+	}
+	return ""
+}
+
+func (m *SendToRouteRequest) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func (m *SendToRouteRequest) GetDestCustomRecords() map[uint64][]byte {
+	if m != nil {
+		return m.DestCustomRecords
+	}
+	return nil
+}
+
+type SendToRouteResponse struct {
+	PaymentPreimage []byte `protobuf:"bytes,1,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
+	PaymentError    string `protobuf:"bytes,2,opt,name=payment_error" json:"payment_error,omitempty"`
+	FailureCode     uint32 `protobuf:"varint,3,opt,name=failure_code" json:"failure_code,omitempty"`
+}
+
+func (m *SendToRouteResponse) Reset()                    { *m = SendToRouteResponse{} }
+func (m *SendToRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendToRouteResponse) ProtoMessage()               {}
+func (*SendToRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *SendToRouteResponse) GetPaymentPreimage() []byte {
+	if m != nil {
+		return m.PaymentPreimage
+	}
+	return nil
+}
+
+func (m *SendToRouteResponse) GetPaymentError() string {
+	if m != nil {
+		return m.PaymentError
+	}
+	return ""
+}
+
+func (m *SendToRouteResponse) GetFailureCode() uint32 {
+	if m != nil {
+		return m.FailureCode
+	}
+	return 0
+}
+
+type TrackPaymentRequest struct {
+	PaymentHash       []byte `protobuf:"bytes,1,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	PaymentHashString string `protobuf:"bytes,2,opt,name=payment_hash_string" json:"payment_hash_string,omitempty"`
+}
+
+func (m *TrackPaymentRequest) Reset()                    { *m = TrackPaymentRequest{} }
+func (m *TrackPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*TrackPaymentRequest) ProtoMessage()               {}
+func (*TrackPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *TrackPaymentRequest) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *TrackPaymentRequest) GetPaymentHashString() string {
+	if m != nil {
+		return m.PaymentHashString
+	}
+	return ""
+}
+
+type HTLCAttempt struct {
+	AttemptId   uint64                 `protobuf:"varint,1,opt,name=attempt_id" json:"attempt_id,omitempty"`
+	Status      HTLCAttempt_HTLCStatus `protobuf:"varint,2,opt,name=status,enum=lnrpc.HTLCAttempt_HTLCStatus" json:"status,omitempty"`
+	Route       *Route                 `protobuf:"bytes,3,opt,name=route" json:"route,omitempty"`
+	AttemptTime int64                  `protobuf:"varint,4,opt,name=attempt_time" json:"attempt_time,omitempty"`
+	ResolveTime int64                  `protobuf:"varint,5,opt,name=resolve_time" json:"resolve_time,omitempty"`
+	Failure     string                 `protobuf:"bytes,6,opt,name=failure" json:"failure,omitempty"`
+}
+
+func (m *HTLCAttempt) Reset()                    { *m = HTLCAttempt{} }
+func (m *HTLCAttempt) String() string            { return proto.CompactTextString(m) }
+func (*HTLCAttempt) ProtoMessage()               {}
+func (*HTLCAttempt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *HTLCAttempt) GetAttemptId() uint64 {
+	if m != nil {
+		return m.AttemptId
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetStatus() HTLCAttempt_HTLCStatus {
+	if m != nil {
+		return m.Status
+	}
+	return HTLCAttempt_IN_FLIGHT
+}
+
+func (m *HTLCAttempt) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
+func (m *HTLCAttempt) GetAttemptTime() int64 {
+	if m != nil {
+		return m.AttemptTime
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetResolveTime() int64 {
+	if m != nil {
+		return m.ResolveTime
+	}
+	return 0
+}
+
+func (m *HTLCAttempt) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
+type PaymentStatus struct {
+	State          PaymentStatus_PaymentState `protobuf:"varint,1,opt,name=state,enum=lnrpc.PaymentStatus_PaymentState" json:"state,omitempty"`
+	PaymentHash    []byte                     `protobuf:"bytes,2,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	Value          int64                      `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+	CreationDate   int64                      `protobuf:"varint,4,opt,name=creation_date" json:"creation_date,omitempty"`
+	PaymentRequest string                     `protobuf:"bytes,5,opt,name=payment_request" json:"payment_request,omitempty"`
+	Preimage       []byte                     `protobuf:"bytes,6,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	FailureReason  string                     `protobuf:"bytes,7,opt,name=failure_reason" json:"failure_reason,omitempty"`
+	Htlcs          []*HTLCAttempt             `protobuf:"bytes,8,rep,name=htlcs" json:"htlcs,omitempty"`
+}
+
+func (m *PaymentStatus) Reset()                    { *m = PaymentStatus{} }
+func (m *PaymentStatus) String() string            { return proto.CompactTextString(m) }
+func (*PaymentStatus) ProtoMessage()               {}
+func (*PaymentStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *PaymentStatus) GetState() PaymentStatus_PaymentState {
+	if m != nil {
+		return m.State
+	}
+	return PaymentStatus_UNKNOWN
+}
+
+func (m *PaymentStatus) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *PaymentStatus) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *PaymentStatus) GetCreationDate() int64 {
+	if m != nil {
+		return m.CreationDate
+	}
+	return 0
+}
+
+func (m *PaymentStatus) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *PaymentStatus) GetPreimage() []byte {
+	if m != nil {
+		return m.Preimage
+	}
+	return nil
+}
+
+func (m *PaymentStatus) GetFailureReason() string {
+	if m != nil {
+		return m.FailureReason
+	}
+	return ""
+}
+
+func (m *PaymentStatus) GetHtlcs() []*HTLCAttempt {
+	if m != nil {
+		return m.Htlcs
+	}
+	return nil
+}
+
 type ChannelPoint struct {
 	FundingTxid    []byte `protobuf:"bytes,1,opt,name=funding_txid,proto3" json:"funding_txid,omitempty"`
 	FundingTxidStr string `protobuf:"bytes,2,opt,name=funding_txid_str" json:"funding_txid_str,omitempty"`
@@ -321,7 +775,7 @@ type ChannelPoint struct {
 func (m *ChannelPoint) Reset()                    { *m = ChannelPoint{} }
 func (m *ChannelPoint) String() string            { return proto.CompactTextString(m) }
 func (*ChannelPoint) ProtoMessage()               {}
-func (*ChannelPoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*ChannelPoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *ChannelPoint) GetFundingTxid() []byte {
 	if m != nil {
@@ -352,7 +806,7 @@ type LightningAddress struct {
 func (m *LightningAddress) Reset()                    { *m = LightningAddress{} }
 func (m *LightningAddress) String() string            { return proto.CompactTextString(m) }
 func (*LightningAddress) ProtoMessage()               {}
-func (*LightningAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*LightningAddress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *LightningAddress) GetPubkey() string {
 	if m != nil {
@@ -375,7 +829,7 @@ type SendManyRequest struct {
 func (m *SendManyRequest) Reset()                    { *m = SendManyRequest{} }
 func (m *SendManyRequest) String() string            { return proto.CompactTextString(m) }
 func (*SendManyRequest) ProtoMessage()               {}
-func (*SendManyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*SendManyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *SendManyRequest) GetAddrToAmount() map[string]int64 {
 	if m != nil {
@@ -391,7 +845,7 @@ type SendManyResponse struct {
 func (m *SendManyResponse) Reset()                    { *m = SendManyResponse{} }
 func (m *SendManyResponse) String() string            { return proto.CompactTextString(m) }
 func (*SendManyResponse) ProtoMessage()               {}
-func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *SendManyResponse) GetTxid() string {
 	if m != nil {
@@ -408,7 +862,7 @@ type SendCoinsRequest struct {
 func (m *SendCoinsRequest) Reset()                    { *m = SendCoinsRequest{} }
 func (m *SendCoinsRequest) String() string            { return proto.CompactTextString(m) }
 func (*SendCoinsRequest) ProtoMessage()               {}
-func (*SendCoinsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*SendCoinsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *SendCoinsRequest) GetAddr() string {
 	if m != nil {
@@ -431,7 +885,7 @@ type SendCoinsResponse struct {
 func (m *SendCoinsResponse) Reset()                    { *m = SendCoinsResponse{} }
 func (m *SendCoinsResponse) String() string            { return proto.CompactTextString(m) }
 func (*SendCoinsResponse) ProtoMessage()               {}
-func (*SendCoinsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*SendCoinsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *SendCoinsResponse) GetTxid() string {
 	if m != nil {
@@ -447,7 +901,7 @@ type NewAddressRequest struct {
 func (m *NewAddressRequest) Reset()                    { *m = NewAddressRequest{} }
 func (m *NewAddressRequest) String() string            { return proto.CompactTextString(m) }
 func (*NewAddressRequest) ProtoMessage()               {}
-func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *NewAddressRequest) GetType() NewAddressRequest_AddressType {
 	if m != nil {
@@ -462,7 +916,7 @@ type NewWitnessAddressRequest struct {
 func (m *NewWitnessAddressRequest) Reset()                    { *m = NewWitnessAddressRequest{} }
 func (m *NewWitnessAddressRequest) String() string            { return proto.CompactTextString(m) }
 func (*NewWitnessAddressRequest) ProtoMessage()               {}
-func (*NewWitnessAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*NewWitnessAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 type NewAddressResponse struct {
 	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
@@ -471,7 +925,7 @@ type NewAddressResponse struct {
 func (m *NewAddressResponse) Reset()                    { *m = NewAddressResponse{} }
 func (m *NewAddressResponse) String() string            { return proto.CompactTextString(m) }
 func (*NewAddressResponse) ProtoMessage()               {}
-func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *NewAddressResponse) GetAddress() string {
 	if m != nil {
@@ -488,7 +942,7 @@ type ConnectPeerRequest struct {
 func (m *ConnectPeerRequest) Reset()                    { *m = ConnectPeerRequest{} }
 func (m *ConnectPeerRequest) String() string            { return proto.CompactTextString(m) }
 func (*ConnectPeerRequest) ProtoMessage()               {}
-func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *ConnectPeerRequest) GetAddr() *LightningAddress {
 	if m != nil {
@@ -511,7 +965,7 @@ type ConnectPeerResponse struct {
 func (m *ConnectPeerResponse) Reset()                    { *m = ConnectPeerResponse{} }
 func (m *ConnectPeerResponse) String() string            { return proto.CompactTextString(m) }
 func (*ConnectPeerResponse) ProtoMessage()               {}
-func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *ConnectPeerResponse) GetPeerId() int32 {
 	if m != nil {
@@ -531,7 +985,7 @@ type HTLC struct {
 func (m *HTLC) Reset()                    { *m = HTLC{} }
 func (m *HTLC) String() string            { return proto.CompactTextString(m) }
 func (*HTLC) ProtoMessage()               {}
-func (*HTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*HTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *HTLC) GetIncoming() bool {
 	if m != nil {
@@ -585,7 +1039,7 @@ type ActiveChannel struct {
 func (m *ActiveChannel) Reset()                    { *m = ActiveChannel{} }
 func (m *ActiveChannel) String() string            { return proto.CompactTextString(m) }
 func (*ActiveChannel) ProtoMessage()               {}
-func (*ActiveChannel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*ActiveChannel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *ActiveChannel) GetRemotePubkey() string {
 	if m != nil {
@@ -670,7 +1124,7 @@ type ListChannelsRequest struct {
 func (m *ListChannelsRequest) Reset()                    { *m = ListChannelsRequest{} }
 func (m *ListChannelsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListChannelsRequest) ProtoMessage()               {}
-func (*ListChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*ListChannelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 type ListChannelsResponse struct {
 	Channels []*ActiveChannel `protobuf:"bytes,11,rep,name=channels" json:"channels,omitempty"`
@@ -679,7 +1133,7 @@ type ListChannelsResponse struct {
 func (m *ListChannelsResponse) Reset()                    { *m = ListChannelsResponse{} }
 func (m *ListChannelsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListChannelsResponse) ProtoMessage()               {}
-func (*ListChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*ListChannelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *ListChannelsResponse) GetChannels() []*ActiveChannel {
 	if m != nil {
@@ -703,7 +1157,7 @@ type Peer struct {
 func (m *Peer) Reset()                    { *m = Peer{} }
 func (m *Peer) String() string            { return proto.CompactTextString(m) }
 func (*Peer) ProtoMessage()               {}
-func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *Peer) GetPubKey() string {
 	if m != nil {
@@ -774,7 +1228,7 @@ type ListPeersRequest struct {
 func (m *ListPeersRequest) Reset()                    { *m = ListPeersRequest{} }
 func (m *ListPeersRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPeersRequest) ProtoMessage()               {}
-func (*ListPeersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*ListPeersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 type ListPeersResponse struct {
 	Peers []*Peer `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
@@ -783,7 +1237,7 @@ type ListPeersResponse struct {
 func (m *ListPeersResponse) Reset()                    { *m = ListPeersResponse{} }
 func (m *ListPeersResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPeersResponse) ProtoMessage()               {}
-func (*ListPeersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*ListPeersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func (m *ListPeersResponse) GetPeers() []*Peer {
 	if m != nil {
@@ -792,48 +1246,233 @@ func (m *ListPeersResponse) GetPeers() []*Peer {
 	return nil
 }
 
-type GetInfoRequest struct {
-}
-
-func (m *GetInfoRequest) Reset()                    { *m = GetInfoRequest{} }
-func (m *GetInfoRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetInfoRequest) ProtoMessage()               {}
-func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
-
-type GetInfoResponse struct {
-	IdentityPubkey     string `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
-	Alias              string `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
-	NumPendingChannels uint32 `protobuf:"varint,3,opt,name=num_pending_channels" json:"num_pending_channels,omitempty"`
-	NumActiveChannels  uint32 `protobuf:"varint,4,opt,name=num_active_channels" json:"num_active_channels,omitempty"`
-	NumPeers           uint32 `protobuf:"varint,5,opt,name=num_peers" json:"num_peers,omitempty"`
-	BlockHeight        uint32 `protobuf:"varint,6,opt,name=block_height" json:"block_height,omitempty"`
-	BlockHash          string `protobuf:"bytes,8,opt,name=block_hash" json:"block_hash,omitempty"`
-	SyncedToChain      bool   `protobuf:"varint,9,opt,name=synced_to_chain" json:"synced_to_chain,omitempty"`
-	Testnet            bool   `protobuf:"varint,10,opt,name=testnet" json:"testnet,omitempty"`
+type ChannelLimits struct {
+	ChannelPoint    string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	AvailableTokens uint32 `protobuf:"varint,2,opt,name=available_tokens" json:"available_tokens,omitempty"`
+	PendingForwards uint32 `protobuf:"varint,3,opt,name=pending_forwards" json:"pending_forwards,omitempty"`
 }
 
-func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
-func (m *GetInfoResponse) String() string            { return proto.CompactTextString(m) }
-func (*GetInfoResponse) ProtoMessage()               {}
-func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (m *ChannelLimits) Reset()                    { *m = ChannelLimits{} }
+func (m *ChannelLimits) String() string            { return proto.CompactTextString(m) }
+func (*ChannelLimits) ProtoMessage()               {}
+func (*ChannelLimits) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
-func (m *GetInfoResponse) GetIdentityPubkey() string {
+func (m *ChannelLimits) GetChannelPoint() string {
 	if m != nil {
-		return m.IdentityPubkey
+		return m.ChannelPoint
 	}
 	return ""
 }
 
-func (m *GetInfoResponse) GetAlias() string {
+func (m *ChannelLimits) GetAvailableTokens() uint32 {
 	if m != nil {
-		return m.Alias
+		return m.AvailableTokens
 	}
-	return ""
+	return 0
 }
 
-func (m *GetInfoResponse) GetNumPendingChannels() uint32 {
+func (m *ChannelLimits) GetPendingForwards() uint32 {
 	if m != nil {
-		return m.NumPendingChannels
+		return m.PendingForwards
+	}
+	return 0
+}
+
+type PeerLimit struct {
+	PubKey          string           `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	AvailableTokens uint32           `protobuf:"varint,2,opt,name=available_tokens" json:"available_tokens,omitempty"`
+	ReputationScore float64          `protobuf:"fixed64,3,opt,name=reputation_score" json:"reputation_score,omitempty"`
+	Restricted      bool             `protobuf:"varint,4,opt,name=restricted" json:"restricted,omitempty"`
+	NumRejected     uint64           `protobuf:"varint,5,opt,name=num_rejected" json:"num_rejected,omitempty"`
+	Channels        []*ChannelLimits `protobuf:"bytes,6,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *PeerLimit) Reset()                    { *m = PeerLimit{} }
+func (m *PeerLimit) String() string            { return proto.CompactTextString(m) }
+func (*PeerLimit) ProtoMessage()               {}
+func (*PeerLimit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *PeerLimit) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *PeerLimit) GetAvailableTokens() uint32 {
+	if m != nil {
+		return m.AvailableTokens
+	}
+	return 0
+}
+
+func (m *PeerLimit) GetReputationScore() float64 {
+	if m != nil {
+		return m.ReputationScore
+	}
+	return 0
+}
+
+func (m *PeerLimit) GetRestricted() bool {
+	if m != nil {
+		return m.Restricted
+	}
+	return false
+}
+
+func (m *PeerLimit) GetNumRejected() uint64 {
+	if m != nil {
+		return m.NumRejected
+	}
+	return 0
+}
+
+func (m *PeerLimit) GetChannels() []*ChannelLimits {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type PeerLimitsRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+}
+
+func (m *PeerLimitsRequest) Reset()                    { *m = PeerLimitsRequest{} }
+func (m *PeerLimitsRequest) String() string            { return proto.CompactTextString(m) }
+func (*PeerLimitsRequest) ProtoMessage()               {}
+func (*PeerLimitsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *PeerLimitsRequest) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+type PeerLimitsResponse struct {
+	PeerRate            float64      `protobuf:"fixed64,1,opt,name=peer_rate" json:"peer_rate,omitempty"`
+	PeerBurst           uint32       `protobuf:"varint,2,opt,name=peer_burst" json:"peer_burst,omitempty"`
+	ChanRate            float64      `protobuf:"fixed64,3,opt,name=chan_rate" json:"chan_rate,omitempty"`
+	ChanBurst           uint32       `protobuf:"varint,4,opt,name=chan_burst" json:"chan_burst,omitempty"`
+	MaxPendingForwards  uint32       `protobuf:"varint,5,opt,name=max_pending_forwards" json:"max_pending_forwards,omitempty"`
+	ReputationEnabled   bool         `protobuf:"varint,6,opt,name=reputation_enabled" json:"reputation_enabled,omitempty"`
+	HoldThresholdSecs   int64        `protobuf:"varint,7,opt,name=hold_threshold_secs" json:"hold_threshold_secs,omitempty"`
+	ReputationThreshold float64      `protobuf:"fixed64,8,opt,name=reputation_threshold" json:"reputation_threshold,omitempty"`
+	Peers               []*PeerLimit `protobuf:"bytes,9,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *PeerLimitsResponse) Reset()                    { *m = PeerLimitsResponse{} }
+func (m *PeerLimitsResponse) String() string            { return proto.CompactTextString(m) }
+func (*PeerLimitsResponse) ProtoMessage()               {}
+func (*PeerLimitsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *PeerLimitsResponse) GetPeerRate() float64 {
+	if m != nil {
+		return m.PeerRate
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetPeerBurst() uint32 {
+	if m != nil {
+		return m.PeerBurst
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetChanRate() float64 {
+	if m != nil {
+		return m.ChanRate
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetChanBurst() uint32 {
+	if m != nil {
+		return m.ChanBurst
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetMaxPendingForwards() uint32 {
+	if m != nil {
+		return m.MaxPendingForwards
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetReputationEnabled() bool {
+	if m != nil {
+		return m.ReputationEnabled
+	}
+	return false
+}
+
+func (m *PeerLimitsResponse) GetHoldThresholdSecs() int64 {
+	if m != nil {
+		return m.HoldThresholdSecs
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetReputationThreshold() float64 {
+	if m != nil {
+		return m.ReputationThreshold
+	}
+	return 0
+}
+
+func (m *PeerLimitsResponse) GetPeers() []*PeerLimit {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type GetInfoRequest struct {
+}
+
+func (m *GetInfoRequest) Reset()                    { *m = GetInfoRequest{} }
+func (m *GetInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetInfoRequest) ProtoMessage()               {}
+func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+type GetInfoResponse struct {
+	IdentityPubkey     string          `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
+	Alias              string          `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
+	NumPendingChannels uint32          `protobuf:"varint,3,opt,name=num_pending_channels" json:"num_pending_channels,omitempty"`
+	NumActiveChannels  uint32          `protobuf:"varint,4,opt,name=num_active_channels" json:"num_active_channels,omitempty"`
+	NumPeers           uint32          `protobuf:"varint,5,opt,name=num_peers" json:"num_peers,omitempty"`
+	BlockHeight        uint32          `protobuf:"varint,6,opt,name=block_height" json:"block_height,omitempty"`
+	BlockHash          string          `protobuf:"bytes,8,opt,name=block_hash" json:"block_hash,omitempty"`
+	SyncedToChain      bool            `protobuf:"varint,9,opt,name=synced_to_chain" json:"synced_to_chain,omitempty"`
+	Testnet            bool            `protobuf:"varint,10,opt,name=testnet" json:"testnet,omitempty"`
+	HtlcBatchStats     *HTLCBatchStats `protobuf:"bytes,11,opt,name=htlc_batch_stats" json:"htlc_batch_stats,omitempty"`
+}
+
+func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
+func (m *GetInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetInfoResponse) ProtoMessage()               {}
+func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *GetInfoResponse) GetIdentityPubkey() string {
+	if m != nil {
+		return m.IdentityPubkey
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *GetInfoResponse) GetNumPendingChannels() uint32 {
+	if m != nil {
+		return m.NumPendingChannels
 	}
 	return 0
 }
@@ -880,6 +1519,61 @@ func (m *GetInfoResponse) GetTestnet() bool {
 	return false
 }
 
+func (m *GetInfoResponse) GetHtlcBatchStats() *HTLCBatchStats {
+	if m != nil {
+		return m.HtlcBatchStats
+	}
+	return nil
+}
+
+type HTLCBatchStats struct {
+	NumBatches   uint64   `protobuf:"varint,1,opt,name=num_batches" json:"num_batches,omitempty"`
+	NumUpdates   uint64   `protobuf:"varint,2,opt,name=num_updates" json:"num_updates,omitempty"`
+	AvgBatchSize float64  `protobuf:"fixed64,3,opt,name=avg_batch_size" json:"avg_batch_size,omitempty"`
+	MaxBatchSize uint32   `protobuf:"varint,4,opt,name=max_batch_size" json:"max_batch_size,omitempty"`
+	Histogram    []uint64 `protobuf:"varint,5,rep,packed,name=histogram" json:"histogram,omitempty"`
+}
+
+func (m *HTLCBatchStats) Reset()                    { *m = HTLCBatchStats{} }
+func (m *HTLCBatchStats) String() string            { return proto.CompactTextString(m) }
+func (*HTLCBatchStats) ProtoMessage()               {}
+func (*HTLCBatchStats) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *HTLCBatchStats) GetNumBatches() uint64 {
+	if m != nil {
+		return m.NumBatches
+	}
+	return 0
+}
+
+func (m *HTLCBatchStats) GetNumUpdates() uint64 {
+	if m != nil {
+		return m.NumUpdates
+	}
+	return 0
+}
+
+func (m *HTLCBatchStats) GetAvgBatchSize() float64 {
+	if m != nil {
+		return m.AvgBatchSize
+	}
+	return 0
+}
+
+func (m *HTLCBatchStats) GetMaxBatchSize() uint32 {
+	if m != nil {
+		return m.MaxBatchSize
+	}
+	return 0
+}
+
+func (m *HTLCBatchStats) GetHistogram() []uint64 {
+	if m != nil {
+		return m.Histogram
+	}
+	return nil
+}
+
 type ConfirmationUpdate struct {
 	BlockSha     []byte `protobuf:"bytes,1,opt,name=block_sha,proto3" json:"block_sha,omitempty"`
 	BlockHeight  int32  `protobuf:"varint,2,opt,name=block_height" json:"block_height,omitempty"`
@@ -889,7 +1583,7 @@ type ConfirmationUpdate struct {
 func (m *ConfirmationUpdate) Reset()                    { *m = ConfirmationUpdate{} }
 func (m *ConfirmationUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ConfirmationUpdate) ProtoMessage()               {}
-func (*ConfirmationUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*ConfirmationUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
 
 func (m *ConfirmationUpdate) GetBlockSha() []byte {
 	if m != nil {
@@ -919,7 +1613,7 @@ type ChannelOpenUpdate struct {
 func (m *ChannelOpenUpdate) Reset()                    { *m = ChannelOpenUpdate{} }
 func (m *ChannelOpenUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelOpenUpdate) ProtoMessage()               {}
-func (*ChannelOpenUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (*ChannelOpenUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
 func (m *ChannelOpenUpdate) GetChannelPoint() *ChannelPoint {
 	if m != nil {
@@ -936,7 +1630,7 @@ type ChannelCloseUpdate struct {
 func (m *ChannelCloseUpdate) Reset()                    { *m = ChannelCloseUpdate{} }
 func (m *ChannelCloseUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelCloseUpdate) ProtoMessage()               {}
-func (*ChannelCloseUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*ChannelCloseUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
 
 func (m *ChannelCloseUpdate) GetClosingTxid() []byte {
 	if m != nil {
@@ -961,7 +1655,7 @@ type CloseChannelRequest struct {
 func (m *CloseChannelRequest) Reset()                    { *m = CloseChannelRequest{} }
 func (m *CloseChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*CloseChannelRequest) ProtoMessage()               {}
-func (*CloseChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*CloseChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
 func (m *CloseChannelRequest) GetChannelPoint() *ChannelPoint {
 	if m != nil {
@@ -995,7 +1689,7 @@ type CloseStatusUpdate struct {
 func (m *CloseStatusUpdate) Reset()                    { *m = CloseStatusUpdate{} }
 func (m *CloseStatusUpdate) String() string            { return proto.CompactTextString(m) }
 func (*CloseStatusUpdate) ProtoMessage()               {}
-func (*CloseStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*CloseStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
 type isCloseStatusUpdate_Update interface {
 	isCloseStatusUpdate_Update()
@@ -1143,7 +1837,7 @@ type PendingUpdate struct {
 func (m *PendingUpdate) Reset()                    { *m = PendingUpdate{} }
 func (m *PendingUpdate) String() string            { return proto.CompactTextString(m) }
 func (*PendingUpdate) ProtoMessage()               {}
-func (*PendingUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*PendingUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
 
 func (m *PendingUpdate) GetTxid() []byte {
 	if m != nil {
@@ -1159,12 +1853,13 @@ type OpenChannelRequest struct {
 	LocalFundingAmount int64  `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
 	PushSat            int64  `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
 	NumConfs           uint32 `protobuf:"varint,6,opt,name=num_confs" json:"num_confs,omitempty"`
+	Private            bool   `protobuf:"varint,7,opt,name=private" json:"private,omitempty"`
 }
 
 func (m *OpenChannelRequest) Reset()                    { *m = OpenChannelRequest{} }
 func (m *OpenChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*OpenChannelRequest) ProtoMessage()               {}
-func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*OpenChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
 
 func (m *OpenChannelRequest) GetTargetPeerId() int32 {
 	if m != nil {
@@ -1208,6 +1903,13 @@ func (m *OpenChannelRequest) GetNumConfs() uint32 {
 	return 0
 }
 
+func (m *OpenChannelRequest) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
 type OpenStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*OpenStatusUpdate_ChanPending
@@ -1219,7 +1921,7 @@ type OpenStatusUpdate struct {
 func (m *OpenStatusUpdate) Reset()                    { *m = OpenStatusUpdate{} }
 func (m *OpenStatusUpdate) String() string            { return proto.CompactTextString(m) }
 func (*OpenStatusUpdate) ProtoMessage()               {}
-func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+func (*OpenStatusUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
 type isOpenStatusUpdate_Update interface {
 	isOpenStatusUpdate_Update()
@@ -1367,7 +2069,7 @@ type PendingChannelRequest struct {
 func (m *PendingChannelRequest) Reset()                    { *m = PendingChannelRequest{} }
 func (m *PendingChannelRequest) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelRequest) ProtoMessage()               {}
-func (*PendingChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+func (*PendingChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 func (m *PendingChannelRequest) GetStatus() ChannelStatus {
 	if m != nil {
@@ -1383,7 +2085,7 @@ type PendingChannelResponse struct {
 func (m *PendingChannelResponse) Reset()                    { *m = PendingChannelResponse{} }
 func (m *PendingChannelResponse) String() string            { return proto.CompactTextString(m) }
 func (*PendingChannelResponse) ProtoMessage()               {}
-func (*PendingChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+func (*PendingChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
 func (m *PendingChannelResponse) GetPendingChannels() []*PendingChannelResponse_PendingChannel {
 	if m != nil {
@@ -1407,7 +2109,7 @@ func (m *PendingChannelResponse_PendingChannel) Reset()         { *m = PendingCh
 func (m *PendingChannelResponse_PendingChannel) String() string { return proto.CompactTextString(m) }
 func (*PendingChannelResponse_PendingChannel) ProtoMessage()    {}
 func (*PendingChannelResponse_PendingChannel) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{34, 0}
+	return fileDescriptor0, []int{44, 0}
 }
 
 func (m *PendingChannelResponse_PendingChannel) GetPeerId() int32 {
@@ -1473,7 +2175,7 @@ type WalletBalanceRequest struct {
 func (m *WalletBalanceRequest) Reset()                    { *m = WalletBalanceRequest{} }
 func (m *WalletBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceRequest) ProtoMessage()               {}
-func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 func (m *WalletBalanceRequest) GetWitnessOnly() bool {
 	if m != nil {
@@ -1489,7 +2191,7 @@ type WalletBalanceResponse struct {
 func (m *WalletBalanceResponse) Reset()                    { *m = WalletBalanceResponse{} }
 func (m *WalletBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceResponse) ProtoMessage()               {}
-func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *WalletBalanceResponse) GetBalance() float64 {
 	if m != nil {
@@ -1504,7 +2206,7 @@ type ChannelBalanceRequest struct {
 func (m *ChannelBalanceRequest) Reset()                    { *m = ChannelBalanceRequest{} }
 func (m *ChannelBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceRequest) ProtoMessage()               {}
-func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 type ChannelBalanceResponse struct {
 	Balance int64 `protobuf:"varint,1,opt,name=balance" json:"balance,omitempty"`
@@ -1513,7 +2215,7 @@ type ChannelBalanceResponse struct {
 func (m *ChannelBalanceResponse) Reset()                    { *m = ChannelBalanceResponse{} }
 func (m *ChannelBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceResponse) ProtoMessage()               {}
-func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *ChannelBalanceResponse) GetBalance() int64 {
 	if m != nil {
@@ -1523,14 +2225,22 @@ func (m *ChannelBalanceResponse) GetBalance() int64 {
 }
 
 type RouteRequest struct {
-	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
-	Amt    int64  `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	PubKey         string       `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	Amt            int64        `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	NumRoutes      int32        `protobuf:"varint,3,opt,name=num_routes" json:"num_routes,omitempty"`
+	FeeLimit       int64        `protobuf:"varint,4,opt,name=fee_limit" json:"fee_limit,omitempty"`
+	CltvLimit      uint32       `protobuf:"varint,5,opt,name=cltv_limit" json:"cltv_limit,omitempty"`
+	OutgoingChanId uint64       `protobuf:"varint,6,opt,name=outgoing_chan_id" json:"outgoing_chan_id,omitempty"`
+	LastHopPubkey  []byte       `protobuf:"bytes,7,opt,name=last_hop_pubkey,proto3" json:"last_hop_pubkey,omitempty"`
+	IgnoredNodes   [][]byte     `protobuf:"bytes,8,rep,name=ignored_nodes,proto3" json:"ignored_nodes,omitempty"`
+	IgnoredEdges   []uint64     `protobuf:"varint,9,rep,packed,name=ignored_edges" json:"ignored_edges,omitempty"`
+	RouteHints     []*RouteHint `protobuf:"bytes,10,rep,name=route_hints" json:"route_hints,omitempty"`
 }
 
 func (m *RouteRequest) Reset()                    { *m = RouteRequest{} }
 func (m *RouteRequest) String() string            { return proto.CompactTextString(m) }
 func (*RouteRequest) ProtoMessage()               {}
-func (*RouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*RouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
 func (m *RouteRequest) GetPubKey() string {
 	if m != nil {
@@ -1546,17 +2256,75 @@ func (m *RouteRequest) GetAmt() int64 {
 	return 0
 }
 
+func (m *RouteRequest) GetNumRoutes() int32 {
+	if m != nil {
+		return m.NumRoutes
+	}
+	return 0
+}
+
+func (m *RouteRequest) GetFeeLimit() int64 {
+	if m != nil {
+		return m.FeeLimit
+	}
+	return 0
+}
+
+func (m *RouteRequest) GetCltvLimit() uint32 {
+	if m != nil {
+		return m.CltvLimit
+	}
+	return 0
+}
+
+func (m *RouteRequest) GetOutgoingChanId() uint64 {
+	if m != nil {
+		return m.OutgoingChanId
+	}
+	return 0
+}
+
+func (m *RouteRequest) GetLastHopPubkey() []byte {
+	if m != nil {
+		return m.LastHopPubkey
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetIgnoredNodes() [][]byte {
+	if m != nil {
+		return m.IgnoredNodes
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetIgnoredEdges() []uint64 {
+	if m != nil {
+		return m.IgnoredEdges
+	}
+	return nil
+}
+
+func (m *RouteRequest) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
+
 type Hop struct {
-	ChanId       uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
-	ChanCapacity int64  `protobuf:"varint,2,opt,name=chan_capacity" json:"chan_capacity,omitempty"`
-	AmtToForward int64  `protobuf:"varint,3,opt,name=amt_to_forward" json:"amt_to_forward,omitempty"`
-	Fee          int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+	ChanId        uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	ChanCapacity  int64  `protobuf:"varint,2,opt,name=chan_capacity" json:"chan_capacity,omitempty"`
+	AmtToForward  int64  `protobuf:"varint,3,opt,name=amt_to_forward" json:"amt_to_forward,omitempty"`
+	Fee           int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+	PubKey        string `protobuf:"bytes,5,opt,name=pub_key" json:"pub_key,omitempty"`
+	TimeLockDelta uint32 `protobuf:"varint,6,opt,name=time_lock_delta" json:"time_lock_delta,omitempty"`
 }
 
 func (m *Hop) Reset()                    { *m = Hop{} }
 func (m *Hop) String() string            { return proto.CompactTextString(m) }
 func (*Hop) ProtoMessage()               {}
-func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
 
 func (m *Hop) GetChanId() uint64 {
 	if m != nil {
@@ -1586,6 +2354,20 @@ func (m *Hop) GetFee() int64 {
 	return 0
 }
 
+func (m *Hop) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *Hop) GetTimeLockDelta() uint32 {
+	if m != nil {
+		return m.TimeLockDelta
+	}
+	return 0
+}
+
 type Route struct {
 	TotalTimeLock uint32 `protobuf:"varint,1,opt,name=total_time_lock" json:"total_time_lock,omitempty"`
 	TotalFees     int64  `protobuf:"varint,2,opt,name=total_fees" json:"total_fees,omitempty"`
@@ -1596,7 +2378,7 @@ type Route struct {
 func (m *Route) Reset()                    { *m = Route{} }
 func (m *Route) String() string            { return proto.CompactTextString(m) }
 func (*Route) ProtoMessage()               {}
-func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
 
 func (m *Route) GetTotalTimeLock() uint32 {
 	if m != nil {
@@ -1626,6 +2408,62 @@ func (m *Route) GetHops() []*Hop {
 	return nil
 }
 
+type QueryRouteResponse struct {
+	Routes []*Route `protobuf:"bytes,1,rep,name=routes" json:"routes,omitempty"`
+}
+
+func (m *QueryRouteResponse) Reset()                    { *m = QueryRouteResponse{} }
+func (m *QueryRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryRouteResponse) ProtoMessage()               {}
+func (*QueryRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *QueryRouteResponse) GetRoutes() []*Route {
+	if m != nil {
+		return m.Routes
+	}
+	return nil
+}
+
+type BuildRouteRequest struct {
+	Amt        int64    `protobuf:"varint,1,opt,name=amt" json:"amt,omitempty"`
+	HopPubkeys []string `protobuf:"bytes,2,rep,name=hop_pubkeys" json:"hop_pubkeys,omitempty"`
+}
+
+func (m *BuildRouteRequest) Reset()                    { *m = BuildRouteRequest{} }
+func (m *BuildRouteRequest) String() string            { return proto.CompactTextString(m) }
+func (*BuildRouteRequest) ProtoMessage()               {}
+func (*BuildRouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *BuildRouteRequest) GetAmt() int64 {
+	if m != nil {
+		return m.Amt
+	}
+	return 0
+}
+
+func (m *BuildRouteRequest) GetHopPubkeys() []string {
+	if m != nil {
+		return m.HopPubkeys
+	}
+	return nil
+}
+
+type BuildRouteResponse struct {
+	Route *Route `protobuf:"bytes,1,opt,name=route" json:"route,omitempty"`
+}
+
+func (m *BuildRouteResponse) Reset()                    { *m = BuildRouteResponse{} }
+func (m *BuildRouteResponse) String() string            { return proto.CompactTextString(m) }
+func (*BuildRouteResponse) ProtoMessage()               {}
+func (*BuildRouteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *BuildRouteResponse) GetRoute() *Route {
+	if m != nil {
+		return m.Route
+	}
+	return nil
+}
+
 type NodeInfoRequest struct {
 	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
 }
@@ -1633,7 +2471,7 @@ type NodeInfoRequest struct {
 func (m *NodeInfoRequest) Reset()                    { *m = NodeInfoRequest{} }
 func (m *NodeInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfoRequest) ProtoMessage()               {}
-func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
 
 func (m *NodeInfoRequest) GetPubKey() string {
 	if m != nil {
@@ -1651,7 +2489,7 @@ type NodeInfo struct {
 func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
 func (m *NodeInfo) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfo) ProtoMessage()               {}
-func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
 
 func (m *NodeInfo) GetNode() *LightningNode {
 	if m != nil {
@@ -1684,7 +2522,7 @@ type LightningNode struct {
 func (m *LightningNode) Reset()                    { *m = LightningNode{} }
 func (m *LightningNode) String() string            { return proto.CompactTextString(m) }
 func (*LightningNode) ProtoMessage()               {}
-func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
 
 func (m *LightningNode) GetLastUpdate() uint32 {
 	if m != nil {
@@ -1719,12 +2557,13 @@ type RoutingPolicy struct {
 	MinHtlc          int64  `protobuf:"varint,2,opt,name=min_htlc" json:"min_htlc,omitempty"`
 	FeeBaseMsat      int64  `protobuf:"varint,3,opt,name=fee_base_msat" json:"fee_base_msat,omitempty"`
 	FeeRateMilliMsat int64  `protobuf:"varint,4,opt,name=fee_rate_milli_msat" json:"fee_rate_milli_msat,omitempty"`
+	MaxHtlc          int64  `protobuf:"varint,5,opt,name=max_htlc" json:"max_htlc,omitempty"`
 }
 
 func (m *RoutingPolicy) Reset()                    { *m = RoutingPolicy{} }
 func (m *RoutingPolicy) String() string            { return proto.CompactTextString(m) }
 func (*RoutingPolicy) ProtoMessage()               {}
-func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
 
 func (m *RoutingPolicy) GetTimeLockDelta() uint32 {
 	if m != nil {
@@ -1754,10 +2593,17 @@ func (m *RoutingPolicy) GetFeeRateMilliMsat() int64 {
 	return 0
 }
 
-type ChannelEdge struct {
-	ChannelId   uint64         `protobuf:"varint,1,opt,name=channel_id" json:"channel_id,omitempty"`
-	ChanPoint   string         `protobuf:"bytes,2,opt,name=chan_point" json:"chan_point,omitempty"`
-	LastUpdate  uint32         `protobuf:"varint,3,opt,name=last_update" json:"last_update,omitempty"`
+func (m *RoutingPolicy) GetMaxHtlc() int64 {
+	if m != nil {
+		return m.MaxHtlc
+	}
+	return 0
+}
+
+type ChannelEdge struct {
+	ChannelId   uint64         `protobuf:"varint,1,opt,name=channel_id" json:"channel_id,omitempty"`
+	ChanPoint   string         `protobuf:"bytes,2,opt,name=chan_point" json:"chan_point,omitempty"`
+	LastUpdate  uint32         `protobuf:"varint,3,opt,name=last_update" json:"last_update,omitempty"`
 	Node1Pub    string         `protobuf:"bytes,4,opt,name=node1_pub" json:"node1_pub,omitempty"`
 	Node2Pub    string         `protobuf:"bytes,5,opt,name=node2_pub" json:"node2_pub,omitempty"`
 	Capacity    int64          `protobuf:"varint,6,opt,name=capacity" json:"capacity,omitempty"`
@@ -1768,7 +2614,7 @@ type ChannelEdge struct {
 func (m *ChannelEdge) Reset()                    { *m = ChannelEdge{} }
 func (m *ChannelEdge) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdge) ProtoMessage()               {}
-func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
 
 func (m *ChannelEdge) GetChannelId() uint64 {
 	if m != nil {
@@ -1832,7 +2678,7 @@ type ChannelGraphRequest struct {
 func (m *ChannelGraphRequest) Reset()                    { *m = ChannelGraphRequest{} }
 func (m *ChannelGraphRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraphRequest) ProtoMessage()               {}
-func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
 
 type ChannelGraph struct {
 	Nodes []*LightningNode `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
@@ -1842,7 +2688,7 @@ type ChannelGraph struct {
 func (m *ChannelGraph) Reset()                    { *m = ChannelGraph{} }
 func (m *ChannelGraph) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraph) ProtoMessage()               {}
-func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
 
 func (m *ChannelGraph) GetNodes() []*LightningNode {
 	if m != nil {
@@ -1865,7 +2711,7 @@ type ChanInfoRequest struct {
 func (m *ChanInfoRequest) Reset()                    { *m = ChanInfoRequest{} }
 func (m *ChanInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChanInfoRequest) ProtoMessage()               {}
-func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *ChanInfoRequest) GetChanId() uint64 {
 	if m != nil {
@@ -1880,7 +2726,7 @@ type NetworkInfoRequest struct {
 func (m *NetworkInfoRequest) Reset()                    { *m = NetworkInfoRequest{} }
 func (m *NetworkInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfoRequest) ProtoMessage()               {}
-func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 type NetworkInfo struct {
 	GraphDiameter        uint32  `protobuf:"varint,1,opt,name=graph_diameter" json:"graph_diameter,omitempty"`
@@ -1897,7 +2743,7 @@ type NetworkInfo struct {
 func (m *NetworkInfo) Reset()                    { *m = NetworkInfo{} }
 func (m *NetworkInfo) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfo) ProtoMessage()               {}
-func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 func (m *NetworkInfo) GetGraphDiameter() uint32 {
 	if m != nil {
@@ -1962,6 +2808,190 @@ func (m *NetworkInfo) GetMaxChannelSize() int64 {
 	return 0
 }
 
+type GraphTopologySubscription struct {
+}
+
+func (m *GraphTopologySubscription) Reset()                    { *m = GraphTopologySubscription{} }
+func (m *GraphTopologySubscription) String() string            { return proto.CompactTextString(m) }
+func (*GraphTopologySubscription) ProtoMessage()               {}
+func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+type GraphTopologyUpdate struct {
+	NodeUpdates    []*NodeUpdate          `protobuf:"bytes,1,rep,name=node_updates" json:"node_updates,omitempty"`
+	ChannelUpdates []*ChannelEdgeUpdate   `protobuf:"bytes,2,rep,name=channel_updates" json:"channel_updates,omitempty"`
+	ClosedChans    []*ClosedChannelUpdate `protobuf:"bytes,3,rep,name=closed_chans" json:"closed_chans,omitempty"`
+}
+
+func (m *GraphTopologyUpdate) Reset()                    { *m = GraphTopologyUpdate{} }
+func (m *GraphTopologyUpdate) String() string            { return proto.CompactTextString(m) }
+func (*GraphTopologyUpdate) ProtoMessage()               {}
+func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *GraphTopologyUpdate) GetNodeUpdates() []*NodeUpdate {
+	if m != nil {
+		return m.NodeUpdates
+	}
+	return nil
+}
+
+func (m *GraphTopologyUpdate) GetChannelUpdates() []*ChannelEdgeUpdate {
+	if m != nil {
+		return m.ChannelUpdates
+	}
+	return nil
+}
+
+func (m *GraphTopologyUpdate) GetClosedChans() []*ClosedChannelUpdate {
+	if m != nil {
+		return m.ClosedChans
+	}
+	return nil
+}
+
+type NodeUpdate struct {
+	Address     string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	IdentityKey string `protobuf:"bytes,2,opt,name=identity_key" json:"identity_key,omitempty"`
+	Alias       string `protobuf:"bytes,3,opt,name=alias" json:"alias,omitempty"`
+	Color       string `protobuf:"bytes,4,opt,name=color" json:"color,omitempty"`
+}
+
+func (m *NodeUpdate) Reset()                    { *m = NodeUpdate{} }
+func (m *NodeUpdate) String() string            { return proto.CompactTextString(m) }
+func (*NodeUpdate) ProtoMessage()               {}
+func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *NodeUpdate) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *NodeUpdate) GetIdentityKey() string {
+	if m != nil {
+		return m.IdentityKey
+	}
+	return ""
+}
+
+func (m *NodeUpdate) GetAlias() string {
+	if m != nil {
+		return m.Alias
+	}
+	return ""
+}
+
+func (m *NodeUpdate) GetColor() string {
+	if m != nil {
+		return m.Color
+	}
+	return ""
+}
+
+type ChannelEdgeUpdate struct {
+	ChanId            uint64         `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	ChanPoint         string         `protobuf:"bytes,2,opt,name=chan_point" json:"chan_point,omitempty"`
+	Capacity          int64          `protobuf:"varint,3,opt,name=capacity" json:"capacity,omitempty"`
+	RoutingPolicy     *RoutingPolicy `protobuf:"bytes,4,opt,name=routing_policy" json:"routing_policy,omitempty"`
+	PrevRoutingPolicy *RoutingPolicy `protobuf:"bytes,5,opt,name=prev_routing_policy" json:"prev_routing_policy,omitempty"`
+	AdvertisingNode   string         `protobuf:"bytes,6,opt,name=advertising_node" json:"advertising_node,omitempty"`
+	ConnectingNode    string         `protobuf:"bytes,7,opt,name=connecting_node" json:"connecting_node,omitempty"`
+}
+
+func (m *ChannelEdgeUpdate) Reset()                    { *m = ChannelEdgeUpdate{} }
+func (m *ChannelEdgeUpdate) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEdgeUpdate) ProtoMessage()               {}
+func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *ChannelEdgeUpdate) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *ChannelEdgeUpdate) GetChanPoint() string {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return ""
+}
+
+func (m *ChannelEdgeUpdate) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ChannelEdgeUpdate) GetRoutingPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.RoutingPolicy
+	}
+	return nil
+}
+
+func (m *ChannelEdgeUpdate) GetPrevRoutingPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.PrevRoutingPolicy
+	}
+	return nil
+}
+
+func (m *ChannelEdgeUpdate) GetAdvertisingNode() string {
+	if m != nil {
+		return m.AdvertisingNode
+	}
+	return ""
+}
+
+func (m *ChannelEdgeUpdate) GetConnectingNode() string {
+	if m != nil {
+		return m.ConnectingNode
+	}
+	return ""
+}
+
+type ClosedChannelUpdate struct {
+	ChanId       uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	Capacity     int64  `protobuf:"varint,2,opt,name=capacity" json:"capacity,omitempty"`
+	ClosedHeight uint32 `protobuf:"varint,3,opt,name=closed_height" json:"closed_height,omitempty"`
+	ChanPoint    string `protobuf:"bytes,4,opt,name=chan_point" json:"chan_point,omitempty"`
+}
+
+func (m *ClosedChannelUpdate) Reset()                    { *m = ClosedChannelUpdate{} }
+func (m *ClosedChannelUpdate) String() string            { return proto.CompactTextString(m) }
+func (*ClosedChannelUpdate) ProtoMessage()               {}
+func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *ClosedChannelUpdate) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *ClosedChannelUpdate) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ClosedChannelUpdate) GetClosedHeight() uint32 {
+	if m != nil {
+		return m.ClosedHeight
+	}
+	return 0
+}
+
+func (m *ClosedChannelUpdate) GetChanPoint() string {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return ""
+}
+
 type SetAliasRequest struct {
 	NewAlias string `protobuf:"bytes,1,opt,name=new_alias" json:"new_alias,omitempty"`
 }
@@ -1969,7 +2999,7 @@ type SetAliasRequest struct {
 func (m *SetAliasRequest) Reset()                    { *m = SetAliasRequest{} }
 func (m *SetAliasRequest) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasRequest) ProtoMessage()               {}
-func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
 
 func (m *SetAliasRequest) GetNewAlias() string {
 	if m != nil {
@@ -1984,24 +3014,35 @@ type SetAliasResponse struct {
 func (m *SetAliasResponse) Reset()                    { *m = SetAliasResponse{} }
 func (m *SetAliasResponse) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasResponse) ProtoMessage()               {}
-func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
 
 type Invoice struct {
-	Memo           string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
-	Receipt        []byte `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
-	RPreimage      []byte `protobuf:"bytes,3,opt,name=r_preimage,proto3" json:"r_preimage,omitempty"`
-	RHash          []byte `protobuf:"bytes,4,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
-	Value          int64  `protobuf:"varint,5,opt,name=value" json:"value,omitempty"`
-	Settled        bool   `protobuf:"varint,6,opt,name=settled" json:"settled,omitempty"`
-	CreationDate   int64  `protobuf:"varint,7,opt,name=creation_date" json:"creation_date,omitempty"`
-	SettleDate     int64  `protobuf:"varint,8,opt,name=settle_date" json:"settle_date,omitempty"`
-	PaymentRequest string `protobuf:"bytes,9,opt,name=payment_request" json:"payment_request,omitempty"`
+	Memo            string               `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	Receipt         []byte               `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	RPreimage       []byte               `protobuf:"bytes,3,opt,name=r_preimage,proto3" json:"r_preimage,omitempty"`
+	RHash           []byte               `protobuf:"bytes,4,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	Value           int64                `protobuf:"varint,5,opt,name=value" json:"value,omitempty"`
+	Settled         bool                 `protobuf:"varint,6,opt,name=settled" json:"settled,omitempty"`
+	CreationDate    int64                `protobuf:"varint,7,opt,name=creation_date" json:"creation_date,omitempty"`
+	SettleDate      int64                `protobuf:"varint,8,opt,name=settle_date" json:"settle_date,omitempty"`
+	PaymentRequest  string               `protobuf:"bytes,9,opt,name=payment_request" json:"payment_request,omitempty"`
+	Private         bool                 `protobuf:"varint,10,opt,name=private" json:"private,omitempty"`
+	RouteHints      []*RouteHint         `protobuf:"bytes,11,rep,name=route_hints" json:"route_hints,omitempty"`
+	DescriptionHash []byte               `protobuf:"bytes,12,opt,name=description_hash,proto3" json:"description_hash,omitempty"`
+	Expiry          int64                `protobuf:"varint,13,opt,name=expiry" json:"expiry,omitempty"`
+	FallbackAddr    string               `protobuf:"bytes,14,opt,name=fallback_addr" json:"fallback_addr,omitempty"`
+	CltvExpiry      uint64               `protobuf:"varint,15,opt,name=cltv_expiry" json:"cltv_expiry,omitempty"`
+	State           Invoice_InvoiceState `protobuf:"varint,16,opt,name=state,enum=lnrpc.Invoice_InvoiceState" json:"state,omitempty"`
+	AddIndex        uint64               `protobuf:"varint,17,opt,name=add_index" json:"add_index,omitempty"`
+	SettleIndex     uint64               `protobuf:"varint,18,opt,name=settle_index" json:"settle_index,omitempty"`
+	AmtPaid         int64                `protobuf:"varint,19,opt,name=amt_paid" json:"amt_paid,omitempty"`
+	CustomRecords   map[uint64][]byte    `protobuf:"bytes,20,rep,name=custom_records" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *Invoice) Reset()                    { *m = Invoice{} }
 func (m *Invoice) String() string            { return proto.CompactTextString(m) }
 func (*Invoice) ProtoMessage()               {}
-func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
 
 func (m *Invoice) GetMemo() string {
 	if m != nil {
@@ -2066,6 +3107,147 @@ func (m *Invoice) GetPaymentRequest() string {
 	return ""
 }
 
+func (m *Invoice) GetPrivate() bool {
+	if m != nil {
+		return m.Private
+	}
+	return false
+}
+
+func (m *Invoice) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
+
+func (m *Invoice) GetDescriptionHash() []byte {
+	if m != nil {
+		return m.DescriptionHash
+	}
+	return nil
+}
+
+func (m *Invoice) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Invoice) GetFallbackAddr() string {
+	if m != nil {
+		return m.FallbackAddr
+	}
+	return ""
+}
+
+func (m *Invoice) GetCltvExpiry() uint64 {
+	if m != nil {
+		return m.CltvExpiry
+	}
+	return 0
+}
+
+func (m *Invoice) GetState() Invoice_InvoiceState {
+	if m != nil {
+		return m.State
+	}
+	return Invoice_OPEN
+}
+
+func (m *Invoice) GetAddIndex() uint64 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
+}
+
+func (m *Invoice) GetSettleIndex() uint64 {
+	if m != nil {
+		return m.SettleIndex
+	}
+	return 0
+}
+
+func (m *Invoice) GetAmtPaid() int64 {
+	if m != nil {
+		return m.AmtPaid
+	}
+	return 0
+}
+
+func (m *Invoice) GetCustomRecords() map[uint64][]byte {
+	if m != nil {
+		return m.CustomRecords
+	}
+	return nil
+}
+
+type HopHint struct {
+	NodeId                    string `protobuf:"bytes,1,opt,name=node_id" json:"node_id,omitempty"`
+	ChanId                    uint64 `protobuf:"varint,2,opt,name=chan_id" json:"chan_id,omitempty"`
+	FeeBaseMsat               uint32 `protobuf:"varint,3,opt,name=fee_base_msat" json:"fee_base_msat,omitempty"`
+	FeeProportionalMillionths uint32 `protobuf:"varint,4,opt,name=fee_proportional_millionths" json:"fee_proportional_millionths,omitempty"`
+	CltvExpiryDelta           uint32 `protobuf:"varint,5,opt,name=cltv_expiry_delta" json:"cltv_expiry_delta,omitempty"`
+}
+
+func (m *HopHint) Reset()                    { *m = HopHint{} }
+func (m *HopHint) String() string            { return proto.CompactTextString(m) }
+func (*HopHint) ProtoMessage()               {}
+func (*HopHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *HopHint) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+func (m *HopHint) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeBaseMsat() uint32 {
+	if m != nil {
+		return m.FeeBaseMsat
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeProportionalMillionths() uint32 {
+	if m != nil {
+		return m.FeeProportionalMillionths
+	}
+	return 0
+}
+
+func (m *HopHint) GetCltvExpiryDelta() uint32 {
+	if m != nil {
+		return m.CltvExpiryDelta
+	}
+	return 0
+}
+
+type RouteHint struct {
+	HopHints []*HopHint `protobuf:"bytes,1,rep,name=hop_hints" json:"hop_hints,omitempty"`
+}
+
+func (m *RouteHint) Reset()                    { *m = RouteHint{} }
+func (m *RouteHint) String() string            { return proto.CompactTextString(m) }
+func (*RouteHint) ProtoMessage()               {}
+func (*RouteHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *RouteHint) GetHopHints() []*HopHint {
+	if m != nil {
+		return m.HopHints
+	}
+	return nil
+}
+
 type AddInvoiceResponse struct {
 	RHash          []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
 	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
@@ -2074,7 +3256,7 @@ type AddInvoiceResponse struct {
 func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
 func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*AddInvoiceResponse) ProtoMessage()               {}
-func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
 
 func (m *AddInvoiceResponse) GetRHash() []byte {
 	if m != nil {
@@ -2098,7 +3280,7 @@ type PaymentHash struct {
 func (m *PaymentHash) Reset()                    { *m = PaymentHash{} }
 func (m *PaymentHash) String() string            { return proto.CompactTextString(m) }
 func (*PaymentHash) ProtoMessage()               {}
-func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
 
 func (m *PaymentHash) GetRHashStr() string {
 	if m != nil {
@@ -2115,13 +3297,19 @@ func (m *PaymentHash) GetRHash() []byte {
 }
 
 type ListInvoiceRequest struct {
-	PendingOnly bool `protobuf:"varint,1,opt,name=pending_only" json:"pending_only,omitempty"`
+	PendingOnly       bool                   `protobuf:"varint,1,opt,name=pending_only" json:"pending_only,omitempty"`
+	States            []Invoice_InvoiceState `protobuf:"varint,2,rep,packed,name=states,enum=lnrpc.Invoice_InvoiceState" json:"states,omitempty"`
+	IndexOffset       uint64                 `protobuf:"varint,3,opt,name=index_offset" json:"index_offset,omitempty"`
+	NumMaxInvoices    uint64                 `protobuf:"varint,4,opt,name=num_max_invoices" json:"num_max_invoices,omitempty"`
+	Reversed          bool                   `protobuf:"varint,5,opt,name=reversed" json:"reversed,omitempty"`
+	CreationDateStart int64                  `protobuf:"varint,6,opt,name=creation_date_start" json:"creation_date_start,omitempty"`
+	CreationDateEnd   int64                  `protobuf:"varint,7,opt,name=creation_date_end" json:"creation_date_end,omitempty"`
 }
 
 func (m *ListInvoiceRequest) Reset()                    { *m = ListInvoiceRequest{} }
 func (m *ListInvoiceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceRequest) ProtoMessage()               {}
-func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
 
 func (m *ListInvoiceRequest) GetPendingOnly() bool {
 	if m != nil {
@@ -2130,42 +3318,165 @@ func (m *ListInvoiceRequest) GetPendingOnly() bool {
 	return false
 }
 
-type ListInvoiceResponse struct {
-	Invoices []*Invoice `protobuf:"bytes,1,rep,name=invoices" json:"invoices,omitempty"`
+func (m *ListInvoiceRequest) GetStates() []Invoice_InvoiceState {
+	if m != nil {
+		return m.States
+	}
+	return nil
+}
+
+func (m *ListInvoiceRequest) GetIndexOffset() uint64 {
+	if m != nil {
+		return m.IndexOffset
+	}
+	return 0
+}
+
+func (m *ListInvoiceRequest) GetNumMaxInvoices() uint64 {
+	if m != nil {
+		return m.NumMaxInvoices
+	}
+	return 0
+}
+
+func (m *ListInvoiceRequest) GetReversed() bool {
+	if m != nil {
+		return m.Reversed
+	}
+	return false
+}
+
+func (m *ListInvoiceRequest) GetCreationDateStart() int64 {
+	if m != nil {
+		return m.CreationDateStart
+	}
+	return 0
+}
+
+func (m *ListInvoiceRequest) GetCreationDateEnd() int64 {
+	if m != nil {
+		return m.CreationDateEnd
+	}
+	return 0
+}
+
+type CancelInvoiceResponse struct {
+}
+
+func (m *CancelInvoiceResponse) Reset()                    { *m = CancelInvoiceResponse{} }
+func (m *CancelInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*CancelInvoiceResponse) ProtoMessage()               {}
+func (*CancelInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+type SettleInvoiceRequest struct {
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (m *SettleInvoiceRequest) Reset()                    { *m = SettleInvoiceRequest{} }
+func (m *SettleInvoiceRequest) String() string            { return proto.CompactTextString(m) }
+func (*SettleInvoiceRequest) ProtoMessage()               {}
+func (*SettleInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *SettleInvoiceRequest) GetPreimage() []byte {
+	if m != nil {
+		return m.Preimage
+	}
+	return nil
+}
+
+type SettleInvoiceResponse struct {
+}
+
+func (m *SettleInvoiceResponse) Reset()                    { *m = SettleInvoiceResponse{} }
+func (m *SettleInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*SettleInvoiceResponse) ProtoMessage()               {}
+func (*SettleInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+type ListInvoiceResponse struct {
+	Invoices         []*Invoice `protobuf:"bytes,1,rep,name=invoices" json:"invoices,omitempty"`
+	FirstIndexOffset uint64     `protobuf:"varint,2,opt,name=first_index_offset" json:"first_index_offset,omitempty"`
+	LastIndexOffset  uint64     `protobuf:"varint,3,opt,name=last_index_offset" json:"last_index_offset,omitempty"`
+}
+
+func (m *ListInvoiceResponse) Reset()                    { *m = ListInvoiceResponse{} }
+func (m *ListInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListInvoiceResponse) ProtoMessage()               {}
+func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
+	if m != nil {
+		return m.Invoices
+	}
+	return nil
+}
+
+func (m *ListInvoiceResponse) GetFirstIndexOffset() uint64 {
+	if m != nil {
+		return m.FirstIndexOffset
+	}
+	return 0
+}
+
+func (m *ListInvoiceResponse) GetLastIndexOffset() uint64 {
+	if m != nil {
+		return m.LastIndexOffset
+	}
+	return 0
+}
+
+type InvoiceSubscription struct {
+	AddIndex        uint64 `protobuf:"varint,1,opt,name=add_index" json:"add_index,omitempty"`
+	SettleIndex     uint64 `protobuf:"varint,2,opt,name=settle_index" json:"settle_index,omitempty"`
+	IncludeCanceled bool   `protobuf:"varint,3,opt,name=include_canceled" json:"include_canceled,omitempty"`
+	IncludeAccepted bool   `protobuf:"varint,4,opt,name=include_accepted" json:"include_accepted,omitempty"`
+}
+
+func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
+func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceSubscription) ProtoMessage()               {}
+func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *InvoiceSubscription) GetAddIndex() uint64 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
+}
+
+func (m *InvoiceSubscription) GetSettleIndex() uint64 {
+	if m != nil {
+		return m.SettleIndex
+	}
+	return 0
 }
 
-func (m *ListInvoiceResponse) Reset()                    { *m = ListInvoiceResponse{} }
-func (m *ListInvoiceResponse) String() string            { return proto.CompactTextString(m) }
-func (*ListInvoiceResponse) ProtoMessage()               {}
-func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
-
-func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
+func (m *InvoiceSubscription) GetIncludeCanceled() bool {
 	if m != nil {
-		return m.Invoices
+		return m.IncludeCanceled
 	}
-	return nil
+	return false
 }
 
-type InvoiceSubscription struct {
+func (m *InvoiceSubscription) GetIncludeAccepted() bool {
+	if m != nil {
+		return m.IncludeAccepted
+	}
+	return false
 }
 
-func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
-func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
-func (*InvoiceSubscription) ProtoMessage()               {}
-func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
-
 type Payment struct {
 	PaymentHash  string   `protobuf:"bytes,1,opt,name=payment_hash" json:"payment_hash,omitempty"`
 	Value        int64    `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
 	CreationDate int64    `protobuf:"varint,3,opt,name=creation_date" json:"creation_date,omitempty"`
 	Path         []string `protobuf:"bytes,4,rep,name=path" json:"path,omitempty"`
 	Fee          int64    `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
+	PaymentIndex uint64   `protobuf:"varint,6,opt,name=payment_index" json:"payment_index,omitempty"`
 }
 
 func (m *Payment) Reset()                    { *m = Payment{} }
 func (m *Payment) String() string            { return proto.CompactTextString(m) }
 func (*Payment) ProtoMessage()               {}
-func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
 
 func (m *Payment) GetPaymentHash() string {
 	if m != nil {
@@ -2202,22 +3513,71 @@ func (m *Payment) GetFee() int64 {
 	return 0
 }
 
+func (m *Payment) GetPaymentIndex() uint64 {
+	if m != nil {
+		return m.PaymentIndex
+	}
+	return 0
+}
+
 type ListPaymentsRequest struct {
+	IndexOffset       uint64 `protobuf:"varint,1,opt,name=index_offset" json:"index_offset,omitempty"`
+	MaxPayments       uint64 `protobuf:"varint,2,opt,name=max_payments" json:"max_payments,omitempty"`
+	Reversed          bool   `protobuf:"varint,3,opt,name=reversed" json:"reversed,omitempty"`
+	CreationDateStart int64  `protobuf:"varint,4,opt,name=creation_date_start" json:"creation_date_start,omitempty"`
+	CreationDateEnd   int64  `protobuf:"varint,5,opt,name=creation_date_end" json:"creation_date_end,omitempty"`
 }
 
 func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
 func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsRequest) ProtoMessage()               {}
-func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+func (m *ListPaymentsRequest) GetIndexOffset() uint64 {
+	if m != nil {
+		return m.IndexOffset
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetMaxPayments() uint64 {
+	if m != nil {
+		return m.MaxPayments
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetReversed() bool {
+	if m != nil {
+		return m.Reversed
+	}
+	return false
+}
+
+func (m *ListPaymentsRequest) GetCreationDateStart() int64 {
+	if m != nil {
+		return m.CreationDateStart
+	}
+	return 0
+}
+
+func (m *ListPaymentsRequest) GetCreationDateEnd() int64 {
+	if m != nil {
+		return m.CreationDateEnd
+	}
+	return 0
+}
 
 type ListPaymentsResponse struct {
-	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	Payments         []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	FirstIndexOffset uint64     `protobuf:"varint,2,opt,name=first_index_offset" json:"first_index_offset,omitempty"`
+	LastIndexOffset  uint64     `protobuf:"varint,3,opt,name=last_index_offset" json:"last_index_offset,omitempty"`
 }
 
 func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
 func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsResponse) ProtoMessage()               {}
-func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
 
 func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	if m != nil {
@@ -2226,13 +3586,27 @@ func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	return nil
 }
 
+func (m *ListPaymentsResponse) GetFirstIndexOffset() uint64 {
+	if m != nil {
+		return m.FirstIndexOffset
+	}
+	return 0
+}
+
+func (m *ListPaymentsResponse) GetLastIndexOffset() uint64 {
+	if m != nil {
+		return m.LastIndexOffset
+	}
+	return 0
+}
+
 type DeleteAllPaymentsRequest struct {
 }
 
 func (m *DeleteAllPaymentsRequest) Reset()                    { *m = DeleteAllPaymentsRequest{} }
 func (m *DeleteAllPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsRequest) ProtoMessage()               {}
-func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
 
 type DeleteAllPaymentsResponse struct {
 }
@@ -2240,7 +3614,7 @@ type DeleteAllPaymentsResponse struct {
 func (m *DeleteAllPaymentsResponse) Reset()                    { *m = DeleteAllPaymentsResponse{} }
 func (m *DeleteAllPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsResponse) ProtoMessage()               {}
-func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
 
 type DebugLevelRequest struct {
 	Show      bool   `protobuf:"varint,1,opt,name=show" json:"show,omitempty"`
@@ -2250,7 +3624,7 @@ type DebugLevelRequest struct {
 func (m *DebugLevelRequest) Reset()                    { *m = DebugLevelRequest{} }
 func (m *DebugLevelRequest) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelRequest) ProtoMessage()               {}
-func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
 
 func (m *DebugLevelRequest) GetShow() bool {
 	if m != nil {
@@ -2273,7 +3647,7 @@ type DebugLevelResponse struct {
 func (m *DebugLevelResponse) Reset()                    { *m = DebugLevelResponse{} }
 func (m *DebugLevelResponse) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelResponse) ProtoMessage()               {}
-func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
 
 func (m *DebugLevelResponse) GetSubSystems() string {
 	if m != nil {
@@ -2289,7 +3663,7 @@ type PayReqString struct {
 func (m *PayReqString) Reset()                    { *m = PayReqString{} }
 func (m *PayReqString) String() string            { return proto.CompactTextString(m) }
 func (*PayReqString) ProtoMessage()               {}
-func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
 
 func (m *PayReqString) GetPayReq() string {
 	if m != nil {
@@ -2299,15 +3673,23 @@ func (m *PayReqString) GetPayReq() string {
 }
 
 type PayReq struct {
-	Destination string `protobuf:"bytes,1,opt,name=destination" json:"destination,omitempty"`
-	PaymentHash string `protobuf:"bytes,2,opt,name=payment_hash" json:"payment_hash,omitempty"`
-	NumSatoshis int64  `protobuf:"varint,3,opt,name=num_satoshis" json:"num_satoshis,omitempty"`
+	Destination     string       `protobuf:"bytes,1,opt,name=destination" json:"destination,omitempty"`
+	PaymentHash     string       `protobuf:"bytes,2,opt,name=payment_hash" json:"payment_hash,omitempty"`
+	NumSatoshis     int64        `protobuf:"varint,3,opt,name=num_satoshis" json:"num_satoshis,omitempty"`
+	RouteHints      []*RouteHint `protobuf:"bytes,4,rep,name=route_hints" json:"route_hints,omitempty"`
+	Timestamp       int64        `protobuf:"varint,5,opt,name=timestamp" json:"timestamp,omitempty"`
+	Expiry          int64        `protobuf:"varint,6,opt,name=expiry" json:"expiry,omitempty"`
+	Description     string       `protobuf:"bytes,7,opt,name=description" json:"description,omitempty"`
+	DescriptionHash string       `protobuf:"bytes,8,opt,name=description_hash" json:"description_hash,omitempty"`
+	FallbackAddr    string       `protobuf:"bytes,9,opt,name=fallback_addr" json:"fallback_addr,omitempty"`
+	CltvExpiry      int64        `protobuf:"varint,10,opt,name=cltv_expiry" json:"cltv_expiry,omitempty"`
+	Features        []uint32     `protobuf:"varint,11,rep,packed,name=features" json:"features,omitempty"`
 }
 
 func (m *PayReq) Reset()                    { *m = PayReq{} }
 func (m *PayReq) String() string            { return proto.CompactTextString(m) }
 func (*PayReq) ProtoMessage()               {}
-func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
 
 func (m *PayReq) GetDestination() string {
 	if m != nil {
@@ -2330,12 +3712,281 @@ func (m *PayReq) GetNumSatoshis() int64 {
 	return 0
 }
 
+func (m *PayReq) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
+
+func (m *PayReq) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *PayReq) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *PayReq) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *PayReq) GetDescriptionHash() string {
+	if m != nil {
+		return m.DescriptionHash
+	}
+	return ""
+}
+
+func (m *PayReq) GetFallbackAddr() string {
+	if m != nil {
+		return m.FallbackAddr
+	}
+	return ""
+}
+
+func (m *PayReq) GetCltvExpiry() int64 {
+	if m != nil {
+		return m.CltvExpiry
+	}
+	return 0
+}
+
+func (m *PayReq) GetFeatures() []uint32 {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+type PairHistory struct {
+	NodeFrom        string  `protobuf:"bytes,1,opt,name=node_from" json:"node_from,omitempty"`
+	NodeTo          string  `protobuf:"bytes,2,opt,name=node_to" json:"node_to,omitempty"`
+	LastFailTime    int64   `protobuf:"varint,3,opt,name=last_fail_time" json:"last_fail_time,omitempty"`
+	LastFailAmt     int64   `protobuf:"varint,4,opt,name=last_fail_amt" json:"last_fail_amt,omitempty"`
+	LastSuccessTime int64   `protobuf:"varint,5,opt,name=last_success_time" json:"last_success_time,omitempty"`
+	LastSuccessAmt  int64   `protobuf:"varint,6,opt,name=last_success_amt" json:"last_success_amt,omitempty"`
+	SuccessProb     float64 `protobuf:"fixed64,7,opt,name=success_prob" json:"success_prob,omitempty"`
+}
+
+func (m *PairHistory) Reset()                    { *m = PairHistory{} }
+func (m *PairHistory) String() string            { return proto.CompactTextString(m) }
+func (*PairHistory) ProtoMessage()               {}
+func (*PairHistory) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+func (m *PairHistory) GetNodeFrom() string {
+	if m != nil {
+		return m.NodeFrom
+	}
+	return ""
+}
+
+func (m *PairHistory) GetNodeTo() string {
+	if m != nil {
+		return m.NodeTo
+	}
+	return ""
+}
+
+func (m *PairHistory) GetLastFailTime() int64 {
+	if m != nil {
+		return m.LastFailTime
+	}
+	return 0
+}
+
+func (m *PairHistory) GetLastFailAmt() int64 {
+	if m != nil {
+		return m.LastFailAmt
+	}
+	return 0
+}
+
+func (m *PairHistory) GetLastSuccessTime() int64 {
+	if m != nil {
+		return m.LastSuccessTime
+	}
+	return 0
+}
+
+func (m *PairHistory) GetLastSuccessAmt() int64 {
+	if m != nil {
+		return m.LastSuccessAmt
+	}
+	return 0
+}
+
+func (m *PairHistory) GetSuccessProb() float64 {
+	if m != nil {
+		return m.SuccessProb
+	}
+	return 0
+}
+
+type QueryMissionControlRequest struct {
+}
+
+func (m *QueryMissionControlRequest) Reset()                    { *m = QueryMissionControlRequest{} }
+func (m *QueryMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlRequest) ProtoMessage()               {}
+func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+type QueryMissionControlResponse struct {
+	Pairs []*PairHistory `protobuf:"bytes,1,rep,name=pairs" json:"pairs,omitempty"`
+}
+
+func (m *QueryMissionControlResponse) Reset()                    { *m = QueryMissionControlResponse{} }
+func (m *QueryMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlResponse) ProtoMessage()               {}
+func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
+
+func (m *QueryMissionControlResponse) GetPairs() []*PairHistory {
+	if m != nil {
+		return m.Pairs
+	}
+	return nil
+}
+
+type ResetMissionControlRequest struct {
+}
+
+func (m *ResetMissionControlRequest) Reset()                    { *m = ResetMissionControlRequest{} }
+func (m *ResetMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlRequest) ProtoMessage()               {}
+func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
+
+type ResetMissionControlResponse struct {
+}
+
+func (m *ResetMissionControlResponse) Reset()                    { *m = ResetMissionControlResponse{} }
+func (m *ResetMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlResponse) ProtoMessage()               {}
+func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+type AutopilotStatusRequest struct {
+}
+
+func (m *AutopilotStatusRequest) Reset()                    { *m = AutopilotStatusRequest{} }
+func (m *AutopilotStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*AutopilotStatusRequest) ProtoMessage()               {}
+func (*AutopilotStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+type AutopilotStatusResponse struct {
+	Active          bool    `protobuf:"varint,1,opt,name=active" json:"active,omitempty"`
+	MaxChannels     uint32  `protobuf:"varint,2,opt,name=max_channels" json:"max_channels,omitempty"`
+	Allocation      float64 `protobuf:"fixed64,3,opt,name=allocation" json:"allocation,omitempty"`
+	MinChanSize     int64   `protobuf:"varint,4,opt,name=min_chan_size" json:"min_chan_size,omitempty"`
+	MaxChanSize     int64   `protobuf:"varint,5,opt,name=max_chan_size" json:"max_chan_size,omitempty"`
+	NumChannels     uint32  `protobuf:"varint,6,opt,name=num_channels" json:"num_channels,omitempty"`
+	TotalAllocation int64   `protobuf:"varint,7,opt,name=total_allocation" json:"total_allocation,omitempty"`
+	NumPendingOpens uint32  `protobuf:"varint,8,opt,name=num_pending_opens" json:"num_pending_opens,omitempty"`
+}
+
+func (m *AutopilotStatusResponse) Reset()                    { *m = AutopilotStatusResponse{} }
+func (m *AutopilotStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*AutopilotStatusResponse) ProtoMessage()               {}
+func (*AutopilotStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+func (m *AutopilotStatusResponse) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *AutopilotStatusResponse) GetMaxChannels() uint32 {
+	if m != nil {
+		return m.MaxChannels
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetAllocation() float64 {
+	if m != nil {
+		return m.Allocation
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetMinChanSize() int64 {
+	if m != nil {
+		return m.MinChanSize
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetMaxChanSize() int64 {
+	if m != nil {
+		return m.MaxChanSize
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetNumChannels() uint32 {
+	if m != nil {
+		return m.NumChannels
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetTotalAllocation() int64 {
+	if m != nil {
+		return m.TotalAllocation
+	}
+	return 0
+}
+
+func (m *AutopilotStatusResponse) GetNumPendingOpens() uint32 {
+	if m != nil {
+		return m.NumPendingOpens
+	}
+	return 0
+}
+
+type SetAutopilotRequest struct {
+	Enable bool `protobuf:"varint,1,opt,name=enable" json:"enable,omitempty"`
+}
+
+func (m *SetAutopilotRequest) Reset()                    { *m = SetAutopilotRequest{} }
+func (m *SetAutopilotRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetAutopilotRequest) ProtoMessage()               {}
+func (*SetAutopilotRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+func (m *SetAutopilotRequest) GetEnable() bool {
+	if m != nil {
+		return m.Enable
+	}
+	return false
+}
+
+type SetAutopilotResponse struct {
+}
+
+func (m *SetAutopilotResponse) Reset()                    { *m = SetAutopilotResponse{} }
+func (m *SetAutopilotResponse) String() string            { return proto.CompactTextString(m) }
+func (*SetAutopilotResponse) ProtoMessage()               {}
+func (*SetAutopilotResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
 func init() {
 	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
 	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
 	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
 	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
 	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
+	proto.RegisterType((*SendToRouteRequest)(nil), "lnrpc.SendToRouteRequest")
+	proto.RegisterType((*SendToRouteResponse)(nil), "lnrpc.SendToRouteResponse")
+	proto.RegisterType((*TrackPaymentRequest)(nil), "lnrpc.TrackPaymentRequest")
+	proto.RegisterType((*HTLCAttempt)(nil), "lnrpc.HTLCAttempt")
+	proto.RegisterType((*PaymentStatus)(nil), "lnrpc.PaymentStatus")
 	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
 	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
 	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
@@ -2354,8 +4005,13 @@ func init() {
 	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
 	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
 	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
+	proto.RegisterType((*ChannelLimits)(nil), "lnrpc.ChannelLimits")
+	proto.RegisterType((*PeerLimit)(nil), "lnrpc.PeerLimit")
+	proto.RegisterType((*PeerLimitsRequest)(nil), "lnrpc.PeerLimitsRequest")
+	proto.RegisterType((*PeerLimitsResponse)(nil), "lnrpc.PeerLimitsResponse")
 	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
 	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
+	proto.RegisterType((*HTLCBatchStats)(nil), "lnrpc.HTLCBatchStats")
 	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
 	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
 	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
@@ -2374,6 +4030,9 @@ func init() {
 	proto.RegisterType((*RouteRequest)(nil), "lnrpc.RouteRequest")
 	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
 	proto.RegisterType((*Route)(nil), "lnrpc.Route")
+	proto.RegisterType((*QueryRouteResponse)(nil), "lnrpc.QueryRouteResponse")
+	proto.RegisterType((*BuildRouteRequest)(nil), "lnrpc.BuildRouteRequest")
+	proto.RegisterType((*BuildRouteResponse)(nil), "lnrpc.BuildRouteResponse")
 	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
 	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
 	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
@@ -2384,12 +4043,22 @@ func init() {
 	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
 	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
 	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
+	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
+	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
+	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
+	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
+	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
 	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
 	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
 	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*HopHint)(nil), "lnrpc.HopHint")
+	proto.RegisterType((*RouteHint)(nil), "lnrpc.RouteHint")
 	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
 	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
 	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
+	proto.RegisterType((*CancelInvoiceResponse)(nil), "lnrpc.CancelInvoiceResponse")
+	proto.RegisterType((*SettleInvoiceRequest)(nil), "lnrpc.SettleInvoiceRequest")
+	proto.RegisterType((*SettleInvoiceResponse)(nil), "lnrpc.SettleInvoiceResponse")
 	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
 	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
 	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
@@ -2401,8 +4070,20 @@ func init() {
 	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
 	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
 	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
+	proto.RegisterType((*PairHistory)(nil), "lnrpc.PairHistory")
+	proto.RegisterType((*QueryMissionControlRequest)(nil), "lnrpc.QueryMissionControlRequest")
+	proto.RegisterType((*QueryMissionControlResponse)(nil), "lnrpc.QueryMissionControlResponse")
+	proto.RegisterType((*ResetMissionControlRequest)(nil), "lnrpc.ResetMissionControlRequest")
+	proto.RegisterType((*ResetMissionControlResponse)(nil), "lnrpc.ResetMissionControlResponse")
+	proto.RegisterType((*AutopilotStatusRequest)(nil), "lnrpc.AutopilotStatusRequest")
+	proto.RegisterType((*AutopilotStatusResponse)(nil), "lnrpc.AutopilotStatusResponse")
+	proto.RegisterType((*SetAutopilotRequest)(nil), "lnrpc.SetAutopilotRequest")
+	proto.RegisterType((*SetAutopilotResponse)(nil), "lnrpc.SetAutopilotResponse")
 	proto.RegisterEnum("lnrpc.ChannelStatus", ChannelStatus_name, ChannelStatus_value)
+	proto.RegisterEnum("lnrpc.HTLCAttempt_HTLCStatus", HTLCAttempt_HTLCStatus_name, HTLCAttempt_HTLCStatus_value)
+	proto.RegisterEnum("lnrpc.PaymentStatus_PaymentState", PaymentStatus_PaymentState_name, PaymentStatus_PaymentState_value)
 	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+	proto.RegisterEnum("lnrpc.Invoice_InvoiceState", Invoice_InvoiceState_name, Invoice_InvoiceState_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2426,6 +4107,7 @@ type LightningClient interface {
 	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
 	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
 	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	PeerLimits(ctx context.Context, in *PeerLimitsRequest, opts ...grpc.CallOption) (*PeerLimitsResponse, error)
 	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
 	// TODO(roasbeef): merge with below with bool?
 	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
@@ -2435,9 +4117,14 @@ type LightningClient interface {
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
 	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
 	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	SendToRoute(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendToRouteResponse, error)
+	TrackPayment(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Lightning_TrackPaymentClient, error)
 	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	AddHoldInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
 	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
 	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	CancelInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*CancelInvoiceResponse, error)
+	SettleInvoice(ctx context.Context, in *SettleInvoiceRequest, opts ...grpc.CallOption) (*SettleInvoiceResponse, error)
 	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
 	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
 	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
@@ -2445,8 +4132,14 @@ type LightningClient interface {
 	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
 	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
 	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
-	QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error)
+	QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*QueryRouteResponse, error)
+	BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error)
 	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error)
+	ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error)
+	AutopilotStatus(ctx context.Context, in *AutopilotStatusRequest, opts ...grpc.CallOption) (*AutopilotStatusResponse, error)
+	SetAutopilot(ctx context.Context, in *SetAutopilotRequest, opts ...grpc.CallOption) (*SetAutopilotResponse, error)
 	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
 	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
 }
@@ -2572,6 +4265,15 @@ func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, o
 	return out, nil
 }
 
+func (c *lightningClient) PeerLimits(ctx context.Context, in *PeerLimitsRequest, opts ...grpc.CallOption) (*PeerLimitsResponse, error) {
+	out := new(PeerLimitsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PeerLimits", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
 	out := new(GetInfoResponse)
 	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
@@ -2712,9 +4414,59 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 	return out, nil
 }
 
-func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+func (c *lightningClient) SendToRoute(ctx context.Context, in *SendToRouteRequest, opts ...grpc.CallOption) (*SendToRouteResponse, error) {
+	out := new(SendToRouteResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendToRoute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TrackPayment(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Lightning_TrackPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/TrackPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningTrackPaymentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_TrackPaymentClient interface {
+	Recv() (*PaymentStatus, error)
+	grpc.ClientStream
+}
+
+type lightningTrackPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningTrackPaymentClient) Recv() (*PaymentStatus, error) {
+	m := new(PaymentStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+	out := new(AddInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AddHoldInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
 	out := new(AddInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddHoldInvoice", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2739,8 +4491,26 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 	return out, nil
 }
 
+func (c *lightningClient) CancelInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*CancelInvoiceResponse, error) {
+	out := new(CancelInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/CancelInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SettleInvoice(ctx context.Context, in *SettleInvoiceRequest, opts ...grpc.CallOption) (*SettleInvoiceResponse, error) {
+	out := new(SettleInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SettleInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2825,8 +4595,8 @@ func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest,
 	return out, nil
 }
 
-func (c *lightningClient) QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error) {
-	out := new(Route)
+func (c *lightningClient) QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*QueryRouteResponse, error) {
+	out := new(QueryRouteResponse)
 	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoute", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -2834,6 +4604,15 @@ func (c *lightningClient) QueryRoute(ctx context.Context, in *RouteRequest, opts
 	return out, nil
 }
 
+func (c *lightningClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
+	out := new(BuildRouteResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BuildRoute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
 	out := new(NetworkInfo)
 	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
@@ -2843,6 +4622,74 @@ func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoReq
 	return out, nil
 }
 
+func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelGraphClient interface {
+	Recv() (*GraphTopologyUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
+	m := new(GraphTopologyUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error) {
+	out := new(QueryMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error) {
+	out := new(ResetMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ResetMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AutopilotStatus(ctx context.Context, in *AutopilotStatusRequest, opts ...grpc.CallOption) (*AutopilotStatusResponse, error) {
+	out := new(AutopilotStatusResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AutopilotStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SetAutopilot(ctx context.Context, in *SetAutopilotRequest, opts ...grpc.CallOption) (*SetAutopilotResponse, error) {
+	out := new(SetAutopilotResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAutopilot", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error) {
 	out := new(SetAliasResponse)
 	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAlias", in, out, c.cc, opts...)
@@ -2874,6 +4721,7 @@ type LightningServer interface {
 	NewWitnessAddress(context.Context, *NewWitnessAddressRequest) (*NewAddressResponse, error)
 	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
 	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
+	PeerLimits(context.Context, *PeerLimitsRequest) (*PeerLimitsResponse, error)
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
 	// TODO(roasbeef): merge with below with bool?
 	PendingChannels(context.Context, *PendingChannelRequest) (*PendingChannelResponse, error)
@@ -2883,9 +4731,14 @@ type LightningServer interface {
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
 	SendPayment(Lightning_SendPaymentServer) error
 	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
+	SendToRoute(context.Context, *SendToRouteRequest) (*SendToRouteResponse, error)
+	TrackPayment(*TrackPaymentRequest, Lightning_TrackPaymentServer) error
 	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
+	AddHoldInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
 	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
 	LookupInvoice(context.Context, *PaymentHash) (*Invoice, error)
+	CancelInvoice(context.Context, *PaymentHash) (*CancelInvoiceResponse, error)
+	SettleInvoice(context.Context, *SettleInvoiceRequest) (*SettleInvoiceResponse, error)
 	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
 	DecodePayReq(context.Context, *PayReqString) (*PayReq, error)
 	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
@@ -2893,8 +4746,14 @@ type LightningServer interface {
 	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
 	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
 	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
-	QueryRoute(context.Context, *RouteRequest) (*Route, error)
+	QueryRoute(context.Context, *RouteRequest) (*QueryRouteResponse, error)
+	BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error)
 	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
+	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
+	QueryMissionControl(context.Context, *QueryMissionControlRequest) (*QueryMissionControlResponse, error)
+	ResetMissionControl(context.Context, *ResetMissionControlRequest) (*ResetMissionControlResponse, error)
+	AutopilotStatus(context.Context, *AutopilotStatusRequest) (*AutopilotStatusResponse, error)
+	SetAutopilot(context.Context, *SetAutopilotRequest) (*SetAutopilotResponse, error)
 	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
 }
@@ -3086,6 +4945,24 @@ func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_PeerLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PeerLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PeerLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PeerLimits(ctx, req.(*PeerLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInfoRequest)
 	if err := dec(in); err != nil {
@@ -3244,6 +5121,45 @@ func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_SendToRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendToRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendToRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendToRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendToRoute(ctx, req.(*SendToRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_TrackPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TrackPaymentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).TrackPayment(m, &lightningTrackPaymentServer{stream})
+}
+
+type Lightning_TrackPaymentServer interface {
+	Send(*PaymentStatus) error
+	grpc.ServerStream
+}
+
+type lightningTrackPaymentServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningTrackPaymentServer) Send(m *PaymentStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Invoice)
 	if err := dec(in); err != nil {
@@ -3262,6 +5178,24 @@ func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_AddHoldInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddHoldInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddHoldInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddHoldInvoice(ctx, req.(*Invoice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListInvoiceRequest)
 	if err := dec(in); err != nil {
@@ -3298,6 +5232,42 @@ func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_CancelInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).CancelInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/CancelInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).CancelInvoice(ctx, req.(*PaymentHash))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SettleInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SettleInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SettleInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SettleInvoice(ctx, req.(*SettleInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(InvoiceSubscription)
 	if err := stream.RecvMsg(m); err != nil {
@@ -3445,6 +5415,24 @@ func _Lightning_QueryRoute_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BuildRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BuildRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BuildRoute(ctx, req.(*BuildRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NetworkInfoRequest)
 	if err := dec(in); err != nil {
@@ -3463,6 +5451,99 @@ func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+}
+
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_QueryMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMissionControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).QueryMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/QueryMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).QueryMissionControl(ctx, req.(*QueryMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ResetMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetMissionControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ResetMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ResetMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ResetMissionControl(ctx, req.(*ResetMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_AutopilotStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutopilotStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AutopilotStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AutopilotStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AutopilotStatus(ctx, req.(*AutopilotStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SetAutopilot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAutopilotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SetAutopilot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SetAutopilot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SetAutopilot(ctx, req.(*SetAutopilotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetAliasRequest)
 	if err := dec(in); err != nil {
@@ -3539,6 +5620,10 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListPeers",
 			Handler:    _Lightning_ListPeers_Handler,
 		},
+		{
+			MethodName: "PeerLimits",
+			Handler:    _Lightning_PeerLimits_Handler,
+		},
 		{
 			MethodName: "GetInfo",
 			Handler:    _Lightning_GetInfo_Handler,
@@ -3559,10 +5644,18 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SendPaymentSync",
 			Handler:    _Lightning_SendPaymentSync_Handler,
 		},
+		{
+			MethodName: "SendToRoute",
+			Handler:    _Lightning_SendToRoute_Handler,
+		},
 		{
 			MethodName: "AddInvoice",
 			Handler:    _Lightning_AddInvoice_Handler,
 		},
+		{
+			MethodName: "AddHoldInvoice",
+			Handler:    _Lightning_AddHoldInvoice_Handler,
+		},
 		{
 			MethodName: "ListInvoices",
 			Handler:    _Lightning_ListInvoices_Handler,
@@ -3571,6 +5664,14 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoice",
 			Handler:    _Lightning_LookupInvoice_Handler,
 		},
+		{
+			MethodName: "CancelInvoice",
+			Handler:    _Lightning_CancelInvoice_Handler,
+		},
+		{
+			MethodName: "SettleInvoice",
+			Handler:    _Lightning_SettleInvoice_Handler,
+		},
 		{
 			MethodName: "DecodePayReq",
 			Handler:    _Lightning_DecodePayReq_Handler,
@@ -3599,10 +5700,30 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryRoute",
 			Handler:    _Lightning_QueryRoute_Handler,
 		},
+		{
+			MethodName: "BuildRoute",
+			Handler:    _Lightning_BuildRoute_Handler,
+		},
 		{
 			MethodName: "GetNetworkInfo",
 			Handler:    _Lightning_GetNetworkInfo_Handler,
 		},
+		{
+			MethodName: "QueryMissionControl",
+			Handler:    _Lightning_QueryMissionControl_Handler,
+		},
+		{
+			MethodName: "ResetMissionControl",
+			Handler:    _Lightning_ResetMissionControl_Handler,
+		},
+		{
+			MethodName: "AutopilotStatus",
+			Handler:    _Lightning_AutopilotStatus_Handler,
+		},
+		{
+			MethodName: "SetAutopilot",
+			Handler:    _Lightning_SetAutopilot_Handler,
+		},
 		{
 			MethodName: "SetAlias",
 			Handler:    _Lightning_SetAlias_Handler,
@@ -3634,11 +5755,21 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "TrackPayment",
+			Handler:       _Lightning_TrackPayment_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeInvoices",
 			Handler:       _Lightning_SubscribeInvoices_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeChannelGraph",
+			Handler:       _Lightning_SubscribeChannelGraph_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
@@ -3646,203 +5777,331 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3160 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x5a, 0x4f, 0x6f, 0x1b, 0xc7,
-	0x15, 0xf7, 0x8a, 0x94, 0x44, 0x3e, 0x2e, 0x45, 0x69, 0x24, 0x51, 0x34, 0xed, 0xc4, 0xf2, 0xc6,
-	0x49, 0x15, 0xc3, 0x31, 0x6d, 0x05, 0x45, 0x83, 0x04, 0x49, 0xa1, 0xd8, 0xae, 0x65, 0x54, 0x91,
-	0x15, 0x4b, 0x8e, 0xdb, 0xa4, 0xc5, 0x66, 0xb5, 0x3b, 0xa2, 0x36, 0x5e, 0xee, 0x6e, 0x76, 0x87,
-	0x92, 0x59, 0x43, 0x97, 0x1e, 0x0a, 0xf4, 0xdc, 0x4b, 0x81, 0x02, 0x45, 0x7b, 0x2c, 0x50, 0x14,
-	0xed, 0xe7, 0xe8, 0xb1, 0xb7, 0xf6, 0x54, 0xa0, 0xc7, 0x7e, 0x88, 0x62, 0xde, 0xcc, 0xec, 0xce,
-	0x2c, 0xe9, 0xa0, 0x41, 0xd1, 0x9b, 0xf8, 0x66, 0xe6, 0xcd, 0x7b, 0x6f, 0xde, 0x9f, 0xdf, 0x7b,
-	0x2b, 0x68, 0x66, 0xa9, 0x7f, 0x3b, 0xcd, 0x12, 0x96, 0x90, 0xf9, 0x28, 0xce, 0x52, 0xbf, 0x7f,
-	0x75, 0x98, 0x24, 0xc3, 0x88, 0x0e, 0xbc, 0x34, 0x1c, 0x78, 0x71, 0x9c, 0x30, 0x8f, 0x85, 0x49,
-	0x9c, 0x8b, 0x4d, 0xce, 0x6f, 0x2c, 0x68, 0x1d, 0x65, 0x5e, 0x9c, 0x7b, 0x3e, 0x27, 0x93, 0x0e,
-	0x2c, 0xb2, 0x17, 0xee, 0xa9, 0x97, 0x9f, 0xf6, 0xac, 0x4d, 0x6b, 0xab, 0x49, 0x96, 0x60, 0xc1,
-	0x1b, 0x25, 0xe3, 0x98, 0xf5, 0xe6, 0x36, 0xad, 0x2d, 0x8b, 0x5c, 0x86, 0x95, 0x78, 0x3c, 0x72,
-	0xfd, 0x24, 0x3e, 0x09, 0xb3, 0x91, 0xe0, 0xd5, 0xab, 0x6d, 0x5a, 0x5b, 0xf3, 0x84, 0x00, 0x1c,
-	0x47, 0x89, 0xff, 0x5c, 0x1c, 0xaf, 0xe3, 0xf1, 0x35, 0xb0, 0x25, 0x8d, 0x86, 0xc3, 0x53, 0xd6,
-	0x9b, 0x57, 0x3b, 0x59, 0x38, 0xa2, 0x6e, 0xce, 0xbc, 0x51, 0xda, 0x5b, 0xd8, 0xb4, 0xb6, 0x6a,
-	0x48, 0x4b, 0x98, 0x17, 0xb9, 0x27, 0x94, 0xe6, 0xbd, 0x45, 0x4e, 0x73, 0x7a, 0xd0, 0x7d, 0x48,
-	0x99, 0x26, 0x5f, 0xfe, 0x84, 0x7e, 0x3d, 0xa6, 0x39, 0x73, 0x3e, 0x02, 0xa2, 0x91, 0xef, 0x53,
-	0xe6, 0x85, 0x51, 0x4e, 0xb6, 0xc0, 0x66, 0xda, 0xe6, 0x9e, 0xb5, 0x59, 0xdb, 0x6a, 0x6d, 0x93,
-	0xdb, 0x68, 0x89, 0xdb, 0xda, 0x01, 0xe7, 0x97, 0x16, 0xb4, 0x0e, 0x69, 0x1c, 0x48, 0x7e, 0xc4,
-	0x86, 0x7a, 0x40, 0x73, 0x86, 0x4a, 0xdb, 0x64, 0x15, 0x5a, 0xfc, 0x97, 0x9b, 0xb3, 0x2c, 0x8c,
-	0x87, 0xa8, 0x79, 0x93, 0xb4, 0xa0, 0xe6, 0x8d, 0x18, 0xea, 0x5a, 0xe3, 0x7a, 0xa5, 0xde, 0x64,
-	0x44, 0x63, 0x56, 0x6a, 0x6b, 0x93, 0x2b, 0xb0, 0xaa, 0x53, 0xd5, 0xf9, 0x79, 0x3c, 0xbf, 0x01,
-	0x1d, 0xb5, 0x98, 0x89, 0x5b, 0x51, 0xf3, 0xa6, 0xf3, 0x2e, 0xd8, 0x42, 0x94, 0x3c, 0x4d, 0xe2,
-	0x9c, 0x92, 0x37, 0xa0, 0x5d, 0x6c, 0x4c, 0xc6, 0x8c, 0xa2, 0x50, 0xad, 0x6d, 0x5b, 0xaa, 0xf1,
-	0x84, 0xd3, 0x9c, 0x23, 0xb0, 0xef, 0x9d, 0x7a, 0x71, 0x4c, 0xa3, 0x83, 0x24, 0x8c, 0x19, 0x17,
-	0xe8, 0x64, 0x1c, 0x07, 0x61, 0x3c, 0x74, 0xd9, 0x8b, 0x30, 0x90, 0x8a, 0xf4, 0x60, 0x59, 0xa7,
-	0x72, 0x81, 0xa4, 0x36, 0x6b, 0x60, 0x27, 0x63, 0x96, 0x8e, 0x99, 0x1b, 0xc6, 0x01, 0x7d, 0x81,
-	0x6a, 0xb5, 0x9d, 0x3b, 0xb0, 0xbc, 0xc7, 0xdf, 0x29, 0x0e, 0xe3, 0xe1, 0x4e, 0x10, 0x64, 0x34,
-	0xcf, 0xb9, 0x07, 0xa4, 0xe3, 0xe3, 0xe7, 0x74, 0x22, 0x3d, 0xc2, 0x86, 0xfa, 0x69, 0x92, 0x0b,
-	0x7f, 0x68, 0x3a, 0xbf, 0xb0, 0xa0, 0xc3, 0xa5, 0xff, 0xc4, 0x8b, 0x27, 0xca, 0x98, 0x1f, 0x81,
-	0xcd, 0x0f, 0x1f, 0x25, 0x3b, 0xc2, 0x73, 0xc4, 0x33, 0x6c, 0x49, 0xf9, 0x2b, 0xbb, 0x6f, 0xeb,
-	0x5b, 0x1f, 0xc4, 0x2c, 0x9b, 0xf4, 0xdf, 0x85, 0x95, 0x29, 0x22, 0x37, 0x7f, 0x29, 0x43, 0x1b,
-	0xe6, 0xcf, 0xbc, 0x68, 0x4c, 0x51, 0x88, 0xda, 0xfb, 0x73, 0xef, 0x59, 0xce, 0x26, 0x2c, 0x97,
-	0x9c, 0xa5, 0x25, 0x6d, 0xa8, 0x17, 0xc6, 0x68, 0x72, 0xe5, 0xf8, 0x8e, 0x7b, 0x49, 0x58, 0xf8,
-	0x11, 0xdf, 0xe1, 0x05, 0x41, 0x36, 0xd3, 0xd9, 0x6b, 0xce, 0x75, 0x58, 0xd1, 0x4e, 0xcc, 0x64,
-	0xfa, 0x6b, 0x0b, 0x56, 0xf6, 0xe9, 0xb9, 0x34, 0x96, 0x62, 0xbb, 0x0d, 0x75, 0x36, 0x49, 0xc5,
-	0xcb, 0x2d, 0x6d, 0xdf, 0x90, 0x9a, 0x4f, 0xed, 0xbb, 0x2d, 0x7f, 0x1e, 0x4d, 0x52, 0xea, 0x3c,
-	0x86, 0x96, 0xf6, 0x93, 0x6c, 0xc0, 0xea, 0xb3, 0x47, 0x47, 0xfb, 0x0f, 0x0e, 0x0f, 0xdd, 0x83,
-	0xa7, 0x1f, 0xff, 0xf0, 0xc1, 0x8f, 0xdd, 0xdd, 0x9d, 0xc3, 0xdd, 0xe5, 0x4b, 0xa4, 0x0b, 0x64,
-	0xff, 0xc1, 0xe1, 0xd1, 0x83, 0xfb, 0x06, 0xdd, 0x22, 0x1d, 0x68, 0xe9, 0x84, 0x39, 0xa7, 0x0f,
-	0xbd, 0x7d, 0x7a, 0xfe, 0x2c, 0x64, 0x31, 0xcd, 0x73, 0xf3, 0x62, 0xe7, 0x4d, 0x20, 0xba, 0x34,
-	0x52, 0xb5, 0x0e, 0x2c, 0x7a, 0x82, 0x24, 0xb5, 0x7b, 0x04, 0xe4, 0x5e, 0x12, 0xc7, 0xd4, 0x67,
-	0x07, 0x94, 0x66, 0x4a, 0xbb, 0x37, 0x35, 0xa3, 0xb5, 0xb6, 0x37, 0xa4, 0x76, 0x53, 0x8e, 0x63,
-	0x43, 0x3d, 0xa5, 0xd9, 0x08, 0x6d, 0xd9, 0x70, 0xde, 0x82, 0x55, 0x83, 0x55, 0x79, 0x65, 0x4a,
-	0x69, 0xe6, 0x4a, 0x83, 0xce, 0x3b, 0x29, 0xd4, 0x77, 0x8f, 0xf6, 0xee, 0x91, 0x65, 0x68, 0x84,
-	0xb1, 0x9f, 0x8c, 0x78, 0x00, 0xf1, 0x95, 0x46, 0xf5, 0x75, 0xc8, 0x0a, 0x34, 0x31, 0xca, 0x78,
-	0x7e, 0x41, 0xff, 0xb5, 0x79, 0x76, 0xa2, 0x2f, 0xd2, 0x30, 0xc3, 0xbc, 0xa4, 0x72, 0x0e, 0x8f,
-	0xcd, 0x36, 0x0f, 0x85, 0x8c, 0x9e, 0x25, 0xbe, 0x58, 0x0a, 0x68, 0xe4, 0x4d, 0x30, 0x30, 0xdb,
-	0xce, 0xef, 0xe7, 0xa0, 0xbd, 0xe3, 0xb3, 0xf0, 0x8c, 0xca, 0x88, 0x22, 0xeb, 0xd0, 0xce, 0xe8,
-	0x28, 0x61, 0xd4, 0x35, 0x3c, 0x7f, 0x1d, 0xda, 0xbe, 0xd8, 0xe1, 0xa6, 0x3c, 0xe8, 0x64, 0x28,
-	0x75, 0x60, 0x91, 0x93, 0xb9, 0x0a, 0x5c, 0x8a, 0x3a, 0x17, 0xdd, 0xf7, 0x52, 0xcf, 0x0f, 0xd9,
-	0x04, 0x2f, 0xaf, 0xf1, 0x93, 0x51, 0xe2, 0x7b, 0x91, 0x7b, 0xec, 0x45, 0x5e, 0xec, 0x53, 0xbc,
-	0xb9, 0x46, 0xba, 0xb0, 0x24, 0xef, 0x51, 0x74, 0x91, 0x0b, 0x2f, 0xc3, 0xca, 0x38, 0xce, 0x29,
-	0x63, 0x11, 0x0d, 0x8a, 0x25, 0x4c, 0x89, 0x3c, 0xc5, 0x88, 0x34, 0x99, 0x7b, 0x2c, 0xc9, 0x4f,
-	0xc3, 0xdc, 0xcd, 0x69, 0xcc, 0x7a, 0x0d, 0x5c, 0xbc, 0x06, 0x1b, 0x95, 0xc5, 0x8c, 0xfa, 0x34,
-	0x3c, 0xa3, 0x41, 0xaf, 0x89, 0x1b, 0x56, 0xa1, 0xc5, 0xb3, 0xf7, 0x38, 0x0d, 0x3c, 0x46, 0xf3,
-	0x1e, 0xa0, 0xb8, 0x0e, 0xb4, 0x53, 0x2a, 0x92, 0xc4, 0x29, 0x8b, 0xfc, 0xbc, 0xd7, 0xc2, 0x78,
-	0x6d, 0xc9, 0x77, 0xe5, 0xaf, 0xe1, 0xac, 0xc3, 0xea, 0x5e, 0x98, 0x33, 0x69, 0x20, 0x2d, 0x0d,
-	0xaf, 0x99, 0x64, 0xf9, 0xaa, 0x6f, 0x41, 0x43, 0x5a, 0x4a, 0x71, 0x5b, 0x93, 0xdc, 0x0c, 0x43,
-	0x3b, 0x7f, 0xb0, 0xa0, 0xce, 0xdd, 0x01, 0xdd, 0x60, 0x7c, 0xec, 0x96, 0xb6, 0xd6, 0xfc, 0x62,
-	0x0e, 0x6b, 0x86, 0xe6, 0x9b, 0x35, 0xdc, 0xc1, 0xcb, 0xcd, 0x84, 0x51, 0x69, 0x80, 0x3a, 0xaa,
-	0x52, 0xd0, 0x32, 0xea, 0x9f, 0xa1, 0x91, 0xf1, 0x35, 0x72, 0x8f, 0x89, 0x5d, 0xc2, 0xbc, 0x92,
-	0x82, 0x7b, 0x84, 0x55, 0x3b, 0xb0, 0x18, 0xc6, 0xc7, 0xc9, 0x38, 0x0e, 0xd0, 0x92, 0x0d, 0xee,
-	0x5b, 0x29, 0x66, 0xcd, 0x70, 0x44, 0x85, 0xed, 0x1c, 0xc2, 0x73, 0x63, 0x8e, 0xde, 0x5b, 0xe8,
-	0x3f, 0x80, 0x15, 0x8d, 0x26, 0x95, 0xef, 0xc3, 0x3c, 0x17, 0x5d, 0x95, 0x1f, 0x65, 0x47, 0xbe,
-	0xc9, 0x59, 0x86, 0xa5, 0x87, 0x94, 0x3d, 0x8a, 0x4f, 0x12, 0xc5, 0xe2, 0x1f, 0x16, 0x74, 0x0a,
-	0x92, 0xe4, 0xb0, 0x01, 0x9d, 0x30, 0xa0, 0x31, 0x0b, 0xd9, 0xc4, 0xf4, 0xc0, 0x36, 0xcc, 0x7b,
-	0x51, 0xe8, 0xe5, 0xd2, 0xf3, 0xae, 0xc2, 0x1a, 0x7f, 0x4e, 0xf5, 0x7a, 0x85, 0xc9, 0x31, 0x99,
-	0x73, 0x57, 0xe1, 0xab, 0x1e, 0x5a, 0xbc, 0x5c, 0x14, 0xe1, 0xb0, 0x02, 0x4d, 0x71, 0x94, 0x0b,
-	0x8a, 0x71, 0x30, 0x55, 0xab, 0x17, 0x90, 0x6a, 0x56, 0xf5, 0x86, 0x2a, 0x65, 0xf9, 0x24, 0xf6,
-	0x69, 0xe0, 0xb2, 0x84, 0x33, 0x0e, 0x63, 0xb4, 0x51, 0x03, 0xe1, 0x03, 0xcd, 0x59, 0x4c, 0x19,
-	0xfa, 0x56, 0xc3, 0x79, 0x8a, 0x09, 0xa4, 0x80, 0x0a, 0x4f, 0xd1, 0xf1, 0xf8, 0xe5, 0x82, 0x67,
-	0x7e, 0xea, 0xc9, 0x4a, 0x55, 0xbd, 0x5c, 0x3c, 0x7a, 0x17, 0x96, 0x14, 0xda, 0xc8, 0xdd, 0x88,
-	0x9e, 0x30, 0x59, 0xa7, 0xbe, 0x0f, 0x2b, 0xd2, 0x85, 0x1e, 0xa7, 0x54, 0x71, 0xbd, 0x59, 0x0d,
-	0x4f, 0x91, 0x9f, 0x56, 0xa5, 0xfd, 0xf5, 0x72, 0xe9, 0x7c, 0x00, 0x44, 0xfe, 0xbe, 0x17, 0x25,
-	0x39, 0x95, 0x1c, 0xd6, 0xc0, 0xf6, 0xa3, 0x24, 0xaf, 0x14, 0xd1, 0x0e, 0x2c, 0xe6, 0x63, 0xdf,
-	0xe7, 0x9e, 0x27, 0x52, 0x59, 0x00, 0xab, 0x78, 0x4a, 0x72, 0x50, 0x69, 0xf1, 0x5b, 0xdc, 0x5f,
-	0x20, 0xa0, 0x28, 0x1c, 0x85, 0x2a, 0x9f, 0xb5, 0x61, 0xfe, 0x24, 0xc9, 0x7c, 0x8a, 0x3a, 0x36,
-	0x9c, 0x3f, 0x5b, 0xb0, 0x82, 0xd7, 0x1c, 0x32, 0x8f, 0x8d, 0x73, 0x29, 0xe2, 0x3b, 0xd0, 0xe6,
-	0x22, 0x52, 0xf5, 0xe8, 0xf2, 0x92, 0xb5, 0xc2, 0xc9, 0x90, 0x2a, 0x36, 0xef, 0x5e, 0x22, 0x77,
-	0xc1, 0xd6, 0xa1, 0x1a, 0xde, 0xd4, 0xda, 0xbe, 0xac, 0x44, 0x9a, 0x7a, 0x9a, 0xdd, 0x4b, 0x64,
-	0x00, 0x80, 0xe9, 0x0c, 0xaf, 0x41, 0x59, 0xb4, 0x03, 0x53, 0x36, 0xdb, 0xbd, 0xf4, 0x71, 0x03,
-	0x16, 0x44, 0x42, 0x71, 0x5e, 0x83, 0xb6, 0x21, 0x80, 0x51, 0x2b, 0x6d, 0xe7, 0x77, 0x16, 0x10,
-	0xfe, 0x5e, 0x15, 0xbb, 0x75, 0x61, 0x89, 0x79, 0xd9, 0x90, 0x32, 0xd7, 0xa8, 0x04, 0x98, 0xac,
-	0x92, 0xa0, 0xc8, 0xc1, 0x73, 0xf8, 0x18, 0x7d, 0x20, 0x1a, 0x51, 0x21, 0xac, 0x9a, 0x0a, 0x07,
-	0x91, 0x65, 0x15, 0xe6, 0x91, 0xe5, 0xa2, 0xae, 0xa2, 0x3e, 0x1d, 0x73, 0x50, 0xe6, 0x31, 0x99,
-	0x7e, 0x65, 0x0c, 0xa0, 0x77, 0x09, 0x6f, 0x77, 0xfe, 0x68, 0xc1, 0x32, 0x17, 0xd1, 0xb0, 0xf9,
-	0x2d, 0xb0, 0xd1, 0x22, 0xff, 0x37, 0x93, 0xbf, 0x03, 0x4d, 0xbc, 0x20, 0x49, 0x69, 0x2c, 0x2d,
-	0xde, 0x33, 0x2d, 0x5e, 0xba, 0xb9, 0x61, 0xf0, 0x0f, 0x61, 0x5d, 0x5e, 0x5f, 0xb1, 0xe9, 0x0d,
-	0x58, 0xc8, 0x51, 0x05, 0x09, 0x41, 0xd6, 0x4c, 0x76, 0x42, 0x3d, 0xe7, 0x4f, 0x73, 0xd0, 0xad,
-	0x9e, 0x97, 0x29, 0xe8, 0x07, 0xb0, 0x3c, 0x95, 0x56, 0x44, 0x3e, 0xbb, 0x65, 0xea, 0x5d, 0x39,
-	0x58, 0x21, 0xf7, 0xff, 0x6a, 0xc1, 0x92, 0x49, 0x9a, 0x2a, 0xf9, 0x3c, 0xec, 0x8a, 0x74, 0xa7,
-	0x5e, 0x7a, 0x46, 0xb5, 0x15, 0x8f, 0xfc, 0x3f, 0x17, 0xd7, 0x6a, 0x90, 0x2f, 0x22, 0xdb, 0xd2,
-	0x60, 0x8d, 0x6f, 0x30, 0xd8, 0x2d, 0x58, 0x7b, 0xe6, 0x45, 0x11, 0x65, 0x1f, 0x0b, 0x96, 0xca,
-	0xdc, 0x6b, 0x60, 0x9f, 0x0b, 0x9c, 0xe5, 0x26, 0x71, 0x24, 0xb2, 0x75, 0xc3, 0xd9, 0x82, 0xf5,
-	0xca, 0xee, 0x12, 0xf4, 0x28, 0x99, 0xf8, 0x4e, 0xcb, 0xd9, 0x80, 0x75, 0x79, 0x91, 0xc9, 0xd8,
-	0x79, 0x1b, 0xba, 0xd5, 0x85, 0xd9, 0x3c, 0x6a, 0xce, 0x2d, 0xb0, 0xb1, 0x35, 0x50, 0x32, 0x4d,
-	0x95, 0x54, 0xd9, 0xc0, 0x08, 0x68, 0xfb, 0x04, 0x6a, 0xbb, 0x49, 0xaa, 0x63, 0x17, 0x0b, 0xab,
-	0xa5, 0xb4, 0xba, 0x5b, 0xd8, 0x78, 0x4e, 0x19, 0xd3, 0x1b, 0x31, 0x9e, 0xee, 0x4f, 0x92, 0xec,
-	0xdc, 0xcb, 0x02, 0xd9, 0x07, 0xb5, 0xa0, 0x76, 0x42, 0xa9, 0x78, 0x08, 0xc7, 0x83, 0x79, 0x94,
-	0x80, 0xd7, 0x07, 0x81, 0x43, 0x44, 0x8e, 0xe3, 0xf8, 0xcc, 0x52, 0xc5, 0x44, 0x6b, 0xf2, 0x0a,
-	0x18, 0x27, 0x68, 0x65, 0x77, 0xd5, 0xe3, 0x2d, 0x46, 0xca, 0x4b, 0x15, 0x77, 0x38, 0x50, 0x40,
-	0x24, 0x49, 0x1d, 0x07, 0x3a, 0xfb, 0x49, 0x40, 0xb5, 0x02, 0x3a, 0xa5, 0xa7, 0xf3, 0x13, 0x68,
-	0xa8, 0x3d, 0xc4, 0x81, 0x3a, 0x4f, 0x17, 0x95, 0x90, 0x2d, 0xa0, 0x2a, 0xdf, 0xc7, 0x1f, 0x0f,
-	0xd3, 0x80, 0x72, 0xf3, 0x39, 0x14, 0x95, 0x67, 0x25, 0x14, 0xab, 0xb0, 0x04, 0xca, 0xe6, 0x3c,
-	0x85, 0xb6, 0x79, 0x7c, 0x15, 0x5a, 0x91, 0x97, 0x33, 0x09, 0xaa, 0xa4, 0xa2, 0x9a, 0x50, 0x05,
-	0x48, 0x34, 0xe1, 0x4b, 0x51, 0xca, 0xb1, 0x51, 0x76, 0x62, 0x68, 0x73, 0xdb, 0x85, 0xf1, 0xf0,
-	0x20, 0x89, 0x42, 0x7f, 0x82, 0x36, 0x54, 0xd6, 0xe3, 0x70, 0x95, 0x79, 0x92, 0xf5, 0x32, 0x34,
-	0x46, 0x61, 0x8c, 0x50, 0x4d, 0x5a, 0x70, 0x1d, 0xda, 0x27, 0x94, 0xbb, 0x79, 0x4e, 0xdd, 0x11,
-	0x4f, 0x6f, 0x35, 0x05, 0x15, 0x39, 0x39, 0xf3, 0x18, 0x75, 0x47, 0x61, 0x14, 0x85, 0x62, 0x51,
-	0xbc, 0xd5, 0xdf, 0x2d, 0x68, 0x49, 0xcf, 0x7a, 0x10, 0x0c, 0x29, 0x7f, 0x19, 0x15, 0x6d, 0x85,
-	0x2f, 0x48, 0x9a, 0x01, 0x76, 0x2b, 0xda, 0xd6, 0x0a, 0x30, 0x91, 0x04, 0xf4, 0x2e, 0xcf, 0xca,
-	0xb2, 0xf1, 0x97, 0xa4, 0x6d, 0x24, 0xcd, 0x4f, 0x45, 0xae, 0x08, 0xc5, 0x9b, 0x60, 0xcb, 0x73,
-	0xa8, 0x33, 0x86, 0x62, 0xf9, 0x4a, 0xa6, 0x3d, 0xe4, 0xde, 0x6d, 0xb5, 0xb7, 0xf1, 0xea, 0xbd,
-	0x1c, 0xad, 0x4a, 0xdd, 0x1e, 0x66, 0x5e, 0x7a, 0xaa, 0x82, 0xe9, 0xb3, 0xa2, 0x67, 0x46, 0x32,
-	0x79, 0x03, 0xe6, 0x39, 0x4b, 0x95, 0xd8, 0x66, 0x7b, 0xc7, 0x75, 0x98, 0xa7, 0xc1, 0x10, 0xbd,
-	0x55, 0x1f, 0x26, 0x68, 0xb6, 0xe3, 0x4e, 0xc9, 0x7f, 0x56, 0x9c, 0xd2, 0x88, 0x2b, 0x67, 0x8d,
-	0x37, 0x5c, 0xec, 0x3c, 0xc9, 0x9e, 0xeb, 0xe0, 0xef, 0xdf, 0x16, 0xb4, 0x34, 0x32, 0x77, 0xba,
-	0x21, 0x17, 0xcd, 0x0d, 0x42, 0x6f, 0x44, 0x19, 0xcd, 0xe4, 0x9b, 0xf3, 0xf0, 0x3b, 0x1b, 0xba,
-	0xc9, 0x98, 0xb9, 0x01, 0x1d, 0x66, 0x94, 0xca, 0x69, 0x4c, 0x17, 0x96, 0x46, 0xde, 0x0b, 0x9d,
-	0x5e, 0xd3, 0xd1, 0x9d, 0xd0, 0xae, 0xae, 0xd0, 0x9d, 0xe1, 0xe5, 0x02, 0xf3, 0xbd, 0x0e, 0x5d,
-	0xe1, 0xe5, 0xb1, 0x90, 0xc2, 0xad, 0xbc, 0x50, 0x0f, 0x96, 0xf9, 0xc5, 0xca, 0x35, 0xf2, 0xf0,
-	0x67, 0xa2, 0x11, 0xb1, 0xf8, 0x0a, 0x77, 0x43, 0x63, 0xa5, 0xa1, 0xce, 0x70, 0xa1, 0x8c, 0x15,
-	0x01, 0xa1, 0x6f, 0x40, 0xe7, 0x90, 0xb2, 0x1d, 0xee, 0xf6, 0xca, 0x50, 0x5c, 0x52, 0x7a, 0xee,
-	0x8a, 0x50, 0x10, 0xf1, 0x4b, 0x78, 0x9f, 0xae, 0x76, 0x89, 0x6c, 0xe7, 0xfc, 0xc5, 0x82, 0xc5,
-	0x47, 0xf1, 0x59, 0x12, 0xfa, 0x08, 0x2a, 0x46, 0x74, 0x94, 0x94, 0x8d, 0x02, 0x36, 0x39, 0x29,
-	0x93, 0x08, 0x81, 0x00, 0x64, 0x6e, 0x9a, 0xd1, 0x70, 0xe4, 0x0d, 0xa9, 0xec, 0x0b, 0x97, 0x60,
-	0x21, 0xd3, 0x07, 0x35, 0xc5, 0xfc, 0x60, 0x5e, 0xc1, 0x7f, 0xd9, 0x6d, 0xa1, 0xda, 0x0d, 0xcc,
-	0x82, 0x19, 0x95, 0xad, 0x22, 0xf7, 0xf3, 0x45, 0xd5, 0x3e, 0x89, 0x7d, 0x82, 0x28, 0xd4, 0x9d,
-	0x31, 0xd7, 0x69, 0xa2, 0x1e, 0x1f, 0x02, 0xd9, 0x09, 0x02, 0x29, 0x75, 0x91, 0xb7, 0x4b, 0x51,
-	0x04, 0xba, 0x9c, 0x71, 0x5c, 0x4c, 0x56, 0xee, 0x42, 0xeb, 0x40, 0x2c, 0xec, 0x7a, 0xf9, 0xa9,
-	0x50, 0x4b, 0x4d, 0x95, 0xca, 0x79, 0x85, 0xe4, 0x85, 0xaa, 0x3b, 0x37, 0x81, 0xf0, 0x76, 0xa4,
-	0xb8, 0xb2, 0x28, 0x4e, 0xaa, 0x94, 0x6b, 0xc5, 0xe9, 0x7b, 0xa2, 0xa3, 0xab, 0x8a, 0xb7, 0xc9,
-	0xdb, 0x6e, 0x24, 0xa9, 0xb0, 0x58, 0x92, 0x1e, 0x2f, 0x77, 0xf2, 0xe0, 0x92, 0x7f, 0x1e, 0x8e,
-	0x8f, 0x73, 0x3f, 0x0b, 0x53, 0x9c, 0xa8, 0x7d, 0x09, 0x8b, 0x52, 0xdc, 0xa9, 0xe1, 0xd8, 0xac,
-	0x99, 0xcd, 0xb4, 0x89, 0x45, 0xd2, 0xb2, 0xa1, 0x9e, 0x7a, 0xec, 0x14, 0x53, 0x7f, 0x53, 0x95,
-	0x17, 0x7c, 0x25, 0xd5, 0x83, 0xca, 0x5b, 0x8a, 0x1e, 0xec, 0x3d, 0xd1, 0x83, 0x96, 0xe4, 0x52,
-	0x13, 0x29, 0x45, 0x55, 0x13, 0xb9, 0xd5, 0xe9, 0x43, 0xef, 0x3e, 0x8d, 0x28, 0xa3, 0x3b, 0x51,
-	0x54, 0xe5, 0x7a, 0x05, 0x2e, 0xcf, 0x58, 0x93, 0xde, 0xf8, 0x5d, 0x58, 0xb9, 0x4f, 0x8f, 0xc7,
-	0xc3, 0x3d, 0x7a, 0x56, 0x42, 0x2e, 0x1b, 0xea, 0xf9, 0x69, 0x72, 0x2e, 0x87, 0x15, 0x04, 0x20,
-	0xe2, 0xab, 0x6e, 0x9e, 0x52, 0x5f, 0xbe, 0xe8, 0xdb, 0x40, 0xf4, 0x63, 0x52, 0x4e, 0xee, 0x54,
-	0xe3, 0x63, 0x37, 0x9f, 0xe4, 0x8c, 0x8e, 0x54, 0x0c, 0x5c, 0x03, 0xfb, 0xc0, 0x9b, 0x3c, 0xa1,
-	0x5f, 0x1f, 0x22, 0xc0, 0xc5, 0x7a, 0xe2, 0x4d, 0xb8, 0x87, 0x14, 0x93, 0x99, 0x05, 0xb1, 0x41,
-	0x0d, 0x2b, 0xc3, 0x58, 0xc0, 0x4d, 0x4b, 0x8d, 0xf7, 0x8c, 0x27, 0x28, 0x86, 0x7e, 0x3c, 0x07,
-	0xa8, 0xe9, 0x80, 0x30, 0xf9, 0xcd, 0x6d, 0x68, 0x1b, 0x28, 0x87, 0x2c, 0x42, 0x6d, 0x67, 0x6f,
-	0x6f, 0xf9, 0x12, 0x69, 0xc1, 0xe2, 0xe3, 0x83, 0x07, 0xfb, 0x8f, 0xf6, 0x1f, 0x2e, 0x5b, 0xfc,
-	0xc7, 0xbd, 0xbd, 0xc7, 0x87, 0xfc, 0xc7, 0xdc, 0xf6, 0x3f, 0xbb, 0xd0, 0x2c, 0xf2, 0x24, 0xf9,
-	0x0a, 0xda, 0x06, 0xd0, 0x21, 0x57, 0xa4, 0xa5, 0x67, 0x81, 0xa5, 0xfe, 0xd5, 0xd9, 0x8b, 0xd2,
-	0xb6, 0xaf, 0xff, 0xfc, 0x6f, 0xff, 0xfa, 0xd5, 0x5c, 0x8f, 0x74, 0x07, 0x67, 0x77, 0x07, 0x12,
-	0xe1, 0x0c, 0xb0, 0x35, 0xc4, 0x46, 0x93, 0x3c, 0x87, 0x25, 0x13, 0x11, 0x91, 0xab, 0x66, 0x4a,
-	0xae, 0xdc, 0xf6, 0xda, 0x2b, 0x56, 0xe5, 0x75, 0x57, 0xf1, 0xba, 0x2e, 0x59, 0xd3, 0xaf, 0x53,
-	0x49, 0x92, 0x50, 0xec, 0xcd, 0xf5, 0x01, 0x34, 0x51, 0xfc, 0x66, 0x0f, 0xa6, 0xfb, 0x97, 0xa7,
-	0x87, 0xcd, 0x72, 0x3a, 0xed, 0xf4, 0xf0, 0x2a, 0x42, 0x96, 0xf9, 0x55, 0xfa, 0x9c, 0x9a, 0x7c,
-	0x01, 0xcd, 0x62, 0xce, 0x48, 0x36, 0xb4, 0x39, 0xa9, 0x3e, 0xab, 0xec, 0xf7, 0xa6, 0x17, 0xa4,
-	0x12, 0x57, 0x90, 0xf3, 0xba, 0x33, 0xc5, 0xf9, 0x7d, 0xeb, 0x26, 0xd9, 0x83, 0x75, 0x19, 0xa8,
-	0xc7, 0xf4, 0xdb, 0x68, 0x32, 0x63, 0x6c, 0x7e, 0xc7, 0x22, 0x1f, 0x40, 0x43, 0x8d, 0x59, 0x49,
-	0x77, 0xf6, 0x44, 0xb7, 0xbf, 0x31, 0x45, 0x97, 0xae, 0xbe, 0x03, 0x50, 0x4e, 0x1d, 0x49, 0xef,
-	0x55, 0x63, 0xd1, 0xc2, 0x88, 0x33, 0x46, 0x94, 0x43, 0x1c, 0xb7, 0x9a, 0x43, 0x4d, 0x72, 0xad,
-	0xdc, 0x3f, 0x73, 0xdc, 0xf9, 0x0d, 0x0c, 0x9d, 0x2e, 0xda, 0x6e, 0x99, 0x2c, 0x71, 0xdb, 0xc5,
-	0xf4, 0x5c, 0xa2, 0x34, 0xf2, 0x39, 0xb4, 0xb4, 0x79, 0x25, 0xd1, 0xfa, 0xb7, 0xca, 0x38, 0xb4,
-	0xdf, 0x9f, 0xb5, 0x24, 0xb9, 0xaf, 0x21, 0xf7, 0x25, 0xa7, 0xc9, 0xb9, 0xe3, 0xb0, 0x85, 0x3f,
-	0xc9, 0xa7, 0x3c, 0x78, 0xe4, 0xd8, 0x88, 0x94, 0xf3, 0x53, 0x73, 0xb8, 0x54, 0xbc, 0xf7, 0xd4,
-	0x84, 0xc9, 0x59, 0x41, 0xae, 0x2d, 0x52, 0x72, 0x25, 0x9f, 0xc0, 0xa2, 0x9c, 0x22, 0x91, 0xf5,
-	0xf2, 0x5d, 0x35, 0xac, 0xd1, 0xef, 0x56, 0xc9, 0x92, 0xd9, 0x2a, 0x32, 0x6b, 0x93, 0x16, 0x67,
-	0x36, 0xa4, 0x2c, 0xe4, 0x3c, 0x22, 0xe8, 0x98, 0x5d, 0x5b, 0x5e, 0x84, 0xd9, 0xcc, 0x86, 0xb3,
-	0x08, 0xb3, 0xd9, 0x5d, 0xa1, 0x19, 0x66, 0x2a, 0xbc, 0x06, 0xb2, 0x2c, 0x91, 0x9f, 0x82, 0xad,
-	0x8f, 0x11, 0x49, 0x5f, 0xd3, 0xbc, 0x32, 0x72, 0xec, 0x5f, 0x99, 0xb9, 0x66, 0x9a, 0x9b, 0xd8,
-	0xfa, 0x35, 0xe4, 0x73, 0xe8, 0x68, 0x63, 0x87, 0xc3, 0x49, 0xec, 0x17, 0xcf, 0x39, 0x3d, 0x8e,
-	0xe8, 0xcf, 0x9c, 0x17, 0x6d, 0x20, 0xe3, 0x15, 0xc7, 0x60, 0xcc, 0x9f, 0xf2, 0x1e, 0xb4, 0x34,
-	0x1e, 0xdf, 0xc4, 0x77, 0x43, 0x5b, 0xd2, 0xc7, 0x0b, 0x77, 0x2c, 0xf2, 0x5b, 0x0b, 0x6c, 0x7d,
-	0xa2, 0x54, 0x18, 0x60, 0xc6, 0x98, 0xa9, 0x70, 0x8b, 0xa9, 0xd9, 0x90, 0xf3, 0x19, 0x0a, 0x79,
-	0x70, 0x73, 0xdf, 0x30, 0xf2, 0x4b, 0xa3, 0x8b, 0xbe, 0xad, 0x7f, 0x0f, 0xba, 0xa8, 0x2e, 0xea,
-	0x9f, 0x84, 0x2e, 0x06, 0x2f, 0x71, 0x1c, 0x75, 0x71, 0xc7, 0x22, 0xef, 0x8b, 0xaf, 0x65, 0xaa,
-	0xc0, 0x13, 0x2d, 0xc0, 0xab, 0x66, 0xd3, 0x3f, 0x65, 0x6d, 0x59, 0x77, 0x2c, 0xf2, 0xa5, 0xf8,
-	0x40, 0x24, 0xcf, 0xa2, 0xf5, 0xff, 0xdb, 0xf3, 0xce, 0x0d, 0xd4, 0xe8, 0x75, 0xe7, 0xb2, 0xa1,
-	0x51, 0x35, 0xc3, 0x1d, 0x00, 0x94, 0x40, 0x8b, 0x54, 0xf0, 0x4a, 0x11, 0xfb, 0xd3, 0x58, 0xcc,
-	0x7c, 0x55, 0x05, 0x7b, 0x38, 0xc7, 0xaf, 0x84, 0x43, 0xca, 0xfd, 0x79, 0xf1, 0xac, 0xd3, 0xe8,
-	0xaa, 0xdf, 0x9f, 0xb5, 0x24, 0xf9, 0xbf, 0x81, 0xfc, 0x5f, 0x23, 0x57, 0x74, 0xfe, 0x83, 0x97,
-	0x3a, 0x1a, 0xbb, 0x20, 0x9f, 0x41, 0x7b, 0x2f, 0x49, 0x9e, 0x8f, 0x53, 0xa5, 0x00, 0x31, 0x61,
-	0x0a, 0x47, 0x7f, 0xfd, 0x2a, 0x08, 0xbb, 0x8e, 0x9c, 0xaf, 0x90, 0xcb, 0x26, 0xe7, 0x12, 0x21,
-	0x5e, 0x10, 0x0f, 0x56, 0x8a, 0xbc, 0x5f, 0x28, 0xd2, 0x37, 0xf9, 0xe8, 0x08, 0x6e, 0xea, 0x0e,
-	0xa3, 0x12, 0x17, 0x77, 0xe4, 0x8a, 0xe7, 0x1d, 0x8b, 0x1c, 0x80, 0x7d, 0x9f, 0xfa, 0x49, 0x40,
-	0x15, 0x14, 0x29, 0x25, 0x2f, 0xa0, 0x4b, 0xbf, 0x6d, 0x10, 0xcd, 0x4c, 0x90, 0x7a, 0x93, 0x8c,
-	0x7e, 0x3d, 0x78, 0x29, 0xb1, 0xcd, 0x85, 0xca, 0x04, 0x0a, 0x71, 0x19, 0x99, 0xa0, 0x02, 0xd1,
-	0x8c, 0x4c, 0x30, 0x05, 0xd1, 0x8c, 0x4c, 0xa0, 0x70, 0x20, 0x89, 0x38, 0x70, 0xab, 0xa0, 0xba,
-	0xa2, 0x7a, 0xbc, 0x0a, 0x0b, 0xf6, 0x37, 0x5f, 0xbd, 0xc1, 0xbc, 0xed, 0xa6, 0x79, 0xdb, 0x21,
-	0xb4, 0xef, 0x53, 0x61, 0x2c, 0xd1, 0x70, 0xf6, 0xcd, 0xd4, 0xa2, 0x37, 0xa7, 0xd5, 0xb4, 0x83,
-	0x6b, 0x66, 0xa2, 0xc7, 0xce, 0x90, 0x7c, 0x01, 0xad, 0x87, 0x94, 0xa9, 0x7e, 0xb3, 0xa8, 0xc1,
-	0x95, 0x06, 0xb4, 0x3f, 0xab, 0x4f, 0xdd, 0x44, 0x6e, 0x7d, 0xd2, 0x2b, 0xb8, 0x0d, 0x78, 0x6b,
-	0x2b, 0x92, 0x80, 0x1b, 0x06, 0x17, 0xe4, 0x47, 0xc8, 0xbc, 0x98, 0x9e, 0x28, 0xe6, 0x95, 0x91,
-	0x4b, 0xbf, 0x53, 0xa1, 0xcf, 0xe2, 0xcc, 0x7b, 0xcf, 0xc1, 0x4b, 0x39, 0x04, 0xe1, 0x9c, 0xe1,
-	0xd3, 0x31, 0xcd, 0x26, 0x62, 0x40, 0xb4, 0xaa, 0x7f, 0xcb, 0x56, 0x5c, 0xcd, 0x0f, 0xdc, 0xdf,
-	0x41, 0x96, 0xd7, 0xc9, 0xb5, 0x92, 0x25, 0x7e, 0x0d, 0x2f, 0x79, 0x0e, 0x5e, 0x7a, 0x23, 0x76,
-	0x41, 0x9e, 0xe1, 0x27, 0x15, 0xbd, 0x8b, 0x2e, 0xab, 0x7d, 0xb5, 0xe1, 0x2e, 0xcc, 0xa2, 0x2d,
-	0x99, 0x08, 0x40, 0xdc, 0x84, 0x35, 0x10, 0xa1, 0x8e, 0xe8, 0x43, 0x35, 0xa8, 0x63, 0xb4, 0xaf,
-	0x1a, 0xd4, 0x31, 0x1b, 0x56, 0x0e, 0x75, 0x4a, 0xac, 0x5f, 0x40, 0x9d, 0xa9, 0xae, 0xa1, 0xc8,
-	0x4e, 0xd3, 0x8d, 0xc1, 0xf1, 0x02, 0xfe, 0x8b, 0xc6, 0xbb, 0xff, 0x09, 0x00, 0x00, 0xff, 0xff,
-	0xd5, 0x82, 0x76, 0x97, 0xd4, 0x21, 0x00, 0x00,
+	// 5201 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x3b, 0xcd, 0x6f, 0x1d, 0x49,
+	0x5e, 0xe9, 0xf7, 0x61, 0xbf, 0xf7, 0x7b, 0x1f, 0x7e, 0xaf, 0xfc, 0xd5, 0x6e, 0x27, 0x13, 0xa7,
+	0x27, 0xb3, 0xeb, 0x35, 0xb3, 0x71, 0xe2, 0xdd, 0x95, 0x96, 0x59, 0xed, 0x82, 0xc7, 0xf1, 0xc4,
+	0xd6, 0x7a, 0x3c, 0xd9, 0xd8, 0x99, 0xec, 0xce, 0x2c, 0x6a, 0xda, 0xdd, 0xe5, 0xe7, 0x9e, 0xf4,
+	0xeb, 0xee, 0xe9, 0xae, 0xe7, 0xc4, 0x84, 0x48, 0xab, 0x3d, 0x70, 0x81, 0x13, 0x5c, 0x90, 0x90,
+	0x90, 0x80, 0x13, 0x02, 0x21, 0x38, 0xf2, 0x37, 0xc0, 0x05, 0x71, 0x41, 0x1c, 0x10, 0x07, 0x24,
+	0x2e, 0x20, 0xae, 0x1c, 0x51, 0x7d, 0x75, 0x57, 0x75, 0xb7, 0x0d, 0x3b, 0x2b, 0x4e, 0xf1, 0xab,
+	0xaa, 0xfe, 0xfd, 0xea, 0xf7, 0xfd, 0x55, 0x81, 0x6e, 0x9a, 0x78, 0x0f, 0x92, 0x34, 0x26, 0x31,
+	0x6a, 0x87, 0x51, 0x9a, 0x78, 0xd6, 0xed, 0x49, 0x1c, 0x4f, 0x42, 0xbc, 0xed, 0x26, 0xc1, 0xb6,
+	0x1b, 0x45, 0x31, 0x71, 0x49, 0x10, 0x47, 0x19, 0x3f, 0x64, 0xff, 0x91, 0x01, 0xbd, 0xd3, 0xd4,
+	0x8d, 0x32, 0xd7, 0xa3, 0xcb, 0x68, 0x01, 0xe6, 0xc9, 0x6b, 0xe7, 0xc2, 0xcd, 0x2e, 0x4c, 0x63,
+	0xc3, 0xd8, 0xec, 0xa2, 0x21, 0xcc, 0xb9, 0xd3, 0x78, 0x16, 0x11, 0xb3, 0xb1, 0x61, 0x6c, 0x1a,
+	0x68, 0x0d, 0xc6, 0xd1, 0x6c, 0xea, 0x78, 0x71, 0x74, 0x1e, 0xa4, 0x53, 0x0e, 0xcb, 0x6c, 0x6e,
+	0x18, 0x9b, 0x6d, 0x84, 0x00, 0xce, 0xc2, 0xd8, 0x7b, 0xc9, 0x3f, 0x6f, 0xb1, 0xcf, 0x97, 0xa0,
+	0x2f, 0xd6, 0x70, 0x30, 0xb9, 0x20, 0x66, 0x5b, 0x9e, 0x24, 0xc1, 0x14, 0x3b, 0x19, 0x71, 0xa7,
+	0x89, 0x39, 0xb7, 0x61, 0x6c, 0x36, 0xd9, 0x5a, 0x4c, 0xdc, 0xd0, 0x39, 0xc7, 0x38, 0x33, 0xe7,
+	0xe9, 0x9a, 0x6d, 0xc2, 0xca, 0x13, 0x4c, 0x94, 0xfb, 0x65, 0xcf, 0xf0, 0x97, 0x33, 0x9c, 0x11,
+	0xfb, 0x07, 0x80, 0x94, 0xe5, 0xc7, 0x98, 0xb8, 0x41, 0x98, 0xa1, 0x4d, 0xe8, 0x13, 0xe5, 0xb0,
+	0x69, 0x6c, 0x34, 0x37, 0x7b, 0x3b, 0xe8, 0x01, 0xe3, 0xc4, 0x03, 0xe5, 0x03, 0xfb, 0xf7, 0x5b,
+	0xd0, 0x3b, 0xc1, 0x91, 0x2f, 0xe0, 0xa1, 0x3e, 0xb4, 0x7c, 0x9c, 0x11, 0x46, 0x74, 0x1f, 0x2d,
+	0x42, 0x8f, 0xfe, 0x72, 0x32, 0x92, 0x06, 0xd1, 0x84, 0x51, 0xde, 0x45, 0x3d, 0x68, 0xba, 0x53,
+	0xc2, 0x68, 0x6d, 0x52, 0xba, 0x12, 0xf7, 0x6a, 0x8a, 0x23, 0x52, 0x50, 0xdb, 0x47, 0xeb, 0xb0,
+	0xa8, 0xae, 0xca, 0xef, 0xdb, 0xec, 0xfb, 0x55, 0x58, 0x90, 0x9b, 0x29, 0xc7, 0x6a, 0xce, 0xc9,
+	0x0d, 0xca, 0x8d, 0x78, 0x46, 0x9c, 0x0c, 0x7b, 0x71, 0xe4, 0x73, 0xf2, 0xdb, 0x68, 0x0c, 0xdd,
+	0x73, 0x8c, 0x9d, 0x30, 0x98, 0x06, 0xc4, 0xec, 0x48, 0x2e, 0x79, 0x21, 0xb9, 0x14, 0x6b, 0xdd,
+	0x0d, 0x63, 0x73, 0x80, 0x4c, 0x18, 0xc5, 0x33, 0x32, 0x89, 0x83, 0x68, 0xe2, 0x78, 0x17, 0x6e,
+	0xe4, 0x04, 0xbe, 0x09, 0x1b, 0xc6, 0x66, 0x8b, 0x42, 0x0e, 0xdd, 0x8c, 0x38, 0x17, 0x71, 0xe2,
+	0x24, 0xb3, 0xb3, 0x97, 0xf8, 0xca, 0xec, 0xb1, 0x8b, 0x2e, 0xc3, 0x20, 0x98, 0x44, 0x71, 0x8a,
+	0x7d, 0x27, 0x8a, 0x7d, 0x9c, 0x99, 0xfd, 0x8d, 0xa6, 0xbe, 0x8c, 0xfd, 0x09, 0xce, 0xcc, 0xc1,
+	0x46, 0x73, 0xb3, 0x85, 0xde, 0x83, 0x5e, 0x1a, 0xcf, 0x08, 0x76, 0x2e, 0x82, 0x88, 0x64, 0xe6,
+	0x90, 0x71, 0x75, 0x24, 0xb8, 0xfa, 0x8c, 0xee, 0x1c, 0x04, 0x11, 0xa1, 0x77, 0x9b, 0xba, 0xaf,
+	0x9d, 0xec, 0xc2, 0x4d, 0xfd, 0xcc, 0x5c, 0x60, 0x77, 0x5b, 0x80, 0xf9, 0x97, 0xf8, 0x2a, 0xc3,
+	0x91, 0x6f, 0x8e, 0x36, 0x8c, 0xcd, 0x0e, 0xfa, 0x08, 0x16, 0x19, 0x6b, 0xbd, 0x59, 0x46, 0xe2,
+	0xa9, 0x93, 0x62, 0x2f, 0xa6, 0xa7, 0xc7, 0x0c, 0xe6, 0x37, 0x04, 0x4c, 0x45, 0x32, 0x0f, 0x1e,
+	0xe3, 0x8c, 0xec, 0xb1, 0xc3, 0xcf, 0xf8, 0xd9, 0xfd, 0x88, 0xa4, 0x57, 0x94, 0xe8, 0xf3, 0x20,
+	0x72, 0x43, 0x87, 0xb1, 0xc3, 0xc7, 0x21, 0x71, 0x4d, 0x44, 0x51, 0x5a, 0xdf, 0x85, 0x95, 0x6b,
+	0xbe, 0xe9, 0x41, 0x93, 0xb2, 0xc0, 0x60, 0xbc, 0x19, 0x40, 0xfb, 0xd2, 0x0d, 0x67, 0x98, 0x49,
+	0xb7, 0xff, 0x41, 0xe3, 0xbb, 0x86, 0x1d, 0x43, 0x9f, 0x63, 0xce, 0x92, 0x38, 0xca, 0x30, 0x7a,
+	0x17, 0x06, 0xb9, 0xc4, 0x28, 0x95, 0xec, 0xcb, 0xde, 0x4e, 0x5f, 0xa5, 0x1c, 0xdd, 0x87, 0xa1,
+	0x76, 0x28, 0x33, 0x1b, 0x1b, 0xcd, 0xca, 0xa9, 0xb2, 0xbe, 0x50, 0x2d, 0xea, 0xdb, 0xff, 0x69,
+	0x00, 0xa2, 0x18, 0x4f, 0x63, 0x76, 0x4a, 0x2a, 0x63, 0xf9, 0xb0, 0x71, 0x93, 0x72, 0x71, 0xe5,
+	0x5c, 0x87, 0x36, 0xbf, 0x62, 0xb3, 0xe6, 0x8a, 0x1f, 0xd7, 0xf3, 0xbc, 0xc5, 0xee, 0xf9, 0x50,
+	0xe1, 0xb9, 0x7e, 0x8f, 0x6b, 0x58, 0xff, 0x4b, 0x30, 0xf8, 0xa7, 0xb0, 0xa8, 0xa1, 0x11, 0x7c,
+	0x36, 0x61, 0x24, 0x29, 0x4b, 0x52, 0x1c, 0x4c, 0xdd, 0x09, 0x16, 0x34, 0x2f, 0x17, 0x12, 0xc0,
+	0x69, 0x1a, 0xa7, 0x82, 0xda, 0x25, 0xe8, 0x9f, 0xbb, 0x41, 0x38, 0x4b, 0xb1, 0xe3, 0xc5, 0x3e,
+	0x27, 0x7a, 0x60, 0x1f, 0xc0, 0xe2, 0x69, 0xea, 0x7a, 0x2f, 0x9f, 0xf2, 0x2f, 0xbe, 0x3a, 0x37,
+	0xed, 0x7f, 0x35, 0xa0, 0x77, 0x70, 0x7a, 0xb4, 0xb7, 0x4b, 0x08, 0x9e, 0x26, 0x4c, 0xb3, 0x5d,
+	0xfe, 0x27, 0xb5, 0x2d, 0x4e, 0xde, 0x37, 0x61, 0x2e, 0x23, 0x2e, 0x99, 0x65, 0xec, 0x9b, 0xe1,
+	0xce, 0x1d, 0xc1, 0x47, 0xe5, 0x3b, 0xf6, 0xf7, 0x09, 0x3b, 0x74, 0xb3, 0x80, 0x96, 0xa0, 0x2f,
+	0xe1, 0x53, 0x4f, 0x60, 0xb6, 0xa4, 0x8f, 0x49, 0x71, 0x16, 0x87, 0x97, 0x98, 0xaf, 0xb6, 0xd9,
+	0xea, 0x02, 0xcc, 0x0b, 0xda, 0xb9, 0xfb, 0xb0, 0xbf, 0x0d, 0xa0, 0xe0, 0x19, 0x40, 0xf7, 0xf0,
+	0xd8, 0xf9, 0xe8, 0xe8, 0xf0, 0xc9, 0xc1, 0xe9, 0xe8, 0x16, 0xea, 0xc1, 0xfc, 0xc9, 0xfe, 0xe9,
+	0xe9, 0xd1, 0xfe, 0xe3, 0x91, 0x81, 0x00, 0xe6, 0x3e, 0xda, 0x3d, 0xa4, 0x7f, 0x37, 0xec, 0xbf,
+	0x6d, 0xc0, 0x40, 0x30, 0x4a, 0x7c, 0xf9, 0x10, 0xda, 0x94, 0x20, 0xce, 0xfa, 0xe1, 0xce, 0x3d,
+	0x71, 0x43, 0xed, 0x90, 0xfa, 0xab, 0xaa, 0xd4, 0x4c, 0xd0, 0x85, 0xdc, 0xb9, 0xa7, 0x5c, 0x86,
+	0x81, 0x97, 0x62, 0x16, 0x28, 0x1c, 0xdf, 0x25, 0x92, 0xb8, 0x1a, 0x6f, 0xc8, 0xdd, 0xe4, 0x08,
+	0x3a, 0xb9, 0x12, 0xcc, 0x31, 0x80, 0x2b, 0x30, 0x94, 0xd2, 0x4e, 0xb1, 0x9b, 0xc5, 0x11, 0x73,
+	0x8f, 0x5d, 0x74, 0x0f, 0xda, 0x17, 0x24, 0xf4, 0x32, 0xb3, 0xa3, 0xb9, 0x79, 0x45, 0x00, 0xf6,
+	0x29, 0xf4, 0xb5, 0x1b, 0xf7, 0x60, 0xfe, 0xf9, 0xf1, 0x0f, 0x8f, 0x3f, 0x79, 0x71, 0x3c, 0xba,
+	0xc5, 0x59, 0x75, 0x78, 0x7a, 0xb8, 0x7b, 0xca, 0xb8, 0xa3, 0x71, 0xae, 0x41, 0x7f, 0x9e, 0x3c,
+	0xdf, 0xdb, 0xdb, 0xdf, 0x7f, 0xbc, 0xff, 0x78, 0xd4, 0x54, 0x78, 0xd7, 0xa2, 0x50, 0xf7, 0x2e,
+	0xdc, 0x28, 0xc2, 0xe1, 0xd3, 0x98, 0x3a, 0x3e, 0xaa, 0x8e, 0xb3, 0xc8, 0xa7, 0xfe, 0x97, 0xbc,
+	0x16, 0x0a, 0xd2, 0x67, 0x1e, 0x4a, 0x59, 0xa5, 0x1a, 0x56, 0xa8, 0x6f, 0x3c, 0x23, 0xc9, 0x8c,
+	0x38, 0x41, 0xe4, 0xe3, 0xd7, 0x42, 0x7d, 0x1f, 0xc2, 0xe8, 0x88, 0xc6, 0xc8, 0x28, 0x88, 0x26,
+	0xbb, 0xbe, 0x9f, 0xe2, 0x2c, 0xa3, 0xd1, 0x57, 0xf8, 0x6d, 0x1e, 0x8d, 0xfb, 0xd0, 0xba, 0x88,
+	0x33, 0x22, 0xd4, 0xf4, 0x77, 0x0c, 0x58, 0xa0, 0xf6, 0xf4, 0xb1, 0x1b, 0x5d, 0x49, 0x6d, 0xff,
+	0x01, 0xf4, 0xe9, 0xc7, 0xa7, 0xf1, 0x2e, 0x8f, 0xda, 0x3c, 0x04, 0x6e, 0x2a, 0x46, 0xae, 0x9c,
+	0x7e, 0xa0, 0x1e, 0xe5, 0xc6, 0xfd, 0x2d, 0x18, 0x57, 0x16, 0x55, 0xbb, 0xee, 0xea, 0x76, 0xdd,
+	0x64, 0x76, 0xbd, 0x01, 0xa3, 0x02, 0xb2, 0x30, 0xea, 0x3e, 0xb4, 0x72, 0x66, 0x74, 0xed, 0x87,
+	0xfc, 0xc4, 0x5e, 0x1c, 0xe4, 0x31, 0x9c, 0x9e, 0x70, 0x7d, 0x3f, 0xad, 0x4d, 0x34, 0x9a, 0xf6,
+	0x3d, 0x18, 0x2b, 0x5f, 0xd4, 0x02, 0xfd, 0x43, 0x03, 0xc6, 0xc7, 0xf8, 0x95, 0x60, 0x96, 0x04,
+	0xbb, 0x03, 0x2d, 0x72, 0x95, 0x48, 0x35, 0xbe, 0x2f, 0x28, 0xaf, 0x9c, 0x7b, 0x20, 0x7e, 0x9e,
+	0x5e, 0x25, 0xd8, 0xfe, 0x04, 0x7a, 0xca, 0x4f, 0xb4, 0x0a, 0x8b, 0x2f, 0x0e, 0x4f, 0x8f, 0xf7,
+	0x4f, 0x4e, 0x9c, 0xa7, 0xcf, 0x3f, 0xfc, 0xe1, 0xfe, 0x4f, 0x9c, 0x83, 0xdd, 0x93, 0x83, 0xd1,
+	0x2d, 0xb4, 0x02, 0xe8, 0x78, 0xff, 0xe4, 0x74, 0xff, 0xb1, 0xb6, 0x6e, 0xa0, 0x05, 0xe8, 0xa9,
+	0x0b, 0x0d, 0xdb, 0x02, 0xf3, 0x18, 0xbf, 0x7a, 0x11, 0x90, 0x08, 0x67, 0x99, 0x8e, 0xd8, 0x7e,
+	0x0f, 0x90, 0x7a, 0x1b, 0x41, 0xda, 0x02, 0xcc, 0xbb, 0x7c, 0x49, 0x50, 0x77, 0x08, 0x68, 0x2f,
+	0x8e, 0x22, 0xec, 0x91, 0xa7, 0x18, 0xa7, 0x92, 0xba, 0xf7, 0x14, 0xa6, 0xf5, 0x76, 0x56, 0x05,
+	0x75, 0x15, 0xc5, 0xe9, 0x43, 0x2b, 0xc1, 0xe9, 0x94, 0xf1, 0xb2, 0x63, 0x7f, 0x0d, 0x16, 0x35,
+	0x50, 0x05, 0xca, 0x04, 0xe3, 0x54, 0xfa, 0xb4, 0xb6, 0x9d, 0x40, 0x8b, 0x5a, 0x0f, 0xb5, 0xc1,
+	0x20, 0xf2, 0xe2, 0x29, 0xf5, 0x88, 0x06, 0x0b, 0xdb, 0x25, 0xe9, 0xd0, 0xd4, 0x84, 0xb9, 0x4d,
+	0x9a, 0xdb, 0xf1, 0x60, 0x46, 0x33, 0x43, 0xfc, 0x3a, 0x09, 0x52, 0x6e, 0xea, 0x22, 0xdf, 0x6b,
+	0xc9, 0x0c, 0x25, 0xc5, 0x97, 0xb1, 0xc7, 0xb7, 0x7c, 0x1c, 0xba, 0x57, 0xcc, 0xda, 0x07, 0xf6,
+	0x9f, 0x34, 0x60, 0xb0, 0xeb, 0x91, 0xe0, 0x12, 0x0b, 0x8b, 0xa2, 0xfe, 0x22, 0xc5, 0xd3, 0x98,
+	0x60, 0x47, 0xd3, 0x7c, 0xea, 0x46, 0xf8, 0x09, 0x27, 0x89, 0x03, 0x71, 0x8f, 0x2e, 0x25, 0x41,
+	0xa6, 0x3c, 0x4d, 0xe6, 0x96, 0x47, 0xd0, 0xf1, 0xdc, 0xc4, 0xf5, 0x02, 0x72, 0x25, 0x3c, 0xcd,
+	0x32, 0x0c, 0xc2, 0xd8, 0x73, 0x43, 0xe7, 0xcc, 0x0d, 0xdd, 0xc8, 0x93, 0x7e, 0x74, 0x05, 0x86,
+	0x02, 0x8f, 0x5c, 0xe7, 0x79, 0xe8, 0x1a, 0x8c, 0x67, 0x51, 0x86, 0x09, 0x09, 0xb1, 0x9f, 0x6f,
+	0xb1, 0x74, 0x94, 0xc6, 0x0c, 0x9e, 0xa2, 0x66, 0x2e, 0x89, 0xb3, 0x8b, 0x20, 0x73, 0x32, 0x1c,
+	0xc9, 0xcc, 0xec, 0x2e, 0xac, 0x96, 0x36, 0x53, 0xec, 0xe1, 0xe0, 0x12, 0xfb, 0x2c, 0x4d, 0x6b,
+	0xd2, 0xa4, 0x92, 0x66, 0xce, 0xb3, 0x84, 0x7a, 0xc1, 0x4c, 0x64, 0x68, 0x36, 0x0c, 0x12, 0xcc,
+	0x9d, 0x04, 0xf7, 0x65, 0x3d, 0x66, 0xaf, 0x3d, 0xc5, 0x97, 0xd9, 0xcb, 0xb0, 0x78, 0x14, 0x64,
+	0x44, 0x30, 0x48, 0x49, 0x81, 0x97, 0xf4, 0x65, 0x21, 0xd5, 0xaf, 0x41, 0x47, 0x70, 0x4a, 0x42,
+	0x5b, 0x12, 0xd0, 0x34, 0x46, 0xdb, 0x7f, 0x6e, 0x40, 0x8b, 0xaa, 0x03, 0x53, 0x83, 0xd9, 0x99,
+	0x53, 0xf0, 0x5a, 0xd1, 0x8b, 0x06, 0x4b, 0x44, 0x15, 0xdd, 0x6c, 0xb2, 0x13, 0x34, 0xd5, 0xbf,
+	0x22, 0x58, 0x30, 0xa0, 0xc5, 0x48, 0xc9, 0xd7, 0x52, 0xec, 0x5d, 0x9a, 0x6d, 0x29, 0x8d, 0xcc,
+	0x25, 0xfc, 0x14, 0x67, 0xaf, 0x58, 0x61, 0x67, 0xe6, 0x65, 0x40, 0x0b, 0xa2, 0xb3, 0x78, 0x16,
+	0xf9, 0x8c, 0x93, 0x1d, 0xaa, 0x5b, 0x09, 0xf3, 0x9a, 0x34, 0xe8, 0x31, 0xde, 0xd9, 0x88, 0xfa,
+	0xc6, 0x8c, 0x69, 0x6f, 0x4e, 0xff, 0x36, 0x8c, 0x95, 0x35, 0x41, 0xbc, 0x05, 0x6d, 0x7a, 0x75,
+	0x99, 0xfa, 0x4b, 0x3e, 0xd2, 0x43, 0xf6, 0x67, 0x30, 0x10, 0xb4, 0x1f, 0xd1, 0xec, 0x39, 0xab,
+	0xea, 0x14, 0x27, 0xdf, 0x84, 0x91, 0x7b, 0xe9, 0x06, 0xa1, 0x7b, 0x16, 0x62, 0x87, 0xc4, 0x2f,
+	0x71, 0xc4, 0x63, 0x3c, 0xd3, 0x63, 0x29, 0xad, 0xf3, 0x38, 0x7d, 0xc5, 0xf2, 0x5c, 0xee, 0xbc,
+	0xff, 0xd4, 0x80, 0x2e, 0x45, 0xc2, 0x20, 0x57, 0x39, 0x7a, 0x23, 0xc8, 0x14, 0x27, 0x33, 0x5e,
+	0x95, 0x39, 0x99, 0x17, 0xa7, 0x3c, 0x70, 0x1a, 0x94, 0x9f, 0x29, 0xa6, 0x69, 0x89, 0x47, 0xb0,
+	0xcf, 0x78, 0xdc, 0xa1, 0x91, 0x83, 0xea, 0x50, 0x8a, 0xbf, 0xc0, 0x6c, 0x95, 0x73, 0x59, 0x95,
+	0xf8, 0x9c, 0x26, 0x71, 0x8d, 0x5e, 0xfb, 0x3e, 0x8c, 0xf3, 0x3b, 0xe6, 0xee, 0xb2, 0x7c, 0x57,
+	0xfb, 0xbf, 0x0d, 0x40, 0xea, 0x31, 0xc1, 0x59, 0x2a, 0x15, 0xaa, 0x14, 0xa9, 0x4c, 0x11, 0xd8,
+	0x0d, 0xd9, 0xd2, 0xd9, 0x2c, 0x15, 0x31, 0x69, 0x40, 0x8f, 0x31, 0x83, 0x64, 0xc7, 0x72, 0x42,
+	0xd8, 0x12, 0x3f, 0xc6, 0x3d, 0xc2, 0x6d, 0x58, 0xa2, 0xb5, 0x42, 0x85, 0x9b, 0xcc, 0x2b, 0x20,
+	0x0b, 0x90, 0xc2, 0x14, 0x1c, 0x51, 0xb6, 0xf9, 0x4c, 0x81, 0x3a, 0xd4, 0x08, 0x2f, 0xe2, 0xd0,
+	0x77, 0xc8, 0x45, 0x8a, 0x33, 0xf6, 0x57, 0x86, 0x3d, 0x51, 0x30, 0x52, 0xb0, 0xca, 0x87, 0xf9,
+	0x11, 0xa6, 0x58, 0x06, 0xba, 0x2b, 0x95, 0xa3, 0xab, 0x55, 0x30, 0x39, 0xb1, 0xf6, 0x08, 0x86,
+	0x4f, 0x30, 0x39, 0x8c, 0xce, 0x63, 0xa9, 0x64, 0xbf, 0xd7, 0x80, 0x85, 0x7c, 0x49, 0x70, 0x62,
+	0x15, 0x16, 0x02, 0x1f, 0x47, 0x24, 0x20, 0x57, 0xba, 0x8f, 0x1a, 0x40, 0xdb, 0x0d, 0x03, 0x37,
+	0x13, 0xbe, 0xe9, 0x36, 0x2c, 0x51, 0x61, 0x49, 0x1a, 0x73, 0x11, 0x31, 0x8d, 0xa1, 0x74, 0xd0,
+	0x5d, 0x97, 0xd9, 0x64, 0xb1, 0xd9, 0x92, 0x5c, 0xe4, 0x9f, 0xe2, 0x54, 0xf2, 0xa4, 0x5c, 0x49,
+	0xcf, 0xb1, 0x55, 0xbd, 0xe6, 0xee, 0xc8, 0x7a, 0x32, 0xbb, 0x8a, 0x3c, 0xec, 0x3b, 0x24, 0xa6,
+	0x80, 0x83, 0x88, 0x59, 0x51, 0x87, 0x15, 0xf7, 0x38, 0x23, 0x11, 0x26, 0xcc, 0xfb, 0x74, 0xd0,
+	0x36, 0x8c, 0xa8, 0xd7, 0x71, 0xce, 0x5c, 0xe2, 0xd1, 0x14, 0xd8, 0x25, 0x19, 0x2b, 0x10, 0x7b,
+	0x3b, 0xcb, 0x8a, 0x03, 0xfa, 0x90, 0xee, 0xd2, 0xfc, 0x29, 0xb3, 0xdf, 0xc2, 0x50, 0x5f, 0x91,
+	0x5e, 0x8d, 0x41, 0xc0, 0x99, 0xc8, 0x8d, 0x4b, 0xae, 0xae, 0xc1, 0x16, 0x57, 0x60, 0xe8, 0x5e,
+	0x4e, 0x24, 0xae, 0xe0, 0xb7, 0xa4, 0x7a, 0xac, 0xc0, 0x90, 0xaa, 0x82, 0xb2, 0x9e, 0xf3, 0xe0,
+	0x22, 0xc8, 0x48, 0x3c, 0x49, 0xdd, 0xa9, 0xd9, 0xa6, 0x85, 0xa8, 0xfd, 0x9c, 0x85, 0xc4, 0xbc,
+	0xf1, 0xf0, 0x9c, 0xc1, 0xa7, 0x07, 0x39, 0x0f, 0xb2, 0x0b, 0x57, 0xe4, 0x5e, 0x65, 0x66, 0x71,
+	0x37, 0xb6, 0x02, 0x43, 0xd9, 0xbb, 0xc8, 0x9c, 0x10, 0x9f, 0x13, 0x61, 0xbc, 0xbf, 0x06, 0x63,
+	0x61, 0x28, 0x9f, 0x24, 0x58, 0x42, 0xdd, 0xaa, 0x73, 0x0e, 0xbd, 0x9d, 0x45, 0xdd, 0xb2, 0x58,
+	0x02, 0x68, 0x7f, 0x0f, 0x90, 0xf8, 0xbd, 0x17, 0xc6, 0x19, 0x16, 0x10, 0x96, 0xa0, 0xef, 0x85,
+	0x71, 0x56, 0x4a, 0x0b, 0x17, 0x60, 0x3e, 0x9b, 0x79, 0x1e, 0xf5, 0xa5, 0x3c, 0x38, 0xfb, 0xb0,
+	0xc8, 0xbe, 0x12, 0x10, 0xa4, 0x5d, 0xfe, 0x02, 0xf8, 0xf3, 0x7e, 0x0a, 0xef, 0x0a, 0xf0, 0x08,
+	0x3d, 0x80, 0xf6, 0x79, 0x9c, 0x7a, 0x9c, 0xcb, 0x1d, 0xfb, 0xaf, 0x0d, 0x18, 0x33, 0x34, 0x3c,
+	0x91, 0x17, 0x57, 0xfc, 0x26, 0x0c, 0xe8, 0x15, 0xb1, 0x54, 0x52, 0x81, 0x64, 0x29, 0xb7, 0x0c,
+	0xb6, 0xca, 0x0f, 0x1f, 0xdc, 0x42, 0x8f, 0xa0, 0xaf, 0x36, 0x7e, 0x18, 0xa6, 0xde, 0xce, 0x9a,
+	0xbc, 0x52, 0x45, 0x34, 0x07, 0xb7, 0xd0, 0xb6, 0x30, 0x7e, 0x86, 0xc6, 0x6c, 0xea, 0x1f, 0x54,
+	0x78, 0x76, 0x70, 0xeb, 0xc3, 0x0e, 0xcc, 0x71, 0xbd, 0xb1, 0xef, 0xc0, 0x40, 0xbb, 0x80, 0x96,
+	0xfd, 0xf5, 0xed, 0xbf, 0x31, 0x00, 0x51, 0x79, 0x95, 0xf8, 0xb6, 0x02, 0x43, 0xe2, 0xa6, 0x13,
+	0x4c, 0x1c, 0x2d, 0xb7, 0x61, 0x3a, 0x19, 0xfb, 0x79, 0x56, 0xc1, 0x6b, 0x15, 0x0b, 0x90, 0xb2,
+	0x28, 0x8b, 0xc0, 0xa6, 0x34, 0x5f, 0x9e, 0x37, 0xc8, 0x2c, 0x5e, 0x24, 0x40, 0x2d, 0x19, 0xc7,
+	0x92, 0x19, 0xad, 0x1b, 0x5d, 0x22, 0x12, 0x0a, 0x61, 0xb3, 0x4c, 0xbb, 0x84, 0x75, 0x52, 0xdf,
+	0x9a, 0x06, 0x97, 0xd4, 0x15, 0xce, 0x33, 0x29, 0xfc, 0xa5, 0x01, 0x23, 0x7a, 0x67, 0x4d, 0x08,
+	0xef, 0x43, 0x9f, 0xb1, 0xe8, 0xff, 0x4d, 0x06, 0xdf, 0x14, 0x3e, 0x39, 0x4e, 0x70, 0x24, 0x44,
+	0x60, 0xea, 0x22, 0x28, 0xf4, 0x5e, 0x93, 0xc0, 0xf7, 0x61, 0x59, 0xa0, 0x2f, 0x31, 0xf9, 0x7e,
+	0x5e, 0xfc, 0xf2, 0x2c, 0xbb, 0x14, 0x6f, 0x38, 0x79, 0xf6, 0x5f, 0x35, 0x60, 0xa5, 0xfc, 0xbd,
+	0xf0, 0xa1, 0x1f, 0x15, 0x91, 0x34, 0x77, 0x7d, 0x3c, 0x64, 0xbf, 0xaf, 0xd3, 0x5d, 0xfa, 0xb0,
+	0xb4, 0x6c, 0xfd, 0x9d, 0x01, 0x43, 0x7d, 0xa9, 0x92, 0xd5, 0x52, 0x3b, 0xcc, 0xfd, 0xb5, 0x14,
+	0x7d, 0x4d, 0x42, 0xd9, 0x94, 0xe5, 0xe7, 0x2f, 0x97, 0x3f, 0x96, 0xad, 0x9e, 0xd7, 0xaa, 0x05,
+	0xc3, 0x3a, 0x37, 0x30, 0xec, 0x7d, 0x58, 0x7a, 0xe1, 0x86, 0x21, 0x26, 0x1f, 0x72, 0x90, 0x4a,
+	0x0b, 0xe3, 0x15, 0x2f, 0x25, 0x9c, 0x38, 0x0a, 0x79, 0xb8, 0xe9, 0xd8, 0x9b, 0xb0, 0x5c, 0x3a,
+	0x5d, 0xe4, 0xf5, 0xf2, 0x4e, 0xf4, 0xa4, 0x61, 0xaf, 0xc2, 0xb2, 0x40, 0xa4, 0x03, 0xb6, 0xbf,
+	0x01, 0x2b, 0xe5, 0x8d, 0x7a, 0x18, 0x4d, 0xfb, 0xbf, 0x0c, 0xe8, 0x6b, 0x5d, 0xaa, 0x4a, 0x92,
+	0x23, 0x1a, 0xa4, 0x0d, 0xd9, 0xa8, 0x64, 0x99, 0x0a, 0x6f, 0x89, 0x35, 0xab, 0xfd, 0xcc, 0x56,
+	0x4d, 0x3f, 0xb3, 0x7d, 0x6d, 0x3f, 0x73, 0xee, 0xba, 0x7e, 0xe6, 0x7c, 0x7d, 0x3f, 0xb3, 0x53,
+	0xdf, 0xcf, 0xec, 0xd6, 0xf5, 0x33, 0xa1, 0xbe, 0x9f, 0x69, 0xff, 0x36, 0x34, 0x0f, 0xe2, 0x44,
+	0x2d, 0x31, 0x78, 0x74, 0x13, 0x9a, 0xe3, 0xe4, 0x7a, 0xd2, 0x90, 0x0a, 0xe1, 0x4e, 0x09, 0x8d,
+	0xb9, 0x22, 0x9b, 0x11, 0x0d, 0x90, 0x1e, 0x34, 0xcf, 0xb1, 0x6c, 0x7b, 0x28, 0x4c, 0x6b, 0xab,
+	0xcd, 0x5f, 0x56, 0x48, 0x89, 0x36, 0x26, 0xf3, 0x1d, 0xb6, 0x0b, 0x6d, 0x76, 0x15, 0x76, 0x82,
+	0x15, 0x16, 0xf9, 0x39, 0xd3, 0x90, 0xb1, 0x5f, 0xe9, 0x98, 0xe7, 0x75, 0x19, 0x5f, 0x2b, 0x5a,
+	0xd5, 0x26, 0xed, 0x19, 0x24, 0xb2, 0xdd, 0x07, 0x32, 0xb0, 0xc7, 0x89, 0xbd, 0x03, 0xe8, 0x47,
+	0x33, 0x9c, 0x5e, 0xe9, 0xdd, 0xb8, 0xdb, 0x30, 0x27, 0xa4, 0x66, 0x54, 0x1b, 0x99, 0xf6, 0x77,
+	0x60, 0xfc, 0xe1, 0x2c, 0x08, 0x7d, 0x4d, 0x15, 0x84, 0xe4, 0x0d, 0x59, 0xe7, 0x14, 0xf2, 0xe1,
+	0xdd, 0xd0, 0xae, 0xfd, 0x08, 0x90, 0xfa, 0x99, 0x40, 0x95, 0x37, 0xc5, 0x6a, 0x1a, 0xab, 0xb6,
+	0x0d, 0x0b, 0xc7, 0xb1, 0x8f, 0x95, 0x6c, 0xac, 0x9a, 0xab, 0xfe, 0x14, 0x3a, 0xf2, 0x0c, 0xb2,
+	0xa1, 0x45, 0x65, 0x5f, 0x72, 0x9f, 0x79, 0x65, 0x4c, 0xcf, 0xc9, 0xfc, 0x39, 0x77, 0x39, 0x3c,
+	0x67, 0xa5, 0x21, 0x83, 0x31, 0x2d, 0x97, 0x28, 0xe3, 0x9c, 0xfd, 0x1c, 0x06, 0xfa, 0xe7, 0x8b,
+	0xd0, 0x63, 0xfa, 0xc7, 0xdd, 0xa3, 0x10, 0x83, 0x72, 0xa9, 0xbc, 0x26, 0xd5, 0xab, 0xa5, 0x3c,
+	0x2f, 0x64, 0x33, 0x11, 0xfb, 0x67, 0x06, 0x0c, 0x28, 0x89, 0x41, 0x34, 0x79, 0x1a, 0x87, 0x81,
+	0x77, 0x55, 0xa7, 0x04, 0x1c, 0xf6, 0x08, 0x3a, 0xd3, 0x20, 0x62, 0xa5, 0xa1, 0x10, 0xf0, 0x32,
+	0x0c, 0xa8, 0x0d, 0x9d, 0xb9, 0x19, 0x76, 0xa6, 0x34, 0xf8, 0x34, 0x65, 0x69, 0x4a, 0x97, 0x69,
+	0xd6, 0xed, 0x4c, 0x83, 0x30, 0x0c, 0xf8, 0x66, 0x1e, 0xab, 0x68, 0x86, 0xc5, 0xa0, 0x30, 0xe7,
+	0x65, 0xff, 0xb3, 0x01, 0x3d, 0x61, 0xf8, 0xfb, 0xfe, 0x04, 0xcb, 0x14, 0x9d, 0x3a, 0xc3, 0x5c,
+	0xcd, 0xc5, 0x9a, 0x56, 0x6e, 0x97, 0x18, 0xd0, 0xcc, 0x93, 0xd5, 0xd8, 0xc7, 0x8f, 0xa8, 0xc8,
+	0x39, 0x89, 0x72, 0x69, 0x87, 0x2d, 0xb5, 0x2b, 0x8e, 0x95, 0x7b, 0xca, 0x2d, 0xe8, 0x8b, 0xef,
+	0x18, 0x17, 0xcc, 0x79, 0x4d, 0x70, 0x3a, 0x87, 0xc4, 0xd9, 0x1d, 0x79, 0xb6, 0x73, 0xfd, 0x59,
+	0x5a, 0x2f, 0x0b, 0xda, 0x9e, 0xa4, 0x6e, 0x72, 0x21, 0x7d, 0xdd, 0xa7, 0xd0, 0x57, 0x97, 0xd1,
+	0xbb, 0xd0, 0xe6, 0xbe, 0xc2, 0xd0, 0x4a, 0x26, 0x5d, 0xe2, 0xf7, 0xa0, 0xcd, 0x3d, 0x47, 0x43,
+	0xeb, 0x31, 0x2a, 0xbc, 0xa3, 0x7a, 0x4a, 0x7f, 0x96, 0xf4, 0x54, 0x73, 0x19, 0xf6, 0x12, 0x6d,
+	0xf9, 0x90, 0x57, 0x71, 0xfa, 0x52, 0x2d, 0x2e, 0xfe, 0xc3, 0x80, 0x9e, 0xb2, 0x4c, 0xf5, 0x70,
+	0x42, 0xaf, 0xe6, 0xf8, 0x81, 0x3b, 0xc5, 0x04, 0xa7, 0x42, 0x0b, 0x44, 0xe6, 0x4c, 0x07, 0x44,
+	0x3e, 0x9e, 0xa4, 0x18, 0x9b, 0x0d, 0x35, 0x73, 0x56, 0xd6, 0x9b, 0x6a, 0xf5, 0xc0, 0xa9, 0x6b,
+	0xc9, 0xea, 0x41, 0x53, 0x7c, 0xee, 0x69, 0xdf, 0x81, 0x15, 0xae, 0xf8, 0x11, 0xbf, 0x85, 0x53,
+	0x92, 0x10, 0x2b, 0x5b, 0xf3, 0x08, 0xcd, 0x93, 0xf3, 0x79, 0x86, 0xda, 0x84, 0x11, 0x55, 0x4c,
+	0x6d, 0xa7, 0x23, 0xbf, 0xa1, 0x97, 0xd2, 0x76, 0x78, 0x11, 0xbf, 0x0e, 0x6b, 0x8c, 0xf3, 0xa7,
+	0x71, 0x12, 0x87, 0xf1, 0xe4, 0xea, 0x64, 0x76, 0x96, 0x79, 0x69, 0x90, 0xb0, 0x81, 0xdc, 0x9f,
+	0x19, 0xb0, 0xa8, 0xed, 0x8a, 0xe4, 0xe8, 0xeb, 0x5c, 0xf0, 0x79, 0x2d, 0xc1, 0x85, 0x35, 0x96,
+	0x5d, 0xbd, 0xd8, 0x97, 0xd9, 0xf6, 0x23, 0x58, 0x90, 0x38, 0x8b, 0xba, 0xa3, 0x59, 0x4d, 0x75,
+	0xa8, 0xcc, 0xc4, 0x27, 0x0f, 0x79, 0xa8, 0xc6, 0x3e, 0xbb, 0x2d, 0xb5, 0x56, 0x7a, 0xde, 0x92,
+	0xe7, 0xd9, 0x96, 0xf8, 0x8a, 0x7f, 0x61, 0xff, 0x08, 0x40, 0x41, 0x59, 0x6e, 0xd9, 0x5d, 0x93,
+	0x69, 0xe4, 0xe6, 0x9f, 0x7b, 0x03, 0x2f, 0x0e, 0xe3, 0x54, 0x78, 0x83, 0x7f, 0x31, 0x60, 0x5c,
+	0xbd, 0x5a, 0x25, 0xe8, 0xd4, 0x59, 0xa3, 0x6a, 0x52, 0xdc, 0x0d, 0xbc, 0x0f, 0xc3, 0x94, 0xdb,
+	0x82, 0x34, 0x94, 0xd6, 0x0d, 0x46, 0xf5, 0x08, 0x16, 0x93, 0x14, 0x5f, 0x3a, 0xa5, 0x4f, 0xda,
+	0x37, 0x7c, 0x42, 0x35, 0xc2, 0xbf, 0xc4, 0x29, 0x09, 0x58, 0x86, 0xc3, 0x1c, 0x6e, 0x3e, 0xc5,
+	0xf4, 0x78, 0x8f, 0x31, 0xdf, 0x60, 0xa9, 0x8f, 0xed, 0xc1, 0x62, 0x0d, 0x2b, 0xab, 0x14, 0xaa,
+	0xd4, 0xe4, 0xbe, 0x4e, 0xc8, 0x47, 0x94, 0x71, 0x4d, 0x19, 0xf7, 0x14, 0x56, 0x70, 0x2e, 0xde,
+	0xa7, 0x9d, 0x70, 0xb2, 0x4b, 0xd9, 0x2c, 0x8d, 0x90, 0x5a, 0x01, 0x7e, 0xe5, 0x70, 0xd6, 0xf3,
+	0x70, 0x81, 0x60, 0x54, 0x9c, 0xe2, 0x31, 0xc8, 0xfe, 0xf7, 0x16, 0xcc, 0x1f, 0x46, 0x97, 0x71,
+	0xe0, 0xb1, 0x02, 0x63, 0x8a, 0xa7, 0x71, 0xd1, 0x06, 0x63, 0x2d, 0xbc, 0x84, 0x88, 0x6a, 0x81,
+	0x76, 0x64, 0x8a, 0x09, 0x15, 0xef, 0x7a, 0x0e, 0x61, 0x2e, 0x55, 0x47, 0xc0, 0x79, 0x77, 0x3c,
+	0x9f, 0xd6, 0x88, 0x5e, 0xa2, 0x68, 0x5f, 0x54, 0xc6, 0x21, 0xf3, 0x32, 0x68, 0xf2, 0x73, 0x7c,
+	0xb1, 0x73, 0xdd, 0x8c, 0xa4, 0x2b, 0x6f, 0x26, 0xeb, 0x0a, 0x5e, 0xc8, 0x97, 0x32, 0x9a, 0xde,
+	0x35, 0x13, 0x5a, 0x13, 0x46, 0x3e, 0xce, 0x6d, 0x8e, 0x5f, 0xbb, 0x2f, 0xc9, 0x60, 0xcd, 0xdb,
+	0x2b, 0x73, 0x90, 0x87, 0x19, 0x37, 0x0c, 0xcf, 0x5c, 0xef, 0xa5, 0xc3, 0xfa, 0xcd, 0x43, 0xe9,
+	0xff, 0x59, 0xba, 0x26, 0xce, 0x2e, 0x30, 0xc1, 0x6d, 0xc9, 0xc1, 0xd1, 0x88, 0xa5, 0xb6, 0xeb,
+	0x02, 0xad, 0x60, 0xaa, 0xfc, 0x97, 0x0f, 0x60, 0xc6, 0xd0, 0x75, 0x7d, 0x5f, 0xcc, 0x3d, 0xc6,
+	0xec, 0xf3, 0x25, 0xe8, 0x0b, 0xd2, 0xf9, 0x2a, 0x92, 0xda, 0x40, 0xb3, 0xa9, 0xc4, 0x0d, 0x7c,
+	0x73, 0x91, 0x5d, 0xe9, 0x57, 0x61, 0x58, 0x1a, 0x60, 0x2e, 0x31, 0x32, 0xef, 0x95, 0xf0, 0xd5,
+	0x4c, 0x2c, 0xbf, 0x0d, 0xe8, 0x2b, 0x4c, 0x2b, 0x8f, 0xa1, 0xaf, 0xdd, 0xbd, 0x03, 0xad, 0x4f,
+	0x9e, 0xee, 0x1f, 0x97, 0xa7, 0x6a, 0x7d, 0xe8, 0xec, 0xed, 0x1e, 0xef, 0xed, 0xd3, 0x5f, 0x0d,
+	0xba, 0xb5, 0xff, 0xe3, 0xa7, 0x87, 0xcf, 0xd8, 0xd0, 0xa8, 0x0f, 0x9d, 0xdd, 0xbd, 0xbd, 0xfd,
+	0xa7, 0xa7, 0x6c, 0x6c, 0xf4, 0x73, 0x03, 0xe6, 0x0f, 0xe2, 0x84, 0x49, 0x62, 0x01, 0xe6, 0x99,
+	0x5b, 0x93, 0xb3, 0x0c, 0xd5, 0x1c, 0x1a, 0x32, 0xcb, 0xac, 0x06, 0xfa, 0x01, 0x7a, 0x17, 0xd6,
+	0xe9, 0x72, 0x92, 0xc6, 0x49, 0x9c, 0x52, 0x29, 0xba, 0x21, 0x0f, 0xf8, 0x71, 0x44, 0x2e, 0xa4,
+	0xb7, 0x5f, 0x83, 0xb1, 0x22, 0x26, 0x91, 0x51, 0xf0, 0x86, 0xfb, 0x03, 0xe8, 0x16, 0xfa, 0x70,
+	0x0f, 0xba, 0x34, 0x55, 0xe3, 0x4a, 0xc3, 0x3d, 0xeb, 0xb0, 0xc8, 0x0f, 0x59, 0x12, 0xfc, 0x7d,
+	0x40, 0xbb, 0xbe, 0x2f, 0xf8, 0x90, 0x27, 0x6e, 0x85, 0xd6, 0xf3, 0xa6, 0x46, 0x8d, 0xa6, 0xf2,
+	0x11, 0xd5, 0x23, 0xe8, 0x89, 0x01, 0xdc, 0x81, 0x9b, 0x5d, 0x70, 0x0b, 0x92, 0xf3, 0xd6, 0x62,
+	0xf0, 0x93, 0x2a, 0xf3, 0x43, 0xfb, 0x1f, 0x0c, 0x40, 0xb4, 0xb1, 0x9b, 0xe3, 0x2c, 0xc6, 0xb8,
+	0xa2, 0x62, 0x2c, 0x6a, 0x20, 0xf4, 0x2b, 0xbc, 0xae, 0x12, 0xce, 0xfe, 0x7f, 0x51, 0x3e, 0xea,
+	0x9e, 0xa9, 0x8a, 0x39, 0xf1, 0xf9, 0x79, 0x86, 0x89, 0x98, 0x18, 0x98, 0x30, 0xa2, 0xa1, 0x91,
+	0x06, 0xad, 0x80, 0x9f, 0xce, 0x44, 0x47, 0x7b, 0x04, 0x9d, 0x14, 0x5f, 0xe2, 0x34, 0x13, 0x9d,
+	0x56, 0xd6, 0x7c, 0xd4, 0xac, 0x97, 0xf6, 0xcc, 0x52, 0x52, 0x4c, 0x0e, 0xf4, 0x4d, 0x1c, 0xf9,
+	0xe2, 0x21, 0x0b, 0x2d, 0xc0, 0x68, 0x2d, 0x15, 0x96, 0x18, 0x69, 0x6f, 0xc2, 0xd2, 0x09, 0x53,
+	0xfe, 0x12, 0xb5, 0xea, 0x14, 0x94, 0xb7, 0x3b, 0x56, 0x61, 0xb9, 0x74, 0x52, 0x80, 0x88, 0xf8,
+	0x78, 0xa0, 0x2c, 0xa2, 0x0d, 0x3a, 0xc3, 0x11, 0xe4, 0xe8, 0xa2, 0x15, 0x27, 0x69, 0xf3, 0xe3,
+	0x3c, 0x48, 0x33, 0xe2, 0x68, 0x4c, 0xe1, 0xda, 0xb7, 0x06, 0xe3, 0xd0, 0x2d, 0x6f, 0x31, 0x7e,
+	0xd9, 0x29, 0x2c, 0x4a, 0xae, 0x2a, 0x01, 0x5c, 0xb7, 0x6c, 0xa3, 0xd6, 0xb2, 0x1b, 0x92, 0xdf,
+	0x41, 0xe4, 0x85, 0x33, 0x1f, 0x3b, 0x1e, 0xe3, 0x09, 0xe6, 0x95, 0x52, 0x47, 0xdd, 0x71, 0x3d,
+	0x0f, 0x27, 0x79, 0xdf, 0xdb, 0x7e, 0x05, 0xf3, 0x42, 0x8d, 0x6a, 0xc7, 0xf9, 0xe5, 0xa1, 0x64,
+	0xd5, 0xcb, 0xf2, 0xf0, 0x48, 0xa7, 0x62, 0x2e, 0xb9, 0x60, 0xa5, 0x50, 0x57, 0x16, 0x66, 0x6d,
+	0xf9, 0x85, 0x04, 0xcb, 0x2f, 0xcb, 0x2a, 0x4e, 0xfb, 0x77, 0x0d, 0xce, 0x5d, 0x81, 0x3d, 0x53,
+	0xb4, 0x51, 0x63, 0x4d, 0x4e, 0x30, 0xeb, 0x6a, 0x8b, 0xc3, 0x66, 0xa3, 0xa2, 0x46, 0xcd, 0x9b,
+	0xd4, 0xa8, 0x75, 0xbd, 0x1a, 0xf1, 0xb4, 0x3d, 0x86, 0x25, 0xfd, 0x32, 0x85, 0xac, 0x73, 0x9c,
+	0xba, 0xac, 0x25, 0xd7, 0xbe, 0xa2, 0xac, 0x2d, 0x30, 0x1f, 0xe3, 0x10, 0x13, 0xbc, 0x1b, 0x86,
+	0x25, 0x16, 0xd0, 0x74, 0xae, 0x66, 0x4f, 0x28, 0xe5, 0x77, 0x60, 0xfc, 0x18, 0x9f, 0xcd, 0x26,
+	0x47, 0xf8, 0xb2, 0xe8, 0x1a, 0xf5, 0xa1, 0x95, 0x5d, 0xc4, 0xaf, 0x84, 0xe9, 0x22, 0x80, 0x90,
+	0xee, 0x3a, 0x59, 0x82, 0x3d, 0xe1, 0x2e, 0xbe, 0x01, 0x48, 0xfd, 0x4c, 0x90, 0x47, 0x83, 0xe3,
+	0xec, 0xcc, 0xc9, 0xae, 0x32, 0x82, 0xa7, 0x32, 0x96, 0xdf, 0x65, 0xa3, 0xfd, 0x67, 0xf8, 0xcb,
+	0x13, 0xd6, 0xb4, 0x63, 0x31, 0xd1, 0xbd, 0xa2, 0xee, 0x47, 0x1c, 0xf8, 0x59, 0x03, 0xe6, 0xf8,
+	0x09, 0xf9, 0x9e, 0x2b, 0x88, 0x78, 0xcb, 0x2c, 0x4f, 0xd6, 0x2a, 0xaf, 0x17, 0xba, 0x32, 0x51,
+	0x96, 0x43, 0x3c, 0xa1, 0x38, 0xa5, 0xf8, 0xda, 0xba, 0x26, 0xbe, 0xd2, 0xea, 0x3b, 0x98, 0x62,
+	0xfe, 0xac, 0x8d, 0xeb, 0x55, 0x11, 0x58, 0xe7, 0x64, 0xa0, 0x57, 0x42, 0xb0, 0xe8, 0x0e, 0xd5,
+	0xc5, 0xe5, 0x8e, 0xec, 0x52, 0xe9, 0x71, 0xb8, 0x5b, 0x17, 0x87, 0x41, 0x96, 0x79, 0xe7, 0xd8,
+	0x25, 0xb3, 0x14, 0xf3, 0x0c, 0x60, 0x60, 0xff, 0x85, 0x41, 0xdd, 0x6f, 0x90, 0x1e, 0xd0, 0x3e,
+	0x7a, 0x7a, 0x25, 0xcb, 0x32, 0xe7, 0x3c, 0x8d, 0xa7, 0x45, 0xdc, 0x61, 0x4b, 0x24, 0x16, 0x0c,
+	0x58, 0x81, 0x21, 0xd3, 0x06, 0xfa, 0xe4, 0x82, 0x8f, 0xe0, 0xf2, 0x77, 0x1c, 0xc5, 0xba, 0x3b,
+	0x55, 0xb4, 0x95, 0x2d, 0x8b, 0x9e, 0xb6, 0xfa, 0x52, 0xc5, 0x84, 0x91, 0xb6, 0x45, 0x3f, 0xca,
+	0x7b, 0x64, 0x72, 0x31, 0x49, 0xe3, 0x33, 0x5e, 0x53, 0xd8, 0xb7, 0xc1, 0x62, 0xed, 0x88, 0x8f,
+	0x83, 0x2c, 0x0b, 0xe2, 0x68, 0x2f, 0x8e, 0x48, 0x1a, 0x4b, 0xe5, 0xb1, 0x7f, 0x1d, 0xd6, 0x6b,
+	0x77, 0x85, 0x8e, 0xdc, 0x83, 0x76, 0xe2, 0x06, 0x69, 0xf9, 0xcd, 0x9f, 0x42, 0x3d, 0x85, 0xff,
+	0x0c, 0x67, 0x98, 0xd4, 0xc3, 0xbf, 0x03, 0xeb, 0xb5, 0xbb, 0x42, 0xa1, 0x4d, 0x58, 0xd9, 0x9d,
+	0x91, 0x38, 0x09, 0xc2, 0x58, 0xbc, 0x85, 0x91, 0x1f, 0xfe, 0xbd, 0x01, 0xab, 0x95, 0xad, 0x22,
+	0x4e, 0xf2, 0x01, 0x8f, 0xd0, 0x79, 0xe1, 0x20, 0x4a, 0xfd, 0x07, 0xfa, 0xbc, 0x28, 0x0c, 0xc5,
+	0x78, 0x5c, 0x4c, 0x45, 0x96, 0x61, 0x20, 0x0b, 0xac, 0x62, 0x28, 0xc2, 0xa4, 0x20, 0x01, 0xf0,
+	0xe5, 0xb6, 0x64, 0xa8, 0x56, 0xde, 0xcd, 0xc9, 0x46, 0x9a, 0x68, 0x06, 0x15, 0xd0, 0xe7, 0xa5,
+	0xd4, 0xd4, 0xd1, 0x54, 0x9c, 0xe0, 0x88, 0x77, 0x26, 0x07, 0xf6, 0x7b, 0xf4, 0x8d, 0x16, 0xc9,
+	0x09, 0x92, 0xb6, 0x4b, 0xf5, 0x98, 0xcd, 0xe1, 0x44, 0xf3, 0x71, 0x05, 0x96, 0xf4, 0x63, 0x9c,
+	0xe2, 0xad, 0x9d, 0x7c, 0xc8, 0xca, 0x59, 0x81, 0xe6, 0xa1, 0xb9, 0x7b, 0x74, 0xc4, 0x93, 0x26,
+	0x9a, 0x3e, 0x1d, 0x1e, 0x3f, 0x19, 0x19, 0xf4, 0xc7, 0xde, 0xd1, 0x27, 0x27, 0xf4, 0x47, 0x63,
+	0xe7, 0x9f, 0x36, 0xa0, 0x9b, 0x97, 0xdd, 0xe8, 0x0b, 0x18, 0x68, 0x6d, 0x4d, 0x24, 0x63, 0x7a,
+	0x5d, 0x6b, 0xd4, 0xba, 0x5d, 0xbf, 0x29, 0xa4, 0xf6, 0xce, 0xcf, 0xff, 0xf1, 0xdf, 0xfe, 0xa0,
+	0x61, 0xa2, 0x95, 0xed, 0xcb, 0x47, 0xdb, 0xa2, 0x9f, 0xb9, 0xcd, 0x26, 0x43, 0x6c, 0x2e, 0x86,
+	0x5e, 0xc2, 0x50, 0xef, 0x7f, 0xa2, 0xdb, 0x7a, 0xb5, 0x58, 0xc2, 0x76, 0xe7, 0x9a, 0x5d, 0x81,
+	0xee, 0x36, 0x43, 0xb7, 0x82, 0x96, 0x54, 0x74, 0x52, 0x28, 0x08, 0xb3, 0x51, 0xa2, 0xfa, 0x9a,
+	0x15, 0x49, 0x78, 0xf5, 0xaf, 0x5c, 0xad, 0xb5, 0xea, 0xcb, 0x55, 0xf1, 0xd4, 0xd5, 0x36, 0x19,
+	0x2a, 0x84, 0x46, 0x14, 0x95, 0xfa, 0xe8, 0x15, 0x7d, 0x0e, 0xdd, 0xfc, 0xe1, 0x0c, 0x5a, 0x55,
+	0x1e, 0xfe, 0xa8, 0x8f, 0x6f, 0x2c, 0xb3, 0xba, 0x21, 0x88, 0x58, 0x67, 0x90, 0x97, 0xed, 0x0a,
+	0xe4, 0x0f, 0x8c, 0x2d, 0x74, 0x04, 0xcb, 0x22, 0xea, 0x9f, 0xe1, 0x5f, 0x84, 0x92, 0x9a, 0x37,
+	0xb8, 0x0f, 0x0d, 0xf4, 0x3d, 0xe8, 0xc8, 0x77, 0x43, 0x68, 0xa5, 0xfe, 0x89, 0x92, 0xb5, 0x5a,
+	0x59, 0x17, 0xb6, 0xb5, 0x0b, 0x50, 0x3c, 0xa3, 0x41, 0xe6, 0x75, 0xef, 0x7c, 0xac, 0xb5, 0x9a,
+	0x1d, 0x01, 0x62, 0x02, 0xe3, 0xca, 0x2b, 0x1d, 0x74, 0xb7, 0x38, 0x5f, 0xfb, 0x7e, 0xe7, 0x06,
+	0x80, 0xf6, 0x0a, 0xe3, 0xdd, 0x08, 0x0d, 0x29, 0xef, 0x22, 0xfc, 0x4a, 0xb4, 0x07, 0xd0, 0x67,
+	0xd0, 0x53, 0x1e, 0xe0, 0x20, 0x65, 0x5a, 0x53, 0x7a, 0xdf, 0x63, 0x59, 0x75, 0x5b, 0x02, 0xfa,
+	0x12, 0x83, 0x3e, 0xb4, 0xbb, 0x14, 0x3a, 0x9b, 0x0d, 0x53, 0x91, 0xfc, 0x08, 0xba, 0xf9, 0x3b,
+	0x08, 0x54, 0x3c, 0x08, 0xd2, 0x5f, 0x4b, 0x58, 0x66, 0x75, 0x43, 0x40, 0x1d, 0x33, 0xa8, 0x3d,
+	0x54, 0x40, 0x45, 0x9f, 0x03, 0x14, 0x2f, 0x00, 0x72, 0xd6, 0x56, 0xde, 0x0e, 0x58, 0x6b, 0x35,
+	0x3b, 0xd2, 0x5f, 0xaa, 0xfa, 0xc9, 0xa0, 0x6e, 0x87, 0x1c, 0xdc, 0xc7, 0x30, 0x2f, 0x26, 0xea,
+	0x68, 0xb9, 0x50, 0x1a, 0xa5, 0x2f, 0x66, 0xad, 0x94, 0x97, 0x05, 0xcc, 0x45, 0x06, 0x73, 0x80,
+	0x7a, 0x14, 0xe6, 0x04, 0x93, 0x80, 0xc2, 0x08, 0x61, 0x41, 0x1f, 0x00, 0x65, 0xb9, 0x0d, 0xd7,
+	0xce, 0xae, 0xac, 0x3b, 0xd7, 0xec, 0xd6, 0xd9, 0xb0, 0xb4, 0xdd, 0x6d, 0xe1, 0x29, 0xd1, 0x6f,
+	0x40, 0x5f, 0x7d, 0x74, 0x83, 0x2c, 0x85, 0xad, 0xa5, 0x07, 0x3a, 0xd6, 0x7a, 0xed, 0x9e, 0x2e,
+	0x4b, 0xd4, 0x57, 0xd1, 0xa0, 0xcf, 0x60, 0x41, 0x19, 0x69, 0x9e, 0x5c, 0x45, 0x5e, 0xae, 0x2b,
+	0xd5, 0x51, 0xa7, 0x55, 0x3b, 0x8b, 0x5e, 0x65, 0x80, 0xc7, 0xb6, 0x06, 0x98, 0xea, 0xc9, 0x1e,
+	0xf4, 0x14, 0x18, 0x37, 0xc1, 0x5d, 0x55, 0xb6, 0xd4, 0x49, 0xe5, 0x43, 0x03, 0xfd, 0xb1, 0x01,
+	0x7d, 0x75, 0x5a, 0x8d, 0xb4, 0x6e, 0x59, 0x09, 0x8e, 0xa9, 0xee, 0xa9, 0x80, 0xec, 0x4f, 0xd9,
+	0x25, 0x9f, 0x6e, 0x1d, 0x6b, 0x4c, 0x7e, 0xa3, 0x0d, 0xe4, 0x1e, 0xa8, 0xaf, 0x27, 0xdf, 0x96,
+	0x37, 0xd5, 0x07, 0x94, 0x6f, 0xb7, 0xdf, 0xb0, 0x51, 0xf7, 0xdb, 0x87, 0x06, 0xfa, 0x80, 0xbf,
+	0xeb, 0x97, 0x79, 0x2f, 0xaa, 0xbe, 0x28, 0xb7, 0x16, 0xb5, 0x35, 0x2e, 0x8f, 0x4d, 0xe3, 0xa1,
+	0x81, 0x7e, 0x13, 0x16, 0x94, 0x6f, 0x19, 0xf7, 0xff, 0xaf, 0xdf, 0xdb, 0xf7, 0x19, 0x45, 0xef,
+	0x7c, 0x60, 0x6c, 0xd9, 0x6b, 0x1a, 0x51, 0x9a, 0x6f, 0x4e, 0xa0, 0xa7, 0x3c, 0x80, 0xce, 0x65,
+	0x50, 0x7d, 0x7b, 0x6d, 0x59, 0x75, 0x5b, 0x02, 0xd7, 0x16, 0xc3, 0x75, 0xdf, 0xbe, 0x7b, 0x2d,
+	0xa2, 0x6d, 0x96, 0xad, 0x52, 0xa9, 0x13, 0xe8, 0xab, 0x8f, 0xa2, 0x73, 0x79, 0xd5, 0xbc, 0x94,
+	0xb6, 0x96, 0xea, 0x9e, 0xfc, 0xda, 0x0f, 0x19, 0xb6, 0x2d, 0xb4, 0xc9, 0x2c, 0x99, 0x6f, 0x31,
+	0x6c, 0xde, 0xcb, 0xed, 0x37, 0x35, 0x6f, 0xa8, 0xa9, 0x14, 0x9e, 0x02, 0x14, 0x5d, 0x03, 0x54,
+	0x2a, 0x3c, 0x73, 0xb7, 0x51, 0x6d, 0x2c, 0xe8, 0xda, 0x2b, 0xeb, 0x57, 0x4a, 0xc7, 0x4f, 0x60,
+	0xb8, 0xeb, 0xfb, 0x07, 0x71, 0xf8, 0x55, 0xa0, 0x0a, 0x9b, 0xb6, 0xc7, 0x2a, 0xd4, 0x6d, 0xfa,
+	0x32, 0x88, 0x82, 0xfe, 0x82, 0xdb, 0xb4, 0xf8, 0x28, 0xcb, 0xa5, 0x52, 0x6d, 0x42, 0x58, 0x56,
+	0xdd, 0x96, 0x40, 0xf2, 0x2e, 0x43, 0x72, 0x07, 0xad, 0x6b, 0x48, 0xde, 0xa8, 0x4d, 0x8b, 0xb7,
+	0xe8, 0x53, 0x18, 0x1c, 0xc5, 0xf1, 0xcb, 0x59, 0x22, 0xa9, 0x40, 0x3a, 0xcf, 0x69, 0x97, 0xc4,
+	0x2a, 0x51, 0x66, 0xdf, 0x63, 0x90, 0xd7, 0xd1, 0x9a, 0x0e, 0xb9, 0xe8, 0xa4, 0xbc, 0x45, 0x3e,
+	0x0c, 0xb4, 0x06, 0x43, 0x2d, 0xdc, 0x3c, 0xb7, 0xa9, 0x6d, 0x45, 0x08, 0x2c, 0x5b, 0x37, 0x60,
+	0xf9, 0x02, 0x06, 0x5a, 0x0f, 0x22, 0x4f, 0xcd, 0xea, 0x7a, 0x18, 0xd6, 0xed, 0xfa, 0x4d, 0x3d,
+	0x35, 0xa3, 0x06, 0xb3, 0xa8, 0x61, 0xe4, 0x2d, 0x03, 0xe4, 0xc2, 0x38, 0xcf, 0x34, 0x72, 0xd1,
+	0x58, 0x3a, 0x67, 0xd4, 0x06, 0x44, 0x85, 0x6b, 0x5a, 0xee, 0x57, 0x40, 0x97, 0x30, 0x99, 0x96,
+	0xf6, 0x1f, 0x63, 0xfa, 0x1f, 0x08, 0x64, 0x99, 0x58, 0xf0, 0x2c, 0xaf, 0x2b, 0xad, 0x81, 0xb6,
+	0xa8, 0x87, 0x87, 0xc4, 0xbd, 0x4a, 0xf1, 0x97, 0xdb, 0x6f, 0x44, 0xe1, 0xf9, 0x56, 0x86, 0x07,
+	0x59, 0x0e, 0x6b, 0xe1, 0xa1, 0x54, 0x3f, 0x5b, 0xeb, 0xb5, 0x7b, 0x75, 0xe1, 0x41, 0x1a, 0x1d,
+	0x0a, 0x61, 0x5c, 0x29, 0xb9, 0xf3, 0x7c, 0xe5, 0xba, 0x42, 0xdd, 0xda, 0xb8, 0xfe, 0x80, 0x8e,
+	0x6d, 0x4b, 0xc7, 0x76, 0x02, 0x83, 0xc7, 0x98, 0x33, 0x8b, 0x4f, 0xcc, 0x2c, 0x3d, 0xde, 0xa8,
+	0xd3, 0x35, 0x6b, 0xb1, 0x66, 0x4f, 0x4f, 0x2d, 0xd8, 0x68, 0x0b, 0x7d, 0x0e, 0xbd, 0x27, 0x98,
+	0xc8, 0x81, 0x59, 0x9e, 0xf5, 0x95, 0x26, 0x68, 0x56, 0xdd, 0xa0, 0x6d, 0x83, 0x41, 0xb3, 0x90,
+	0x99, 0x43, 0xdb, 0xa6, 0xb3, 0x39, 0x1e, 0x19, 0x9c, 0xc0, 0x7f, 0x8b, 0x7e, 0xcc, 0x80, 0xe7,
+	0x13, 0xe1, 0x15, 0x65, 0x4a, 0xa4, 0x02, 0x5f, 0x28, 0xad, 0xd7, 0x41, 0xa6, 0x45, 0xf1, 0xf6,
+	0x1b, 0x31, 0xd8, 0x7d, 0x8b, 0x30, 0x40, 0x31, 0x2a, 0xcf, 0x15, 0x45, 0xf3, 0xd8, 0xd2, 0x6d,
+	0x54, 0x47, 0xea, 0xf6, 0xd7, 0x19, 0xfc, 0x7b, 0xe8, 0x6e, 0x01, 0x9f, 0x39, 0xe8, 0x02, 0xc1,
+	0xf6, 0x1b, 0x77, 0x4a, 0xa8, 0xfe, 0x40, 0x31, 0x26, 0xcf, 0x13, 0xaf, 0xca, 0xc0, 0xdd, 0x5a,
+	0xab, 0xd9, 0x11, 0xb8, 0x2c, 0x86, 0x6b, 0x89, 0xda, 0xd5, 0x42, 0x09, 0x1d, 0x7a, 0xc1, 0xde,
+	0x37, 0xaa, 0x23, 0xc7, 0x22, 0x97, 0x2d, 0x4f, 0x27, 0x2d, 0x54, 0xdd, 0xd2, 0xf3, 0x5b, 0x0e,
+	0x99, 0x25, 0x61, 0x2f, 0x94, 0xb2, 0x40, 0x1b, 0xb2, 0x4a, 0xdd, 0xbb, 0x76, 0xf0, 0x67, 0x59,
+	0x75, 0x27, 0xf2, 0x7c, 0xe3, 0x35, 0x2c, 0xd6, 0x54, 0xfd, 0xe8, 0x9e, 0xca, 0xeb, 0xda, 0x7a,
+	0xde, 0xb2, 0x6f, 0x3a, 0xa2, 0xf3, 0x0a, 0x21, 0x4a, 0xce, 0x94, 0x9f, 0xf1, 0x04, 0x8a, 0xd7,
+	0xb0, 0x58, 0xd3, 0x0f, 0xc8, 0x31, 0x5f, 0xdf, 0x49, 0xb0, 0xec, 0x9b, 0x8e, 0xe8, 0x98, 0xb7,
+	0xea, 0x30, 0x4f, 0x60, 0xa1, 0xd4, 0x4f, 0xc8, 0xab, 0xab, 0xfa, 0x16, 0x84, 0xf5, 0xce, 0x75,
+	0xdb, 0x02, 0xdb, 0x32, 0xc3, 0xb6, 0x80, 0x06, 0x14, 0x9b, 0x2b, 0x0f, 0x21, 0x17, 0xfa, 0x6a,
+	0x0d, 0x8f, 0x8a, 0x9c, 0xa3, 0x52, 0xff, 0x5b, 0xeb, 0xb5, 0x7b, 0x7a, 0xb2, 0x4f, 0x75, 0xae,
+	0x84, 0x82, 0x55, 0x78, 0x7c, 0xe2, 0xa6, 0x54, 0x78, 0xda, 0xa0, 0xce, 0x5a, 0xad, 0xac, 0x17,
+	0x15, 0x5e, 0xd1, 0x0d, 0xcc, 0xad, 0xa1, 0xd2, 0x57, 0xb4, 0xd6, 0x6a, 0x76, 0x38, 0x88, 0xb3,
+	0x39, 0xf6, 0xdf, 0x5c, 0xbf, 0xf5, 0x3f, 0x03, 0x00, 0x26, 0x04, 0xe1, 0x45, 0x18, 0x3b, 0x00,
+	0x00,
 }
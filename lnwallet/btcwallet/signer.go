@@ -5,6 +5,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
@@ -203,3 +204,28 @@ func (b *BtcWallet) ComputeInputScript(tx *wire.MsgTx,
 // A compile time check to ensure that BtcWallet implements the Signer
 // interface.
 var _ lnwallet.Signer = (*BtcWallet)(nil)
+
+// SignMessage attempts to sign a target message with the private key that
+// corresponds to the passed public key. If the target private key is unable to
+// be found, then an error will be returned. The actual digest signed is the
+// double SHA-256 of the passed message.
+//
+// NOTE: This is a part of the MessageSigner interface.
+func (b *BtcWallet) SignMessage(pubKey *btcec.PublicKey,
+	msg []byte) (*btcec.Signature, error) {
+
+	// First attempt to fetch the private key which corresponds to the
+	// specified public key.
+	privKey, err := b.fetchPrivKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Double hash and sign the data.
+	msgDigest := chainhash.DoubleHashB(msg)
+	return privKey.Sign(msgDigest)
+}
+
+// A compile time check to ensure that BtcWallet implements the MessageSigner
+// interface.
+var _ lnwallet.MessageSigner = (*BtcWallet)(nil)
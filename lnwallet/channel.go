@@ -1724,6 +1724,116 @@ func (lc *LightningChannel) SettleHTLC(preimage [32]byte) (uint32, error) {
 	return targetHTLC.Index, nil
 }
 
+// SettleHTLCByIndex attempts to settle the outstanding received HTLC located
+// at the passed index within the remote party's update log. Unlike
+// SettleHTLC, this allows a particular HTLC to be targeted when several
+// outstanding HTLCs share the same payment hash. In the case the supplied
+// preimage doesn't match the HTLC, or the HTLC has already been settled or
+// cancelled, an error is returned.
+func (lc *LightningChannel) SettleHTLCByIndex(preimage [32]byte,
+	logIndex uint32) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	paymentHash := fastsha256.Sum256(preimage[:])
+	targetHTLC, err := lc.unresolvedHTLC(logIndex)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(targetHTLC.RHash[:], paymentHash[:]) {
+		return fmt.Errorf("invalid payment hash")
+	}
+	lc.removeUnresolvedHTLC(targetHTLC)
+
+	pd := &PaymentDescriptor{
+		Amount:      targetHTLC.Amount,
+		RPreimage:   preimage,
+		Index:       lc.ourLogCounter,
+		ParentIndex: targetHTLC.Index,
+		EntryType:   Settle,
+	}
+
+	lc.ourUpdateLog.PushBack(pd)
+	lc.ourLogCounter++
+
+	return nil
+}
+
+// CancelHTLCByIndex attempts to cancel the outstanding received HTLC located
+// at the passed index within the remote party's update log. Unlike
+// CancelHTLC, this allows a particular HTLC to be targeted when several
+// outstanding HTLCs share the same payment hash. If the HTLC has already
+// been settled or cancelled, an error is returned.
+func (lc *LightningChannel) CancelHTLCByIndex(logIndex uint32) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	addEntry, err := lc.unresolvedHTLC(logIndex)
+	if err != nil {
+		return err
+	}
+	lc.removeUnresolvedHTLC(addEntry)
+
+	pd := &PaymentDescriptor{
+		Amount:      addEntry.Amount,
+		RHash:       addEntry.RHash,
+		ParentIndex: addEntry.Index,
+		Index:       lc.ourLogCounter,
+		EntryType:   Cancel,
+	}
+
+	lc.ourUpdateLog.PushBack(pd)
+	lc.ourLogCounter++
+
+	return nil
+}
+
+// unresolvedHTLC returns the received HTLC at the passed index within the
+// remote party's update log. An error is returned if the HTLC doesn't exist,
+// or has already been settled or cancelled.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) unresolvedHTLC(
+	logIndex uint32) (*PaymentDescriptor, error) {
+
+	addEntry, ok := lc.theirLogIndex[logIndex]
+	if !ok {
+		return nil, fmt.Errorf("non existant log entry")
+	}
+	htlc := addEntry.Value.(*PaymentDescriptor)
+
+	for _, pd := range lc.rHashMap[htlc.RHash] {
+		if pd == htlc {
+			return htlc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("htlc already resolved")
+}
+
+// removeUnresolvedHTLC removes the passed received HTLC from the set of
+// HTLCs which are yet to be settled or cancelled.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) removeUnresolvedHTLC(htlc *PaymentDescriptor) {
+	unresolved := lc.rHashMap[htlc.RHash]
+	for i, pd := range unresolved {
+		if pd != htlc {
+			continue
+		}
+
+		unresolved = append(unresolved[:i], unresolved[i+1:]...)
+		break
+	}
+
+	if len(unresolved) == 0 {
+		delete(lc.rHashMap, htlc.RHash)
+	} else {
+		lc.rHashMap[htlc.RHash] = unresolved
+	}
+}
+
 // ReceiveHTLCSettle attempts to settle an existing outgoing HTLC indexed by an
 // index into the local log. If the specified index doesn't exist within the
 // log, and error is returned. Similarly if the preimage is invalid w.r.t to
@@ -1253,3 +1253,82 @@ func TestCancelHTLC(t *testing.T) {
 			bobChannel.channelState.TheirBalance, expectedBalance)
 	}
 }
+
+// TestResolveHTLCByIndex tests that HTLCs sharing the same payment hash can
+// each be settled or cancelled individually by their index within the log.
+func TestResolveHTLCByIndex(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(5)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Alice will send Bob two HTLCs of different amounts, both of which
+	// share the same payment hash.
+	var preimage [32]byte
+	copy(preimage[:], bytes.Repeat([]byte{0xbb}, 32))
+	paymentHash := fastsha256.Sum256(preimage[:])
+
+	amts := []btcutil.Amount{
+		btcutil.SatoshiPerBitcoin, btcutil.SatoshiPerBitcoin * 2,
+	}
+	var bobIndexes []uint32
+	for _, amt := range amts {
+		htlc := &lnwire.HTLCAddRequest{
+			RedemptionHashes: [][32]byte{paymentHash},
+			Amount:           amt,
+			Expiry:           10,
+		}
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("unable to add alice htlc: %v", err)
+		}
+		index, err := bobChannel.ReceiveHTLC(htlc)
+		if err != nil {
+			t.Fatalf("unable to add bob htlc: %v", err)
+		}
+		bobIndexes = append(bobIndexes, index)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to create new commitment state: %v", err)
+	}
+
+	// Bob will cancel the second, larger HTLC, and settle the first. An
+	// HTLC can only be resolved once, so attempting to settle the
+	// cancelled HTLC should fail.
+	if err := bobChannel.CancelHTLCByIndex(bobIndexes[1]); err != nil {
+		t.Fatalf("unable to cancel htlc: %v", err)
+	}
+	if err := aliceChannel.ReceiveCancelHTLC(bobIndexes[1]); err != nil {
+		t.Fatalf("unable to recv htlc cancel: %v", err)
+	}
+	err = bobChannel.SettleHTLCByIndex(preimage, bobIndexes[1])
+	if err == nil {
+		t.Fatalf("cancelled htlc shouldn't be settled")
+	}
+
+	var wrongPreimage [32]byte
+	err = bobChannel.SettleHTLCByIndex(wrongPreimage, bobIndexes[0])
+	if err == nil {
+		t.Fatalf("htlc settled with invalid preimage")
+	}
+	err = bobChannel.SettleHTLCByIndex(preimage, bobIndexes[0])
+	if err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	err = aliceChannel.ReceiveHTLCSettle(preimage, bobIndexes[0])
+	if err != nil {
+		t.Fatalf("unable to recv htlc settle: %v", err)
+	}
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to create new commitment: %v", err)
+	}
+
+	// Only the amount of the settled HTLC should have been transferred
+	// to Bob.
+	expectedBalance := btcutil.Amount(btcutil.SatoshiPerBitcoin * 6)
+	if bobChannel.channelState.OurBalance != expectedBalance {
+		t.Fatalf("balance is wrong: expected %v, got %v",
+			expectedBalance, bobChannel.channelState.OurBalance)
+	}
+}
@@ -142,6 +142,12 @@ type WalletController interface {
 	// WalletController restarts.
 	FetchRootKey() (*btcec.PrivateKey, error)
 
+	// SignMessage attempts to sign a target message with the private key
+	// that corresponds to the passed public key. This is used to sign the
+	// portion of channel announcements which proves ownership of the
+	// multi-sig keys within a funding output.
+	SignMessage(pubKey *btcec.PublicKey, msg []byte) (*btcec.Signature, error)
+
 	// SendOutputs funds, signs, and broadcasts a Bitcoin transaction
 	// paying out to the specified outputs. In the case the wallet has
 	// insufficient funds, or the outputs are non-standard, an error
@@ -287,6 +293,18 @@ type Signer interface {
 	ComputeInputScript(tx *wire.MsgTx, signDesc *SignDescriptor) (*InputScript, error)
 }
 
+// MessageSigner represents an abstract object capable of signing arbitrary
+// messages. The capabilities of this interface are used to sign announcements
+// to the network, or just arbitrary messages that leverage the wallet's keys
+// to attest to some message.
+type MessageSigner interface {
+	// SignMessage attempts to sign a target message with the private key
+	// that corresponds to the passed public key. If the target private key
+	// is unable to be found, then an error will be returned. The actual
+	// digest signed is the double SHA-256 of the passed message.
+	SignMessage(pubKey *btcec.PublicKey, msg []byte) (*btcec.Signature, error)
+}
+
 // WalletDriver represents a "driver" for a particular concrete
 // WalletController implementation. A driver is identified by a globally unique
 // string identifier along with a 'New()' method which is responsible for
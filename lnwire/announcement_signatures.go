@@ -0,0 +1,106 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// AnnounceSignatures is a direct message between two endpoints of a channel
+// and serves as an opt-in mechanism to allow the announcement of the channel
+// to the rest of the network. It contains the necessary signatures by the
+// sender to construct the ChannelAnnouncement message. Once each side has
+// received the other's half of the proof, either of them is able to assemble
+// the fully authenticated announcement and broadcast it.
+type AnnounceSignatures struct {
+	// ChannelID is the unique description of the funding transaction the
+	// signatures are for.
+	ChannelID ChannelID
+
+	// NodeSignature is the signature which contains the signed
+	// announcement data by the sender's identity key. This signature
+	// proves that the sender is willing to route payments over the
+	// channel.
+	NodeSignature *btcec.Signature
+
+	// BitcoinSignature is the signature by the sender's funding key over
+	// its identity key. This signature proves that the sender controls
+	// one of the keys of the 2-of-2 funding output.
+	BitcoinSignature *btcec.Signature
+}
+
+// A compile time check to ensure AnnounceSignatures implements the
+// lnwire.Message interface.
+var _ Message = (*AnnounceSignatures)(nil)
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the AnnounceSignatures are valid.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures) Validate() error {
+	// The signatures can only be verified once they're combined with the
+	// rest of the channel announcement, which is done by the discovery
+	// service.
+	return nil
+}
+
+// Decode deserializes a serialized AnnounceSignatures stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures) Decode(r io.Reader, pver uint32) error {
+	err := readElements(r,
+		&a.ChannelID,
+		&a.NodeSignature,
+		&a.BitcoinSignature,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Encode serializes the target AnnounceSignatures into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		a.ChannelID,
+		a.NodeSignature,
+		a.BitcoinSignature,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures) Command() uint32 {
+	return CmdAnnounceSignatures
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *AnnounceSignatures) MaxPayloadLength(pver uint32) uint32 {
+	var length uint32
+
+	// ChannelID - 8 bytes
+	length += 8
+
+	// NodeSignature - 64 bytes
+	length += 64
+
+	// BitcoinSignature - 64 bytes
+	length += 64
+
+	return length
+}
@@ -0,0 +1,40 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAnnounceSignaturesEncodeDecode(t *testing.T) {
+	as := &AnnounceSignatures{
+		ChannelID:        someChannelID,
+		NodeSignature:    someSig,
+		BitcoinSignature: someSig,
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := as.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode AnnounceSignatures: %v", err)
+	}
+
+	// Ensure the max payload estimate is correct.
+	serializedLength := uint32(b.Len())
+	if serializedLength != as.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, as.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	as2 := &AnnounceSignatures{}
+	if err := as2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode AnnounceSignatures: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(as, as2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			as, as2)
+	}
+}
@@ -176,12 +176,12 @@ func (c *ChannelAnnouncement) MaxPayloadLength(pver uint32) uint32 {
 // DataToSign is used to retrieve part of the announcement message which
 // should be signed.
 func (c *ChannelAnnouncement) DataToSign() ([]byte, error) {
-	// We should not include the signatures itself.
+	// We should not include any of the signatures themselves. As the
+	// bitcoin signatures are also omitted, each node is able to generate
+	// its half of the announcement proof independently of the other.
 	var w bytes.Buffer
 	err := writeElements(&w,
 		c.ChannelID,
-		c.FirstBitcoinSig,
-		c.SecondBitcoinSig,
 		c.FirstNodeID,
 		c.SecondNodeID,
 		c.FirstBitcoinKey,
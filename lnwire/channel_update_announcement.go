@@ -38,6 +38,11 @@ type ChannelUpdateAnnouncement struct {
 	// HtlcMinimumMstat is the minimum HTLC value which will be accepted.
 	HtlcMinimumMstat uint32
 
+	// HtlcMaximumMstat is the maximum HTLC value which will be accepted.
+	// A value of zero indicates that there's no limit beyond the capacity
+	// of the channel.
+	HtlcMaximumMstat uint32
+
 	// FeeBaseMstat...
 	FeeBaseMstat uint32
 
@@ -77,6 +82,7 @@ func (c *ChannelUpdateAnnouncement) Decode(r io.Reader, pver uint32) error {
 		&c.Flags,
 		&c.Expiry,
 		&c.HtlcMinimumMstat,
+		&c.HtlcMaximumMstat,
 		&c.FeeBaseMstat,
 		&c.FeeProportionalMillionths,
 	)
@@ -99,6 +105,7 @@ func (c *ChannelUpdateAnnouncement) Encode(w io.Writer, pver uint32) error {
 		c.Flags,
 		c.Expiry,
 		c.HtlcMinimumMstat,
+		c.HtlcMaximumMstat,
 		c.FeeBaseMstat,
 		c.FeeProportionalMillionths,
 	)
@@ -142,6 +149,9 @@ func (c *ChannelUpdateAnnouncement) MaxPayloadLength(pver uint32) uint32 {
 	// HtlcMinimumMstat - 4 bytes
 	length += 4
 
+	// HtlcMaximumMstat - 4 bytes
+	length += 4
+
 	// FeeBaseMstat - 4 bytes
 	length += 4
 
@@ -163,6 +173,7 @@ func (c *ChannelUpdateAnnouncement) DataToSign() ([]byte, error) {
 		c.Flags,
 		c.Expiry,
 		c.HtlcMinimumMstat,
+		c.HtlcMaximumMstat,
 		c.FeeBaseMstat,
 		c.FeeProportionalMillionths,
 	)
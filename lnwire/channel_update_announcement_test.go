@@ -14,6 +14,7 @@ func TestChannelUpdateAnnouncementEncodeDecode(t *testing.T) {
 		Flags:                     maxUint16,
 		Expiry:                    maxUint16,
 		HtlcMinimumMstat:          maxUint32,
+		HtlcMaximumMstat:          maxUint32,
 		FeeBaseMstat:              maxUint32,
 		FeeProportionalMillionths: maxUint32,
 	}
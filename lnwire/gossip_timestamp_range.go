@@ -0,0 +1,79 @@
+package lnwire
+
+import "io"
+
+// GossipTimestampRange is a message that allows the sender to restrict the
+// set of future gossip announcements sent by the receiver. Nodes should send
+// this if they wish to receive gossip messages, as by default no gossip is
+// relayed to a peer until a filter has been received. The receiver will then
+// send all known announcements with timestamps within the specified range,
+// followed by any new announcements within the range as they arrive.
+type GossipTimestampRange struct {
+	// FirstTimestamp is the timestamp of the earliest announcement message
+	// that should be sent by the receiver.
+	FirstTimestamp uint32
+
+	// TimestampRange is the horizon beyond the FirstTimestamp that any
+	// announcement messages should be sent for. The receiving node MUST
+	// NOT send any announcements that have a timestamp greater than
+	// FirstTimestamp + TimestampRange.
+	TimestampRange uint32
+}
+
+// A compile time check to ensure GossipTimestampRange implements the
+// lnwire.Message interface.
+var _ Message = (*GossipTimestampRange)(nil)
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the GossipTimestampRange are valid.
+//
+// This is part of the lnwire.Message interface.
+func (g *GossipTimestampRange) Validate() error {
+	return nil
+}
+
+// Decode deserializes a serialized GossipTimestampRange stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (g *GossipTimestampRange) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&g.FirstTimestamp,
+		&g.TimestampRange,
+	)
+}
+
+// Encode serializes the target GossipTimestampRange into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (g *GossipTimestampRange) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		g.FirstTimestamp,
+		g.TimestampRange,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (g *GossipTimestampRange) Command() uint32 {
+	return CmdGossipTimestampRange
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (g *GossipTimestampRange) MaxPayloadLength(pver uint32) uint32 {
+	var length uint32
+
+	// FirstTimestamp - 4 bytes
+	length += 4
+
+	// TimestampRange - 4 bytes
+	length += 4
+
+	return length
+}
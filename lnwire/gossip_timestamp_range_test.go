@@ -0,0 +1,39 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestGossipTimestampRangeEncodeDecode(t *testing.T) {
+	g := &GossipTimestampRange{
+		FirstTimestamp: 1490000000,
+		TimestampRange: 3600,
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := g.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode GossipTimestampRange: %v", err)
+	}
+
+	// Ensure the max payload estimate is correct.
+	serializedLength := uint32(b.Len())
+	if serializedLength != g.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, g.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	g2 := &GossipTimestampRange{}
+	if err := g2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode GossipTimestampRange: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(g, g2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			g, g2)
+	}
+}
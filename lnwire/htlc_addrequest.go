@@ -20,7 +20,7 @@ type HTLCAddRequest struct {
 	// is binded to.
 	ChannelPoint *wire.OutPoint
 
-	// Expiry is the number of blocks after which this HTLC should expire.
+	// Expiry is the absolute block height at which this HTLC expires.
 	// It is the receiver's duty to ensure that the outgoing HTLC has a
 	// sufficient expiry value to allow her to redeem the incmoing HTLC.
 	Expiry uint32
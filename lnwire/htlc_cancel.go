@@ -41,6 +41,18 @@ const (
 	// IncorrectValue indicates that the HTLC ultimately extended to the
 	// destination did not match the value that was expected.
 	IncorrectValue = 5
+
+	// TemporaryChannelFailure indicates that the HTLC was rejected due to
+	// a transient condition at a node within the route, such as the
+	// sending peer exceeding its HTLC rate limits, or the incoming channel
+	// having too many concurrent pending forwards. The payment may succeed
+	// if retried at a later time.
+	TemporaryChannelFailure = 6
+
+	// MPPTimeout indicates that the destination received a shard of a
+	// multi-path payment, yet the remainder of the payment didn't arrive
+	// before the destination gave up waiting for it.
+	MPPTimeout = 7
 )
 
 // String returns a human-readable version of the CancelReason type.
@@ -66,11 +78,27 @@ func (c CancelReason) String() string {
 	case IncorrectValue:
 		return "IncorrectValue: htlc value was wrong"
 
+	case TemporaryChannelFailure:
+		return "TemporaryChannelFailure: htlc temporarily rejected, " +
+			"retry later"
+
+	case MPPTimeout:
+		return "MPPTimeout: the remainder of the multi-path payment " +
+			"didn't arrive in time"
+
 	default:
 		return "unknown reason"
 	}
 }
 
+// Error returns a human-readable version of the CancelReason, allowing a
+// CancelReason to be returned directly as an error.
+//
+// NOTE: Part of the error interface.
+func (c CancelReason) Error() string {
+	return c.String()
+}
+
 // CancelHTLC is sent by Alice to Bob in order to remove a previously added
 // HTLC. Upon receipt of an CancelHTLC the HTLC should be removed from the next
 // commitment transaction, with the CancelHTLC propagated backwards in the
@@ -82,6 +82,12 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(b[:]); err != nil {
 			return err
 		}
+	case FundingFlag:
+		var b [1]byte
+		b[0] = uint8(e)
+		if _, err := w.Write(b[:]); err != nil {
+			return err
+		}
 	case uint16:
 		var b [2]byte
 		binary.BigEndian.PutUint16(b[:], uint16(e))
@@ -303,6 +309,40 @@ func writeElement(w io.Writer, element interface{}) error {
 		if err != nil {
 			return err
 		}
+	case []ChannelID:
+		// Enforce a max number of channel IDs within a single slice.
+		numChanIDs := len(e)
+		if numChanIDs > MaxShortChanIDsPerMsg {
+			return fmt.Errorf("too many channel IDs: %v", numChanIDs)
+		}
+
+		// First write out the number of elements in the slice as a
+		// length prefix, followed by each channel ID in series.
+		if err := writeElement(w, uint16(numChanIDs)); err != nil {
+			return err
+		}
+		for _, chanID := range e {
+			if err := writeElement(w, chanID); err != nil {
+				return err
+			}
+		}
+	case []ChanUpdateTimestamps:
+		numTimestamps := len(e)
+		if numTimestamps > MaxShortChanIDsPerMsg {
+			return fmt.Errorf("too many timestamps: %v",
+				numTimestamps)
+		}
+
+		if err := writeElement(w, uint16(numTimestamps)); err != nil {
+			return err
+		}
+		for _, timestamps := range e {
+			err := writeElements(w, timestamps.Timestamp1,
+				timestamps.Timestamp2)
+			if err != nil {
+				return err
+			}
+		}
 	case ChannelID:
 		// Check that field fit in 3 bytes and write the blockHeight
 		if e.BlockHeight > ((1 << 24) - 1) {
@@ -390,6 +430,12 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 		*e = b[0]
+	case *FundingFlag:
+		var b [1]uint8
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		*e = FundingFlag(b[0])
 	case *CancelReason:
 		var b [2]byte
 		if _, err := io.ReadFull(r, b[:]); err != nil {
@@ -627,6 +673,41 @@ func readElement(r io.Reader, element interface{}) error {
 		if err != nil {
 			return err
 		}
+	case *[]ChannelID:
+		var numChanIDs uint16
+		if err := readElement(r, &numChanIDs); err != nil {
+			return err
+		}
+		if numChanIDs > MaxShortChanIDsPerMsg {
+			return fmt.Errorf("too many channel IDs: %v", numChanIDs)
+		}
+
+		chanIDs := make([]ChannelID, numChanIDs)
+		for i := range chanIDs {
+			if err := readElement(r, &chanIDs[i]); err != nil {
+				return err
+			}
+		}
+		*e = chanIDs
+	case *[]ChanUpdateTimestamps:
+		var numTimestamps uint16
+		if err := readElement(r, &numTimestamps); err != nil {
+			return err
+		}
+		if numTimestamps > MaxShortChanIDsPerMsg {
+			return fmt.Errorf("too many timestamps: %v",
+				numTimestamps)
+		}
+
+		timestamps := make([]ChanUpdateTimestamps, numTimestamps)
+		for i := range timestamps {
+			err := readElements(r, &timestamps[i].Timestamp1,
+				&timestamps[i].Timestamp2)
+			if err != nil {
+				return err
+			}
+		}
+		*e = timestamps
 	case *ChannelID:
 		var blockHeight [4]byte
 		if _, err = io.ReadFull(r, blockHeight[1:]); err != nil {
@@ -51,6 +51,14 @@ const (
 	CmdChannelAnnoucmentMessage       = uint32(5000)
 	CmdChannelUpdateAnnoucmentMessage = uint32(5010)
 	CmdNodeAnnoucmentMessage          = uint32(5020)
+	CmdAnnounceSignatures             = uint32(5030)
+
+	// Commands for querying the channel graph of a peer.
+	CmdQueryChannelRange    = uint32(5040)
+	CmdReplyChannelRange    = uint32(5050)
+	CmdQueryShortChanIDs    = uint32(5060)
+	CmdReplyShortChanIDsEnd = uint32(5070)
+	CmdGossipTimestampRange = uint32(5080)
 
 	// Commands for connection keep-alive.
 	CmdPing = uint32(6000)
@@ -122,6 +130,18 @@ func makeEmptyMessage(command uint32) (Message, error) {
 		msg = &ChannelUpdateAnnouncement{}
 	case CmdNodeAnnoucmentMessage:
 		msg = &NodeAnnouncement{}
+	case CmdAnnounceSignatures:
+		msg = &AnnounceSignatures{}
+	case CmdQueryChannelRange:
+		msg = &QueryChannelRange{}
+	case CmdReplyChannelRange:
+		msg = &ReplyChannelRange{}
+	case CmdQueryShortChanIDs:
+		msg = &QueryShortChanIDs{}
+	case CmdReplyShortChanIDsEnd:
+		msg = &ReplyShortChanIDsEnd{}
+	case CmdGossipTimestampRange:
+		msg = &GossipTimestampRange{}
 	case CmdPing:
 		msg = &Ping{}
 	case CmdPong:
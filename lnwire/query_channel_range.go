@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// QueryChannelRange is a message sent by a node in order to query the
+// receiving node of the set of open channels they know of with a funding
+// transaction confirmed within the specified block range. The receiver
+// responds with one or more ReplyChannelRange messages, allowing the sender
+// to determine which of the channels it lacks, or holds outdated policies
+// for.
+type QueryChannelRange struct {
+	// FirstBlockHeight is the first block in the query range. The
+	// responder should send all the channels within this range, up to and
+	// including FirstBlockHeight+NumBlocks-1.
+	FirstBlockHeight uint32
+
+	// NumBlocks is the number of blocks beyond the first block that
+	// channels should be sent for.
+	NumBlocks uint32
+}
+
+// A compile time check to ensure QueryChannelRange implements the
+// lnwire.Message interface.
+var _ Message = (*QueryChannelRange)(nil)
+
+// LastBlockHeight returns the last block height covered by the range of a
+// QueryChannelRange message.
+func (q *QueryChannelRange) LastBlockHeight() uint32 {
+	// Handle overflows by casting to uint64.
+	lastBlockHeight := uint64(q.FirstBlockHeight) + uint64(q.NumBlocks) - 1
+	if lastBlockHeight > (1<<32)-1 {
+		return (1 << 32) - 1
+	}
+	return uint32(lastBlockHeight)
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the QueryChannelRange are valid.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Validate() error {
+	if q.NumBlocks == 0 {
+		return fmt.Errorf("query must span at least one block")
+	}
+
+	return nil
+}
+
+// Decode deserializes a serialized QueryChannelRange stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&q.FirstBlockHeight,
+		&q.NumBlocks,
+	)
+}
+
+// Encode serializes the target QueryChannelRange into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		q.FirstBlockHeight,
+		q.NumBlocks,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) Command() uint32 {
+	return CmdQueryChannelRange
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryChannelRange) MaxPayloadLength(pver uint32) uint32 {
+	var length uint32
+
+	// FirstBlockHeight - 4 bytes
+	length += 4
+
+	// NumBlocks - 4 bytes
+	length += 4
+
+	return length
+}
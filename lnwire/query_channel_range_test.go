@@ -0,0 +1,39 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestQueryChannelRangeEncodeDecode(t *testing.T) {
+	q := &QueryChannelRange{
+		FirstBlockHeight: 100,
+		NumBlocks:        2016,
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := q.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode QueryChannelRange: %v", err)
+	}
+
+	// Ensure the max payload estimate is correct.
+	serializedLength := uint32(b.Len())
+	if serializedLength != q.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, q.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	q2 := &QueryChannelRange{}
+	if err := q2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode QueryChannelRange: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(q, q2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			q, q2)
+	}
+}
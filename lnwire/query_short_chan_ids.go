@@ -0,0 +1,64 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// QueryShortChanIDs is a message that allows the sender to query a set of
+// channels by their channel IDs. The receiver responds with the channel
+// announcement, the latest channel updates, and the node announcements of
+// each channel it knows of, followed by a ReplyShortChanIDsEnd message.
+type QueryShortChanIDs struct {
+	// ShortChanIDs is a slice of the channels which are being queried.
+	ShortChanIDs []ChannelID
+}
+
+// A compile time check to ensure QueryShortChanIDs implements the
+// lnwire.Message interface.
+var _ Message = (*QueryShortChanIDs)(nil)
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the QueryShortChanIDs are valid.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryShortChanIDs) Validate() error {
+	if len(q.ShortChanIDs) == 0 {
+		return fmt.Errorf("query must include at least one channel")
+	}
+
+	return nil
+}
+
+// Decode deserializes a serialized QueryShortChanIDs stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryShortChanIDs) Decode(r io.Reader, pver uint32) error {
+	return readElements(r, &q.ShortChanIDs)
+}
+
+// Encode serializes the target QueryShortChanIDs into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryShortChanIDs) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w, q.ShortChanIDs)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryShortChanIDs) Command() uint32 {
+	return CmdQueryShortChanIDs
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (q *QueryShortChanIDs) MaxPayloadLength(pver uint32) uint32 {
+	// ShortChanIDs - 2 byte length prefix + 8 bytes per channel
+	return 2 + MaxShortChanIDsPerMsg*8
+}
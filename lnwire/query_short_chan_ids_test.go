@@ -0,0 +1,38 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestQueryShortChanIDsEncodeDecode(t *testing.T) {
+	q := &QueryShortChanIDs{
+		ShortChanIDs: []ChannelID{someChannelID},
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := q.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode QueryShortChanIDs: %v", err)
+	}
+
+	// Ensure the message doesn't exceed the max payload estimate.
+	serializedLength := uint32(b.Len())
+	if serializedLength > q.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, q.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	q2 := &QueryShortChanIDs{}
+	if err := q2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode QueryShortChanIDs: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(q, q2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			q, q2)
+	}
+}
@@ -0,0 +1,125 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxShortChanIDsPerMsg is the maximum number of channel IDs which may be
+// included within a single ReplyChannelRange or QueryShortChanIDs message.
+const MaxShortChanIDsPerMsg = 8000
+
+// ChanUpdateTimestamps houses the timestamps of the latest channel updates
+// for both directions of a channel. A timestamp of zero indicates that no
+// update is known for that direction.
+type ChanUpdateTimestamps struct {
+	// Timestamp1 is the timestamp of the latest update for the direction
+	// of the channel controlled by the first node.
+	Timestamp1 uint32
+
+	// Timestamp2 is the timestamp of the latest update for the direction
+	// of the channel controlled by the second node.
+	Timestamp2 uint32
+}
+
+// ReplyChannelRange is the response to the QueryChannelRange message. It
+// includes the original query, and the next streaming chunk of the channels
+// within that range, along with the timestamps of their latest updates. The
+// timestamps allow the receiver to determine whether it holds outdated
+// policies for channels it already knows of.
+type ReplyChannelRange struct {
+	// QueryChannelRange is the corresponding query to this response.
+	QueryChannelRange
+
+	// Complete denotes if this is the conclusion of the set of streaming
+	// responses to the original query.
+	Complete uint8
+
+	// ShortChanIDs is a slice of the channels within this chunk of the
+	// response.
+	ShortChanIDs []ChannelID
+
+	// Timestamps houses the timestamps of the latest channel updates for
+	// each of the channels within ShortChanIDs, in the same order.
+	Timestamps []ChanUpdateTimestamps
+}
+
+// A compile time check to ensure ReplyChannelRange implements the
+// lnwire.Message interface.
+var _ Message = (*ReplyChannelRange)(nil)
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the ReplyChannelRange are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Validate() error {
+	if len(c.ShortChanIDs) != len(c.Timestamps) {
+		return fmt.Errorf("number of timestamps (%v) doesn't match "+
+			"number of channels (%v)", len(c.Timestamps),
+			len(c.ShortChanIDs))
+	}
+
+	return nil
+}
+
+// Decode deserializes a serialized ReplyChannelRange stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
+	if err := c.QueryChannelRange.Decode(r, pver); err != nil {
+		return err
+	}
+
+	return readElements(r,
+		&c.Complete,
+		&c.ShortChanIDs,
+		&c.Timestamps,
+	)
+}
+
+// Encode serializes the target ReplyChannelRange into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Encode(w io.Writer, pver uint32) error {
+	if err := c.QueryChannelRange.Encode(w, pver); err != nil {
+		return err
+	}
+
+	return writeElements(w,
+		c.Complete,
+		c.ShortChanIDs,
+		c.Timestamps,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) Command() uint32 {
+	return CmdReplyChannelRange
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyChannelRange) MaxPayloadLength(pver uint32) uint32 {
+	var length uint32
+
+	// QueryChannelRange - 8 bytes
+	length += c.QueryChannelRange.MaxPayloadLength(pver)
+
+	// Complete - 1 byte
+	length++
+
+	// ShortChanIDs - 2 byte length prefix + 8 bytes per channel
+	length += 2 + MaxShortChanIDsPerMsg*8
+
+	// Timestamps - 2 byte length prefix + 8 bytes per channel
+	length += 2 + MaxShortChanIDsPerMsg*8
+
+	return length
+}
@@ -0,0 +1,47 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReplyChannelRangeEncodeDecode(t *testing.T) {
+	r := &ReplyChannelRange{
+		QueryChannelRange: QueryChannelRange{
+			FirstBlockHeight: 100,
+			NumBlocks:        2016,
+		},
+		Complete:     1,
+		ShortChanIDs: []ChannelID{someChannelID, someChannelID},
+		Timestamps: []ChanUpdateTimestamps{
+			{Timestamp1: 1, Timestamp2: 2},
+			{Timestamp1: 3, Timestamp2: 0},
+		},
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := r.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode ReplyChannelRange: %v", err)
+	}
+
+	// Ensure the message doesn't exceed the max payload estimate.
+	serializedLength := uint32(b.Len())
+	if serializedLength > r.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, r.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	r2 := &ReplyChannelRange{}
+	if err := r2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode ReplyChannelRange: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(r, r2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			r, r2)
+	}
+}
@@ -0,0 +1,62 @@
+package lnwire
+
+import "io"
+
+// ReplyShortChanIDsEnd is a message that marks the end of a streaming
+// response to an initial QueryShortChanIDs message. This marks that the
+// receiver of the original QueryShortChanIDs for the target chain has either
+// sent all adequate responses it knows of, or doesn't know of any short chan
+// ID's for the target chain.
+type ReplyShortChanIDsEnd struct {
+	// Complete will be set to 0 if we don't know of the chain that the
+	// remote peer sent their query for. Otherwise, we'll set this to 1 in
+	// order to indicate that we've sent all known responses for the prior
+	// set of short chan ID's in the corresponding QueryShortChanIDs
+	// message.
+	Complete uint8
+}
+
+// A compile time check to ensure ReplyShortChanIDsEnd implements the
+// lnwire.Message interface.
+var _ Message = (*ReplyShortChanIDsEnd)(nil)
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the ReplyShortChanIDsEnd are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyShortChanIDsEnd) Validate() error {
+	return nil
+}
+
+// Decode deserializes a serialized ReplyShortChanIDsEnd stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyShortChanIDsEnd) Decode(r io.Reader, pver uint32) error {
+	return readElements(r, &c.Complete)
+}
+
+// Encode serializes the target ReplyShortChanIDsEnd into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyShortChanIDsEnd) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w, c.Complete)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyShortChanIDsEnd) Command() uint32 {
+	return CmdReplyShortChanIDsEnd
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for this message
+// observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ReplyShortChanIDsEnd) MaxPayloadLength(pver uint32) uint32 {
+	// Complete - 1 byte
+	return 1
+}
@@ -0,0 +1,38 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReplyShortChanIDsEndEncodeDecode(t *testing.T) {
+	r := &ReplyShortChanIDsEnd{
+		Complete: 1,
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := r.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode ReplyShortChanIDsEnd: %v", err)
+	}
+
+	// Ensure the max payload estimate is correct.
+	serializedLength := uint32(b.Len())
+	if serializedLength != r.MaxPayloadLength(0) {
+		t.Fatalf("payload length estimate is incorrect: expected %v "+
+			"got %v", serializedLength, r.MaxPayloadLength(0))
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	r2 := &ReplyShortChanIDsEnd{}
+	if err := r2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode ReplyShortChanIDsEnd: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(r, r2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			r, r2)
+	}
+}
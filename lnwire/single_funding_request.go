@@ -8,6 +8,18 @@ import (
 	"github.com/roasbeef/btcutil"
 )
 
+// FundingFlag represents the possible bit mask values for the ChannelFlags
+// field within the SingleFundingRequest message.
+type FundingFlag uint8
+
+const (
+	// FFAnnounceChannel is a FundingFlag that when set, indicates the
+	// initiator of a funding flow wishes to announce the channel to the
+	// greater network. If unset, then the channel is private, and both
+	// parties will refrain from advertising it.
+	FFAnnounceChannel FundingFlag = 1 << iota
+)
+
 // SingleFundingRequest is the message Alice sends to Bob if we should like
 // to create a channel with Bob where she's the sole provider of funds to the
 // channel. Single funder channels simplify the initial funding workflow, are
@@ -77,6 +89,12 @@ type SingleFundingRequest struct {
 	// this amount are not enforceable onchain from our point view.
 	DustLimit btcutil.Amount
 
+	// ChannelFlags is a bit-field which allows the initiator of the
+	// channel to specify further behavior surrounding the channel.
+	// Currently, the only defined bit is FFAnnounceChannel, which signals
+	// whether the channel should be announced to the network.
+	ChannelFlags FundingFlag
+
 	// TODO(roasbeef): confirmation depth
 }
 
@@ -117,6 +135,7 @@ func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
 	// Pubkey (33)
 	// DeliveryPkScript (final delivery)
 	// DustLimit (8)
+	// ChannelFlags (1)
 	err := readElements(r,
 		&c.ChannelID,
 		&c.ChannelType,
@@ -128,7 +147,8 @@ func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
 		&c.CommitmentKey,
 		&c.ChannelDerivationPoint,
 		&c.DeliveryPkScript,
-		&c.DustLimit)
+		&c.DustLimit,
+		&c.ChannelFlags)
 	if err != nil {
 		return err
 	}
@@ -152,6 +172,7 @@ func (c *SingleFundingRequest) Encode(w io.Writer, pver uint32) error {
 	// Pubkey (33)
 	// DeliveryPkScript (final delivery)
 	// DustLimit (8)
+	// ChannelFlags (1)
 	err := writeElements(w,
 		c.ChannelID,
 		c.ChannelType,
@@ -163,7 +184,8 @@ func (c *SingleFundingRequest) Encode(w io.Writer, pver uint32) error {
 		c.CommitmentKey,
 		c.ChannelDerivationPoint,
 		c.DeliveryPkScript,
-		c.DustLimit)
+		c.DustLimit,
+		c.ChannelFlags)
 	if err != nil {
 		return err
 	}
@@ -184,11 +206,11 @@ func (c *SingleFundingRequest) Command() uint32 {
 // the fields within a SingleFundingRequest. To enforce a maximum
 // DeliveryPkScript size, the size of a P2PKH public key script is used.
 // Therefore, the final breakdown is: 8 + 1 + 8 + 8 + 8 + 4 + 33 + 33 + 25 + 8
-// + 9 = 166.
+// + 9 + 1 = 167.
 //
 // This is part of the lnwire.Message interface.
 func (c *SingleFundingRequest) MaxPayloadLength(uint32) uint32 {
-	return 174
+	return 175
 }
 
 // Validate examines each populated field within the SingleFundingRequest for
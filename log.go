@@ -6,8 +6,10 @@ import (
 
 	"github.com/btcsuite/btclog"
 	"github.com/btcsuite/seelog"
+	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/connmgr"
@@ -32,6 +34,9 @@ var (
 	brarLog    = btclog.Disabled
 	cmgrLog    = btclog.Disabled
 	crtrLog    = btclog.Disabled
+	discLog    = btclog.Disabled
+	atplLog    = btclog.Disabled
+	whksLog    = btclog.Disabled
 )
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -49,6 +54,9 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"BRAR": brarLog,
 	"CMGR": cmgrLog,
 	"CRTR": crtrLog,
+	"DISC": discLog,
+	"ATPL": atplLog,
+	"WHKS": whksLog,
 }
 
 // useLogger updates the logger references for subsystemID to logger.  Invalid
@@ -103,6 +111,17 @@ func useLogger(subsystemID string, logger btclog.Logger) {
 	case "CRTR":
 		crtrLog = logger
 		routing.UseLogger(crtrLog)
+
+	case "DISC":
+		discLog = logger
+		discovery.UseLogger(discLog)
+
+	case "ATPL":
+		atplLog = logger
+		autopilot.UseLogger(atplLog)
+
+	case "WHKS":
+		whksLog = logger
 	}
 }
 
@@ -5,7 +5,6 @@ import (
 	"container/list"
 	"crypto/rand"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"net"
 	"sync"
@@ -19,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/connmgr"
@@ -433,9 +433,15 @@ out:
 
 		case *lnwire.NodeAnnouncement,
 			*lnwire.ChannelAnnouncement,
-			*lnwire.ChannelUpdateAnnouncement:
-
-			p.server.chanRouter.ProcessRoutingMessage(msg,
+			*lnwire.ChannelUpdateAnnouncement,
+			*lnwire.AnnounceSignatures,
+			*lnwire.QueryChannelRange,
+			*lnwire.ReplyChannelRange,
+			*lnwire.QueryShortChanIDs,
+			*lnwire.ReplyShortChanIDsEnd,
+			*lnwire.GossipTimestampRange:
+
+			p.server.discoverSrv.ProcessRemoteAnnouncement(msg,
 				p.addr.IdentityKey)
 		}
 
@@ -1014,16 +1020,18 @@ type pendingPayment struct {
 	htlc  *lnwire.HTLCAddRequest
 	index uint32
 
-	err chan error
+	err      chan error
+	preimage chan [32]byte
 }
 
 // commitmentState is the volatile+persistent state of an active channel's
 // commitment update state-machine. This struct is used by htlcManager's to
 // save meta-state required for proper functioning.
 type commitmentState struct {
-	// htlcsToSettle is a list of preimages which allow us to settle one or
-	// many of the pending HTLCs we've received from the upstream peer.
-	htlcsToSettle map[uint32]*channeldb.Invoice
+	// htlcsToSettle is the set of HTLCs, identified by their log index,
+	// which pay one of our invoices, and are to be settled once they're
+	// locked in.
+	htlcsToSettle map[uint32]struct{}
 
 	// htlcsToCancel is a set of HTLCs identified by their log index which
 	// are to be cancelled upon the next state transition.
@@ -1035,9 +1043,25 @@ type commitmentState struct {
 	// htlcSwitch, or subsystem that initiated the HTLC.
 	cancelReasons map[uint32]lnwire.CancelReason
 
-	// TODO(roasbeef): use once trickle+batch logic is in
+	// pendingBatch is the set of HTLC adds we've added to the remote
+	// party's log which have yet to be committed to within a new
+	// commitment update.
 	pendingBatch []*pendingPayment
 
+	// numPendingResolutions is the number of HTLC settles and cancels
+	// we've added to the remote party's log which have yet to be
+	// committed to within a new commitment update.
+	numPendingResolutions int
+
+	// batchSize is the maximum number of HTLC updates which may
+	// accumulate within the pending batch before a new commitment update
+	// is forced.
+	batchSize int
+
+	// batchStats tracks metrics concerning the size of the batches of
+	// HTLC updates committed to within this channel.
+	batchStats *batchStats
+
 	// clearedHTCLs is a map of outgoing HTLCs we've committed to in our
 	// chain which have not yet been settled by the upstream peer.
 	clearedHTCLs map[uint32]*pendingPayment
@@ -1070,10 +1094,32 @@ type commitmentState struct {
 	// along with the HTLC to forward the packet to the next hop.
 	pendingCircuits map[uint32]*sphinx.ProcessedPacket
 
+	// pendingShards tracks the remote log index of the incoming HTLCs for
+	// which we're the exit node, yet which only carry a single shard of a
+	// multi-path payment. Once locked in, each shard is handed off to the
+	// invoice registry, which holds it until the remainder of the payment
+	// arrives.
+	pendingShards map[uint32]*pendingShard
+
+	// shardResolutions is the channel over which the invoice registry
+	// delivers the final resolution of each shard it holds.
+	shardResolutions chan *shardResolution
+
+	// remotePub is the serialized compressed identity public key of the
+	// remote peer. It's used to key the peer's state within the server's
+	// htlcLimiter.
+	remotePub [33]byte
+
 	channel   *lnwallet.LightningChannel
 	chanPoint *wire.OutPoint
 }
 
+// numPendingUpdates returns the total number of HTLC adds, settles and
+// cancels which have yet to be committed to within a new commitment update.
+func (s *commitmentState) numPendingUpdates() int {
+	return len(s.pendingBatch) + s.numPendingResolutions
+}
+
 // htlcManager is the primary goroutine which drives a channel's commitment
 // update state-machine in response to messages received via several channels.
 // The htlcManager reads messages from the upstream (remote) peer, and also
@@ -1108,20 +1154,31 @@ func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 		channel:         channel,
 		chanPoint:       channel.ChannelPoint(),
 		clearedHTCLs:    make(map[uint32]*pendingPayment),
-		htlcsToSettle:   make(map[uint32]*channeldb.Invoice),
+		htlcsToSettle:   make(map[uint32]struct{}),
 		htlcsToCancel:   make(map[uint32]lnwire.CancelReason),
 		cancelReasons:   make(map[uint32]lnwire.CancelReason),
 		pendingCircuits: make(map[uint32]*sphinx.ProcessedPacket),
 		sphinx:          p.server.sphinx,
 		switchChan:      htlcPlex,
+		batchSize:       cfg.HTLCBatchSize,
+		batchStats:      newBatchStats(),
+
+		pendingShards:    make(map[uint32]*pendingShard),
+		shardResolutions: make(chan *shardResolution),
 	}
+	copy(state.remotePub[:], p.addr.IdentityKey.SerializeCompressed())
 
 	// TODO(roasbeef): check to see if able to settle any currently pending
 	// HTLCs
 	//   * also need signals when new invoices are added by the
 	//   invoiceRegistry
 
-	batchTimer := time.Tick(10 * time.Millisecond)
+	// The batch ticker fires at the configured commit interval, ensuring
+	// that any pending HTLC updates which haven't yet filled up an entire
+	// batch are committed to in a timely manner.
+	batchTicker := time.NewTicker(cfg.HTLCCommitInterval)
+	defer batchTicker.Stop()
+
 out:
 	for {
 		select {
@@ -1163,10 +1220,10 @@ out:
 			} else if sent {
 				state.numUnAcked += 1
 			}
-		case <-batchTimer:
+		case <-batchTicker.C:
 			// If the current batch is empty, then we have no work
 			// here.
-			if len(state.pendingBatch) == 0 {
+			if state.numPendingUpdates() == 0 {
 				continue
 			}
 
@@ -1187,6 +1244,8 @@ out:
 			state.numUnAcked += 1
 		case pkt := <-downstreamLink:
 			p.handleDownStreamPkt(state, pkt)
+		case res := <-state.shardResolutions:
+			p.resolveShard(state, res)
 		case msg, ok := <-upstreamLink:
 			// If the upstream message link is closed, this signals
 			// that the channel itself is being closed, therefore
@@ -1201,6 +1260,13 @@ out:
 		}
 	}
 
+	peerLog.Debugf("HTLC batch stats for ChannelPoint(%v): %v",
+		state.chanPoint, state.batchStats)
+
+	// As this channel is no longer active, we'll remove any of its
+	// remaining state from the HTLC limiter.
+	p.server.htlcLimiter.removeChannel(state.remotePub, *state.chanPoint)
+
 	p.wg.Done()
 	peerLog.Tracef("htlcManager for peer %v done", p)
 }
@@ -1210,7 +1276,6 @@ out:
 // HTLCs, timeout previously cleared HTLCs, and finally to settle currently
 // cleared HTLCs with the upstream peer.
 func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
-	var isSettle bool
 	switch htlc := pkt.msg.(type) {
 	case *lnwire.HTLCAddRequest:
 		// A new payment has been initiated via the
@@ -1238,9 +1303,10 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		p.queueMsg(htlc, nil)
 
 		state.pendingBatch = append(state.pendingBatch, &pendingPayment{
-			htlc:  htlc,
-			index: index,
-			err:   pkt.err,
+			htlc:     htlc,
+			index:    index,
+			err:      pkt.err,
+			preimage: pkt.preimage,
 		})
 
 	case *lnwire.HTLCSettleRequest:
@@ -1265,7 +1331,12 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
 		p.queueMsg(htlc, nil)
-		isSettle = true
+		state.numPendingResolutions++
+
+		// As the forwarded HTLC has now been resolved, we release its
+		// pending forward slot.
+		p.server.htlcLimiter.resolvePendingForward(state.remotePub,
+			*state.chanPoint, logIndex)
 
 	case *lnwire.CancelHTLC:
 		// An HTLC cancellation has been triggered somewhere upstream,
@@ -1286,13 +1357,19 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		// Finally, we send the HTLC message to the peer which
 		// initially created the HTLC.
 		p.queueMsg(htlc, nil)
-		isSettle = true
+		state.numPendingResolutions++
+
+		// As the forwarded HTLC has now been resolved, we release its
+		// pending forward slot.
+		p.server.htlcLimiter.resolvePendingForward(state.remotePub,
+			*state.chanPoint, logIndex)
 	}
 
-	// If this newly added update exceeds the max batch size for adds, or
-	// this is a settle request, then initiate an update.
+	// If this newly added update fills up the current batch, then initiate
+	// an update. Otherwise, the update will be committed to once the batch
+	// ticker fires.
 	// TODO(roasbeef): enforce max HTLCs in flight limit
-	if len(state.pendingBatch) >= 10 || isSettle {
+	if state.numPendingUpdates() >= state.batchSize {
 		if sent, err := p.updateCommitTx(state); err != nil {
 			peerLog.Errorf("unable to update "+
 				"commitment: %v", err)
@@ -1333,6 +1410,20 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			return
 		}
 
+		// Before processing the HTLC any further, we ensure that the
+		// remote peer is within its HTLC rate limits, both for the
+		// peer as a whole and this particular channel. If not, then
+		// the HTLC will be cancelled back with a temporary failure
+		// after the next state transition.
+		err = p.server.htlcLimiter.admitHTLC(state.remotePub,
+			*state.chanPoint)
+		if err != nil {
+			peerLog.Warnf("rejecting HTLC from ChannelPoint(%v): %v",
+				state.chanPoint, err)
+			state.htlcsToCancel[index] = lnwire.TemporaryChannelFailure
+			return
+		}
+
 		// TODO(roasbeef): perform sanity checks on per-hop payload
 		//  * time-lock is sane, fee, chain, etc
 
@@ -1353,36 +1444,140 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			return
 		}
 
+		// If we're the destination, then the final hop payload may
+		// span several frames, each further frame of which is
+		// addressed to ourselves, so we'll peel them off in turn.
+		sphinxPacket, hopPayload, err := routing.CollectPayloadFrames(
+			state.sphinx, p.server.identityPriv.PubKey(),
+			sphinxPacket, rHash,
+		)
+		if err != nil {
+			peerLog.Errorf("unable to process onion pkt: %v", err)
+			state.htlcsToCancel[index] = lnwire.SphinxParseError
+			return
+		}
+
 		switch sphinxPacket.Action {
 		// We're the designated payment destination. Therefore we
 		// attempt to see if we have an invoice locally which'll allow
 		// us to settle this HTLC.
 		case sphinx.ExitNode:
 			rHash := htlcPkt.RedemptionHashes[0]
+			finalPayload, hasPayload := routing.DecodeFinalHopPayload(
+				hopPayload,
+			)
+			var customRecords map[uint64][]byte
+			if hasPayload {
+				customRecords = finalPayload.CustomRecords
+			}
+
+			// Custom records of a type reserved for the protocol
+			// itself may not be sent by the payer, so we'll fail
+			// any HTLC carrying them, rather than storing them
+			// along with the invoice.
+			err := routing.ValidateCustomRecords(customRecords)
+			if err != nil {
+				peerLog.Errorf("rejecting HTLC with payment "+
+					"hash (%x): %v", rHash[:], err)
+				state.htlcsToCancel[index] = lnwire.SphinxParseError
+				return
+			}
+
 			invoice, err := p.server.invoices.LookupInvoice(rHash)
+
+			// If we don't recognize the payment hash, then this
+			// may be a spontaneous keysend payment, carrying its
+			// preimage within its custom records, in which case
+			// the invoice registry will create an invoice for it
+			// once the HTLC is locked in. Until then, we'll check
+			// the HTLC against the invoice it would create.
+			var isKeySend bool
+			if err != nil &&
+				customRecords[routing.KeySendRecordType] != nil {
+
+				invoice, err = p.server.invoices.keySendInvoice(
+					rHash, finalPayload.TotalAmount,
+					customRecords,
+				)
+				isKeySend = err == nil
+			}
 			if err != nil {
 				// If we're the exit node, but don't recognize
 				// the payment hash, then we'll fail the HTLC
 				// on the next state transition.
 				peerLog.Errorf("unable to settle HTLC, "+
-					"payment hash (%x) unrecognized", rHash[:])
+					"payment hash (%x) unrecognized: %v",
+					rHash[:], err)
 				state.htlcsToCancel[index] = lnwire.UnknownPaymentHash
 				return
 			}
 
+			// If the invoice has been canceled, or has expired,
+			// then it no longer accepts payment, so we'll fail the
+			// HTLC as if we didn't recognize its payment hash.
+			if !isInvoicePayable(invoice, time.Now()) {
+				peerLog.Errorf("unable to settle HTLC, invoice "+
+					"for payment hash (%x) no longer "+
+					"payable: state=%v", rHash[:],
+					invoice.Terms.State)
+				state.htlcsToCancel[index] = lnwire.UnknownPaymentHash
+				return
+			}
+
+			switch {
+			// If the final hop payload indicates that this HTLC
+			// only carries a single shard of a multi-path payment,
+			// then we'll hold it until the remainder of the
+			// payment arrives, as long as the payment's total
+			// amount is acceptable for the invoice.
+			case hasPayload && htlcPkt.Amount < finalPayload.TotalAmount:
+				totalOK := p.server.invoices.acceptsAmount(
+					invoice, finalPayload.TotalAmount,
+				)
+				if !cfg.DebugHTLC && !totalOK {
+					peerLog.Errorf("rejecting HTLC due to "+
+						"incorrect total amount: "+
+						"requested %v, received %v",
+						invoice.Terms.Value,
+						finalPayload.TotalAmount)
+					state.htlcsToCancel[index] = lnwire.IncorrectValue
+					return
+				}
+
+				state.pendingShards[index] = newPendingShard(
+					htlcPkt, finalPayload.TotalAmount,
+					customRecords, isKeySend,
+				)
+
 			// If we're not currently in debug mode, and the
-			// extended HTLC doesn't meet the value requested, then
-			// we'll fail the HTLC.
-			if !cfg.DebugHTLC && htlcPkt.Amount < invoice.Terms.Value {
+			// extended HTLC either doesn't meet the value
+			// requested, or overpays it by more than we allow,
+			// then we'll fail the HTLC.
+			case !cfg.DebugHTLC &&
+				!p.server.invoices.acceptsAmount(invoice, htlcPkt.Amount):
+
 				peerLog.Errorf("rejecting HTLC due to incorrect "+
-					"amount: expected %v, received %v",
+					"amount: requested %v, received %v",
 					invoice.Terms.Value, htlcPkt.Amount)
 				state.htlcsToCancel[index] = lnwire.IncorrectValue
-			} else {
-				// Otherwise, everything is in order and we'll
-				// settle the HTLC after the current state
-				// transition.
-				state.htlcsToSettle[index] = invoice
+
+			// If the invoice is a hold invoice, then we don't know
+			// the preimage, so we'll hand the HTLC off to the
+			// invoice registry, which will hold it until the
+			// invoice is settled or canceled. HTLCs carrying custom
+			// records are also handed off to the registry, which
+			// stores the records with the invoice once it's
+			// settled.
+			case invoice.IsHold() || len(customRecords) > 0:
+				state.pendingShards[index] = newPendingShard(
+					htlcPkt, htlcPkt.Amount, customRecords,
+					isKeySend,
+				)
+
+			// Otherwise, everything is in order and we'll settle
+			// the HTLC after the current state transition.
+			default:
+				state.htlcsToSettle[index] = struct{}{}
 			}
 
 		// There are additional hops left within this route, so we
@@ -1391,6 +1586,23 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		// switch, we'll attach the routing information so the switch
 		// can finalize the circuit.
 		case sphinx.MoreHops:
+			// Before accepting the HTLC for forwarding, we'll
+			// reserve a pending forward slot for it. If the channel
+			// already has too many forwards pending resolution, or
+			// the peer's HTLCs have been jamming our liquidity,
+			// then we'll fail the HTLC back with a temporary
+			// failure.
+			err := p.server.htlcLimiter.addPendingForward(
+				state.remotePub, *state.chanPoint, index,
+			)
+			if err != nil {
+				peerLog.Warnf("rejecting HTLC forward from "+
+					"ChannelPoint(%v): %v", state.chanPoint,
+					err)
+				state.htlcsToCancel[index] = lnwire.TemporaryChannelFailure
+				return
+			}
+
 			state.pendingCircuits[index] = sphinxPacket
 		default:
 			peerLog.Errorf("mal formed onion packet")
@@ -1467,23 +1679,31 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		// can them from the pending set, and signal the requester (if
 		// existing) that the payment has been fully fulfilled.
 		var bandwidthUpdate btcutil.Amount
-		settledPayments := make(map[lnwallet.PaymentHash]struct{})
+		settledPayments := make(map[lnwallet.PaymentHash]btcutil.Amount)
 		cancelledHtlcs := make(map[uint32]struct{})
+		heldShards := make(map[uint32]struct{})
 		for _, htlc := range htlcsToForward {
 			parentIndex := htlc.ParentIndex
 			if p, ok := state.clearedHTCLs[parentIndex]; ok {
 				switch htlc.EntryType {
 				// If the HTLC was settled successfully, then
 				// we return a nil error back to the possible
-				// caller.
+				// caller, along with the preimage if they're
+				// awaiting it.
 				case lnwallet.Settle:
+					if p.preimage != nil {
+						p.preimage <- [32]byte(htlc.RPreimage)
+					}
 					p.err <- nil
 
 				// Otherwise, the HTLC failed, so we propagate
 				// the error back to the potential caller.
 				case lnwallet.Cancel:
-					errMsg := state.cancelReasons[parentIndex]
-					p.err <- errors.New(errMsg.String())
+					// The cancel reason is itself an
+					// error, allowing the router to
+					// inspect the reason for the
+					// failure.
+					p.err <- state.cancelReasons[parentIndex]
 				}
 
 				delete(state.clearedHTCLs, htlc.ParentIndex)
@@ -1495,31 +1715,84 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				continue
 			}
 
-			// If we can settle this HTLC within our local state
-			// update log, then send the update entry to the remote
-			// party.
-			invoice, ok := state.htlcsToSettle[htlc.Index]
-			if ok {
-				preimage := invoice.Terms.PaymentPreimage
-				logIndex, err := state.channel.SettleHTLC(preimage)
+			shard, ok := state.pendingShards[htlc.Index]
+			delete(state.pendingShards, htlc.Index)
+
+			// If the HTLC pays a keysend payment, then we'll first
+			// create its invoice, unless another shard of the
+			// payment already has. If we're unable to, then we'll
+			// cancel the HTLC instead.
+			if ok && shard.keySend {
+				_, err := p.server.invoices.AddKeySendInvoice(
+					shard.rHash, shard.total,
+					shard.customRecords,
+				)
 				if err != nil {
-					peerLog.Errorf("unable to settle htlc: %v", err)
-					p.Disconnect()
-					continue
+					peerLog.Errorf("unable to create "+
+						"keysend invoice: %v", err)
+					state.htlcsToCancel[htlc.Index] = lnwire.UnknownPaymentHash
+					ok = false
 				}
+			}
 
-				settleMsg := &lnwire.HTLCSettleRequest{
-					ChannelPoint:     state.chanPoint,
-					HTLCKey:          lnwire.HTLCKey(logIndex),
-					RedemptionProofs: [][32]byte{preimage},
-				}
-				p.queueMsg(settleMsg, nil)
+			// If this HTLC carries a shard of a multi-path
+			// payment, pays a hold invoice, or carries custom
+			// records, then now that it's locked in, we'll hand it
+			// off to the invoice registry which will hold it until
+			// it can be resolved.
+			if ok {
+				p.server.invoices.AddPaymentShard(
+					shard.rHash, shard.total,
+					shard.paymentShard(
+						htlc.Index,
+						state.shardResolutions, p.quit,
+					),
+				)
+
+				heldShards[htlc.Index] = struct{}{}
+				continue
+			}
 
+			// If this HTLC pays one of our invoices, then we'll
+			// first mark the invoice as settled. Only once that
+			// succeeds do we release the preimage by settling the
+			// HTLC within our local state update log, and sending
+			// the update entry to the remote party.
+			if _, ok := state.htlcsToSettle[htlc.Index]; ok {
 				delete(state.htlcsToSettle, htlc.Index)
-				settledPayments[htlc.RHash] = struct{}{}
 
-				bandwidthUpdate += htlc.Amount
-				continue
+				preimage, err := p.server.invoices.SettlePayment(
+					chainhash.Hash(htlc.RHash), htlc.Amount,
+				)
+				if err == nil {
+					err := state.channel.SettleHTLCByIndex(
+						preimage, htlc.Index,
+					)
+					if err != nil {
+						peerLog.Errorf("unable to settle "+
+							"htlc: %v", err)
+						p.Disconnect()
+						continue
+					}
+
+					settleMsg := &lnwire.HTLCSettleRequest{
+						ChannelPoint:     state.chanPoint,
+						HTLCKey:          lnwire.HTLCKey(htlc.Index),
+						RedemptionProofs: [][32]byte{preimage},
+					}
+					p.queueMsg(settleMsg, nil)
+
+					settledPayments[htlc.RHash] += htlc.Amount
+
+					bandwidthUpdate += htlc.Amount
+					continue
+				}
+
+				// Otherwise, the invoice no longer accepts
+				// payment, so we'll cancel the HTLC instead.
+				peerLog.Errorf("unable to settle invoice: %v",
+					err)
+				state.htlcsToCancel[htlc.Index] = lnwire.UnknownPaymentHash
 			}
 
 			// Alternatively, if we marked this HTLC for
@@ -1531,7 +1804,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				continue
 			}
 
-			logIndex, err := state.channel.CancelHTLC(htlc.RHash)
+			err := state.channel.CancelHTLCByIndex(htlc.Index)
 			if err != nil {
 				peerLog.Errorf("unable to cancel htlc: %v", err)
 				p.Disconnect()
@@ -1540,7 +1813,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 
 			cancelMsg := &lnwire.CancelHTLC{
 				ChannelPoint: state.chanPoint,
-				HTLCKey:      lnwire.HTLCKey(logIndex),
+				HTLCKey:      lnwire.HTLCKey(htlc.Index),
 				Reason:       reason,
 			}
 			p.queueMsg(cancelMsg, nil)
@@ -1560,6 +1833,9 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				if _, ok := cancelledHtlcs[htlc.Index]; ok {
 					continue
 				}
+				if _, ok := heldShards[htlc.Index]; ok {
+					continue
+				}
 
 				onionPkt := state.pendingCircuits[htlc.Index]
 				delete(state.pendingCircuits, htlc.Index)
@@ -1606,15 +1882,101 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			state.numUnAcked += 1
 		}
 
-		// Notify the invoiceRegistry of the invoices we just settled
-		// with this latest commitment update.
-		// TODO(roasbeef): wait until next transition?
-		for invoice, _ := range settledPayments {
-			err := p.server.invoices.SettleInvoice(chainhash.Hash(invoice))
-			if err != nil {
-				peerLog.Errorf("unable to settle invoice: %v", err)
-			}
+	}
+}
+
+// pendingShard is an incoming HTLC, for which we're the exit node, that
+// carries a single shard of a multi-path payment, pays a hold invoice, or
+// carries custom records, and has yet to be locked in.
+type pendingShard struct {
+	rHash         chainhash.Hash
+	amt           btcutil.Amount
+	total         btcutil.Amount
+	expiry        uint32
+	customRecords map[uint64][]byte
+
+	// keySend is true if the HTLC pays a spontaneous keysend payment,
+	// whose invoice is created once the HTLC is locked in.
+	keySend bool
+}
+
+// newPendingShard creates a pending shard for the passed HTLC, which belongs
+// to a payment of the passed total amount. The shard expires along with the
+// HTLC, such that a held hold invoice is canceled before the HTLC times out.
+func newPendingShard(htlc *lnwire.HTLCAddRequest, total btcutil.Amount,
+	customRecords map[uint64][]byte, keySend bool) *pendingShard {
+
+	return &pendingShard{
+		rHash:         htlc.RedemptionHashes[0],
+		amt:           htlc.Amount,
+		total:         total,
+		expiry:        htlc.Expiry,
+		customRecords: customRecords,
+		keySend:       keySend,
+	}
+}
+
+// paymentShard returns the payment shard to be handed off to the invoice
+// registry once the shard's HTLC has been locked in at the passed index.
+func (s *pendingShard) paymentShard(htlcIndex uint32,
+	resolutions chan<- *shardResolution,
+	quit <-chan struct{}) *paymentShard {
+
+	return &paymentShard{
+		htlcIndex:     htlcIndex,
+		amt:           s.amt,
+		resolutions:   resolutions,
+		quit:          quit,
+		expiry:        s.expiry,
+		customRecords: s.customRecords,
+	}
+}
+
+// resolveShard settles or cancels a locked-in HTLC which carries a shard of a
+// multi-path payment, according to the resolution delivered by the invoice
+// registry. The HTLC is targeted by its log index, as other HTLCs over the
+// channel may share its payment hash. A new commitment update is then
+// initiated to include the resolution.
+func (p *peer) resolveShard(state *commitmentState, res *shardResolution) {
+	if res.preimage != nil {
+		err := state.channel.SettleHTLCByIndex(
+			*res.preimage, res.htlcIndex,
+		)
+		if err != nil {
+			peerLog.Errorf("unable to settle htlc: %v", err)
+			p.Disconnect()
+			return
+		}
+
+		settleMsg := &lnwire.HTLCSettleRequest{
+			ChannelPoint:     state.chanPoint,
+			HTLCKey:          lnwire.HTLCKey(res.htlcIndex),
+			RedemptionProofs: [][32]byte{*res.preimage},
 		}
+		p.queueMsg(settleMsg, nil)
+
+		p.server.htlcSwitch.UpdateLink(state.chanPoint, res.amt)
+	} else {
+		err := state.channel.CancelHTLCByIndex(res.htlcIndex)
+		if err != nil {
+			peerLog.Errorf("unable to cancel htlc: %v", err)
+			p.Disconnect()
+			return
+		}
+
+		cancelMsg := &lnwire.CancelHTLC{
+			ChannelPoint: state.chanPoint,
+			HTLCKey:      lnwire.HTLCKey(res.htlcIndex),
+			Reason:       res.reason,
+		}
+		p.queueMsg(cancelMsg, nil)
+	}
+
+	if sent, err := p.updateCommitTx(state); err != nil {
+		peerLog.Errorf("unable to update commitment: %v", err)
+		p.Disconnect()
+	} else if sent {
+		state.numUnAcked += 1
 	}
 }
 
@@ -1643,6 +2005,12 @@ func (p *peer) updateCommitTx(state *commitmentState) (bool, error) {
 	}
 	p.queueMsg(commitSig, nil)
 
+	// Record the size of this batch within both the per-channel and
+	// global batch metrics.
+	batchSize := state.numPendingUpdates()
+	state.batchStats.recordBatch(batchSize)
+	p.server.htlcBatchStats.recordBatch(batchSize)
+
 	// Move all pending updates to the map of cleared HTLCs, clearing out
 	// the set of pending updates.
 	for _, update := range state.pendingBatch {
@@ -1652,6 +2020,7 @@ func (p *peer) updateCommitTx(state *commitmentState) (bool, error) {
 	}
 	state.logCommitTimer = nil
 	state.pendingBatch = nil
+	state.numPendingResolutions = 0
 
 	return true, nil
 }
@@ -1671,13 +2040,22 @@ func logEntryToHtlcPkt(chanPoint wire.OutPoint,
 	var msg lnwire.Message
 	switch pd.EntryType {
 	case lnwallet.Add:
-		// TODO(roasbeef): timeout, onion blob, etc
+		// TODO(roasbeef): onion blob, etc
 		var b bytes.Buffer
 		if err := onionPkt.Packet.Encode(&b); err != nil {
 			return nil, err
 		}
 
+		// The outgoing HTLC expires the time lock delta we
+		// advertise before the incoming one, leaving us time to
+		// redeem the incoming HTLC once the outgoing one settles.
+		var expiry uint32
+		if pd.Timeout > defaultTimeLockDelta {
+			expiry = pd.Timeout - defaultTimeLockDelta
+		}
+
 		msg = &lnwire.HTLCAddRequest{
+			Expiry:           expiry,
 			Amount:           btcutil.Amount(pd.Amount),
 			RedemptionHashes: [][32]byte{pd.RHash},
 			OnionBlob:        b.Bytes(),
@@ -1 +1,92 @@
 package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestHoldInvoiceForwardedExpiry tests that an HTLC forwarded to us carries
+// an absolute expiry, which is held by the invoice registry along with the
+// HTLC, such that a hold invoice paid by it is canceled before it expires.
+func TestHoldInvoiceForwardedExpiry(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+
+	resolutions := make(chan *shardResolution)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	_, rHash := addTestHoldInvoice(t, registry)
+
+	// The forwarding node receives an HTLC expiring at height 600, which
+	// it forwards to us along with the onion it uncovered.
+	hopKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	onion, err := sphinx.NewOnionPacket(
+		[]*btcec.PublicKey{hopKey.PubKey()}, sessionKey,
+		[][]byte{nil}, rHash[:],
+	)
+	if err != nil {
+		t.Fatalf("unable to create onion: %v", err)
+	}
+	incoming := &lnwallet.PaymentDescriptor{
+		EntryType: lnwallet.Add,
+		RHash:     lnwallet.PaymentHash(rHash),
+		Timeout:   600,
+		Amount:    1000,
+	}
+	pkt, err := logEntryToHtlcPkt(
+		wire.OutPoint{}, incoming, &sphinx.ProcessedPacket{
+			Action: sphinx.MoreHops,
+			Packet: onion,
+		}, 0,
+	)
+	if err != nil {
+		t.Fatalf("unable to create htlc packet: %v", err)
+	}
+
+	// The outgoing HTLC should expire the time lock delta we advertise
+	// before the incoming one.
+	htlc := pkt.msg.(*lnwire.HTLCAddRequest)
+	expiry := incoming.Timeout - defaultTimeLockDelta
+	if htlc.Expiry != expiry {
+		t.Fatalf("expected expiry %v, got %v", expiry, htlc.Expiry)
+	}
+
+	// Once we receive and lock in the HTLC, it's held by the registry
+	// until the hold invoice is resolved.
+	shard := newPendingShard(htlc, htlc.Amount, nil, false)
+	registry.AddPaymentShard(
+		shard.rHash, shard.total, shard.paymentShard(0, resolutions, quit),
+	)
+	receiveResolutions(t, resolutions, 0)
+	assertInvoiceState(t, registry, rHash, channeldb.ContractAccepted)
+
+	// As the chain nears the HTLC's expiry, the hold invoice should be
+	// canceled, failing the HTLC back.
+	registry.cancelExpiringHolds(expiry - registry.holdExpiryDelta - 1)
+	receiveResolutions(t, resolutions, 0)
+
+	registry.cancelExpiringHolds(expiry - registry.holdExpiryDelta)
+	res := receiveResolutions(t, resolutions, 1)
+	if res[0].preimage != nil || res[0].rHash != rHash {
+		t.Fatalf("expected held HTLC to be cancelled, instead have %v",
+			res[0])
+	}
+	assertInvoiceState(t, registry, rHash, channeldb.ContractCanceled)
+}
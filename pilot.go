@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/autopilot"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// chanController is an implementation of the autopilot.ChannelController
+// interface that's backed by a running lnd instance.
+type chanController struct {
+	server *server
+}
+
+// A compile time assertion to ensure chanController meets the
+// autopilot.ChannelController interface.
+var _ autopilot.ChannelController = (*chanController)(nil)
+
+// OpenChannel opens a channel to a target peer, with a capacity of the
+// specified amount. If we aren't yet connected to the target peer, then a
+// connection is first attempted using the passed addresses. This function
+// unblocks once the funding transaction of the channel has been broadcast.
+//
+// NOTE: Part of the autopilot.ChannelController interface.
+func (c *chanController) OpenChannel(target *btcec.PublicKey,
+	amt btcutil.Amount, addrs []net.Addr) error {
+
+	// If we aren't already connected to the target peer, then we'll
+	// attempt to connect to each of its advertised addresses in turn,
+	// stopping at the first successful connection.
+	targetPub := string(target.SerializeCompressed())
+	c.server.peersMtx.RLock()
+	_, connected := c.server.peersByPub[targetPub]
+	c.server.peersMtx.RUnlock()
+
+	if !connected {
+		var connErr error
+		for _, addr := range addrs {
+			tcpAddr, ok := addr.(*net.TCPAddr)
+			if !ok {
+				continue
+			}
+
+			connErr = c.server.ConnectToPeer(&lnwire.NetAddress{
+				IdentityKey: target,
+				Address:     tcpAddr,
+				ChainNet:    activeNetParams.Net,
+			}, false)
+			if connErr == nil {
+				connected = true
+				break
+			}
+		}
+
+		if !connected {
+			return fmt.Errorf("unable to connect to %x: %v",
+				target.SerializeCompressed(), connErr)
+		}
+	}
+
+	// With the connection established, we'll now send the funding request
+	// to the server. Channels opened by the autopilot agent are always
+	// announced, as their purpose is to connect us to the greater network.
+	updateChan, errChan := c.server.OpenChannel(0, target, amt, 0, 1,
+		false)
+
+	// We'll wait for the first update, which is sent once the funding
+	// transaction has been broadcast.
+	select {
+	case err := <-errChan:
+		return err
+
+	case update := <-updateChan:
+		if _, ok := update.Update.(*lnrpc.OpenStatusUpdate_ChanPending); !ok {
+			return fmt.Errorf("unexpected funding update: %v",
+				update)
+		}
+
+		return nil
+
+	case <-c.server.quit:
+		return fmt.Errorf("server shutting down")
+	}
+}
+
+// initAutoPilot initializes a new autopilot.Agent instance based on the passed
+// configuration struct. All interfaces needed to drive the agent are backed by
+// the passed server.
+func initAutoPilot(svr *server, cfg *autoPilotConfig) (*autopilot.Agent,
+	error) {
+
+	atplLog.Infof("Instantiating autopilot with cfg: %+v", cfg)
+
+	// First, we'll create the preferential attachment heuristic,
+	// initialized with the passed auto pilot configuration parameters.
+	prefAttachment := autopilot.NewConstrainedPrefAttachment(
+		btcutil.Amount(cfg.MinChanSize), btcutil.Amount(cfg.MaxChanSize),
+		uint16(cfg.MaxChannels), cfg.Allocation,
+	)
+
+	// With the heuristic itself created, we can now populate the
+	// remainder of the required items for the autopilot agent.
+	pilotCfg := autopilot.Config{
+		Self:           svr.identityPriv.PubKey(),
+		Heuristic:      prefAttachment,
+		ChanController: &chanController{svr},
+		WalletBalance: func() (btcutil.Amount, error) {
+			return svr.lnwallet.ConfirmedBalance(1, true)
+		},
+		Graph: autopilot.ChannelGraphFromDatabase(
+			svr.chanDB.ChannelGraph(),
+		),
+		FailedNodeBackoff: autopilot.DefaultFailedNodeBackoff,
+	}
+
+	// Next, we'll fetch the current state of open channels from the
+	// database to use as the initial state for the auto-pilot agent.
+	activeChannels, err := svr.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	initialChanState := make([]autopilot.Channel, len(activeChannels))
+	for i, channel := range activeChannels {
+		initialChanState[i] = autopilot.Channel{
+			ChanPoint: *channel.ChanID,
+			Capacity:  channel.Capacity,
+			Node:      autopilot.NewNodeID(channel.IdentityPub),
+		}
+	}
+
+	return autopilot.New(pilotCfg, initialChanState)
+}
+
+// autoPilotManager manages the lifetime of the autopilot agent, allowing it
+// to be enabled and disabled while the daemon is running. When enabled, the
+// manager feeds the agent with the external signals it requires: changes in
+// the wallet's balance, and channels opening and closing.
+type autoPilotManager struct {
+	sync.Mutex
+
+	cfg    *autoPilotConfig
+	server *server
+
+	// agent is the currently active agent. This is nil if the autopilot
+	// is disabled.
+	agent *autopilot.Agent
+
+	// quit is closed when the currently active agent is disabled in order
+	// to stop the goroutine delivering signals to it.
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAutoPilotManager creates a new autoPilotManager backed by the passed
+// server.
+func newAutoPilotManager(svr *server,
+	cfg *autoPilotConfig) *autoPilotManager {
+
+	return &autoPilotManager{
+		cfg:    cfg,
+		server: svr,
+	}
+}
+
+// Start launches the autopilot agent if it has been marked as active within
+// the configuration.
+func (m *autoPilotManager) Start() error {
+	if !m.cfg.Active {
+		return nil
+	}
+
+	return m.SetActive(true)
+}
+
+// Stop stops the autopilot agent if it's active.
+func (m *autoPilotManager) Stop() error {
+	return m.SetActive(false)
+}
+
+// Active returns true if the autopilot agent is currently active.
+func (m *autoPilotManager) Active() bool {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.agent != nil
+}
+
+// Status returns a snapshot of the current state of the autopilot agent. If
+// the agent isn't active, then a nil status is returned.
+func (m *autoPilotManager) Status() (*autopilot.Status, error) {
+	m.Lock()
+	agent := m.agent
+	m.Unlock()
+
+	if agent == nil {
+		return nil, nil
+	}
+
+	return agent.Status()
+}
+
+// SetActive enables or disables the autopilot agent. A new agent is created
+// each time the autopilot is enabled, ensuring its initial state reflects the
+// current set of open channels.
+func (m *autoPilotManager) SetActive(active bool) error {
+	m.Lock()
+	defer m.Unlock()
+
+	switch {
+	case active && m.agent == nil:
+		return m.enable()
+
+	case !active && m.agent != nil:
+		return m.disable()
+	}
+
+	return nil
+}
+
+// enable creates and starts a new autopilot agent, along with the goroutine
+// responsible for delivering external signals to it.
+//
+// NOTE: This method MUST be called with the manager's mutex held.
+func (m *autoPilotManager) enable() error {
+	agent, err := initAutoPilot(m.server, m.cfg)
+	if err != nil {
+		return err
+	}
+
+	// Before starting the agent, we'll subscribe to the wallet's
+	// transactions and the topology of the channel graph, so no signals
+	// are missed.
+	txnSubscription, err := m.server.lnwallet.SubscribeTransactions()
+	if err != nil {
+		return err
+	}
+	graphSubscription, err := m.server.chanRouter.SubscribeTopology()
+	if err != nil {
+		txnSubscription.Cancel()
+		return err
+	}
+
+	if err := agent.Start(); err != nil {
+		txnSubscription.Cancel()
+		graphSubscription.Cancel()
+		return err
+	}
+
+	m.agent = agent
+	m.quit = make(chan struct{})
+
+	m.wg.Add(1)
+	go m.signalHandler(agent, txnSubscription, graphSubscription)
+
+	atplLog.Infof("Autopilot agent enabled")
+
+	return nil
+}
+
+// disable stops the currently active autopilot agent.
+//
+// NOTE: This method MUST be called with the manager's mutex held.
+func (m *autoPilotManager) disable() error {
+	close(m.quit)
+	m.wg.Wait()
+
+	if err := m.agent.Stop(); err != nil {
+		return err
+	}
+	m.agent = nil
+
+	atplLog.Infof("Autopilot agent disabled")
+
+	return nil
+}
+
+// signalHandler delivers the external signals required by the autopilot
+// agent: confirmed transactions modifying the wallet's balance, and the
+// opening and closing of our channels as seen within the channel graph.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *autoPilotManager) signalHandler(agent *autopilot.Agent,
+	txnSubscription lnwallet.TransactionSubscription,
+	graphSubscription *routing.TopologyClient) {
+
+	defer m.wg.Done()
+	defer txnSubscription.Cancel()
+	defer graphSubscription.Cancel()
+
+	selfPub := m.server.identityPriv.PubKey().SerializeCompressed()
+
+	for {
+		select {
+		// A new transaction relevant to the wallet has confirmed,
+		// which may modify our available balance.
+		case <-txnSubscription.ConfirmedTransactions():
+			agent.OnBalanceChange()
+
+		// A new topology change has arrived. We'll inform the agent
+		// of any channels of ours which have been opened or closed.
+		case topChange, ok := <-graphSubscription.TopologyChanges:
+			// If the router is shutting down, then we'll exit, as
+			// no further signals will be sent.
+			if !ok {
+				return
+			}
+
+			for _, edgeUpdate := range topChange.ChannelEdgeUpdates {
+				// We only care about edges advertised by
+				// ourselves, as the connecting node is then
+				// our channel counterparty.
+				edgeSrc := edgeUpdate.AdvertisingNode.SerializeCompressed()
+				if !bytes.Equal(selfPub, edgeSrc) {
+					continue
+				}
+
+				agent.OnChannelOpen(autopilot.Channel{
+					ChanPoint: edgeUpdate.ChanPoint,
+					Capacity:  edgeUpdate.Capacity,
+					Node: autopilot.NewNodeID(
+						edgeUpdate.ConnectingNode,
+					),
+				})
+			}
+
+			// Closing a channel we don't know of is a no-op
+			// for the agent, so we can pass along all closed
+			// channels.
+			if len(topChange.ClosedChannels) != 0 {
+				closedChans := make(
+					[]wire.OutPoint, 0,
+					len(topChange.ClosedChannels),
+				)
+				for _, closedChan := range topChange.ClosedChannels {
+					closedChans = append(
+						closedChans, closedChan.ChanPoint,
+					)
+				}
+
+				agent.OnChannelClose(closedChans...)
+			}
+
+		case <-m.quit:
+			return
+		}
+	}
+}
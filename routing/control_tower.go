@@ -0,0 +1,489 @@
+package routing
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// errPaymentInterrupted is the failure recorded for payments which were
+// interrupted by a restart before any of their HTLCs were sent.
+var errPaymentInterrupted = errors.New("payment interrupted before any " +
+	"HTLC was sent")
+
+// PaymentClient represents an intent to receive notifications regarding the
+// state of an outgoing payment. The Updates channel will be sent upon with
+// the full state of the payment each time it changes.
+type PaymentClient struct {
+	// Updates is a receive only channel over which the state of the
+	// payment is delivered, starting with its current state. The channel
+	// is closed once the payment has reached its final state, the client
+	// has been cancelled, or the router is shutting down.
+	Updates <-chan *channeldb.TrackedPayment
+
+	// Cancel is a function closure that should be executed when the
+	// client wishes to cancel their notification intent. Doing so allows
+	// the ChannelRouter to free up resources.
+	Cancel func()
+}
+
+// paymentClient is the internal counterpart of a PaymentClient. Like the
+// topologyClient, it queues all updates handed to it such that a slow client
+// never blocks the progress of the payment.
+type paymentClient struct {
+	// incoming is the channel over which new payment states are handed to
+	// the client's dispatcher.
+	incoming chan *channeldb.TrackedPayment
+
+	// ntfnChan is the send-only channel the client's updates are
+	// delivered over.
+	ntfnChan chan<- *channeldb.TrackedPayment
+
+	// exit is closed once the client has been cancelled.
+	exit     chan struct{}
+	exitOnce sync.Once
+
+	wg sync.WaitGroup
+}
+
+// isFinalPayment returns true if the passed payment has reached its final
+// state, meaning no further updates will follow.
+func isFinalPayment(payment *channeldb.TrackedPayment) bool {
+	switch payment.Status {
+	case channeldb.StatusFailed:
+		return true
+	case channeldb.StatusSucceeded:
+		return len(payment.InFlightAttempts()) == 0
+	default:
+		return false
+	}
+}
+
+// notificationDispatcher queues all payment states handed to the client,
+// delivering them in the order they were received. The client's notification
+// channel is closed once a final state has been delivered.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *paymentClient) notificationDispatcher(quit chan struct{}) {
+	defer c.wg.Done()
+	defer close(c.ntfnChan)
+
+	var pending []*channeldb.TrackedPayment
+	for {
+		// We'll only attempt to deliver a notification if we have one
+		// pending, as a send on a nil channel blocks forever.
+		var (
+			next     *channeldb.TrackedPayment
+			ntfnChan chan<- *channeldb.TrackedPayment
+		)
+		if len(pending) != 0 {
+			next = pending[0]
+			ntfnChan = c.ntfnChan
+		}
+
+		select {
+		case payment := <-c.incoming:
+			pending = append(pending, payment)
+
+		case ntfnChan <- next:
+			pending[0] = nil
+			pending = pending[1:]
+
+			if isFinalPayment(next) {
+				return
+			}
+
+		case <-c.exit:
+			return
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// controlTower wraps the payment control tower within the database. It
+// records each payment sent by the router, along with each of its attempts,
+// such that duplicate payments are prevented, and payments in flight can be
+// resumed after a restart. Additionally, any clients tracking a payment are
+// notified of each of its state transitions.
+//
+// If the control tower isn't backed by a database, then payments aren't
+// tracked at all, and all of its methods are no-ops.
+//
+// NOTE: All methods of the controlTower are safe for concurrent use.
+type controlTower struct {
+	db *channeldb.PaymentControl
+
+	// clients maps a payment hash to the set of clients tracking the
+	// payment, keyed by their unique client ID. The mutex is held for the
+	// duration of each notification, ensuring clients receive each
+	// payment's states in order.
+	clients      map[[32]byte]map[uint64]*paymentClient
+	nextClientID uint64
+	clientMtx    sync.Mutex
+
+	// resumed is the set of payments which were in flight when the
+	// router was last started. As the HTLCs of such payments no longer
+	// have a caller awaiting their result, their outcome is reported to
+	// the control tower by the switch instead.
+	resumed    map[[32]byte]struct{}
+	resumedMtx sync.Mutex
+
+	quit chan struct{}
+}
+
+// newControlTower creates a new control tower backed by the passed database,
+// which may be nil.
+func newControlTower(db *channeldb.DB, quit chan struct{}) *controlTower {
+	c := &controlTower{
+		clients: make(map[[32]byte]map[uint64]*paymentClient),
+		resumed: make(map[[32]byte]struct{}),
+		quit:    quit,
+	}
+	if db != nil {
+		c.db = channeldb.NewPaymentControl(db)
+	}
+
+	return c
+}
+
+// initPayment records the intent to send a payment of the passed amount to
+// the passed payment hash. An error is returned if the payment is either
+// already in flight, or has already succeeded.
+func (c *controlTower) initPayment(paymentHash [32]byte, amt btcutil.Amount,
+	payReq []byte) error {
+
+	if c.db == nil {
+		return nil
+	}
+
+	err := c.db.InitPayment(&channeldb.PaymentCreationInfo{
+		PaymentHash:    paymentHash,
+		Value:          amt,
+		CreationDate:   time.Now(),
+		PaymentRequest: payReq,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.notifyClients(paymentHash)
+	return nil
+}
+
+// registerAttempt records that an HTLC for the payment is about to be sent
+// over the passed route, returning the ID of the new attempt.
+func (c *controlTower) registerAttempt(paymentHash [32]byte,
+	route *Route) (uint64, error) {
+
+	if c.db == nil {
+		return 0, nil
+	}
+
+	attempt := &channeldb.PaymentAttempt{
+		TotalAmount:   route.TotalAmount,
+		TotalFees:     route.TotalFees,
+		TotalTimeLock: route.TotalTimeLock,
+		Hops:          make([]channeldb.AttemptHop, len(route.Hops)),
+		AttemptTime:   time.Now(),
+	}
+	for i, hop := range route.Hops {
+		copy(attempt.Hops[i].PubKey[:],
+			hop.Channel.Node.PubKey.SerializeCompressed())
+		attempt.Hops[i].ChannelID = hop.Channel.ChannelID
+		attempt.Hops[i].AmtToForward = hop.AmtToForward
+		attempt.Hops[i].Fee = hop.Fee
+	}
+
+	attemptID, err := c.db.RegisterAttempt(paymentHash, attempt)
+	if err != nil {
+		return 0, err
+	}
+
+	c.notifyClients(paymentHash)
+	return attemptID, nil
+}
+
+// resolveAttempt records the outcome of the target attempt. A nil error
+// indicates the attempt was settled, revealing the passed preimage.
+func (c *controlTower) resolveAttempt(paymentHash [32]byte, attemptID uint64,
+	preimage [32]byte, attemptErr error) {
+
+	if c.db == nil {
+		return
+	}
+
+	var err error
+	if attemptErr == nil {
+		err = c.db.SettleAttempt(paymentHash, attemptID, preimage)
+	} else {
+		err = c.db.FailAttempt(paymentHash, attemptID,
+			attemptErr.Error())
+	}
+	if err != nil {
+		log.Errorf("Unable to record outcome of attempt %v for "+
+			"payment %x: %v", attemptID, paymentHash, err)
+		return
+	}
+
+	c.notifyClients(paymentHash)
+}
+
+// fail records that the payment has failed for the passed reason.
+func (c *controlTower) fail(paymentHash [32]byte, reason error) {
+	if c.db == nil {
+		return
+	}
+
+	if err := c.db.Fail(paymentHash, reason.Error()); err != nil {
+		log.Errorf("Unable to record failure of payment %x: %v",
+			paymentHash, err)
+		return
+	}
+
+	c.notifyClients(paymentHash)
+}
+
+// resume loads all payments which were in flight when the router was last
+// running. Payments without any HTLCs in flight are failed outright, as their
+// sender is no longer around to continue them. The remainder are resumed,
+// awaiting the resolution of their HTLCs via resolveHTLC.
+func (c *controlTower) resume() error {
+	if c.db == nil {
+		return nil
+	}
+
+	payments, err := c.db.FetchInFlightPayments()
+	if err != nil {
+		return err
+	}
+
+	c.resumedMtx.Lock()
+	defer c.resumedMtx.Unlock()
+
+	for _, payment := range payments {
+		paymentHash := payment.Info.PaymentHash
+
+		if len(payment.InFlightAttempts()) == 0 {
+			log.Infof("Failing interrupted payment %x", paymentHash)
+
+			c.fail(paymentHash, errPaymentInterrupted)
+			continue
+		}
+
+		log.Infof("Resuming payment %x with %v HTLCs in flight",
+			paymentHash, len(payment.InFlightAttempts()))
+
+		c.resumed[paymentHash] = struct{}{}
+	}
+
+	return nil
+}
+
+// resolveHTLC records the outcome of an HTLC belonging to a resumed payment,
+// which was sent over the channel with the passed short channel ID. A chanID
+// of zero indicates the channel is unknown. A nil error indicates the HTLC was
+// settled, revealing the passed preimage. HTLCs which don't belong to a
+// resumed payment are ignored, as their outcome is recorded by the goroutine
+// which sent them.
+func (c *controlTower) resolveHTLC(paymentHash [32]byte, chanID uint64,
+	amt btcutil.Amount, preimage [32]byte, htlcErr error) {
+
+	c.resumedMtx.Lock()
+	defer c.resumedMtx.Unlock()
+
+	if _, ok := c.resumed[paymentHash]; !ok {
+		return
+	}
+
+	payment, err := c.db.FetchPayment(paymentHash)
+	if err != nil {
+		log.Errorf("Unable to fetch resumed payment %x: %v",
+			paymentHash, err)
+		return
+	}
+
+	inFlight := payment.InFlightAttempts()
+	if len(inFlight) == 0 {
+		delete(c.resumed, paymentHash)
+		return
+	}
+
+	// As the HTLC itself doesn't identify the attempt it belongs to,
+	// we'll match it against the in flight attempts by the channel of
+	// their route's first hop, along with their amount.
+	attempt := matchAttempt(inFlight, chanID, amt)
+	if attempt == nil {
+		log.Warnf("Unable to match HTLC of %v over channel %v to an "+
+			"attempt of resumed payment %x", amt, chanID,
+			paymentHash)
+		return
+	}
+
+	c.resolveAttempt(paymentHash, attempt.AttemptID, preimage, htlcErr)
+
+	if len(inFlight) > 1 {
+		return
+	}
+
+	// With the last HTLC of the payment resolved, the payment has reached
+	// its final state. If none of its HTLCs were settled, then it has
+	// failed.
+	delete(c.resumed, paymentHash)
+	if htlcErr != nil && payment.Status != channeldb.StatusSucceeded {
+		c.fail(paymentHash, htlcErr)
+	}
+}
+
+// matchAttempt returns the attempt an HTLC of the passed amount, sent over the
+// channel with the passed short channel ID, belongs to. If chanID is zero, then
+// the attempt is matched by its amount alone. Nil is returned if no attempt
+// matches.
+func matchAttempt(attempts []*channeldb.PaymentAttempt, chanID uint64,
+	amt btcutil.Amount) *channeldb.PaymentAttempt {
+
+	for _, attempt := range attempts {
+		if attempt.TotalAmount != amt {
+			continue
+		}
+
+		if chanID == 0 || (len(attempt.Hops) != 0 &&
+			attempt.Hops[0].ChannelID == chanID) {
+
+			return attempt
+		}
+	}
+
+	return nil
+}
+
+// subscribePayment returns a new client which receives the state of the
+// target payment each time it changes, starting with its current state.
+func (c *controlTower) subscribePayment(paymentHash [32]byte) (*PaymentClient,
+	error) {
+
+	if c.db == nil {
+		return nil, ErrPaymentTrackingDisabled
+	}
+
+	// The client mutex is held while fetching the current state of the
+	// payment, such that no transition can slip in before the client is
+	// registered.
+	c.clientMtx.Lock()
+	defer c.clientMtx.Unlock()
+
+	payment, err := c.db.FetchPayment(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ntfnChan := make(chan *channeldb.TrackedPayment)
+	client := &paymentClient{
+		incoming: make(chan *channeldb.TrackedPayment, 1),
+		ntfnChan: ntfnChan,
+		exit:     make(chan struct{}),
+	}
+	client.incoming <- payment
+
+	client.wg.Add(1)
+	go client.notificationDispatcher(c.quit)
+
+	// If the payment has already reached its final state, then the
+	// client won't receive any further updates, so it isn't registered.
+	clientID := c.nextClientID
+	c.nextClientID++
+	if !isFinalPayment(payment) {
+		if c.clients[paymentHash] == nil {
+			c.clients[paymentHash] = make(map[uint64]*paymentClient)
+		}
+		c.clients[paymentHash][clientID] = client
+	}
+
+	return &PaymentClient{
+		Updates: ntfnChan,
+		Cancel: func() {
+			client.exitOnce.Do(func() {
+				close(client.exit)
+			})
+			client.wg.Wait()
+
+			c.clientMtx.Lock()
+			delete(c.clients[paymentHash], clientID)
+			if len(c.clients[paymentHash]) == 0 {
+				delete(c.clients, paymentHash)
+			}
+			c.clientMtx.Unlock()
+		},
+	}, nil
+}
+
+// notifyClients delivers the current state of the target payment to all
+// clients tracking it. Once the payment has reached its final state, the
+// clients are removed.
+func (c *controlTower) notifyClients(paymentHash [32]byte) {
+	c.clientMtx.Lock()
+	defer c.clientMtx.Unlock()
+
+	clients, ok := c.clients[paymentHash]
+	if !ok {
+		return
+	}
+
+	payment, err := c.db.FetchPayment(paymentHash)
+	if err != nil {
+		log.Errorf("Unable to fetch payment %x: %v", paymentHash, err)
+		return
+	}
+
+	for _, client := range clients {
+		select {
+		case client.incoming <- payment:
+		case <-client.exit:
+		case <-c.quit:
+			return
+		}
+	}
+
+	if isFinalPayment(payment) {
+		delete(c.clients, paymentHash)
+	}
+}
+
+// TrackPayment returns a new client which receives the state of the payment
+// with the target payment hash each time it changes, starting with its
+// current state. ErrPaymentTrackingDisabled is returned if the router wasn't
+// configured with a database to track payments within.
+func (r *ChannelRouter) TrackPayment(paymentHash [32]byte) (*PaymentClient,
+	error) {
+
+	return r.control.subscribePayment(paymentHash)
+}
+
+// ResolvePaymentHTLC is called by the switch once an HTLC sent by this node,
+// which no longer has a caller awaiting its result, has been resolved. This
+// is the case for the HTLCs of payments that were in flight when the router
+// was last shut down. The HTLC is matched to the attempt it belongs to using
+// the channel point of the channel it was sent over, along with its amount. A
+// nil error indicates the HTLC was settled, revealing the passed preimage.
+// Otherwise, the error should be the lnwire.CancelReason returned by the
+// network.
+func (r *ChannelRouter) ResolvePaymentHTLC(paymentHash [32]byte,
+	chanPoint wire.OutPoint, amt btcutil.Amount, preimage [32]byte,
+	htlcErr error) {
+
+	// If the channel isn't known to the graph, then we'll fall back to
+	// matching the HTLC by its amount alone.
+	chanID, err := r.cfg.Graph.ChannelID(&chanPoint)
+	if err != nil {
+		log.Debugf("Unable to find short channel ID of %v: %v",
+			chanPoint, err)
+		chanID = 0
+	}
+
+	r.control.resolveHTLC(paymentHash, chanID, amt, preimage, htlcErr)
+}
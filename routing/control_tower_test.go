@@ -0,0 +1,282 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+// receivePaymentUpdate waits for the next update to be delivered to the
+// passed client, asserting the payment's status.
+func receivePaymentUpdate(t *testing.T, client *PaymentClient,
+	status channeldb.PaymentStatus) *channeldb.TrackedPayment {
+
+	select {
+	case payment, ok := <-client.Updates:
+		if !ok {
+			t.Fatalf("client closed, expected status %v", status)
+		}
+		if payment.Status != status {
+			t.Fatalf("expected status %v, got %v", status,
+				payment.Status)
+		}
+		return payment
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no update received, expected status %v", status)
+	}
+
+	return nil
+}
+
+// assertClientClosed asserts that the passed client's update channel is
+// closed without any further updates.
+func assertClientClosed(t *testing.T, client *PaymentClient) {
+	select {
+	case payment, ok := <-client.Updates:
+		if ok {
+			t.Fatalf("unexpected update with status %v",
+				payment.Status)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("client not closed")
+	}
+}
+
+func TestControlTowerSendPayment(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	db, cleanUpDB, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create db: %v", err)
+	}
+	defer cleanUpDB()
+
+	// Each HTLC sent to the switch is held until a result is handed to
+	// it, allowing us to inspect the payment while it's in flight.
+	type htlcResult struct {
+		preimage [32]byte
+		err      error
+	}
+	sent := make(chan *lnwire.HTLCAddRequest, 1)
+	results := make(chan *htlcResult, 1)
+	router, err := New(Config{
+		Graph: graph,
+		SendToSwitch: func(_ *btcec.PublicKey,
+			htlc *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+			sent <- htlc
+			res := <-results
+			return res.preimage, res.err
+		},
+		PaymentControlDB: db,
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	payment := &LightningPayment{
+		Target:         aliases["satoshi"],
+		Amount:         btcutil.Amount(1000),
+		PaymentHash:    [32]byte{1},
+		PaymentRequest: []byte("lntb1fakepayreq"),
+	}
+
+	// Before the payment is sent, it can't be tracked.
+	_, err = router.TrackPayment(payment.PaymentHash)
+	if err != channeldb.ErrPaymentNotInitiated {
+		t.Fatalf("expected ErrPaymentNotInitiated, got %v", err)
+	}
+
+	sendErrs := make(chan error, 1)
+	go func() {
+		_, err := router.SendPayment(payment)
+		sendErrs <- err
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("htlc not sent")
+	}
+
+	// With the HTLC in flight, the payment should be reported as such,
+	// and a second payment to the same hash should be rejected.
+	client, err := router.TrackPayment(payment.PaymentHash)
+	if err != nil {
+		t.Fatalf("unable to track payment: %v", err)
+	}
+	defer client.Cancel()
+
+	tracked := receivePaymentUpdate(t, client, channeldb.StatusInFlight)
+	if len(tracked.Attempts) != 1 {
+		t.Fatalf("expected 1 attempt, got %v", len(tracked.Attempts))
+	}
+	if string(tracked.Info.PaymentRequest) != "lntb1fakepayreq" {
+		t.Fatalf("payment request not recorded")
+	}
+	if _, err := router.SendPayment(payment); err != channeldb.ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
+	}
+
+	// Once the HTLC is settled, the payment should succeed, after which
+	// the client receives no further updates.
+	preimage := [32]byte{2}
+	results <- &htlcResult{preimage: preimage}
+	if err := <-sendErrs; err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	tracked = receivePaymentUpdate(t, client, channeldb.StatusSucceeded)
+	if tracked.Preimage != preimage {
+		t.Fatalf("expected preimage %x, got %x", preimage,
+			tracked.Preimage)
+	}
+	assertClientClosed(t, client)
+
+	if _, err := router.SendPayment(payment); err != channeldb.ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, got %v", err)
+	}
+
+	// Next, we'll send a payment which is rejected by the destination,
+	// which should be recorded as failed.
+	payment.PaymentHash = [32]byte{3}
+	rejection := lnwire.CancelReason(lnwire.UnknownPaymentHash)
+	go func() {
+		_, err := router.SendPayment(payment)
+		sendErrs <- err
+	}()
+	<-sent
+	results <- &htlcResult{err: rejection}
+	if err := <-sendErrs; err != rejection {
+		t.Fatalf("expected %v, got %v", rejection, err)
+	}
+
+	client, err = router.TrackPayment(payment.PaymentHash)
+	if err != nil {
+		t.Fatalf("unable to track payment: %v", err)
+	}
+	tracked = receivePaymentUpdate(t, client, channeldb.StatusFailed)
+	if tracked.FailureReason != rejection.Error() {
+		t.Fatalf("expected failure reason %v, got %v", rejection,
+			tracked.FailureReason)
+	}
+	if tracked.Attempts[0].State != channeldb.AttemptFailed {
+		t.Fatalf("attempt not failed")
+	}
+	assertClientClosed(t, client)
+
+	// A failed payment may be attempted again.
+	go func() {
+		_, err := router.SendPayment(payment)
+		sendErrs <- err
+	}()
+	<-sent
+	results <- &htlcResult{preimage: preimage}
+	if err := <-sendErrs; err != nil {
+		t.Fatalf("unable to retry payment: %v", err)
+	}
+}
+
+func TestControlTowerResume(t *testing.T) {
+	db, cleanUpDB, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create db: %v", err)
+	}
+	defer cleanUpDB()
+
+	// We'll simulate a restart by recording two payments: one with a
+	// pair of HTLCs in flight, and another which never got as far as
+	// sending an HTLC.
+	pControl := channeldb.NewPaymentControl(db)
+	inFlightHash, interruptedHash := [32]byte{1}, [32]byte{2}
+	for _, hash := range [][32]byte{inFlightHash, interruptedHash} {
+		err := pControl.InitPayment(&channeldb.PaymentCreationInfo{
+			PaymentHash:  hash,
+			Value:        2000,
+			CreationDate: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("unable to init payment: %v", err)
+		}
+	}
+	// Both HTLCs are of the same amount, but are sent over different
+	// first hop channels.
+	for _, chanID := range []uint64{1, 2} {
+		_, err := pControl.RegisterAttempt(inFlightHash,
+			&channeldb.PaymentAttempt{
+				TotalAmount: 1000,
+				Hops: []channeldb.AttemptHop{
+					{ChannelID: chanID},
+				},
+			})
+		if err != nil {
+			t.Fatalf("unable to register attempt: %v", err)
+		}
+	}
+
+	quit := make(chan struct{})
+	defer close(quit)
+	control := newControlTower(db, quit)
+	if err := control.resume(); err != nil {
+		t.Fatalf("unable to resume payments: %v", err)
+	}
+
+	// The interrupted payment has no HTLCs which may still succeed, so it
+	// should have failed.
+	interrupted, err := pControl.FetchPayment(interruptedHash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if interrupted.Status != channeldb.StatusFailed {
+		t.Fatalf("expected interrupted payment to fail, got %v",
+			interrupted.Status)
+	}
+
+	client, err := control.subscribePayment(inFlightHash)
+	if err != nil {
+		t.Fatalf("unable to track payment: %v", err)
+	}
+	defer client.Cancel()
+	receivePaymentUpdate(t, client, channeldb.StatusInFlight)
+
+	// Resolving an HTLC of a payment which wasn't resumed should be
+	// ignored.
+	control.resolveHTLC([32]byte{3}, 1, 1000, [32]byte{}, nil)
+
+	// Resolving an HTLC which doesn't match any of the attempts should
+	// also be ignored.
+	cancelReason := lnwire.CancelReason(lnwire.InsufficientCapacity)
+	control.resolveHTLC(inFlightHash, 3, 1000, [32]byte{}, cancelReason)
+	control.resolveHTLC(inFlightHash, 1, 1001, [32]byte{}, cancelReason)
+
+	// The HTLC sent over the second channel is cancelled, which should
+	// fail the matching attempt, leaving the payment in flight.
+	control.resolveHTLC(inFlightHash, 2, 1000, [32]byte{}, cancelReason)
+	tracked := receivePaymentUpdate(t, client, channeldb.StatusInFlight)
+	if tracked.Attempts[1].State != channeldb.AttemptFailed {
+		t.Fatalf("expected second attempt to fail")
+	}
+
+	// Settling the remaining HTLC should complete the payment.
+	preimage := [32]byte{4}
+	control.resolveHTLC(inFlightHash, 1, 1000, preimage, nil)
+	tracked = receivePaymentUpdate(t, client, channeldb.StatusSucceeded)
+	if tracked.Preimage != preimage {
+		t.Fatalf("expected preimage %x, got %x", preimage,
+			tracked.Preimage)
+	}
+	assertClientClosed(t, client)
+
+	if _, ok := control.resumed[inFlightHash]; ok {
+		t.Fatalf("payment still resumed after completion")
+	}
+}
@@ -18,6 +18,37 @@ var (
 	// the length of that path exceeds HopLimit.
 	ErrMaxHopsExceeded = errors.New("potential path has too many hops")
 
+	// ErrFeeLimitExceeded is returned when the total fees of a route
+	// exceed the fee limit specified for the payment.
+	ErrFeeLimitExceeded = errors.New("route exceeds fee limit")
+
+	// ErrCltvLimitExceeded is returned when the total time lock of a
+	// route exceeds the time lock limit specified for the payment.
+	ErrCltvLimitExceeded = errors.New("route exceeds time lock limit")
+
 	// ErrTargetNotInNetwork is returned when a
 	ErrTargetNotInNetwork = errors.New("target not found")
+
+	// ErrPaymentTimeout is returned when a payment couldn't be completed
+	// before its timeout elapsed.
+	ErrPaymentTimeout = errors.New("payment attempt timed out")
+
+	// ErrRouterShuttingDown is returned if the router is in the process of
+	// shutting down.
+	ErrRouterShuttingDown = errors.New("router shutting down")
+
+	// ErrInvalidCustomRecord is returned when a payment carries a custom
+	// record whose type lies below CustomRecordTypeStart.
+	ErrInvalidCustomRecord = errors.New("custom record type is reserved")
+
+	// ErrPayloadTooLarge is returned when the final hop payload of a
+	// payment, along with the hops of its route, doesn't fit within the
+	// onion.
+	ErrPayloadTooLarge = errors.New("final hop payload too large for " +
+		"route")
+
+	// ErrPaymentTrackingDisabled is returned when attempting to track a
+	// payment while the router isn't configured with a database to record
+	// payments within.
+	ErrPaymentTrackingDisabled = errors.New("payment tracking disabled")
 )
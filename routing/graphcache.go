@@ -0,0 +1,290 @@
+package routing
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// cachedEdge is a directed channel edge held within the graphCache, along with
+// the vertex of the node the edge leads to. The vertex is stored alongside the
+// edge so path finding doesn't need to serialize the public key of the target
+// node each time the edge is relaxed.
+type cachedEdge struct {
+	policy *channeldb.ChannelEdge
+	to     vertex
+}
+
+// cachedChannel houses the information the graphCache requires to map a
+// directed edge update to its source node, and a spent funding outpoint to
+// the channel it closes.
+type cachedChannel struct {
+	chanPoint wire.OutPoint
+
+	// node1 and node2 are the two nodes connected by the channel. The
+	// first node is always the lexicographically smaller of the two,
+	// matching the ordering used within the channel graph.
+	node1 vertex
+	node2 vertex
+}
+
+// graphCache is an in-memory representation of the channel graph which is
+// used during path finding in order to avoid hitting the database for each
+// node visited during the graph traversal. The cache is populated from the
+// channel graph upon creation, and is then kept up to date by the
+// ChannelRouter as it processes network announcements and prunes closed
+// channels from the graph.
+//
+// NOTE: The edges held within the cache are never mutated once added.
+// Instead, an updated edge replaces the prior version entirely, which allows
+// path finding to safely hand out edges after the read lock is released.
+type graphCache struct {
+	sync.RWMutex
+
+	graph *channeldb.ChannelGraph
+
+	// source is the node that all path finding originates from.
+	source *channeldb.LightningNode
+
+	// nodes is the set of all advertised nodes within the graph.
+	nodes map[vertex]*channeldb.LightningNode
+
+	// edges maps each node to the set of its outgoing edges, keyed by
+	// channel ID.
+	edges map[vertex]map[uint64]*cachedEdge
+
+	// channels maps each known channel ID to the nodes it connects.
+	channels map[uint64]*cachedChannel
+
+	// chanPoints maps the funding outpoint of each known channel to its
+	// channel ID.
+	chanPoints map[wire.OutPoint]uint64
+}
+
+// newGraphCache creates a new graphCache, populating it with the current
+// contents of the passed channel graph.
+func newGraphCache(graph *channeldb.ChannelGraph) (*graphCache, error) {
+	source, err := graph.SourceNode()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &graphCache{
+		graph:      graph,
+		source:     source,
+		nodes:      make(map[vertex]*channeldb.LightningNode),
+		edges:      make(map[vertex]map[uint64]*cachedEdge),
+		channels:   make(map[uint64]*cachedChannel),
+		chanPoints: make(map[wire.OutPoint]uint64),
+	}
+
+	// First, we'll load all the nodes within the graph. We gather them
+	// all up front rather than loading their edges within the same
+	// callback to avoid nesting database transactions.
+	var nodes []*channeldb.LightningNode
+	err = graph.ForEachNode(func(node *channeldb.LightningNode) error {
+		nodes = append(nodes, node)
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNotFound {
+		return nil, err
+	}
+
+	// With the nodes loaded, we'll now add each of their outgoing edges.
+	for _, node := range nodes {
+		from := newVertex(node.PubKey)
+		c.nodes[from] = node
+
+		err := node.ForEachChannel(nil, func(edge *channeldb.ChannelEdge) error {
+			to := newVertex(edge.Node.PubKey)
+			c.addChannel(edge.ChannelID, &edge.ChannelPoint, from, to)
+			c.addEdge(from, to, edge)
+			return nil
+		})
+		if err != nil && err != channeldb.ErrGraphNotFound {
+			return nil, err
+		}
+	}
+
+	log.Infof("Loaded %v nodes and %v channels into graph cache",
+		len(c.nodes), len(c.channels))
+
+	return c, nil
+}
+
+// addNode adds the passed node to the cache, replacing any prior version of
+// the node.
+func (c *graphCache) addNode(node *channeldb.LightningNode) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.nodes[newVertex(node.PubKey)] = node
+}
+
+// addChannelEdge records the existence of a new channel between the two passed
+// nodes. No edges are added for the channel until the routing policy for
+// either direction is known.
+func (c *graphCache) addChannelEdge(node1, node2 *btcec.PublicKey,
+	chanPoint *wire.OutPoint, chanID uint64) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.addChannel(chanID, chanPoint, newVertex(node1), newVertex(node2))
+}
+
+// updateEdge adds or replaces the directed edge described by the passed
+// routing policy. As with the channel graph, the flags of the edge determine
+// which direction is being updated: a flag of 0 indicates the policy of the
+// first node, otherwise it's the policy of the second node. The policy being
+// replaced, if any, is returned along with the vertices of the node that
+// advertised the policy and the node the edge leads to.
+func (c *graphCache) updateEdge(edge *channeldb.ChannelEdge) (
+	*channeldb.ChannelEdge, vertex, vertex, error) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	// If we don't yet know of the nodes the channel connects, then we'll
+	// consult the channel graph in order to determine the direction of
+	// the edge.
+	channel, ok := c.channels[edge.ChannelID]
+	if !ok {
+		node1, node2, err := c.graph.ChannelNodes(edge.ChannelID)
+		if err != nil {
+			return nil, vertex{}, vertex{}, err
+		}
+
+		c.addChannel(edge.ChannelID, &edge.ChannelPoint, node1, node2)
+		channel = c.channels[edge.ChannelID]
+	}
+
+	from, to := channel.node1, channel.node2
+	if edge.Flags != 0 {
+		from, to = to, from
+	}
+
+	// If the edge doesn't carry the node it leads to, then we'll
+	// populate it using our set of known nodes. The node may not have
+	// been advertised yet, in which case we create a bare node with just
+	// its public key.
+	policy := *edge
+	if policy.Node == nil {
+		node, ok := c.nodes[to]
+		if !ok {
+			pub, err := btcec.ParsePubKey(to[:], btcec.S256())
+			if err != nil {
+				return nil, from, to, err
+			}
+			node = &channeldb.LightningNode{PubKey: pub}
+		}
+		policy.Node = node
+	}
+
+	var prevPolicy *channeldb.ChannelEdge
+	if prevEdge, ok := c.edges[from][edge.ChannelID]; ok {
+		prevPolicy = prevEdge.policy
+	}
+
+	c.addEdge(from, to, &policy)
+	return prevPolicy, from, to, nil
+}
+
+// pruneChannels removes any channels whose funding outpoint is found within
+// the passed set of spent outputs, returning a summary of each channel
+// removed.
+func (c *graphCache) pruneChannels(spentOutputs []*wire.OutPoint) []*ClosedChanSummary {
+	c.Lock()
+	defer c.Unlock()
+
+	var closedChans []*ClosedChanSummary
+	for _, op := range spentOutputs {
+		chanID, ok := c.chanPoints[*op]
+		if !ok {
+			continue
+		}
+
+		// The capacity of the channel is only known if either of its
+		// directions has been advertised.
+		channel := c.channels[chanID]
+		closedChan := &ClosedChanSummary{
+			ChanID:    chanID,
+			ChanPoint: *op,
+		}
+		for _, node := range []vertex{channel.node1, channel.node2} {
+			if edge, ok := c.edges[node][chanID]; ok {
+				closedChan.Capacity = edge.policy.Capacity
+			}
+		}
+		closedChans = append(closedChans, closedChan)
+
+		delete(c.edges[channel.node1], chanID)
+		delete(c.edges[channel.node2], chanID)
+		delete(c.channels, chanID)
+		delete(c.chanPoints, *op)
+	}
+
+	return closedChans
+}
+
+// fetchNode returns a copy of the node the passed edge leads to, preferring
+// the latest version of the node within the cache. The public key of the node
+// is also copied, allowing callers to freely modify the returned node.
+//
+// NOTE: The read lock MUST be held when calling this method.
+func (c *graphCache) fetchNode(edge *cachedEdge) *channeldb.LightningNode {
+	node, ok := c.nodes[edge.to]
+	if !ok {
+		node = edge.policy.Node
+	}
+
+	nodeCopy := *node
+	pubCopy := *node.PubKey
+	nodeCopy.PubKey = &pubCopy
+
+	return &nodeCopy
+}
+
+// addChannel records the nodes and funding outpoint of the target channel.
+//
+// NOTE: The write lock MUST be held when calling this method.
+func (c *graphCache) addChannel(chanID uint64, chanPoint *wire.OutPoint,
+	node1, node2 vertex) {
+
+	if _, ok := c.channels[chanID]; ok {
+		return
+	}
+
+	// We order the nodes in the same manner as the channel graph, with
+	// the "smaller" public key coming first.
+	if bytes.Compare(node1[:], node2[:]) == 1 {
+		node1, node2 = node2, node1
+	}
+
+	c.channels[chanID] = &cachedChannel{
+		chanPoint: *chanPoint,
+		node1:     node1,
+		node2:     node2,
+	}
+	c.chanPoints[*chanPoint] = chanID
+}
+
+// addEdge adds the directed edge from -> to to the cache, replacing any prior
+// edge in the same direction.
+//
+// NOTE: The write lock MUST be held when calling this method.
+func (c *graphCache) addEdge(from, to vertex, edge *channeldb.ChannelEdge) {
+	outgoing, ok := c.edges[from]
+	if !ok {
+		outgoing = make(map[uint64]*cachedEdge)
+		c.edges[from] = outgoing
+	}
+
+	outgoing[edge.ChannelID] = &cachedEdge{
+		policy: edge,
+		to:     to,
+	}
+}
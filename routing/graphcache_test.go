@@ -0,0 +1,247 @@
+package routing
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestGraphCacheUpdates(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	// We'll create a new node which isn't yet known to the graph, and
+	// connect it to sophon. Until the new channel is added, the node
+	// should be unreachable.
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	testAddr, err := net.ResolveTCPAddr("tcp", "192.0.0.1:8888")
+	if err != nil {
+		t.Fatalf("unable to resolve addr: %v", err)
+	}
+	newNode := &channeldb.LightningNode{
+		LastUpdate: time.Now(),
+		Address:    testAddr,
+		PubKey:     priv.PubKey(),
+		Alias:      "vitalik",
+	}
+	if err := graph.AddLightningNode(newNode); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	cache.addNode(newNode)
+
+	const paymentAmt = btcutil.Amount(100)
+	if _, err := findRoute(cache, newNode.PubKey, paymentAmt,
+		nil); err != ErrNoPathFound {
+
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// Next, we'll add the channel between sophon and the new node, along
+	// with the routing policy for both directions.
+	const chanID = 99999
+	chanPoint := wire.OutPoint{Index: 99}
+	err = graph.AddChannelEdge(aliases["sophon"], newNode.PubKey,
+		&chanPoint, chanID)
+	if err != nil {
+		t.Fatalf("unable to add channel: %v", err)
+	}
+	cache.addChannelEdge(aliases["sophon"], newNode.PubKey, &chanPoint,
+		chanID)
+
+	for _, flags := range []uint16{0, 1} {
+		edge := &channeldb.ChannelEdge{
+			ChannelID:    chanID,
+			ChannelPoint: chanPoint,
+			LastUpdate:   time.Now(),
+			Flags:        flags,
+			Expiry:       1,
+			FeeBaseMSat:  10,
+			Capacity:     100000,
+		}
+		if err := graph.UpdateEdgeInfo(edge); err != nil {
+			t.Fatalf("unable to update edge: %v", err)
+		}
+		if _, _, _, err := cache.updateEdge(edge); err != nil {
+			t.Fatalf("unable to update cache: %v", err)
+		}
+	}
+
+	// The new node should now be reachable through son goku and sophon,
+	// with the final hop leading to the new node itself.
+	route, err := findRoute(cache, newNode.PubKey, paymentAmt, nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 3 {
+		t.Fatalf("route is of incorrect length, expected %v got %v", 3,
+			len(route.Hops))
+	}
+	lastHop := route.Hops[len(route.Hops)-1]
+	if lastHop.Channel.ChannelID != chanID ||
+		lastHop.Channel.Node.Alias != newNode.Alias {
+
+		t.Fatalf("last hop should be the new channel to %v, is instead "+
+			"chan_id=%v to %v", newNode.Alias,
+			lastHop.Channel.ChannelID, lastHop.Channel.Node.Alias)
+	}
+
+	// A cache created from scratch should also contain the new channel.
+	freshCache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+	if _, err := findRoute(freshCache, newNode.PubKey, paymentAmt,
+		nil); err != nil {
+
+		t.Fatalf("unable to find route: %v", err)
+	}
+
+	// Finally, once the funding output of the channel is spent, the
+	// channel should be pruned, leaving the new node unreachable once
+	// again.
+	closedChans := cache.pruneChannels([]*wire.OutPoint{&chanPoint})
+	if len(closedChans) != 1 {
+		t.Fatalf("expected %v channel pruned, instead %v", 1,
+			len(closedChans))
+	}
+	if closedChans[0].ChanID != chanID ||
+		closedChans[0].Capacity != 100000 {
+
+		t.Fatalf("unexpected closed channel summary: %v",
+			closedChans[0])
+	}
+	if _, err := findRoute(cache, newNode.PubKey, paymentAmt,
+		nil); err != ErrNoPathFound {
+
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+}
+
+// generateTestGraph writes a randomly generated graph with the target number
+// of nodes and channels to the passed path, using the same JSON format as the
+// graphs within the testdata directory. The first node is marked as the
+// source, and the graph is guaranteed to be connected.
+func generateTestGraph(path string, numNodes, numChans int) error {
+	r := rand.New(rand.NewSource(1))
+
+	var g testGraph
+	for i := 0; i < numNodes; i++ {
+		var seed [8]byte
+		binary.BigEndian.PutUint64(seed[:], uint64(i))
+		keyBytes := sha256.Sum256(seed[:])
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(), keyBytes[:])
+
+		g.Nodes = append(g.Nodes, testNode{
+			Source: i == 0,
+			PubKey: hex.EncodeToString(pub.SerializeCompressed()),
+			Alias:  fmt.Sprintf("node%d", i),
+		})
+	}
+
+	for i := 0; i < numChans; i++ {
+		// The first numNodes-1 channels form a random spanning tree,
+		// ensuring every node is reachable from the source. The
+		// remainder of the channels connect random pairs of nodes.
+		var node1, node2 int
+		if i < numNodes-1 {
+			node1 = i + 1
+			node2 = r.Intn(i + 1)
+		} else {
+			node1 = r.Intn(numNodes)
+			node2 = r.Intn(numNodes - 1)
+			if node2 >= node1 {
+				node2++
+			}
+		}
+
+		chanID := uint64(i + 1)
+		var txid [32]byte
+		binary.BigEndian.PutUint64(txid[:], chanID)
+
+		g.Edges = append(g.Edges, testChan{
+			Node1:        g.Nodes[node1].PubKey,
+			Node2:        g.Nodes[node2].PubKey,
+			ChannelID:    chanID,
+			ChannelPoint: fmt.Sprintf("%x:0", txid),
+			Expiry:       uint16(1 + r.Intn(144)),
+			FeeBaseMsat:  int64(r.Intn(1000)),
+			FeeRate:      float64(r.Intn(1000)),
+			Capacity:     100000000,
+		})
+	}
+
+	graphJSON, err := json.Marshal(&g)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, graphJSON, 0600)
+}
+
+func BenchmarkFindRoute(b *testing.B) {
+	const (
+		numNodes = 10000
+		numChans = 50000
+	)
+
+	tempDir, err := ioutil.TempDir("", "graphcache")
+	if err != nil {
+		b.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	graphPath := filepath.Join(tempDir, "graph.json")
+	if err := generateTestGraph(graphPath, numNodes, numChans); err != nil {
+		b.Fatalf("unable to generate graph: %v", err)
+	}
+
+	graph, cleanUp, aliases, err := parseTestGraph(graphPath)
+	defer cleanUp()
+	if err != nil {
+		b.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		b.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	targets := make([]*btcec.PublicKey, 0, 100)
+	r := rand.New(rand.NewSource(2))
+	for len(targets) < cap(targets) {
+		alias := fmt.Sprintf("node%d", 1+r.Intn(numNodes-1))
+		targets = append(targets, aliases[alias])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		target := targets[i%len(targets)]
+		_, err := findRoute(cache, target, 1000, nil)
+		if err != nil && err != ErrMaxHopsExceeded {
+			b.Fatalf("unable to find route: %v", err)
+		}
+	}
+}
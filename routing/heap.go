@@ -0,0 +1,52 @@
+package routing
+
+// distanceHeap is a min-distance heap that's used within our path finding
+// algorithm to keep track of the "closest" node to our source node. It
+// implements heap.Interface, so it should be manipulated using the methods of
+// the container/heap package.
+//
+// NOTE: Rather than updating the distance of an existing entry when a shorter
+// path to a node is found, a new entry is pushed onto the heap. As a result,
+// the heap may contain stale entries for a node which must be skipped by the
+// caller once popped.
+type distanceHeap struct {
+	nodes []nodeWithDist
+}
+
+// Len returns the number of nodes in the priority queue.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (d *distanceHeap) Len() int { return len(d.nodes) }
+
+// Less returns whether the item in the priority queue with index i should sort
+// before the item with index j.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (d *distanceHeap) Less(i, j int) bool {
+	return d.nodes[i].dist < d.nodes[j].dist
+}
+
+// Swap swaps the nodes at the passed indices in the priority queue.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (d *distanceHeap) Swap(i, j int) {
+	d.nodes[i], d.nodes[j] = d.nodes[j], d.nodes[i]
+}
+
+// Push pushes the passed item onto the priority queue.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (d *distanceHeap) Push(x interface{}) {
+	d.nodes = append(d.nodes, x.(nodeWithDist))
+}
+
+// Pop removes the highest priority item (according to Less) from the priority
+// queue and returns it.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (d *distanceHeap) Pop() interface{} {
+	n := len(d.nodes)
+	x := d.nodes[n-1]
+	d.nodes = d.nodes[0 : n-1]
+	return x
+}
@@ -0,0 +1,238 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// mppPayloadType is the leading byte of a final hop payload which
+	// carries a multi-path payment record. The filler payloads used for
+	// all other hops consist of printable characters, so this value never
+	// collides with them.
+	mppPayloadType byte = 0x01
+
+	// recordPayloadType is the leading byte of a final hop payload which
+	// carries custom records in addition to the multi-path payment
+	// record.
+	recordPayloadType byte = 0x02
+
+	// CustomRecordTypeStart is the lowest type of a custom record. Types
+	// below this value are reserved for the protocol itself.
+	CustomRecordTypeStart uint64 = 65536
+
+	// KeySendRecordType is the type of the custom record which carries
+	// the preimage of a spontaneous keysend payment.
+	KeySendRecordType uint64 = 5482373484
+)
+
+// FinalHopPayload is the payload delivered to the final hop within a route
+// via the per-hop data of the Sphinx packet. It allows the destination to
+// recognize an HTLC as a single shard of a larger multi-path payment, and
+// carries any custom records intended for the destination.
+//
+// Without custom records, the payload is serialized as:
+// type (1 byte) || total_amount (8 bytes), padded with zeroes to
+// sphinx.HopPayloadSize. Otherwise, the custom records are serialized after
+// the total amount as: num_bytes (varint) || records, where each record is
+// serialized as: type (varint) || length (varint) || value, in ascending
+// order of type. The payload is then padded with zeroes to a multiple of
+// sphinx.HopPayloadSize, as a payload spanning several frames is delivered
+// to the destination within the per-hop data of several hops.
+type FinalHopPayload struct {
+	// TotalAmount is the total value of the payment the HTLC carrying this
+	// payload belongs to. If the HTLC carries less than this amount, then
+	// the destination should hold it until the remaining shards arrive.
+	TotalAmount btcutil.Amount
+
+	// CustomRecords is the set of custom records intended for the
+	// destination, keyed by their type.
+	CustomRecords map[uint64][]byte
+}
+
+// Encode serializes the payload into a byte slice suitable for inclusion as
+// the final hop's payload within a Sphinx packet. The length of the returned
+// slice is a multiple of sphinx.HopPayloadSize, each frame of which is to be
+// delivered within the per-hop data of its own hop.
+func (f *FinalHopPayload) Encode() []byte {
+	if len(f.CustomRecords) == 0 {
+		var payload [sphinx.HopPayloadSize]byte
+		payload[0] = mppPayloadType
+		binary.BigEndian.PutUint64(payload[1:9], uint64(f.TotalAmount))
+
+		return payload[:]
+	}
+
+	types := make([]uint64, 0, len(f.CustomRecords))
+	for recordType := range f.CustomRecords {
+		types = append(types, recordType)
+	}
+	sort.Sort(recordTypeSorter(types))
+
+	// Writes to a bytes.Buffer never fail, so we can safely ignore the
+	// errors below.
+	var records bytes.Buffer
+	for _, recordType := range types {
+		value := f.CustomRecords[recordType]
+		wire.WriteVarInt(&records, 0, recordType)
+		wire.WriteVarBytes(&records, 0, value)
+	}
+
+	var payload bytes.Buffer
+	payload.WriteByte(recordPayloadType)
+	var total [8]byte
+	binary.BigEndian.PutUint64(total[:], uint64(f.TotalAmount))
+	payload.Write(total[:])
+	wire.WriteVarBytes(&payload, 0, records.Bytes())
+
+	if rem := payload.Len() % sphinx.HopPayloadSize; rem != 0 {
+		payload.Write(make([]byte, sphinx.HopPayloadSize-rem))
+	}
+
+	return payload.Bytes()
+}
+
+// ValidateCustomRecords ensures that the type of each of the passed custom
+// records lies within the range reserved for custom records.
+func ValidateCustomRecords(records map[uint64][]byte) error {
+	for recordType := range records {
+		if recordType < CustomRecordTypeStart {
+			return ErrInvalidCustomRecord
+		}
+	}
+
+	return nil
+}
+
+// recordTypeSorter sorts the types of a set of custom records in ascending
+// order.
+type recordTypeSorter []uint64
+
+func (r recordTypeSorter) Len() int {
+	return len(r)
+}
+
+func (r recordTypeSorter) Less(i, j int) bool {
+	return r[i] < r[j]
+}
+
+func (r recordTypeSorter) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+// NumFrames returns the number of hop payload frames the encoded payload
+// spans.
+func (f *FinalHopPayload) NumFrames() int {
+	return len(f.Encode()) / sphinx.HopPayloadSize
+}
+
+// DecodeFinalHopPayload attempts to parse a FinalHopPayload from the per-hop
+// payload uncovered by the final hop of a route, along with any further
+// frames of the payload. If the payload doesn't carry a multi-path payment
+// record, as is the case for payments sent by older nodes, or its custom
+// records are malformed, then false is returned.
+func DecodeFinalHopPayload(payload []byte) (*FinalHopPayload, bool) {
+	if len(payload) < 9 {
+		return nil, false
+	}
+
+	finalPayload := &FinalHopPayload{
+		TotalAmount: btcutil.Amount(
+			binary.BigEndian.Uint64(payload[1:9]),
+		),
+	}
+
+	switch payload[0] {
+	case mppPayloadType:
+		return finalPayload, true
+
+	case recordPayloadType:
+		records, err := decodeCustomRecords(
+			bytes.NewReader(payload[9:]), len(payload),
+		)
+		if err != nil {
+			log.Debugf("Unable to decode custom records: %v", err)
+			return nil, false
+		}
+		finalPayload.CustomRecords = records
+
+		return finalPayload, true
+
+	default:
+		return nil, false
+	}
+}
+
+// CollectPayloadFrames gathers the frames of the final hop payload of an onion
+// packet which has been processed by the node with the passed public key. As
+// the frames of a payload spanning several frames beyond the first are
+// addressed to the destination itself, each further frame is peeled off in
+// turn, until the packet's final layer is reached. The final processed packet
+// is returned along with the concatenated payload. Packets not addressed to
+// the node itself are returned unaltered along with their hop payload.
+func CollectPayloadFrames(router *sphinx.Router, self *btcec.PublicKey,
+	packet *sphinx.ProcessedPacket,
+	assocData []byte) (*sphinx.ProcessedPacket, []byte, error) {
+
+	var selfID [20]byte
+	copy(selfID[:], btcutil.Hash160(self.SerializeCompressed()))
+
+	payload := packet.HopPayload[:]
+	for packet.Action == sphinx.MoreHops && packet.NextHop == selfID {
+		var err error
+		packet, err = router.ProcessOnionPacket(packet.Packet, assocData)
+		if err != nil {
+			return nil, nil, err
+		}
+		payload = append(payload, packet.HopPayload[:]...)
+	}
+
+	return packet, payload, nil
+}
+
+// decodeCustomRecords parses the serialized custom records of a final hop
+// payload, whose size may not exceed maxSize. The records must be serialized
+// in strictly ascending order of type.
+func decodeCustomRecords(r io.Reader,
+	maxSize int) (map[uint64][]byte, error) {
+
+	recordBytes, err := wire.ReadVarBytes(
+		r, 0, uint32(maxSize), "records",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint64][]byte)
+	recordReader := bytes.NewReader(recordBytes)
+	var lastType uint64
+	for recordReader.Len() > 0 {
+		recordType, err := wire.ReadVarInt(recordReader, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 && recordType <= lastType {
+			return nil, fmt.Errorf("record type %v out of order",
+				recordType)
+		}
+		lastType = recordType
+
+		value, err := wire.ReadVarBytes(
+			recordReader, 0, uint32(len(recordBytes)), "value",
+		)
+		if err != nil {
+			return nil, err
+		}
+		records[recordType] = value
+	}
+
+	return records, nil
+}
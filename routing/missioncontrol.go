@@ -0,0 +1,311 @@
+package routing
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// DefaultPenaltyHalfLife is the default half-life of a failure
+	// recorded by mission control. After a single half-life has elapsed
+	// since a failure, the pair is once again considered during path
+	// finding.
+	DefaultPenaltyHalfLife = time.Hour
+
+	// DefaultAttemptCost is the default virtual cost of an additional
+	// payment attempt. Path finding will pay up to this amount in extra
+	// fees in order to avoid a node pair which is certain to fail.
+	DefaultAttemptCost = btcutil.Amount(100)
+
+	// minPairProbability is the minimum estimated success probability of
+	// a node pair for it to be considered during path finding. Any pair
+	// with a lower probability is pruned from the graph.
+	minPairProbability = 0.5
+)
+
+// nodePair is a directed pair of nodes. Payment outcomes are recorded by
+// mission control at the granularity of node pairs rather than individual
+// channels, as a node will forward over any of its channels to the next hop
+// as it sees fit.
+type nodePair struct {
+	from vertex
+	to   vertex
+}
+
+// pairHistory is the most recent payment outcomes recorded for a node pair.
+type pairHistory struct {
+	lastFailTime time.Time
+	lastFailAmt  btcutil.Amount
+
+	lastSuccessTime time.Time
+	lastSuccessAmt  btcutil.Amount
+}
+
+// MissionControlPair is a snapshot of the history mission control has
+// recorded for a particular node pair, along with the estimated probability
+// of a payment of the queried amount succeeding over the pair.
+type MissionControlPair struct {
+	channeldb.MissionControlPair
+
+	// SuccessProbability is the current estimated probability of
+	// successfully forwarding the last failed amount over the pair.
+	SuccessProbability float64
+}
+
+// missionControl keeps track of the outcomes of past payment attempts in
+// order to steer path finding away from node pairs that were recently unable
+// to forward a payment. Failures are penalized heavily at first, with the
+// penalty decaying exponentially over time, allowing a failed pair to be
+// considered once again after enough time has passed. Optionally, the history
+// is persisted to the database so it survives restarts.
+//
+// NOTE: All methods of the missionControl are safe for concurrent use.
+type missionControl struct {
+	sync.Mutex
+
+	history map[nodePair]*pairHistory
+
+	// penaltyHalfLife is the half-life of a recorded failure.
+	penaltyHalfLife time.Duration
+
+	// db, if non-nil, is the database the history is persisted to.
+	db *channeldb.DB
+
+	// now returns the current time. It's a field in order to allow tests
+	// to control the passage of time.
+	now func() time.Time
+}
+
+// newMissionControl creates a new instance of mission control with the
+// passed penalty half-life. If db is non-nil, then any previously persisted
+// history is loaded, and all new outcomes are written through to the
+// database.
+func newMissionControl(penaltyHalfLife time.Duration,
+	db *channeldb.DB) (*missionControl, error) {
+
+	if penaltyHalfLife == 0 {
+		penaltyHalfLife = DefaultPenaltyHalfLife
+	}
+
+	m := &missionControl{
+		history:         make(map[nodePair]*pairHistory),
+		penaltyHalfLife: penaltyHalfLife,
+		db:              db,
+		now:             time.Now,
+	}
+
+	if db == nil {
+		return m, nil
+	}
+
+	pairs, err := db.FetchMissionControlPairs()
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range pairs {
+		m.history[nodePair{from: pair.From, to: pair.To}] = &pairHistory{
+			lastFailTime:    pair.LastFailTime,
+			lastFailAmt:     pair.LastFailAmt,
+			lastSuccessTime: pair.LastSuccessTime,
+			lastSuccessAmt:  pair.LastSuccessAmt,
+		}
+	}
+
+	log.Infof("Loaded history of %v node pairs into mission control",
+		len(pairs))
+
+	return m, nil
+}
+
+// successProbability returns the estimated probability of successfully
+// forwarding a payment of amt over the target node pair.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *missionControl) successProbability(pair nodePair,
+	amt btcutil.Amount) float64 {
+
+	history, ok := m.history[pair]
+	if !ok || history.lastFailTime.IsZero() {
+		return 1
+	}
+
+	// If the pair has successfully forwarded a payment since its last
+	// failure, or the amount is smaller than the amount that failed, then
+	// we don't apply a penalty.
+	if history.lastSuccessTime.After(history.lastFailTime) ||
+		amt < history.lastFailAmt {
+
+		return 1
+	}
+
+	// Otherwise, the probability starts at zero immediately after the
+	// failure, recovering exponentially as time passes.
+	elapsed := m.now().Sub(history.lastFailTime)
+	halfLives := float64(elapsed) / float64(m.penaltyHalfLife)
+	return 1 - math.Pow(0.5, halfLives)
+}
+
+// prunedPairs returns the set of node pairs that should be excluded from
+// path finding for a payment of amt, as their estimated success probability
+// is too low.
+func (m *missionControl) prunedPairs(amt btcutil.Amount) map[nodePair]struct{} {
+	m.Lock()
+	defer m.Unlock()
+
+	pruned := make(map[nodePair]struct{})
+	for pair := range m.history {
+		if m.successProbability(pair, amt) < minPairProbability {
+			pruned[pair] = struct{}{}
+		}
+	}
+
+	return pruned
+}
+
+// pairProbabilities returns the estimated probability of successfully
+// forwarding a payment of amt over each node pair with a recorded failure.
+// Pairs which aren't penalized at all are omitted.
+func (m *missionControl) pairProbabilities(
+	amt btcutil.Amount) map[nodePair]float64 {
+
+	m.Lock()
+	defer m.Unlock()
+
+	probabilities := make(map[nodePair]float64)
+	for pair := range m.history {
+		if p := m.successProbability(pair, amt); p < 1 {
+			probabilities[pair] = p
+		}
+	}
+
+	return probabilities
+}
+
+// reportFailure records a failed attempt to forward amt over each of the
+// passed node pairs.
+func (m *missionControl) reportFailure(amt btcutil.Amount, pairs ...nodePair) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.now()
+	for _, pair := range pairs {
+		history := m.fetchHistory(pair)
+		history.lastFailTime = now
+		history.lastFailAmt = amt
+
+		m.persist(pair, history)
+	}
+}
+
+// reportSuccess records a successful attempt to forward amt over each of the
+// passed node pairs.
+func (m *missionControl) reportSuccess(amt btcutil.Amount, pairs ...nodePair) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.now()
+	for _, pair := range pairs {
+		history := m.fetchHistory(pair)
+		history.lastSuccessTime = now
+		history.lastSuccessAmt = amt
+
+		m.persist(pair, history)
+	}
+}
+
+// fetchHistory returns the history of the target pair, creating an empty
+// entry if none yet exists.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *missionControl) fetchHistory(pair nodePair) *pairHistory {
+	history, ok := m.history[pair]
+	if !ok {
+		history = &pairHistory{}
+		m.history[pair] = history
+	}
+
+	return history
+}
+
+// persist writes the history of the pair to the database if persistence is
+// enabled. Failures are logged rather than returned, as the history is
+// merely an optimization for future path finding attempts.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *missionControl) persist(pair nodePair, history *pairHistory) {
+	if m.db == nil {
+		return
+	}
+
+	err := m.db.PutMissionControlPair(&channeldb.MissionControlPair{
+		From:            pair.from,
+		To:              pair.to,
+		LastFailTime:    history.lastFailTime,
+		LastFailAmt:     history.lastFailAmt,
+		LastSuccessTime: history.lastSuccessTime,
+		LastSuccessAmt:  history.lastSuccessAmt,
+	})
+	if err != nil {
+		log.Errorf("unable to persist mission control pair: %v", err)
+	}
+}
+
+// snapshot returns the history of all node pairs known to mission control.
+func (m *missionControl) snapshot() []*MissionControlPair {
+	m.Lock()
+	defer m.Unlock()
+
+	pairs := make([]*MissionControlPair, 0, len(m.history))
+	for pair, history := range m.history {
+		pairs = append(pairs, &MissionControlPair{
+			MissionControlPair: channeldb.MissionControlPair{
+				From:            pair.from,
+				To:              pair.to,
+				LastFailTime:    history.lastFailTime,
+				LastFailAmt:     history.lastFailAmt,
+				LastSuccessTime: history.lastSuccessTime,
+				LastSuccessAmt:  history.lastSuccessAmt,
+			},
+			SuccessProbability: m.successProbability(
+				pair, history.lastFailAmt,
+			),
+		})
+	}
+
+	return pairs
+}
+
+// reset clears all history recorded by mission control, including any
+// history persisted to the database.
+func (m *missionControl) reset() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.db != nil {
+		if err := m.db.ResetMissionControl(); err != nil {
+			return err
+		}
+	}
+
+	m.history = make(map[nodePair]*pairHistory)
+	return nil
+}
+
+// routePairs returns the directed node pairs traversed by the passed route,
+// starting with the pair formed by the source node and the first hop.
+func routePairs(source vertex, route *Route) []nodePair {
+	pairs := make([]nodePair, 0, len(route.Hops))
+
+	prev := source
+	for _, hop := range route.Hops {
+		next := newVertex(hop.Channel.Node.PubKey)
+		pairs = append(pairs, nodePair{from: prev, to: next})
+		prev = next
+	}
+
+	return pairs
+}
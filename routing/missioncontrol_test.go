@@ -0,0 +1,159 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestMissionControlProbability(t *testing.T) {
+	mc, err := newMissionControl(time.Hour, nil)
+	if err != nil {
+		t.Fatalf("unable to create mission control: %v", err)
+	}
+
+	now := time.Unix(10000, 0)
+	mc.now = func() time.Time { return now }
+
+	pair := nodePair{from: vertex{1}, to: vertex{2}}
+
+	// Without any history, the pair shouldn't be pruned.
+	if len(mc.prunedPairs(1000)) != 0 {
+		t.Fatalf("pair without history shouldn't be pruned")
+	}
+
+	// Immediately after a failure, the pair should be pruned for the
+	// failed amount and above, but not for smaller amounts.
+	mc.reportFailure(1000, pair)
+	if _, ok := mc.prunedPairs(1000)[pair]; !ok {
+		t.Fatalf("failed pair should be pruned")
+	}
+	if _, ok := mc.prunedPairs(500)[pair]; ok {
+		t.Fatalf("failed pair shouldn't be pruned for smaller amount")
+	}
+
+	// Once the penalty has partially decayed, the pair should still be
+	// pruned.
+	now = now.Add(30 * time.Minute)
+	if _, ok := mc.prunedPairs(1000)[pair]; !ok {
+		t.Fatalf("failed pair should be pruned")
+	}
+
+	// After a full half-life, the pair should once again be considered.
+	now = now.Add(30 * time.Minute)
+	if _, ok := mc.prunedPairs(1000)[pair]; ok {
+		t.Fatalf("pair should no longer be pruned")
+	}
+
+	// A new failure followed by a success should clear the penalty
+	// entirely.
+	mc.reportFailure(1000, pair)
+	now = now.Add(time.Second)
+	mc.reportSuccess(1000, pair)
+	if _, ok := mc.prunedPairs(1000)[pair]; ok {
+		t.Fatalf("pair should no longer be pruned after success")
+	}
+
+	// Finally, resetting mission control should remove all history.
+	if err := mc.reset(); err != nil {
+		t.Fatalf("unable to reset mission control: %v", err)
+	}
+	if len(mc.snapshot()) != 0 {
+		t.Fatalf("history remains after reset")
+	}
+}
+
+func TestMissionControlPersistence(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to create db: %v", err)
+	}
+	defer cleanUp()
+
+	mc, err := newMissionControl(time.Hour, db)
+	if err != nil {
+		t.Fatalf("unable to create mission control: %v", err)
+	}
+
+	pair := nodePair{from: vertex{1}, to: vertex{2}}
+	mc.reportFailure(1000, pair)
+
+	// A fresh instance of mission control backed by the same database
+	// should load the previously recorded failure.
+	mc, err = newMissionControl(time.Hour, db)
+	if err != nil {
+		t.Fatalf("unable to create mission control: %v", err)
+	}
+	if _, ok := mc.prunedPairs(1000)[pair]; !ok {
+		t.Fatalf("persisted failure wasn't loaded")
+	}
+}
+
+func TestSendPaymentRetry(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	// We'll fail any payment sent directly to luo ji over our own
+	// channel, forcing the router to fall back to the route through
+	// satoshi.
+	var firstHops []*btcec.PublicKey
+	router, err := New(Config{
+		Graph: graph,
+		SendToSwitch: func(firstHop *btcec.PublicKey,
+			_ *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+			firstHops = append(firstHops, firstHop)
+			if firstHop.IsEqual(aliases["luoji"]) {
+				return [32]byte{}, errors.New("link unavailable")
+			}
+			return [32]byte{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	payment := &LightningPayment{
+		Target: aliases["luoji"],
+		Amount: btcutil.Amount(100),
+	}
+	routes, err := router.SendPayment(payment)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	route := routes[0]
+
+	if len(firstHops) != 2 {
+		t.Fatalf("expected %v attempts, instead have %v", 2,
+			len(firstHops))
+	}
+	if !route.Hops[0].Channel.Node.PubKey.IsEqual(aliases["satoshi"]) {
+		t.Fatalf("first hop should be satoshi, is instead: %v",
+			route.Hops[0].Channel.Node.Alias)
+	}
+
+	// If the destination rejects the payment, then no further attempts
+	// should be made.
+	firstHops = nil
+	router.cfg.SendToSwitch = func(firstHop *btcec.PublicKey,
+		_ *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+		firstHops = append(firstHops, firstHop)
+		return [32]byte{}, lnwire.CancelReason(lnwire.UnknownPaymentHash)
+	}
+	payment.Target = aliases["sophon"]
+	if _, err := router.SendPayment(payment); err == nil {
+		t.Fatalf("payment should have failed")
+	}
+	if len(firstHops) != 1 {
+		t.Fatalf("expected a single attempt, instead have %v",
+			len(firstHops))
+	}
+}
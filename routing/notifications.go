@@ -0,0 +1,272 @@
+package routing
+
+import (
+	"image/color"
+	"net"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// maxPendingTopologyChanges is the maximum number of topology changes which
+// may be queued for a single client. A client which falls this far behind is
+// dropped, rather than allowing its queue to grow without bound.
+const maxPendingTopologyChanges = 1000
+
+// TopologyChange represents a new set of modifications to the channel graph.
+// Topology changes will be dispatched in real-time as the ChannelRouter
+// validates and processes modifications to the channel graph, in the order
+// in which they were applied.
+type TopologyChange struct {
+	// NodeUpdates is a slice of nodes which are either new to the channel
+	// graph, or have had their attributes updated in an authenticated
+	// manner.
+	NodeUpdates []*NetworkNodeUpdate
+
+	// ChannelEdgeUpdates is a slice of channel edges which are either
+	// newly opened and authenticated, or have had their routing policies
+	// updated.
+	ChannelEdgeUpdates []*ChannelEdgeUpdate
+
+	// ClosedChannels contains a slice of summaries of the channels which
+	// have been removed from the channel graph, either because their
+	// funding output has been spent, or because they've been pruned as
+	// zombies.
+	ClosedChannels []*ClosedChanSummary
+}
+
+// isEmpty returns true if the TopologyChange is empty. A TopologyChange is
+// considered empty if it contains no *new* updates of any type.
+func (t *TopologyChange) isEmpty() bool {
+	return len(t.NodeUpdates) == 0 && len(t.ChannelEdgeUpdates) == 0 &&
+		len(t.ClosedChannels) == 0
+}
+
+// NetworkNodeUpdate is an update for a node within the Lightning Network. A
+// NetworkNodeUpdate is sent out either when a new node joins the network, or
+// a node broadcasts a new update with a newer time stamp that supersedes its
+// old update. All updates are properly authenticated.
+type NetworkNodeUpdate struct {
+	// Address is the TCP address the node is reachable over.
+	Address *net.TCPAddr
+
+	// IdentityKey is the identity public key of the target node. This is
+	// used to encrypt onion blobs as well as to authenticate any new
+	// updates.
+	IdentityKey *btcec.PublicKey
+
+	// Alias is the alias or nick name of the node.
+	Alias string
+
+	// Color is the node's color.
+	Color color.RGBA
+}
+
+// ChannelEdgeUpdate is an update for a new channel within the ChannelGraph.
+// This update is sent out once a new authenticated channel edge is
+// discovered within the network, or once the routing policy of an existing
+// directed edge is updated. The prior routing policy of the directed edge is
+// included, allowing clients to determine which attributes of the policy
+// have changed.
+type ChannelEdgeUpdate struct {
+	// ChanID is the unique short channel ID for the channel. This encodes
+	// where in the blockchain the channel's funding transaction was
+	// originally confirmed.
+	ChanID uint64
+
+	// ChanPoint is the outpoint which represents the multi-sig funding
+	// output for the channel.
+	ChanPoint wire.OutPoint
+
+	// Capacity is the capacity of the newly created channel.
+	Capacity btcutil.Amount
+
+	// Policy is the updated routing policy of the directed edge.
+	Policy *channeldb.ChannelEdge
+
+	// PrevPolicy is the routing policy of the directed edge prior to the
+	// update. This is nil if this is the first update for the directed
+	// edge.
+	PrevPolicy *channeldb.ChannelEdge
+
+	// AdvertisingNode is the node that's advertising this edge.
+	AdvertisingNode *btcec.PublicKey
+
+	// ConnectingNode is the node that the advertising node connects to.
+	ConnectingNode *btcec.PublicKey
+}
+
+// ClosedChanSummary is a summary of a channel which has been removed from the
+// channel graph. The summary serves to notify clients that a channel has
+// been closed, or has been pruned as a zombie.
+type ClosedChanSummary struct {
+	// ChanID is the short-channel ID which uniquely identifies the
+	// channel.
+	ChanID uint64
+
+	// Capacity was the total capacity of the channel before it was
+	// removed. This is zero if neither direction of the channel was ever
+	// advertised.
+	Capacity btcutil.Amount
+
+	// ClosedHeight is the height in the chain that the channel was closed
+	// at. This is zero for zombie channels, as those are pruned due to
+	// their staleness rather than an on-chain event.
+	ClosedHeight uint32
+
+	// ChanPoint is the funding point, or the multi-sig utxo which
+	// previously represented the channel.
+	ChanPoint wire.OutPoint
+}
+
+// TopologyClient represents an intent to receive notifications from the
+// channel router regarding changes to the topology of the channel graph. The
+// TopologyChanges channel will be sent upon with new updates to the channel
+// graph in real-time as they're encountered.
+type TopologyClient struct {
+	// TopologyChanges is a receive only channel that new channel graph
+	// updates will be sent over. The channel is closed once the client
+	// has been cancelled, or the router is shutting down.
+	TopologyChanges <-chan *TopologyChange
+
+	// Cancel is a function closure that should be executed when the
+	// client wishes to cancel their notification intent. Doing so allows
+	// the ChannelRouter to free up resources.
+	Cancel func()
+}
+
+// topologyClient is a data-structure used by the channel router to couple
+// the client's notification channel along with a special "exit" channel
+// that can be used to cancel all lingering goroutines blocked on a send to
+// the notification channel.
+type topologyClient struct {
+	// incoming is the channel over which new topology changes are handed
+	// to the client's dispatcher.
+	incoming chan *TopologyChange
+
+	// ntfnChan is a send-only channel that's used to propagate
+	// notification s from the channel router to an instance of a
+	// topologyClient client.
+	ntfnChan chan<- *TopologyChange
+
+	// exit is a channel that is used internally by the channel router to
+	// cancel any active un-consumed goroutine notifications.
+	exit     chan struct{}
+	exitOnce sync.Once
+
+	wg sync.WaitGroup
+}
+
+// notificationDispatcher queues all topology changes handed to the client,
+// delivering them over the client's notification channel in the order they
+// were received. Queueing the changes ensures a slow client never blocks the
+// router from processing further modifications to the channel graph. If the
+// client falls more than maxPendingTopologyChanges behind, then it's dropped
+// and its notification channel closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *topologyClient) notificationDispatcher(quit chan struct{}) {
+	defer c.wg.Done()
+	defer close(c.ntfnChan)
+
+	var pending []*TopologyChange
+	for {
+		// We'll only attempt to deliver a notification if we have one
+		// pending, as a send on a nil channel blocks forever.
+		var (
+			next     *TopologyChange
+			ntfnChan chan<- *TopologyChange
+		)
+		if len(pending) != 0 {
+			next = pending[0]
+			ntfnChan = c.ntfnChan
+		}
+
+		select {
+		case change := <-c.incoming:
+			if len(pending) >= maxPendingTopologyChanges {
+				log.Warnf("Dropping topology client with %v "+
+					"undelivered changes", len(pending))
+				c.exitOnce.Do(func() {
+					close(c.exit)
+				})
+				return
+			}
+			pending = append(pending, change)
+
+		case ntfnChan <- next:
+			pending[0] = nil
+			pending = pending[1:]
+
+		case <-c.exit:
+			return
+
+		case <-quit:
+			return
+		}
+	}
+}
+
+// SubscribeTopology returns a new topology client which can be used by the
+// caller to receive notifications whenever a change in the channel graph
+// topology occurs. Changes that will be sent at notifications include: new
+// nodes appearing, node updating their attributes, new channels, channels
+// closing, and updates in the routing policies of a channel's directed
+// edges.
+func (r *ChannelRouter) SubscribeTopology() (*TopologyClient, error) {
+	ntfnChan := make(chan *TopologyChange)
+	client := &topologyClient{
+		incoming: make(chan *TopologyChange),
+		ntfnChan: ntfnChan,
+		exit:     make(chan struct{}),
+	}
+
+	r.topologyClientMtx.Lock()
+	clientID := r.nextTopologyClientID
+	r.nextTopologyClientID++
+	r.topologyClients[clientID] = client
+	r.topologyClientMtx.Unlock()
+
+	client.wg.Add(1)
+	go client.notificationDispatcher(r.quit)
+
+	return &TopologyClient{
+		TopologyChanges: ntfnChan,
+		Cancel: func() {
+			// We'll first signal the client's dispatcher to exit,
+			// which unblocks any pending notifications, before
+			// removing the client.
+			client.exitOnce.Do(func() {
+				close(client.exit)
+			})
+			client.wg.Wait()
+
+			r.topologyClientMtx.Lock()
+			delete(r.topologyClients, clientID)
+			r.topologyClientMtx.Unlock()
+		},
+	}, nil
+}
+
+// notifyTopologyChange notifies all registered clients of a new change in
+// the channel graph topology.
+func (r *ChannelRouter) notifyTopologyChange(change *TopologyChange) {
+	if change.isEmpty() {
+		return
+	}
+
+	r.topologyClientMtx.RLock()
+	defer r.topologyClientMtx.RUnlock()
+
+	for _, client := range r.topologyClients {
+		select {
+		case client.incoming <- change:
+		case <-client.exit:
+		case <-r.quit:
+			return
+		}
+	}
+}
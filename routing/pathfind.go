@@ -1,6 +1,8 @@
 package routing
 
 import (
+	"container/heap"
+	"fmt"
 	"math"
 
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -75,6 +77,31 @@ type Hop struct {
 	Fee btcutil.Amount
 }
 
+// HopHint is a routing hint that describes a channel which may not be known
+// to the channel graph, such as a private channel. Payment requests carry a
+// set of these hints in order to allow the payer to reach a destination that
+// sits behind such channels.
+type HopHint struct {
+	// NodeID is the public key of the node at the start of the channel.
+	NodeID *btcec.PublicKey
+
+	// ChannelID is the short channel ID of the channel.
+	ChannelID uint64
+
+	// FeeBaseMSat is the base fee charged by the node to forward a
+	// payment over the channel.
+	FeeBaseMSat btcutil.Amount
+
+	// FeeProportionalMillionths is the fee rate, in millionths of a
+	// satoshi, charged by the node for each satoshi forwarded over the
+	// channel.
+	FeeProportionalMillionths btcutil.Amount
+
+	// CLTVExpiryDelta is the time lock delta the node requires for the
+	// channel.
+	CLTVExpiryDelta uint16
+}
+
 // computeFee computes the fee to forward an HTLC of `amt` satoshis over the
 // passed active payment channel. This value is currently computed as specified
 // in BOLT07, but will likely change in the near future.
@@ -85,23 +112,22 @@ func computeFee(amt btcutil.Amount, edge *channeldb.ChannelEdge) btcutil.Amount
 // newRoute returns a fully valid route between the source and target that's
 // capable of supporting a payment of `amtToSend` after fees are fully
 // computed. IF the route is too long, or the selected path cannot support the
-// fully payment including fees, then a non-nil error is returned. prevHop maps
-// a vertex to the channel required to get to it.
-func newRoute(amtToSend btcutil.Amount, source, target vertex,
-	prevHop map[vertex]edgeWithPrev) (*Route, error) {
-
-	// If the potential route if below the max hop limit, then we'll use
-	// the prevHop map to unravel the path. We end up with a list of edges
-	// in the reverse direction which we'll use to properly calculate the
-	// timelock and fee values.
-	pathEdges := make([]*channeldb.ChannelEdge, 0, len(prevHop))
-	prev := target
-	for prev != source { // TODO(roasbeef): assumes no cycles
-		// Add the current hop to the limit of path edges then walk
-		// backwards from this hop via the prev pointer for this hop
-		// within the prevHop map.
-		pathEdges = append(pathEdges, prevHop[prev].edge)
-		prev = newVertex(prevHop[prev].prevNode)
+// fully payment including fees, then a non-nil error is returned. The
+// pathEdges slice holds the edges of the path in the forward direction, from
+// the source to the target. The bandwidthHints map, if non-nil, houses the
+// current spendable balance of each of the source node's outgoing channels,
+// keyed by channel ID. The finalCLTVDelta is the time lock delta required by
+// the target for the final hop, which is included in the route's total time
+// lock.
+func newRoute(amtToSend btcutil.Amount, path []*channeldb.ChannelEdge,
+	bandwidthHints map[uint64]btcutil.Amount,
+	finalCLTVDelta uint16) (*Route, error) {
+
+	// We'll calculate the timelock and fee values by walking the path
+	// backwards, so we first reverse the list of path edges.
+	pathEdges := make([]*channeldb.ChannelEdge, len(path))
+	for i, edge := range path {
+		pathEdges[len(path)-1-i] = edge
 	}
 
 	// The route is invalid if it spans more than 20 hops. The current
@@ -113,7 +139,8 @@ func newRoute(amtToSend btcutil.Amount, source, target vertex,
 	}
 
 	route := &Route{
-		Hops: make([]*Hop, len(pathEdges)),
+		TotalTimeLock: uint32(finalCLTVDelta),
+		Hops:          make([]*Hop, len(pathEdges)),
 	}
 
 	// The running amount is the total amount of satoshis required at this
@@ -141,6 +168,19 @@ func newRoute(amtToSend btcutil.Amount, source, target vertex,
 			return nil, ErrInsufficientCapacity
 		}
 
+		// If this is the first hop in the route, then the channel is
+		// one of our own. In this case we have a much better view of
+		// the channel than its advertised capacity: the current
+		// spendable balance as reported by the switch. As the first
+		// hop carries the total amount of the payment (including all
+		// fees), we ensure the full running amount can be supported.
+		if i == len(pathEdges)-1 {
+			bandwidth, ok := bandwidthHints[edge.ChannelID]
+			if ok && runningAmt > bandwidth {
+				return nil, ErrInsufficientCapacity
+			}
+		}
+
 		// We don't pay any fees to ourselves on the first-hop channel,
 		// so we don't tally up the running fee and amount.
 		if i != len(pathEdges)-1 {
@@ -189,27 +229,157 @@ func newVertex(pub *btcec.PublicKey) vertex {
 }
 
 // nodeWithDist is a helper struct that couples the distance from the current
-// source to a node with a pointer to the node itself.
+// source to a node with the vertex of the node itself.
 type nodeWithDist struct {
 	dist float64
-	node *channeldb.LightningNode
+	node vertex
 }
 
 // edgeWithPrev is a helper struct used in path finding that couples an
 // directional edge with the node's ID in the opposite direction.
 type edgeWithPrev struct {
-	edge     *channeldb.ChannelEdge
-	prevNode *btcec.PublicKey
+	edge     *cachedEdge
+	prevNode vertex
+}
+
+// restrictParams wraps the set of restrictions that a path returned by
+// findPath must adhere to, along with the auxiliary information used to
+// weigh the edges considered. The zero value imposes no restrictions.
+type restrictParams struct {
+	// ignoredNodes is the set of nodes that the path must not traverse.
+	ignoredNodes map[vertex]struct{}
+
+	// ignoredEdges is the set of channels, identified by their channel
+	// ID, that the path must not traverse.
+	ignoredEdges map[uint64]struct{}
+
+	// ignoredPairs is the set of directed node pairs that mission control
+	// has deemed too unreliable to forward the payment.
+	ignoredPairs map[nodePair]struct{}
+
+	// pairProbabilities houses the estimated success probability of each
+	// node pair mission control has recorded a failure for. Pairs absent
+	// from the map are assumed to always succeed.
+	pairProbabilities map[nodePair]float64
+
+	// attemptCost is the virtual cost of an additional payment attempt.
+	// If non-zero, edges over pairs that are likely to fail are penalized
+	// in proportion to the expected number of extra attempts.
+	attemptCost btcutil.Amount
+
+	// bandwidthHints, keyed by channel ID, supplies the current spendable
+	// balance of each of our outgoing channels.
+	bandwidthHints map[uint64]btcutil.Amount
+
+	// feeLimit is the maximum total fee the route may carry. A value of
+	// zero indicates no limit.
+	feeLimit btcutil.Amount
+
+	// cltvLimit is the maximum total time lock the route may carry. A
+	// value of zero indicates no limit.
+	cltvLimit uint32
+
+	// finalCLTVDelta is the time lock delta the target requires for the
+	// final hop of the route.
+	finalCLTVDelta uint16
+
+	// outgoingChanID, if non-zero, is the only one of our channels the
+	// route may use as its first hop.
+	outgoingChanID uint64
+
+	// lastHop, if non-nil, is the node the route must pass through
+	// immediately before reaching the target.
+	lastHop *vertex
+
+	// additionalEdges is a set of ephemeral edges, keyed by the node they
+	// originate from, that are considered during path finding in addition
+	// to the edges within the graph. These are typically derived from the
+	// route hints of a payment request.
+	additionalEdges map[vertex]map[uint64]*cachedEdge
+
+	// extraPayloadHops is the number of hops within the onion taken up by
+	// the frames of the final hop payload beyond its first, which reduce
+	// the number of hops the route may span.
+	extraPayloadHops int
+}
+
+// hopLimit returns the maximum number of hops the route may span, leaving
+// room within the onion for the frames of the final hop payload.
+func (r *restrictParams) hopLimit() int {
+	return HopLimit - r.extraPayloadHops
+}
+
+// hopHintEdges converts the passed route hints into a set of ephemeral edges
+// that lead towards the target. Each route hint is a chain of hops, with the
+// final hop of the chain connecting directly to the target.
+func hopHintEdges(target *btcec.PublicKey,
+	routeHints [][]HopHint) map[vertex]map[uint64]*cachedEdge {
+
+	edges := make(map[vertex]map[uint64]*cachedEdge)
+	for _, routeHint := range routeHints {
+		for i, hint := range routeHint {
+			// The hop leads to the node of the next hint within
+			// the chain, or the target itself if this is the
+			// final hop.
+			toNode := target
+			if i < len(routeHint)-1 {
+				toNode = routeHint[i+1].NodeID
+			}
+
+			// As the capacity of the hinted channel isn't known,
+			// we'll optimistically assume it's able to carry any
+			// payment.
+			edge := &channeldb.ChannelEdge{
+				ChannelID:                 hint.ChannelID,
+				Expiry:                    hint.CLTVExpiryDelta,
+				FeeBaseMSat:               hint.FeeBaseMSat,
+				FeeProportionalMillionths: hint.FeeProportionalMillionths,
+				Capacity:                  btcutil.MaxSatoshi,
+				Node: &channeldb.LightningNode{
+					PubKey: toNode,
+				},
+			}
+
+			from := newVertex(hint.NodeID)
+			if _, ok := edges[from]; !ok {
+				edges[from] = make(map[uint64]*cachedEdge)
+			}
+			edges[from][hint.ChannelID] = &cachedEdge{
+				policy: edge,
+				to:     newVertex(toNode),
+			}
+		}
+	}
+
+	return edges
 }
 
+// riskFactorBillionths controls the influence of the time lock of an edge on
+// its weight. It's expressed as the fraction of the payment amount, in
+// billionths, that we're willing to pay in fees per block in order to
+// reduce the time our funds may be locked up in the case of a failure.
+const riskFactorBillionths = 15
+
 // edgeWeight computes the weight of an edge. This value is used when searching
-// for the shortest path within the channel graph between two nodes. Currently
-// this is just 1 + the cltv delta value required at this hop, this value
-// should be tuned with experimental and empirical data.
-//
-// TODO(roasbeef): compute robust weight metric
-func edgeWeight(e *channeldb.ChannelEdge) float64 {
-	return float64(1 + e.Expiry)
+// for the shortest path within the channel graph between two nodes. The
+// weight is the sum of the fee charged by the edge to forward amt, a penalty
+// for the time lock the edge adds to the route which is proportional to the
+// amount, and a base weight of 1 in order to favor shorter paths when all
+// else is equal. If the success probability of the edge is below 1, then an
+// additional penalty is applied based on the virtual cost of an extra
+// payment attempt.
+func edgeWeight(amt, fee btcutil.Amount, e *channeldb.ChannelEdge,
+	probability float64, attemptCost btcutil.Amount) float64 {
+
+	timeLockPenalty := float64(amt) * float64(e.Expiry) *
+		riskFactorBillionths / 1000000000
+
+	weight := 1 + float64(fee) + timeLockPenalty
+	if attemptCost != 0 && probability > 0 && probability < 1 {
+		weight += float64(attemptCost) * (1/probability - 1)
+	}
+
+	return weight
 }
 
 // findRoute attempts to find a path from the source node within the
@@ -221,127 +391,541 @@ func edgeWeight(e *channeldb.ChannelEdge) float64 {
 // we calculate the required fee and time lock values running backwards along
 // the route. The route that's selected is the one with the lowest total fee.
 //
-// TODO(roasbeef): make member, add caching
-//  * add k-path
-func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
-	amt btcutil.Amount) (*Route, error) {
-
-	// First initialize empty list of all the node that we've yet to
-	// visited.
-	// TODO(roasbeef): make into incremental fibonacci heap rather than
-	// loading all into memory.
-	var unvisited []*channeldb.LightningNode
-
-	// For each node/vertex the graph we create an entry in the distance
-	// map for the node set with a distance of "infinity".  We also mark
-	// add the node to our set of unvisited nodes.
-	distance := make(map[vertex]nodeWithDist)
-	if err := graph.ForEachNode(func(node *channeldb.LightningNode) error {
-		// TODO(roasbeef): with larger graph can just use disk seeks
-		// with a visited map
-		distance[newVertex(node.PubKey)] = nodeWithDist{
-			dist: infinity,
-			node: node,
-		}
+// The passed restrictions, if non-nil, constrain the set of edges considered
+// during the search. When present, the bandwidth hints are used in place of
+// the advertised capacity for our own channels, and any channel with
+// insufficient (or zero, if the link is inactive) bandwidth is skipped
+// entirely. Any edge connecting a directed node pair within the ignored pairs
+// set is excluded from the search. This set is populated by mission control
+// with the pairs that have recently failed to forward a payment.
+func findRoute(graph *graphCache, target *btcec.PublicKey,
+	amt btcutil.Amount, r *restrictParams) (*Route, error) {
+
+	if r == nil {
+		r = &restrictParams{}
+	}
 
-		unvisited = append(unvisited, node)
-		return nil
-	}); err != nil {
+	path, err := findPath(graph, graph.source, target, amt, r)
+	if err != nil {
 		return nil, err
 	}
 
-	// Next we obtain the source node from the graph, and initialize it
-	// with a distance of 0. This indicates our starting point in the graph
-	// traversal.
-	sourceNode, err := graph.SourceNode()
+	// With the path found, we construct a new route which calculate the
+	// relevant total fees and proper time lock values for each hop.
+	route, err := newRoute(amt, path, r.bandwidthHints, r.finalCLTVDelta)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := r.checkRoute(route); err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
+
+// checkRoute ensures the fully constructed route doesn't exceed the hop, fee
+// or time lock limits of the restrictions.
+func (r *restrictParams) checkRoute(route *Route) error {
+	if len(route.Hops) > r.hopLimit() {
+		return ErrMaxHopsExceeded
+	}
+	if r.feeLimit != 0 && route.TotalFees > r.feeLimit {
+		return ErrFeeLimitExceeded
+	}
+	if r.cltvLimit != 0 && route.TotalTimeLock > r.cltvLimit {
+		return ErrCltvLimitExceeded
+	}
+
+	return nil
+}
+
+// findPath attempts to find a path from the passed source node to the target
+// node using a modified version of Dijkstra's algorithm. The traversal is
+// carried out over the in-memory graph cache, using a min-distance heap to
+// select the next node to visit. The returned path consists of copies of the
+// edges traversed in the forward direction. Any edge that's unable to carry
+// amt, or that violates the passed restrictions is excluded from the search.
+func findPath(graph *graphCache, sourceNode *channeldb.LightningNode,
+	target *btcec.PublicKey, amt btcutil.Amount,
+	r *restrictParams) ([]*channeldb.ChannelEdge, error) {
+
+	graph.RLock()
+	defer graph.RUnlock()
+
+	// The self vertex is our own node. Note that it may differ from the
+	// source vertex if we're searching for a spur path from a node
+	// further along a route.
+	selfVertex := newVertex(graph.source.PubKey)
+
+	// We'll track the best known distance to each node within the
+	// distance map. Any node absent from the map has a distance of
+	// "infinity". We start by initializing the source node with a
+	// distance of 0, which indicates our starting point in the graph
+	// traversal. Alongside the distance, we also track the fees and time
+	// lock accumulated along the best path to each node in order to
+	// enforce the fee and time lock limits. The time lock starts out at
+	// the delta required by the target for the final hop.
 	sourceVertex := newVertex(sourceNode.PubKey)
-	distance[sourceVertex] = nodeWithDist{
-		dist: 0,
-		node: sourceNode,
+	targetVertex := newVertex(target)
+	distance := map[vertex]float64{
+		sourceVertex: 0,
+	}
+	fees := make(map[vertex]btcutil.Amount)
+	timeLocks := map[vertex]uint32{
+		sourceVertex: uint32(r.finalCLTVDelta),
 	}
 
+	// The heap holds the set of nodes we've reached, but have yet to
+	// visit, ordered by their distance from the source.
+	var nodeHeap distanceHeap
+	heap.Push(&nodeHeap, nodeWithDist{
+		dist: 0,
+		node: sourceVertex,
+	})
+
 	// We'll use this map as a series of "previous" hop pointers. So to get
 	// to `vertex` we'll take the edge that it's mapped to within `prev`.
 	prev := make(map[vertex]edgeWithPrev)
-
-	for len(unvisited) != 0 {
-		var bestNode *channeldb.LightningNode
-		smallestDist := infinity
-
-		// First we examine our list of unvisited nodes, for the most
-		// optimal vertex to examine next.
-		for i, node := range unvisited {
-			// The "best" node to visit next is node with the
-			// smallest distance from the source of all the
-			// unvisited nodes.
-			v := newVertex(node.PubKey)
-			if nodeInfo := distance[v]; nodeInfo.dist < smallestDist {
-				smallestDist = nodeInfo.dist
-				bestNode = nodeInfo.node
-
-				// Since we're going to visit this node, we can
-				// remove it from the set of unvisited nodes.
-				copy(unvisited[i:], unvisited[i+1:])
-				unvisited[len(unvisited)-1] = nil // Avoid GC leak.
-				unvisited = unvisited[:len(unvisited)-1]
-
-				break
-			}
+	visited := make(map[vertex]struct{})
+
+	// We'll also note whether any edges were skipped due to their
+	// capacity, so we're able to report the reason a path couldn't be
+	// found.
+	var insufficientCapacity bool
+
+	for nodeHeap.Len() != 0 {
+		// Fetch the node that's closest to the source from the heap.
+		// As we push a fresh entry each time a shorter path to a node
+		// is found, we skip any stale entries for nodes we've already
+		// visited.
+		best := heap.Pop(&nodeHeap).(nodeWithDist)
+		pivot := best.node
+		if _, ok := visited[pivot]; ok {
+			continue
 		}
+		visited[pivot] = struct{}{}
 
 		// If we've reached our target, then we're done here and can
 		// exit the graph traversal early.
-		if bestNode == nil || bestNode.PubKey.IsEqual(target) {
+		if pivot == targetVertex {
 			break
 		}
 
+		// If we've been provided any additional edges for this node,
+		// then we'll consider them alongside the edges within the
+		// graph. Edges known to the graph take precedence over any
+		// additional edges for the same channel.
+		edges := graph.edges[pivot]
+		if extraEdges, ok := r.additionalEdges[pivot]; ok {
+			merged := make(map[uint64]*cachedEdge,
+				len(edges)+len(extraEdges))
+			for chanID, edge := range extraEdges {
+				merged[chanID] = edge
+			}
+			for chanID, edge := range edges {
+				merged[chanID] = edge
+			}
+			edges = merged
+		}
+
 		// Now that we've found the next potential step to take we'll
 		// examine all the outgoing edge (channels) from this node to
 		// further our graph traversal.
-		pivot := newVertex(bestNode.PubKey)
-		err := bestNode.ForEachChannel(nil, func(edge *channeldb.ChannelEdge) error {
+		for chanID, edge := range edges {
+			policy := edge.policy
+			v := edge.to
+
+			// We'll skip any edge that's unable to carry the
+			// payment, either as its capacity or maximum HTLC is
+			// too small, or the amount is below its minimum HTLC.
+			if policy.Capacity < amt {
+				insufficientCapacity = true
+				continue
+			}
+			if policy.MaxHTLC != 0 && amt > policy.MaxHTLC {
+				insufficientCapacity = true
+				continue
+			}
+			if amt < policy.MinHTLC {
+				continue
+			}
+
+			// If this edge is one of our own outgoing channels,
+			// then we'll consult the bandwidth hints to see if the
+			// channel is able to carry the payment at all. Channels
+			// whose links are inactive will report a bandwidth of
+			// zero, so they'll be skipped here as well. If the
+			// outgoing channel has been restricted, then all of
+			// our other channels are skipped.
+			if pivot == selfVertex {
+				bandwidth, ok := r.bandwidthHints[chanID]
+				if ok && bandwidth < amt {
+					continue
+				}
+
+				if r.outgoingChanID != 0 &&
+					r.outgoingChanID != chanID {
+
+					continue
+				}
+			}
+
+			// If this edge, or the node it leads to has been
+			// explicitly excluded from the search, then we'll
+			// skip it.
+			if _, ok := r.ignoredEdges[chanID]; ok {
+				continue
+			}
+			if _, ok := r.ignoredNodes[v]; ok {
+				continue
+			}
+			if _, ok := visited[v]; ok {
+				continue
+			}
+
+			// If a last hop has been specified, then the target
+			// may only be reached through that node.
+			if v == targetVertex && r.lastHop != nil &&
+				pivot != *r.lastHop {
+
+				continue
+			}
+
+			// If mission control has deemed this pair too
+			// unreliable to forward the payment, then we'll ignore
+			// the edge entirely.
+			pair := nodePair{from: pivot, to: v}
+			if _, ok := r.ignoredPairs[pair]; ok {
+				continue
+			}
+
+			// We don't pay any fees to ourselves for the use of
+			// our own channels, so only the edges of other nodes
+			// carry a fee.
+			var fee btcutil.Amount
+			if pivot != selfVertex {
+				fee = computeFee(amt, policy)
+			}
+
+			// Before relaxing the edge, we'll ensure the path
+			// through it doesn't exceed our fee or time lock
+			// limits. As the fees are computed using the payment
+			// amount rather than the amount forwarded at each hop,
+			// this is only an estimate, and the final route is
+			// checked once again after it has been constructed.
+			tempFee := fees[pivot] + fee
+			tempTimeLock := timeLocks[pivot] + uint32(policy.Expiry)
+			if r.feeLimit != 0 && tempFee > r.feeLimit {
+				continue
+			}
+			if r.cltvLimit != 0 && tempTimeLock > r.cltvLimit {
+				continue
+			}
+
 			// Compute the tentative distance to this new
 			// channel/edge which is the distance to our current
 			// pivot node plus the weight of this edge.
-			tempDist := distance[pivot].dist + edgeWeight(edge)
+			probability, ok := r.pairProbabilities[pair]
+			if !ok {
+				probability = 1
+			}
+			tempDist := best.dist + edgeWeight(amt, fee, policy,
+				probability, r.attemptCost)
 
 			// If this new tentative distance is better than the
 			// current best known distance to this node, then we
 			// record the new better distance, and also populate
 			// our "next hop" map with this edge.
-			// TODO(roasbeef): add capacity to relaxation criteria?
-			//  * also add min payment?
-			v := newVertex(edge.Node.PubKey)
-			if tempDist < distance[v].dist {
-				// TODO(roasbeef): unconditionally add for all
-				// paths
-				distance[v] = nodeWithDist{
-					dist: tempDist,
-					node: edge.Node,
-				}
+			if dist, ok := distance[v]; !ok || tempDist < dist {
+				distance[v] = tempDist
+				fees[v] = tempFee
+				timeLocks[v] = tempTimeLock
 				prev[v] = edgeWithPrev{
 					edge:     edge,
-					prevNode: bestNode.PubKey,
+					prevNode: pivot,
 				}
+
+				heap.Push(&nodeHeap, nodeWithDist{
+					dist: tempDist,
+					node: v,
+				})
 			}
-			return nil
-		})
-		if err != nil {
-			return nil, err
 		}
 	}
 
 	// If the target node isn't found in the prev hop map, then a path
-	// doesn't exist, so we terminate in an error.
-	if _, ok := prev[newVertex(target)]; !ok {
+	// doesn't exist, so we terminate in an error. If we skipped any edges
+	// as they were too small to carry the payment, then we report the
+	// lack of capacity instead.
+	if _, ok := prev[targetVertex]; !ok {
+		if insufficientCapacity {
+			return nil, ErrInsufficientCapacity
+		}
 		return nil, ErrNoPathFound
 	}
 
-	// Otherwise, we construct a new route which calculate the relevant
-	// total fees and proper time lock values for each hop.
-	targetVerex := newVertex(target)
-	return newRoute(amt, sourceVertex, targetVerex, prev)
+	// Otherwise, we use the prevHop map to unravel the path. We end up
+	// with a list of edges in the reverse direction, so we prepend each
+	// edge in order to obtain the forward direction. Each edge is copied
+	// along with the node it leads to, so the caller is free to modify
+	// the path without affecting the cache.
+	var pathEdges []*channeldb.ChannelEdge
+	for v := targetVertex; v != sourceVertex; {
+		hop := prev[v]
+
+		edge := *hop.edge.policy
+		edge.Node = graph.fetchNode(hop.edge)
+		pathEdges = append([]*channeldb.ChannelEdge{&edge},
+			pathEdges...)
+
+		// If the path ever exceeds the hop limit, then we'll bail out
+		// now rather than risk an endless walk.
+		if len(pathEdges) > r.hopLimit() {
+			return nil, ErrMaxHopsExceeded
+		}
+
+		v = hop.prevNode
+	}
+
+	return pathEdges, nil
+}
+
+// buildPath constructs the path which travels from the source node of the
+// graph through each of the passed hops in order. Between each pair of
+// consecutive nodes, the channel with the largest capacity able to carry amt
+// is selected. As with findPath, the returned path consists of copies of the
+// edges traversed in the forward direction.
+func buildPath(graph *graphCache, hops []*btcec.PublicKey,
+	amt btcutil.Amount) ([]*channeldb.ChannelEdge, error) {
+
+	if len(hops) > HopLimit {
+		return nil, ErrMaxHopsExceeded
+	}
+
+	graph.RLock()
+	defer graph.RUnlock()
+
+	pathEdges := make([]*channeldb.ChannelEdge, 0, len(hops))
+	from := newVertex(graph.source.PubKey)
+	for _, hop := range hops {
+		to := newVertex(hop)
+
+		var best *cachedEdge
+		for _, edge := range graph.edges[from] {
+			if edge.to != to || edge.policy.Capacity < amt {
+				continue
+			}
+			if best == nil ||
+				edge.policy.Capacity > best.policy.Capacity {
+
+				best = edge
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("no channel from %x to %x able "+
+				"to carry %v", from[:], to[:], amt)
+		}
+
+		edge := *best.policy
+		edge.Node = graph.fetchNode(best)
+		pathEdges = append(pathEdges, &edge)
+
+		from = to
+	}
+
+	return pathEdges, nil
+}
+
+// findPaths implements a k-shortest paths algorithm to find all the reachable
+// paths between the passed source and target. The algorithm will continue to
+// traverse the graph until all possible candidate paths have been depleted,
+// or numPaths paths have been found. This function implements a modified
+// version of Yen's algorithm, where each newly found path is loop-free.
+func findPaths(graph *graphCache, source *channeldb.LightningNode,
+	target *btcec.PublicKey, amt btcutil.Amount, numPaths uint32,
+	r *restrictParams) ([][]*channeldb.ChannelEdge, error) {
+
+	if r == nil {
+		r = &restrictParams{}
+	}
+
+	// TODO(roasbeef): modify to not exhaust all paths, use sampling
+	shortestPaths := make([][]*channeldb.ChannelEdge, 0, numPaths)
+
+	// First we'll find a single shortest path from the source (our
+	// selfNode) to the target destination that's capable of carrying amt
+	// satoshis along the path before fees are calculated.
+	startingPath, err := findPath(graph, source, target, amt, r)
+	if err != nil {
+		log.Errorf("Unable to find path: %v", err)
+		return nil, err
+	}
+	shortestPaths = append(shortestPaths, startingPath)
+
+	// While we still have candidate paths to explore we'll keep exploring
+	// the sub-graphs created to find the next k-th shortest path.
+	var candidatePaths [][]*channeldb.ChannelEdge
+	for k := uint32(1); k < numPaths; k++ {
+		prevShortest := shortestPaths[k-1]
+
+		// We'll examine each edge in the previous iteration's shortest
+		// path in order to find path deviations from each node in the
+		// path.
+		for i := 0; i < len(prevShortest); i++ {
+			// The spur node is the i-th node within the previous
+			// shortest path, and the root path is the path from
+			// the source up to the spur node.
+			spurNode := source
+			if i > 0 {
+				spurNode = prevShortest[i-1].Node
+			}
+			rootPath := prevShortest[:i]
+
+			// We'll start with the edges and nodes the caller has
+			// excluded from the search, adding to them below.
+			spurRestrictions := *r
+			spurRestrictions.ignoredEdges = make(map[uint64]struct{})
+			for chanID := range r.ignoredEdges {
+				spurRestrictions.ignoredEdges[chanID] = struct{}{}
+			}
+			spurRestrictions.ignoredNodes = make(map[vertex]struct{})
+			for v := range r.ignoredNodes {
+				spurRestrictions.ignoredNodes[v] = struct{}{}
+			}
+
+			// Before we kickoff our next path finding iteration,
+			// we'll ignore any edges that share the same root path
+			// as one of the shortest paths found so far, forcing
+			// the spur path to deviate from all of them.
+			for _, path := range shortestPaths {
+				if len(path) > i && isSamePath(rootPath, path[:i]) {
+					chanID := path[i].ChannelID
+					spurRestrictions.ignoredEdges[chanID] = struct{}{}
+				}
+			}
+
+			// Next we'll ignore all the nodes within the root path
+			// other than the spur node itself, ensuring the
+			// combined path is loop-free.
+			if i > 0 {
+				v := newVertex(source.PubKey)
+				spurRestrictions.ignoredNodes[v] = struct{}{}
+				for _, edge := range rootPath[:i-1] {
+					v := newVertex(edge.Node.PubKey)
+					spurRestrictions.ignoredNodes[v] = struct{}{}
+				}
+			}
+
+			// With the edges that are part of our root path, and
+			// the nodes within the root path ignored, we'll
+			// attempt to find another shortest path from the spur
+			// node to the destination.
+			spurPath, err := findPath(graph, spurNode, target, amt,
+				&spurRestrictions)
+			switch {
+			case err == ErrNoPathFound || err == ErrMaxHopsExceeded ||
+				err == ErrInsufficientCapacity:
+				continue
+			case err != nil:
+				return nil, err
+			}
+
+			// Create the new combined path by concatenating the
+			// rootPath to the spurPath. If this path is new, then
+			// we'll add it to our set of candidate paths.
+			newPath := make([]*channeldb.ChannelEdge, 0,
+				len(rootPath)+len(spurPath))
+			newPath = append(newPath, rootPath...)
+			newPath = append(newPath, spurPath...)
+
+			if !containsPath(candidatePaths, newPath) &&
+				!containsPath(shortestPaths, newPath) {
+
+				candidatePaths = append(candidatePaths, newPath)
+			}
+		}
+
+		// If we have no candidates left, then we'll exit early as all
+		// possible paths have been found.
+		if len(candidatePaths) == 0 {
+			break
+		}
+
+		// To conclude this latest iteration, we'll take the candidate
+		// path with the smallest weight, and add it to our set of
+		// shortest paths.
+		best := 0
+		bestWeight := pathWeight(source, amt, candidatePaths[0], r)
+		for j := 1; j < len(candidatePaths); j++ {
+			weight := pathWeight(source, amt, candidatePaths[j], r)
+			if weight < bestWeight {
+				best = j
+				bestWeight = weight
+			}
+		}
+		shortestPaths = append(shortestPaths, candidatePaths[best])
+		candidatePaths = append(candidatePaths[:best],
+			candidatePaths[best+1:]...)
+	}
+
+	return shortestPaths, nil
+}
+
+// pathWeight returns the total weight of the passed path originating from the
+// source node, which is the sum of the weights of each of its edges.
+func pathWeight(source *channeldb.LightningNode, amt btcutil.Amount,
+	path []*channeldb.ChannelEdge, r *restrictParams) float64 {
+
+	var weight float64
+	from := newVertex(source.PubKey)
+	for i, edge := range path {
+		to := newVertex(edge.Node.PubKey)
+
+		// As in findPath, we don't pay a fee for our own channel.
+		var fee btcutil.Amount
+		if i != 0 {
+			fee = computeFee(amt, edge)
+		}
+
+		probability, ok := r.pairProbabilities[nodePair{from, to}]
+		if !ok {
+			probability = 1
+		}
+
+		weight += edgeWeight(amt, fee, edge, probability, r.attemptCost)
+		from = to
+	}
+
+	return weight
+}
+
+// isSamePath returns true if path1 and path2 travel through the exact same
+// edges, and false otherwise.
+func isSamePath(path1, path2 []*channeldb.ChannelEdge) bool {
+	if len(path1) != len(path2) {
+		return false
+	}
+
+	for i := 0; i < len(path1); i++ {
+		if path1[i].ChannelID != path2[i].ChannelID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsPath returns true if the passed path is already contained within
+// the set of paths.
+func containsPath(paths [][]*channeldb.ChannelEdge,
+	path []*channeldb.ChannelEdge) bool {
+
+	for _, p := range paths {
+		if isSamePath(p, path) {
+			return true
+		}
+	}
+
+	return false
 }
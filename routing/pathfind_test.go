@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -55,15 +56,16 @@ type testChan struct {
 	Flags        uint16  `json:"flags"`
 	Expiry       uint16  `json:"expiry"`
 	MinHTLC      int64   `json:"min_htlc"`
+	MaxHTLC      int64   `json:"max_htlc"`
 	FeeBaseMsat  int64   `json:"fee_base_msat"`
 	FeeRate      float64 `json:"fee_rate"`
 	Capacity     int64   `json:"capacity"`
 }
 
-// makeTestGraph creates a new instance of a channeldb.ChannelGraph for testing
-// purposes. A callback which cleans up the created temporary directories is
-// also returned and intended to be executed after the test completes.
-func makeTestGraph() (*channeldb.ChannelGraph, func(), error) {
+// makeTestDB creates a new instance of channeldb.DB for testing purposes. A
+// callback which cleans up the created temporary directories is also
+// returned and intended to be executed after the test completes.
+func makeTestDB() (*channeldb.DB, func(), error) {
 	// First, create a temporary directory to be used for the duration of
 	// this test.
 	tempDirName, err := ioutil.TempDir("", "channeldb")
@@ -74,14 +76,32 @@ func makeTestGraph() (*channeldb.ChannelGraph, func(), error) {
 	// Next, create channeldb for the first time.
 	cdb, err := channeldb.Open(tempDirName)
 	if err != nil {
+		os.RemoveAll(tempDirName)
 		return nil, nil, err
 	}
 
+	// As the database only lives for the duration of the test, we skip
+	// syncing each write to disk in order to speed up the creation of
+	// larger test graphs.
+	cdb.NoSync = true
+
 	cleanUp := func() {
 		cdb.Close()
 		os.RemoveAll(tempDirName)
 	}
 
+	return cdb, cleanUp, nil
+}
+
+// makeTestGraph creates a new instance of a channeldb.ChannelGraph for testing
+// purposes. A callback which cleans up the created temporary directories is
+// also returned and intended to be executed after the test completes.
+func makeTestGraph() (*channeldb.ChannelGraph, func(), error) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return cdb.ChannelGraph(), cleanUp, nil
 }
 
@@ -224,6 +244,7 @@ func parseTestGraph(path string) (*channeldb.ChannelGraph, func(), aliasMap, err
 			LastUpdate:                time.Now(),
 			Expiry:                    edge.Expiry,
 			MinHTLC:                   btcutil.Amount(edge.MinHTLC),
+			MaxHTLC:                   btcutil.Amount(edge.MaxHTLC),
 			FeeBaseMSat:               btcutil.Amount(edge.FeeBaseMsat),
 			FeeProportionalMillionths: btcutil.Amount(edge.FeeRate),
 			Capacity:                  btcutil.Amount(edge.Capacity),
@@ -254,6 +275,10 @@ func TestBasicGraphPathFinding(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to create graph: %v", err)
 	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
 
 	// With the test graph loaded, we'll test some basic path finding using
 	// the pre-generated graph. Consult the testdata/basic_graph.json file
@@ -262,7 +287,7 @@ func TestBasicGraphPathFinding(t *testing.T) {
 
 	const paymentAmt = btcutil.Amount(100)
 	target := aliases["sophon"]
-	route, err := findRoute(graph, target, paymentAmt)
+	route, err := findRoute(cache, target, paymentAmt, nil)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
 	}
@@ -298,7 +323,7 @@ func TestBasicGraphPathFinding(t *testing.T) {
 	// exist two possible paths in the graph, but the shorter (1 hop) path
 	// should be selected.
 	target = aliases["luoji"]
-	route, err = findRoute(graph, target, paymentAmt)
+	route, err = findRoute(cache, target, paymentAmt, nil)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
 	}
@@ -334,13 +359,17 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to create graph: %v", err)
 	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
 
 	const paymentAmt = btcutil.Amount(100)
 
 	// We start by confirminig that routing a payment 20 hops away is possible.
 	// Alice should be able to find a valid route to ursula.
 	target := aliases["ursula"]
-	route, err := findRoute(graph, target, paymentAmt)
+	route, err := findRoute(cache, target, paymentAmt, nil)
 	if err != nil {
 		t.Fatalf("path should have been found")
 	}
@@ -348,12 +377,22 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	// Vincent is 21 hops away from Alice, and thus no valid route should be
 	// presented to Alice.
 	target = aliases["vincent"]
-	route, err = findRoute(graph, target, paymentAmt)
+	route, err = findRoute(cache, target, paymentAmt, nil)
 	if err == nil {
 		t.Fatalf("should not have been able to find path, supposed to be "+
 			"greater than 20 hops, found route with %v hops", len(route.Hops))
 	}
 
+	// If the final hop payload spans a second frame, then it takes up the
+	// place of a hop within the onion, so ursula should no longer be
+	// reachable either.
+	target = aliases["ursula"]
+	route, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		extraPayloadHops: 1,
+	})
+	if err != ErrMaxHopsExceeded {
+		t.Fatalf("expected ErrMaxHopsExceeded, instead got %v", err)
+	}
 }
 
 func TestPathNotAvailable(t *testing.T) {
@@ -362,6 +401,10 @@ func TestPathNotAvailable(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to create graph: %v", err)
 	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
 
 	// With the test graph loaded, we'll test that queries for target that
 	// are either unreachable within the graph, or unknown result in an
@@ -376,7 +419,7 @@ func TestPathNotAvailable(t *testing.T) {
 		t.Fatalf("unable to parse pubkey: %v", err)
 	}
 
-	if _, err := findRoute(graph, unknownNode, 100); err != ErrNoPathFound {
+	if _, err := findRoute(cache, unknownNode, 100, nil); err != ErrNoPathFound {
 		t.Fatalf("path shouldn't have been found: %v", err)
 	}
 }
@@ -387,6 +430,10 @@ func TestPathInsufficientCapacity(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to create graph: %v", err)
 	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
 
 	// Next, test that attempting to find a path in which the current
 	// channel graph cannot support due to insufficient capacity triggers
@@ -399,10 +446,358 @@ func TestPathInsufficientCapacity(t *testing.T) {
 	target := aliases["sophon"]
 
 	const payAmt = btcutil.SatoshiPerBitcoin
-	_, err = findRoute(graph, target, payAmt)
+	_, err = findRoute(cache, target, payAmt, nil)
 	if err != ErrInsufficientCapacity {
 		t.Fatalf("graph shouldn't be able to support payment: %v", err)
 	}
+
+	// Similarly, although the channel from son goku to sophon has a
+	// capacity of 500 satoshis, son goku won't accept HTLCs above 400
+	// satoshis, so a payment in between should also be rejected.
+	_, err = findRoute(cache, target, 450, nil)
+	if err != ErrInsufficientCapacity {
+		t.Fatalf("graph shouldn't be able to support payment: %v", err)
+	}
+}
+
+func TestPathBandwidthHints(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	const paymentAmt = btcutil.Amount(100)
+
+	// First, we'll mark the direct channel from roasbeef to luo ji as
+	// inactive by reporting a bandwidth of zero for it. Even though the
+	// direct path is the shortest, path finding should route around the
+	// inactive link and instead go through satoshi.
+	bandwidthHints := map[uint64]btcutil.Amount{
+		689530843: 0,
+	}
+	target := aliases["luoji"]
+	route, err := findRoute(cache, target, paymentAmt,
+		&restrictParams{bandwidthHints: bandwidthHints})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 {
+		t.Fatalf("route is of incorrect length, expected %v got %v", 2,
+			len(route.Hops))
+	}
+	if !route.Hops[0].Channel.Node.PubKey.IsEqual(aliases["satoshi"]) {
+		t.Fatalf("first hop should be satoshi, is instead: %v",
+			route.Hops[0].Channel.Node.Alias)
+	}
+
+	// Next, we'll report that our channel to son goku, while having a
+	// large advertised capacity, only has a spendable balance below the
+	// payment amount. As this is the only path to sophon, no path should
+	// be found.
+	bandwidthHints = map[uint64]btcutil.Amount{
+		12345: paymentAmt - 1,
+	}
+	target = aliases["sophon"]
+	_, err = findRoute(cache, target, paymentAmt,
+		&restrictParams{bandwidthHints: bandwidthHints})
+	if err != ErrNoPathFound {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// Finally, if the channel has enough bandwidth to carry the payment
+	// itself, but not the additional fees required by son goku, then the
+	// route should be rejected due to insufficient capacity.
+	bandwidthHints = map[uint64]btcutil.Amount{
+		12345: paymentAmt,
+	}
+	_, err = findRoute(cache, target, paymentAmt,
+		&restrictParams{bandwidthHints: bandwidthHints})
+	if err != ErrInsufficientCapacity {
+		t.Fatalf("route should have been rejected: %v", err)
+	}
+}
+
+func TestPathRouteRestrictions(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	const (
+		paymentAmt    = btcutil.Amount(100)
+		directChanID  = 689530843
+		satoshiChanID = 2340213491
+	)
+	target := aliases["luoji"]
+
+	// assertViaSatoshi asserts that the route reaches luo ji through
+	// satoshi rather than over the direct channel.
+	assertViaSatoshi := func(route *Route) {
+		if len(route.Hops) != 2 {
+			t.Fatalf("route is of incorrect length, expected %v got %v",
+				2, len(route.Hops))
+		}
+		if route.Hops[0].Channel.ChannelID != satoshiChanID {
+			t.Fatalf("route should pass through satoshi, first hop "+
+				"is instead chan_id=%v",
+				route.Hops[0].Channel.ChannelID)
+		}
+	}
+
+	// Without any restrictions, the direct channel to luo ji should be
+	// selected.
+	route, err := findRoute(cache, target, paymentAmt, nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 1 ||
+		route.Hops[0].Channel.ChannelID != directChanID {
+
+		t.Fatalf("direct channel should have been selected")
+	}
+
+	// If we restrict the outgoing channel to our channel with satoshi,
+	// then the route should be forced through satoshi.
+	route, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		outgoingChanID: satoshiChanID,
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	assertViaSatoshi(route)
+
+	// The same should be true if satoshi is required to be the last hop
+	// before luo ji.
+	satoshi := newVertex(aliases["satoshi"])
+	route, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		lastHop: &satoshi,
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	assertViaSatoshi(route)
+
+	// Ignoring the direct channel should also result in the route through
+	// satoshi.
+	route, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		ignoredEdges: map[uint64]struct{}{
+			directChanID: {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	assertViaSatoshi(route)
+
+	// Satoshi charges a fee of 10 satoshis to forward the payment, so if
+	// we limit the fee below that, then no route should be found.
+	_, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		outgoingChanID: satoshiChanID,
+		feeLimit:       5,
+	})
+	if err != ErrNoPathFound {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// Similarly, the path through satoshi has a total time lock of two
+	// blocks, so a limit of a single block should rule it out.
+	_, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		lastHop:   &satoshi,
+		cltvLimit: 1,
+	})
+	if err != ErrNoPathFound {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// The final CLTV delta required by the target is included within the
+	// total time lock of the route, and so counts towards the limit.
+	route, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		lastHop:        &satoshi,
+		finalCLTVDelta: 9,
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if route.TotalTimeLock != 11 {
+		t.Fatalf("expected total time lock of 11, instead have %v",
+			route.TotalTimeLock)
+	}
+	_, err = findRoute(cache, target, paymentAmt, &restrictParams{
+		lastHop:        &satoshi,
+		cltvLimit:      10,
+		finalCLTVDelta: 9,
+	})
+	if err != ErrNoPathFound {
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+}
+
+func TestPathRouteHints(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	// We'll create two new nodes which aren't known to the graph. The
+	// first sits behind a private channel with satoshi, and the second
+	// sits behind a private channel with the first.
+	privKey1, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	privKey2, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	privateNode1 := privKey1.PubKey()
+	privateNode2 := privKey2.PubKey()
+
+	const paymentAmt = btcutil.Amount(100)
+
+	// Without any route hints, the first node should be unreachable.
+	if _, err := findRoute(cache, privateNode1, paymentAmt,
+		nil); err != ErrNoPathFound {
+
+		t.Fatalf("path shouldn't have been found: %v", err)
+	}
+
+	// Once we provide a hint for the private channel between satoshi and
+	// the node, we should be able to find a route through satoshi.
+	routeHints := [][]HopHint{{
+		{
+			NodeID:          aliases["satoshi"],
+			ChannelID:       1111,
+			FeeBaseMSat:     5,
+			CLTVExpiryDelta: 10,
+		},
+	}}
+	route, err := findRoute(cache, privateNode1, paymentAmt,
+		&restrictParams{
+			additionalEdges: hopHintEdges(privateNode1, routeHints),
+		})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 {
+		t.Fatalf("route is of incorrect length, expected %v got %v", 2,
+			len(route.Hops))
+	}
+	lastHop := route.Hops[1]
+	if lastHop.Channel.ChannelID != 1111 ||
+		!lastHop.Channel.Node.PubKey.IsEqual(privateNode1) {
+
+		t.Fatalf("last hop should be the hinted channel, is instead "+
+			"chan_id=%v", lastHop.Channel.ChannelID)
+	}
+
+	// Satoshi should charge the fee specified within the hint.
+	if route.TotalFees != 5 {
+		t.Fatalf("expected total fees of %v, got %v", 5,
+			route.TotalFees)
+	}
+
+	// A hint consisting of a chain of hops should allow us to reach the
+	// second node through the first.
+	routeHints[0] = append(routeHints[0], HopHint{
+		NodeID:          privateNode1,
+		ChannelID:       2222,
+		CLTVExpiryDelta: 10,
+	})
+	route, err = findRoute(cache, privateNode2, paymentAmt,
+		&restrictParams{
+			additionalEdges: hopHintEdges(privateNode2, routeHints),
+		})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 3 {
+		t.Fatalf("route is of incorrect length, expected %v got %v", 3,
+			len(route.Hops))
+	}
+	if route.Hops[2].Channel.ChannelID != 2222 {
+		t.Fatalf("last hop should be the hinted channel, is instead "+
+			"chan_id=%v", route.Hops[2].Channel.ChannelID)
+	}
+}
+
+func TestKShortestPathFinding(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+	cache, err := newGraphCache(graph)
+	if err != nil {
+		t.Fatalf("unable to create graph cache: %v", err)
+	}
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+
+	// There are only two loop-free paths from roasbeef to luo ji: the
+	// direct channel, and the path through satoshi. So even though we
+	// request three paths, only two should be returned.
+	const paymentAmt = btcutil.Amount(100)
+	target := aliases["luoji"]
+	paths, err := findPaths(cache, sourceNode, target, paymentAmt, 3, nil)
+	if err != nil {
+		t.Fatalf("unable to find paths: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected %v paths, instead have %v", 2, len(paths))
+	}
+
+	// The paths should be returned in order of increasing weight, so the
+	// direct path should be first, followed by the path through satoshi.
+	if len(paths[0]) != 1 || paths[0][0].ChannelID != 689530843 {
+		t.Fatalf("first path should be the direct channel to luo ji: %v",
+			spew.Sdump(paths[0]))
+	}
+	if len(paths[1]) != 2 ||
+		!paths[1][0].Node.PubKey.IsEqual(aliases["satoshi"]) ||
+		!paths[1][1].Node.PubKey.IsEqual(aliases["luoji"]) {
+
+		t.Fatalf("second path should pass through satoshi: %v",
+			spew.Sdump(paths[1]))
+	}
+
+	// Finally, the router should construct a route for each path, with
+	// the direct route first as it doesn't require any fees.
+	router, err := New(Config{Graph: graph})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	routes, err := router.FindRoutes(target, paymentAmt, 3, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find routes: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected %v routes, instead have %v", 2, len(routes))
+	}
+	if routes[0].TotalFees != 0 || len(routes[0].Hops) != 1 {
+		t.Fatalf("direct route should be first: %v", spew.Sdump(routes))
+	}
+	if routes[1].TotalFees <= routes[0].TotalFees {
+		t.Fatalf("routes should be sorted by total fee: %v",
+			spew.Sdump(routes))
+	}
 }
 
 func TestPathInsufficientCapacityWithFee(t *testing.T) {
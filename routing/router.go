@@ -2,8 +2,8 @@ package routing
 
 import (
 	"bytes"
-	"encoding/hex"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,6 +20,17 @@ import (
 	"github.com/lightningnetwork/lightning-onion"
 )
 
+const (
+	// DefaultChannelPruneExpiry is the default duration after which a
+	// channel for which neither node has sent an update is considered a
+	// zombie, and pruned from the channel graph.
+	DefaultChannelPruneExpiry = time.Hour * 24 * 14
+
+	// DefaultGraphPruneInterval is the default interval at which the
+	// router checks the channel graph for zombie channels.
+	DefaultGraphPruneInterval = time.Hour
+)
+
 // FeeSchema is the set fee configuration for a Lighting Node on the network.
 // Using the coefficients described within he schema, the required fee to
 // forward outgoing payments can be derived.
@@ -48,9 +59,9 @@ type Config struct {
 	Graph *channeldb.ChannelGraph
 
 	// Chain is the router's source to the most up-to-date blockchain data.
-	// All incoming advertised channels will be checked against the chain
-	// to ensure that the channels advertised are still open.
-	// TODO(roasbeef): remove after discovery service is in
+	// The router uses the chain in order to prune the channel graph of
+	// any channels which have been closed by spending their funding
+	// output.
 	Chain lnwallet.BlockChainIO
 
 	// Notifier is an instance of the ChainNotifier that the router uses to
@@ -66,23 +77,87 @@ type Config struct {
 	// TODO(roasbeef): should either be in discovery or switch
 	FeeSchema *FeeSchema
 
-	// Broadcast is a function that is used to broadcast a particular set
-	// of messages to all peers that the daemon is connected to. If
-	// supplied, the exclude parameter indicates that the target peer should
-	// be excluded from the broadcast.
-	Broadcast func(exclude *btcec.PublicKey, msg ...lnwire.Message) error
-
-	// SendMessages is a function which allows the ChannelRouter to send a
-	// set of messages to a particular peer identified by the target public
-	// key.
-	SendMessages func(target *btcec.PublicKey, msg ...lnwire.Message) error
-
 	// SendToSwitch is a function that directs a link-layer switch to
 	// forward a fully encoded payment to the first hop in the route
 	// denoted by its public key. A non-nil error is to be returned if the
-	// payment was unsuccessful.
+	// payment was unsuccessful. Otherwise, the preimage revealed by the
+	// destination is returned.
 	SendToSwitch func(firstHop *btcec.PublicKey,
-		htlcAdd *lnwire.HTLCAddRequest) error
+		htlcAdd *lnwire.HTLCAddRequest) ([32]byte, error)
+
+	// QueryBandwidth is a method that allows the router to query the
+	// lower link layer to determine the up to date available bandwidth
+	// of one of our directly connected channels. Unlike the advertised
+	// capacity of a channel, this value reflects our current spendable
+	// balance within the channel. If the link backing the channel isn't
+	// currently active, then a bandwidth of zero should be returned.
+	QueryBandwidth func(edge *channeldb.ChannelEdge) btcutil.Amount
+
+	// PenaltyHalfLife is the half-life of a failure recorded by mission
+	// control. If zero, then DefaultPenaltyHalfLife is used.
+	PenaltyHalfLife time.Duration
+
+	// MissionControlDB is an optional database mission control will
+	// persist its payment history to. If nil, then the history is only
+	// kept in memory.
+	MissionControlDB *channeldb.DB
+
+	// PaymentControlDB is an optional database the router records the
+	// state of each outgoing payment within. This prevents a payment hash
+	// from being paid twice, allows payments in flight to be resumed after
+	// a restart, and allows payments to be tracked. If nil, then payments
+	// aren't recorded.
+	PaymentControlDB *channeldb.DB
+
+	// AttemptCost is the virtual cost of an additional payment attempt.
+	// When non-zero, path finding penalizes node pairs that mission
+	// control estimates are likely to fail, trading off higher fees for
+	// a greater chance of success. A value of zero disables the penalty.
+	AttemptCost btcutil.Amount
+
+	// ChannelPruneExpiry is the duration after which a channel for which
+	// neither node has sent an update is considered a zombie, and pruned
+	// from the channel graph. If zero, then DefaultChannelPruneExpiry is
+	// used.
+	ChannelPruneExpiry time.Duration
+
+	// GraphPruneInterval is the interval at which the router checks the
+	// channel graph for zombie channels. If zero, then
+	// DefaultGraphPruneInterval is used.
+	GraphPruneInterval time.Duration
+}
+
+// RouteRestrictions houses the set of optional restrictions that a route
+// returned by the ChannelRouter must satisfy. The zero value imposes no
+// restrictions.
+type RouteRestrictions struct {
+	// FeeLimit is the maximum total fee the route may carry. A value of
+	// zero indicates no limit.
+	FeeLimit btcutil.Amount
+
+	// CltvLimit is the maximum total time lock the route may carry. A
+	// value of zero indicates no limit.
+	CltvLimit uint32
+
+	// OutgoingChannelID, if non-zero, is the channel ID of the only one
+	// of our channels that may be used as the first hop of the route.
+	OutgoingChannelID uint64
+
+	// LastHop, if non-nil, is the node the route must pass through
+	// immediately before reaching the destination.
+	LastHop *btcec.PublicKey
+
+	// IgnoredNodes is a set of nodes the route must not pass through.
+	IgnoredNodes []*btcec.PublicKey
+
+	// IgnoredEdges is a set of channels, identified by their channel ID,
+	// that the route must not pass through.
+	IgnoredEdges []uint64
+
+	// FinalCLTVDelta is the time lock delta the destination requires for
+	// the final hop of the route. It's added to the total time lock of the
+	// route, and so counts towards the CltvLimit.
+	FinalCLTVDelta uint16
 }
 
 // ChannelRouter is the layer 3 router within the Lightning stack. Below the
@@ -106,37 +181,34 @@ type ChannelRouter struct {
 	// when doing any path finding.
 	selfNode *channeldb.LightningNode
 
-	// TODO(roasbeef): make LRU, invalidate upon new block connect
-	shortestPathCache map[[33]byte][]*Route
-	nodeCache         map[[33]byte]*channeldb.LightningNode
-	edgeCache         map[wire.OutPoint]*channeldb.ChannelEdge
+	// graphCache is an in-memory copy of the channel graph which is used
+	// for path finding. It's kept in sync with the channel graph as
+	// network announcements are processed and closed channels are pruned.
+	graphCache *graphCache
 
 	// newBlocks is a channel in which new blocks connected to the end of
 	// the main chain are sent over.
 	newBlocks chan *chainntnfs.BlockEpoch
 
-	// networkMsgs is a channel that carries new network messages from
-	// outside the ChannelRouter to be processed by the networkHandler.
-	networkMsgs chan *routingMsg
-
-	// syncRequests is a channel that carries requests to synchronize newly
-	// connected peers to the state of the channel graph from our PoV.
-	syncRequests chan *syncRequest
-
-	// prematureAnnouncements maps a blockheight to a set of announcements
-	// which are "premature" from our PoV. An announcement is premature if
-	// it claims to be anchored in a block which is beyond the current
-	// mainchain tip as we know it. Premature announcements will be
-	// processed once the chain tip as we know it extends to/past the
-	// premature height.
-	// TODO(roasbeef): limit premature announcements to N
-	prematureAnnouncements map[uint32][]lnwire.Message
-
-	// bestHeight is the height of the block at the tip of the main chain
-	// as we know it.
+	// bestHeight is the height of the latest block known to the router.
+	// It's used to set the absolute expiry of the HTLCs we send. This
+	// MUST be used atomically.
 	bestHeight uint32
 
-	fakeSig *btcec.Signature
+	// missionControl records the outcomes of past payment attempts in
+	// order to steer future path finding away from unreliable node pairs.
+	missionControl *missionControl
+
+	// control records the state of each outgoing payment, and notifies
+	// any clients tracking them.
+	control *controlTower
+
+	// topologyClients maps a client's unique notification ID to a
+	// topologyClient client that contains its notification dispatch
+	// channel.
+	topologyClients      map[uint64]*topologyClient
+	nextTopologyClientID uint64
+	topologyClientMtx    sync.RWMutex
 
 	started uint32
 	stopped uint32
@@ -150,33 +222,38 @@ type ChannelRouter struct {
 // channel graph is a subset of the UTXO set) set, then the router will proceed
 // to fully sync to the latest state of the UTXO set.
 func New(cfg Config) (*ChannelRouter, error) {
-	// TODO(roasbeef): remove this place holder after sigs are properly
-	// stored in the graph.
-	s := "30450221008ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa" +
-		"1bf0314f882d70220299105481d63e0f4bc2a88121167221b6700d72a0e" +
-		"ad154c03be696a292d24ae"
-	fakeSigHex, err := hex.DecodeString(s)
+	selfNode, err := cfg.Graph.SourceNode()
 	if err != nil {
 		return nil, err
 	}
-	fakeSig, err := btcec.ParseSignature(fakeSigHex, btcec.S256())
+
+	if cfg.ChannelPruneExpiry == 0 {
+		cfg.ChannelPruneExpiry = DefaultChannelPruneExpiry
+	}
+	if cfg.GraphPruneInterval == 0 {
+		cfg.GraphPruneInterval = DefaultGraphPruneInterval
+	}
+
+	mc, err := newMissionControl(cfg.PenaltyHalfLife, cfg.MissionControlDB)
 	if err != nil {
 		return nil, err
 	}
 
-	selfNode, err := cfg.Graph.SourceNode()
+	cache, err := newGraphCache(cfg.Graph)
 	if err != nil {
 		return nil, err
 	}
 
+	quit := make(chan struct{})
+
 	return &ChannelRouter{
-		cfg:                    &cfg,
-		selfNode:               selfNode,
-		fakeSig:                fakeSig,
-		networkMsgs:            make(chan *routingMsg),
-		syncRequests:           make(chan *syncRequest),
-		prematureAnnouncements: make(map[uint32][]lnwire.Message),
-		quit: make(chan struct{}),
+		cfg:             &cfg,
+		selfNode:        selfNode,
+		missionControl:  mc,
+		control:         newControlTower(cfg.PaymentControlDB, quit),
+		graphCache:      cache,
+		topologyClients: make(map[uint64]*topologyClient),
+		quit:            quit,
 	}, nil
 }
 
@@ -200,18 +277,19 @@ func (r *ChannelRouter) Start() error {
 	}
 	r.newBlocks = blockEpochs.Epochs
 
-	_, height, err := r.cfg.Chain.GetBestBlock()
-	if err != nil {
-		return err
-	}
-	r.bestHeight = uint32(height)
-
 	// Before we begin normal operation of the router, we first need to
 	// synchronize the channel graph to the latest state of the UTXO set.
 	if err := r.syncGraphWithChain(); err != nil {
 		return err
 	}
 
+	// Any payments which were in flight when we last shut down are
+	// resumed, such that their outcome is recorded once their HTLCs are
+	// resolved.
+	if err := r.control.resume(); err != nil {
+		return err
+	}
+
 	r.wg.Add(1)
 	go r.networkHandler()
 
@@ -245,6 +323,8 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 	if err != nil {
 		return err
 	}
+	atomic.StoreUint32(&r.bestHeight, uint32(bestHeight))
+
 	pruneHash, pruneHeight, err := r.cfg.Graph.PruneTip()
 	if err != nil {
 		switch {
@@ -312,6 +392,7 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 		if err != nil {
 			return err
 		}
+		r.graphCache.pruneChannels(spentOutputs)
 
 		log.Infof("Block %v (height=%v) closed %v channels",
 			nextHash, nextHeight, numClosed)
@@ -325,47 +406,20 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 	return nil
 }
 
-// networkHandler is the primary goroutine for the ChannelRouter. The roles of
-// this goroutine include answering queries related to the state of the
-// network, syncing up newly connected peers, and also periodically
-// broadcasting our latest state to all connected peers.
+// networkHandler is the primary goroutine for the ChannelRouter. The role of
+// this goroutine is to keep the channel graph in sync with the main chain by
+// pruning any channels which have been closed with each new block.
+// Additionally, any zombie channels are periodically pruned from the graph.
 //
 // NOTE: This MUST be run as a goroutine.
 func (r *ChannelRouter) networkHandler() {
 	defer r.wg.Done()
 
-	var announcementBatch []lnwire.Message
-
-	// TODO(roasbeef): parametrize the above
-	trickleTimer := time.NewTicker(time.Millisecond * 300)
-	defer trickleTimer.Stop()
-
-	retransmitTimer := time.NewTicker(time.Minute * 30)
-	defer retransmitTimer.Stop()
+	pruneTicker := time.NewTicker(r.cfg.GraphPruneInterval)
+	defer pruneTicker.Stop()
 
 	for {
 		select {
-		// A new fully validated network message has just arrived. As a
-		// result we'll modify the channel graph accordingly depending
-		// on the exact type of the message.
-		case netMsg := <-r.networkMsgs:
-			// Process the network announcement to determine if
-			// this is either a new announcement from our PoV or an
-			// update to a prior vertex/edge we previously
-			// accepted.
-			accepted := r.processNetworkAnnouncement(netMsg.msg)
-
-			// If the update was accepted, then add it to our next
-			// announcement batch to be broadcast once the trickle
-			// timer ticks gain.
-			if accepted {
-				announcementBatch = append(announcementBatch, netMsg.msg)
-			}
-
-			// TODO(roasbeef): remove all unconnected vertexes
-			// after N blocks pass with no corresponding
-			// announcements.
-
 		// A new block has arrived, so we can prune the channel graph
 		// of any channels which were closed in the block.
 		case newBlock, ok := <-r.newBlocks:
@@ -375,26 +429,8 @@ func (r *ChannelRouter) networkHandler() {
 				return
 			}
 
-			// Once a new block arrives, we update our running
-			// track of the height of the chain tip.
 			blockHeight := uint32(newBlock.Height)
-			r.bestHeight = blockHeight
-
-			// Next we check if we have any premature announcements
-			// for this height, if so, then we process them once
-			// more as normal announcements.
-			prematureAnns := r.prematureAnnouncements[uint32(newBlock.Height)]
-			if len(prematureAnns) != 0 {
-				log.Infof("Re-processing %v premature announcements for "+
-					"height %v", len(prematureAnns), blockHeight)
-			}
-
-			for _, ann := range prematureAnns {
-				if ok := r.processNetworkAnnouncement(ann); ok {
-					announcementBatch = append(announcementBatch, ann)
-				}
-			}
-			delete(r.prematureAnnouncements, blockHeight)
+			atomic.StoreUint32(&r.bestHeight, blockHeight)
 
 			log.Infof("Pruning channel graph using block %v (height=%v)",
 				newBlock.Hash, blockHeight)
@@ -428,96 +464,28 @@ func (r *ChannelRouter) networkHandler() {
 				continue
 			}
 
+			// Once the graph itself has been pruned, we'll also
+			// remove the closed channels from our graph cache.
+			closedChans := r.graphCache.pruneChannels(spentOutputs)
+
 			log.Infof("Block %v (height=%v) closed %v channels",
 				newBlock.Hash, blockHeight, numClosed)
 
-		// The retransmission timer has ticked which indicates that we
-		// should broadcast our personal channel to the network. This
-		// addresses the case of channel advertisements whether being
-		// dropped, or not properly propagated through the network.
-		case <-retransmitTimer.C:
-			var selfChans []lnwire.Message
-
-			selfPub := r.selfNode.PubKey.SerializeCompressed()
-			err := r.selfNode.ForEachChannel(nil, func(c *channeldb.ChannelEdge) error {
-				chanNodePub := c.Node.PubKey.SerializeCompressed()
-
-				// Compare our public key with that of the
-				// channel peer. If our key is "less" than
-				// theirs, then we're the "first" node in the
-				// advertisement, otherwise we're the second.
-				flags := uint16(1)
-				if bytes.Compare(selfPub, chanNodePub) == -1 {
-					flags = 0
-				}
-
-				selfChans = append(selfChans, &lnwire.ChannelUpdateAnnouncement{
-					Signature:                 r.fakeSig,
-					ChannelID:                 lnwire.NewChanIDFromInt(c.ChannelID),
-					Timestamp:                 uint32(c.LastUpdate.Unix()),
-					Flags:                     flags,
-					Expiry:                    c.Expiry,
-					HtlcMinimumMstat:          uint32(c.MinHTLC),
-					FeeBaseMstat:              uint32(c.FeeBaseMSat),
-					FeeProportionalMillionths: uint32(c.FeeProportionalMillionths),
-				})
-				return nil
-			})
-			if err != nil {
-				log.Errorf("unable to retransmit "+
-					"channels: %v", err)
-				continue
-			}
-
-			log.Infof("Retransmitting %v outgoing channels",
-				len(selfChans))
-
-			// With all the wire messages properly crafted, we'll
-			// broadcast our known outgoing channel to all our
-			// immediate peers.
-			if err := r.cfg.Broadcast(nil, selfChans...); err != nil {
-				log.Errorf("unable to re-broadcast "+
-					"channels: %v", err)
-			}
-
-		// The trickle timer has ticked, which indicates we should
-		// flush to the network the pending batch of new announcements
-		// we've received since the last trickle tick.
-		case <-trickleTimer.C:
-			// If the current announcement batch is nil, then we
-			// have no further work here.
-			if len(announcementBatch) == 0 {
-				continue
-			}
-
-			log.Infof("Broadcasting batch of %v new announcements",
-				len(announcementBatch))
-
-			// If we have new things to announce then broadcast
-			// then to all our immediately connected peers.
-			err := r.cfg.Broadcast(nil, announcementBatch...)
-			if err != nil {
-				log.Errorf("unable to send batch announcement: %v", err)
-				continue
+			// Finally, we'll notify any topology clients of the
+			// channels which were closed within the block.
+			for _, closedChan := range closedChans {
+				closedChan.ClosedHeight = blockHeight
 			}
+			r.notifyTopologyChange(&TopologyChange{
+				ClosedChannels: closedChans,
+			})
 
-			// If we we're able to broadcast the current batch
-			// successfully, then we reset the batch for a new
-			// round of announcements.
-			announcementBatch = nil
-
-		// We've just received a new request to synchronize a peer with
-		// our latest graph state. This indicates that a peer has just
-		// connected for the first time, so for now we dump our entire
-		// graph and allow them to sift through the (subjectively) new
-		// information on their own.
-		case syncReq := <-r.syncRequests:
-			nodePub := syncReq.node.SerializeCompressed()
-			log.Infof("Synchronizing channel graph with %x", nodePub)
-
-			if err := r.syncChannelGraph(syncReq); err != nil {
-				log.Errorf("unable to sync graph state with %x: %v",
-					nodePub, err)
+		// The prune ticker has ticked, so we'll check the graph for
+		// any channels which have gone stale.
+		case <-pruneTicker.C:
+			if err := r.pruneZombieChans(); err != nil {
+				log.Errorf("unable to prune zombie channels: "+
+					"%v", err)
 			}
 
 		// The router has been signalled to exit, to we exit our main
@@ -528,499 +496,480 @@ func (r *ChannelRouter) networkHandler() {
 	}
 }
 
-// processNetworkAnnouncement processes a new network relate authenticated
-// channel or node announcement. If the update didn't affect the internal state
-// of the draft due to either being out of date, invalid, or redundant, then
-// false is returned. Otherwise, true is returned indicating that the caller
-// may want to batch this request to be broadcast to immediate peers during the
-// next announcement epoch.
-func (r *ChannelRouter) processNetworkAnnouncement(msg lnwire.Message) bool {
-	isPremature := func(chanID *lnwire.ChannelID) bool {
-		return chanID.BlockHeight > r.bestHeight
-	}
-
-	switch msg := msg.(type) {
-
-	// A new node announcement has arrived which either presents a new
-	// node, or a node updating previously advertised information.
-	case *lnwire.NodeAnnouncement:
-		// Before proceeding ensure that we aren't already away of this
-		// node, and if we are then this is a newer update that we
-		// known of.
-		lastUpdate, exists, err := r.cfg.Graph.HasLightningNode(msg.NodeID)
-		if err != nil {
-			log.Errorf("Unable to query for the existence of node: %v",
-				err)
-			return false
-		}
+// pruneZombieChans prunes from the channel graph all channels for which
+// neither node has sent an update within the channel prune expiry. Such
+// channels are likely to have been abandoned by their nodes, and would only
+// lead path finding astray.
+func (r *ChannelRouter) pruneZombieChans() error {
+	staleTime := time.Now().Add(-r.cfg.ChannelPruneExpiry)
 
-		// If we've reached this pint then we're aware of th vertex
-		// being advertised. So we now check if the new message has a
-		// new time stamp, if not then we won't accept the new data as
-		// it would override newer data.
-		msgTimestamp := time.Unix(int64(msg.Timestamp), 0)
-		if exists && lastUpdate.After(msgTimestamp) ||
-			lastUpdate.Equal(msgTimestamp) {
-
-			log.Debugf("Ignoring outdated announcement for %x",
-				msg.NodeID.SerializeCompressed())
-			return false
-		}
+	zombies, err := r.cfg.Graph.PruneZombieChannels(staleTime)
+	if err != nil {
+		return err
+	}
 
-		node := &channeldb.LightningNode{
-			LastUpdate: msgTimestamp,
-			Address:    msg.Address,
-			PubKey:     msg.NodeID,
-			Alias:      msg.Alias.String(),
-		}
+	// As with closed channels, we'll also remove the zombies from our
+	// graph cache so they're no longer considered during path finding.
+	closedChans := r.graphCache.pruneChannels(zombies)
 
-		if err = r.cfg.Graph.AddLightningNode(node); err != nil {
-			log.Errorf("unable to add node %v: %v", msg.NodeID, err)
-			return false
-		}
+	if len(zombies) != 0 {
+		log.Infof("Pruned %v zombie channels not updated since %v",
+			len(zombies), staleTime)
+	}
 
-		log.Infof("Updated vertex data for node=%x",
-			msg.NodeID.SerializeCompressed())
-
-	// A new channel announcement has arrived, this indicates the
-	// *creation* of a new channel within the graph. This only advertises
-	// the existence of a channel and not yet the routing policies in
-	// either direction of the channel.
-	case *lnwire.ChannelAnnouncement:
-		// Prior to processing the announcement we first check if we
-		// already know of this channel, if so, then we can exit early.
-		channelID := msg.ChannelID.ToUint64()
-		_, _, exists, err := r.cfg.Graph.HasChannelEdge(channelID)
-		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
-			log.Errorf("unable to check for edge existence: %v", err)
-			return false
-		} else if exists {
-			log.Debugf("Ignoring announcement for known chan_id=%v",
-				channelID)
-			return false
-		}
+	r.notifyTopologyChange(&TopologyChange{
+		ClosedChannels: closedChans,
+	})
 
-		// If the advertised inclusionary block is beyond our knowledge
-		// of the chain tip, then we'll put the announcement in limbo
-		// to be fully verified once we advance forward in the chain.
-		if isPremature(&msg.ChannelID) {
-			blockHeight := msg.ChannelID.BlockHeight
-			log.Infof("Announcement for chan_id=(%v), is "+
-				"premature: advertises height %v, only height "+
-				"%v is known", channelID,
-				msg.ChannelID.BlockHeight, r.bestHeight)
-
-			r.prematureAnnouncements[blockHeight] = append(
-				r.prematureAnnouncements[blockHeight],
-				msg,
-			)
-			return false
-		}
+	return nil
+}
 
-		// Before we can add the channel to the channel graph, we need
-		// to obtain the full funding outpoint that's encoded within
-		// the channel ID.
-		fundingPoint, err := r.fetchChanPoint(&msg.ChannelID)
-		if err != nil {
-			log.Errorf("unable to fetch chan point for chan_id=%v: %v",
-				channelID, err)
-			return false
-		}
+// ChannelGraphSource represents the source of information about the topology
+// of the lightning network. It's responsible for the addition of nodes and
+// edges to the channel graph, and for applying updates to the routing
+// policies of existing edges. All information passed to a ChannelGraphSource
+// is assumed to have been fully authenticated by the caller.
+type ChannelGraphSource interface {
+	// AddNode is used to add information about a node to the channel
+	// graph. If the node is already known, then its information is
+	// updated.
+	AddNode(node *channeldb.LightningNode) error
+
+	// AddEdge is used to add a new (undirected, blank) edge between the
+	// two target nodes to the channel graph. The funding outpoint and
+	// channel ID uniquely identify the edge.
+	AddEdge(node1, node2 *btcec.PublicKey, chanPoint *wire.OutPoint,
+		chanID uint64) error
+
+	// UpdateEdge is used to update the routing policy of one of the
+	// directed edges of a channel that's already known to the channel
+	// graph.
+	UpdateEdge(policy *channeldb.ChannelEdge) error
+}
 
-		// Now that we have the funding outpoint of the channel, ensure
-		// that it hasn't yet been spent. If so, then this channel has
-		// been closed so we'll ignore it.
-		if _, err := r.cfg.Chain.GetUtxo(&fundingPoint.Hash,
-			fundingPoint.Index); err != nil {
-			log.Errorf("unable to fetch utxo for chan_id=%v: %v",
-				channelID, err)
-			return false
-		}
+// A compile time check to ensure ChannelRouter implements the
+// ChannelGraphSource interface.
+var _ ChannelGraphSource = (*ChannelRouter)(nil)
 
-		// TODO(roasbeef): also add capacity here two instead of on the
-		// directed edges.
-		err = r.cfg.Graph.AddChannelEdge(msg.FirstNodeID,
-			msg.SecondNodeID, fundingPoint, channelID)
-		if err != nil {
-			log.Errorf("unable to add channel: %v", err)
-			return false
-		}
+// AddNode is used to add information about a node to the router database. If
+// the node with this pubkey is already present in the database, then its
+// information is updated.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) AddNode(node *channeldb.LightningNode) error {
+	if err := r.cfg.Graph.AddLightningNode(node); err != nil {
+		return err
+	}
+	r.graphCache.addNode(node)
+
+	log.Infof("Updated vertex data for node=%x",
+		node.PubKey.SerializeCompressed())
+
+	r.notifyTopologyChange(&TopologyChange{
+		NodeUpdates: []*NetworkNodeUpdate{
+			{
+				Address:     node.Address,
+				IdentityKey: node.PubKey,
+				Alias:       node.Alias,
+				Color:       node.Color,
+			},
+		},
+	})
 
-		log.Infof("New channel discovered! Link "+
-			"connects %x and %x with ChannelPoint(%v), chan_id=%v",
-			msg.FirstNodeID.SerializeCompressed(),
-			msg.SecondNodeID.SerializeCompressed(),
-			fundingPoint, channelID)
-
-	// A new authenticated channel update has has arrived, this indicates
-	// that the directional information for an already known channel has
-	// been updated. All updates are signed and validated before reaching
-	// us, so we trust the data to be legitimate.
-	case *lnwire.ChannelUpdateAnnouncement:
-		chanID := msg.ChannelID.ToUint64()
-		edge1Timestamp, edge2Timestamp, _, err := r.cfg.Graph.HasChannelEdge(chanID)
-		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
-			log.Errorf("unable to check for edge existence: %v", err)
-			return false
-		}
+	return nil
+}
 
-		// If the advertised inclusionary block is beyond our knowledge
-		// of the chain tip, then we'll put the announcement in limbo
-		// to be fully verified once we advance forward in the chain.
-		if isPremature(&msg.ChannelID) {
-			blockHeight := msg.ChannelID.BlockHeight
-			log.Infof("Update announcement for chan_id=(%v), is "+
-				"premature: advertises height %v, only height "+
-				"%v is known", chanID, blockHeight,
-				r.bestHeight)
-
-			r.prematureAnnouncements[blockHeight] = append(
-				r.prematureAnnouncements[blockHeight],
-				msg,
-			)
-			return false
-		}
+// AddEdge is used to add a new edge between the two target nodes to the
+// router database. The edge doesn't yet carry any routing policy, so it isn't
+// used during path finding until at least one of its directed edges has been
+// updated.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) AddEdge(node1, node2 *btcec.PublicKey,
+	chanPoint *wire.OutPoint, chanID uint64) error {
 
-		// As edges are directional edge node has a unique policy for
-		// the direction of the edge they control. Therefore we first
-		// check if we already have the most up to date information for
-		// that edge. If so, then we can exit early.
-		updateTimestamp := time.Unix(int64(msg.Timestamp), 0)
-		switch msg.Flags {
-
-		// A flag set of 0 indicates this is an announcement for
-		// the "first" node in the channel.
-		case 0:
-			if edge1Timestamp.After(updateTimestamp) ||
-				edge1Timestamp.Equal(updateTimestamp) {
-
-				log.Debugf("Ignoring announcement (flags=%v) "+
-					"for known chan_id=%v", msg.Flags,
-					chanID)
-				return false
-			}
+	// TODO(roasbeef): also add capacity here two instead of on the
+	// directed edges.
+	err := r.cfg.Graph.AddChannelEdge(node1, node2, chanPoint, chanID)
+	if err != nil {
+		return err
+	}
+	r.graphCache.addChannelEdge(node1, node2, chanPoint, chanID)
 
-		// Similarly, a flag set of 1 indicates this is an
-		// announcement for the "second" node in the channel.
-		case 1:
-			if edge2Timestamp.After(updateTimestamp) ||
-				edge2Timestamp.Equal(updateTimestamp) {
+	log.Infof("New channel discovered! Link connects %x and %x with "+
+		"ChannelPoint(%v), chan_id=%v", node1.SerializeCompressed(),
+		node2.SerializeCompressed(), chanPoint, chanID)
 
-				log.Debugf("Ignoring announcement (flags=%v) "+
-					"for known chan_id=%v", msg.Flags,
-					chanID)
-				return false
-			}
-		}
+	return nil
+}
 
-		// Before we can update the channel information, we need to get
-		// the UTXO itself so we can store the proper capacity.
-		chanPoint, err := r.fetchChanPoint(&msg.ChannelID)
-		if err != nil {
-			log.Errorf("unable to fetch chan point for chan_id=%v: %v", chanID, err)
-			return false
-		}
-		utxo, err := r.cfg.Chain.GetUtxo(&chanPoint.Hash,
-			chanPoint.Index)
-		if err != nil {
-			log.Errorf("unable to fetch utxo for chan_id=%v: %v",
-				chanID, err)
-			return false
-		}
+// UpdateEdge is used to update the routing policy of one of the directed
+// edges of an existing channel. The update is written to disk, and also
+// applied to the graph cache so it's immediately visible to path finding.
+//
+// NOTE: This method is part of the ChannelGraphSource interface.
+func (r *ChannelRouter) UpdateEdge(policy *channeldb.ChannelEdge) error {
+	if err := r.cfg.Graph.UpdateEdgeInfo(policy); err != nil {
+		return err
+	}
 
-		// TODO(roasbeef): should be msat here
-		chanUpdate := &channeldb.ChannelEdge{
-			ChannelID:                 chanID,
-			ChannelPoint:              *chanPoint,
-			LastUpdate:                updateTimestamp,
-			Flags:                     msg.Flags,
-			Expiry:                    msg.Expiry,
-			MinHTLC:                   btcutil.Amount(msg.HtlcMinimumMstat),
-			FeeBaseMSat:               btcutil.Amount(msg.FeeBaseMstat),
-			FeeProportionalMillionths: btcutil.Amount(msg.FeeProportionalMillionths),
-			// TODO(roasbeef): this is a hack, needs to be removed
-			// after commitment fees are dynamic.
-			Capacity: btcutil.Amount(utxo.Value) - btcutil.Amount(5000),
-		}
+	prevPolicy, from, to, err := r.graphCache.updateEdge(policy)
+	if err != nil {
+		return fmt.Errorf("unable to update graph cache: %v", err)
+	}
 
-		err = r.cfg.Graph.UpdateEdgeInfo(chanUpdate)
-		if err != nil {
-			log.Errorf("unable to add channel: %v", err)
-			return false
-		}
+	log.Infof("New channel update applied: %v", spew.Sdump(policy))
 
-		log.Infof("New channel update applied: %v",
-			spew.Sdump(chanUpdate))
+	// With the update applied, we'll notify any topology clients of the
+	// new routing policy, along with the policy it replaces.
+	advertisingNode, err := btcec.ParsePubKey(from[:], btcec.S256())
+	if err != nil {
+		return err
+	}
+	connectingNode, err := btcec.ParsePubKey(to[:], btcec.S256())
+	if err != nil {
+		return err
 	}
+	r.notifyTopologyChange(&TopologyChange{
+		ChannelEdgeUpdates: []*ChannelEdgeUpdate{
+			{
+				ChanID:          policy.ChannelID,
+				ChanPoint:       policy.ChannelPoint,
+				Capacity:        policy.Capacity,
+				Policy:          policy,
+				PrevPolicy:      prevPolicy,
+				AdvertisingNode: advertisingNode,
+				ConnectingNode:  connectingNode,
+			},
+		},
+	})
 
-	return true
+	return nil
 }
 
-// syncRequest represents a request from an outside subsystem to the wallet to
-// sync a new node to the latest graph state.
-type syncRequest struct {
-	node *btcec.PublicKey
+// FindRoute attempts to query the ChannelRouter for the "best" path to a
+// particular target destination which is able to send `amt` after factoring in
+// channel capacities and cumulative fees along the route. If non-nil, the
+// returned route will satisfy the passed restrictions. Any route hints passed
+// are used as additional edges in order to reach a target that isn't fully
+// known to the channel graph.
+func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount,
+	restrictions *RouteRestrictions, routeHints [][]HopHint) (*Route, error) {
+
+	return r.findCappedRoute(target, amt, restrictions, routeHints, nil, 1)
 }
 
-// SynchronizeNode sends a message to the ChannelRouter indicating it should
-// synchronize routing state with the target node. This method is to be
-// utilized when a node connections for the first time to provide it with the
-// latest channel graph state.
-func (r *ChannelRouter) SynchronizeNode(pub *btcec.PublicKey) {
-	select {
-	case r.syncRequests <- &syncRequest{
-		node: pub,
-	}:
-	case <-r.quit:
-		return
-	}
-}
+// findCappedRoute is identical to FindRoute, but additionally caps the
+// bandwidth of any of our channels present within bandwidthCaps to the
+// corresponding amount. The route leaves room within the onion for the passed
+// number of final hop payload frames, each frame beyond the first taking up
+// the place of a hop.
+func (r *ChannelRouter) findCappedRoute(target *btcec.PublicKey,
+	amt btcutil.Amount, restrictions *RouteRestrictions,
+	routeHints [][]HopHint, bandwidthCaps map[uint64]btcutil.Amount,
+	numPayloadFrames int) (*Route, error) {
 
-// syncChannelGraph attempts to synchronize the target node in the syncReq to
-// the latest channel graph state. In order to accomplish this, (currently) the
-// entire graph is read from disk, then serialized to the format defined within
-// the current wire protocol. This cache of graph data is then sent directly to
-// the target node.
-func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
-	targetNode := syncReq.node
-
-	// TODO(roasbeef): need to also store sig data in db
-	//  * will be nice when we switch to pairing sigs would only need one ^_^
-
-	// We'll collate all the gathered routing messages into a single slice
-	// containing all the messages to be sent to the target peer.
-	var announceMessages []lnwire.Message
-
-	// First run through all the vertexes in the graph, retrieving the data
-	// for the announcement we originally retrieved.
-	var numNodes uint32
-	if err := r.cfg.Graph.ForEachNode(func(node *channeldb.LightningNode) error {
-		alias, err := lnwire.NewAlias(node.Alias)
-		if err != nil {
-			return err
-		}
+	dest := target.SerializeCompressed()
 
-		ann := &lnwire.NodeAnnouncement{
-			Signature: r.fakeSig,
-			Timestamp: uint32(node.LastUpdate.Unix()),
-			Address:   node.Address,
-			NodeID:    node.PubKey,
-			Alias:     alias,
-		}
-		announceMessages = append(announceMessages, ann)
+	log.Debugf("Searching for path to %x, sending %v", dest, amt)
 
-		numNodes++
+	// We can short circuit the routing by opportunistically checking to
+	// see if the target vertex event exists in the current graph. If
+	// we've been given route hints, then the target may be reachable
+	// through them alone.
+	if err := r.checkTarget(target, routeHints); err != nil {
+		return nil, err
+	}
 
-		return nil
-	}); err != nil {
-		return err
+	restrictParams, err := r.newRestrictParams(target, amt, restrictions,
+		routeHints, bandwidthCaps)
+	if err != nil {
+		return nil, err
 	}
+	restrictParams.extraPayloadHops = numPayloadFrames - 1
 
-	// With the vertexes gathered, we'll no retrieve the initial
-	// announcement, as well as the latest channel update announcement for
-	// both of the directed edges that make up the channel.
-	// TODO(roasbeef): multi-sig keys should also be stored in DB
-	var numEdges uint32
-	if err := r.cfg.Graph.ForEachChannel(func(e1, e2 *channeldb.ChannelEdge) error {
-		// First we'll need to obtain the channel ID for the channel
-		// advertisement. As an edge may not be advertised, we'll grab
-		// the channel ID from the edge that was.
-		var chanID lnwire.ChannelID
-		switch {
-		case e1 != nil:
-			chanID = lnwire.NewChanIDFromInt(e1.ChannelID)
-		case e2 != nil:
-			chanID = lnwire.NewChanIDFromInt(e2.ChannelID)
-		default:
-			chanID = lnwire.NewChanIDFromInt(e1.ChannelID)
-		}
+	route, err := findRoute(r.graphCache, target, amt, restrictParams)
+	if err != nil {
+		log.Errorf("Unable to find path: %v", err)
+		return nil, err
+	}
 
-		chanAnn := &lnwire.ChannelAnnouncement{
-			FirstNodeSig:     r.fakeSig,
-			SecondNodeSig:    r.fakeSig,
-			ChannelID:        chanID,
-			FirstBitcoinSig:  r.fakeSig,
-			SecondBitcoinSig: r.fakeSig,
-		}
+	// TODO(roabseef): also create the Sphinx packet and add in the route
 
-		// If the edge was advertised, then we'll use the node's
-		// identity within the announcement we send to the sync node.
-		// Otherwise, we'll fill in a dummy key.
-		//
-		// TODO(roasbeef): both else clauses need to be removed
-		// once we fully validate, andrew's PR will reconcile
-		// this
-		if e1 != nil {
-			chanAnn.FirstNodeID = e1.Node.PubKey
-			chanAnn.FirstBitcoinKey = e1.Node.PubKey
-		} else {
-			chanAnn.FirstNodeID = e2.Node.PubKey
-			chanAnn.FirstBitcoinKey = e2.Node.PubKey
-		}
-		if e2 != nil {
-			chanAnn.SecondNodeID = e2.Node.PubKey
-			chanAnn.SecondBitcoinKey = e2.Node.PubKey
-		} else {
-			chanAnn.SecondNodeID = e1.Node.PubKey
-			chanAnn.SecondBitcoinKey = e1.Node.PubKey
-		}
+	log.Debugf("Obtained path sending %v to %x: %v", amt, dest,
+		newLogClosure(func() string {
+			return spew.Sdump(route)
+		}),
+	)
 
-		// We'll unconditionally queue the channel's existence proof as
-		// it will need to be processed before either of the channel
-		// update announcements.
-		announceMessages = append(announceMessages, chanAnn)
-
-		// Since it's up to a node's policy as to whether they
-		// advertise the edge in dire direction, we don't create an
-		// advertisement if the edge is nil.
-		if e1 != nil {
-			announceMessages = append(announceMessages, &lnwire.ChannelUpdateAnnouncement{
-				Signature:                 r.fakeSig,
-				ChannelID:                 chanID,
-				Timestamp:                 uint32(e1.LastUpdate.Unix()),
-				Flags:                     0,
-				Expiry:                    e1.Expiry,
-				HtlcMinimumMstat:          uint32(e1.MinHTLC),
-				FeeBaseMstat:              uint32(e1.FeeBaseMSat),
-				FeeProportionalMillionths: uint32(e1.FeeProportionalMillionths),
-			})
-		}
-		if e2 != nil {
-			announceMessages = append(announceMessages, &lnwire.ChannelUpdateAnnouncement{
-				Signature:                 r.fakeSig,
-				ChannelID:                 chanID,
-				Timestamp:                 uint32(e2.LastUpdate.Unix()),
-				Flags:                     1,
-				Expiry:                    e2.Expiry,
-				HtlcMinimumMstat:          uint32(e2.MinHTLC),
-				FeeBaseMstat:              uint32(e2.FeeBaseMSat),
-				FeeProportionalMillionths: uint32(e2.FeeProportionalMillionths),
-			})
-		}
+	return route, nil
+}
 
-		numEdges++
-		return nil
-	}); err != nil && err != channeldb.ErrGraphNoEdgesFound {
-		log.Errorf("unable to sync edges w/ peer: %v", err)
-		return err
+// routeSorter implements sort.Interface to allow a set of routes to be sorted
+// in ascending order by their total fee, with ties broken by the total time
+// lock of the route.
+type routeSorter []*Route
+
+func (r routeSorter) Len() int {
+	return len(r)
+}
+
+func (r routeSorter) Less(i, j int) bool {
+	if r[i].TotalFees != r[j].TotalFees {
+		return r[i].TotalFees < r[j].TotalFees
 	}
 
-	log.Infof("Syncing channel graph state with %x, sending %v "+
-		"nodes and %v edges", targetNode.SerializeCompressed(),
-		numNodes, numEdges)
+	return r[i].TotalTimeLock < r[j].TotalTimeLock
+}
 
-	// With all the announcement messages gathered, send them all in a
-	// single batch to the target peer.
-	return r.cfg.SendMessages(targetNode, announceMessages...)
+func (r routeSorter) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
 }
 
-// fetchChanPoint retrieves the original outpoint which is encoded within the
-// channelID.
-func (r *ChannelRouter) fetchChanPoint(chanID *lnwire.ChannelID) (*wire.OutPoint, error) {
-	// First fetch the block hash by the block number encoded, then use
-	// that hash to fetch the block itself.
-	blockNum := int64(chanID.BlockHeight)
-	blockHash, err := r.cfg.Chain.GetBlockHash(blockNum)
+// FindRoutes attempts to query the ChannelRouter for up to numRoutes distinct
+// loop-free routes to a particular target destination which are able to send
+// `amt` after factoring in channel capacities and cumulative fees along the
+// route. The returned routes are sorted in ascending order by their total
+// fees, with ties broken by their total time lock. This allows callers to
+// select among several alternatives, or fall back to the next route without
+// recomputing a path.
+func (r *ChannelRouter) FindRoutes(target *btcec.PublicKey, amt btcutil.Amount,
+	numRoutes uint32, restrictions *RouteRestrictions,
+	routeHints [][]HopHint) ([]*Route, error) {
+
+	dest := target.SerializeCompressed()
+
+	log.Debugf("Searching for %v paths to %x, sending %v", numRoutes, dest,
+		amt)
+
+	if numRoutes == 0 {
+		numRoutes = 1
+	}
+
+	// We can short circuit the routing by opportunistically checking to
+	// see if the target vertex event exists in the current graph. If
+	// we've been given route hints, then the target may be reachable
+	// through them alone.
+	if err := r.checkTarget(target, routeHints); err != nil {
+		return nil, err
+	}
+
+	restrictParams, err := r.newRestrictParams(target, amt, restrictions,
+		routeHints, nil)
 	if err != nil {
 		return nil, err
 	}
-	fundingBlock, err := r.cfg.Chain.GetBlock(blockHash)
+
+	// Now that we know the destination is reachable within the graph,
+	// we'll execute our k-shortest paths algorithm to find a set of
+	// candidate paths to the destination.
+	paths, err := findPaths(r.graphCache, r.selfNode, target, amt,
+		numRoutes, restrictParams)
 	if err != nil {
+		log.Errorf("Unable to find paths: %v", err)
 		return nil, err
 	}
 
-	// As a sanity check, ensure that the advertised transaction index is
-	// within the bounds of the total number of transactions within a
-	// block.
-	numTxns := uint32(len(fundingBlock.Transactions))
-	if chanID.TxIndex > numTxns-1 {
-		return nil, fmt.Errorf("tx_index=#%v is out of range "+
-			"(max_index=%v), network_chan_id=%v\n", chanID.TxIndex,
-			numTxns-1, spew.Sdump(chanID))
+	// For each of the candidate paths, we'll attempt to construct a full
+	// route. Any paths which are unable to carry the payment once fees
+	// are factored in, or that violate our restrictions are skipped.
+	routes := make([]*Route, 0, len(paths))
+	for _, path := range paths {
+		route, err := newRoute(amt, path, restrictParams.bandwidthHints,
+			restrictParams.finalCLTVDelta)
+		if err == nil {
+			err = restrictParams.checkRoute(route)
+		}
+		if err != nil {
+			log.Debugf("Skipping path to %x: %v", dest, err)
+			continue
+		}
+
+		routes = append(routes, route)
+	}
+
+	// If none of the candidate paths are able to carry the payment, then
+	// we'll exit with an error.
+	if len(routes) == 0 {
+		return nil, ErrNoPathFound
 	}
 
-	// TODO(roasbeef): skipping validation here as the discovery service
-	// should handle full validate
+	// Finally, we'll sort the routes by their total fee, then by their
+	// total time lock, so the cheapest route is first.
+	sort.Sort(routeSorter(routes))
 
-	// Finally once we have the block itself, we seek to the targeted
-	// transaction index to obtain the funding output and txid.
-	fundingTx := fundingBlock.Transactions[chanID.TxIndex]
-	return &wire.OutPoint{
-		Hash:  fundingTx.TxHash(),
-		Index: uint32(chanID.TxPosition),
-	}, nil
-}
+	log.Debugf("Obtained %v paths sending %v to %x: %v", len(routes), amt,
+		dest, newLogClosure(func() string {
+			return spew.Sdump(routes)
+		}),
+	)
 
-// routingMsg couples a routing related wire message with the peer that
-// originally sent it.
-type routingMsg struct {
-	msg  lnwire.Message
-	peer *btcec.PublicKey
+	return routes, nil
 }
 
-// ProcessRoutingMessags sends a new routing message along with the peer that
-// sent the routing message to the ChannelRouter. The announcement will be
-// processed then added to a queue for batched tickled announcement to all
-// connected peers.
-//
-// TODO(roasbeef): need to move to discovery package
-func (r *ChannelRouter) ProcessRoutingMessage(msg lnwire.Message, src *btcec.PublicKey) {
-	// TODO(roasbeef): msg wrappers to add a doneChan
+// BuildRoute constructs a route capable of sending amt to the final node
+// within the passed list of hops, traveling through each of the hops in
+// order. Between each pair of consecutive hops, the channel with the largest
+// capacity is selected, and the fees and time locks of the route are then
+// filled in according to the policies of the selected channels. This allows
+// routes computed by external tooling to be completed before being passed to
+// SendToRoute.
+func (r *ChannelRouter) BuildRoute(amt btcutil.Amount,
+	hops []*btcec.PublicKey) (*Route, error) {
+
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("route must have at least one hop")
+	}
 
-	rMsg := &routingMsg{
-		msg:  msg,
-		peer: src,
+	path, err := buildPath(r.graphCache, hops, amt)
+	if err != nil {
+		return nil, err
 	}
 
-	select {
-	case r.networkMsgs <- rMsg:
-	case <-r.quit:
-		return
+	bandwidthHints, err := generateBandwidthHints(r.selfNode,
+		r.cfg.QueryBandwidth)
+	if err != nil {
+		return nil, err
 	}
+
+	return newRoute(amt, path, bandwidthHints, 0)
 }
 
-// FindRoute attempts to query the ChannelRouter for the "best" path to a
-// particular target destination which is able to send `amt` after factoring in
-// channel capacities and cumulative fees along the route.
-func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount) (*Route, error) {
-	dest := target.SerializeCompressed()
+// checkTarget ensures the target of a payment is known to the channel graph.
+// If any route hints are present, then the target may sit behind private
+// channels, so the check is skipped.
+func (r *ChannelRouter) checkTarget(target *btcec.PublicKey,
+	routeHints [][]HopHint) error {
 
-	log.Debugf("Searching for path to %x, sending %v", dest, amt)
+	if len(routeHints) != 0 {
+		return nil
+	}
 
-	// We can short circuit the routing by opportunistically checking to
-	// see if the target vertex event exists in the current graph.
-	if _, exists, err := r.cfg.Graph.HasLightningNode(target); err != nil {
-		return nil, err
+	_, exists, err := r.cfg.Graph.HasLightningNode(target)
+	if err != nil {
+		return err
 	} else if !exists {
-		log.Debugf("Target %x is not in known graph", dest)
-		return nil, ErrTargetNotInNetwork
+		log.Debugf("Target %x is not in known graph",
+			target.SerializeCompressed())
+		return ErrTargetNotInNetwork
 	}
 
-	// TODO(roasbeef): add k-shortest paths
-	route, err := findRoute(r.cfg.Graph, target, amt)
+	return nil
+}
+
+// newRestrictParams assembles the full set of restrictions used by path
+// finding for a payment of amt to target. In addition to the restrictions
+// specified by the caller, this includes the current bandwidth of each of our
+// channels, the node pairs mission control deems too unreliable to carry the
+// payment, and the ephemeral edges described by any route hints. The
+// bandwidth of any of our channels present within bandwidthCaps is capped to
+// the corresponding amount.
+func (r *ChannelRouter) newRestrictParams(target *btcec.PublicKey,
+	amt btcutil.Amount, restrictions *RouteRestrictions,
+	routeHints [][]HopHint,
+	bandwidthCaps map[uint64]btcutil.Amount) (*restrictParams, error) {
+
+	// Before attempting to find a path, we'll query the switch for the
+	// current bandwidth of each of our outgoing channels. This allows
+	// path finding to skip any of our channels which are inactive, or
+	// don't currently have enough of a local balance to carry the
+	// payment.
+	bandwidthHints, err := generateBandwidthHints(r.selfNode,
+		r.cfg.QueryBandwidth)
 	if err != nil {
-		log.Errorf("Unable to find path: %v", err)
 		return nil, err
 	}
+	for chanID, bandwidthCap := range bandwidthCaps {
+		bandwidth, ok := bandwidthHints[chanID]
+		if ok && bandwidth > bandwidthCap {
+			bandwidthHints[chanID] = bandwidthCap
+		}
+	}
 
-	// TODO(roabseef): also create the Sphinx packet and add in the route
+	// We'll also exclude any node pairs which mission control deems too
+	// unreliable to carry the payment at this point in time. If the
+	// attempt cost is set, then the remaining pairs with a reduced
+	// success probability are penalized during path finding.
+	params := &restrictParams{
+		ignoredPairs:   r.missionControl.prunedPairs(amt),
+		bandwidthHints: bandwidthHints,
+		attemptCost:    r.cfg.AttemptCost,
+	}
+	if r.cfg.AttemptCost != 0 {
+		params.pairProbabilities = r.missionControl.pairProbabilities(amt)
+	}
+	if len(routeHints) != 0 {
+		params.additionalEdges = hopHintEdges(target, routeHints)
+	}
 
-	log.Debugf("Obtained path sending %v to %x: %v", amt, dest,
-		newLogClosure(func() string {
-			return spew.Sdump(route)
-		}),
-	)
+	if restrictions == nil {
+		return params, nil
+	}
 
-	return route, nil
+	params.feeLimit = restrictions.FeeLimit
+	params.cltvLimit = restrictions.CltvLimit
+	params.finalCLTVDelta = restrictions.FinalCLTVDelta
+	params.outgoingChanID = restrictions.OutgoingChannelID
+	if restrictions.LastHop != nil {
+		lastHop := newVertex(restrictions.LastHop)
+		params.lastHop = &lastHop
+	}
+
+	params.ignoredNodes = make(map[vertex]struct{})
+	for _, node := range restrictions.IgnoredNodes {
+		params.ignoredNodes[newVertex(node)] = struct{}{}
+	}
+	params.ignoredEdges = make(map[uint64]struct{})
+	for _, chanID := range restrictions.IgnoredEdges {
+		params.ignoredEdges[chanID] = struct{}{}
+	}
+
+	return params, nil
+}
+
+// generateBandwidthHints is a helper function that's utilized by the main
+// findRoute function in order to obtain hints from the lower layer w.r.t
+// the available bandwidth of edges on the network. Currently, we'll only
+// obtain bandwidth hints for the edges we directly have open ourselves.
+// Obtaining these hints allows us to reduce the number of extraneous attempts
+// as we can skip channels that are inactive, or just don't have enough
+// bandwidth to carry the payment.
+func generateBandwidthHints(sourceNode *channeldb.LightningNode,
+	queryBandwidth func(*channeldb.ChannelEdge) btcutil.Amount) (map[uint64]btcutil.Amount, error) {
+
+	// If the lower layer hasn't been wired up to provide bandwidth hints,
+	// then we'll simply fall back to using the advertised capacity of
+	// each of our channels.
+	if queryBandwidth == nil {
+		return nil, nil
+	}
+
+	// First, we'll collect the set of outbound edges from the target
+	// source node, querying the lower link layer for the current
+	// bandwidth of each channel.
+	bandwidthHints := make(map[uint64]btcutil.Amount)
+	err := sourceNode.ForEachChannel(nil, func(edge *channeldb.ChannelEdge) error {
+		bandwidthHints[edge.ChannelID] = queryBandwidth(edge)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return bandwidthHints, nil
 }
 
 // generateSphinxPacket generates then encodes a sphinx packet which encodes
 // the onion route specified by the passed layer 3 route. The blob returned
 // from this function can immediately be included within an HTLC add packet to
-// be sent to the first hop within the route.
+// be sent to the first hop within the route. The final hop's payload carries
+// the total amount of the payment the HTLC belongs to, allowing the
+// destination to reassemble multi-path payments.
 //
 // TODO(roasbeef): add params for the per-hop payloads
-func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte, error) {
+func generateSphinxPacket(route *Route, paymentHash []byte,
+	finalPayload *FinalHopPayload) ([]byte, error) {
+
 	// First obtain all the public keys along the route which are contained
 	// in each hop.
 	nodes := make([]*btcec.PublicKey, len(route.Hops))
@@ -1042,12 +991,30 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte, error) {
 	// TODO(roasbeef): properly set CLTV value, payment amount, and chain
 	// within hop payloads.
 	var hopPayloads [][]byte
-	for i := 0; i < len(route.Hops); i++ {
+	for i := 0; i < len(route.Hops)-1; i++ {
 		payload := bytes.Repeat([]byte{byte('A' + i)},
 			sphinx.HopPayloadSize)
 		hopPayloads = append(hopPayloads, payload)
 	}
 
+	// A final hop payload too large to fit within the per-hop data of a
+	// single hop is split into several frames. Each frame beyond the
+	// first is addressed to the destination itself, which peels them off
+	// in turn.
+	encodedPayload := finalPayload.Encode()
+	numFrames := len(encodedPayload) / sphinx.HopPayloadSize
+	if len(nodes)+numFrames-1 > sphinx.NumMaxHops {
+		return nil, ErrPayloadTooLarge
+	}
+	for i := 0; i < numFrames; i++ {
+		frame := encodedPayload[i*sphinx.HopPayloadSize : (i+1)*
+			sphinx.HopPayloadSize]
+		hopPayloads = append(hopPayloads, frame)
+		if i > 0 {
+			nodes = append(nodes, nodes[len(nodes)-1])
+		}
+	}
+
 	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
 	if err != nil {
 		return nil, err
@@ -1080,6 +1047,18 @@ func generateSphinxPacket(route *Route, paymentHash []byte) ([]byte, error) {
 	return onionBlob.Bytes(), nil
 }
 
+// DefaultPaymentTimeout is the default maximum duration spent attempting to
+// complete a payment before giving up.
+const DefaultPaymentTimeout = time.Minute
+
+// DefaultMaxShards is the default maximum number of HTLC shards a single
+// payment may be split into.
+const DefaultMaxShards = 16
+
+// MinShardAmount is the smallest amount a payment will be split into. Shards
+// smaller than twice this amount are never split further.
+const MinShardAmount = btcutil.Amount(1000)
+
 // LightningPayment describes a payment to be sent through the network to the
 // final destination.
 type LightningPayment struct {
@@ -1095,67 +1074,483 @@ type LightningPayment struct {
 	// the first hop.
 	PaymentHash [32]byte
 
+	// Timeout is the maximum duration that will be spent attempting to
+	// complete the payment. Once the timeout elapses, no further routes
+	// will be attempted. If zero, then DefaultPaymentTimeout is used.
+	Timeout time.Duration
+
+	// RouteRestrictions is the set of restrictions each route attempted
+	// for the payment must satisfy.
+	RouteRestrictions
+
+	// RouteHints is a set of routes, typically provided within a payment
+	// request, that may be used to reach a target that sits behind
+	// private channels.
+	RouteHints [][]HopHint
+
+	// MaxShards is the maximum number of HTLCs the payment may be split
+	// into if no single route is able to carry the full amount. A value
+	// of one disables splitting, while zero indicates DefaultMaxShards.
+	MaxShards uint32
+
+	// PaymentRequest is the encoded payment request the payment pays, if
+	// any. It's recorded along with the payment.
+	PaymentRequest []byte
+
+	// CustomRecords is an optional set of custom records delivered to the
+	// destination within the final hop payload of each HTLC of the
+	// payment. The type of each record must be at least
+	// CustomRecordTypeStart.
+	CustomRecords map[uint64][]byte
+
 	// TODO(roasbeef): add message?
 }
 
 // SendPayment attempts to send a payment as described within the passed
 // LightningPayment. This function is blocking and will return either: when the
 // payment is successful, or all candidates routes have been attempted and
-// resulted in a failed payment. If the payment succeeds, then a non-nil Route
-// will be returned which describes the path the successful payment traversed
-// within the network to reach the destination.
-func (r *ChannelRouter) SendPayment(payment *LightningPayment) (*Route, error) {
-	// Query the graph for a potential path to the destination node that
-	// can support our payment amount. If a path is ultimately unavailable,
-	// then an error will be returned.
-	route, err := r.FindRoute(payment.Target, payment.Amount)
+// resulted in a failed payment. If the payment succeeds, then the set of
+// routes the payment's shards successfully traversed within the network to
+// reach the destination will be returned.
+//
+// If no single route is able to carry the full amount, then the payment is
+// split into several HTLC shards which share the payment hash, each of which
+// is dispatched concurrently over its own route. The final hop payload of
+// every shard carries the total amount of the payment, allowing the
+// destination to hold the shards until the full amount has arrived. Splitting
+// is adaptive: a shard which can't be routed, or which fails due to
+// insufficient capacity, is itself split in two until either MaxShards is
+// reached, or the shards would fall below MinShardAmount.
+//
+// Each failed attempt is reported to mission control, which penalizes the
+// node pairs of the failed route, causing the next attempt to select an
+// alternative route. Attempts continue until either no route remains, the
+// destination rejects the payment outright, or the payment's timeout
+// elapses. As an HTLC in flight can't be abandoned, this method always waits
+// for any outstanding shards to be resolved before returning.
+//
+// If the router is configured with a PaymentControlDB, then the payment and
+// each of its attempts are recorded within it, and an error is returned if a
+// payment to the same payment hash is already in flight, or has already
+// succeeded.
+func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([]*Route, error) {
+	if err := ValidateCustomRecords(payment.CustomRecords); err != nil {
+		return nil, err
+	}
+
+	// The bandwidth of our channels reported by the switch is only
+	// updated once the HTLC of each shard has been processed by it. So
+	// that shards dispatched in quick succession don't oversubscribe our
+	// channels, we'll cap the bandwidth of each channel to its bandwidth
+	// at the start of the payment, minus the amounts of our shards
+	// currently in flight over it.
+	startBandwidth, err := generateBandwidthHints(r.selfNode,
+		r.cfg.QueryBandwidth)
 	if err != nil {
 		return nil, err
 	}
-	log.Tracef("Selected route for payment: %#v", route)
+	inFlightAmts := make(map[uint64]btcutil.Amount)
+	bandwidthCaps := func() map[uint64]btcutil.Amount {
+		caps := make(map[uint64]btcutil.Amount, len(inFlightAmts))
+		for chanID, inFlightAmt := range inFlightAmts {
+			bandwidth, ok := startBandwidth[chanID]
+			switch {
+			case !ok:
+				continue
+			case inFlightAmt > bandwidth:
+				caps[chanID] = 0
+			default:
+				caps[chanID] = bandwidth - inFlightAmt
+			}
+		}
+		return caps
+	}
+
+	err = r.control.initPayment(payment.PaymentHash, payment.Amount,
+		payment.PaymentRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := payment.Timeout
+	if timeout == 0 {
+		timeout = DefaultPaymentTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	timeoutChan := timer.C
+
+	maxShards := payment.MaxShards
+	if maxShards == 0 {
+		maxShards = DefaultMaxShards
+	}
+
+	sourceVertex := newVertex(r.selfNode.PubKey)
+
+	// Every shard carries the same final hop payload.
+	finalPayload := &FinalHopPayload{
+		TotalAmount:   payment.Amount,
+		CustomRecords: payment.CustomRecords,
+	}
+
+	// Each shard in flight will deliver exactly one result, and there can
+	// never be more than maxShards shards in flight, so the results
+	// channel is buffered such that the goroutines dispatching shards
+	// never block.
+	type shardResult struct {
+		amt   btcutil.Amount
+		route *Route
+		err   error
+	}
+	results := make(chan *shardResult, maxShards)
+
+	var (
+		// pending is the set of shard amounts which are awaiting a
+		// route. Initially we'll attempt to send the full amount as a
+		// single shard.
+		pending   = []btcutil.Amount{payment.Amount}
+		numShards = uint32(1)
+		inFlight  int
+
+		routes    []*Route
+		succeeded bool
+		timedOut  bool
+		stopErr   error
+		lastErr   error
+	)
+
+	// canSplit returns true if a shard of the passed amount may be split
+	// into two smaller shards.
+	canSplit := func(amt btcutil.Amount) bool {
+		return numShards < maxShards && amt >= 2*MinShardAmount
+	}
+
+	for {
+		// Dispatch each of the pending shards, unless the payment has
+		// already succeeded, been rejected, or timed out. Once a
+		// single shard has been settled, the destination has received
+		// the full amount, so no further shards should be sent.
+		for len(pending) > 0 && !succeeded && !timedOut && stopErr == nil {
+			amt := pending[0]
+			pending = pending[1:]
+
+			// Query the graph for a potential path to the
+			// destination node that can support the shard's
+			// amount. If no such path exists, then we'll attempt
+			// to split the shard in two.
+			route, err := r.findCappedRoute(payment.Target, amt,
+				shardRestrictions(payment, amt),
+				payment.RouteHints, bandwidthCaps(),
+				finalPayload.NumFrames())
+			switch {
+			case err == nil:
+
+			case (err == ErrNoPathFound ||
+				err == ErrInsufficientCapacity) && canSplit(amt):
+
+				log.Debugf("Unable to route shard of %v for "+
+					"payment %x, splitting", amt,
+					payment.PaymentHash)
+
+				pending = append(pending, amt/2, amt-amt/2)
+				numShards++
+				continue
+
+			// If we're unable to route the shard at all, then the
+			// payment has failed. If we've already attempted prior
+			// routes, then we'll return the error of the last
+			// attempt as it's more descriptive.
+			default:
+				if lastErr != nil {
+					stopErr = lastErr
+				} else {
+					stopErr = err
+				}
+				continue
+			}
+			log.Tracef("Selected route for shard of %v: %#v", amt,
+				route)
+
+			// Before the shard is sent, we'll record the attempt,
+			// such that it can be resumed after a restart.
+			attemptID, err := r.control.registerAttempt(
+				payment.PaymentHash, route,
+			)
+			if err != nil {
+				stopErr = err
+				continue
+			}
+
+			inFlight++
+			firstHop := route.Hops[0].Channel.ChannelID
+			inFlightAmts[firstHop] += route.TotalAmount
+
+			go func(amt btcutil.Amount, route *Route) {
+				preimage, err := r.sendToRoute(route,
+					payment.PaymentHash, finalPayload)
+				r.control.resolveAttempt(payment.PaymentHash,
+					attemptID, preimage, err)
+
+				results <- &shardResult{
+					amt:   amt,
+					route: route,
+					err:   err,
+				}
+			}(amt, route)
+		}
+
+		// If there are no longer any shards in flight, then the
+		// payment has reached its final state.
+		if inFlight == 0 {
+			if succeeded {
+				return routes, nil
+			}
+
+			err := stopErr
+			if err == nil {
+				err = fmt.Errorf("%v, last failure: %v",
+					ErrPaymentTimeout, lastErr)
+			}
+			r.control.fail(payment.PaymentHash, err)
+
+			return nil, err
+		}
+
+		select {
+		case res := <-results:
+			inFlight--
+
+			firstHop := res.route.Hops[0].Channel.ChannelID
+			inFlightAmts[firstHop] -= res.route.TotalAmount
+			if inFlightAmts[firstHop] == 0 {
+				delete(inFlightAmts, firstHop)
+			}
+
+			// Report the outcome of the shard to mission control
+			// so that future attempts avoid any failed pairs.
+			pairs := routePairs(sourceVertex, res.route)
+			r.reportAttempt(res.amt, pairs, res.err)
+			if res.err == nil {
+				routes = append(routes, res.route)
+				succeeded = true
+				continue
+			}
+
+			log.Errorf("Attempt to send shard of %v for payment %x "+
+				"failed: %v", res.amt, payment.PaymentHash,
+				res.err)
+
+			lastErr = res.err
+
+			// If the destination itself rejected the payment, then
+			// there's no point in attempting an alternative route.
+			reason, ok := res.err.(lnwire.CancelReason)
+			if ok && (reason == lnwire.UnknownPaymentHash ||
+				reason == lnwire.IncorrectValue) {
+
+				stopErr = res.err
+				continue
+			}
+
+			// If the shard failed as a link along its route lacked
+			// the capacity to carry it, then we'll split it in
+			// two. Otherwise, we'll retry the shard in full over
+			// an alternative route.
+			if ok && reason == lnwire.InsufficientCapacity &&
+				canSplit(res.amt) {
+
+				half := res.amt / 2
+				pending = append(pending, half, res.amt-half)
+				numShards++
+			} else {
+				pending = append(pending, res.amt)
+			}
+
+		// Once the payment has timed out, we'll stop dispatching new
+		// shards, but will continue to wait for those in flight.
+		case <-timeoutChan:
+			timedOut = true
+			timeoutChan = nil
+
+		// If we're shutting down, then the payment is left in flight
+		// within the control tower, allowing it to be resumed once
+		// we're restarted.
+		case <-r.quit:
+			return nil, ErrRouterShuttingDown
+		}
+	}
+}
+
+// SendToRoute attempts to send a payment with the passed payment hash over
+// an explicitly constructed route, such as one built via BuildRoute. Unlike
+// SendPayment, no alternative routes are attempted if the payment fails. If
+// the payment is successful, then the preimage revealed by the destination is
+// returned. Otherwise, the failure returned by the network is passed along
+// unaltered, which in the case of a failure generated by a remote node will
+// be an lnwire.CancelReason. Any custom records passed are delivered to the
+// destination within the final hop payload.
+func (r *ChannelRouter) SendToRoute(route *Route, paymentHash [32]byte,
+	customRecords map[uint64][]byte) ([32]byte, error) {
+
+	var preimage [32]byte
+
+	if err := ValidateCustomRecords(customRecords); err != nil {
+		return preimage, err
+	}
+
+	switch {
+	case len(route.Hops) == 0:
+		return preimage, fmt.Errorf("route must have at least one hop")
+	case len(route.Hops) > HopLimit:
+		return preimage, ErrMaxHopsExceeded
+	}
+
+	// The amount delivered to the destination is the amount forwarded by
+	// the final hop, which must be covered by the amount extended to the
+	// first hop.
+	amt := route.Hops[len(route.Hops)-1].AmtToForward
+	if route.TotalAmount < amt {
+		return preimage, fmt.Errorf("route total amount %v is less "+
+			"than the amount to deliver %v", route.TotalAmount, amt)
+	}
+
+	select {
+	case <-r.quit:
+		return preimage, ErrRouterShuttingDown
+	default:
+	}
+
+	// As with SendPayment, the payment and its single attempt are
+	// recorded within the control tower.
+	if err := r.control.initPayment(paymentHash, amt, nil); err != nil {
+		return preimage, err
+	}
+	attemptID, err := r.control.registerAttempt(paymentHash, route)
+	if err != nil {
+		r.control.fail(paymentHash, err)
+		return preimage, err
+	}
+
+	// As with SendPayment, we'll report the outcome of the payment to
+	// mission control, such that future path finding attempts are able to
+	// benefit from it.
+	pairs := routePairs(newVertex(r.selfNode.PubKey), route)
+	finalPayload := &FinalHopPayload{
+		TotalAmount:   amt,
+		CustomRecords: customRecords,
+	}
+	preimage, err = r.sendToRoute(route, paymentHash, finalPayload)
+	r.reportAttempt(amt, pairs, err)
+	r.control.resolveAttempt(paymentHash, attemptID, preimage, err)
+	if err != nil {
+		log.Errorf("Attempt to send payment %x over route failed: %v",
+			paymentHash, err)
+
+		r.control.fail(paymentHash, err)
+	}
+
+	return preimage, err
+}
+
+// reportAttempt reports the outcome of an attempt to send amt over the route
+// consisting of the passed node pairs to mission control. A nil error
+// indicates the attempt was successful.
+func (r *ChannelRouter) reportAttempt(amt btcutil.Amount, pairs []nodePair,
+	err error) {
+
+	if err == nil {
+		r.missionControl.reportSuccess(amt, pairs...)
+		return
+	}
+
+	switch reason, ok := err.(lnwire.CancelReason); {
+	// If the error wasn't returned by a remote node, then our switch was
+	// unable to forward the payment over the first hop, so we only
+	// penalize our own pair.
+	case !ok:
+		r.missionControl.reportFailure(amt, pairs[0])
+
+	// If the destination itself rejected the payment, or gave up waiting
+	// for the remainder of a multi-path payment, then the route itself
+	// was sound.
+	case reason == lnwire.UnknownPaymentHash ||
+		reason == lnwire.IncorrectValue ||
+		reason == lnwire.MPPTimeout:
+
+		r.missionControl.reportSuccess(amt, pairs...)
+
+	// As failures don't yet identify the node that generated them, we'll
+	// penalize all the pairs beyond our first hop, as the HTLC was
+	// successfully extended over our own channel.
+	case len(pairs) > 1:
+		r.missionControl.reportFailure(amt, pairs[1:]...)
+
+	default:
+		r.missionControl.reportFailure(amt, pairs...)
+	}
+}
+
+// shardRestrictions returns the restrictions the route of a shard of the
+// passed amount must satisfy. The fee limit of the payment is divided among
+// its shards in proportion to their amounts.
+func shardRestrictions(payment *LightningPayment,
+	amt btcutil.Amount) *RouteRestrictions {
+
+	restrictions := payment.RouteRestrictions
+	if restrictions.FeeLimit != 0 && amt < payment.Amount {
+		feeLimit := restrictions.FeeLimit * amt / payment.Amount
+		if feeLimit == 0 {
+			feeLimit = 1
+		}
+		restrictions.FeeLimit = feeLimit
+	}
+
+	return &restrictions
+}
+
+// sendToRoute generates the sphinx packet for the passed route, then sends
+// the HTLC to the first hop in the route via the switch. The passed final hop
+// payload carries the total amount of the payment the HTLC belongs to, along
+// with any custom records. This method blocks until the HTLC has either been
+// settled or cancelled, returning the preimage of the payment if it was
+// settled.
+func (r *ChannelRouter) sendToRoute(route *Route, paymentHash [32]byte,
+	finalPayload *FinalHopPayload) ([32]byte, error) {
 
 	// Generate the raw encoded sphinx packet to be included along with the
 	// htlcAdd message that we send directly to the switch.
-	sphinxPacket, err := generateSphinxPacket(route, payment.PaymentHash[:])
+	sphinxPacket, err := generateSphinxPacket(route, paymentHash[:],
+		finalPayload)
 	if err != nil {
-		return nil, err
+		return [32]byte{}, err
 	}
 
 	// Craft an HTLC packet to send to the layer 2 switch. The metadata
 	// within this packet will be used to route the payment through the
-	// network, starting with the first-hop.
+	// network, starting with the first-hop. The HTLC expires once the
+	// route's total time lock has passed from the current height.
+	expiry := atomic.LoadUint32(&r.bestHeight) + route.TotalTimeLock
 	htlcAdd := &lnwire.HTLCAddRequest{
+		Expiry:           expiry,
 		Amount:           route.TotalAmount,
-		RedemptionHashes: [][32]byte{payment.PaymentHash},
+		RedemptionHashes: [][32]byte{paymentHash},
 		OnionBlob:        sphinxPacket,
 	}
 
 	// Attempt to send this payment through the network to complete the
-	// payment. If this attempt fails, then we'll bail our early.
+	// payment.
 	firstHop := route.Hops[0].Channel.Node.PubKey
-	if err := r.cfg.SendToSwitch(firstHop, htlcAdd); err != nil {
-		return nil, err
-	}
-
-	return route, nil
-}
-
-// TopologyClient...
-// TODO(roasbeef): put in discovery package?
-type TopologyClient struct {
-}
-
-// TopologyChange...
-type TopologyChange struct {
-	NewNodes    []*channeldb.LinkNode
-	NewChannels []*channeldb.ChannelEdge
+	return r.cfg.SendToSwitch(firstHop, htlcAdd)
 }
 
-// notifyTopologyChange...
-func (r *ChannelRouter) notifyTopologyChange() {
+// QueryMissionControl returns the payment history mission control has
+// recorded for each node pair.
+func (r *ChannelRouter) QueryMissionControl() []*MissionControlPair {
+	return r.missionControl.snapshot()
 }
 
-// SubscribeTopology....
-func (r *ChannelRouter) SubscribeTopology() (*TopologyClient, error) {
-	return nil, nil
+// ResetMissionControl clears all payment history recorded by mission
+// control.
+func (r *ChannelRouter) ResetMissionControl() error {
+	return r.missionControl.reset()
 }
@@ -0,0 +1,665 @@
+package routing
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestFinalHopPayloadEncoding(t *testing.T) {
+	payload := &FinalHopPayload{
+		TotalAmount: btcutil.Amount(123456789),
+	}
+
+	encoded := payload.Encode()
+	if len(encoded) != sphinx.HopPayloadSize {
+		t.Fatalf("expected payload of %v bytes, instead have %v",
+			sphinx.HopPayloadSize, len(encoded))
+	}
+
+	var rawPayload [sphinx.HopPayloadSize]byte
+	copy(rawPayload[:], encoded)
+	decoded, ok := DecodeFinalHopPayload(rawPayload[:])
+	if !ok {
+		t.Fatalf("unable to decode payload")
+	}
+	if decoded.TotalAmount != payload.TotalAmount {
+		t.Fatalf("total amount mismatch: expected %v, got %v",
+			payload.TotalAmount, decoded.TotalAmount)
+	}
+
+	// The filler payloads of intermediate hops shouldn't be mistaken for
+	// a multi-path payment record.
+	for i := range rawPayload {
+		rawPayload[i] = 'A'
+	}
+	if _, ok := DecodeFinalHopPayload(rawPayload[:]); ok {
+		t.Fatalf("filler payload decoded as multi-path payment record")
+	}
+}
+
+func TestFinalHopPayloadCustomRecords(t *testing.T) {
+	payload := &FinalHopPayload{
+		TotalAmount: btcutil.Amount(1000),
+		CustomRecords: map[uint64][]byte{
+			KeySendRecordType:     bytes.Repeat([]byte{1}, 32),
+			CustomRecordTypeStart: []byte("custom"),
+		},
+	}
+
+	// As the custom records don't fit within a single hop's payload, the
+	// payload should span several frames.
+	encoded := payload.Encode()
+	if len(encoded)%sphinx.HopPayloadSize != 0 || payload.NumFrames() < 2 {
+		t.Fatalf("expected payload spanning several frames, instead "+
+			"have %v bytes", len(encoded))
+	}
+	decoded, ok := DecodeFinalHopPayload(encoded)
+	if !ok {
+		t.Fatalf("unable to decode payload")
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("payload mismatch: expected %v, got %v",
+			spew.Sdump(payload), spew.Sdump(decoded))
+	}
+
+	// We'll now send the payload over a two hop route, ensuring the
+	// destination is able to collect all of its frames.
+	var (
+		keys  []*btcec.PrivateKey
+		route = &Route{}
+	)
+	for i := 0; i < 2; i++ {
+		key, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		keys = append(keys, key)
+		route.Hops = append(route.Hops, &Hop{
+			Channel: &channeldb.ChannelEdge{
+				Node: &channeldb.LightningNode{
+					PubKey: key.PubKey(),
+				},
+			},
+		})
+	}
+
+	paymentHash := bytes.Repeat([]byte{2}, 32)
+	onionBlob, err := generateSphinxPacket(route, paymentHash, payload)
+	if err != nil {
+		t.Fatalf("unable to generate sphinx packet: %v", err)
+	}
+	onionPkt := &sphinx.OnionPacket{}
+	if err := onionPkt.Decode(bytes.NewReader(onionBlob)); err != nil {
+		t.Fatalf("unable to decode onion packet: %v", err)
+	}
+
+	// The first hop should forward the packet to the destination.
+	router := sphinx.NewRouter(keys[0], &chaincfg.SimNetParams)
+	packet, err := router.ProcessOnionPacket(onionPkt, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to process onion packet: %v", err)
+	}
+	packet, hopPayload, err := CollectPayloadFrames(
+		router, keys[0].PubKey(), packet, paymentHash,
+	)
+	if err != nil {
+		t.Fatalf("unable to collect payload frames: %v", err)
+	}
+	if packet.Action != sphinx.MoreHops || len(hopPayload) !=
+		sphinx.HopPayloadSize {
+
+		t.Fatalf("first hop should forward the packet")
+	}
+
+	router = sphinx.NewRouter(keys[1], &chaincfg.SimNetParams)
+	packet, err = router.ProcessOnionPacket(packet.Packet, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to process onion packet: %v", err)
+	}
+	packet, hopPayload, err = CollectPayloadFrames(
+		router, keys[1].PubKey(), packet, paymentHash,
+	)
+	if err != nil {
+		t.Fatalf("unable to collect payload frames: %v", err)
+	}
+	if packet.Action != sphinx.ExitNode {
+		t.Fatalf("destination should be the exit node")
+	}
+	decoded, ok = DecodeFinalHopPayload(hopPayload)
+	if !ok {
+		t.Fatalf("unable to decode payload")
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Fatalf("payload mismatch: expected %v, got %v",
+			spew.Sdump(payload), spew.Sdump(decoded))
+	}
+
+	// The frames of the payload, along with the hops of the route, must
+	// fit within the onion.
+	for len(route.Hops) < sphinx.NumMaxHops {
+		route.Hops = append(route.Hops, route.Hops[0])
+	}
+	_, err = generateSphinxPacket(route, paymentHash, payload)
+	if err != ErrPayloadTooLarge {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+
+	// Finally, records whose types lie below the custom range should be
+	// rejected.
+	payload.CustomRecords[1] = nil
+	if err := ValidateCustomRecords(payload.CustomRecords); err !=
+		ErrInvalidCustomRecord {
+
+		t.Fatalf("expected ErrInvalidCustomRecord, got %v", err)
+	}
+}
+
+func TestSendPaymentMultiPath(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	var (
+		mtx   sync.Mutex
+		htlcs []*lnwire.HTLCAddRequest
+	)
+	router, err := New(Config{
+		Graph: graph,
+		SendToSwitch: func(_ *btcec.PublicKey,
+			htlc *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+			mtx.Lock()
+			htlcs = append(htlcs, htlc)
+			mtx.Unlock()
+			return [32]byte{}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// No single route to satoshi is able to carry the full amount of the
+	// payment, so it should be split into two shards, each of which is
+	// routed through luo ji.
+	payment := &LightningPayment{
+		Target: aliases["satoshi"],
+		Amount: btcutil.Amount(60000),
+	}
+	routes, err := router.SendPayment(payment)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected %v shards, instead have %v", 2, len(routes))
+	}
+	if len(htlcs) != 2 {
+		t.Fatalf("expected %v htlcs, instead have %v", 2, len(htlcs))
+	}
+
+	var total btcutil.Amount
+	for _, htlc := range htlcs {
+		if htlc.Amount >= payment.Amount {
+			t.Fatalf("shard of %v should be smaller than payment "+
+				"of %v", htlc.Amount, payment.Amount)
+		}
+		if htlc.RedemptionHashes[0] != payment.PaymentHash {
+			t.Fatalf("shard doesn't share the payment hash")
+		}
+		total += htlc.Amount
+	}
+	if total < payment.Amount {
+		t.Fatalf("shards deliver %v, expected at least %v", total,
+			payment.Amount)
+	}
+
+	// If splitting is disabled, then the payment should fail outright.
+	payment.MaxShards = 1
+	if _, err := router.SendPayment(payment); err != ErrInsufficientCapacity {
+		t.Fatalf("expected ErrInsufficientCapacity, instead have %v",
+			err)
+	}
+
+	// Next, we'll have the second hop reject any HTLC larger than 20k
+	// satoshis due to insufficient capacity. The initial shard should
+	// fail, causing it to be split further, with both halves succeeding.
+	htlcs = nil
+	router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		htlc *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+		mtx.Lock()
+		htlcs = append(htlcs, htlc)
+		mtx.Unlock()
+
+		if htlc.Amount > 20000 {
+			return [32]byte{}, lnwire.CancelReason(
+				lnwire.InsufficientCapacity,
+			)
+		}
+		return [32]byte{}, nil
+	}
+	payment = &LightningPayment{
+		Target: aliases["satoshi"],
+		Amount: btcutil.Amount(30000),
+	}
+	routes, err = router.SendPayment(payment)
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected %v shards, instead have %v", 2, len(routes))
+	}
+	if len(htlcs) != 3 {
+		t.Fatalf("expected %v attempts, instead have %v", 3, len(htlcs))
+	}
+}
+
+func TestSendPaymentInFlightBandwidth(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	const (
+		luojiChanID   = 689530843
+		satoshiChanID = 2340213491
+		paymentAmt    = btcutil.Amount(40000)
+	)
+
+	// Our channel to luo ji only has a spendable balance of 35k satoshis,
+	// which isn't updated as shards are sent over it, just as the switch
+	// only updates it once each HTLC has been processed.
+	bandwidth := map[uint64]btcutil.Amount{
+		luojiChanID:   35000,
+		satoshiChanID: 10000,
+	}
+
+	// Each HTLC is held until the full amount of the payment is in
+	// flight, as the destination would do.
+	var (
+		mtx      sync.Mutex
+		sentAmts = make(map[string]btcutil.Amount)
+		total    btcutil.Amount
+		allSent  = make(chan struct{})
+	)
+	router, err := New(Config{
+		Graph: graph,
+		SendToSwitch: func(firstHop *btcec.PublicKey,
+			htlc *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+			mtx.Lock()
+			sentAmts[string(firstHop.SerializeCompressed())] +=
+				htlc.Amount
+			total += htlc.Amount
+			if total >= paymentAmt && total-htlc.Amount < paymentAmt {
+				close(allSent)
+			}
+			mtx.Unlock()
+
+			select {
+			case <-allSent:
+				return [32]byte{}, nil
+			case <-time.After(time.Second * 5):
+				return [32]byte{}, lnwire.CancelReason(
+					lnwire.MPPTimeout,
+				)
+			}
+		},
+		QueryBandwidth: func(edge *channeldb.ChannelEdge) btcutil.Amount {
+			if amt, ok := bandwidth[edge.ChannelID]; ok {
+				return amt
+			}
+			return edge.Capacity
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// The payment should be split such that the shards in flight over our
+	// channel to luo ji never exceed its bandwidth, with the remainder
+	// sent over our direct channel to satoshi.
+	payment := &LightningPayment{
+		Target: aliases["satoshi"],
+		Amount: paymentAmt,
+	}
+	if _, err := router.SendPayment(payment); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	luojiKey := string(aliases["luoji"].SerializeCompressed())
+	if sentAmts[luojiKey] > bandwidth[luojiChanID] {
+		t.Fatalf("%v sent to luo ji, exceeding bandwidth of %v",
+			sentAmts[luojiKey], bandwidth[luojiChanID])
+	}
+	satoshiKey := string(aliases["satoshi"].SerializeCompressed())
+	if sentAmts[satoshiKey] == 0 {
+		t.Fatalf("no shards sent over direct channel to satoshi")
+	}
+}
+
+func TestBuildAndSendToRoute(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	preimage := [32]byte{1, 2, 3}
+	var htlcs []*lnwire.HTLCAddRequest
+	router, err := New(Config{
+		Graph: graph,
+		SendToSwitch: func(_ *btcec.PublicKey,
+			htlc *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+			htlcs = append(htlcs, htlc)
+			return preimage, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// We'll build a route to satoshi which travels through luo ji, rather
+	// than using our direct channel.
+	amt := btcutil.Amount(1000)
+	hops := []*btcec.PublicKey{aliases["luoji"], aliases["satoshi"]}
+	route, err := router.BuildRoute(amt, hops)
+	if err != nil {
+		t.Fatalf("unable to build route: %v", err)
+	}
+
+	if len(route.Hops) != 2 {
+		t.Fatalf("expected %v hops, instead have %v", 2,
+			len(route.Hops))
+	}
+	expectedChans := []uint64{689530843, 523452362}
+	var timeLock uint32
+	for i, hop := range route.Hops {
+		if hop.Channel.ChannelID != expectedChans[i] {
+			t.Fatalf("hop %v should use channel %v, instead uses "+
+				"%v", i, expectedChans[i],
+				hop.Channel.ChannelID)
+		}
+		if !hop.Channel.Node.PubKey.IsEqual(hops[i]) {
+			t.Fatalf("hop %v leads to the wrong node", i)
+		}
+		timeLock += uint32(hop.TimeLockDelta)
+	}
+	if route.TotalAmount != amt+route.TotalFees {
+		t.Fatalf("total amount should be %v, instead is %v",
+			amt+route.TotalFees, route.TotalAmount)
+	}
+	if route.TotalTimeLock != timeLock {
+		t.Fatalf("total time lock should be %v, instead is %v",
+			timeLock, route.TotalTimeLock)
+	}
+
+	// A route can't be built between nodes which don't share a channel.
+	_, err = router.BuildRoute(amt, []*btcec.PublicKey{
+		aliases["luoji"], aliases["sophon"],
+	})
+	if err == nil {
+		t.Fatalf("route shouldn't be built without a channel")
+	}
+
+	// Sending over the route should return the preimage revealed by the
+	// destination, with the HTLC carrying the route's total amount, and
+	// expiring once the route's total time lock has passed.
+	router.bestHeight = 100
+	paymentHash := [32]byte{4, 5, 6}
+	sentPreimage, err := router.SendToRoute(route, paymentHash, nil)
+	if err != nil {
+		t.Fatalf("unable to send to route: %v", err)
+	}
+	if sentPreimage != preimage {
+		t.Fatalf("preimage mismatch: expected %x, got %x", preimage,
+			sentPreimage)
+	}
+	if len(htlcs) != 1 {
+		t.Fatalf("expected a single htlc, instead have %v", len(htlcs))
+	}
+	if htlcs[0].Amount != route.TotalAmount {
+		t.Fatalf("htlc should carry %v, instead carries %v",
+			route.TotalAmount, htlcs[0].Amount)
+	}
+	if htlcs[0].RedemptionHashes[0] != paymentHash {
+		t.Fatalf("htlc has incorrect payment hash")
+	}
+	if htlcs[0].Expiry != 100+route.TotalTimeLock {
+		t.Fatalf("htlc should expire at %v, instead expires at %v",
+			100+route.TotalTimeLock, htlcs[0].Expiry)
+	}
+
+	// If the payment fails, then the failure should be returned
+	// unaltered.
+	router.cfg.SendToSwitch = func(_ *btcec.PublicKey,
+		_ *lnwire.HTLCAddRequest) ([32]byte, error) {
+
+		return [32]byte{}, lnwire.CancelReason(lnwire.UnknownPaymentHash)
+	}
+	_, err = router.SendToRoute(route, paymentHash, nil)
+	if err != lnwire.CancelReason(lnwire.UnknownPaymentHash) {
+		t.Fatalf("expected %v, instead have %v",
+			lnwire.CancelReason(lnwire.UnknownPaymentHash), err)
+	}
+
+	// Custom records of a type reserved for the protocol itself should be
+	// rejected before anything is sent.
+	_, err = router.SendToRoute(route, paymentHash, map[uint64][]byte{
+		1: []byte("order"),
+	})
+	if err != ErrInvalidCustomRecord {
+		t.Fatalf("expected ErrInvalidCustomRecord, instead have %v", err)
+	}
+}
+
+// waitForTopologyChange waits for the next topology change to be delivered to
+// the passed client.
+func waitForTopologyChange(t *testing.T,
+	client *TopologyClient) *TopologyChange {
+
+	select {
+	case change := <-client.TopologyChanges:
+		return change
+	case <-time.After(time.Second * 5):
+		t.Fatalf("topology change wasn't received")
+	}
+	return nil
+}
+
+func TestTopologyNotifications(t *testing.T) {
+	graph, cleanUp, _, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	router, err := New(Config{
+		Graph: graph,
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer router.Stop()
+
+	client, err := router.SubscribeTopology()
+	if err != nil {
+		t.Fatalf("unable to subscribe to topology: %v", err)
+	}
+
+	// A newly added node should be delivered as a node update.
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	testAddr, err := net.ResolveTCPAddr("tcp", "192.0.0.1:8888")
+	if err != nil {
+		t.Fatalf("unable to resolve addr: %v", err)
+	}
+	newNode := &channeldb.LightningNode{
+		LastUpdate: time.Now(),
+		Address:    testAddr,
+		PubKey:     priv.PubKey(),
+		Alias:      "vitalik",
+	}
+	if err := router.AddNode(newNode); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+	change := waitForTopologyChange(t, client)
+	if len(change.NodeUpdates) != 1 {
+		t.Fatalf("expected 1 node update, instead have %v",
+			len(change.NodeUpdates))
+	}
+	nodeUpdate := change.NodeUpdates[0]
+	if !nodeUpdate.IdentityKey.IsEqual(newNode.PubKey) ||
+		nodeUpdate.Alias != newNode.Alias {
+
+		t.Fatalf("unexpected node update: %v", nodeUpdate)
+	}
+
+	// Next, we'll update the policy of the first node of an existing
+	// channel. The update should carry both the new policy, and the policy
+	// it replaces.
+	const chanID = 3495345
+	prevPolicy, _, err := graph.FetchChannelEdgesByID(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch edge: %v", err)
+	}
+	policy := *prevPolicy
+	policy.Node = nil
+	policy.LastUpdate = time.Now().Add(time.Second)
+	policy.FeeBaseMSat = prevPolicy.FeeBaseMSat + 100
+	if err := router.UpdateEdge(&policy); err != nil {
+		t.Fatalf("unable to update edge: %v", err)
+	}
+	change = waitForTopologyChange(t, client)
+	if len(change.ChannelEdgeUpdates) != 1 {
+		t.Fatalf("expected 1 edge update, instead have %v",
+			len(change.ChannelEdgeUpdates))
+	}
+	edgeUpdate := change.ChannelEdgeUpdates[0]
+	if edgeUpdate.ChanID != chanID ||
+		edgeUpdate.Policy.FeeBaseMSat != policy.FeeBaseMSat {
+
+		t.Fatalf("unexpected edge update: %v", edgeUpdate)
+	}
+	if edgeUpdate.PrevPolicy == nil ||
+		edgeUpdate.PrevPolicy.FeeBaseMSat != prevPolicy.FeeBaseMSat {
+
+		t.Fatalf("edge update doesn't carry prior policy: %v",
+			edgeUpdate.PrevPolicy)
+	}
+	node1, node2, err := graph.ChannelNodes(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel nodes: %v", err)
+	}
+	if !bytes.Equal(edgeUpdate.AdvertisingNode.SerializeCompressed(),
+		node1[:]) ||
+		!bytes.Equal(edgeUpdate.ConnectingNode.SerializeCompressed(),
+			node2[:]) {
+
+		t.Fatalf("edge update has incorrect direction")
+	}
+
+	// If every channel not belonging to the source node has gone stale,
+	// then they should all be pruned as zombies and reported as closed.
+	router.cfg.ChannelPruneExpiry = -time.Hour
+	if err := router.pruneZombieChans(); err != nil {
+		t.Fatalf("unable to prune zombies: %v", err)
+	}
+	change = waitForTopologyChange(t, client)
+	var found bool
+	for _, closedChan := range change.ClosedChannels {
+		if closedChan.ChanID != chanID {
+			continue
+		}
+		found = true
+		if closedChan.Capacity != policy.Capacity ||
+			closedChan.ClosedHeight != 0 {
+
+			t.Fatalf("unexpected closed channel summary: %v",
+				closedChan)
+		}
+	}
+	if !found {
+		t.Fatalf("zombie channel wasn't reported as closed")
+	}
+
+	// Once cancelled, the client's notification channel should be closed.
+	client.Cancel()
+	if _, ok := <-client.TopologyChanges; ok {
+		t.Fatalf("notification channel wasn't closed")
+	}
+}
+
+// TestTopologyClientOverflow tests that a client which doesn't consume its
+// topology changes is dropped once too many changes have been queued for it,
+// without blocking the delivery of changes to the remaining clients.
+func TestTopologyClientOverflow(t *testing.T) {
+	graph, cleanUp, _, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	router, err := New(Config{
+		Graph: graph,
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	defer router.Stop()
+
+	slowClient, err := router.SubscribeTopology()
+	if err != nil {
+		t.Fatalf("unable to subscribe to topology: %v", err)
+	}
+	defer slowClient.Cancel()
+
+	change := &TopologyChange{
+		ClosedChannels: []*ClosedChanSummary{{ChanID: 1}},
+	}
+	for i := 0; i <= maxPendingTopologyChanges; i++ {
+		router.notifyTopologyChange(change)
+	}
+
+	// The slow client should now find its notification channel closed.
+	select {
+	case _, ok := <-slowClient.TopologyChanges:
+		if ok {
+			t.Fatalf("slow topology client wasn't dropped")
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("slow topology client wasn't dropped")
+	}
+
+	// A new client should still receive changes as usual.
+	client, err := router.SubscribeTopology()
+	if err != nil {
+		t.Fatalf("unable to subscribe to topology: %v", err)
+	}
+	defer client.Cancel()
+
+	router.notifyTopologyChange(change)
+	if waitForTopologyChange(t, client) != change {
+		t.Fatalf("unexpected topology change delivered")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -34,8 +35,86 @@ import (
 
 var (
 	defaultAccount uint32 = waddrmgr.DefaultAccountNum
+
+	// errNoPayReqAmount is returned when attempting to pay a payment
+	// request which doesn't specify the amount to be paid, without
+	// explicitly specifying the amount to pay.
+	errNoPayReqAmount = errors.New("payment request doesn't specify " +
+		"an amount, so one must be specified explicitly")
+
+	// errPayReqAmountMismatch is returned when the amount explicitly
+	// specified for the payment of a payment request differs from the
+	// amount specified by the payment request itself.
+	errPayReqAmountMismatch = errors.New("specified amount doesn't " +
+		"match the amount of the payment request")
+
+	// errKeySendPaymentHash is returned when a keysend payment is
+	// requested along with a payment hash or payment request, as the
+	// payment hash of a keysend payment is derived from a preimage
+	// generated by the sender.
+	errKeySendPaymentHash = errors.New("keysend payments can't specify " +
+		"a payment hash or payment request")
+
+	// errPayReqExpired is returned when attempting to pay a payment
+	// request whose expiry time has passed.
+	errPayReqExpired = errors.New("payment request has expired")
 )
 
+// newKeySendPayment generates a random preimage for a spontaneous keysend
+// payment, returning the payment hash along with the custom records which
+// deliver the preimage to the destination alongside the passed records.
+func newKeySendPayment(
+	destRecords map[uint64][]byte) ([32]byte, map[uint64][]byte, error) {
+
+	var preimage [32]byte
+	if _, err := rand.Read(preimage[:]); err != nil {
+		return [32]byte{}, nil, err
+	}
+
+	customRecords := make(map[uint64][]byte, len(destRecords)+1)
+	for recordType, value := range destRecords {
+		customRecords[recordType] = value
+	}
+	customRecords[routing.KeySendRecordType] = preimage[:]
+
+	return fastsha256.Sum256(preimage[:]), customRecords, nil
+}
+
+// payReqAmount returns the amount to pay for the passed payment request. If
+// the payment request doesn't specify an amount, then the explicitly specified
+// amount is paid, which must be positive. Otherwise, any explicitly specified
+// amount must match that of the payment request.
+func payReqAmount(payReq *zpay32.Invoice, amt int64) (btcutil.Amount, error) {
+	switch {
+	case payReq.Amount == nil && amt <= 0:
+		return 0, errNoPayReqAmount
+
+	case payReq.Amount == nil:
+		return btcutil.Amount(amt), nil
+
+	case amt != 0 && btcutil.Amount(amt) != *payReq.Amount:
+		return 0, errPayReqAmountMismatch
+
+	default:
+		return *payReq.Amount, nil
+	}
+}
+
+// checkPayReq ensures the passed payment request can still be paid: it must
+// not have expired, and the final CLTV delta it requires must fit within a
+// route.
+func checkPayReq(payReq *zpay32.Invoice) error {
+	if time.Now().After(payReq.ExpiryTime()) {
+		return errPayReqExpired
+	}
+	if payReq.MinFinalCLTVExpiryDelta() > math.MaxUint16 {
+		return fmt.Errorf("final cltv delta of %v is too large",
+			payReq.MinFinalCLTVExpiryDelta())
+	}
+
+	return nil
+}
+
 // rpcServer is a gRPC, RPC front end to the lnd daemon.
 // TODO(roasbeef): pagination support for the list-style calls
 type rpcServer struct {
@@ -278,7 +357,8 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs,
+		in.Private)
 
 	var outpoint wire.OutPoint
 out:
@@ -366,7 +446,8 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 	}
 
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs,
+		in.Private)
 
 	select {
 	// If an error occurs them immediately return the error to the client.
@@ -636,6 +717,7 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 		BlockHash:          bestHash.String(),
 		SyncedToChain:      isSynced,
 		Testnet:            activeNetParams.Params == &chaincfg.TestNet3Params,
+		HtlcBatchStats:     r.server.htlcBatchStats.rpcStats(),
 	}, nil
 }
 
@@ -690,6 +772,64 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 	return resp, nil
 }
 
+// PeerLimits returns the HTLC rate limits and forwarding caps currently
+// enforced by the daemon, along with the current limiter state of each peer
+// that has sent us HTLCs. If a public key is specified within the request,
+// then only the state of that particular peer is returned.
+func (r *rpcServer) PeerLimits(ctx context.Context,
+	in *lnrpc.PeerLimitsRequest) (*lnrpc.PeerLimitsResponse, error) {
+
+	rpcsLog.Tracef("[peerlimits] request for peer=%v", in.PubKey)
+
+	var targetPub []byte
+	if in.PubKey != "" {
+		pubKey, err := hex.DecodeString(in.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		targetPub = pubKey
+	}
+
+	limiter := r.server.htlcLimiter
+	resp := &lnrpc.PeerLimitsResponse{
+		PeerRate:            limiter.cfg.PeerRate,
+		PeerBurst:           uint32(limiter.cfg.PeerBurst),
+		ChanRate:            limiter.cfg.ChanRate,
+		ChanBurst:           uint32(limiter.cfg.ChanBurst),
+		MaxPendingForwards:  uint32(limiter.cfg.MaxPendingForwards),
+		ReputationEnabled:   limiter.cfg.TrackReputation,
+		HoldThresholdSecs:   int64(limiter.cfg.HoldThreshold.Seconds()),
+		ReputationThreshold: limiter.cfg.ReputationThreshold,
+	}
+
+	for _, peerState := range limiter.snapshot() {
+		if targetPub != nil && !bytes.Equal(targetPub, peerState.pubKey[:]) {
+			continue
+		}
+
+		peerLimit := &lnrpc.PeerLimit{
+			PubKey:          hex.EncodeToString(peerState.pubKey[:]),
+			AvailableTokens: peerState.tokens,
+			ReputationScore: peerState.reputationScore,
+			Restricted:      peerState.restricted,
+			NumRejected:     peerState.numRejected,
+		}
+		for _, chanState := range peerState.channels {
+			peerLimit.Channels = append(peerLimit.Channels,
+				&lnrpc.ChannelLimits{
+					ChannelPoint:    chanState.chanPoint.String(),
+					AvailableTokens: chanState.tokens,
+					PendingForwards: chanState.pendingForwards,
+				},
+			)
+		}
+
+		resp.Peers = append(resp.Peers, peerLimit)
+	}
+
+	return resp, nil
+}
+
 // WalletBalance returns the sum of all confirmed unspent outputs under control
 // by the wallet. This method can be modified by having the request specify
 // only witness outputs should be factored into the final output sum.
@@ -822,16 +962,30 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 }
 
 // savePayment saves a successfully completed payment to the database for
-// historical record keeping.
-func (r *rpcServer) savePayment(route *routing.Route, amount btcutil.Amount,
+// historical record keeping. If the payment was split into several shards,
+// then the path of the first shard is recorded, along with the total fees
+// paid across all shards and the largest time lock of any shard.
+func (r *rpcServer) savePayment(routes []*routing.Route, amount btcutil.Amount,
 	rHash []byte) error {
 
+	route := routes[0]
 	paymentPath := make([][33]byte, len(route.Hops))
 	for i, hop := range route.Hops {
 		hopPub := hop.Channel.Node.PubKey.SerializeCompressed()
 		copy(paymentPath[i][:], hopPub)
 	}
 
+	var (
+		totalFees     btcutil.Amount
+		totalTimeLock uint32
+	)
+	for _, route := range routes {
+		totalFees += route.TotalFees
+		if route.TotalTimeLock > totalTimeLock {
+			totalTimeLock = route.TotalTimeLock
+		}
+	}
+
 	payment := &channeldb.OutgoingPayment{
 		Invoice: channeldb.Invoice{
 			Terms: channeldb.ContractTerm{
@@ -840,8 +994,8 @@ func (r *rpcServer) savePayment(route *routing.Route, amount btcutil.Amount,
 			CreationDate: time.Now(),
 		},
 		Path:           paymentPath,
-		Fee:            route.TotalFees,
-		TimeLockLength: route.TotalTimeLock,
+		Fee:            totalFees,
+		TimeLockLength: totalTimeLock,
 	}
 	copy(payment.PaymentHash[:], rHash)
 
@@ -885,17 +1039,37 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 				// attempt to decode it, populating the
 				// nextPayment accordingly.
 				if nextPayment.PaymentRequest != "" {
-					payReq, err := zpay32.Decode(nextPayment.PaymentRequest)
+					payReq, err := zpay32.DecodePaymentRequest(
+						nextPayment.PaymentRequest,
+						activeNetParams.Params,
+					)
+					if err != nil {
+						errChan <- err
+						return
+					}
+					amt, err := payReqAmount(
+						payReq, nextPayment.Amt,
+					)
 					if err != nil {
 						errChan <- err
 						return
 					}
+					if err := checkPayReq(payReq); err != nil {
+						errChan <- err
+						return
+					}
 
 					// TODO(roasbeef): eliminate necessary
 					// encode/decode
 					nextPayment.Dest = payReq.Destination.SerializeCompressed()
-					nextPayment.Amt = int64(payReq.Amount)
+					nextPayment.Amt = int64(amt)
 					nextPayment.PaymentHash = payReq.PaymentHash[:]
+					nextPayment.RouteHints = marshalRouteHints(
+						payReq.RouteHints,
+					)
+					nextPayment.FinalCltvDelta = uint32(
+						payReq.MinFinalCLTVExpiryDelta(),
+					)
 				}
 
 				payChan <- nextPayment
@@ -918,6 +1092,24 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 
 			}
 
+			// Along with the destination, we'll also parse any
+			// restrictions the route of the payment must satisfy.
+			restrictions, err := unmarshalRouteRestrictions(
+				nextPayment.FeeLimit, nextPayment.CltvLimit,
+				nextPayment.OutgoingChanId,
+				nextPayment.LastHopPubkey,
+				nextPayment.IgnoredNodes,
+				nextPayment.IgnoredEdges,
+				nextPayment.FinalCltvDelta,
+			)
+			if err != nil {
+				return err
+			}
+			routeHints, err := unmarshalRouteHints(nextPayment.RouteHints)
+			if err != nil {
+				return err
+			}
+
 			// If we're in debug HTLC mode, then all outgoing HTLCs
 			// will pay to the same debug rHash. Otherwise, we pay
 			// to the rHash specified within the RPC request.
@@ -928,6 +1120,26 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 				copy(rHash[:], nextPayment.PaymentHash)
 			}
 
+			// A keysend payment instead pays to the hash of a
+			// preimage we generate ourselves, which is delivered
+			// to the destination within the payment's custom
+			// records.
+			customRecords := nextPayment.DestCustomRecords
+			if nextPayment.Keysend {
+				if nextPayment.PaymentRequest != "" ||
+					len(nextPayment.PaymentHash) != 0 {
+
+					return errKeySendPaymentHash
+				}
+
+				rHash, customRecords, err = newKeySendPayment(
+					customRecords,
+				)
+				if err != nil {
+					return err
+				}
+			}
+
 			// We launch a new goroutine to execute the current
 			// payment so we can continue to serve requests while
 			// this payment is being dispatched.
@@ -944,8 +1156,18 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 					Target:      destNode,
 					Amount:      amt,
 					PaymentHash: rHash,
+					Timeout: time.Duration(
+						nextPayment.TimeoutSeconds,
+					) * time.Second,
+					RouteRestrictions: *restrictions,
+					RouteHints:        routingHopHints(routeHints),
+					MaxShards:         nextPayment.MaxShards,
+					PaymentRequest: []byte(
+						nextPayment.PaymentRequest,
+					),
+					CustomRecords: customRecords,
 				}
-				route, err := r.server.chanRouter.SendPayment(payment)
+				routes, err := r.server.chanRouter.SendPayment(payment)
 				if err != nil {
 					errChan <- err
 					return
@@ -953,15 +1175,14 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 
 				// Save the completed payment to the database
 				// for record keeping purposes.
-				if err := r.savePayment(route, amt, rHash[:]); err != nil {
+				if err := r.savePayment(routes, amt, rHash[:]); err != nil {
 					errChan <- err
 					return
 				}
 
-				// TODO(roasbeef): tack on payment hash also?
-				err = paymentStream.Send(&lnrpc.SendResponse{
-					PaymentRoute: marshalRoute(route),
-				})
+				err = paymentStream.Send(
+					marshalSendResponse(routes, rHash),
+				)
 				if err != nil {
 					errChan <- err
 					return
@@ -981,21 +1202,33 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	nextPayment *lnrpc.SendRequest) (*lnrpc.SendResponse, error) {
 
 	var (
-		destPub *btcec.PublicKey
-		amt     btcutil.Amount
-		rHash   [32]byte
+		destPub        *btcec.PublicKey
+		amt            btcutil.Amount
+		rHash          [32]byte
+		routeHints     [][]zpay32.HopHint
+		finalCLTVDelta = nextPayment.FinalCltvDelta
 	)
 
 	// If the proto request has an encoded payment request, then we we'll
 	// use that solely to dipatch the payment.
 	if nextPayment.PaymentRequest != "" {
-		payReq, err := zpay32.Decode(nextPayment.PaymentRequest)
+		payReq, err := zpay32.DecodePaymentRequest(
+			nextPayment.PaymentRequest, activeNetParams.Params,
+		)
+		if err != nil {
+			return nil, err
+		}
+		amt, err = payReqAmount(payReq, nextPayment.Amt)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkPayReq(payReq); err != nil {
+			return nil, err
+		}
 		destPub = payReq.Destination
-		amt = payReq.Amount
-		rHash = payReq.PaymentHash
+		rHash = *payReq.PaymentHash
+		routeHints = payReq.RouteHints
+		finalCLTVDelta = uint32(payReq.MinFinalCLTVExpiryDelta())
 
 		// Otherwise, the payment conditions have been manually
 		// specified in the proto.
@@ -1024,15 +1257,53 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 		}
 
 		amt = btcutil.Amount(nextPayment.Amt)
+
+		routeHints, err = unmarshalRouteHints(nextPayment.RouteHints)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A keysend payment pays to the hash of a preimage we generate
+	// ourselves, which is delivered to the destination within the
+	// payment's custom records.
+	customRecords := nextPayment.DestCustomRecords
+	if nextPayment.Keysend {
+		if nextPayment.PaymentRequest != "" ||
+			nextPayment.PaymentHashString != "" {
+
+			return nil, errKeySendPaymentHash
+		}
+
+		var err error
+		rHash, customRecords, err = newKeySendPayment(customRecords)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse any restrictions the route of the payment must satisfy.
+	restrictions, err := unmarshalRouteRestrictions(nextPayment.FeeLimit,
+		nextPayment.CltvLimit, nextPayment.OutgoingChanId,
+		nextPayment.LastHopPubkey, nextPayment.IgnoredNodes,
+		nextPayment.IgnoredEdges, finalCLTVDelta)
+	if err != nil {
+		return nil, err
 	}
 
 	// Finally, send a payment request to the channel router. If the
 	// payment succeeds, then the returned route will be that was used
 	// successfully within the payment.
-	route, err := r.server.chanRouter.SendPayment(&routing.LightningPayment{
-		Target:      destPub,
-		Amount:      amt,
-		PaymentHash: rHash,
+	routes, err := r.server.chanRouter.SendPayment(&routing.LightningPayment{
+		Target:            destPub,
+		Amount:            amt,
+		PaymentHash:       rHash,
+		Timeout:           time.Duration(nextPayment.TimeoutSeconds) * time.Second,
+		RouteRestrictions: *restrictions,
+		RouteHints:        routingHopHints(routeHints),
+		MaxShards:         nextPayment.MaxShards,
+		PaymentRequest:    []byte(nextPayment.PaymentRequest),
+		CustomRecords:     customRecords,
 	})
 	if err != nil {
 		return nil, err
@@ -1040,11 +1311,198 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 
 	// With the payment completed successfully, we now ave the details of
 	// the completed payment to the databse for historical record keeping.
-	if err := r.savePayment(route, amt, rHash[:]); err != nil {
+	if err := r.savePayment(routes, amt, rHash[:]); err != nil {
+		return nil, err
+	}
+
+	return marshalSendResponse(routes, rHash), nil
+}
+
+// marshalSendResponse converts the routes traversed by the shards of a
+// successful payment to the passed payment hash into a SendResponse. The
+// route of the first shard is also returned as the payment route for the
+// benefit of older clients.
+func marshalSendResponse(routes []*routing.Route,
+	rHash [32]byte) *lnrpc.SendResponse {
+
+	resp := &lnrpc.SendResponse{
+		PaymentRoute:  marshalRoute(routes[0]),
+		PaymentRoutes: make([]*lnrpc.Route, len(routes)),
+		PaymentHash:   rHash[:],
+	}
+	for i, route := range routes {
+		resp.PaymentRoutes[i] = marshalRoute(route)
+	}
+
+	return resp
+}
+
+// SendToRoute attempts to send a payment over an explicitly specified route,
+// rather than allowing the router to select one. If the payment succeeds, the
+// preimage revealed by the destination is returned. If the payment is
+// rejected by a node along the route, then the decoded failure is returned
+// within the response instead.
+func (r *rpcServer) SendToRoute(ctx context.Context,
+	req *lnrpc.SendToRouteRequest) (*lnrpc.SendToRouteResponse, error) {
+
+	if req.Route == nil {
+		return nil, fmt.Errorf("a route must be specified")
+	}
+
+	// The payment hash may be specified as either raw bytes or a hex
+	// encoded string.
+	paymentHash := req.PaymentHash
+	if len(paymentHash) == 0 {
+		var err error
+		paymentHash, err = hex.DecodeString(req.PaymentHashString)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(paymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly 32 "+
+			"bytes, is instead %v", len(paymentHash))
+	}
+	var rHash [32]byte
+	copy(rHash[:], paymentHash)
+
+	route, err := unmarshalRoute(req.Route)
+	if err != nil {
 		return nil, err
 	}
 
-	return &lnrpc.SendResponse{}, nil
+	// If the payment failed within the network, then we'll return the
+	// decoded failure to the caller. Any other error indicates we were
+	// unable to dispatch the payment at all.
+	preimage, err := r.server.chanRouter.SendToRoute(
+		route, rHash, req.DestCustomRecords,
+	)
+	if reason, ok := err.(lnwire.CancelReason); ok {
+		return &lnrpc.SendToRouteResponse{
+			PaymentError: reason.String(),
+			FailureCode:  uint32(reason),
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// With the payment completed successfully, we'll save its details to
+	// the database for historical record keeping.
+	amt := route.Hops[len(route.Hops)-1].AmtToForward
+	err = r.savePayment([]*routing.Route{route}, amt, rHash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendToRouteResponse{
+		PaymentPreimage: preimage[:],
+	}, nil
+}
+
+// TrackPayment returns a stream of the state of the outgoing payment with the
+// target payment hash. The current state of the payment is sent immediately,
+// followed by each of its state transitions. The stream is closed once the
+// payment has either succeeded with none of its HTLCs remaining in flight, or
+// has failed.
+func (r *rpcServer) TrackPayment(req *lnrpc.TrackPaymentRequest,
+	updateStream lnrpc.Lightning_TrackPaymentServer) error {
+
+	// The payment hash may be specified as either raw bytes or a hex
+	// encoded string.
+	paymentHash := req.PaymentHash
+	if len(paymentHash) == 0 {
+		var err error
+		paymentHash, err = hex.DecodeString(req.PaymentHashString)
+		if err != nil {
+			return err
+		}
+	}
+	if len(paymentHash) != 32 {
+		return fmt.Errorf("payment hash must be exactly 32 bytes, is "+
+			"instead %v", len(paymentHash))
+	}
+	var rHash [32]byte
+	copy(rHash[:], paymentHash)
+
+	client, err := r.server.chanRouter.TrackPayment(rHash)
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		// Each update carries the full state of the payment, which
+		// we'll send to the client. Once the payment has reached its
+		// final state, the channel is closed, ending the stream.
+		case payment, ok := <-client.Updates:
+			if !ok {
+				return nil
+			}
+
+			status := marshalPaymentStatus(payment)
+			if err := updateStream.Send(status); err != nil {
+				return err
+			}
+
+		// If the client has disconnected, then we'll exit so the
+		// notification client can be cleaned up.
+		case <-updateStream.Context().Done():
+			return nil
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshalPaymentStatus converts the state of a payment recorded by the control
+// tower into its RPC counterpart.
+func marshalPaymentStatus(payment *channeldb.TrackedPayment) *lnrpc.PaymentStatus {
+	status := &lnrpc.PaymentStatus{
+		State:          lnrpc.PaymentStatus_PaymentState(payment.Status),
+		PaymentHash:    payment.Info.PaymentHash[:],
+		Value:          int64(payment.Info.Value),
+		CreationDate:   payment.Info.CreationDate.Unix(),
+		PaymentRequest: string(payment.Info.PaymentRequest),
+		FailureReason:  payment.FailureReason,
+		Htlcs:          make([]*lnrpc.HTLCAttempt, len(payment.Attempts)),
+	}
+	if payment.Status == channeldb.StatusSucceeded {
+		status.Preimage = payment.Preimage[:]
+	}
+
+	for i, attempt := range payment.Attempts {
+		route := &lnrpc.Route{
+			TotalTimeLock: attempt.TotalTimeLock,
+			TotalFees:     int64(attempt.TotalFees),
+			TotalAmt:      int64(attempt.TotalAmount),
+			Hops:          make([]*lnrpc.Hop, len(attempt.Hops)),
+		}
+		for j, hop := range attempt.Hops {
+			route.Hops[j] = &lnrpc.Hop{
+				ChanId:       hop.ChannelID,
+				AmtToForward: int64(hop.AmtToForward),
+				Fee:          int64(hop.Fee),
+				PubKey:       hex.EncodeToString(hop.PubKey[:]),
+			}
+		}
+
+		htlc := &lnrpc.HTLCAttempt{
+			AttemptId:   attempt.AttemptID,
+			Status:      lnrpc.HTLCAttempt_HTLCStatus(attempt.State),
+			Route:       route,
+			AttemptTime: attempt.AttemptTime.Unix(),
+			Failure:     attempt.Failure,
+		}
+		if !attempt.ResolveTime.IsZero() {
+			htlc.ResolveTime = attempt.ResolveTime.Unix()
+		}
+
+		status.Htlcs[i] = htlc
+	}
+
+	return status
 }
 
 // AddInvoice attempts to add a new invoice to the invoice database. Any
@@ -1075,6 +1533,44 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		copy(paymentPreimage[:], invoice.RPreimage[:])
 	}
 
+	// Next, generate the payment hash itself from the preimage. This will
+	// be used by clients to query for the state of a particular invoice.
+	rHash := fastsha256.Sum256(paymentPreimage[:])
+
+	return r.addInvoice(invoice, &paymentPreimage, rHash)
+}
+
+// AddHoldInvoice attempts to add a new hold invoice to the invoice database.
+// Unlike regular invoices, only the payment hash of a hold invoice is
+// specified, and payments to it are held until the invoice is either settled
+// with the payment preimage using SettleInvoice, or canceled.
+func (r *rpcServer) AddHoldInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	// As the preimage of a hold invoice is only revealed once it's
+	// settled, it MUST NOT be specified, while the payment hash MUST be
+	// exactly 32 bytes.
+	if len(invoice.RPreimage) != 0 {
+		return nil, fmt.Errorf("payment preimage must not be " +
+			"specified for hold invoices")
+	}
+	if len(invoice.RHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly "+
+			"32 bytes, is instead %v", len(invoice.RHash))
+	}
+
+	var rHash [32]byte
+	copy(rHash[:], invoice.RHash)
+
+	return r.addInvoice(invoice, nil, rHash)
+}
+
+// addInvoice adds a new invoice with the passed payment hash to the invoice
+// database. If the preimage is nil, then the invoice is added as a hold
+// invoice.
+func (r *rpcServer) addInvoice(invoice *lnrpc.Invoice, preimage *[32]byte,
+	rHash [32]byte) (*lnrpc.AddInvoiceResponse, error) {
+
 	// The size of the memo and receipt attached must not exceed the
 	// maximum values for either of the fields.
 	if len(invoice.Memo) > channeldb.MaxMemoSize {
@@ -1086,20 +1582,111 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 			"(maxsize=%v)", len(invoice.Receipt), channeldb.MaxReceiptSize)
 	}
 
-	// Finally, the value of an invoice MUST NOT be zero.
-	if invoice.Value == 0 {
-		return nil, fmt.Errorf("zero value invoices are disallowed")
+	// The value of an invoice MUST NOT be negative. A value of zero leaves
+	// the amount to be paid up to the payer.
+	if invoice.Value < 0 {
+		return nil, fmt.Errorf("negative value invoices are disallowed")
+	}
+
+	// Finally, a description hash, if specified, MUST be exactly 32
+	// bytes.
+	if len(invoice.DescriptionHash) > 0 && len(invoice.DescriptionHash) != 32 {
+		return nil, fmt.Errorf("description hash must be exactly 32 "+
+			"bytes, is instead %v", len(invoice.DescriptionHash))
+	}
+
+	// Along with any route hints specified by the caller, we'll include
+	// hints for our private channels if requested, allowing the payer to
+	// reach us through them.
+	routeHints, err := unmarshalRouteHints(invoice.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Private {
+		privateHints, err := r.privateChannelHints(
+			btcutil.Amount(invoice.Value),
+		)
+		if err != nil {
+			return nil, err
+		}
+		routeHints = append(routeHints, privateHints...)
+	}
+
+	creationDate := time.Now()
+
+	// We'll now create the payment request which allows the caller to
+	// compactly send the invoice to the payer. The memo is included as the
+	// description of the payment, unless the caller specified a
+	// description hash, or the memo is too long to fit within the payment
+	// request, in which case its hash is included instead.
+	var options []func(*zpay32.Invoice)
+	if invoice.Value > 0 {
+		options = append(options, zpay32.Amount(
+			btcutil.Amount(invoice.Value),
+		))
+	}
+	switch {
+	case len(invoice.DescriptionHash) > 0:
+		var descHash [32]byte
+		copy(descHash[:], invoice.DescriptionHash)
+		options = append(options, zpay32.DescriptionHash(descHash))
+
+	case len(invoice.Memo) > zpay32.MaxDescriptionSize:
+		descHash := fastsha256.Sum256([]byte(invoice.Memo))
+		options = append(options, zpay32.DescriptionHash(descHash))
+
+	default:
+		options = append(options, zpay32.Description(invoice.Memo))
+	}
+	if invoice.Expiry > 0 {
+		expiry := time.Duration(invoice.Expiry) * time.Second
+		options = append(options, zpay32.Expiry(expiry))
+	}
+	if invoice.CltvExpiry > 0 {
+		options = append(options, zpay32.CLTVExpiry(invoice.CltvExpiry))
+	}
+	if invoice.FallbackAddr != "" {
+		addr, err := btcutil.DecodeAddress(
+			invoice.FallbackAddr, activeNetParams.Params,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fallback address: %v",
+				err)
+		}
+		options = append(options, zpay32.FallbackAddr(addr))
+	}
+	for _, routeHint := range routeHints {
+		options = append(options, zpay32.RouteHint(routeHint))
+	}
+
+	payReq, err := zpay32.NewInvoice(
+		activeNetParams.Params, rHash, creationDate, options...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payReqString, err := payReq.Encode(zpay32.MessageSigner{
+		SignCompact: r.server.nodeSigner.SignDigestCompact,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	i := &channeldb.Invoice{
-		CreationDate: time.Now(),
-		Memo:         []byte(invoice.Memo),
-		Receipt:      invoice.Receipt,
+		CreationDate:   creationDate,
+		ExpiryDate:     payReq.ExpiryTime(),
+		Memo:           []byte(invoice.Memo),
+		Receipt:        invoice.Receipt,
+		PaymentRequest: []byte(payReqString),
 		Terms: channeldb.ContractTerm{
 			Value: btcutil.Amount(invoice.Value),
 		},
 	}
-	copy(i.Terms.PaymentPreimage[:], paymentPreimage[:])
+	if preimage != nil {
+		i.Terms.PaymentPreimage = *preimage
+	} else {
+		i.Terms.HoldHash = &rHash
+	}
 
 	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
 		newLogClosure(func() string {
@@ -1111,24 +1698,84 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		return nil, err
 	}
 
-	// Next, generate the payment hash itself from the preimage. This will
-	// be used by clients to query for the state of a particular invoice.
-	rHash := fastsha256.Sum256(paymentPreimage[:])
-
-	// Finally we also create an encoded payment request which allows the
-	// caller to comactly send the invoice to the payer.
-	payReqString := zpay32.Encode(&zpay32.PaymentRequest{
-		Destination: r.server.identityPriv.PubKey(),
-		PaymentHash: rHash,
-		Amount:      btcutil.Amount(invoice.Value),
-	})
-
 	return &lnrpc.AddInvoiceResponse{
 		RHash:          rHash[:],
 		PaymentRequest: payReqString,
 	}, nil
 }
 
+// privateChannelHints returns a route hint for each of our private channels
+// that's able to carry a payment of amt. Each hint consists of a single hop
+// from the remote peer of the channel to ourselves. If the routing policy of
+// the remote peer isn't known, then we assume they use the same default policy
+// that we advertise for our own channels.
+func (r *rpcServer) privateChannelHints(amt btcutil.Amount) ([][]zpay32.HopHint, error) {
+	graph := r.server.chanDB.ChannelGraph()
+	selfNode, err := graph.SourceNode()
+	if err != nil {
+		return nil, err
+	}
+
+	// We'll first gather all of our channels, so we don't nest any
+	// database transactions when inspecting them below.
+	var chanEdges []*channeldb.ChannelEdge
+	err = selfNode.ForEachChannel(nil, func(edge *channeldb.ChannelEdge) error {
+		chanEdges = append(chanEdges, edge)
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNotFound {
+		return nil, err
+	}
+
+	var routeHints [][]zpay32.HopHint
+	for _, edge := range chanEdges {
+		if len(routeHints) == zpay32.MaxRouteHints {
+			break
+		}
+
+		isPrivate, err := graph.IsChannelPrivate(edge.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if !isPrivate || edge.Capacity < amt {
+			continue
+		}
+
+		hint := zpay32.HopHint{
+			NodeID:                    edge.Node.PubKey,
+			ChannelID:                 edge.ChannelID,
+			FeeBaseMSat:               defaultFeeBaseMSat,
+			FeeProportionalMillionths: defaultFeeRate,
+			CLTVExpiryDelta:           defaultTimeLockDelta,
+		}
+
+		// If we know of the remote peer's policy for the channel,
+		// which is the edge leading to ourselves, then we'll use it
+		// within the hint.
+		e1, e2, err := graph.FetchChannelEdgesByID(edge.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		for _, remotePolicy := range []*channeldb.ChannelEdge{e1, e2} {
+			if remotePolicy == nil ||
+				!remotePolicy.Node.PubKey.IsEqual(selfNode.PubKey) {
+
+				continue
+			}
+
+			hint.FeeBaseMSat = uint32(remotePolicy.FeeBaseMSat)
+			hint.FeeProportionalMillionths = uint32(
+				remotePolicy.FeeProportionalMillionths,
+			)
+			hint.CLTVExpiryDelta = remotePolicy.Expiry
+		}
+
+		routeHints = append(routeHints, []zpay32.HopHint{hint})
+	}
+
+	return routeHints, nil
+}
+
 // LookupInvoice attemps to look up an invoice according to its payment hash.
 // The passed payment hash *must* be exactly 32 bytes, if not an error is
 // returned.
@@ -1171,69 +1818,276 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 			return spew.Sdump(invoice)
 		}))
 
-	return &lnrpc.Invoice{
-		Memo:      string(invoice.Memo[:]),
-		Receipt:   invoice.Receipt[:],
-		RPreimage: invoice.Terms.PaymentPreimage[:],
-		Value:     int64(invoice.Terms.Value),
-		Settled:   invoice.Terms.Settled,
-	}, nil
+	return r.createRPCInvoice(invoice)
 }
 
-// ListInvoices returns a list of all the invoices currently stored within the
-// database. Any active debug invoices are ignored.
-func (r *rpcServer) ListInvoices(ctx context.Context,
-	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
+// createRPCInvoice converts the passed invoice from the database into its
+// RPC representation.
+func (r *rpcServer) createRPCInvoice(invoice *channeldb.Invoice) (*lnrpc.Invoice,
+	error) {
 
-	dbInvoices, err := r.server.chanDB.FetchAllInvoices(req.PendingOnly)
+	payReqString, err := r.invoicePaymentRequest(invoice)
 	if err != nil {
 		return nil, err
 	}
 
-	invoices := make([]*lnrpc.Invoice, len(dbInvoices))
-	for i, dbInvoice := range dbInvoices {
-		invoiceAmount := dbInvoice.Terms.Value
-		paymentPreimge := dbInvoice.Terms.PaymentPreimage[:]
-		invoice := &lnrpc.Invoice{
-			Memo:         string(dbInvoice.Memo[:]),
-			Receipt:      dbInvoice.Receipt[:],
-			RPreimage:    paymentPreimge,
-			Value:        int64(invoiceAmount),
-			Settled:      dbInvoice.Terms.Settled,
-			CreationDate: dbInvoice.CreationDate.Unix(),
-			PaymentRequest: zpay32.Encode(&zpay32.PaymentRequest{
-				Destination: r.server.identityPriv.PubKey(),
-				PaymentHash: fastsha256.Sum256(paymentPreimge),
-				Amount:      invoiceAmount,
-			}),
-		}
+	var state lnrpc.Invoice_InvoiceState
+	switch invoice.Terms.State {
+	case channeldb.ContractOpen:
+		state = lnrpc.Invoice_OPEN
+	case channeldb.ContractSettled:
+		state = lnrpc.Invoice_SETTLED
+	case channeldb.ContractCanceled:
+		state = lnrpc.Invoice_CANCELED
+	case channeldb.ContractExpired:
+		state = lnrpc.Invoice_EXPIRED
+	case channeldb.ContractAccepted:
+		state = lnrpc.Invoice_ACCEPTED
+	default:
+		return nil, fmt.Errorf("unknown invoice state: %v",
+			invoice.Terms.State)
+	}
+
+	rHash := invoice.PaymentHash()
+	rpcInvoice := &lnrpc.Invoice{
+		Memo:           string(invoice.Memo[:]),
+		Receipt:        invoice.Receipt[:],
+		RHash:          rHash[:],
+		Value:          int64(invoice.Terms.Value),
+		Settled:        invoice.Terms.State == channeldb.ContractSettled,
+		CreationDate:   invoice.CreationDate.Unix(),
+		PaymentRequest: payReqString,
+		State:          state,
+		AddIndex:       invoice.AddIndex,
+		SettleIndex:    invoice.SettleIndex,
+		AmtPaid:        int64(invoice.AmtPaid),
+		CustomRecords:  invoice.CustomRecords,
+	}
+
+	// The preimage of a hold invoice is only known once it has been
+	// settled.
+	if !invoice.IsHold() || invoice.Terms.State == channeldb.ContractSettled {
+		rpcInvoice.RPreimage = invoice.Terms.PaymentPreimage[:]
+	}
+
+	if !invoice.ExpiryDate.IsZero() {
+		expiry := invoice.ExpiryDate.Sub(invoice.CreationDate)
+		rpcInvoice.Expiry = int64(expiry / time.Second)
+	}
 
-		invoices[i] = invoice
+	return rpcInvoice, nil
+}
+
+// invoicePaymentRequest returns the encoded payment request of the passed
+// invoice. Invoices created before payment requests were stored along side
+// them lack one, so for those we'll fall back to re-creating their legacy
+// payment request.
+func (r *rpcServer) invoicePaymentRequest(invoice *channeldb.Invoice) (string,
+	error) {
+
+	if len(invoice.PaymentRequest) != 0 {
+		return string(invoice.PaymentRequest), nil
+	}
+
+	return zpay32.Encode(&zpay32.PaymentRequest{
+		Destination: r.server.identityPriv.PubKey(),
+		PaymentHash: invoice.PaymentHash(),
+		Amount:      invoice.Terms.Value,
+	})
+}
+
+// ListInvoices returns a list of all the invoices currently stored within the
+// database. Any active debug invoices are ignored.
+func (r *rpcServer) ListInvoices(ctx context.Context,
+	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
+
+	q := channeldb.InvoiceQuery{
+		IndexOffset:    req.IndexOffset,
+		NumMaxInvoices: req.NumMaxInvoices,
+		Reversed:       req.Reversed,
+		PendingOnly:    req.PendingOnly,
+	}
+	for _, state := range req.States {
+		dbState, err := unmarshalInvoiceState(state)
+		if err != nil {
+			return nil, err
+		}
+		q.States = append(q.States, dbState)
+	}
+	if req.CreationDateStart != 0 {
+		q.CreationDateStart = time.Unix(req.CreationDateStart, 0)
+	}
+	if req.CreationDateEnd != 0 {
+		q.CreationDateEnd = time.Unix(req.CreationDateEnd, 0)
+	}
+
+	invoiceSlice, err := r.server.chanDB.QueryInvoices(q)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*lnrpc.Invoice, 0, len(invoiceSlice.Invoices))
+	for _, dbInvoice := range invoiceSlice.Invoices {
+		invoice, err := r.createRPCInvoice(dbInvoice)
+		if err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, invoice)
 	}
 
 	return &lnrpc.ListInvoiceResponse{
-		Invoices: invoices,
+		Invoices:         invoices,
+		FirstIndexOffset: invoiceSlice.FirstIndexOffset,
+		LastIndexOffset:  invoiceSlice.LastIndexOffset,
 	}, nil
 }
 
+// unmarshalInvoiceState converts the passed RPC invoice state into the
+// corresponding contract state of invoices within the database.
+func unmarshalInvoiceState(
+	state lnrpc.Invoice_InvoiceState) (channeldb.ContractState, error) {
+
+	switch state {
+	case lnrpc.Invoice_OPEN:
+		return channeldb.ContractOpen, nil
+	case lnrpc.Invoice_SETTLED:
+		return channeldb.ContractSettled, nil
+	case lnrpc.Invoice_CANCELED:
+		return channeldb.ContractCanceled, nil
+	case lnrpc.Invoice_EXPIRED:
+		return channeldb.ContractExpired, nil
+	case lnrpc.Invoice_ACCEPTED:
+		return channeldb.ContractAccepted, nil
+	default:
+		return 0, fmt.Errorf("unknown invoice state: %v", state)
+	}
+}
+
+// CancelInvoice cancels the invoice identified by the passed payment hash,
+// after which it'll no longer accept payment. Only open invoices can be
+// canceled.
+func (r *rpcServer) CancelInvoice(ctx context.Context,
+	req *lnrpc.PaymentHash) (*lnrpc.CancelInvoiceResponse, error) {
+
+	var (
+		rHash []byte
+		err   error
+	)
+
+	// If the RHash as a raw string was provided, then decode that and use
+	// that directly. Otherwise, we use the raw bytes provided.
+	if req.RHashStr != "" {
+		rHash, err = hex.DecodeString(req.RHashStr)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		rHash = req.RHash
+	}
+
+	if len(rHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly "+
+			"32 bytes, is instead %v", len(rHash))
+	}
+
+	var payHash chainhash.Hash
+	copy(payHash[:], rHash)
+
+	rpcsLog.Debugf("[cancelinvoice] canceling invoice %x", payHash[:])
+
+	if err := r.server.invoices.CancelInvoice(payHash); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.CancelInvoiceResponse{}, nil
+}
+
+// SettleInvoice settles an accepted hold invoice using the passed payment
+// preimage, settling the HTLCs held for it.
+func (r *rpcServer) SettleInvoice(ctx context.Context,
+	req *lnrpc.SettleInvoiceRequest) (*lnrpc.SettleInvoiceResponse, error) {
+
+	if len(req.Preimage) != 32 {
+		return nil, fmt.Errorf("payment preimage must be exactly "+
+			"32 bytes, is instead %v", len(req.Preimage))
+	}
+
+	var preimage [32]byte
+	copy(preimage[:], req.Preimage)
+
+	rpcsLog.Debugf("[settleinvoice] settling invoice %x",
+		fastsha256.Sum256(preimage[:]))
+
+	if err := r.server.invoices.SettleHoldInvoice(preimage); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SettleInvoiceResponse{}, nil
+}
+
 // SubscribeInvoices returns a uni-directional stream (sever -> client) for
-// notifying the client of newly added/settled invoices.
+// notifying the client of newly added and settled invoices. Hold invoices
+// whose payment has been accepted, and canceled or expired invoices, are only
+// sent if the request asks for them. If the add or settle index of the
+// request is non-zero, then all invoices added or settled after that index
+// are sent before any live notifications.
 func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
 	updateStream lnrpc.Lightning_SubscribeInvoicesServer) error {
 
-	invoiceClient := r.server.invoices.SubscribeNotifications()
+	invoiceClient, err := r.server.invoices.SubscribeNotifications(
+		req.AddIndex, req.SettleIndex,
+	)
+	if err != nil {
+		return err
+	}
 	defer invoiceClient.Cancel()
 
 	for {
 		select {
-		// TODO(roasbeef): include newly added invoices?
+		case newInvoice := <-invoiceClient.NewInvoices:
+			invoice, err := r.createRPCInvoice(newInvoice)
+			if err != nil {
+				return err
+			}
+			if err := updateStream.Send(invoice); err != nil {
+				return err
+			}
 		case settledInvoice := <-invoiceClient.SettledInvoices:
-			invoice := &lnrpc.Invoice{
-				Memo:      string(settledInvoice.Memo[:]),
-				Receipt:   settledInvoice.Receipt[:],
-				RPreimage: settledInvoice.Terms.PaymentPreimage[:],
-				Value:     int64(settledInvoice.Terms.Value),
-				Settled:   settledInvoice.Terms.Settled,
+			invoice, err := r.createRPCInvoice(settledInvoice)
+			if err != nil {
+				return err
+			}
+			if err := updateStream.Send(invoice); err != nil {
+				return err
+			}
+		case acceptedInvoice := <-invoiceClient.AcceptedInvoices:
+			// Accepted hold invoices are neither added nor
+			// settled, so they're only streamed to clients which
+			// have asked for them, as older clients don't expect
+			// them.
+			if !req.IncludeAccepted {
+				continue
+			}
+
+			invoice, err := r.createRPCInvoice(acceptedInvoice)
+			if err != nil {
+				return err
+			}
+			if err := updateStream.Send(invoice); err != nil {
+				return err
+			}
+		case canceledInvoice := <-invoiceClient.CanceledInvoices:
+			// Canceled and expired invoices are only streamed to
+			// clients which have asked for them, as older clients
+			// don't expect them.
+			if !req.IncludeCanceled {
+				continue
+			}
+
+			invoice, err := r.createRPCInvoice(canceledInvoice)
+			if err != nil {
+				return err
 			}
 			if err := updateStream.Send(invoice); err != nil {
 				return err
@@ -1399,25 +2253,26 @@ func marshalDbEdge(c1, c2 *channeldb.ChannelEdge) *lnrpc.ChannelEdge {
 		Capacity:   int64(capacity),
 	}
 
-	if c1 != nil {
-		edge.Node1Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c1.Expiry),
-			MinHtlc:          int64(c1.MinHTLC),
-			FeeBaseMsat:      int64(c1.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c1.FeeProportionalMillionths),
-		}
-	}
+	edge.Node1Policy = marshalDbRoutingPolicy(c1)
+	edge.Node2Policy = marshalDbRoutingPolicy(c2)
 
-	if c2 != nil {
-		edge.Node2Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c2.Expiry),
-			MinHtlc:          int64(c2.MinHTLC),
-			FeeBaseMsat:      int64(c2.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c2.FeeProportionalMillionths),
-		}
+	return edge
+}
+
+// marshalDbRoutingPolicy converts the routing policy of the passed directed
+// edge into its RPC representation. If the edge is nil, then nil is returned.
+func marshalDbRoutingPolicy(edge *channeldb.ChannelEdge) *lnrpc.RoutingPolicy {
+	if edge == nil {
+		return nil
 	}
 
-	return edge
+	return &lnrpc.RoutingPolicy{
+		TimeLockDelta:    uint32(edge.Expiry),
+		MinHtlc:          int64(edge.MinHTLC),
+		FeeBaseMsat:      int64(edge.FeeBaseMSat),
+		FeeRateMilliMsat: int64(edge.FeeProportionalMillionths),
+		MaxHtlc:          int64(edge.MaxHTLC),
+	}
 }
 
 // GetChainInfo returns the latest authenticated network announcement for the
@@ -1492,16 +2347,17 @@ func (r *rpcServer) GetNodeInfo(_ context.Context, in *lnrpc.NodeInfoRequest) (*
 	}, nil
 }
 
-// QueryRoute attempts to query the daemons' Channel Router for a possible
-// route to a target destination capable of carrying a specific amount of
-// satoshis within the route's flow. The retuned route contains the full
-// details required to craft and send an HTLC, also including the necessary
-// information that should be present within the Sphinx packet encapsualted
-// within the HTLC.
+// QueryRoute attempts to query the daemons' Channel Router for a set of
+// possible routes to a target destination capable of carrying a specific
+// amount of satoshis within the route's flow. The retuned routes contain the
+// full details required to craft and send an HTLC, also including the
+// necessary information that should be present within the Sphinx packet
+// encapsualted within the HTLC. The routes are sorted by their total fee.
 //
-// TODO(roasbeef): should return a slice of routes in reality
-//  * create separate PR to send based on well formatted route
-func (r *rpcServer) QueryRoute(_ context.Context, in *lnrpc.RouteRequest) (*lnrpc.Route, error) {
+// TODO(roasbeef): create separate PR to send based on well formatted route
+func (r *rpcServer) QueryRoute(_ context.Context,
+	in *lnrpc.RouteRequest) (*lnrpc.QueryRouteResponse, error) {
+
 	// First parse the hex-encdoed public key into a full public key objet
 	// we can properly manipulate.
 	pubKeyBytes, err := hex.DecodeString(in.PubKey)
@@ -1513,19 +2369,201 @@ func (r *rpcServer) QueryRoute(_ context.Context, in *lnrpc.RouteRequest) (*lnrp
 		return nil, err
 	}
 
-	// Query the channel router for a possible path to the destination that
-	// can carry `in.Amt` satoshis _including_ the total fee required on
-	// the route.
-	route, err := r.server.chanRouter.FindRoute(pubKey,
-		btcutil.Amount(in.Amt))
+	// If the number of routes wasn't specified, then we'll default to
+	// returning only the best route.
+	numRoutes := uint32(1)
+	if in.NumRoutes > 0 {
+		numRoutes = uint32(in.NumRoutes)
+	}
+
+	restrictions, err := unmarshalRouteRestrictions(in.FeeLimit,
+		in.CltvLimit, in.OutgoingChanId, in.LastHopPubkey,
+		in.IgnoredNodes, in.IgnoredEdges, 0)
 	if err != nil {
 		return nil, err
 	}
+	routeHints, err := unmarshalRouteHints(in.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query the channel router for a set of possible paths to the
+	// destination that can carry `in.Amt` satoshis _including_ the total
+	// fee required on the route.
+	routes, err := r.server.chanRouter.FindRoutes(pubKey,
+		btcutil.Amount(in.Amt), numRoutes, restrictions,
+		routingHopHints(routeHints))
+	if err != nil {
+		return nil, err
+	}
+
+	// If a set of routes exists within the network that is able to
+	// support our request, then we'll convert the result into the format
+	// required by the RPC system.
+	resp := &lnrpc.QueryRouteResponse{
+		Routes: make([]*lnrpc.Route, len(routes)),
+	}
+	for i, route := range routes {
+		resp.Routes[i] = marshalRoute(route)
+	}
 
-	// If a route exists within the network that is able to support our
-	// request, then we'll convert the result into the format required by
-	// the RPC system.
-	return marshalRoute(route), nil
+	return resp, nil
+}
+
+// BuildRoute constructs a route which travels through the specified list of
+// hops in order, filling in the fees and time locks of each hop according to
+// the policies of the channels between them. The returned route may then be
+// passed to SendToRoute.
+func (r *rpcServer) BuildRoute(_ context.Context,
+	in *lnrpc.BuildRouteRequest) (*lnrpc.BuildRouteResponse, error) {
+
+	if in.Amt <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	hops := make([]*btcec.PublicKey, 0, len(in.HopPubkeys))
+	for _, hopStr := range in.HopPubkeys {
+		pubBytes, err := hex.DecodeString(hopStr)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		hops = append(hops, pubKey)
+	}
+
+	route, err := r.server.chanRouter.BuildRoute(btcutil.Amount(in.Amt),
+		hops)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BuildRouteResponse{
+		Route: marshalRoute(route),
+	}, nil
+}
+
+// unmarshalRouteRestrictions parses the route restrictions of an RPC request
+// into the form expected by the channel router.
+func unmarshalRouteRestrictions(feeLimit int64, cltvLimit uint32,
+	outgoingChanID uint64, lastHop []byte, ignoredNodes [][]byte,
+	ignoredEdges []uint64,
+	finalCLTVDelta uint32) (*routing.RouteRestrictions, error) {
+
+	if feeLimit < 0 {
+		return nil, fmt.Errorf("fee limit cannot be negative")
+	}
+	if finalCLTVDelta > math.MaxUint16 {
+		return nil, fmt.Errorf("final cltv delta of %v is too large",
+			finalCLTVDelta)
+	}
+
+	restrictions := &routing.RouteRestrictions{
+		FeeLimit:          btcutil.Amount(feeLimit),
+		CltvLimit:         cltvLimit,
+		OutgoingChannelID: outgoingChanID,
+		IgnoredEdges:      ignoredEdges,
+		FinalCLTVDelta:    uint16(finalCLTVDelta),
+	}
+
+	if len(lastHop) != 0 {
+		pub, err := btcec.ParsePubKey(lastHop, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		restrictions.LastHop = pub
+	}
+
+	for _, node := range ignoredNodes {
+		pub, err := btcec.ParsePubKey(node, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+		restrictions.IgnoredNodes = append(restrictions.IgnoredNodes, pub)
+	}
+
+	return restrictions, nil
+}
+
+// marshalRouteHints converts the route hints of a decoded payment request into
+// their RPC representation.
+func marshalRouteHints(routeHints [][]zpay32.HopHint) []*lnrpc.RouteHint {
+	rpcHints := make([]*lnrpc.RouteHint, 0, len(routeHints))
+	for _, routeHint := range routeHints {
+		rpcHint := &lnrpc.RouteHint{
+			HopHints: make([]*lnrpc.HopHint, 0, len(routeHint)),
+		}
+		for _, hint := range routeHint {
+			nodeID := hint.NodeID.SerializeCompressed()
+			rpcHint.HopHints = append(rpcHint.HopHints, &lnrpc.HopHint{
+				NodeId:                    hex.EncodeToString(nodeID),
+				ChanId:                    hint.ChannelID,
+				FeeBaseMsat:               hint.FeeBaseMSat,
+				FeeProportionalMillionths: hint.FeeProportionalMillionths,
+				CltvExpiryDelta:           uint32(hint.CLTVExpiryDelta),
+			})
+		}
+		rpcHints = append(rpcHints, rpcHint)
+	}
+
+	return rpcHints
+}
+
+// unmarshalRouteHints parses the route hints of an RPC request into the form
+// they're encoded within a payment request.
+func unmarshalRouteHints(rpcHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, error) {
+	routeHints := make([][]zpay32.HopHint, 0, len(rpcHints))
+	for _, rpcHint := range rpcHints {
+		routeHint := make([]zpay32.HopHint, 0, len(rpcHint.HopHints))
+		for _, hint := range rpcHint.HopHints {
+			pubBytes, err := hex.DecodeString(hint.NodeId)
+			if err != nil {
+				return nil, err
+			}
+			nodeID, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+			if err != nil {
+				return nil, err
+			}
+			if hint.CltvExpiryDelta > math.MaxUint16 {
+				return nil, fmt.Errorf("cltv expiry delta of %v "+
+					"is too large", hint.CltvExpiryDelta)
+			}
+
+			routeHint = append(routeHint, zpay32.HopHint{
+				NodeID:                    nodeID,
+				ChannelID:                 hint.ChanId,
+				FeeBaseMSat:               hint.FeeBaseMsat,
+				FeeProportionalMillionths: hint.FeeProportionalMillionths,
+				CLTVExpiryDelta:           uint16(hint.CltvExpiryDelta),
+			})
+		}
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
+
+// routingHopHints converts the route hints of a payment request into the form
+// expected by the channel router.
+func routingHopHints(routeHints [][]zpay32.HopHint) [][]routing.HopHint {
+	hints := make([][]routing.HopHint, 0, len(routeHints))
+	for _, routeHint := range routeHints {
+		hops := make([]routing.HopHint, 0, len(routeHint))
+		for _, hint := range routeHint {
+			hops = append(hops, routing.HopHint{
+				NodeID:                    hint.NodeID,
+				ChannelID:                 hint.ChannelID,
+				FeeBaseMSat:               btcutil.Amount(hint.FeeBaseMSat),
+				FeeProportionalMillionths: btcutil.Amount(hint.FeeProportionalMillionths),
+				CLTVExpiryDelta:           hint.CLTVExpiryDelta,
+			})
+		}
+		hints = append(hints, hops)
+	}
+
+	return hints
 }
 
 func marshalRoute(route *routing.Route) *lnrpc.Route {
@@ -1541,12 +2579,57 @@ func marshalRoute(route *routing.Route) *lnrpc.Route {
 			ChanCapacity: int64(hop.Channel.Capacity),
 			AmtToForward: int64(hop.AmtToForward),
 			Fee:          int64(hop.Fee),
+			PubKey: hex.EncodeToString(
+				hop.Channel.Node.PubKey.SerializeCompressed(),
+			),
+			TimeLockDelta: uint32(hop.TimeLockDelta),
 		}
 	}
 
 	return resp
 }
 
+// unmarshalRoute parses a route specified within an RPC request. Each hop
+// must identify the node it leads to, along with the channel used to reach
+// it.
+func unmarshalRoute(rpcRoute *lnrpc.Route) (*routing.Route, error) {
+	if len(rpcRoute.Hops) == 0 {
+		return nil, fmt.Errorf("route must have at least one hop")
+	}
+
+	route := &routing.Route{
+		TotalTimeLock: rpcRoute.TotalTimeLock,
+		TotalFees:     btcutil.Amount(rpcRoute.TotalFees),
+		TotalAmount:   btcutil.Amount(rpcRoute.TotalAmt),
+		Hops:          make([]*routing.Hop, len(rpcRoute.Hops)),
+	}
+	for i, rpcHop := range rpcRoute.Hops {
+		pubBytes, err := hex.DecodeString(rpcHop.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		route.Hops[i] = &routing.Hop{
+			Channel: &channeldb.ChannelEdge{
+				ChannelID: rpcHop.ChanId,
+				Capacity:  btcutil.Amount(rpcHop.ChanCapacity),
+				Node: &channeldb.LightningNode{
+					PubKey: pubKey,
+				},
+			},
+			TimeLockDelta: uint16(rpcHop.TimeLockDelta),
+			AmtToForward:  btcutil.Amount(rpcHop.AmtToForward),
+			Fee:           btcutil.Amount(rpcHop.Fee),
+		}
+	}
+
+	return route, nil
+}
+
 // GetNetworkInfo returns some basic stats about the known channel graph from
 // the PoV of the node.
 func (r *rpcServer) GetNetworkInfo(context.Context, *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
@@ -1641,19 +2724,250 @@ func (r *rpcServer) GetNetworkInfo(context.Context, *lnrpc.NetworkInfoRequest) (
 	}, nil
 }
 
+// SubscribeChannelGraph launches a streaming RPC that allows the caller to
+// receive notifications upon any changes to the channel graph topology from
+// the view of the responding node. Events notified include: new nodes coming
+// online, nodes updating their authenticated attributes, new channels being
+// advertised, updates in the routing policy for a directional channel edge,
+// and finally when prior channels are closed on-chain or pruned as zombies.
+func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
+
+	// First, we start by subscribing to a new intent to receive
+	// notifications from the channel router.
+	client, err := r.server.chanRouter.SubscribeTopology()
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the resources for the topology update client is cleaned
+	// up once either the server, or client exists.
+	defer client.Cancel()
+
+	for {
+		select {
+
+		// A new update has been sent by the channel router, we'll
+		// marshal it into the form expected by the gRPC client, then
+		// send it off.
+		case topChange, ok := <-client.TopologyChanges:
+			// If the second value from the channel read is nil,
+			// then this means that the channel router is exiting
+			// or the notification client was cancelled. So we'll
+			// exit early.
+			if !ok {
+				return errors.New("server shutting down")
+			}
+
+			// Convert the struct from the channel router into the
+			// form expected by the gRPC service then send it off
+			// to the client.
+			graphUpdate := marshallTopologyChange(topChange)
+			if err := updateStream.Send(graphUpdate); err != nil {
+				return err
+			}
+
+		// If the client has disconnected, then we'll exit so the
+		// notification client can be cleaned up.
+		case <-updateStream.Context().Done():
+			return nil
+
+		// The server is quitting, so we'll exit immediately. Returning
+		// nil will close the clients read end of the stream.
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshallTopologyChange performs a mapping from the topology change struct
+// returned by the router to the form of notifications expected by the current
+// gRPC service.
+func marshallTopologyChange(topChange *routing.TopologyChange) *lnrpc.GraphTopologyUpdate {
+	nodeUpdates := make([]*lnrpc.NodeUpdate, len(topChange.NodeUpdates))
+	for i, nodeUpdate := range topChange.NodeUpdates {
+		var addr string
+		if nodeUpdate.Address != nil {
+			addr = nodeUpdate.Address.String()
+		}
+
+		nodeUpdates[i] = &lnrpc.NodeUpdate{
+			Address: addr,
+			IdentityKey: hex.EncodeToString(
+				nodeUpdate.IdentityKey.SerializeCompressed(),
+			),
+			Alias: nodeUpdate.Alias,
+			Color: fmt.Sprintf("#%02x%02x%02x", nodeUpdate.Color.R,
+				nodeUpdate.Color.G, nodeUpdate.Color.B),
+		}
+	}
+
+	channelUpdates := make([]*lnrpc.ChannelEdgeUpdate,
+		len(topChange.ChannelEdgeUpdates))
+	for i, channelUpdate := range topChange.ChannelEdgeUpdates {
+		channelUpdates[i] = &lnrpc.ChannelEdgeUpdate{
+			ChanId:    channelUpdate.ChanID,
+			ChanPoint: channelUpdate.ChanPoint.String(),
+			Capacity:  int64(channelUpdate.Capacity),
+			RoutingPolicy: marshalDbRoutingPolicy(
+				channelUpdate.Policy,
+			),
+			PrevRoutingPolicy: marshalDbRoutingPolicy(
+				channelUpdate.PrevPolicy,
+			),
+			AdvertisingNode: hex.EncodeToString(
+				channelUpdate.AdvertisingNode.SerializeCompressed(),
+			),
+			ConnectingNode: hex.EncodeToString(
+				channelUpdate.ConnectingNode.SerializeCompressed(),
+			),
+		}
+	}
+
+	closedChans := make([]*lnrpc.ClosedChannelUpdate,
+		len(topChange.ClosedChannels))
+	for i, closedChan := range topChange.ClosedChannels {
+		closedChans[i] = &lnrpc.ClosedChannelUpdate{
+			ChanId:       closedChan.ChanID,
+			Capacity:     int64(closedChan.Capacity),
+			ClosedHeight: closedChan.ClosedHeight,
+			ChanPoint:    closedChan.ChanPoint.String(),
+		}
+	}
+
+	return &lnrpc.GraphTopologyUpdate{
+		NodeUpdates:    nodeUpdates,
+		ChannelUpdates: channelUpdates,
+		ClosedChans:    closedChans,
+	}
+}
+
+// QueryMissionControl returns the payment history recorded by mission
+// control for each node pair, along with the current estimated probability
+// of successfully forwarding the last failed amount over the pair.
+func (r *rpcServer) QueryMissionControl(ctx context.Context,
+	in *lnrpc.QueryMissionControlRequest) (*lnrpc.QueryMissionControlResponse, error) {
+
+	rpcsLog.Tracef("[querymissioncontrol] request")
+
+	// unixTime returns the unix timestamp of the passed time, or zero if
+	// the time is unset.
+	unixTime := func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.Unix()
+	}
+
+	pairs := r.server.chanRouter.QueryMissionControl()
+	resp := &lnrpc.QueryMissionControlResponse{
+		Pairs: make([]*lnrpc.PairHistory, 0, len(pairs)),
+	}
+	for _, pair := range pairs {
+		resp.Pairs = append(resp.Pairs, &lnrpc.PairHistory{
+			NodeFrom:        hex.EncodeToString(pair.From[:]),
+			NodeTo:          hex.EncodeToString(pair.To[:]),
+			LastFailTime:    unixTime(pair.LastFailTime),
+			LastFailAmt:     int64(pair.LastFailAmt),
+			LastSuccessTime: unixTime(pair.LastSuccessTime),
+			LastSuccessAmt:  int64(pair.LastSuccessAmt),
+			SuccessProb:     pair.SuccessProbability,
+		})
+	}
+
+	return resp, nil
+}
+
+// ResetMissionControl clears all payment history recorded by mission
+// control, including any history persisted to disk.
+func (r *rpcServer) ResetMissionControl(ctx context.Context,
+	in *lnrpc.ResetMissionControlRequest) (*lnrpc.ResetMissionControlResponse, error) {
+
+	rpcsLog.Debugf("[resetmissioncontrol] request")
+
+	if err := r.server.chanRouter.ResetMissionControl(); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ResetMissionControlResponse{}, nil
+}
+
+// AutopilotStatus returns whether the autopilot agent is active, along with
+// its configured constraints. If the agent is active, then a snapshot of the
+// channels it's currently managing is also returned.
+func (r *rpcServer) AutopilotStatus(ctx context.Context,
+	in *lnrpc.AutopilotStatusRequest) (*lnrpc.AutopilotStatusResponse, error) {
+
+	rpcsLog.Debugf("[autopilotstatus] request")
+
+	pilotCfg := r.server.autopilot.cfg
+	resp := &lnrpc.AutopilotStatusResponse{
+		MaxChannels: uint32(pilotCfg.MaxChannels),
+		Allocation:  pilotCfg.Allocation,
+		MinChanSize: pilotCfg.MinChanSize,
+		MaxChanSize: pilotCfg.MaxChanSize,
+	}
+
+	status, err := r.server.autopilot.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	// A nil status indicates that the agent isn't currently active.
+	if status == nil {
+		return resp, nil
+	}
+
+	resp.Active = true
+	resp.NumChannels = uint32(status.NumChannels)
+	resp.TotalAllocation = int64(status.TotalAllocation)
+	resp.NumPendingOpens = uint32(status.NumPendingOpens)
+
+	return resp, nil
+}
+
+// SetAutopilot enables or disables the autopilot agent. Enabling an already
+// active agent, or disabling an inactive one is a no-op.
+func (r *rpcServer) SetAutopilot(ctx context.Context,
+	in *lnrpc.SetAutopilotRequest) (*lnrpc.SetAutopilotResponse, error) {
+
+	rpcsLog.Debugf("[setautopilot] enable=%v", in.Enable)
+
+	if err := r.server.autopilot.SetActive(in.Enable); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SetAutopilotResponse{}, nil
+}
+
 // ListPayments returns a list of all outgoing payments.
-func (r *rpcServer) ListPayments(context.Context,
-	*lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
+func (r *rpcServer) ListPayments(ctx context.Context,
+	req *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
 
 	rpcsLog.Debugf("[ListPayments]")
 
-	payments, err := r.server.chanDB.FetchAllPayments()
-	if err != nil && err != channeldb.ErrNoPaymentsCreated {
+	q := channeldb.PaymentsQuery{
+		IndexOffset: req.IndexOffset,
+		MaxPayments: req.MaxPayments,
+		Reversed:    req.Reversed,
+	}
+	if req.CreationDateStart != 0 {
+		q.CreationDateStart = time.Unix(req.CreationDateStart, 0)
+	}
+	if req.CreationDateEnd != 0 {
+		q.CreationDateEnd = time.Unix(req.CreationDateEnd, 0)
+	}
+
+	paymentsSlice, err := r.server.chanDB.QueryPayments(q)
+	if err != nil {
 		return nil, err
 	}
+	payments := paymentsSlice.Payments
 
 	paymentsResp := &lnrpc.ListPaymentsResponse{
-		Payments: make([]*lnrpc.Payment, len(payments)),
+		Payments:         make([]*lnrpc.Payment, len(payments)),
+		FirstIndexOffset: paymentsSlice.FirstIndexOffset,
+		LastIndexOffset:  paymentsSlice.LastIndexOffset,
 	}
 	for i, payment := range payments {
 		path := make([]string, len(payment.Path))
@@ -1666,6 +2980,7 @@ func (r *rpcServer) ListPayments(context.Context,
 			Value:        int64(payment.Terms.Value),
 			CreationDate: payment.CreationDate.Unix(),
 			Path:         path,
+			PaymentIndex: payment.SequenceNum,
 		}
 	}
 
@@ -1725,15 +3040,48 @@ func (r *rpcServer) DecodePayReq(ctx context.Context,
 	// Fist we'll attempt to decode the payment request string, if the
 	// request is invalid or the checksum doesn't match, then we'll exit
 	// here with an error.
-	payReq, err := zpay32.Decode(req.PayReq)
+	payReq, err := zpay32.DecodePaymentRequest(
+		req.PayReq, activeNetParams.Params,
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	dest := payReq.Destination.SerializeCompressed()
-	return &lnrpc.PayReq{
+	resp := &lnrpc.PayReq{
 		Destination: hex.EncodeToString(dest),
 		PaymentHash: hex.EncodeToString(payReq.PaymentHash[:]),
-		NumSatoshis: int64(payReq.Amount),
-	}, nil
+		RouteHints:  marshalRouteHints(payReq.RouteHints),
+	}
+	if payReq.Amount != nil {
+		resp.NumSatoshis = int64(*payReq.Amount)
+	}
+
+	// Legacy payment requests carry no timestamp, nor any of the optional
+	// fields below, so we only populate them for bech32 invoices.
+	if payReq.Timestamp.IsZero() {
+		return resp, nil
+	}
+
+	resp.Timestamp = payReq.Timestamp.Unix()
+	resp.Expiry = int64(
+		payReq.ExpiryTime().Sub(payReq.Timestamp) / time.Second,
+	)
+	resp.CltvExpiry = int64(payReq.MinFinalCLTVExpiryDelta())
+	if payReq.Description != nil {
+		resp.Description = *payReq.Description
+	}
+	if payReq.DescriptionHash != nil {
+		resp.DescriptionHash = hex.EncodeToString(
+			payReq.DescriptionHash[:],
+		)
+	}
+	if payReq.FallbackAddr != nil {
+		resp.FallbackAddr = payReq.FallbackAddr.String()
+	}
+	for _, feature := range payReq.Features {
+		resp.Features = append(resp.Features, uint32(feature))
+	}
+
+	return resp, nil
 }
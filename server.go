@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -58,10 +59,35 @@ type server struct {
 	invoices      *invoiceRegistry
 	breachArbiter *breachArbiter
 
+	// webhooks POSTs notifications of invoice events to the configured
+	// webhook URLs. It's nil if no webhook URLs are configured.
+	webhooks *webhookDispatcher
+
 	chanRouter *routing.ChannelRouter
 
+	// discoverSrv is the authenticated gossiper which validates all
+	// incoming network announcements before applying them to the channel
+	// graph, and broadcasts our own channels to the network.
+	discoverSrv *discovery.AuthenticatedGossiper
+
+	// autopilot manages the autopilot agent, which automatically opens
+	// channels on our behalf when enabled.
+	autopilot *autoPilotManager
+
+	// nodeSigner is used to sign messages with our node's identity key,
+	// such as the announcements of our node and channels.
+	nodeSigner *nodeSigner
+
 	utxoNursery *utxoNursery
 
+	// htlcBatchStats aggregates metrics concerning the size of the HTLC
+	// batches committed to across all active channels.
+	htlcBatchStats *batchStats
+
+	// htlcLimiter enforces the per-peer and per-channel HTLC rate limits,
+	// as well as caps on the number of concurrent pending forwards.
+	htlcLimiter *htlcLimiter
+
 	sphinx *sphinx.Router
 
 	connMgr *connmgr.ConnManager
@@ -107,11 +133,27 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		chainNotifier: notifier,
 		chanDB:        chanDB,
 
-		invoices:    newInvoiceRegistry(chanDB),
+		invoices: newInvoiceRegistry(
+			chanDB, notifier, cfg.MPPTimeout, cfg.MaxOverpayment,
+			cfg.AcceptKeySend,
+		),
 		utxoNursery: newUtxoNursery(chanDB, notifier, wallet),
 		htlcSwitch:  newHtlcSwitch(),
 
+		htlcBatchStats: newBatchStats(),
+		htlcLimiter: newHtlcLimiter(htlcLimiterConfig{
+			PeerRate:            cfg.HTLCPeerRate,
+			PeerBurst:           cfg.HTLCPeerBurst,
+			ChanRate:            cfg.HTLCChanRate,
+			ChanBurst:           cfg.HTLCChanBurst,
+			MaxPendingForwards:  cfg.MaxPendingForwards,
+			TrackReputation:     cfg.HTLCReputation,
+			HoldThreshold:       cfg.HTLCHoldThreshold,
+			ReputationThreshold: cfg.HTLCReputationThreshold,
+		}),
+
 		identityPriv: privKey,
+		nodeSigner:   newNodeSigner(privKey),
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
@@ -133,6 +175,10 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		quit:    make(chan struct{}),
 	}
 
+	if len(cfg.Webhooks.URLs) != 0 {
+		s.webhooks = newWebhookDispatcher(cfg.Webhooks, chanDB, s.invoices)
+	}
+
 	// If the debug HTLC flag is on, then we invoice a "master debug"
 	// invoice which all outgoing payments will be sent and all incoming
 	// HTLCs with the debug R-Hash immediately settled.
@@ -157,18 +203,49 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		// TODO(roasbeef): make alias configurable
 		Alias: hex.EncodeToString(serializedPubKey[:10]),
 	}
+
+	// Sign our node announcement so it can be relayed to, and
+	// authenticated by, the rest of the network.
+	selfAlias, err := lnwire.NewAlias(self.Alias)
+	if err != nil {
+		return nil, err
+	}
+	self.AuthSig, err = discovery.SignAnnouncement(s.nodeSigner,
+		self.PubKey, &lnwire.NodeAnnouncement{
+			Timestamp: uint32(self.LastUpdate.Unix()),
+			Address:   self.Address,
+			NodeID:    self.PubKey,
+			Alias:     selfAlias,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 	if err := chanGraph.SetSourceNode(self); err != nil {
 		return nil, err
 	}
 
+	// If mission control persistence is active, then we'll hand the
+	// router our database so its payment history survives restarts.
+	var missionControlDB *channeldb.DB
+	if cfg.PersistMissionControl {
+		missionControlDB = chanDB
+	}
+
+	// Outgoing payments are recorded within the database, preventing
+	// duplicate payments. As all payments pay to the same hash in debug
+	// HTLC mode, they aren't recorded in that mode.
+	paymentControlDB := chanDB
+	if cfg.DebugHTLC {
+		paymentControlDB = nil
+	}
+
 	s.chanRouter, err = routing.New(routing.Config{
-		Graph:        chanGraph,
-		Chain:        bio,
-		Notifier:     notifier,
-		Broadcast:    s.broadcastMessage,
-		SendMessages: s.sendToPeer,
+		Graph:    chanGraph,
+		Chain:    bio,
+		Notifier: notifier,
 		SendToSwitch: func(firstHop *btcec.PublicKey,
-			htlcAdd *lnwire.HTLCAddRequest) error {
+			htlcAdd *lnwire.HTLCAddRequest) ([32]byte, error) {
 
 			firstHopPub := firstHop.SerializeCompressed()
 			destInterface := chainhash.Hash(fastsha256.Sum256(firstHopPub))
@@ -178,12 +255,39 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 				msg:  htlcAdd,
 			})
 		},
+		QueryBandwidth: func(edge *channeldb.ChannelEdge) btcutil.Amount {
+			return s.htlcSwitch.LinkBandwidth(&edge.ChannelPoint)
+		},
+		PenaltyHalfLife:    cfg.PenaltyHalfLife,
+		MissionControlDB:   missionControlDB,
+		PaymentControlDB:   paymentControlDB,
+		AttemptCost:        btcutil.Amount(cfg.AttemptCost),
+		ChannelPruneExpiry: cfg.ChanPruneExpiry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// HTLCs sent by the router which were in flight across a restart are
+	// resolved by the switch without a circuit, so we'll have it report
+	// their outcome back to the router.
+	s.htlcSwitch.resolveLocalPayment = s.chanRouter.ResolvePaymentHTLC
+
+	s.discoverSrv, err = discovery.New(discovery.Config{
+		Router:             s.chanRouter,
+		Graph:              chanGraph,
+		Chain:              bio,
+		Notifier:           notifier,
+		SendToPeer:         s.sendToPeer,
+		NumActiveSyncers:   cfg.NumGraphSyncPeers,
+		ChannelPruneExpiry: cfg.ChanPruneExpiry,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	s.rpcServer = newRpcServer(s)
+	s.autopilot = newAutoPilotManager(s, cfg.Autopilot)
 	s.breachArbiter = newBreachArbiter(wallet, chanDB, notifier, s.htlcSwitch)
 	s.fundingMgr = newFundingManager(wallet, s.breachArbiter)
 
@@ -266,6 +370,14 @@ func (s *server) Start() error {
 	if err := s.htlcSwitch.Start(); err != nil {
 		return err
 	}
+	if err := s.invoices.Start(); err != nil {
+		return err
+	}
+	if s.webhooks != nil {
+		if err := s.webhooks.Start(); err != nil {
+			return err
+		}
+	}
 	if err := s.utxoNursery.Start(); err != nil {
 		return err
 	}
@@ -275,10 +387,20 @@ func (s *server) Start() error {
 	if err := s.chanRouter.Start(); err != nil {
 		return err
 	}
+	if err := s.discoverSrv.Start(); err != nil {
+		return err
+	}
 
 	s.wg.Add(1)
 	go s.queryHandler()
 
+	// With all other sub-systems started, we'll now launch the autopilot
+	// agent if it's active, as it depends on the query handler in order
+	// to connect to peers and open channels.
+	if err := s.autopilot.Start(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -292,16 +414,24 @@ func (s *server) Stop() error {
 	}
 
 	// Shutdown the wallet, funding manager, and the rpc server.
+	s.autopilot.Stop()
 	s.chainNotifier.Stop()
 	s.rpcServer.Stop()
 	s.fundingMgr.Stop()
+	s.discoverSrv.Stop()
 	s.chanRouter.Stop()
 	s.htlcSwitch.Stop()
+	if s.webhooks != nil {
+		s.webhooks.Stop()
+	}
+	s.invoices.Stop()
 	s.utxoNursery.Stop()
 	s.breachArbiter.Stop()
 
 	s.lnwallet.Shutdown()
 
+	srvrLog.Infof("HTLC commitment batch stats: %v", s.htlcBatchStats)
+
 	// Signal all the lingering goroutines to quit.
 	close(s.quit)
 	s.wg.Wait()
@@ -500,10 +630,10 @@ func (s *server) addPeer(p *peer) {
 	s.peersByPub[string(p.addr.IdentityKey.SerializeCompressed())] = p
 	s.peersMtx.Unlock()
 
-	// Once the peer has been added to our indexes, send a message to the
-	// channel router so we can synchronize our view of the channel graph
-	// with this new peer.
-	go s.chanRouter.SynchronizeNode(p.addr.IdentityKey)
+	// Once the peer has been added to our indexes, we'll initialize our
+	// gossip sync state with the peer, allowing us to reconcile our view
+	// of the channel graph with this new peer.
+	s.discoverSrv.InitSyncState(p.addr.IdentityKey)
 }
 
 // removePeer removes the passed peer from the server's state of all active
@@ -531,6 +661,10 @@ func (s *server) removePeer(p *peer) {
 
 	delete(s.peersByID, p.id)
 	delete(s.peersByPub, string(p.addr.IdentityKey.SerializeCompressed()))
+
+	// With the peer removed, we no longer need to maintain our gossip
+	// sync state with it.
+	s.discoverSrv.PruneSyncState(p.addr.IdentityKey)
 }
 
 // connectPeerMsg is a message requesting the server to open a connection to a
@@ -567,6 +701,10 @@ type openChanReq struct {
 
 	numConfs uint32
 
+	// private indicates that the channel shouldn't be announced to the
+	// greater network.
+	private bool
+
 	updates chan *lnrpc.OpenStatusUpdate
 	err     chan error
 }
@@ -805,10 +943,11 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 }
 
 // OpenChannel sends a request to the server to open a channel to the specified
-// peer identified by ID with the passed channel funding paramters.
+// peer identified by ID with the passed channel funding paramters. If private
+// is true, then the channel won't be announced to the greater network.
 func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
-	localAmt, pushAmt btcutil.Amount,
-	numConfs uint32) (chan *lnrpc.OpenStatusUpdate, chan error) {
+	localAmt, pushAmt btcutil.Amount, numConfs uint32,
+	private bool) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
 	errChan := make(chan error, 1)
 	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
@@ -819,6 +958,7 @@ func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
 		localFundingAmt: localAmt,
 		pushAmt:         pushAmt,
 		numConfs:        numConfs,
+		private:         private,
 		updates:         updateChan,
 		err:             errChan,
 	}
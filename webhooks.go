@@ -0,0 +1,620 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/fastsha256"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+const (
+	// webhookInvoiceAdded, webhookInvoiceSettled, webhookInvoiceCanceled
+	// and webhookInvoiceExpired are the events of which the webhook
+	// dispatcher sends notifications.
+	webhookInvoiceAdded    = "invoice_added"
+	webhookInvoiceSettled  = "invoice_settled"
+	webhookInvoiceCanceled = "invoice_canceled"
+	webhookInvoiceExpired  = "invoice_expired"
+
+	// webhookSignatureHeader is the HTTP header carrying the hex encoded
+	// HMAC-SHA256 of a notification's body, keyed by the webhook secret.
+	webhookSignatureHeader = "X-Lnd-Signature"
+
+	// webhookDeliveryHeader is the HTTP header carrying the ID of a
+	// notification's delivery. As the ID is preserved across retries,
+	// receivers may use it to ignore notifications they've already
+	// processed.
+	webhookDeliveryHeader = "X-Lnd-Delivery"
+
+	// webhookTimeout is the maximum duration of a single attempt to
+	// deliver a notification.
+	webhookTimeout = 10 * time.Second
+)
+
+// webhookNotification is the JSON encoded body of each notification sent by
+// the webhook dispatcher.
+type webhookNotification struct {
+	// Event is the event the notification is for.
+	Event string `json:"event"`
+
+	// Timestamp is the time at which the notification was created, in
+	// seconds since the unix epoch. As the timestamp is covered by the
+	// notification's signature, receivers may use it to reject replayed
+	// notifications.
+	Timestamp int64 `json:"timestamp"`
+
+	// Invoice is the invoice the event occurred for.
+	Invoice *webhookInvoice `json:"invoice"`
+}
+
+// webhookInvoice is the JSON encoding of an invoice within a notification.
+// Custom records are keyed by their type, with their values hex encoded.
+type webhookInvoice struct {
+	PaymentHash    string            `json:"payment_hash"`
+	Memo           string            `json:"memo"`
+	Value          int64             `json:"value"`
+	AmtPaid        int64             `json:"amt_paid"`
+	State          string            `json:"state"`
+	CreationDate   int64             `json:"creation_date"`
+	PaymentRequest string            `json:"payment_request,omitempty"`
+	AddIndex       uint64            `json:"add_index"`
+	SettleIndex    uint64            `json:"settle_index,omitempty"`
+	CustomRecords  map[string]string `json:"custom_records,omitempty"`
+}
+
+// newWebhookNotification creates the JSON encoded body of a notification of
+// the passed event for the passed invoice.
+func newWebhookNotification(event string, invoice *channeldb.Invoice,
+	timestamp time.Time) ([]byte, error) {
+
+	paymentHash := invoice.PaymentHash()
+	ntfnInvoice := &webhookInvoice{
+		PaymentHash:    hex.EncodeToString(paymentHash[:]),
+		Memo:           string(invoice.Memo),
+		Value:          int64(invoice.Terms.Value),
+		AmtPaid:        int64(invoice.AmtPaid),
+		State:          invoice.Terms.State.String(),
+		CreationDate:   invoice.CreationDate.Unix(),
+		PaymentRequest: string(invoice.PaymentRequest),
+		AddIndex:       invoice.AddIndex,
+		SettleIndex:    invoice.SettleIndex,
+	}
+	if len(invoice.CustomRecords) != 0 {
+		ntfnInvoice.CustomRecords = make(map[string]string)
+		for recordType, value := range invoice.CustomRecords {
+			key := strconv.FormatUint(recordType, 10)
+			encoded := hex.EncodeToString(value)
+			ntfnInvoice.CustomRecords[key] = encoded
+		}
+	}
+
+	return json.Marshal(&webhookNotification{
+		Event:     event,
+		Timestamp: timestamp.Unix(),
+		Invoice:   ntfnInvoice,
+	})
+}
+
+// signWebhookPayload returns the hex encoded HMAC-SHA256 of the passed
+// payload, keyed by the passed secret.
+func signWebhookPayload(secret, payload []byte) string {
+	mac := hmac.New(fastsha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDispatcher POSTs signed JSON notifications of added, settled,
+// canceled and expired invoices to a set of configured URLs. The dispatcher
+// subscribes to the invoice registry, and queues a delivery of each
+// notification to each URL within the database, such that notifications
+// survive restarts. Along with the deliveries, the indexes of the latest
+// added and settled invoices notified are stored, such that the invoices
+// added or settled while lnd was shut down are notified upon restart. Failed
+// deliveries are retried with an exponential backoff, until they either
+// succeed or the maximum number of attempts is reached.
+type webhookDispatcher struct {
+	cfg *webhooksConfig
+
+	cdb      *channeldb.DB
+	invoices *invoiceRegistry
+
+	client *http.Client
+
+	// addIndex and settleIndex are the indexes of the latest added and
+	// settled invoices for which notifications have been queued. They're
+	// only accessed by the invoiceEventHandler once started.
+	addIndex    uint64
+	settleIndex uint64
+
+	// pending holds the deliveries which have been queued, but which are
+	// yet to be picked up by the deliveryHandler.
+	pending    []*channeldb.WebhookDelivery
+	pendingMtx sync.Mutex
+
+	// deliverySignal is signalled each time new deliveries are added to
+	// the queue.
+	deliverySignal chan struct{}
+
+	started uint32
+	stopped uint32
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newWebhookDispatcher creates a new webhook dispatcher which sends
+// notifications of the events of the passed invoice registry according to
+// the passed config.
+func newWebhookDispatcher(cfg *webhooksConfig, cdb *channeldb.DB,
+	invoices *invoiceRegistry) *webhookDispatcher {
+
+	return &webhookDispatcher{
+		cfg:            cfg,
+		cdb:            cdb,
+		invoices:       invoices,
+		client:         &http.Client{Timeout: webhookTimeout},
+		deliverySignal: make(chan struct{}, 1),
+		quit:           make(chan struct{}),
+	}
+}
+
+// Start subscribes to the events of the invoice registry, and launches the
+// goroutines responsible for queueing and delivering notifications. Any
+// deliveries left within the queue from a prior run are resumed, and the
+// invoices added or settled since the last queued notifications are notified.
+func (w *webhookDispatcher) Start() error {
+	if !atomic.CompareAndSwapUint32(&w.started, 0, 1) {
+		return nil
+	}
+
+	// The queue is only read once, after which the deliveryHandler keeps
+	// track of it in memory.
+	queue, err := w.cdb.FetchWebhookDeliveries()
+	if err != nil {
+		return err
+	}
+	w.pending = queue
+
+	// If notifications have never been queued, then we'll start from the
+	// latest invoices, rather than notifying every invoice ever added.
+	// The indexes are read before subscribing, such that any invoice
+	// added or settled in between is still notified.
+	indexes, err := w.cdb.FetchWebhookIndexes()
+	switch {
+	case err == channeldb.ErrWebhookIndexesNotFound:
+		addIndex, settleIndex, err := w.cdb.LatestInvoiceIndexes()
+		if err != nil {
+			return err
+		}
+		indexes = &channeldb.WebhookIndexes{
+			AddIndex:    addIndex,
+			SettleIndex: settleIndex,
+		}
+		if err := w.cdb.AddWebhookDeliveries(nil, indexes); err != nil {
+			return err
+		}
+
+	case err != nil:
+		return err
+	}
+	w.addIndex = indexes.AddIndex
+	w.settleIndex = indexes.SettleIndex
+
+	// We'll subscribe before fetching the invoices we've missed, such
+	// that no events slip through in between. Events which are also
+	// within the backlog are then skipped by queueNotification.
+	client, err := w.invoices.SubscribeNotifications(0, 0)
+	if err != nil {
+		return err
+	}
+
+	added, err := w.cdb.InvoicesAddedSince(w.addIndex)
+	if err != nil {
+		client.Cancel()
+		return err
+	}
+	settled, err := w.cdb.InvoicesSettledSince(w.settleIndex)
+	if err != nil {
+		client.Cancel()
+		return err
+	}
+
+	w.wg.Add(2)
+	go w.invoiceEventHandler(client, added, settled)
+	go w.deliveryHandler()
+
+	return nil
+}
+
+// Stop signals all active goroutines for a graceful shutdown. Any deliveries
+// which are yet to succeed remain within the queue.
+func (w *webhookDispatcher) Stop() error {
+	if !atomic.CompareAndSwapUint32(&w.stopped, 0, 1) {
+		return nil
+	}
+
+	close(w.quit)
+	w.wg.Wait()
+
+	return nil
+}
+
+// invoiceEventHandler first queues a notification of each of the passed
+// added and settled invoices which were missed while no notifications were
+// being queued, and then of each event delivered by the passed invoice
+// subscription.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *webhookDispatcher) invoiceEventHandler(client *invoiceSubscription,
+	added, settled []*channeldb.Invoice) {
+
+	defer w.wg.Done()
+	defer client.Cancel()
+
+	for _, invoice := range added {
+		w.queueEvent(webhookInvoiceAdded, invoice)
+	}
+	for _, invoice := range settled {
+		w.queueEvent(webhookInvoiceSettled, invoice)
+	}
+
+	for {
+		var (
+			event   string
+			invoice *channeldb.Invoice
+		)
+		select {
+		case invoice = <-client.NewInvoices:
+			event = webhookInvoiceAdded
+		case invoice = <-client.SettledInvoices:
+			event = webhookInvoiceSettled
+
+		// Expired invoices are delivered along with canceled ones, so
+		// we'll tell them apart by their state.
+		case invoice = <-client.CanceledInvoices:
+			event = webhookInvoiceCanceled
+			if invoice.Terms.State == channeldb.ContractExpired {
+				event = webhookInvoiceExpired
+			}
+
+		// Notifications aren't sent for accepted hold invoices, as
+		// they'll be followed by either a settle or cancel event.
+		case <-client.AcceptedInvoices:
+			continue
+
+		case <-w.quit:
+			return
+		}
+
+		w.queueEvent(event, invoice)
+	}
+}
+
+// queueEvent queues a notification of the passed event, logging any failure
+// to do so.
+func (w *webhookDispatcher) queueEvent(event string,
+	invoice *channeldb.Invoice) {
+
+	if err := w.queueNotification(event, invoice); err != nil {
+		whksLog.Errorf("Unable to queue %v notification for invoice "+
+			"%x: %v", event, invoice.PaymentHash(), err)
+	}
+}
+
+// queueNotification adds a delivery of a notification of the passed event to
+// each configured URL to the queue. Notifications of added and settled
+// invoices which have already been queued are skipped.
+func (w *webhookDispatcher) queueNotification(event string,
+	invoice *channeldb.Invoice) error {
+
+	indexes := &channeldb.WebhookIndexes{
+		AddIndex:    w.addIndex,
+		SettleIndex: w.settleIndex,
+	}
+	switch event {
+	case webhookInvoiceAdded:
+		if invoice.AddIndex <= w.addIndex {
+			return nil
+		}
+		indexes.AddIndex = invoice.AddIndex
+
+	case webhookInvoiceSettled:
+		if invoice.SettleIndex <= w.settleIndex {
+			return nil
+		}
+		indexes.SettleIndex = invoice.SettleIndex
+	}
+
+	now := time.Now()
+	payload, err := newWebhookNotification(event, invoice, now)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*channeldb.WebhookDelivery, 0, len(w.cfg.URLs))
+	for _, webhookURL := range w.cfg.URLs {
+		deliveries = append(deliveries, &channeldb.WebhookDelivery{
+			URL:         webhookURL,
+			Payload:     payload,
+			NextAttempt: now,
+		})
+	}
+	err = w.cdb.AddWebhookDeliveries(deliveries, indexes)
+	if err != nil {
+		return err
+	}
+	w.addIndex = indexes.AddIndex
+	w.settleIndex = indexes.SettleIndex
+
+	w.pendingMtx.Lock()
+	w.pending = append(w.pending, deliveries...)
+	w.pendingMtx.Unlock()
+
+	select {
+	case w.deliverySignal <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// deliveryHandler attempts each queued delivery once it's due, and then waits
+// until either the next delivery is due, or new deliveries are queued.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *webhookDispatcher) deliveryHandler() {
+	defer w.wg.Done()
+
+	var queue []*channeldb.WebhookDelivery
+	for {
+		w.pendingMtx.Lock()
+		queue = append(queue, w.pending...)
+		w.pending = nil
+		w.pendingMtx.Unlock()
+
+		var nextAttempt time.Time
+		queue, nextAttempt = w.attemptDueDeliveries(queue)
+
+		var (
+			timer *time.Timer
+			retry <-chan time.Time
+		)
+		if !nextAttempt.IsZero() {
+			timer = time.NewTimer(nextAttempt.Sub(time.Now()))
+			retry = timer.C
+		}
+
+		select {
+		case <-w.deliverySignal:
+		case <-retry:
+		case <-w.quit:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		select {
+		case <-w.quit:
+			return
+		default:
+		}
+	}
+}
+
+// attemptDueDeliveries attempts each of the passed deliveries whose next
+// attempt is due. Deliveries to different URLs are attempted concurrently,
+// such that a slow or unavailable URL doesn't hold up the others. The
+// deliveries which remain within the queue are returned, along with the time
+// at which the earliest of them is next due. A zero time is returned if no
+// deliveries remain.
+func (w *webhookDispatcher) attemptDueDeliveries(
+	queue []*channeldb.WebhookDelivery) ([]*channeldb.WebhookDelivery,
+	time.Time) {
+
+	// We'll first group the deliveries by their URL, preserving the order
+	// in which they were queued.
+	var urls []string
+	byURL := make(map[string][]*channeldb.WebhookDelivery)
+	for _, delivery := range queue {
+		if _, ok := byURL[delivery.URL]; !ok {
+			urls = append(urls, delivery.URL)
+		}
+		byURL[delivery.URL] = append(byURL[delivery.URL], delivery)
+	}
+
+	type urlResult struct {
+		remaining   []*channeldb.WebhookDelivery
+		nextAttempt time.Time
+	}
+	results := make([]urlResult, len(urls))
+
+	var wg sync.WaitGroup
+	for i, webhookURL := range urls {
+		wg.Add(1)
+		go func(i int, deliveries []*channeldb.WebhookDelivery) {
+			defer wg.Done()
+
+			res := &results[i]
+			res.remaining, res.nextAttempt = w.attemptURLDeliveries(
+				deliveries,
+			)
+		}(i, byURL[webhookURL])
+	}
+	wg.Wait()
+
+	var (
+		remaining   []*channeldb.WebhookDelivery
+		nextAttempt time.Time
+	)
+	for _, res := range results {
+		remaining = append(remaining, res.remaining...)
+
+		if res.nextAttempt.IsZero() {
+			continue
+		}
+		if nextAttempt.IsZero() || res.nextAttempt.Before(nextAttempt) {
+			nextAttempt = res.nextAttempt
+		}
+	}
+
+	return remaining, nextAttempt
+}
+
+// attemptURLDeliveries attempts each of the passed deliveries to a single URL
+// whose next attempt is due, in order. Once an attempt fails, the URL is
+// likely to be unavailable, so the remaining deliveries aren't attempted
+// before the failed delivery is next due. The deliveries which remain within
+// the queue are returned, along with the time at which the earliest of them
+// is next due.
+func (w *webhookDispatcher) attemptURLDeliveries(
+	deliveries []*channeldb.WebhookDelivery) ([]*channeldb.WebhookDelivery,
+	time.Time) {
+
+	var (
+		remaining    []*channeldb.WebhookDelivery
+		nextAttempt  time.Time
+		blockedUntil time.Time
+		quitting     bool
+	)
+	for _, delivery := range deliveries {
+		select {
+		case <-w.quit:
+			quitting = true
+		default:
+		}
+
+		due := !delivery.NextAttempt.After(time.Now())
+		if due && blockedUntil.IsZero() && !quitting {
+			done, err := w.attemptDelivery(delivery)
+			switch {
+			// If we're unable to update the queue, then we'll try
+			// again after the minimum backoff, in the hope the
+			// failure was transient.
+			case err != nil:
+				whksLog.Errorf("Unable to update notification "+
+					"%v to %v: %v", delivery.ID,
+					delivery.URL, err)
+
+				delivery.NextAttempt = time.Now().Add(
+					w.cfg.MinBackoff,
+				)
+				blockedUntil = delivery.NextAttempt
+
+			case done:
+				continue
+
+			default:
+				blockedUntil = delivery.NextAttempt
+			}
+		}
+
+		attempt := delivery.NextAttempt
+		if attempt.Before(blockedUntil) {
+			attempt = blockedUntil
+		}
+		if nextAttempt.IsZero() || attempt.Before(nextAttempt) {
+			nextAttempt = attempt
+		}
+
+		remaining = append(remaining, delivery)
+	}
+
+	return remaining, nextAttempt
+}
+
+// attemptDelivery attempts the passed delivery, returning true if it has
+// been removed from the queue, either because it succeeded, or because it has
+// run out of attempts. Otherwise, the delivery is rescheduled according to
+// our backoff.
+func (w *webhookDispatcher) attemptDelivery(
+	delivery *channeldb.WebhookDelivery) (bool, error) {
+
+	err := w.deliver(delivery)
+	if err == nil {
+		whksLog.Debugf("Delivered notification %v to %v",
+			delivery.ID, delivery.URL)
+
+		return true, w.cdb.DeleteWebhookDelivery(delivery.ID)
+	}
+
+	delivery.Attempts++
+	if delivery.Attempts >= uint32(w.cfg.MaxAttempts) {
+		whksLog.Errorf("Dropping notification %v to %v after %v "+
+			"failed attempts: %v", delivery.ID, delivery.URL,
+			delivery.Attempts, err)
+
+		return true, w.cdb.DeleteWebhookDelivery(delivery.ID)
+	}
+
+	backoff := w.backoff(delivery.Attempts)
+	whksLog.Warnf("Unable to deliver notification %v to %v, retrying "+
+		"in %v: %v", delivery.ID, delivery.URL, backoff, err)
+
+	delivery.NextAttempt = time.Now().Add(backoff)
+	return false, w.cdb.UpdateWebhookDelivery(delivery)
+}
+
+// backoff returns the delay before a delivery which has failed the passed
+// number of times is attempted again. The delay starts at the minimum backoff
+// and doubles with each failure, up to the maximum backoff.
+func (w *webhookDispatcher) backoff(attempts uint32) time.Duration {
+	backoff := w.cfg.MinBackoff
+	for i := uint32(1); i < attempts && backoff < w.cfg.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > w.cfg.MaxBackoff {
+		backoff = w.cfg.MaxBackoff
+	}
+
+	return backoff
+}
+
+// deliver POSTs the passed delivery's notification to its URL, signed with
+// the webhook secret. Any response with a status other than 2xx is treated as
+// a failure.
+func (w *webhookDispatcher) deliver(
+	delivery *channeldb.WebhookDelivery) error {
+
+	req, err := http.NewRequest(
+		"POST", delivery.URL, bytes.NewReader(delivery.Payload),
+	)
+	if err != nil {
+		return err
+	}
+	req.Cancel = w.quit
+
+	signature := signWebhookPayload([]byte(w.cfg.Secret), delivery.Payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookSignatureHeader, signature)
+	req.Header.Set(
+		webhookDeliveryHeader, strconv.FormatUint(delivery.ID, 10),
+	)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// We'll drain the body of the response, such that the underlying
+	// connection may be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %v",
+			resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// webhookRequest is a notification received by a test webhook server.
+type webhookRequest struct {
+	signature  string
+	deliveryID string
+	payload    []byte
+}
+
+// newTestWebhookServer creates a local HTTP server which forwards each
+// notification it receives over the returned channel. The first numFailures
+// requests are rejected.
+func newTestWebhookServer(numFailures int) (*httptest.Server,
+	chan *webhookRequest) {
+
+	var mtx sync.Mutex
+	requests := make(chan *webhookRequest, 20)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			payload, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			requests <- &webhookRequest{
+				signature: r.Header.Get(
+					webhookSignatureHeader,
+				),
+				deliveryID: r.Header.Get(
+					webhookDeliveryHeader,
+				),
+				payload: payload,
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if numFailures > 0 {
+				numFailures--
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		},
+	))
+
+	return server, requests
+}
+
+// receiveWebhook waits for the next notification to be received by a test
+// webhook server, asserting that it's correctly signed and is for the
+// expected event.
+func receiveWebhook(t *testing.T, requests chan *webhookRequest,
+	secret string, event string) (*webhookRequest, *webhookNotification) {
+
+	select {
+	case req := <-requests:
+		expectedSig := signWebhookPayload([]byte(secret), req.payload)
+		if req.signature != expectedSig {
+			t.Fatalf("invalid signature: expected %v, got %v",
+				expectedSig, req.signature)
+		}
+
+		ntfn := &webhookNotification{}
+		if err := json.Unmarshal(req.payload, ntfn); err != nil {
+			t.Fatalf("unable to decode notification: %v", err)
+		}
+		if ntfn.Event != event {
+			t.Fatalf("expected %v event, got %v", event,
+				ntfn.Event)
+		}
+
+		return req, ntfn
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no %v notification received", event)
+	}
+
+	return nil, nil
+}
+
+// assertWebhookQueueEmpty asserts that the webhook queue is eventually empty.
+func assertWebhookQueueEmpty(t *testing.T, cdb *channeldb.DB) {
+	var deliveries []*channeldb.WebhookDelivery
+	for i := 0; i < 100; i++ {
+		var err error
+		deliveries, err = cdb.FetchWebhookDeliveries()
+		if err != nil {
+			t.Fatalf("unable to fetch deliveries: %v", err)
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		time.Sleep(time.Millisecond * 50)
+	}
+
+	t.Fatalf("expected empty queue, instead have %v deliveries",
+		len(deliveries))
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	// The server rejects the first notification it receives, which should
+	// be retried.
+	server, requests := newTestWebhookServer(1)
+	defer server.Close()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	cfg := &webhooksConfig{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		MaxAttempts: 5,
+		MinBackoff:  time.Millisecond * 10,
+		MaxBackoff:  time.Millisecond * 50,
+	}
+	dispatcher := newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	invoice, rHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	// Both the rejected notification and its retry should carry the same
+	// delivery ID.
+	first, _ := receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+	retry, ntfn := receiveWebhook(
+		t, requests, cfg.Secret, webhookInvoiceAdded,
+	)
+	if first.deliveryID != retry.deliveryID {
+		t.Fatalf("retry has delivery ID %v, expected %v",
+			retry.deliveryID, first.deliveryID)
+	}
+	if ntfn.Invoice.PaymentHash != hex.EncodeToString(rHash[:]) {
+		t.Fatalf("notification for wrong invoice %v",
+			ntfn.Invoice.PaymentHash)
+	}
+	if ntfn.Invoice.Value != int64(invoice.Terms.Value) {
+		t.Fatalf("expected value %v, got %v", invoice.Terms.Value,
+			ntfn.Invoice.Value)
+	}
+
+	// Settling the invoice should result in a notification carrying the
+	// amount paid and the payment's custom records.
+	records := map[uint64][]byte{65536: []byte("order")}
+	if err := registry.SettleInvoice(rHash, 1000, records); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	_, ntfn = receiveWebhook(t, requests, cfg.Secret, webhookInvoiceSettled)
+	if ntfn.Invoice.AmtPaid != 1000 {
+		t.Fatalf("expected amount paid 1000, got %v",
+			ntfn.Invoice.AmtPaid)
+	}
+	if ntfn.Invoice.CustomRecords["65536"] != "6f72646572" {
+		t.Fatalf("unexpected custom records: %v",
+			ntfn.Invoice.CustomRecords)
+	}
+
+	// Canceling an invoice should also be notified.
+	_, rHash = addTestInvoice(t, registry, time.Now().Add(time.Hour))
+	receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+	if err := registry.CancelInvoice(rHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+	_, ntfn = receiveWebhook(
+		t, requests, cfg.Secret, webhookInvoiceCanceled,
+	)
+	if ntfn.Invoice.PaymentHash != hex.EncodeToString(rHash[:]) {
+		t.Fatalf("notification for wrong invoice %v",
+			ntfn.Invoice.PaymentHash)
+	}
+
+	assertWebhookQueueEmpty(t, cdb)
+}
+
+func TestWebhookDispatcherResume(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	// The server rejects every notification it receives.
+	server, requests := newTestWebhookServer(100)
+	defer server.Close()
+
+	// We'll simulate a restart by queueing a delivery before the
+	// dispatcher is started, which has already failed once.
+	payload := []byte(`{"event":"invoice_settled"}`)
+	err := cdb.AddWebhookDeliveries([]*channeldb.WebhookDelivery{
+		{
+			URL:         server.URL,
+			Payload:     payload,
+			Attempts:    1,
+			NextAttempt: time.Now(),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unable to add delivery: %v", err)
+	}
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	cfg := &webhooksConfig{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond * 10,
+		MaxBackoff:  time.Millisecond * 50,
+	}
+	dispatcher := newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	// The delivery should be attempted twice more, after which it's
+	// dropped from the queue.
+	for i := 0; i < 2; i++ {
+		receiveWebhook(t, requests, cfg.Secret, webhookInvoiceSettled)
+	}
+	assertWebhookQueueEmpty(t, cdb)
+
+	select {
+	case <-requests:
+		t.Fatalf("delivery attempted after being dropped")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+// assertWebhookInvoice asserts that the passed notification is for the
+// invoice with the passed payment hash.
+func assertWebhookInvoice(t *testing.T, ntfn *webhookNotification,
+	rHash chainhash.Hash) {
+
+	if ntfn.Invoice.PaymentHash != hex.EncodeToString(rHash[:]) {
+		t.Fatalf("notification for wrong invoice %v, expected %v",
+			ntfn.Invoice.PaymentHash, rHash)
+	}
+}
+
+func TestWebhookDispatcherBacklog(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	server, requests := newTestWebhookServer(0)
+	defer server.Close()
+
+	// An invoice added before the dispatcher is ever started shouldn't be
+	// notified.
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	addTestInvoice(t, registry, time.Now().Add(time.Hour))
+
+	cfg := &webhooksConfig{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		MaxAttempts: 5,
+		MinBackoff:  time.Millisecond * 10,
+		MaxBackoff:  time.Millisecond * 50,
+	}
+	dispatcher := newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+
+	_, firstHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+	_, ntfn := receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+	assertWebhookInvoice(t, ntfn, firstHash)
+	assertWebhookQueueEmpty(t, cdb)
+
+	if err := dispatcher.Stop(); err != nil {
+		t.Fatalf("unable to stop dispatcher: %v", err)
+	}
+
+	// While the dispatcher is shut down, we'll add a new invoice and
+	// settle the one already notified.
+	_, secondHash := addTestInvoice(t, registry, time.Now().Add(time.Hour))
+	if err := registry.SettleInvoice(firstHash, 1000, nil); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	// Once restarted, the dispatcher should notify both events it missed,
+	// without notifying the first invoice's addition again.
+	dispatcher = newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	_, ntfn = receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+	assertWebhookInvoice(t, ntfn, secondHash)
+	_, ntfn = receiveWebhook(
+		t, requests, cfg.Secret, webhookInvoiceSettled,
+	)
+	assertWebhookInvoice(t, ntfn, firstHash)
+
+	select {
+	case <-requests:
+		t.Fatalf("unexpected notification")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestWebhookDispatcherExpiry(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	server, requests := newTestWebhookServer(0)
+	defer server.Close()
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	registry.expiryInterval = time.Millisecond * 50
+	if err := registry.Start(); err != nil {
+		t.Fatalf("unable to start registry: %v", err)
+	}
+	defer registry.Stop()
+
+	cfg := &webhooksConfig{
+		URLs:        []string{server.URL},
+		Secret:      "secret",
+		MaxAttempts: 5,
+		MinBackoff:  time.Millisecond * 10,
+		MaxBackoff:  time.Millisecond * 50,
+	}
+	dispatcher := newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	_, rHash := addTestInvoice(
+		t, registry, time.Now().Add(time.Millisecond*200),
+	)
+	receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+
+	// Once the invoice expires, an expired notification should be sent.
+	_, ntfn := receiveWebhook(
+		t, requests, cfg.Secret, webhookInvoiceExpired,
+	)
+	assertWebhookInvoice(t, ntfn, rHash)
+	if ntfn.Invoice.State != channeldb.ContractExpired.String() {
+		t.Fatalf("expected expired invoice, got %v",
+			ntfn.Invoice.State)
+	}
+}
+
+func TestWebhookDispatcherFailingURL(t *testing.T) {
+	cdb, cleanUp := makeTestDB(t)
+	defer cleanUp()
+
+	// The first server rejects every notification it receives, while the
+	// second accepts them.
+	failingServer, failingRequests := newTestWebhookServer(100)
+	defer failingServer.Close()
+	server, requests := newTestWebhookServer(0)
+	defer server.Close()
+
+	// We'll queue several deliveries to each server before the dispatcher
+	// is started, such that they're all due at once.
+	var deliveries []*channeldb.WebhookDelivery
+	for i := 0; i < 3; i++ {
+		for _, webhookURL := range []string{
+			failingServer.URL, server.URL,
+		} {
+			delivery := &channeldb.WebhookDelivery{
+				URL:         webhookURL,
+				Payload:     []byte(`{"event":"invoice_added"}`),
+				NextAttempt: time.Now(),
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	if err := cdb.AddWebhookDeliveries(deliveries, nil); err != nil {
+		t.Fatalf("unable to add deliveries: %v", err)
+	}
+
+	registry := newInvoiceRegistry(cdb, nil, time.Minute, 0, false)
+	cfg := &webhooksConfig{
+		URLs:        []string{failingServer.URL, server.URL},
+		Secret:      "secret",
+		MaxAttempts: 5,
+		MinBackoff:  time.Minute,
+		MaxBackoff:  time.Hour,
+	}
+	dispatcher := newWebhookDispatcher(cfg, cdb, registry)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	// All deliveries to the second server should succeed, regardless of
+	// the failing one.
+	for i := 0; i < 3; i++ {
+		receiveWebhook(t, requests, cfg.Secret, webhookInvoiceAdded)
+	}
+
+	// Only the first delivery to the failing server should've been
+	// attempted, with the rest skipped until it's next due.
+	receiveWebhook(t, failingRequests, cfg.Secret, webhookInvoiceAdded)
+	select {
+	case <-failingRequests:
+		t.Fatalf("delivery attempted after prior delivery failed")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	dispatcher := newWebhookDispatcher(&webhooksConfig{
+		MinBackoff: time.Second,
+		MaxBackoff: time.Second * 10,
+	}, nil, nil)
+
+	tests := []struct {
+		attempts uint32
+		backoff  time.Duration
+	}{
+		{attempts: 1, backoff: time.Second},
+		{attempts: 2, backoff: time.Second * 2},
+		{attempts: 3, backoff: time.Second * 4},
+		{attempts: 4, backoff: time.Second * 8},
+		{attempts: 5, backoff: time.Second * 10},
+		{attempts: 100, backoff: time.Second * 10},
+	}
+	for _, test := range tests {
+		backoff := dispatcher.backoff(test.attempts)
+		if backoff != test.backoff {
+			t.Fatalf("expected backoff of %v after %v attempts, "+
+				"got %v", test.backoff, test.attempts, backoff)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package zpay32
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the set of characters used to encode 5-bit groups within
+// a bech32 string.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Gen is the set of generator coefficients used to compute the bech32
+// checksum.
+var bech32Gen = []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// bech32ChecksumLen is the number of 5-bit groups the checksum of a bech32
+// string is made up of.
+const bech32ChecksumLen = 6
+
+// bech32Polymod computes the BCH checksum over the passed values, as
+// specified within BIP 173.
+func bech32Polymod(values []int) int {
+	chk := 1
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ v
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= bech32Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HrpExpand expands the human-readable part into the values used
+// within the checksum computation.
+func bech32HrpExpand(hrp string) []int {
+	v := make([]int, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		v = append(v, int(hrp[i]>>5))
+	}
+	v = append(v, 0)
+	for i := 0; i < len(hrp); i++ {
+		v = append(v, int(hrp[i]&31))
+	}
+	return v
+}
+
+// bech32Checksum computes the checksum over the human-readable part and the
+// 5-bit groups of the data part.
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := bech32HrpExpand(hrp)
+	for _, d := range data {
+		values = append(values, int(d))
+	}
+	values = append(values, make([]int, bech32ChecksumLen)...)
+
+	polymod := bech32Polymod(values) ^ 1
+	checksum := make([]byte, bech32ChecksumLen)
+	for i := range checksum {
+		checksum[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+	return checksum
+}
+
+// bech32VerifyChecksum returns true if the checksum contained within the
+// passed data is valid for the human-readable part.
+func bech32VerifyChecksum(hrp string, data []byte) bool {
+	values := bech32HrpExpand(hrp)
+	for _, d := range data {
+		values = append(values, int(d))
+	}
+	return bech32Polymod(values) == 1
+}
+
+// bech32Encode encodes the passed human-readable part and data part, made up
+// of 5-bit groups, into a bech32 string.
+func bech32Encode(hrp string, data []byte) (string, error) {
+	checksum := bech32Checksum(hrp, data)
+
+	var b bytes.Buffer
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	combined := make([]byte, 0, len(data)+len(checksum))
+	combined = append(combined, data...)
+	combined = append(combined, checksum...)
+	for _, group := range combined {
+		if int(group) >= len(bech32Charset) {
+			return "", fmt.Errorf("invalid data group: %v", group)
+		}
+		b.WriteByte(bech32Charset[group])
+	}
+
+	return b.String(), nil
+}
+
+// bech32Decode decodes the passed bech32 string, returning the human-readable
+// part, and the data part as a series of 5-bit groups with the checksum
+// stripped. Unlike regular bech32 strings, no limit is placed on the total
+// length of the string, as invoices regularly exceed the 90 character limit
+// specified by BIP 173.
+func bech32Decode(bech string) (string, []byte, error) {
+	// Mixed case strings are disallowed, though the string may be all
+	// upper case.
+	if strings.ToLower(bech) != bech && strings.ToUpper(bech) != bech {
+		return "", nil, fmt.Errorf("string contains mixed case")
+	}
+	bech = strings.ToLower(bech)
+
+	// The human-readable part is separated from the data part by the last
+	// occurrence of the separator, as it may appear within the
+	// human-readable part itself.
+	sep := strings.LastIndexByte(bech, '1')
+	if sep < 1 || sep+bech32ChecksumLen+1 > len(bech) {
+		return "", nil, fmt.Errorf("invalid separator index %d", sep)
+	}
+
+	hrp := bech[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid character in "+
+				"human-readable part: %v", hrp[i])
+		}
+	}
+
+	data := make([]byte, 0, len(bech)-sep-1)
+	for i := sep + 1; i < len(bech); i++ {
+		group := strings.IndexByte(bech32Charset, bech[i])
+		if group == -1 {
+			return "", nil, fmt.Errorf("invalid character in "+
+				"data part: %q", bech[i])
+		}
+		data = append(data, byte(group))
+	}
+
+	if !bech32VerifyChecksum(hrp, data) {
+		return "", nil, ErrCheckSumMismatch
+	}
+
+	return hrp, data[:len(data)-bech32ChecksumLen], nil
+}
+
+// convertBits regroups the passed data from groups of fromBits bits into
+// groups of toBits bits. If pad is true, then the final group is padded with
+// zeroes. Otherwise, any incomplete trailing group must consist solely of
+// zero padding, which is discarded.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte,
+	error) {
+
+	var (
+		acc    uint32
+		bits   uint
+		maxVal = uint32(1)<<toBits - 1
+	)
+
+	regrouped := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data range: %v", b)
+		}
+
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			regrouped = append(regrouped, byte(acc>>bits&maxVal))
+		}
+	}
+
+	switch {
+	case pad && bits > 0:
+		regrouped = append(regrouped, byte(acc<<(toBits-bits)&maxVal))
+
+	case !pad && (bits >= fromBits || acc<<(toBits-bits)&maxVal != 0):
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return regrouped, nil
+}
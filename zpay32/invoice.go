@@ -0,0 +1,1014 @@
+package zpay32
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/fastsha256"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// invoicePrefix is the prefix of the human-readable part of every
+	// encoded invoice.
+	invoicePrefix = "ln"
+
+	// mSatPerBtc is the number of milli-satoshis in 1 BTC.
+	mSatPerBtc = 100000000000
+
+	// timestampBase32Len is the number of 5-bit groups used to encode the
+	// timestamp of an invoice.
+	timestampBase32Len = 7
+
+	// signatureBase32Len is the number of 5-bit groups used to encode the
+	// 64-byte signature of an invoice, along with its 1-byte recovery ID.
+	signatureBase32Len = 104
+
+	// hashBase32Len is the number of 5-bit groups used to encode a
+	// 32-byte hash.
+	hashBase32Len = 52
+
+	// pubKeyBase32Len is the number of 5-bit groups used to encode a
+	// 33-byte compressed public key.
+	pubKeyBase32Len = 53
+
+	// maxFieldBase32Len is the maximum number of 5-bit groups the data of
+	// a single tagged field may consist of, as its length is encoded
+	// using two 5-bit groups.
+	maxFieldBase32Len = 1<<10 - 1
+
+	// The following constants are the field types of the tagged fields
+	// which may be present within an invoice.
+	fieldTypeP = 1
+	fieldTypeD = 13
+	fieldTypeN = 19
+	fieldTypeH = 23
+	fieldTypeX = 6
+	fieldTypeF = 9
+	fieldTypeR = 3
+	fieldTypeC = 24
+	fieldType9 = 5
+
+	// MaxDescriptionSize is the maximum size of the description of an
+	// invoice in bytes. Longer descriptions must instead be committed to
+	// using a description hash.
+	MaxDescriptionSize = maxFieldBase32Len * 5 / 8
+
+	// DefaultInvoiceExpiry is the expiry of an invoice which doesn't
+	// explicitly specify one.
+	DefaultInvoiceExpiry = time.Hour
+
+	// DefaultMinFinalCLTVExpiry is the minimum CLTV expiry of the final
+	// hop of a payment for an invoice which doesn't explicitly specify
+	// one.
+	DefaultMinFinalCLTVExpiry = 9
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of a decoded
+	// invoice doesn't match its contents, or the public key of the payee
+	// contained within the invoice.
+	ErrInvalidSignature = errors.New("invalid invoice signature")
+
+	// ErrUnknownNetwork is returned when an invoice is encoded for, or
+	// decoded against, a network without a known human-readable prefix.
+	ErrUnknownNetwork = errors.New("unknown network")
+)
+
+// Invoice represents a signed payment request for a payment within the
+// Lightning Network. In addition to the destination, payment hash and amount
+// carried by the legacy PaymentRequest, an Invoice is bound to a network,
+// carries a timestamp and expiry, and may include a description of the
+// payment along with hints for reaching the destination. The invoice is
+// signed by the destination, allowing its public key to be recovered from the
+// signature when it isn't explicitly included.
+type Invoice struct {
+	// Net specifies what network this invoice is meant for.
+	Net *chaincfg.Params
+
+	// Amount is the optional amount to be paid in satoshis. If nil, then
+	// the payer may choose the amount to pay.
+	Amount *btcutil.Amount
+
+	// Timestamp is the time the invoice was created.
+	Timestamp time.Time
+
+	// PaymentHash is the payment hash to use within the HTLC extended
+	// throughout the payment path to the destination.
+	PaymentHash *[32]byte
+
+	// Destination is the public key of the node to be paid. When decoding
+	// an invoice that doesn't explicitly include the key, it's recovered
+	// from the invoice's signature.
+	Destination *btcec.PublicKey
+
+	// Description is a short description of the purpose of the payment.
+	// Exactly one of Description and DescriptionHash must be set.
+	Description *string
+
+	// DescriptionHash is the SHA256 hash of a description of the purpose
+	// of the payment which is too long to fit within the invoice.
+	DescriptionHash *[32]byte
+
+	// Expiry is the optional duration after the invoice's timestamp at
+	// which the invoice expires. If nil, then DefaultInvoiceExpiry
+	// applies.
+	Expiry *time.Duration
+
+	// MinFinalCLTVExpiry is the optional minimum CLTV expiry the final hop
+	// of the payment must be extended with. If nil, then
+	// DefaultMinFinalCLTVExpiry applies.
+	MinFinalCLTVExpiry *uint64
+
+	// FallbackAddr is an optional on-chain address that may be paid if
+	// the payment can't be made over the Lightning Network.
+	FallbackAddr btcutil.Address
+
+	// RouteHints is an optional set of routes that may be used to reach
+	// the destination. Each route is a chain of hops, with the final hop
+	// of each route connecting directly to the destination.
+	RouteHints [][]HopHint
+
+	// Features is the optional set of feature bits set within the
+	// invoice, in ascending order. Bits are numbered from the least
+	// significant bit of the encoded field.
+	Features []uint16
+}
+
+// MessageSigner is passed to the Encode method of an Invoice, and is used to
+// sign the invoice under the identity key of the node creating it.
+type MessageSigner struct {
+	// SignCompact signs the passed hash with the node's identity key,
+	// returning a 65-byte compact signature, in the format produced by
+	// btcec.SignCompact.
+	SignCompact func(hash []byte) ([]byte, error)
+}
+
+// NewInvoice creates a new Invoice for the passed network, payment hash and
+// timestamp. Any optional fields are set by passing the functional options
+// defined within this package, such as Amount and Description.
+func NewInvoice(net *chaincfg.Params, paymentHash [32]byte,
+	timestamp time.Time, options ...func(*Invoice)) (*Invoice, error) {
+
+	invoice := &Invoice{
+		Net:         net,
+		PaymentHash: &paymentHash,
+		Timestamp:   timestamp,
+	}
+
+	for _, option := range options {
+		option(invoice)
+	}
+
+	if err := validateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// Amount is a functional option that sets the amount to be paid to an
+// invoice.
+func Amount(amt btcutil.Amount) func(*Invoice) {
+	return func(i *Invoice) {
+		i.Amount = &amt
+	}
+}
+
+// Destination is a functional option that explicitly includes the public key
+// of the payee within an invoice. Without it, the key is recovered from the
+// invoice's signature when decoding.
+func Destination(destination *btcec.PublicKey) func(*Invoice) {
+	return func(i *Invoice) {
+		i.Destination = destination
+	}
+}
+
+// Description is a functional option that sets the description of an
+// invoice.
+func Description(description string) func(*Invoice) {
+	return func(i *Invoice) {
+		i.Description = &description
+	}
+}
+
+// DescriptionHash is a functional option that sets the description hash of an
+// invoice.
+func DescriptionHash(descriptionHash [32]byte) func(*Invoice) {
+	return func(i *Invoice) {
+		i.DescriptionHash = &descriptionHash
+	}
+}
+
+// Expiry is a functional option that sets the expiry of an invoice.
+func Expiry(expiry time.Duration) func(*Invoice) {
+	return func(i *Invoice) {
+		i.Expiry = &expiry
+	}
+}
+
+// CLTVExpiry is a functional option that sets the minimum CLTV expiry of the
+// final hop of a payment to an invoice.
+func CLTVExpiry(delta uint64) func(*Invoice) {
+	return func(i *Invoice) {
+		i.MinFinalCLTVExpiry = &delta
+	}
+}
+
+// FallbackAddr is a functional option that sets the on-chain fallback address
+// of an invoice.
+func FallbackAddr(fallbackAddr btcutil.Address) func(*Invoice) {
+	return func(i *Invoice) {
+		i.FallbackAddr = fallbackAddr
+	}
+}
+
+// RouteHint is a functional option that adds a route hint to an invoice.
+func RouteHint(routeHint []HopHint) func(*Invoice) {
+	return func(i *Invoice) {
+		i.RouteHints = append(i.RouteHints, routeHint)
+	}
+}
+
+// Features is a functional option that sets the feature bits of an invoice.
+func Features(features ...uint16) func(*Invoice) {
+	return func(i *Invoice) {
+		i.Features = append(i.Features, features...)
+	}
+}
+
+// ExpiryTime returns the time at which the invoice expires.
+func (invoice *Invoice) ExpiryTime() time.Time {
+	expiry := DefaultInvoiceExpiry
+	if invoice.Expiry != nil {
+		expiry = *invoice.Expiry
+	}
+
+	return invoice.Timestamp.Add(expiry)
+}
+
+// MinFinalCLTVExpiryDelta returns the minimum CLTV expiry of the final hop of
+// a payment to the invoice.
+func (invoice *Invoice) MinFinalCLTVExpiryDelta() uint64 {
+	if invoice.MinFinalCLTVExpiry != nil {
+		return *invoice.MinFinalCLTVExpiry
+	}
+
+	return DefaultMinFinalCLTVExpiry
+}
+
+// Encode encodes the invoice as a bech32 string, signing it using the passed
+// MessageSigner. The human-readable part of the string identifies the network
+// the invoice is meant for, along with its amount, while the data part
+// consists of the timestamp, the tagged fields and finally the signature.
+func (invoice *Invoice) Encode(signer MessageSigner) (string, error) {
+	if err := validateInvoice(invoice); err != nil {
+		return "", err
+	}
+
+	hrp, err := invoiceHrp(invoice.Net)
+	if err != nil {
+		return "", err
+	}
+	if invoice.Amount != nil {
+		hrp += encodeAmount(*invoice.Amount)
+	}
+
+	// The data part starts with the timestamp, followed by each of the
+	// tagged fields.
+	timestamp := uint64(invoice.Timestamp.Unix())
+	if timestamp >= 1<<(5*timestampBase32Len) {
+		return "", fmt.Errorf("timestamp %v too large", timestamp)
+	}
+	data := uintToBase32(timestamp, timestampBase32Len)
+
+	fields, err := encodeTaggedFields(invoice)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, fields...)
+
+	// With the data part assembled, we'll sign the SHA256 of the
+	// human-readable part concatenated with the data part converted to
+	// bytes.
+	hash, err := invoiceSigHash(hrp, data)
+	if err != nil {
+		return "", err
+	}
+	sig, err := signer.SignCompact(hash)
+	if err != nil {
+		return "", err
+	}
+
+	// The compact signature is of the form: header || R || S, where the
+	// header byte encodes the recovery ID. Within the invoice, the
+	// signature is encoded as: R || S || recovery ID.
+	if len(sig) != 65 {
+		return "", fmt.Errorf("invalid compact signature length: %v",
+			len(sig))
+	}
+	var sigBytes [65]byte
+	copy(sigBytes[:], sig[1:])
+	sigBytes[64] = (sig[0] - 27) & 3
+
+	sigBase32, err := convertBits(sigBytes[:], 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, sigBase32...)
+
+	return bech32Encode(hrp, data)
+}
+
+// DecodeInvoice parses the passed bech32 encoded invoice, verifying that it's
+// meant for the passed network, and that its signature is valid. If the
+// invoice doesn't explicitly include the public key of the payee, then it's
+// recovered from the signature.
+func DecodeInvoice(invoice string, net *chaincfg.Params) (*Invoice, error) {
+	hrp, data, err := bech32Decode(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	// The human-readable part must start with the prefix of the network
+	// we're on, optionally followed by the amount of the invoice.
+	expectedHrp, err := invoiceHrp(net)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(hrp, expectedHrp) {
+		return nil, fmt.Errorf("invoice not for current active "+
+			"network '%s'", net.Name)
+	}
+
+	decoded := &Invoice{
+		Net: net,
+	}
+
+	// The network prefixes of some networks are themselves prefixes of
+	// others, so we'll ensure whatever follows the prefix is a valid
+	// amount.
+	if amtStr := hrp[len(expectedHrp):]; amtStr != "" {
+		amt, err := decodeAmount(amtStr)
+		if err != nil {
+			return nil, err
+		}
+		decoded.Amount = &amt
+	}
+
+	if len(data) < timestampBase32Len+signatureBase32Len {
+		return nil, fmt.Errorf("invoice too short")
+	}
+
+	// The signature is the final portion of the data part, so we'll
+	// separate it from the data it signs.
+	sigStart := len(data) - signatureBase32Len
+	sigBase32 := data[sigStart:]
+	data = data[:sigStart]
+
+	timestamp, err := base32ToUint(data[:timestampBase32Len])
+	if err != nil {
+		return nil, err
+	}
+	decoded.Timestamp = time.Unix(int64(timestamp), 0)
+
+	if err := parseTaggedFields(decoded, data[timestampBase32Len:]); err != nil {
+		return nil, err
+	}
+
+	// With the fields parsed, we'll now verify the signature, recovering
+	// the public key of the payee if it wasn't explicitly included.
+	sigBytes, err := convertBits(sigBase32, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigBytes) != 65 {
+		return nil, fmt.Errorf("invalid signature length: %v",
+			len(sigBytes))
+	}
+	recoveryID := sigBytes[64]
+	if recoveryID > 3 {
+		return nil, fmt.Errorf("invalid recovery ID: %v", recoveryID)
+	}
+
+	hash, err := invoiceSigHash(hrp, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The compact signature expected by btcec is of the form: header || R
+	// || S, where the header encodes the recovery ID and that the
+	// recovered key is compressed.
+	var compactSig [65]byte
+	compactSig[0] = 27 + 4 + recoveryID
+	copy(compactSig[1:], sigBytes[:64])
+
+	pubKey, _, err := btcec.RecoverCompact(btcec.S256(), compactSig[:],
+		hash)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	switch {
+	case decoded.Destination == nil:
+		decoded.Destination = pubKey
+
+	case !decoded.Destination.IsEqual(pubKey):
+		return nil, ErrInvalidSignature
+	}
+
+	if err := validateInvoice(decoded); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// DecodePaymentRequest decodes the passed payment request, which may either
+// be a bech32 encoded invoice, or a legacy zbase32 encoded PaymentRequest. As
+// legacy payment requests aren't bound to a network, carry no timestamp and
+// aren't signed, only the destination, payment hash, amount and route hints
+// of the returned Invoice are populated for them.
+func DecodePaymentRequest(payReq string, net *chaincfg.Params) (*Invoice,
+	error) {
+
+	if strings.HasPrefix(strings.ToLower(payReq), invoicePrefix) {
+		return DecodeInvoice(payReq, net)
+	}
+
+	legacyReq, err := Decode(payReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Invoice{
+		Net:         net,
+		Amount:      &legacyReq.Amount,
+		PaymentHash: &legacyReq.PaymentHash,
+		Destination: legacyReq.Destination,
+		RouteHints:  legacyReq.RouteHints,
+	}, nil
+}
+
+// validateInvoice ensures the passed invoice carries all required fields,
+// and that its fields are within their permitted ranges.
+func validateInvoice(invoice *Invoice) error {
+	if invoice.Net == nil {
+		return fmt.Errorf("net params not set")
+	}
+	if invoice.PaymentHash == nil {
+		return fmt.Errorf("no payment hash found")
+	}
+
+	// Exactly one of the description and the description hash must be
+	// set.
+	switch {
+	case invoice.Description != nil && invoice.DescriptionHash != nil:
+		return fmt.Errorf("both description and description hash set")
+
+	case invoice.Description == nil && invoice.DescriptionHash == nil:
+		return fmt.Errorf("neither description nor description hash " +
+			"set")
+	}
+
+	if invoice.Amount != nil && *invoice.Amount <= 0 {
+		return fmt.Errorf("amount must be positive if set, is %v",
+			*invoice.Amount)
+	}
+
+	if invoice.Expiry != nil && *invoice.Expiry < 0 {
+		return fmt.Errorf("negative expiry: %v", *invoice.Expiry)
+	}
+
+	if invoice.FallbackAddr != nil &&
+		!invoice.FallbackAddr.IsForNet(invoice.Net) {
+
+		return fmt.Errorf("fallback address not for network %v",
+			invoice.Net.Name)
+	}
+
+	for i, routeHint := range invoice.RouteHints {
+		if len(routeHint) == 0 {
+			return fmt.Errorf("route hint #%v has no hops", i)
+		}
+	}
+
+	return nil
+}
+
+// invoiceHrp returns the human-readable prefix of invoices meant for the
+// passed network, without any amount.
+func invoiceHrp(net *chaincfg.Params) (string, error) {
+	if net == nil {
+		return "", ErrUnknownNetwork
+	}
+
+	switch net.Net {
+	case wire.MainNet:
+		return invoicePrefix + "bc", nil
+	case wire.TestNet3:
+		return invoicePrefix + "tb", nil
+	case wire.TestNet:
+		return invoicePrefix + "bcrt", nil
+	case wire.SimNet:
+		return invoicePrefix + "sb", nil
+	default:
+		return "", ErrUnknownNetwork
+	}
+}
+
+// invoiceSigHash returns the hash which is signed by the payee of an
+// invoice: the SHA256 of the human-readable part, followed by the data part
+// converted to bytes.
+func invoiceSigHash(hrp string, data []byte) ([]byte, error) {
+	dataBytes, err := convertBits(data, 5, 8, true)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fastsha256.Sum256(append([]byte(hrp), dataBytes...))
+	return hash[:], nil
+}
+
+// encodeAmount encodes the passed amount as it's represented within the
+// human-readable part of an invoice: the amount in BTC, followed by the
+// largest multiplier which allows the amount to be expressed as an integer.
+func encodeAmount(amt btcutil.Amount) string {
+	mSat := uint64(amt) * 1000
+
+	// We'll attempt each multiplier in ascending order of granularity,
+	// using the first one able to represent the amount exactly.
+	multipliers := []struct {
+		suffix string
+		mSat   uint64
+	}{
+		{"m", mSatPerBtc / 1000},
+		{"u", mSatPerBtc / 1000000},
+		{"n", mSatPerBtc / 1000000000},
+	}
+	for _, m := range multipliers {
+		if mSat%m.mSat == 0 {
+			return strconv.FormatUint(mSat/m.mSat, 10) + m.suffix
+		}
+	}
+
+	// As amounts are expressed in whole satoshis, the nano multiplier is
+	// always able to represent the amount, so we'll never reach this
+	// point.
+	return strconv.FormatUint(mSat*10/(mSatPerBtc/1000000000), 10) + "p"
+}
+
+// decodeAmount decodes the amount within the human-readable part of an
+// invoice. As amounts are tracked in satoshis, amounts which can't be
+// expressed in whole satoshis are rejected.
+func decodeAmount(amount string) (btcutil.Amount, error) {
+	if len(amount) < 1 {
+		return 0, fmt.Errorf("amount must be non-empty")
+	}
+
+	// The amount may be suffixed by a multiplier, otherwise it's
+	// expressed in whole BTC.
+	var (
+		numStr     = amount
+		mSatPerNum = uint64(mSatPerBtc)
+		isPico     bool
+	)
+	switch amount[len(amount)-1] {
+	case 'm':
+		mSatPerNum = mSatPerBtc / 1000
+	case 'u':
+		mSatPerNum = mSatPerBtc / 1000000
+	case 'n':
+		mSatPerNum = mSatPerBtc / 1000000000
+	case 'p':
+		isPico = true
+	}
+	if mSatPerNum != mSatPerBtc || isPico {
+		numStr = amount[:len(amount)-1]
+	}
+
+	num, err := strconv.ParseUint(numStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %v", amount, err)
+	}
+
+	var mSat uint64
+	if isPico {
+		// A single pico-bitcoin is a tenth of a milli-satoshi.
+		if num%10 != 0 {
+			return 0, fmt.Errorf("amount %q not a whole number "+
+				"of milli-satoshis", amount)
+		}
+		mSat = num / 10
+	} else {
+		mSat = num * mSatPerNum
+		if num != 0 && mSat/num != mSatPerNum {
+			return 0, fmt.Errorf("amount %q overflows", amount)
+		}
+	}
+
+	if mSat%1000 != 0 {
+		return 0, fmt.Errorf("amount %q not a whole number of "+
+			"satoshis", amount)
+	}
+
+	return btcutil.Amount(mSat / 1000), nil
+}
+
+// encodeTaggedFields encodes the optional fields of the passed invoice as a
+// series of tagged fields. Each field is encoded as: type (5 bits) || length
+// (10 bits) || data.
+func encodeTaggedFields(invoice *Invoice) ([]byte, error) {
+	var fields []byte
+
+	writeField := func(fieldType byte, data []byte) error {
+		if len(data) > maxFieldBase32Len {
+			return fmt.Errorf("field of type %v too long: %v "+
+				"groups", fieldType, len(data))
+		}
+
+		fields = append(fields, fieldType)
+		fields = append(fields, uintToBase32(uint64(len(data)), 2)...)
+		fields = append(fields, data...)
+		return nil
+	}
+	writeBytesField := func(fieldType byte, b []byte) error {
+		base32, err := convertBits(b, 8, 5, true)
+		if err != nil {
+			return err
+		}
+		return writeField(fieldType, base32)
+	}
+
+	if err := writeBytesField(fieldTypeP, invoice.PaymentHash[:]); err != nil {
+		return nil, err
+	}
+
+	if invoice.Description != nil {
+		err := writeBytesField(fieldTypeD, []byte(*invoice.Description))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if invoice.DescriptionHash != nil {
+		err := writeBytesField(fieldTypeH, invoice.DescriptionHash[:])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if invoice.Destination != nil {
+		err := writeBytesField(
+			fieldTypeN, invoice.Destination.SerializeCompressed(),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if invoice.Expiry != nil {
+		seconds := uint64(invoice.Expiry.Seconds())
+		if err := writeField(fieldTypeX, minimalUintToBase32(seconds)); err != nil {
+			return nil, err
+		}
+	}
+
+	if invoice.MinFinalCLTVExpiry != nil {
+		delta := minimalUintToBase32(*invoice.MinFinalCLTVExpiry)
+		if err := writeField(fieldTypeC, delta); err != nil {
+			return nil, err
+		}
+	}
+
+	if invoice.FallbackAddr != nil {
+		fallback, err := encodeFallbackAddr(invoice.FallbackAddr)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeField(fieldTypeF, fallback); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each route hint is encoded within its own field, consisting of the
+	// concatenation of its hops.
+	for _, routeHint := range invoice.RouteHints {
+		var hops []byte
+		for _, hint := range routeHint {
+			hops = appendHopHint(hops, &hint)
+		}
+		if err := writeBytesField(fieldTypeR, hops); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(invoice.Features) != 0 {
+		if err := writeField(fieldType9, encodeFeatures(invoice.Features)); err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+// parseTaggedFields parses the tagged fields of an invoice, populating the
+// passed invoice with their contents. As required by the specification,
+// fields of an unknown type, and known fields with an unexpected length, are
+// skipped.
+func parseTaggedFields(invoice *Invoice, fields []byte) error {
+	for len(fields) > 0 {
+		if len(fields) < 3 {
+			return fmt.Errorf("truncated tagged field")
+		}
+
+		fieldType := fields[0]
+		dataLen, err := base32ToUint(fields[1:3])
+		if err != nil {
+			return err
+		}
+		if uint64(len(fields)-3) < dataLen {
+			return fmt.Errorf("tagged field of type %v is "+
+				"truncated", fieldType)
+		}
+		data := fields[3 : 3+dataLen]
+		fields = fields[3+dataLen:]
+
+		switch fieldType {
+		case fieldTypeP:
+			if invoice.PaymentHash != nil ||
+				len(data) != hashBase32Len {
+
+				continue
+			}
+			hash, err := parseHash(data)
+			if err != nil {
+				return err
+			}
+			invoice.PaymentHash = hash
+
+		case fieldTypeD:
+			if invoice.Description != nil {
+				continue
+			}
+			b, err := convertBits(data, 5, 8, false)
+			if err != nil {
+				return err
+			}
+			description := string(b)
+			invoice.Description = &description
+
+		case fieldTypeH:
+			if invoice.DescriptionHash != nil ||
+				len(data) != hashBase32Len {
+
+				continue
+			}
+			hash, err := parseHash(data)
+			if err != nil {
+				return err
+			}
+			invoice.DescriptionHash = hash
+
+		case fieldTypeN:
+			if invoice.Destination != nil ||
+				len(data) != pubKeyBase32Len {
+
+				continue
+			}
+			b, err := convertBits(data, 5, 8, false)
+			if err != nil {
+				return err
+			}
+			invoice.Destination, err = btcec.ParsePubKey(
+				b, btcec.S256(),
+			)
+			if err != nil {
+				return err
+			}
+
+		case fieldTypeX:
+			if invoice.Expiry != nil {
+				continue
+			}
+			seconds, err := base32ToUint(data)
+			if err != nil {
+				return err
+			}
+			expiry := time.Duration(seconds) * time.Second
+			invoice.Expiry = &expiry
+
+		case fieldTypeC:
+			if invoice.MinFinalCLTVExpiry != nil {
+				continue
+			}
+			delta, err := base32ToUint(data)
+			if err != nil {
+				return err
+			}
+			invoice.MinFinalCLTVExpiry = &delta
+
+		case fieldTypeF:
+			if invoice.FallbackAddr != nil {
+				continue
+			}
+			addr, err := parseFallbackAddr(data, invoice.Net)
+			if err != nil {
+				return err
+			}
+			invoice.FallbackAddr = addr
+
+		case fieldTypeR:
+			b, err := convertBits(data, 5, 8, false)
+			if err != nil {
+				return err
+			}
+			if len(b) == 0 || len(b)%hopHintSize != 0 {
+				return fmt.Errorf("invalid route hint "+
+					"length: %v", len(b))
+			}
+
+			hintReader := bytes.NewReader(b)
+			routeHint := make([]HopHint, 0, len(b)/hopHintSize)
+			for hintReader.Len() > 0 {
+				hint, err := decodeHopHint(hintReader)
+				if err != nil {
+					return err
+				}
+				routeHint = append(routeHint, *hint)
+			}
+			invoice.RouteHints = append(invoice.RouteHints, routeHint)
+
+		case fieldType9:
+			if invoice.Features != nil {
+				continue
+			}
+			invoice.Features = decodeFeatures(data)
+
+		// Fields of an unknown type are ignored.
+		default:
+		}
+	}
+
+	return nil
+}
+
+// parseHash parses a 32-byte hash from the passed 5-bit groups.
+func parseHash(data []byte) (*[32]byte, error) {
+	b, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 32 {
+		return nil, fmt.Errorf("invalid hash length: %v", len(b))
+	}
+
+	var hash [32]byte
+	copy(hash[:], b)
+	return &hash, nil
+}
+
+// encodeFallbackAddr encodes the passed fallback address as: version (5 bits)
+// || hash. The version is the witness version for native segwit addresses,
+// 17 for P2PKH and 18 for P2SH addresses.
+func encodeFallbackAddr(addr btcutil.Address) ([]byte, error) {
+	var version byte
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		version = 17
+	case *btcutil.AddressScriptHash:
+		version = 18
+	case *btcutil.AddressWitnessPubKeyHash,
+		*btcutil.AddressWitnessScriptHash:
+
+		version = 0
+	default:
+		return nil, fmt.Errorf("unsupported fallback address type: "+
+			"%T", addr)
+	}
+
+	hash, err := convertBits(addr.ScriptAddress(), 8, 5, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{version}, hash...), nil
+}
+
+// parseFallbackAddr parses a fallback address encoded within an invoice for
+// the passed network.
+func parseFallbackAddr(data []byte, net *chaincfg.Params) (btcutil.Address,
+	error) {
+
+	if len(data) < 1 {
+		return nil, fmt.Errorf("empty fallback address field")
+	}
+
+	hash, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version := data[0]; version {
+	case 0:
+		switch len(hash) {
+		case 20:
+			return btcutil.NewAddressWitnessPubKeyHash(hash, net)
+		case 32:
+			return btcutil.NewAddressWitnessScriptHashFromHash(
+				hash, net,
+			)
+		default:
+			return nil, fmt.Errorf("unknown witness program "+
+				"length: %v", len(hash))
+		}
+
+	case 17:
+		return btcutil.NewAddressPubKeyHash(hash, net)
+
+	case 18:
+		return btcutil.NewAddressScriptHashFromHash(hash, net)
+
+	default:
+		return nil, fmt.Errorf("unknown fallback address version: %v",
+			version)
+	}
+}
+
+// encodeFeatures encodes the passed feature bits as a big-endian bit field,
+// split into 5-bit groups.
+func encodeFeatures(features []uint16) []byte {
+	var maxBit uint16
+	for _, bit := range features {
+		if bit > maxBit {
+			maxBit = bit
+		}
+	}
+
+	data := make([]byte, int(maxBit)/5+1)
+	for _, bit := range features {
+		data[len(data)-1-int(bit)/5] |= 1 << (bit % 5)
+	}
+
+	return data
+}
+
+// decodeFeatures decodes the feature bits set within the passed big-endian
+// bit field of 5-bit groups, returning them in ascending order.
+func decodeFeatures(data []byte) []uint16 {
+	features := make([]uint16, 0)
+	for i := len(data) - 1; i >= 0; i-- {
+		for j := uint16(0); j < 5; j++ {
+			if data[i]&(1<<j) != 0 {
+				bit := uint16(len(data)-1-i)*5 + j
+				features = append(features, bit)
+			}
+		}
+	}
+
+	return features
+}
+
+// uintToBase32 encodes the passed integer as a big-endian series of numGroups
+// 5-bit groups.
+func uintToBase32(num uint64, numGroups int) []byte {
+	data := make([]byte, numGroups)
+	for i := numGroups - 1; i >= 0; i-- {
+		data[i] = byte(num & 31)
+		num >>= 5
+	}
+	return data
+}
+
+// minimalUintToBase32 encodes the passed integer as a big-endian series of
+// 5-bit groups, using the minimal number of groups.
+func minimalUintToBase32(num uint64) []byte {
+	numGroups := 1
+	for n := num >> 5; n > 0; n >>= 5 {
+		numGroups++
+	}
+	return uintToBase32(num, numGroups)
+}
+
+// base32ToUint decodes a big-endian integer from the passed 5-bit groups.
+func base32ToUint(data []byte) (uint64, error) {
+	// A uint64 can hold at most 12 full 5-bit groups, along with 4 bits
+	// of a 13th.
+	if len(data) > 13 || (len(data) == 13 && data[0] > 15) {
+		return 0, fmt.Errorf("integer of %v groups overflows",
+			len(data))
+	}
+
+	var num uint64
+	for _, group := range data {
+		num = num<<5 | uint64(group)
+	}
+	return num, nil
+}
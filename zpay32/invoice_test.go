@@ -0,0 +1,358 @@
+package zpay32
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	testMessageSigner = MessageSigner{
+		SignCompact: func(hash []byte) ([]byte, error) {
+			privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+				testPrivKey)
+			return btcec.SignCompact(btcec.S256(), privKey, hash,
+				true)
+		},
+	}
+
+	testDescriptionHash = [32]byte{
+		0x3c, 0xd7, 0x8f, 0x1a, 0x6e, 0x8e, 0x27, 0x2a,
+		0xd5, 0xb1, 0x5c, 0x9e, 0x34, 0x09, 0x56, 0x1b,
+		0x04, 0xa1, 0xe4, 0x3a, 0xdb, 0x83, 0x15, 0x7d,
+		0x0b, 0x6f, 0x15, 0x1b, 0x2e, 0xb0, 0x6a, 0x52,
+	}
+
+	testTimestamp = time.Unix(1496314658, 0)
+)
+
+// TestInvoiceEncodeDecode tests that invoices carrying various combinations
+// of optional fields survive a round trip through encoding and decoding,
+// with the payee's public key recovered from the signature when it isn't
+// explicitly included.
+func TestInvoiceEncodeDecode(t *testing.T) {
+	fallbackAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		testPubKey.SerializeCompressed()[1:21], &chaincfg.TestNet3Params,
+	)
+	if err != nil {
+		t.Fatalf("unable to create fallback addr: %v", err)
+	}
+	routeHint := []HopHint{
+		{
+			NodeID:                    testPubKey,
+			ChannelID:                 0x0102030405060708,
+			FeeBaseMSat:               1,
+			FeeProportionalMillionths: 20,
+			CLTVExpiryDelta:           3,
+		},
+		{
+			NodeID:                    testPubKey,
+			ChannelID:                 0x030405060708090a,
+			FeeBaseMSat:               2,
+			FeeProportionalMillionths: 30,
+			CLTVExpiryDelta:           4,
+		},
+	}
+
+	tests := []struct {
+		net     *chaincfg.Params
+		options []func(*Invoice)
+	}{
+		// An invoice without an amount, carrying only a description.
+		{
+			net: &chaincfg.MainNetParams,
+			options: []func(*Invoice){
+				Description("coffee"),
+			},
+		},
+
+		// An invoice requiring the milli multiplier for its amount,
+		// which explicitly includes the payee's public key.
+		{
+			net: &chaincfg.TestNet3Params,
+			options: []func(*Invoice){
+				Amount(btcutil.Amount(250000)),
+				Description("1 cup coffee"),
+				Destination(testPubKey),
+			},
+		},
+
+		// An invoice carrying all optional fields, with an amount
+		// requiring the nano multiplier.
+		{
+			net: &chaincfg.TestNet3Params,
+			options: []func(*Invoice){
+				Amount(btcutil.Amount(1234567)),
+				DescriptionHash(testDescriptionHash),
+				Expiry(time.Minute * 30),
+				CLTVExpiry(144),
+				FallbackAddr(fallbackAddr),
+				RouteHint(routeHint),
+				RouteHint(routeHint[1:]),
+				Features(1, 9, 15),
+			},
+		},
+
+		// An invoice for the simulation network with an amount of
+		// whole bitcoins.
+		{
+			net: &chaincfg.SimNetParams,
+			options: []func(*Invoice){
+				Amount(btcutil.SatoshiPerBitcoin * 2),
+				Description(strings.Repeat("a", 500)),
+			},
+		},
+	}
+
+	for i, test := range tests {
+		invoice, err := NewInvoice(
+			test.net, testPayHash, testTimestamp, test.options...,
+		)
+		if err != nil {
+			t.Fatalf("test #%v: unable to create invoice: %v", i, err)
+		}
+
+		encoded, err := invoice.Encode(testMessageSigner)
+		if err != nil {
+			t.Fatalf("test #%v: unable to encode invoice: %v", i, err)
+		}
+
+		decoded, err := DecodeInvoice(encoded, test.net)
+		if err != nil {
+			t.Fatalf("test #%v: unable to decode invoice: %v", i, err)
+		}
+
+		// The public key of the payee should've been recovered if it
+		// wasn't included, so we'll set it on the original in order
+		// to compare the two.
+		if !decoded.Destination.IsEqual(testPubKey) {
+			t.Fatalf("test #%v: destination mismatch: expected %x, "+
+				"got %x", i, testPubKey.SerializeCompressed(),
+				decoded.Destination.SerializeCompressed())
+		}
+		invoice.Destination = nil
+		decoded.Destination = nil
+		for _, routeHint := range decoded.RouteHints {
+			for j := range routeHint {
+				routeHint[j].NodeID = testPubKey
+			}
+		}
+
+		if !reflect.DeepEqual(invoice, decoded) {
+			t.Fatalf("test #%v: invoice mismatch: expected %v, "+
+				"got %v", i, spew.Sdump(invoice),
+				spew.Sdump(decoded))
+		}
+
+		// The invoice should also be decodable as a generic payment
+		// request.
+		payReq, err := DecodePaymentRequest(encoded, test.net)
+		if err != nil {
+			t.Fatalf("test #%v: unable to decode payment request: %v",
+				i, err)
+		}
+		if *payReq.PaymentHash != testPayHash {
+			t.Fatalf("test #%v: payment hash mismatch", i)
+		}
+	}
+}
+
+// TestDecodeInvoiceTestVector tests that we're able to decode an invoice
+// produced by another implementation, recovering the public key of the payee
+// from its signature.
+func TestDecodeInvoiceTestVector(t *testing.T) {
+	const invoice = "lnbc1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5" +
+		"rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rf" +
+		"wvs8qun0dfjkxaq8rkx3yf5tcsyz3d73gafnh3cax9rn449d9p5uxz9ezhhyp" +
+		"d0elx87sjle52x86fux2ypatgddc6k63n7erqz25le42c4u4ecky03ylcqca784w"
+
+	decoded, err := DecodeInvoice(invoice, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	const expectedPub = "03e7156ae33b0a208d0744199163177e909e80176e55d97a2f" +
+		"221ede0f934dd9ad"
+	pub := hex.EncodeToString(decoded.Destination.SerializeCompressed())
+	if pub != expectedPub {
+		t.Fatalf("expected destination %v, got %v", expectedPub, pub)
+	}
+
+	switch {
+	case decoded.Amount != nil:
+		t.Fatalf("expected no amount, got %v", *decoded.Amount)
+
+	case decoded.Timestamp.Unix() != 1496314658:
+		t.Fatalf("unexpected timestamp: %v", decoded.Timestamp.Unix())
+
+	case decoded.Description == nil ||
+		*decoded.Description != "Please consider supporting this project":
+
+		t.Fatalf("unexpected description: %v", decoded.Description)
+
+	case decoded.ExpiryTime() != decoded.Timestamp.Add(DefaultInvoiceExpiry):
+		t.Fatalf("unexpected expiry time: %v", decoded.ExpiryTime())
+
+	case decoded.MinFinalCLTVExpiryDelta() != DefaultMinFinalCLTVExpiry:
+		t.Fatalf("unexpected min final cltv expiry: %v",
+			decoded.MinFinalCLTVExpiryDelta())
+	}
+
+	const expectedHash = "0001020304050607080900010203040506070809000102" +
+		"030405060708090102"
+	if hash := hex.EncodeToString(decoded.PaymentHash[:]); hash != expectedHash {
+		t.Fatalf("expected payment hash %v, got %v", expectedHash, hash)
+	}
+}
+
+// TestDecodeInvoiceFailures tests that invoices which have been tampered
+// with, or are meant for another network, are rejected.
+func TestDecodeInvoiceFailures(t *testing.T) {
+	invoice, err := NewInvoice(
+		&chaincfg.TestNet3Params, testPayHash, testTimestamp,
+		Amount(btcutil.Amount(1000)), Description("coffee"),
+		Destination(testPubKey),
+	)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	encoded, err := invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	// An invoice meant for testnet shouldn't be accepted on mainnet.
+	if _, err := DecodeInvoice(encoded, &chaincfg.MainNetParams); err == nil {
+		t.Fatalf("invoice for wrong network accepted")
+	}
+
+	// Modifying a single character should cause the checksum to fail.
+	tampered := []byte(encoded)
+	if tampered[20] == 'q' {
+		tampered[20] = 'p'
+	} else {
+		tampered[20] = 'q'
+	}
+	_, err = DecodeInvoice(string(tampered), &chaincfg.TestNet3Params)
+	if err != ErrCheckSumMismatch {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	// An invoice signed by a key other than the one it includes should
+	// be rejected, even if the checksum is valid.
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	invoice.Destination = otherKey.PubKey()
+	encoded, err = invoice.Encode(testMessageSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+	_, err = DecodeInvoice(encoded, &chaincfg.TestNet3Params)
+	if err != ErrInvalidSignature {
+		t.Fatalf("expected invalid signature, got %v", err)
+	}
+
+	// Finally, an invoice must carry exactly one of a description and a
+	// description hash.
+	_, err = NewInvoice(
+		&chaincfg.TestNet3Params, testPayHash, testTimestamp,
+	)
+	if err == nil {
+		t.Fatalf("invoice without description created")
+	}
+	_, err = NewInvoice(
+		&chaincfg.TestNet3Params, testPayHash, testTimestamp,
+		Description("coffee"), DescriptionHash(testDescriptionHash),
+	)
+	if err == nil {
+		t.Fatalf("invoice with description and hash created")
+	}
+}
+
+// TestDecodePaymentRequestLegacy tests that legacy zbase32 payment requests
+// are still decoded by DecodePaymentRequest.
+func TestDecodePaymentRequestLegacy(t *testing.T) {
+	legacyReq := &PaymentRequest{
+		Destination: testPubKey,
+		PaymentHash: testPayHash,
+		Amount:      btcutil.Amount(50000),
+	}
+	encoded, err := Encode(legacyReq)
+	if err != nil {
+		t.Fatalf("unable to encode payment request: %v", err)
+	}
+
+	decoded, err := DecodePaymentRequest(encoded, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatalf("unable to decode payment request: %v", err)
+	}
+
+	switch {
+	case !decoded.Destination.IsEqual(testPubKey):
+		t.Fatalf("destination mismatch")
+
+	case *decoded.PaymentHash != testPayHash:
+		t.Fatalf("payment hash mismatch")
+
+	case decoded.Amount == nil || *decoded.Amount != legacyReq.Amount:
+		t.Fatalf("amount mismatch: expected %v, got %v",
+			legacyReq.Amount, decoded.Amount)
+	}
+}
+
+// TestInvoiceAmountEncoding tests the encoding of amounts within the
+// human-readable part of an invoice.
+func TestInvoiceAmountEncoding(t *testing.T) {
+	tests := []struct {
+		amt     btcutil.Amount
+		encoded string
+	}{
+		{btcutil.SatoshiPerBitcoin, "1000m"},
+		{100000, "1m"},
+		{2500, "25u"},
+		{1, "10n"},
+		{1234567, "12345670n"},
+	}
+
+	for _, test := range tests {
+		encoded := encodeAmount(test.amt)
+		if encoded != test.encoded {
+			t.Fatalf("expected %v to encode to %v, got %v",
+				test.amt, test.encoded, encoded)
+		}
+
+		decoded, err := decodeAmount(encoded)
+		if err != nil {
+			t.Fatalf("unable to decode %v: %v", encoded, err)
+		}
+		if decoded != test.amt {
+			t.Fatalf("expected %v to decode to %v, got %v",
+				encoded, test.amt, decoded)
+		}
+	}
+
+	// Amounts expressed in whole bitcoins and pico-bitcoins should be
+	// accepted, while amounts below a satoshi should be rejected.
+	if amt, err := decodeAmount("2"); err != nil ||
+		amt != 2*btcutil.SatoshiPerBitcoin {
+
+		t.Fatalf("unable to decode whole bitcoins: %v, %v", amt, err)
+	}
+	if amt, err := decodeAmount("10000p"); err != nil || amt != 1 {
+		t.Fatalf("unable to decode pico-bitcoins: %v, %v", amt, err)
+	}
+	for _, amt := range []string{"1p", "1n", "", "m", "1x"} {
+		if _, err := decodeAmount(amt); err == nil {
+			t.Fatalf("invalid amount %q accepted", amt)
+		}
+	}
+}
@@ -7,22 +7,61 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"math"
 
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 	"github.com/tv42/zbase32"
 )
 
-// invoiceSize is the size of an encoded invoice without the added check-sum.
-// The size of broken down as follows: 33-bytes (destination pub key), 32-bytes
-// (payment hash), 8-bytes for the payment amount in satoshis.
+// invoiceSize is the size of an encoded invoice without the added check-sum
+// or any route hints. The size of broken down as follows: 33-bytes
+// (destination pub key), 32-bytes (payment hash), 8-bytes for the payment
+// amount in satoshis.
 const invoiceSize = 33 + 32 + 8
 
+// checkSumSize is the size of the crc32 checksum appended to each encoded
+// invoice.
+const checkSumSize = 4
+
+// hopHintSize is the size of a single encoded hop hint. The size is broken
+// down as follows: 33-bytes (node pub key), 8-bytes (short channel ID),
+// 4-bytes (base fee), 4-bytes (fee rate), 2-bytes (time lock delta).
+const hopHintSize = 33 + 8 + 4 + 4 + 2
+
+// MaxRouteHints is the maximum number of route hints that can be encoded
+// within a payment request, as well as the maximum number of hops within a
+// single route hint.
+const MaxRouteHints = math.MaxUint8
+
 // ErrCheckSumMismatch is returned byt he Decode function fi when
 // decoding an encoded invoice, the checksum doesn't match indicating
 // an error somewhere in the bitstream.
 var ErrCheckSumMismatch = errors.New("the checksum is incorrect")
 
+// HopHint is a routing hint that describes a channel which may not be known to
+// the payer, such as a private channel. The hint contains the information
+// required to route a payment over the channel towards the destination.
+type HopHint struct {
+	// NodeID is the public key of the node at the start of the channel.
+	NodeID *btcec.PublicKey
+
+	// ChannelID is the short channel ID of the channel.
+	ChannelID uint64
+
+	// FeeBaseMSat is the base fee charged by the node to forward a
+	// payment over the channel.
+	FeeBaseMSat uint32
+
+	// FeeProportionalMillionths is the fee rate charged by the node to
+	// forward a payment over the channel.
+	FeeProportionalMillionths uint32
+
+	// CLTVExpiryDelta is the time lock delta the node requires for the
+	// channel.
+	CLTVExpiryDelta uint16
+}
+
 // PaymentRequest is a bare-bones invoice for a payment within the Lightning
 // Network.  With the details of the invoice, the sender has all the data
 // necessary to send a payment to the recipient.
@@ -37,6 +76,11 @@ type PaymentRequest struct {
 	// Amount is the amount to be sent to the destination expressed in
 	// satoshis.
 	Amount btcutil.Amount
+
+	// RouteHints is an optional set of routes that may be used to reach
+	// the destination. Each route is a chain of hops, with the final hop
+	// of each route connecting directly to the destination.
+	RouteHints [][]HopHint
 }
 
 // castagnoli is an initialized crc32 checksum generated which Castagnoli's
@@ -52,10 +96,16 @@ func checkSum(data []byte) []byte {
 }
 
 // Encode encodes the passed payment request using zbase32 with an added 4-byte
-// crc32 checksum. The resulting encoding is 77-bytes long and consists of 124
-// ASCII characters.
+// crc32 checksum. Without any route hints, the resulting encoding is 77-bytes
+// long and consists of 124 ASCII characters. Any route hints are appended to
+// the payment request prior to the checksum.
 // TODO(roasbeef): add version byte?
-func Encode(payReq *PaymentRequest) string {
+func Encode(payReq *PaymentRequest) (string, error) {
+	if len(payReq.RouteHints) > MaxRouteHints {
+		return "", fmt.Errorf("payment request has %v route hints, "+
+			"max is %v", len(payReq.RouteHints), MaxRouteHints)
+	}
+
 	var (
 		invoiceBytes [invoiceSize]byte
 		n            int
@@ -67,12 +117,49 @@ func Encode(payReq *PaymentRequest) string {
 	n += copy(invoiceBytes[n:], payReq.PaymentHash[:])
 	binary.BigEndian.PutUint64(invoiceBytes[n:], uint64(payReq.Amount))
 
+	b := invoiceBytes[:]
+
+	// If the payment request carries any route hints, then they're
+	// appended in the form: num_routes || (num_hops || hops)*
+	if len(payReq.RouteHints) != 0 {
+		b = append(b, uint8(len(payReq.RouteHints)))
+		for _, routeHint := range payReq.RouteHints {
+			if len(routeHint) == 0 || len(routeHint) > MaxRouteHints {
+				return "", fmt.Errorf("route hint has %v hops, "+
+					"must be between 1 and %v",
+					len(routeHint), MaxRouteHints)
+			}
+
+			b = append(b, uint8(len(routeHint)))
+			for _, hint := range routeHint {
+				b = appendHopHint(b, &hint)
+			}
+		}
+	}
+
 	// Next, we append the checksum to the end of the buffer which covers
 	// the serialized payment request.
-	b := append(invoiceBytes[:], checkSum(invoiceBytes[:])...)
+	b = append(b, checkSum(b)...)
 
 	// Finally encode the raw bytes as a zbase32 encoded string.
-	return zbase32.EncodeToString(b)
+	return zbase32.EncodeToString(b), nil
+}
+
+// appendHopHint appends the serialization of the passed hop hint to b,
+// returning the extended slice.
+func appendHopHint(b []byte, hint *HopHint) []byte {
+	var hintBytes [hopHintSize]byte
+
+	n := copy(hintBytes[:], hint.NodeID.SerializeCompressed())
+	binary.BigEndian.PutUint64(hintBytes[n:], hint.ChannelID)
+	n += 8
+	binary.BigEndian.PutUint32(hintBytes[n:], hint.FeeBaseMSat)
+	n += 4
+	binary.BigEndian.PutUint32(hintBytes[n:], hint.FeeProportionalMillionths)
+	n += 4
+	binary.BigEndian.PutUint16(hintBytes[n:], hint.CLTVExpiryDelta)
+
+	return append(b, hintBytes[:]...)
 }
 
 // Decode attempts to decode the zbase32 encoded payment request. If the
@@ -90,14 +177,20 @@ func Decode(payData string) (*PaymentRequest, error) {
 		return nil, err
 	}
 
+	if len(payReqBytes) < invoiceSize+checkSumSize {
+		return nil, fmt.Errorf("encoded payment request is too short: "+
+			"%v bytes", len(payReqBytes))
+	}
+
 	// With the bytes decoded, we first verify the checksum to ensure the
 	// payment request wasn't altered in its decoded form.
-	invoiceBytes := payReqBytes[:invoiceSize]
+	sumStart := len(payReqBytes) - checkSumSize
+	invoiceBytes := payReqBytes[:sumStart]
 	generatedSum := checkSum(invoiceBytes)
 
 	// If the checksums don't match, then we return an error to the
 	// possibly detected error.
-	encodedSum := payReqBytes[invoiceSize:]
+	encodedSum := payReqBytes[sumStart:]
 	if !bytes.Equal(encodedSum, generatedSum) {
 		return nil, ErrCheckSumMismatch
 	}
@@ -106,7 +199,26 @@ func Decode(payData string) (*PaymentRequest, error) {
 	// request and can safely decode the payReq, passing it back up to the
 	// caller.
 	invoiceReader := bytes.NewReader(invoiceBytes)
-	return decodePaymentRequest(invoiceReader)
+	payReq, err := decodePaymentRequest(invoiceReader)
+	if err != nil {
+		return nil, err
+	}
+
+	// If any bytes remain, then the payment request also carries a set of
+	// route hints.
+	if invoiceReader.Len() != 0 {
+		payReq.RouteHints, err = decodeRouteHints(invoiceReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if invoiceReader.Len() != 0 {
+		return nil, fmt.Errorf("%v trailing bytes within payment "+
+			"request", invoiceReader.Len())
+	}
+
+	return payReq, nil
 }
 
 func decodePaymentRequest(r io.Reader) (*PaymentRequest, error) {
@@ -137,3 +249,57 @@ func decodePaymentRequest(r io.Reader) (*PaymentRequest, error) {
 
 	return i, nil
 }
+
+// decodeRouteHints decodes the set of route hints encoded within a payment
+// request from the passed io.Reader.
+func decodeRouteHints(r io.Reader) ([][]HopHint, error) {
+	var numRoutes [1]byte
+	if _, err := io.ReadFull(r, numRoutes[:]); err != nil {
+		return nil, err
+	}
+
+	routeHints := make([][]HopHint, 0, numRoutes[0])
+	for i := 0; i < int(numRoutes[0]); i++ {
+		var numHops [1]byte
+		if _, err := io.ReadFull(r, numHops[:]); err != nil {
+			return nil, err
+		}
+		if numHops[0] == 0 {
+			return nil, fmt.Errorf("route hint #%v has no hops", i)
+		}
+
+		routeHint := make([]HopHint, 0, numHops[0])
+		for j := 0; j < int(numHops[0]); j++ {
+			hint, err := decodeHopHint(r)
+			if err != nil {
+				return nil, err
+			}
+			routeHint = append(routeHint, *hint)
+		}
+
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
+
+// decodeHopHint decodes a single hop hint from the passed io.Reader.
+func decodeHopHint(r io.Reader) (*HopHint, error) {
+	var hintBytes [hopHintSize]byte
+	if _, err := io.ReadFull(r, hintBytes[:]); err != nil {
+		return nil, err
+	}
+
+	nodeID, err := btcec.ParsePubKey(hintBytes[:33], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return &HopHint{
+		NodeID:                    nodeID,
+		ChannelID:                 binary.BigEndian.Uint64(hintBytes[33:]),
+		FeeBaseMSat:               binary.BigEndian.Uint32(hintBytes[41:]),
+		FeeProportionalMillionths: binary.BigEndian.Uint32(hintBytes[45:]),
+		CLTVExpiryDelta:           binary.BigEndian.Uint16(hintBytes[49:]),
+	}, nil
+}
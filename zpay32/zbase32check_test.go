@@ -2,6 +2,7 @@ package zpay32
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -50,7 +51,10 @@ func TestEncodeDecode(t *testing.T) {
 	for i, test := range tests {
 		// First ensure encoding the test payment request string in the
 		// specified encoding.
-		encodedReq := Encode(&test.payReq)
+		encodedReq, err := Encode(&test.payReq)
+		if err != nil {
+			t.Fatalf("unable to encode invoice #%v: %v", i, err)
+		}
 		if encodedReq != test.encoding {
 			t.Fatalf("encoding mismatch for %v: expected %v got %v",
 				spew.Sdump(test.payReq), test.encoding, encodedReq)
@@ -81,6 +85,85 @@ func TestEncodeDecode(t *testing.T) {
 	}
 }
 
+// withoutCurves returns a copy of the passed payment request, with the curves
+// of all its public keys nil'd out.
+func withoutCurves(payReq *PaymentRequest) *PaymentRequest {
+	stripCurve := func(pub *btcec.PublicKey) *btcec.PublicKey {
+		stripped := *pub
+		stripped.Curve = nil
+		return &stripped
+	}
+
+	reqCopy := *payReq
+	reqCopy.Destination = stripCurve(payReq.Destination)
+	reqCopy.RouteHints = make([][]HopHint, len(payReq.RouteHints))
+	for i, routeHint := range payReq.RouteHints {
+		reqCopy.RouteHints[i] = make([]HopHint, len(routeHint))
+		for j, hint := range routeHint {
+			hint.NodeID = stripCurve(hint.NodeID)
+			reqCopy.RouteHints[i][j] = hint
+		}
+	}
+
+	return &reqCopy
+}
+
+func TestEncodeDecodeRouteHints(t *testing.T) {
+	_, hintPubKey := btcec.PrivKeyFromBytes(btcec.S256(), testPayHash[:])
+
+	payReq := &PaymentRequest{
+		Destination: testPubKey,
+		PaymentHash: testPayHash,
+		Amount:      btcutil.Amount(50000),
+		RouteHints: [][]HopHint{
+			{
+				{
+					NodeID:                    hintPubKey,
+					ChannelID:                 12345,
+					FeeBaseMSat:               1000,
+					FeeProportionalMillionths: 10,
+					CLTVExpiryDelta:           144,
+				},
+			},
+			{
+				{
+					NodeID:    testPubKey,
+					ChannelID: 54321,
+				},
+				{
+					NodeID:          hintPubKey,
+					ChannelID:       99999,
+					CLTVExpiryDelta: 6,
+				},
+			},
+		},
+	}
+
+	encodedReq, err := Encode(payReq)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+	decodedReq, err := Decode(encodedReq)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	// The curves of the public keys are nil'd out within copies of both
+	// requests so that they can be compared directly, without modifying
+	// the keys shared with other tests.
+	expectedReq := withoutCurves(payReq)
+	if !reflect.DeepEqual(expectedReq, withoutCurves(decodedReq)) {
+		t.Fatalf("decoded payment request doesn't match: expected %v, "+
+			"got %v", spew.Sdump(expectedReq), spew.Sdump(decodedReq))
+	}
+
+	// A route hint without any hops is invalid, and shouldn't be encoded.
+	payReq.RouteHints = append(payReq.RouteHints, nil)
+	if _, err := Encode(payReq); err == nil {
+		t.Fatalf("payment request with empty route hint was encoded")
+	}
+}
+
 func TestChecksumMismatch(t *testing.T) {
 	// We start with a pre-encoded invoice, which has a valid checksum.
 	payReqString := []byte("ycyr8brdjic6oak3bemztc5nupo56y3itq4z5q4qxwb35orf7fmj5phw8bx148zzipg3rh6t1btadpnxf7z1mnfd76hsw1eaoca3ot4uyyyyyyyyydbibt79jo1o")